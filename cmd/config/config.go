@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/effective"
+)
+
+// NewCmd builds a new Config command, grouping the commands about the dynamic configuration of a running Traefik.
+func NewCmd(traefikConfiguration *static.Configuration, loaders []cli.ResourceLoader) (*cli.Command, error) {
+	cmd := &cli.Command{
+		Name:        "config",
+		Description: `Commands about the dynamic configuration of a running Traefik.`,
+	}
+
+	err := cmd.AddCommand(&cli.Command{
+		Name:          "export",
+		Description:   `Calls Traefik /api/config/effective endpoint (the API must be enabled in insecure mode) to print the effective dynamic configuration.`,
+		Configuration: traefikConfiguration,
+		Run:           runExport(traefikConfiguration),
+		Resources:     loaders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func runExport(traefikConfiguration *static.Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		if err := interpolate.Interpolate(traefikConfiguration); err != nil {
+			return fmt.Errorf("unable to interpolate the static configuration: %w", err)
+		}
+
+		traefikConfiguration.SetEffectiveConfiguration()
+
+		snapshot, err := Export(*traefikConfiguration)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.MarshalIndent(snapshot.Configuration, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+}
+
+// Export fetches the effective dynamic configuration from the API of the running Traefik,
+// served on the traefik entry point in insecure mode.
+func Export(staticConfiguration static.Configuration) (*effective.Snapshot, error) {
+	if staticConfiguration.API == nil || !staticConfiguration.API.Insecure {
+		return nil, errors.New("please enable `api.insecure` to export the configuration")
+	}
+
+	apiEntryPoint, ok := staticConfiguration.EntryPoints["traefik"]
+	if !ok {
+		return nil, errors.New("api: missing traefik entry point")
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Get("http://" + apiEntryPoint.GetAddress() + "/api/config/effective")
+	if err != nil {
+		return nil, fmt.Errorf("unable to call the API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the response of the API: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status from the API: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	var snapshot effective.Snapshot
+	if err = json.Unmarshal(body, &snapshot); err != nil {
+		return nil, fmt.Errorf("unable to decode the effective configuration: %w", err)
+	}
+
+	return &snapshot, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 )
 
@@ -24,6 +25,10 @@ func NewCmd(traefikConfiguration *static.Configuration, loaders []cli.ResourceLo
 
 func runCmd(traefikConfiguration *static.Configuration) func(_ []string) error {
 	return func(_ []string) error {
+		if err := interpolate.Interpolate(traefikConfiguration); err != nil {
+			return fmt.Errorf("unable to interpolate the static configuration: %w", err)
+		}
+
 		traefikConfiguration.SetEffectiveConfiguration()
 
 		resp, errPing := Do(*traefikConfiguration)
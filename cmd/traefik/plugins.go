@@ -17,7 +17,7 @@ func createPluginBuilder(staticConfiguration *static.Configuration) (*plugins.Bu
 }
 
 func initPlugins(staticCfg *static.Configuration) (*plugins.Client, map[string]plugins.Descriptor, *plugins.DevPlugin, error) {
-	if !isPilotEnabled(staticCfg) || !hasPlugins(staticCfg) {
+	if !hasPlugins(staticCfg) || (!isPilotEnabled(staticCfg) && !hasOnlyRegistryPlugins(staticCfg)) {
 		return nil, map[string]plugins.Descriptor{}, nil, nil
 	}
 
@@ -43,6 +43,22 @@ func isPilotEnabled(staticCfg *static.Configuration) bool {
 	return staticCfg.Pilot != nil && staticCfg.Pilot.Token != ""
 }
 
+// hasOnlyRegistryPlugins returns true when all the plugins are pulled from OCI registries,
+// and therefore do not require Traefik Pilot.
+func hasOnlyRegistryPlugins(staticCfg *static.Configuration) bool {
+	if staticCfg.Experimental.DevPlugin != nil {
+		return false
+	}
+
+	for _, desc := range staticCfg.Experimental.Plugins {
+		if desc.Registry == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 func hasPlugins(staticCfg *static.Configuration) bool {
 	return staticCfg.Experimental != nil &&
 		(len(staticCfg.Experimental.Plugins) > 0 || staticCfg.Experimental.DevPlugin != nil)
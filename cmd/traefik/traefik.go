@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	stdlog "log"
 	"net/http"
 	"os"
@@ -20,24 +21,38 @@ import (
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v2/autogen/genstatic"
 	"github.com/traefik/traefik/v2/cmd"
+	cmdConfig "github.com/traefik/traefik/v2/cmd/config"
 	"github.com/traefik/traefik/v2/cmd/healthcheck"
 	cmdVersion "github.com/traefik/traefik/v2/cmd/version"
 	tcli "github.com/traefik/traefik/v2/pkg/cli"
 	"github.com/traefik/traefik/v2/pkg/collector"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/middlewares/apiauth"
 	"github.com/traefik/traefik/v2/pkg/pilot"
+	"github.com/traefik/traefik/v2/pkg/plugins"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+	"github.com/traefik/traefik/v2/pkg/server/confighash"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
+	"github.com/traefik/traefik/v2/pkg/server/effective"
+	"github.com/traefik/traefik/v2/pkg/server/freshness"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	"github.com/traefik/traefik/v2/pkg/server/override"
+	"github.com/traefik/traefik/v2/pkg/server/resources"
+	"github.com/traefik/traefik/v2/pkg/server/secret"
 	"github.com/traefik/traefik/v2/pkg/server/service"
+	"github.com/traefik/traefik/v2/pkg/server/tenancy"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/traefik/traefik/v2/pkg/version"
@@ -67,6 +82,18 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	configCommand, err := cmdConfig.NewCmd(&tConfig.Configuration, loaders)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(configCommand)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cmdTraefik.AddCommand(cmdVersion.NewCmd())
 	if err != nil {
 		stdlog.Println(err)
@@ -83,6 +110,10 @@ Complete documentation is available at https://traefik.io`,
 }
 
 func runCmd(staticConfiguration *static.Configuration) error {
+	if err := interpolate.Interpolate(staticConfiguration); err != nil {
+		return fmt.Errorf("unable to interpolate the static configuration: %w", err)
+	}
+
 	configureLogging(staticConfiguration)
 
 	http.DefaultTransport.(*http.Transport).Proxy = http.ProxyFromEnvironment
@@ -208,6 +239,45 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		return nil, err
 	}
 
+	// Drain mode
+
+	var drainer *drain.Manager
+	if staticConfiguration.Drain != nil {
+		drainEntryPoints, err := getDrainEntryPoints(staticConfiguration.Drain, serverEntryPointsTCP)
+		if err != nil {
+			return nil, err
+		}
+
+		drainer = drain.NewManager(time.Duration(staticConfiguration.Drain.Timeout), drainEntryPoints)
+		for name, entryPoint := range serverEntryPointsTCP {
+			drainer.Register(name, entryPoint)
+		}
+
+		if staticConfiguration.Ping != nil {
+			drainer.AddListener(staticConfiguration.Ping.Drain)
+		}
+	}
+
+	// Connection tables
+
+	var connectionTables *connections.Registry
+	if staticConfiguration.API != nil && staticConfiguration.API.Connections {
+		connectionTables = connections.NewRegistry()
+		for name, entryPoint := range serverEntryPointsTCP {
+			connectionTables.Register(name, entryPoint)
+		}
+	}
+
+	// Audit log
+
+	var auditLog *audit.Log
+	if staticConfiguration.Audit != nil {
+		auditLog, err = audit.New(staticConfiguration.Audit.FilePath, staticConfiguration.Audit.MaxEntries)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up the audit log: %w", err)
+		}
+	}
+
 	// Pilot
 
 	var aviator *pilot.Pilot
@@ -229,25 +299,118 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		return nil, err
 	}
 
+	for name, conf := range staticConfiguration.Providers.Plugin {
+		pName, pConf, err := plugins.FindProviderConfig(conf)
+		if err != nil {
+			return nil, fmt.Errorf("plugin provider %s: %w", name, err)
+		}
+
+		p, err := pluginBuilder.BuildProvider(name, pName, pConf)
+		if err != nil {
+			return nil, fmt.Errorf("plugin provider %s: %w", name, err)
+		}
+
+		err = providerAggregator.AddProvider(p)
+		if err != nil {
+			return nil, fmt.Errorf("plugin provider %s: %w", name, err)
+		}
+	}
+
 	// Metrics
 
 	metricRegistries := registerMetricClients(staticConfiguration.Metrics)
 	if pilotRegistry != nil {
 		metricRegistries = append(metricRegistries, pilotRegistry)
 	}
+
+	// The live request rates are reported by the topology graph of the API.
+	var ratesRegistry *metrics.RatesRegistry
+	if staticConfiguration.API != nil {
+		ratesRegistry = metrics.RegisterRates()
+		metricRegistries = append(metricRegistries, ratesRegistry)
+	}
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
 
+	if staticConfiguration.Providers.KubernetesGateway != nil {
+		staticConfiguration.Providers.KubernetesGateway.SetMetricsRegistry(metricsRegistry)
+	}
+
 	// Service manager factory
 
 	roundTripperManager := service.NewRoundTripperManager()
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
+
+	var overrides *override.Store
+	if staticConfiguration.API != nil && staticConfiguration.API.Overrides {
+		overrides = override.NewStore()
+	}
+
+	var effectiveConfig *effective.Store
+	if staticConfiguration.API != nil {
+		effectiveConfig = effective.NewStore()
+	}
+
+	configHashes := confighash.NewTracker(metricsRegistry.ConfigHashGauge())
+
+	var apiAuth *apiauth.Authenticator
+	if staticConfiguration.API != nil {
+		apiAuth, err = apiauth.New(staticConfiguration.API.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up the API authentication: %w", err)
+		}
+	}
+
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler, service.APIOptions{
+		Overrides:       overrides,
+		Auth:            apiAuth,
+		Rates:           ratesRegistry,
+		Drainer:         drainer,
+		AuditLog:        auditLog,
+		Connections:     connectionTables,
+		Plugins:         pluginBuilder,
+		EffectiveConfig: effectiveConfig,
+		ConfigHash:      configHashes,
+	})
 
 	// Router factory
 
-	accessLog := setupAccessLog(staticConfiguration.AccessLog)
+	accessLog := setupAccessLog(staticConfiguration.AccessLog, metricsRegistry)
 	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder)
+
+	// Resource guard
+
+	if staticConfiguration.ResourceGuard != nil {
+		guardedEntryPoints, err := getResourceGuardEntryPoints(staticConfiguration.ResourceGuard, serverEntryPointsTCP)
+		if err != nil {
+			return nil, err
+		}
+
+		monitor := resources.NewMonitor(*staticConfiguration.ResourceGuard, metricsRegistry)
+
+		guards := make(map[string]*resources.Guard)
+		for _, name := range guardedEntryPoints {
+			guards[name] = monitor.Guard(name)
+			serverEntryPointsTCP[name].SetResourceGuard(guards[name])
+		}
+		chainBuilder.SetResourceGuards(guards)
+
+		routinesPool.GoCtx(monitor.Run)
+	}
+
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+
+	// Secrets
+
+	var secrets *secret.Resolver
+	if staticConfiguration.Secrets != nil {
+		secrets, err = secret.New(staticConfiguration.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up the secrets: %w", err)
+		}
+
+		routerFactory.SetSecrets(secrets)
+		routinesPool.GoCtx(secrets.Run)
+	}
 
 	// Watcher
 
@@ -258,6 +421,39 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		getDefaultsEntrypoints(staticConfiguration),
 	)
 
+	// Changes suppression
+	watcher.SetHashTracker(configHashes)
+
+	// Runtime overrides
+	if overrides != nil {
+		watcher.SetOverrides(overrides)
+	}
+
+	// Secrets rotation
+	if secrets != nil {
+		watcher.SetSecrets(secrets)
+	}
+
+	// Audit log
+	if auditLog != nil {
+		watcher.SetAuditLog(auditLog)
+	}
+
+	// Tenants
+	if len(staticConfiguration.Tenants) > 0 {
+		watcher.SetTenants(tenancy.New(staticConfiguration.Tenants))
+	}
+
+	// Providers freshness
+	freshnessWatchdog := freshness.New(staticConfiguration.Providers.Freshness, metricsRegistry)
+	watcher.SetFreshnessWatchdog(freshnessWatchdog)
+	routinesPool.GoCtx(freshnessWatchdog.Run)
+
+	// Effective configuration
+	if effectiveConfig != nil {
+		watcher.AddListener(effectiveConfig.Set)
+	}
+
 	// TLS
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
@@ -283,6 +479,27 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	// Switch router
 	watcher.AddListener(switchRouter(routerFactory, serverEntryPointsTCP, serverEntryPointsUDP, aviator))
 
+	// Kubernetes readiness gates, set once the routers are switched.
+	if staticConfiguration.Providers.KubernetesCRD != nil {
+		watcher.AddListener(staticConfiguration.Providers.KubernetesCRD.ListenConfiguration)
+	}
+
+	// Readiness
+	if staticConfiguration.Ping != nil {
+		watcher.AddProviderListener(staticConfiguration.Ping.ProviderLoaded)
+
+		for _, syncer := range providerAggregator.Syncers() {
+			providerName, synced := syncer.Synced()
+			routinesPool.GoCtx(func(ctx context.Context) {
+				select {
+				case <-synced:
+					staticConfiguration.Ping.ProviderLoaded(providerName)
+				case <-ctx.Done():
+				}
+			})
+		}
+	}
+
 	// Metrics
 	if metricsRegistry.IsEpEnabled() || metricsRegistry.IsSvcEnabled() {
 		var eps []string
@@ -317,7 +534,63 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
-	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
+	svr := server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog)
+
+	if drainer != nil {
+		if err := svr.SetDrainer(drainer, staticConfiguration.Drain.Signals); err != nil {
+			return nil, fmt.Errorf("unable to set up the drain mode: %w", err)
+		}
+	}
+
+	return svr, nil
+}
+
+// getDrainEntryPoints returns the entry points drained by default,
+// all the TCP entry points but the traefik one when none is configured.
+func getDrainEntryPoints(config *static.Drain, entryPoints server.TCPEntryPoints) ([]string, error) {
+	if len(config.EntryPoints) > 0 {
+		for _, name := range config.EntryPoints {
+			if _, ok := entryPoints[name]; !ok {
+				return nil, fmt.Errorf("unable to drain the entry point %s, which is not a TCP entry point", name)
+			}
+		}
+
+		return config.EntryPoints, nil
+	}
+
+	var names []string
+	for name := range entryPoints {
+		if name != static.DefaultInternalEntryPointName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// getResourceGuardEntryPoints returns the entry points shedding their new connections,
+// all the TCP entry points but the traefik one when none is configured.
+func getResourceGuardEntryPoints(config *static.ResourceGuard, entryPoints server.TCPEntryPoints) ([]string, error) {
+	if len(config.EntryPoints) > 0 {
+		for _, name := range config.EntryPoints {
+			if _, ok := entryPoints[name]; !ok {
+				return nil, fmt.Errorf("unable to guard the entry point %s, which is not a TCP entry point", name)
+			}
+		}
+
+		return config.EntryPoints, nil
+	}
+
+	var names []string
+	for name := range entryPoints {
+		if name != static.DefaultInternalEntryPointName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
 }
 
 func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvider http.Handler) http.Handler {
@@ -368,6 +641,19 @@ func switchRouter(routerFactory *server.RouterFactory, serverEntryPointsTCP serv
 func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, httpChallengeProvider, tlsChallengeProvider challenge.Provider) []*acme.Provider {
 	localStores := map[string]*acme.LocalStore{}
 
+	policies := make(map[string]*acme.Policy)
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.ACME != nil {
+			policies[name] = resolver.ACME.Policy
+		}
+	}
+
+	// The suffixes of the policies are checked by the validation of the static configuration.
+	selector, err := acme.NewResolverSelector(policies)
+	if err != nil {
+		log.WithoutContext().Errorf("The resolvers are not selected by host suffix: %v", err)
+	}
+
 	var resolvers []*acme.Provider
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME != nil {
@@ -379,6 +665,7 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 				Configuration:         resolver.ACME,
 				Store:                 localStores[resolver.ACME.Storage],
 				ResolverName:          name,
+				Selector:              selector,
 				HTTPChallengeProvider: httpChallengeProvider,
 				TLSChallengeProvider:  tlsChallengeProvider,
 			}
@@ -436,6 +723,16 @@ func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 			metricsConfig.InfluxDB.Address, metricsConfig.InfluxDB.PushInterval)
 	}
 
+	if metricsConfig.OTLP != nil {
+		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "otlp"))
+		otlpRegister := metrics.RegisterOTLP(ctx, metricsConfig.OTLP)
+		if otlpRegister != nil {
+			registries = append(registries, otlpRegister)
+			log.FromContext(ctx).Debugf("Configured OTLP metrics: pushing to %s once every %s",
+				metricsConfig.OTLP.Address, metricsConfig.OTLP.PushInterval)
+		}
+	}
+
 	return registries
 }
 
@@ -453,12 +750,12 @@ func appendCertMetric(gauge gokitmetrics.Gauge, certificate *x509.Certificate) {
 	gauge.With(labels...).Set(notAfter)
 }
 
-func setupAccessLog(conf *types.AccessLog) *accesslog.Handler {
+func setupAccessLog(conf *types.AccessLog, metricsRegistry metrics.Registry) *accesslog.Handler {
 	if conf == nil {
 		return nil
 	}
 
-	accessLoggerMiddleware, err := accesslog.NewHandler(conf)
+	accessLoggerMiddleware, err := accesslog.NewHandler(conf, metricsRegistry)
 	if err != nil {
 		log.WithoutContext().Warnf("Unable to create access logger : %v", err)
 		return nil
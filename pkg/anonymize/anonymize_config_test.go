@@ -132,6 +132,19 @@ func TestDo_dynamicConfiguration(t *testing.T) {
 					},
 				},
 			},
+			"qux": {
+				Redirect: &dynamic.Redirection{
+					Location:   "https://example.com{{ .Path }}",
+					StatusCode: 42,
+				},
+			},
+			"quux": {
+				Static: &dynamic.StaticResponse{
+					StatusCode: 42,
+					Headers:    map[string]string{"foo": "bar"},
+					Body:       "foo",
+				},
+			},
 		},
 		ServersTransports: map[string]*dynamic.ServersTransport{
 			"foo": {
@@ -534,6 +547,10 @@ func TestDo_staticConfiguration(t *testing.T) {
 						{Main: "foobar", SANs: []string{"foobar", "foobar"}},
 					},
 				},
+				RequestID: &types.RequestID{
+					HeaderName: "foobar",
+					Generator:  "foobar",
+				},
 			},
 		},
 	}
@@ -778,6 +795,26 @@ func TestDo_staticConfiguration(t *testing.T) {
 		Insecure:  true,
 		Dashboard: true,
 		Debug:     true,
+		Overrides: true,
+		Auth: &static.APIAuth{
+			Basic: &static.APIBasicAuth{
+				Users:     []string{"admin:foo"},
+				UsersFile: "users.htpasswd",
+				Realm:     "traefik",
+			},
+			OIDC: &static.APIOIDCAuth{
+				Issuer:       "https://issuer.example.com",
+				ClientID:     "traefik",
+				ClientSecret: "secret",
+				Scopes:       []string{"openid", "email"},
+				Claim:        "email",
+			},
+			MTLS: &static.APIMTLSAuth{
+				CAFiles: []traefiktls.FileOrContent{"ca.pem"},
+			},
+			Admins:  []string{"admin"},
+			Viewers: []string{"viewer"},
+		},
 		DashboardAssets: &assetfs.AssetFS{
 			Asset: func(path string) ([]byte, error) {
 				return nil, nil
@@ -824,12 +861,38 @@ func TestDo_staticConfiguration(t *testing.T) {
 			AddEntryPointsLabels: true,
 			AddServicesLabels:    true,
 		},
+		OTLP: &types.OTLP{
+			Address:              "localhost:4317",
+			Protocol:             "http",
+			Insecure:             true,
+			Headers:              map[string]string{"api-key": "secret"},
+			PushInterval:         42,
+			Temporality:          "delta",
+			ExplicitBoundaries:   []float64{0.1, 5},
+			AddEntryPointsLabels: true,
+			AddServicesLabels:    true,
+		},
 	}
 
 	config.Ping = &ping.Handler{
 		EntryPoint:            "MyEntryPoint",
 		ManualRouting:         true,
 		TerminatingStatusCode: 42,
+		RequiredProviders:     []string{"foobar"},
+	}
+
+	config.Drain = &static.Drain{
+		EntryPoints: []string{"foobar"},
+		Signals:     []string{"SIGUSR2"},
+		Timeout:     ptypes.Duration(111 * time.Second),
+		Endpoint:    true,
+	}
+
+	config.UpstreamOverride = &static.UpstreamOverride{
+		Header:   "X-Upstream",
+		Secret:   "secret",
+		MaxAge:   ptypes.Duration(111 * time.Second),
+		Services: []string{"foobar"},
 	}
 
 	config.Log = &types.TraefikLog{
@@ -859,6 +922,14 @@ func TestDo_staticConfiguration(t *testing.T) {
 			},
 		},
 		BufferingSize: 42,
+		AsyncWriter: &types.AccessLogWriter{
+			QueueSize:      42,
+			OverflowPolicy: "drop",
+			DiskBuffer: &types.AccessLogDiskBuffer{
+				FilePath: "AccessLog DiskBuffer FilePath",
+				MaxSize:  42,
+			},
+		},
 	}
 
 	config.Tracing = &static.Tracing{
@@ -914,6 +985,11 @@ func TestDo_staticConfiguration(t *testing.T) {
 			SecretToken:        "foobar",
 			ServiceEnvironment: "foobar",
 		},
+		Sampling: &types.TracingSampling{
+			Rate:        func(v float64) *float64 { return &v }(0.42),
+			StatusCodes: []string{"500-599"},
+			MinDuration: ptypes.Duration(111 * time.Second),
+		},
 	}
 
 	config.HostResolver = &types.HostResolverConfig{
@@ -953,6 +1029,13 @@ func TestDo_staticConfiguration(t *testing.T) {
 			"Descriptor0": {
 				ModuleName: "foobar",
 				Version:    "foobar",
+				Registry: &plugins.Registry{
+					Repository: "foobar",
+					Digest:     "foobar",
+					Username:   "username",
+					Password:   "password",
+					PlainHTTP:  true,
+				},
 			},
 			"Descriptor1": {
 				ModuleName: "foobar",
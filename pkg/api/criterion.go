@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,7 +14,10 @@ const (
 	defaultPage    = 1
 )
 
-const nextPageHeader = "X-Next-Page"
+const (
+	nextPageHeader   = "X-Next-Page"
+	totalCountHeader = "X-Total-Count"
+)
 
 type pageInfo struct {
 	startIndex int
@@ -22,8 +26,9 @@ type pageInfo struct {
 }
 
 type searchCriterion struct {
-	Search string `url:"search"`
-	Status string `url:"status"`
+	Search   string `url:"search"`
+	Status   string `url:"status"`
+	Provider string `url:"provider"`
 }
 
 func newSearchCriterion(query url.Values) *searchCriterion {
@@ -33,18 +38,23 @@ func newSearchCriterion(query url.Values) *searchCriterion {
 
 	search := query.Get("search")
 	status := query.Get("status")
+	provider := query.Get("provider")
 
-	if status == "" && search == "" {
+	if status == "" && search == "" && provider == "" {
 		return nil
 	}
 
-	return &searchCriterion{Search: search, Status: status}
+	return &searchCriterion{Search: search, Status: status, Provider: provider}
 }
 
 func (c *searchCriterion) withStatus(name string) bool {
 	return c.Status == "" || strings.EqualFold(name, c.Status)
 }
 
+func (c *searchCriterion) withProvider(name string) bool {
+	return c.Provider == "" || strings.EqualFold(getProviderName(name), c.Provider)
+}
+
 func (c *searchCriterion) searchIn(values ...string) bool {
 	if c.Search == "" {
 		return true
@@ -88,6 +98,46 @@ func pagination(request *http.Request, max int) (pageInfo, error) {
 	return pageInfo{startIndex: startIndex, endIndex: endIndex, nextPage: nextPage}, nil
 }
 
+// selectFields keeps, in each of the items, only the fields listed in the fields query parameter,
+// a comma separated list of JSON keys.
+// The items are returned as is when no fields are selected.
+func selectFields(request *http.Request, items interface{}) (interface{}, error) {
+	fields := make(map[string]struct{})
+	for _, field := range strings.Split(request.URL.Query().Get("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = struct{}{}
+		}
+	}
+
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []map[string]json.RawMessage
+	if err = json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]json.RawMessage, 0, len(all))
+	for _, item := range all {
+		result := make(map[string]json.RawMessage, len(fields))
+		for field := range fields {
+			if value, ok := item[field]; ok {
+				result[field] = value
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
 func getIntParam(request *http.Request, key string, defaultValue int) (int, error) {
 	raw := request.URL.Query().Get(key)
 	if raw == "" {
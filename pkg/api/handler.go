@@ -10,7 +10,15 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
-	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+	"github.com/traefik/traefik/v2/pkg/server/confighash"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
+	"github.com/traefik/traefik/v2/pkg/server/effective"
+	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	"github.com/traefik/traefik/v2/pkg/server/override"
 	"github.com/traefik/traefik/v2/pkg/version"
 )
 
@@ -30,18 +38,23 @@ func writeError(rw http.ResponseWriter, msg string, code int) {
 
 type serviceInfoRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+	ServerStatus map[string]string        `json:"serverStatus,omitempty"`
+	Promotion    *runtime.PromotionStatus `json:"promotion,omitempty"`
 }
 
 // RunTimeRepresentation is the configuration information exposed by the API handler.
 type RunTimeRepresentation struct {
-	Routers     map[string]*runtime.RouterInfo        `json:"routers,omitempty"`
-	Middlewares map[string]*runtime.MiddlewareInfo    `json:"middlewares,omitempty"`
-	Services    map[string]*serviceInfoRepresentation `json:"services,omitempty"`
-	TCPRouters  map[string]*runtime.TCPRouterInfo     `json:"tcpRouters,omitempty"`
-	TCPServices map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
-	UDPRouters  map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
-	UDPServices map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+	// SchemaVersion is the version of the schema of the representation.
+	SchemaVersion int `json:"schemaVersion"`
+
+	Routers        map[string]*runtime.RouterInfo        `json:"routers,omitempty"`
+	Middlewares    map[string]*runtime.MiddlewareInfo    `json:"middlewares,omitempty"`
+	Services       map[string]*serviceInfoRepresentation `json:"services,omitempty"`
+	TCPRouters     map[string]*runtime.TCPRouterInfo     `json:"tcpRouters,omitempty"`
+	TCPMiddlewares map[string]*runtime.TCPMiddlewareInfo `json:"tcpMiddlewares,omitempty"`
+	TCPServices    map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
+	UDPRouters     map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
+	UDPServices    map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -51,14 +64,76 @@ type Handler struct {
 	staticConfig    static.Configuration
 	dashboardAssets *assetfs.AssetFS
 
+	// overrides holds the runtime overrides, and is nil when their endpoints are disabled.
+	overrides *override.Store
+
+	// circuitBreakers resets the circuit breakers, and is nil when the overrides endpoints are disabled.
+	circuitBreakers *circuitbreaker.Registry
+
+	// rates holds the live request rates, and is nil when they are not measured.
+	rates *metrics.RatesRegistry
+
+	// drainer drains the entry points, and is nil when the drain mode is disabled.
+	drainer *drain.Manager
+
+	// auditLog holds the applied configurations, and is nil when the audit log is disabled.
+	auditLog *audit.Log
+
+	// connections holds the connection tables of the entry points, and is nil when their endpoints are disabled.
+	connections *connections.Registry
+
+	// pluginBuilder builds the plugin middlewares run by the middleware simulations.
+	pluginBuilder middleware.PluginsBuilder
+
+	// effectiveConfig holds the effective dynamic configuration, and is nil when its endpoint is disabled.
+	effectiveConfig *effective.Store
+
+	// configHash holds the hash of the applied dynamic configuration, reported in the overview.
+	configHash *confighash.Tracker
+
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
 }
 
-// NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+// Options holds the optional components of the API.
+// The endpoints and reports depending on a component are disabled when it is nil.
+type Options struct {
+	// Overrides enables the overrides endpoints.
+	Overrides *override.Store
+	// CircuitBreakers are reset by the overrides endpoints.
+	CircuitBreakers *circuitbreaker.Registry
+	// Rates reports the request rates in the topology graph.
+	Rates *metrics.RatesRegistry
+	// Drainer enables the drain endpoints.
+	Drainer *drain.Manager
+	// AuditLog enables the audit endpoint.
+	AuditLog *audit.Log
+	// Connections enables the connections endpoints.
+	Connections *connections.Registry
+	// Plugins builds the plugin middlewares of the middleware simulations.
+	Plugins middleware.PluginsBuilder
+	// EffectiveConfig enables the effective configuration endpoint.
+	EffectiveConfig *effective.Store
+	// ConfigHash reports the hash of the applied configuration in the overview.
+	ConfigHash *confighash.Tracker
+}
+
+// NewBuilder returns a http.Handler builder based on runtime.Configuration,
+// whose optional endpoints are enabled by the given options.
+func NewBuilder(staticConfig static.Configuration, options Options) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.overrides = options.Overrides
+		handler.circuitBreakers = options.CircuitBreakers
+		handler.rates = options.Rates
+		handler.drainer = options.Drainer
+		handler.auditLog = options.AuditLog
+		handler.connections = options.Connections
+		handler.pluginBuilder = options.Plugins
+		handler.effectiveConfig = options.EffectiveConfig
+		handler.configHash = options.ConfigHash
+
+		return handler.createRouter()
 	}
 }
 
@@ -101,6 +176,9 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
+	router.Methods(http.MethodPost).Path("/api/http/middlewares/{middlewareID}/simulate").HandlerFunc(h.simulateMiddleware)
+	router.Methods(http.MethodGet).Path("/api/http/dryrun/path").HandlerFunc(h.getPathDryRun)
+	router.Methods(http.MethodGet).Path("/api/http/graph").HandlerFunc(h.getGraph)
 
 	router.Methods(http.MethodGet).Path("/api/tcp/routers").HandlerFunc(h.getTCPRouters)
 	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}").HandlerFunc(h.getTCPRouter)
@@ -112,41 +190,46 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
-	version.Handler{}.Append(router)
-
-	if h.dashboard {
-		DashboardHandler{Assets: h.dashboardAssets}.Append(router)
+	if h.overrides != nil {
+		router.Methods(http.MethodGet).Path("/api/overrides").HandlerFunc(h.writeOverrides)
+		router.Methods(http.MethodDelete).Path("/api/overrides").HandlerFunc(h.clearOverrides)
+		router.Methods(http.MethodPost).Path("/api/overrides/http/routers/{routerID}/disable").HandlerFunc(h.disableRouter)
+		router.Methods(http.MethodPost).Path("/api/overrides/http/routers/{routerID}/enable").HandlerFunc(h.enableRouter)
+		router.Methods(http.MethodPost).Path("/api/overrides/http/services/{serviceID}/drain").HandlerFunc(h.drainServer)
+		router.Methods(http.MethodPost).Path("/api/overrides/http/services/{serviceID}/undrain").HandlerFunc(h.undrainServer)
+		router.Methods(http.MethodPost).Path("/api/overrides/http/middlewares/{middlewareID}/reset").HandlerFunc(h.resetCircuitBreaker)
+		router.Methods(http.MethodPost).Path("/api/overrides/providers/{providerID}/pause").HandlerFunc(h.pauseProvider)
+		router.Methods(http.MethodPost).Path("/api/overrides/providers/{providerID}/resume").HandlerFunc(h.resumeProvider)
 	}
 
-	return router
-}
+	if h.drainer != nil {
+		router.Methods(http.MethodGet).Path("/api/drain").HandlerFunc(h.writeDrainStatus)
 
-func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.Request) {
-	siRepr := make(map[string]*serviceInfoRepresentation, len(h.runtimeConfiguration.Services))
-	for k, v := range h.runtimeConfiguration.Services {
-		siRepr[k] = &serviceInfoRepresentation{
-			ServiceInfo:  v,
-			ServerStatus: v.GetAllStatus(),
+		if h.staticConfig.Drain != nil && h.staticConfig.Drain.Endpoint {
+			router.Methods(http.MethodPost).Path("/api/drain").HandlerFunc(h.drainEntryPoints)
 		}
 	}
 
-	result := RunTimeRepresentation{
-		Routers:     h.runtimeConfiguration.Routers,
-		Middlewares: h.runtimeConfiguration.Middlewares,
-		Services:    siRepr,
-		TCPRouters:  h.runtimeConfiguration.TCPRouters,
-		TCPServices: h.runtimeConfiguration.TCPServices,
-		UDPRouters:  h.runtimeConfiguration.UDPRouters,
-		UDPServices: h.runtimeConfiguration.UDPServices,
+	if h.auditLog != nil {
+		router.Methods(http.MethodGet).Path("/api/audit").HandlerFunc(h.getAuditEntries)
 	}
 
-	rw.Header().Set("Content-Type", "application/json")
+	if h.connections != nil {
+		router.Methods(http.MethodGet).Path("/api/entrypoints/{entryPointID}/connections").HandlerFunc(h.getConnections)
+		router.Methods(http.MethodDelete).Path("/api/entrypoints/{entryPointID}/connections/{connectionID}").HandlerFunc(h.closeConnection)
+	}
 
-	err := json.NewEncoder(rw).Encode(result)
-	if err != nil {
-		log.FromContext(request.Context()).Error(err)
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	if h.effectiveConfig != nil {
+		router.Methods(http.MethodGet).Path("/api/config/effective").HandlerFunc(h.getEffectiveConfiguration)
 	}
+
+	version.Handler{}.Append(router)
+
+	if h.dashboard {
+		DashboardHandler{Assets: h.dashboardAssets}.Append(router)
+	}
+
+	return router
 }
 
 func getProviderName(id string) string {
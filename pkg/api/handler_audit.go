@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+)
+
+// getAuditEntries writes the entries of the audit log, from the oldest to the latest,
+// filtered by provider, and by the since and until RFC 3339 times.
+func (h Handler) getAuditEntries(rw http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+
+	since, err := parseAuditTime(query.Get("since"))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	until, err := parseAuditTime(query.Get("until"))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	provider := query.Get("provider")
+
+	entries := make([]audit.Entry, 0)
+	for _, entry := range h.auditLog.Entries() {
+		if provider != "" && entry.Provider != provider {
+			continue
+		}
+
+		if !since.IsZero() && entry.Time.Before(since) {
+			continue
+		}
+
+		if !until.IsZero() && entry.Time.After(until) {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(rw).Encode(entries)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, it must follow RFC 3339", value)
+	}
+
+	return t, nil
+}
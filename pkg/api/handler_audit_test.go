@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+)
+
+func TestHandler_Audit(t *testing.T) {
+	auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"), 10)
+	require.NoError(t, err)
+
+	require.NoError(t, auditLog.Record("file", &dynamic.Configuration{}))
+	require.NoError(t, auditLog.Record("docker", &dynamic.Configuration{HTTP: &dynamic.HTTPConfiguration{}}))
+
+	hour := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	testCases := []struct {
+		desc               string
+		path               string
+		expectedStatusCode int
+		expectedProviders  []string
+	}{
+		{
+			desc:               "all entries",
+			path:               "/api/audit",
+			expectedStatusCode: http.StatusOK,
+			expectedProviders:  []string{"file", "docker"},
+		},
+		{
+			desc:               "provider entries",
+			path:               "/api/audit?provider=docker",
+			expectedStatusCode: http.StatusOK,
+			expectedProviders:  []string{"docker"},
+		},
+		{
+			desc:               "entries until",
+			path:               "/api/audit?until=" + hour,
+			expectedStatusCode: http.StatusOK,
+			expectedProviders:  []string{"file", "docker"},
+		},
+		{
+			desc:               "entries since",
+			path:               "/api/audit?since=" + hour,
+			expectedStatusCode: http.StatusOK,
+			expectedProviders:  []string{},
+		},
+		{
+			desc:               "invalid time",
+			path:               "/api/audit?since=yesterday",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{AuditLog: auditLog})(&runtime.Configuration{})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedProviders == nil {
+				return
+			}
+
+			var entries []audit.Entry
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+
+			providers := make([]string, 0)
+			for _, entry := range entries {
+				assert.Len(t, entry.Hash, 64)
+				providers = append(providers, entry.Provider)
+			}
+
+			assert.Equal(t, test.expectedProviders, providers)
+		})
+	}
+}
+
+func TestHandler_auditDisabled(t *testing.T) {
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/audit")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+)
+
+// getConnections writes the active connections of the entry point.
+func (h Handler) getConnections(rw http.ResponseWriter, request *http.Request) {
+	entryPointID := mux.Vars(request)["entryPointID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	conns, err := h.connections.Connections(entryPointID)
+	if errors.Is(err, connections.ErrUnknownEntryPoint) {
+		writeError(rw, fmt.Sprintf("entry point not found: %s", entryPointID), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(conns)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// closeConnection closes an active connection of the entry point.
+func (h Handler) closeConnection(rw http.ResponseWriter, request *http.Request) {
+	entryPointID := mux.Vars(request)["entryPointID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.ParseUint(mux.Vars(request)["connectionID"], 10, 64)
+	if err != nil {
+		writeError(rw, fmt.Sprintf("invalid connection ID: %s", mux.Vars(request)["connectionID"]), http.StatusBadRequest)
+		return
+	}
+
+	err = h.connections.Close(entryPointID, id)
+	switch {
+	case errors.Is(err, connections.ErrUnknownEntryPoint):
+		writeError(rw, fmt.Sprintf("entry point not found: %s", entryPointID), http.StatusNotFound)
+		return
+	case errors.Is(err, connections.ErrUnknownConnection):
+		writeError(rw, fmt.Sprintf("connection not found: %d", id), http.StatusNotFound)
+		return
+	case err != nil:
+		// The connection is removed from the table even when closing it fails.
+		log.FromContext(request.Context()).Debugf("Error while closing the connection %d of the entry point %s: %v", id, entryPointID, err)
+	}
+
+	log.FromContext(request.Context()).Infof("Closed the connection %d of the entry point %s", id, entryPointID)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
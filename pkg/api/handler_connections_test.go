@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+)
+
+type connectionTable struct {
+	conns  []connections.Info
+	closed []uint64
+}
+
+func (c *connectionTable) Connections() []connections.Info {
+	return c.conns
+}
+
+func (c *connectionTable) CloseConnection(id uint64) error {
+	for _, conn := range c.conns {
+		if conn.ID == id {
+			c.closed = append(c.closed, id)
+			return nil
+		}
+	}
+
+	return connections.ErrUnknownConnection
+}
+
+func TestHandler_Connections(t *testing.T) {
+	startedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedConns      []connections.Info
+		expectedClosed     []uint64
+	}{
+		{
+			desc:               "list connections",
+			method:             http.MethodGet,
+			path:               "/api/entrypoints/web/connections",
+			expectedStatusCode: http.StatusOK,
+			expectedConns: []connections.Info{{
+				ID:         1,
+				ClientAddr: "10.0.0.1:51000",
+				Protocol:   connections.ProtocolTLS,
+				SNI:        "foo.bar",
+				Router:     "foo@file",
+				StartedAt:  startedAt,
+				Age:        "1m0s",
+			}},
+		},
+		{
+			desc:               "list connections of unknown entry point",
+			method:             http.MethodGet,
+			path:               "/api/entrypoints/unknown/connections",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "close connection",
+			method:             http.MethodDelete,
+			path:               "/api/entrypoints/web/connections/1",
+			expectedStatusCode: http.StatusNoContent,
+			expectedClosed:     []uint64{1},
+		},
+		{
+			desc:               "close unknown connection",
+			method:             http.MethodDelete,
+			path:               "/api/entrypoints/web/connections/2",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "close connection with invalid ID",
+			method:             http.MethodDelete,
+			path:               "/api/entrypoints/web/connections/foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "close connection of unknown entry point",
+			method:             http.MethodDelete,
+			path:               "/api/entrypoints/unknown/connections/1",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			table := &connectionTable{conns: []connections.Info{{
+				ID:         1,
+				ClientAddr: "10.0.0.1:51000",
+				Protocol:   connections.ProtocolTLS,
+				SNI:        "foo.bar",
+				Router:     "foo@file",
+				StartedAt:  startedAt,
+				Age:        "1m0s",
+			}}}
+
+			registry := connections.NewRegistry()
+			registry.Register("web", table)
+
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{Connections: registry})(&runtime.Configuration{})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, test.expectedClosed, table.closed)
+
+			if test.expectedConns == nil {
+				return
+			}
+
+			var conns []connections.Info
+			err = json.NewDecoder(resp.Body).Decode(&conns)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedConns, conns)
+		})
+	}
+}
+
+func TestHandler_connectionsDisabled(t *testing.T) {
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/entrypoints/web/connections")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
+)
+
+func (h Handler) drainEntryPoints(rw http.ResponseWriter, request *http.Request) {
+	var entryPoints []string
+	if value := request.URL.Query().Get("entryPoints"); value != "" {
+		entryPoints = strings.Split(value, ",")
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := h.drainer.Drain(entryPoints...)
+	if errors.Is(err, drain.ErrUnknownEntryPoint) {
+		writeError(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+
+	h.writeDrainStatus(rw, request)
+}
+
+// writeDrainStatus writes the drain progress of the entry points.
+func (h Handler) writeDrainStatus(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(h.drainer.Status())
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
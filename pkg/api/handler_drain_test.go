@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
+)
+
+type drainTarget struct{}
+
+func (drainTarget) Drain(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func (drainTarget) ActiveConnections() int {
+	return 3
+}
+
+func TestHandler_Drain(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		endpoint           bool
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedStates     map[string]string
+	}{
+		{
+			desc:               "status",
+			method:             http.MethodGet,
+			path:               "/api/drain",
+			expectedStatusCode: http.StatusOK,
+			expectedStates:     map[string]string{"traefik": drain.StateServing, "web": drain.StateServing},
+		},
+		{
+			desc:               "drain default entry points",
+			endpoint:           true,
+			method:             http.MethodPost,
+			path:               "/api/drain",
+			expectedStatusCode: http.StatusAccepted,
+			expectedStates:     map[string]string{"traefik": drain.StateServing, "web": drain.StateDraining},
+		},
+		{
+			desc:               "drain given entry points",
+			endpoint:           true,
+			method:             http.MethodPost,
+			path:               "/api/drain?entryPoints=web,traefik",
+			expectedStatusCode: http.StatusAccepted,
+			expectedStates:     map[string]string{"traefik": drain.StateDraining, "web": drain.StateDraining},
+		},
+		{
+			desc:               "drain unknown entry point",
+			endpoint:           true,
+			method:             http.MethodPost,
+			path:               "/api/drain?entryPoints=web,unknown",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			drainer := drain.NewManager(time.Minute, []string{"web"})
+			drainer.Register("web", drainTarget{})
+			drainer.Register("traefik", drainTarget{})
+
+			staticConfig := static.Configuration{
+				API:    &static.API{},
+				Global: &static.Global{},
+				Drain:  &static.Drain{Endpoint: test.endpoint},
+			}
+
+			handler := NewBuilder(staticConfig, Options{Drainer: drainer})(&runtime.Configuration{})
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedStates == nil {
+				return
+			}
+
+			var statuses []drain.EntryPointStatus
+			err = json.NewDecoder(resp.Body).Decode(&statuses)
+			require.NoError(t, err)
+
+			states := make(map[string]string)
+			for _, status := range statuses {
+				assert.Equal(t, 3, status.ActiveConnections)
+				states[status.EntryPoint] = status.State
+			}
+
+			assert.Equal(t, test.expectedStates, states)
+		})
+	}
+}
+
+func TestHandler_drainEndpointDisabled(t *testing.T) {
+	drainer := drain.NewManager(time.Minute, []string{"web"})
+	drainer.Register("web", drainTarget{})
+
+	staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}, Drain: &static.Drain{}}
+
+	handler := NewBuilder(staticConfig, Options{Drainer: drainer})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Post(server.URL+"/api/drain", "", nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.NotEqual(t, http.StatusAccepted, resp.StatusCode)
+	assert.False(t, drainer.Draining())
+}
+
+func TestHandler_drainDisabled(t *testing.T) {
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/drain")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
+	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
+	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
+	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
+)
+
+// maxChainDepth is the maximum number of nested chains followed by the path dry run.
+const maxChainDepth = 10
+
+type pathDryRunRepresentation struct {
+	Middlewares     []string          `json:"middlewares"`
+	Path            string            `json:"path"`
+	RawPath         string            `json:"rawPath,omitempty"`
+	RequestURI      string            `json:"requestURI"`
+	ReplacedPath    string            `json:"replacedPath,omitempty"`
+	ForwardedPrefix string            `json:"forwardedPrefix,omitempty"`
+	Captures        map[string]string `json:"captures,omitempty"`
+}
+
+// getPathDryRun applies the given chain of path middlewares to the given URL, without forwarding any request,
+// and returns the resulting path and headers.
+func (h Handler) getPathDryRun(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	query := request.URL.Query()
+
+	target := query.Get("url")
+	if !strings.HasPrefix(target, "/") {
+		writeError(rw, "url must be a path starting with /", http.StatusBadRequest)
+		return
+	}
+
+	var names []string
+	for _, name := range strings.Split(query.Get("middlewares"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	resolved, err := h.resolvePathMiddlewares(names, 0)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := pathDryRunRepresentation{Middlewares: resolved}
+	if result.Middlewares == nil {
+		result.Middlewares = []string{}
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		result.Path = req.URL.Path
+		result.RawPath = req.URL.RawPath
+		result.RequestURI = req.RequestURI
+		result.ReplacedPath = req.Header.Get(replacepath.ReplacedPathHeader)
+		result.ForwardedPrefix = req.Header.Get(stripprefix.ForwardedPrefixHeader)
+		result.Captures = middlewares.GetPathCaptures(req.Context())
+	})
+
+	for i := len(resolved) - 1; i >= 0; i-- {
+		handler, err = h.buildPathMiddleware(request.Context(), resolved[i], handler)
+		if err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.RequestURI = req.URL.RequestURI()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		writeError(rw, strings.TrimSpace(recorder.Body.String()), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// resolvePathMiddlewares returns the names of the given middlewares, the chains being replaced by their middlewares.
+func (h Handler) resolvePathMiddlewares(names []string, depth int) ([]string, error) {
+	if depth > maxChainDepth {
+		return nil, errors.New("too many nested chains")
+	}
+
+	var resolved []string
+	for _, name := range names {
+		mi, ok := h.runtimeConfiguration.Middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("middleware not found: %s", name)
+		}
+
+		if mi.Chain == nil {
+			resolved = append(resolved, name)
+			continue
+		}
+
+		var chained []string
+		for _, chainedName := range mi.Chain.Middlewares {
+			// The chained middlewares are defined by the provider of the chain, unless stated otherwise.
+			if !strings.Contains(chainedName, "@") && strings.Contains(name, "@") {
+				chainedName += "@" + getProviderName(name)
+			}
+			chained = append(chained, chainedName)
+		}
+
+		chainedNames, err := h.resolvePathMiddlewares(chained, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, chainedNames...)
+	}
+
+	return resolved, nil
+}
+
+// buildPathMiddleware builds the given middleware, which must be a middleware rewriting the path.
+func (h Handler) buildPathMiddleware(ctx context.Context, name string, next http.Handler) (http.Handler, error) {
+	config := h.runtimeConfiguration.Middlewares[name].Middleware
+
+	switch {
+	case config.AddPrefix != nil:
+		return addprefix.New(ctx, next, *config.AddPrefix, name)
+	case config.ReplacePath != nil:
+		return replacepath.New(ctx, next, *config.ReplacePath, name)
+	case config.ReplacePathRegex != nil:
+		return replacepathregex.New(ctx, next, *config.ReplacePathRegex, name)
+	case config.StripPrefix != nil:
+		return stripprefix.New(ctx, next, *config.StripPrefix, name)
+	case config.StripPrefixRegex != nil:
+		return stripprefixregex.New(ctx, next, *config.StripPrefixRegex, name)
+	default:
+		return nil, fmt.Errorf("middleware %s does not rewrite the path", name)
+	}
+}
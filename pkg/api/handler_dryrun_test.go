@@ -0,0 +1,134 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_pathDryRun(t *testing.T) {
+	conf := runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"strip@file": {
+				Middleware: &dynamic.Middleware{
+					StripPrefixRegex: &dynamic.StripPrefixRegex{Regex: []string{`^/(?P<tenant>[a-z]+)`}},
+				},
+			},
+			"replace@file": {
+				Middleware: &dynamic.Middleware{
+					ReplacePathRegex: &dynamic.ReplacePathRegex{Regex: `^/api/(.*)`, Replacement: "/$1"},
+				},
+			},
+			"prefix@file": {
+				Middleware: &dynamic.Middleware{
+					AddPrefix: &dynamic.AddPrefix{Prefix: "/tenants/${tenant}"},
+				},
+			},
+			"chain@file": {
+				Middleware: &dynamic.Middleware{
+					Chain: &dynamic.Chain{Middlewares: []string{"replace", "prefix@file"}},
+				},
+			},
+			"loop@file": {
+				Middleware: &dynamic.Middleware{
+					Chain: &dynamic.Chain{Middlewares: []string{"loop"}},
+				},
+			},
+			"auth@file": {
+				Middleware: &dynamic.Middleware{
+					BasicAuth: &dynamic.BasicAuth{},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		middlewares        string
+		url                string
+		expectedStatusCode int
+		expectedJSON       string
+	}{
+		{
+			desc:               "no middlewares",
+			url:                "/foo/bar?a=b",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{"middlewares":[],"path":"/foo/bar","requestURI":"/foo/bar?a=b"}`,
+		},
+		{
+			desc:               "chain of path middlewares",
+			middlewares:        "strip@file, chain@file",
+			url:                "/foo/api/bar?a=b",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON: `{
+				"middlewares": ["strip@file", "replace@file", "prefix@file"],
+				"path": "/tenants/foo/bar",
+				"rawPath": "/tenants/foo/bar",
+				"requestURI": "/tenants/foo/bar?a=b",
+				"replacedPath": "/api/bar",
+				"forwardedPrefix": "/foo",
+				"captures": {"tenant": "foo"}
+			}`,
+		},
+		{
+			desc:               "unknown middleware",
+			middlewares:        "unknown@file",
+			url:                "/foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "not a path middleware",
+			middlewares:        "auth@file",
+			url:                "/foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "chain loop",
+			middlewares:        "loop@file",
+			url:                "/foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "invalid url",
+			middlewares:        "strip@file",
+			url:                "foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &conf)
+			server := httptest.NewServer(handler.createRouter())
+			defer server.Close()
+
+			query := url.Values{"middlewares": {test.middlewares}, "url": {test.url}}
+
+			resp, err := http.DefaultClient.Get(server.URL + "/api/http/dryrun/path?" + query.Encode())
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedJSON == "" {
+				return
+			}
+
+			contents, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, test.expectedJSON, string(contents))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// getEffectiveConfiguration writes the effective dynamic configuration, once the configurations of the providers are merged,
+// and the entry point models and the runtime overrides are applied.
+func (h Handler) getEffectiveConfiguration(rw http.ResponseWriter, request *http.Request) {
+	snapshot := h.effectiveConfig.Get()
+	if snapshot == nil {
+		writeError(rw, "no configuration has been applied yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(snapshot)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
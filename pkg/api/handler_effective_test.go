@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/effective"
+)
+
+func TestHandler_EffectiveConfiguration(t *testing.T) {
+	store := effective.NewStore()
+
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{EffectiveConfig: store})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/config/effective")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	store.Set(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"web-foo@file": {EntryPoints: []string{"web"}, Rule: "Host(`foo.com`)", Service: "foo@file"},
+			},
+		},
+	})
+
+	resp, err = http.DefaultClient.Get(server.URL + "/api/config/effective")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var snapshot effective.Snapshot
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshot))
+
+	assert.False(t, snapshot.UpdatedAt.IsZero())
+	require.NotNil(t, snapshot.Configuration.HTTP)
+	assert.Contains(t, snapshot.Configuration.HTTP.Routers, "web-foo@file")
+}
+
+func TestHandler_effectiveConfigurationDisabled(t *testing.T) {
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/config/effective")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
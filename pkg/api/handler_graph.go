@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// The types of the nodes of the topology graph.
+const (
+	nodeEntryPoint = "entryPoint"
+	nodeRouter     = "router"
+	nodeMiddleware = "middleware"
+	nodeService    = "service"
+	nodeServer     = "server"
+)
+
+// The health of the nodes of the topology graph.
+const (
+	healthUp       = "up"
+	healthDegraded = "degraded"
+	healthDown     = "down"
+	healthUnknown  = "unknown"
+)
+
+// The statuses of the servers reported by the health checks.
+const (
+	serverUp   = "UP"
+	serverDown = "DOWN"
+)
+
+type graphNode struct {
+	ID       string        `json:"id"`
+	Type     string        `json:"type"`
+	Name     string        `json:"name"`
+	Provider string        `json:"provider,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Health   string        `json:"health"`
+	Rate     *metrics.Rate `json:"rate,omitempty"`
+}
+
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type graphRepresentation struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+// graphBuilder builds the topology graph of the HTTP configuration:
+// entry points -> routers -> middlewares -> services -> servers.
+type graphBuilder struct {
+	nodes map[string]graphNode
+	edges map[graphEdge]struct{}
+}
+
+func (h Handler) getGraph(rw http.ResponseWriter, request *http.Request) {
+	var entryPointRates, serviceRates map[string]metrics.Rate
+	if h.rates != nil {
+		entryPointRates = h.rates.EntryPointRates()
+		serviceRates = h.rates.ServiceRates()
+	}
+
+	builder := &graphBuilder{
+		nodes: make(map[string]graphNode),
+		edges: make(map[graphEdge]struct{}),
+	}
+
+	for name := range h.staticConfig.EntryPoints {
+		builder.addNode(graphNode{
+			ID:     nodeID(nodeEntryPoint, name),
+			Type:   nodeEntryPoint,
+			Name:   name,
+			Health: healthUp,
+			Rate:   rateOf(entryPointRates, name),
+		})
+	}
+
+	for name, mi := range h.runtimeConfiguration.Middlewares {
+		builder.addNode(graphNode{
+			ID:       nodeID(nodeMiddleware, name),
+			Type:     nodeMiddleware,
+			Name:     name,
+			Provider: getProviderName(name),
+			Status:   mi.Status,
+			Health:   statusHealth(mi.Status),
+		})
+	}
+
+	for name, si := range h.runtimeConfiguration.Services {
+		builder.addService(name, si, serviceRates)
+	}
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		builder.addRouter(name, rt)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(builder.representation())
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (b *graphBuilder) addRouter(name string, rt *runtime.RouterInfo) {
+	id := nodeID(nodeRouter, name)
+	provider := getProviderName(name)
+
+	b.addNode(graphNode{
+		ID:       id,
+		Type:     nodeRouter,
+		Name:     name,
+		Provider: provider,
+		Status:   rt.Status,
+		Health:   statusHealth(rt.Status),
+	})
+
+	entryPoints := rt.Using
+	if len(entryPoints) == 0 {
+		entryPoints = rt.EntryPoints
+	}
+
+	for _, entryPoint := range entryPoints {
+		b.addEdge(nodeID(nodeEntryPoint, entryPoint), id)
+	}
+
+	// The middlewares are chained in the order of the router, skipping the unknown ones.
+	previous := id
+	for _, middleware := range rt.Middlewares {
+		current := nodeID(nodeMiddleware, qualifiedName(provider, middleware))
+		if _, ok := b.nodes[current]; !ok {
+			continue
+		}
+
+		b.addEdge(previous, current)
+		previous = current
+	}
+
+	if rt.Service != "" {
+		b.addEdge(previous, nodeID(nodeService, qualifiedName(provider, rt.Service)))
+	}
+}
+
+func (b *graphBuilder) addService(name string, si *runtime.ServiceInfo, rates map[string]metrics.Rate) {
+	id := nodeID(nodeService, name)
+	provider := getProviderName(name)
+
+	node := graphNode{
+		ID:       id,
+		Type:     nodeService,
+		Name:     name,
+		Provider: provider,
+		Status:   si.Status,
+		Health:   statusHealth(si.Status),
+	}
+
+	switch {
+	case si.LoadBalancer != nil:
+		// Only the requests of the load balancers are measured.
+		node.Rate = rateOf(rates, name)
+
+		serverStatus := si.GetAllStatus()
+
+		var down int
+		for _, server := range si.LoadBalancer.Servers {
+			serverID := nodeID(nodeServer, name+"/"+server.URL)
+
+			status := serverStatus[server.URL]
+			if status == serverDown {
+				down++
+			}
+
+			b.addNode(graphNode{
+				ID:       serverID,
+				Type:     nodeServer,
+				Name:     server.URL,
+				Provider: provider,
+				Status:   status,
+				Health:   serverHealth(status),
+			})
+			b.addEdge(id, serverID)
+		}
+
+		// The health of the servers degrades the health of their service.
+		if node.Health == healthUp && down > 0 {
+			node.Health = healthDegraded
+			if down == len(si.LoadBalancer.Servers) {
+				node.Health = healthDown
+			}
+		}
+
+	case si.Weighted != nil:
+		for _, child := range si.Weighted.Services {
+			b.addEdge(id, nodeID(nodeService, qualifiedName(provider, child.Name)))
+		}
+
+	case si.Mirroring != nil:
+		b.addEdge(id, nodeID(nodeService, qualifiedName(provider, si.Mirroring.Service)))
+		for _, mirror := range si.Mirroring.Mirrors {
+			b.addEdge(id, nodeID(nodeService, qualifiedName(provider, mirror.Name)))
+		}
+	}
+
+	b.addNode(node)
+}
+
+func (b *graphBuilder) addNode(node graphNode) {
+	b.nodes[node.ID] = node
+}
+
+func (b *graphBuilder) addEdge(from, to string) {
+	b.edges[graphEdge{From: from, To: to}] = struct{}{}
+}
+
+// representation returns the sorted nodes, and the edges between existing nodes.
+func (b *graphBuilder) representation() graphRepresentation {
+	result := graphRepresentation{
+		Nodes: make([]graphNode, 0, len(b.nodes)),
+		Edges: make([]graphEdge, 0, len(b.edges)),
+	}
+
+	for _, node := range b.nodes {
+		result.Nodes = append(result.Nodes, node)
+	}
+
+	for edge := range b.edges {
+		_, fromExists := b.nodes[edge.From]
+		_, toExists := b.nodes[edge.To]
+		if fromExists && toExists {
+			result.Edges = append(result.Edges, edge)
+		}
+	}
+
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		return result.Nodes[i].ID < result.Nodes[j].ID
+	})
+
+	sort.Slice(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From == result.Edges[j].From {
+			return result.Edges[i].To < result.Edges[j].To
+		}
+		return result.Edges[i].From < result.Edges[j].From
+	})
+
+	return result
+}
+
+func nodeID(nodeType, name string) string {
+	return nodeType + "/" + name
+}
+
+func qualifiedName(provider, name string) string {
+	if strings.Contains(name, "@") || provider == "" {
+		return name
+	}
+
+	return name + "@" + provider
+}
+
+func rateOf(rates map[string]metrics.Rate, name string) *metrics.Rate {
+	if rates == nil {
+		return nil
+	}
+
+	// The entry points and services without recent requests have a zero rate.
+	rate := rates[name]
+
+	return &rate
+}
+
+func statusHealth(status string) string {
+	switch status {
+	case runtime.StatusEnabled:
+		return healthUp
+	case runtime.StatusWarning:
+		return healthDegraded
+	case runtime.StatusDisabled:
+		return healthDown
+	default:
+		return healthUnknown
+	}
+}
+
+// serverHealth returns the health of a server, which is unknown without health check.
+func serverHealth(status string) string {
+	switch status {
+	case serverUp:
+		return healthUp
+	case serverDown:
+		return healthDown
+	default:
+		return healthUnknown
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+func TestHandler_Graph(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rates    func() *metrics.RatesRegistry
+		jsonFile string
+	}{
+		{
+			desc:     "without rates",
+			jsonFile: "testdata/graph.json",
+		},
+		{
+			desc: "with rates",
+			rates: func() *metrics.RatesRegistry {
+				registry := metrics.RegisterRates()
+				registry.EntryPointReqsCounter().With("entrypoint", "web", "code", "200").Add(10)
+				registry.ServiceReqsCounter().With("service", "foo-service@myprovider", "code", "500").Add(5)
+
+				return registry
+			},
+			jsonFile: "testdata/graph-rates.json",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			staticConfig := static.Configuration{
+				API:    &static.API{},
+				Global: &static.Global{},
+				EntryPoints: static.EntryPoints{
+					"web":       {},
+					"websecure": {},
+				},
+			}
+
+			handler := New(staticConfig, newGraphRuntimeConfiguration())
+			if test.rates != nil {
+				handler.rates = test.rates()
+			}
+
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			resp, err := http.DefaultClient.Get(server.URL + "/api/http/graph")
+			require.NoError(t, err)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			contents, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = ioutil.WriteFile(test.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := ioutil.ReadFile(test.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
+
+func newGraphRuntimeConfiguration() *runtime.Configuration {
+	conf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Middlewares: []string{"auth", "missing", "compress@file"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+				},
+				Status: runtime.StatusEnabled,
+				Using:  []string{"web"},
+			},
+			"bar@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"websecure", "unknown"},
+					Service:     "weighted",
+					Rule:        "Host(`bar.foo`)",
+				},
+				Status: runtime.StatusWarning,
+				Using:  []string{"websecure"},
+			},
+		},
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"auth@myprovider": {
+				Middleware: &dynamic.Middleware{
+					BasicAuth: &dynamic.BasicAuth{Users: []string{"admin:admin"}},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"compress@file": {
+				Middleware: &dynamic.Middleware{
+					Compress: &dynamic.Compress{},
+				},
+				Status: runtime.StatusEnabled,
+			},
+		},
+		Services: map[string]*runtime.ServiceInfo{
+			"foo-service@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://127.0.0.1"}, {URL: "http://127.0.0.2"}},
+					},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"bar-service@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://127.0.0.3"}},
+					},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"weighted@myprovider": {
+				Service: &dynamic.Service{
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{{Name: "foo-service"}, {Name: "bar-service"}},
+					},
+				},
+				Status: runtime.StatusEnabled,
+			},
+		},
+	}
+
+	conf.Services["foo-service@myprovider"].UpdateServerStatus("http://127.0.0.1", "UP")
+	conf.Services["foo-service@myprovider"].UpdateServerStatus("http://127.0.0.2", "DOWN")
+
+	return conf
+}
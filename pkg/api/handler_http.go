@@ -29,10 +29,11 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus map[string]string        `json:"serverStatus,omitempty"`
+	Promotion    *runtime.PromotionStatus `json:"promotion,omitempty"`
+	Name         string                   `json:"name,omitempty"`
+	Provider     string                   `json:"provider,omitempty"`
+	Type         string                   `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
@@ -41,6 +42,7 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		Promotion:    si.GetPromotionStatus(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
@@ -85,8 +87,16 @@ func (h Handler) getRouters(rw http.ResponseWriter, request *http.Request) {
 	}
 
 	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+	rw.Header().Set(totalCountHeader, strconv.Itoa(len(results)))
 
-	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	page, err := selectFields(request, results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(page)
 	if err != nil {
 		log.FromContext(request.Context()).Error(err)
 		writeError(rw, err.Error(), http.StatusInternalServerError)
@@ -137,8 +147,16 @@ func (h Handler) getServices(rw http.ResponseWriter, request *http.Request) {
 	}
 
 	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+	rw.Header().Set(totalCountHeader, strconv.Itoa(len(results)))
 
-	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	page, err := selectFields(request, results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(page)
 	if err != nil {
 		log.FromContext(request.Context()).Error(err)
 		writeError(rw, err.Error(), http.StatusInternalServerError)
@@ -222,7 +240,7 @@ func keepRouter(name string, item *runtime.RouterInfo, criterion *searchCriterio
 		return true
 	}
 
-	return criterion.withStatus(item.Status) && criterion.searchIn(item.Rule, name)
+	return criterion.withStatus(item.Status) && criterion.withProvider(name) && criterion.searchIn(item.Rule, name)
 }
 
 func keepService(name string, item *runtime.ServiceInfo, criterion *searchCriterion) bool {
@@ -230,7 +248,7 @@ func keepService(name string, item *runtime.ServiceInfo, criterion *searchCriter
 		return true
 	}
 
-	return criterion.withStatus(item.Status) && criterion.searchIn(name)
+	return criterion.withStatus(item.Status) && criterion.withProvider(name) && criterion.searchIn(name)
 }
 
 func keepMiddleware(name string, item *runtime.MiddlewareInfo, criterion *searchCriterion) bool {
@@ -23,6 +23,7 @@ func TestHandler_HTTP(t *testing.T) {
 	type expected struct {
 		statusCode int
 		nextPage   string
+		totalCount string
 		jsonFile   string
 	}
 
@@ -201,6 +202,83 @@ func TestHandler_HTTP(t *testing.T) {
 				jsonFile:   "testdata/routers-filtered-search.json",
 			},
 		},
+		{
+			desc: "routers filtered by provider",
+			path: "/api/http/routers?provider=myprovider",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"test@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "fii-service@myprovider",
+							Rule:        "Host(`fii.bar.other`)",
+							Middlewares: []string{"addPrefixTest", "auth"},
+						},
+						Status: runtime.StatusEnabled,
+					},
+					"bar@anotherprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`)",
+							Middlewares: []string{"auth", "addPrefixTest@anotherprovider"},
+						},
+						Status: runtime.StatusEnabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				totalCount: "1",
+				jsonFile:   "testdata/routers-filtered-provider.json",
+			},
+		},
+		{
+			desc: "routers with selected fields",
+			path: "/api/http/routers?fields=name,rule,status",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"test@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar.other`)",
+							Middlewares: []string{"addPrefixTest", "auth"},
+						},
+						Status: runtime.StatusEnabled,
+					},
+					"bar@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"web"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`)",
+							Middlewares: []string{"auth", "addPrefixTest@anotherprovider"},
+						},
+						Status: runtime.StatusDisabled,
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				totalCount: "2",
+				jsonFile:   "testdata/routers-selected-fields.json",
+			},
+		},
+		{
+			desc: "routers filtered by status, with selected fields, 1 res per page, want page 1",
+			path: "/api/http/routers?status=enabled&fields=name&per_page=1",
+			conf: runtime.Configuration{
+				Routers: generateHTTPRouters(5),
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "2",
+				totalCount: "5",
+				jsonFile:   "testdata/routers-page-selected-fields.json",
+			},
+		},
 		{
 			desc: "one router by id",
 			path: "/api/http/routers/bar@myprovider",
@@ -507,6 +585,56 @@ func TestHandler_HTTP(t *testing.T) {
 				jsonFile:   "testdata/services-filtered-search.json",
 			},
 		},
+		{
+			desc: "services filtered by provider, with selected fields",
+			path: "/api/http/services?provider=anotherprovider&fields=name,provider,serverStatus",
+			conf: runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"bar@myprovider": func() *runtime.ServiceInfo {
+						si := &runtime.ServiceInfo{
+							Service: &dynamic.Service{
+								LoadBalancer: &dynamic.ServersLoadBalancer{
+									PassHostHeader: Bool(true),
+									Servers: []dynamic.Server{
+										{
+											URL: "http://127.0.0.1",
+										},
+									},
+								},
+							},
+							UsedBy: []string{"foo@myprovider", "test@myprovider"},
+							Status: runtime.StatusEnabled,
+						}
+						si.UpdateServerStatus("http://127.0.0.1", "UP")
+						return si
+					}(),
+					"baz@anotherprovider": func() *runtime.ServiceInfo {
+						si := &runtime.ServiceInfo{
+							Service: &dynamic.Service{
+								LoadBalancer: &dynamic.ServersLoadBalancer{
+									PassHostHeader: Bool(true),
+									Servers: []dynamic.Server{
+										{
+											URL: "http://127.0.0.2",
+										},
+									},
+								},
+							},
+							UsedBy: []string{"foo@myprovider"},
+							Status: runtime.StatusEnabled,
+						}
+						si.UpdateServerStatus("http://127.0.0.2", "UP")
+						return si
+					}(),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				totalCount: "1",
+				jsonFile:   "testdata/services-filtered-provider-selected-fields.json",
+			},
+		},
 		{
 			desc: "one service by id",
 			path: "/api/http/services/bar@myprovider",
@@ -821,6 +949,10 @@ func TestHandler_HTTP(t *testing.T) {
 
 			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
 
+			if test.expected.totalCount != "" {
+				assert.Equal(t, test.expected.totalCount, resp.Header.Get(totalCountHeader))
+			}
+
 			if test.expected.jsonFile == "" {
 				return
 			}
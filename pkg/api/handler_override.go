@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/override"
+)
+
+func (h Handler) clearOverrides(rw http.ResponseWriter, request *http.Request) {
+	h.overrides.Clear()
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) disableRouter(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if _, ok := h.runtimeConfiguration.Routers[routerID]; !ok {
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	// Disabling the internal routers could make the API itself unreachable.
+	if getProviderName(routerID) == "internal" {
+		writeError(rw, fmt.Sprintf("internal router cannot be disabled: %s", routerID), http.StatusBadRequest)
+		return
+	}
+
+	h.overrides.DisableRouter(routerID)
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) enableRouter(rw http.ResponseWriter, request *http.Request) {
+	// The router is not checked, as it is not part of the configuration while it is disabled.
+	h.overrides.EnableRouter(mux.Vars(request)["routerID"])
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) drainServer(rw http.ResponseWriter, request *http.Request) {
+	serviceID := mux.Vars(request)["serviceID"]
+	serverURL := request.URL.Query().Get("server")
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if serverURL == "" {
+		writeError(rw, "server is required", http.StatusBadRequest)
+		return
+	}
+
+	service, ok := h.runtimeConfiguration.Services[serviceID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("service not found: %s", serviceID), http.StatusNotFound)
+		return
+	}
+
+	if service.LoadBalancer == nil {
+		writeError(rw, fmt.Sprintf("service has no servers: %s", serviceID), http.StatusBadRequest)
+		return
+	}
+
+	var found bool
+	for _, server := range service.LoadBalancer.Servers {
+		if server.URL == serverURL {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		writeError(rw, fmt.Sprintf("server not found in service %s: %q", serviceID, serverURL), http.StatusNotFound)
+		return
+	}
+
+	h.overrides.DrainServer(serviceID, serverURL)
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) undrainServer(rw http.ResponseWriter, request *http.Request) {
+	serverURL := request.URL.Query().Get("server")
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if serverURL == "" {
+		writeError(rw, "server is required", http.StatusBadRequest)
+		return
+	}
+
+	// The server is not checked, as it is not part of the configuration while it is draining.
+	h.overrides.UndrainServer(mux.Vars(request)["serviceID"], serverURL)
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) pauseProvider(rw http.ResponseWriter, request *http.Request) {
+	providerID := mux.Vars(request)["providerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	// Pausing the internal provider could make the API itself unreachable.
+	if providerID == "internal" {
+		writeError(rw, "internal provider cannot be paused", http.StatusBadRequest)
+		return
+	}
+
+	mode := request.URL.Query().Get("config")
+	switch mode {
+	case "":
+		mode = override.PauseKeep
+	case override.PauseKeep, override.PauseDrop:
+	default:
+		writeError(rw, fmt.Sprintf("invalid config %q, it must be %q or %q", mode, override.PauseKeep, override.PauseDrop), http.StatusBadRequest)
+		return
+	}
+
+	// The provider is not checked, as it may not have provided any configuration yet.
+	h.overrides.PauseProvider(providerID, mode)
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) resumeProvider(rw http.ResponseWriter, request *http.Request) {
+	h.overrides.ResumeProvider(mux.Vars(request)["providerID"])
+
+	h.writeOverrides(rw, request)
+}
+
+func (h Handler) resetCircuitBreaker(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	if middleware.CircuitBreaker == nil {
+		writeError(rw, fmt.Sprintf("middleware is not a circuit breaker: %s", middlewareID), http.StatusBadRequest)
+		return
+	}
+
+	// Only the circuit breakers of the middleware are reset, the configuration is not reloaded.
+	h.circuitBreakers.Reset(middlewareID)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// writeOverrides writes the current overrides.
+func (h Handler) writeOverrides(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(h.overrides.Get())
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
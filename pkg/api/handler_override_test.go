@@ -0,0 +1,235 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
+	"github.com/traefik/traefik/v2/pkg/server/override"
+)
+
+func TestHandler_overrides(t *testing.T) {
+	conf := runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@file":     {Router: &dynamic.Router{Service: "svc@file"}},
+			"api@internal": {Router: &dynamic.Router{Service: "api@internal"}},
+		},
+		Services: map[string]*runtime.ServiceInfo{
+			"svc@file": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://127.0.0.1"}},
+					},
+				},
+			},
+			"wrr@file": {
+				Service: &dynamic.Service{Weighted: &dynamic.WeightedRoundRobin{}},
+			},
+		},
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"cb@file": {
+				Middleware: &dynamic.Middleware{CircuitBreaker: &dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5"}},
+			},
+			"auth@file": {
+				Middleware: &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		expectedStatusCode int
+		expectedJSON       string
+		expectedReloads    int
+	}{
+		{
+			desc:               "get overrides",
+			method:             http.MethodGet,
+			path:               "/api/overrides",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{}`,
+		},
+		{
+			desc:               "disable router",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/routers/foo@file/disable",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{"disabledRouters": ["foo@file"]}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "disable unknown router",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/routers/bar@file/disable",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "disable internal router",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/routers/api@internal/disable",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "enable router",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/routers/bar@file/enable",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "drain server",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/services/svc@file/drain?server=http://127.0.0.1",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{"drainingServers": {"svc@file": ["http://127.0.0.1"]}}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "drain unknown server",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/services/svc@file/drain?server=http://127.0.0.2",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "drain server without server",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/services/svc@file/drain",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "drain server of a service without servers",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/services/wrr@file/drain?server=http://127.0.0.1",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "undrain server",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/services/svc@file/undrain?server=http://127.0.0.1",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "reset circuit breaker",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/middlewares/cb@file/reset",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			desc:               "reset unknown middleware",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/middlewares/unknown@file/reset",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "reset middleware which is not a circuit breaker",
+			method:             http.MethodPost,
+			path:               "/api/overrides/http/middlewares/auth@file/reset",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "pause provider",
+			method:             http.MethodPost,
+			path:               "/api/overrides/providers/docker/pause",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{"pausedProviders": {"docker": "keep"}}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "pause provider dropping its configuration",
+			method:             http.MethodPost,
+			path:               "/api/overrides/providers/docker/pause?config=drop",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{"pausedProviders": {"docker": "drop"}}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "pause provider with invalid config",
+			method:             http.MethodPost,
+			path:               "/api/overrides/providers/docker/pause?config=foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "pause internal provider",
+			method:             http.MethodPost,
+			path:               "/api/overrides/providers/internal/pause",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "resume provider",
+			method:             http.MethodPost,
+			path:               "/api/overrides/providers/docker/resume",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{}`,
+			expectedReloads:    1,
+		},
+		{
+			desc:               "clear overrides",
+			method:             http.MethodDelete,
+			path:               "/api/overrides",
+			expectedStatusCode: http.StatusOK,
+			expectedJSON:       `{}`,
+			expectedReloads:    1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			overrides := override.NewStore()
+
+			reloads := make(chan struct{}, 10)
+			overrides.AddListener(func() {
+				reloads <- struct{}{}
+			})
+
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{Overrides: overrides, CircuitBreakers: circuitbreaker.NewRegistry()})(&conf)
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Len(t, reloads, test.expectedReloads)
+
+			if test.expectedJSON == "" {
+				return
+			}
+
+			contents, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, test.expectedJSON, string(contents))
+		})
+	}
+}
+
+func TestHandler_overridesDisabled(t *testing.T) {
+	handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, Options{})(&runtime.Configuration{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/overrides")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -35,6 +35,8 @@ type overview struct {
 	UDP       schemeOverview `json:"udp"`
 	Features  features       `json:"features,omitempty"`
 	Providers []string       `json:"providers,omitempty"`
+	// ConfigHash is the hash of the applied dynamic configuration.
+	ConfigHash string `json:"configHash,omitempty"`
 }
 
 func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
@@ -52,8 +54,9 @@ func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
 			Routers:  getUDPRouterSection(h.runtimeConfiguration.UDPRouters),
 			Services: getUDPServiceSection(h.runtimeConfiguration.UDPServices),
 		},
-		Features:  getFeatures(h.staticConfig),
-		Providers: getProviders(h.staticConfig),
+		Features:   getFeatures(h.staticConfig),
+		Providers:  getProviders(h.staticConfig),
+		ConfigHash: h.configHash.Get(),
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// rawDataSchemaVersion is the version of the schema of the rawdata representation.
+// It is incremented on each change breaking the consumers of the representation.
+const rawDataSchemaVersion = 1
+
+const rawDataBufferSize = 32 * 1024
+
+func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.Request) {
+	siRepr := make(map[string]*serviceInfoRepresentation, len(h.runtimeConfiguration.Services))
+	for k, v := range h.runtimeConfiguration.Services {
+		siRepr[k] = &serviceInfoRepresentation{
+			ServiceInfo:  v,
+			ServerStatus: v.GetAllStatus(),
+			Promotion:    v.GetPromotionStatus(),
+		}
+	}
+
+	result := RunTimeRepresentation{
+		SchemaVersion:  rawDataSchemaVersion,
+		Routers:        h.runtimeConfiguration.Routers,
+		Middlewares:    h.runtimeConfiguration.Middlewares,
+		Services:       siRepr,
+		TCPRouters:     h.runtimeConfiguration.TCPRouters,
+		TCPMiddlewares: h.runtimeConfiguration.TCPMiddlewares,
+		TCPServices:    h.runtimeConfiguration.TCPServices,
+		UDPRouters:     h.runtimeConfiguration.UDPRouters,
+		UDPServices:    h.runtimeConfiguration.UDPServices,
+	}
+
+	logger := log.FromContext(request.Context())
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	var out io.Writer = rw
+
+	var gz *gzip.Writer
+	if acceptsGzip(request.Header.Get("Accept-Encoding")) {
+		rw.Header().Set("Content-Encoding", "gzip")
+
+		gz = gzip.NewWriter(rw)
+		out = gz
+	}
+
+	if err := writeRawData(out, result); err != nil {
+		// As the response is streamed, its status cannot be changed once the first bytes are written,
+		// so the response is aborted, without closing the gzip stream,
+		// to prevent the client from reading a truncated representation as a complete one.
+		logger.Errorf("Unable to write the rawdata: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// writeRawData writes the JSON representation of the rawdata, one element at a time,
+// so that the whole representation is never held in memory.
+func writeRawData(w io.Writer, result RunTimeRepresentation) error {
+	bw := bufio.NewWriterSize(w, rawDataBufferSize)
+
+	_, err := bw.WriteString(`{"schemaVersion":` + strconv.Itoa(result.SchemaVersion))
+	if err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name     string
+		elements interface{}
+	}{
+		{name: "routers", elements: result.Routers},
+		{name: "middlewares", elements: result.Middlewares},
+		{name: "services", elements: result.Services},
+		{name: "tcpRouters", elements: result.TCPRouters},
+		{name: "tcpMiddlewares", elements: result.TCPMiddlewares},
+		{name: "tcpServices", elements: result.TCPServices},
+		{name: "udpRouters", elements: result.UDPRouters},
+		{name: "udpServices", elements: result.UDPServices},
+	}
+
+	for _, section := range sections {
+		if err := writeRawDataSection(bw, section.name, section.elements); err != nil {
+			return err
+		}
+	}
+
+	if _, err := bw.WriteString("}\n"); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeRawDataSection writes a map of elements as a JSON object sorted by name, and omits it when empty.
+func writeRawDataSection(bw *bufio.Writer, name string, elements interface{}) error {
+	value := reflect.ValueOf(elements)
+	if value.Len() == 0 {
+		return nil
+	}
+
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	if _, err := bw.WriteString(`,"` + name + `":{`); err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if i > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		rawKey, err := json.Marshal(key.String())
+		if err != nil {
+			return err
+		}
+
+		rawElement, err := json.Marshal(value.MapIndex(key).Interface())
+		if err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(rawKey); err != nil {
+			return err
+		}
+
+		if err := bw.WriteByte(':'); err != nil {
+			return err
+		}
+
+		if _, err := bw.Write(rawElement); err != nil {
+			return err
+		}
+	}
+
+	return bw.WriteByte('}')
+}
+
+// acceptsGzip returns whether the given Accept-Encoding header value allows a gzip encoded response.
+// An explicit gzip coding takes precedence over the wildcard one.
+func acceptsGzip(acceptEncoding string) bool {
+	gzipAccepted, wildcardAccepted := -1, -1
+
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		parts := strings.Split(coding, ";")
+
+		accepted := 1
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err != nil || q <= 0 {
+				accepted = 0
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case "gzip":
+			gzipAccepted = accepted
+		case "*":
+			wildcardAccepted = accepted
+		}
+	}
+
+	if gzipAccepted >= 0 {
+		return gzipAccepted == 1
+	}
+
+	return wildcardAccepted == 1
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_RawData_gzip(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"bar@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service@myprovider",
+					Rule:        "Host(`foo.bar`)",
+				},
+				Status: runtime.StatusEnabled,
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/rawdata", nil)
+	require.NoError(t, err)
+
+	// Setting the header disables the transparent decompression of the client.
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+
+	reader, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+
+	contents, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	expected := `{
+		"schemaVersion": 1,
+		"routers": {
+			"bar@myprovider": {
+				"entryPoints": ["web"],
+				"service": "foo-service@myprovider",
+				"rule": "Host(` + "`foo.bar`" + `)",
+				"status": "enabled"
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, string(contents))
+}
+
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestHandler_RawData_writeError(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"bar@myprovider": {
+				Router: &dynamic.Router{Service: "foo-service@myprovider", Rule: "Host(`foo.bar`)"},
+				Status: runtime.StatusEnabled,
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+
+	for _, encoding := range []string{"", "gzip"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/rawdata", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+
+		rw := failingResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.getRuntimeConfiguration(rw, req)
+		}, "encoding %q", encoding)
+	}
+}
+
+func Test_acceptsGzip(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		expected       bool
+	}{
+		{acceptEncoding: "", expected: false},
+		{acceptEncoding: "identity", expected: false},
+		{acceptEncoding: "gzip", expected: true},
+		{acceptEncoding: "GZIP", expected: true},
+		{acceptEncoding: "deflate, gzip;q=0.5", expected: true},
+		{acceptEncoding: "zstd, br", expected: false},
+		{acceptEncoding: "gzip;q=0", expected: false},
+		{acceptEncoding: "gzip;q=0.0, *", expected: false},
+		{acceptEncoding: "*", expected: true},
+		{acceptEncoding: "*;q=0", expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.acceptEncoding, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, acceptsGzip(test.acceptEncoding))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/plugins"
+	"github.com/traefik/traefik/v2/pkg/server/middleware"
+)
+
+const (
+	// maxSimulationBodySize is the maximum size of the simulation request,
+	// and of the bodies reported in the simulation result.
+	maxSimulationBodySize = 1024 * 1024
+
+	// simulationRemoteAddr is the remote address of the simulated requests, unless stated otherwise.
+	simulationRemoteAddr = "192.0.2.1:1234"
+)
+
+type simulationRequest struct {
+	Method string `json:"method,omitempty"`
+	// URL is the absolute URL, or the path with the query, of the simulated request.
+	URL        string      `json:"url"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	RemoteAddr string      `json:"remoteAddr,omitempty"`
+}
+
+type simulatedRequestRepresentation struct {
+	Method     string      `json:"method"`
+	Host       string      `json:"host"`
+	Path       string      `json:"path"`
+	RequestURI string      `json:"requestURI"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type simulatedResponseRepresentation struct {
+	StatusCode    int         `json:"statusCode"`
+	Headers       http.Header `json:"headers,omitempty"`
+	Body          string      `json:"body,omitempty"`
+	BodyTruncated bool        `json:"bodyTruncated,omitempty"`
+}
+
+type simulationRepresentation struct {
+	Middleware string `json:"middleware"`
+	// Forwarded is true when the request went through all the middlewares, and would have been forwarded to the service.
+	Forwarded bool `json:"forwarded"`
+	// Request is the request as it would have been forwarded to the service.
+	Request *simulatedRequestRepresentation `json:"request,omitempty"`
+	// Response is the response sent to the client,
+	// the service answering the forwarded requests with an empty 200 response.
+	Response simulatedResponseRepresentation `json:"response"`
+}
+
+// simulateMiddleware runs a synthetic request through the given middleware, without forwarding it to any service,
+// and returns the forwarded request or the response of the middleware.
+func (h Handler) simulateMiddleware(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	if _, ok := h.runtimeConfiguration.Middlewares[middlewareID]; !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	var simulation simulationRequest
+	if err := json.NewDecoder(http.MaxBytesReader(rw, request.Body, maxSimulationBodySize)).Decode(&simulation); err != nil {
+		writeError(rw, fmt.Sprintf("invalid simulation request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	req, err := newSimulatedRequest(simulation)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := simulationRepresentation{Middleware: middlewareID}
+
+	// The request may be forwarded concurrently by some middlewares, such as the mirroring ones.
+	var mu sync.Mutex
+	service := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(http.MaxBytesReader(rw, req.Body, maxSimulationBodySize))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		result.Forwarded = true
+		result.Request = &simulatedRequestRepresentation{
+			Method:     req.Method,
+			Host:       req.Host,
+			Path:       req.URL.Path,
+			RequestURI: req.URL.RequestURI(),
+			Headers:    req.Header.Clone(),
+			Body:       string(body),
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	// The middlewares are built from copies of their configuration,
+	// so that the errors of the simulation are not reported on the running middlewares.
+	configs := make(map[string]*runtime.MiddlewareInfo, len(h.runtimeConfiguration.Middlewares))
+	for name, mi := range h.runtimeConfiguration.Middlewares {
+		configs[name] = &runtime.MiddlewareInfo{Middleware: mi.Middleware}
+	}
+
+	var pluginBuilder middleware.PluginsBuilder = noPlugins{}
+	if h.pluginBuilder != nil {
+		pluginBuilder = h.pluginBuilder
+	}
+
+	builder := middleware.NewBuilder(configs, simulatedServices{}, pluginBuilder, nil)
+
+	handler, err := builder.BuildChain(request.Context(), []string{middlewareID}).Then(service)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req.WithContext(request.Context()))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	result.Response = simulatedResponseRepresentation{
+		StatusCode: recorder.Code,
+		Headers:    recorder.Header(),
+		Body:       recorder.Body.String(),
+	}
+
+	if len(result.Response.Body) > maxSimulationBodySize {
+		result.Response.Body = result.Response.Body[:maxSimulationBodySize]
+		result.Response.BodyTruncated = true
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func newSimulatedRequest(simulation simulationRequest) (*http.Request, error) {
+	target := simulation.URL
+	switch {
+	case strings.HasPrefix(target, "/"):
+		target = "http://localhost" + target
+	case !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://"):
+		return nil, fmt.Errorf("url must be an absolute HTTP URL, or a path starting with /: %q", simulation.URL)
+	}
+
+	method := simulation.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, target, strings.NewReader(simulation.Body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	for name, values := range simulation.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+		req.Header.Del("Host")
+	}
+
+	if req.URL.Scheme == "https" {
+		req.TLS = &tls.ConnectionState{ServerName: req.URL.Hostname()}
+	}
+
+	req.RequestURI = req.URL.RequestURI()
+
+	req.RemoteAddr = simulation.RemoteAddr
+	if req.RemoteAddr == "" {
+		req.RemoteAddr = simulationRemoteAddr
+	}
+
+	return req, nil
+}
+
+// simulatedServices builds the services used by the middlewares during a simulation,
+// such as the services serving the error pages, which answer without forwarding any request.
+type simulatedServices struct{}
+
+func (simulatedServices) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(rw, "simulated response of the service %s", serviceName)
+	}), nil
+}
+
+// noPlugins is the plugin builder of the simulations when no plugin builder is given.
+type noPlugins struct{}
+
+func (noPlugins) Build(pName string, _ map[string]interface{}, _ string) (plugins.Constructor, error) {
+	return nil, fmt.Errorf("plugin %s is not available", pName)
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_simulateMiddleware(t *testing.T) {
+	conf := runtime.Configuration{
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"headers@file": {
+				Middleware: &dynamic.Middleware{
+					Headers: &dynamic.Headers{
+						CustomRequestHeaders:  map[string]string{"X-Tenant": "foo"},
+						CustomResponseHeaders: map[string]string{"X-Served-By": "traefik"},
+					},
+				},
+			},
+			"strip@file": {
+				Middleware: &dynamic.Middleware{
+					StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/api"}},
+				},
+			},
+			"auth@file": {
+				Middleware: &dynamic.Middleware{
+					BasicAuth: &dynamic.BasicAuth{Users: []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}},
+				},
+			},
+			"chain@file": {
+				Middleware: &dynamic.Middleware{
+					Chain: &dynamic.Chain{Middlewares: []string{"auth", "strip", "headers"}},
+				},
+			},
+			"loop@file": {
+				Middleware: &dynamic.Middleware{
+					Chain: &dynamic.Chain{Middlewares: []string{"loop"}},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc               string
+		middleware         string
+		body               string
+		expectedStatusCode int
+		expected           *simulationRepresentation
+	}{
+		{
+			desc:               "forwarded request",
+			middleware:         "chain@file",
+			body:               `{"method": "POST", "url": "http://example.com/api/foo?a=b", "headers": {"Authorization": ["Basic dGVzdDp0ZXN0"]}, "body": "hello"}`,
+			expectedStatusCode: http.StatusOK,
+			expected: &simulationRepresentation{
+				Middleware: "chain@file",
+				Forwarded:  true,
+				Request: &simulatedRequestRepresentation{
+					Method:     http.MethodPost,
+					Host:       "example.com",
+					Path:       "/foo",
+					RequestURI: "/foo?a=b",
+					Headers: http.Header{
+						"Authorization":      {"Basic dGVzdDp0ZXN0"},
+						"X-Forwarded-Prefix": {"/api"},
+						"X-Tenant":           {"foo"},
+					},
+					Body: "hello",
+				},
+				Response: simulatedResponseRepresentation{
+					StatusCode: http.StatusOK,
+					Headers:    http.Header{"X-Served-By": {"traefik"}},
+				},
+			},
+		},
+		{
+			desc:               "short-circuited request",
+			middleware:         "chain@file",
+			body:               `{"url": "/api/foo"}`,
+			expectedStatusCode: http.StatusOK,
+			expected: &simulationRepresentation{
+				Middleware: "chain@file",
+				Response: simulatedResponseRepresentation{
+					StatusCode: http.StatusUnauthorized,
+					Headers: http.Header{
+						"Content-Type":     {"text/plain"},
+						"Www-Authenticate": {`Basic realm="traefik"`},
+					},
+					Body: "401 Unauthorized\n",
+				},
+			},
+		},
+		{
+			desc:               "unknown middleware",
+			middleware:         "unknown@file",
+			body:               `{"url": "/"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "invalid middleware",
+			middleware:         "loop@file",
+			body:               `{"url": "/"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "invalid url",
+			middleware:         "strip@file",
+			body:               `{"url": "foo"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "invalid body",
+			middleware:         "strip@file",
+			body:               `{`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &conf)
+			server := httptest.NewServer(handler.createRouter())
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/api/http/middlewares/"+test.middleware+"/simulate", "application/json", strings.NewReader(test.body))
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expected == nil {
+				return
+			}
+
+			var result simulationRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+			assert.Equal(t, test.expected, &result)
+		})
+	}
+
+	// The errors of the simulations are not reported on the running middlewares.
+	assert.Empty(t, conf.Middlewares["loop@file"].Err)
+}
@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	"net/http"
 	"reflect"
 	"time"
 
@@ -24,8 +25,9 @@ type HTTPConfiguration struct {
 
 // Model is a set of default router's values.
 type Model struct {
-	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Middlewares []string               `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	TLS         *RouterTLSConfig       `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	AccessLog   *types.RouterAccessLog `json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -35,18 +37,61 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Redirect     *Redirection         `json:"redirect,omitempty" toml:"redirect,omitempty" yaml:"redirect,omitempty" label:"-" export:"true"`
+	Static       *StaticResponse      `json:"static,omitempty" toml:"static,omitempty" yaml:"static,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // Router holds the router configuration.
 type Router struct {
-	EntryPoints []string         `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
-	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	Service     string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
-	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
-	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
-	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EntryPoints      []string               `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Middlewares      []string               `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	Service          string                 `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Rule             string                 `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority         int                    `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
+	TLS              *RouterTLSConfig       `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	AccessLog        *types.RouterAccessLog `json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" export:"true"`
+	Tracing          *RouterTracing         `json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" export:"true"`
+	Upgrades         *RouterUpgrades        `json:"upgrades,omitempty" toml:"upgrades,omitempty" yaml:"upgrades,omitempty" export:"true"`
+	ModelMiddlewares string                 `json:"modelMiddlewares,omitempty" toml:"modelMiddlewares,omitempty" yaml:"modelMiddlewares,omitempty" export:"true"`
+	Timeouts         *UpstreamTimeouts      `json:"timeouts,omitempty" toml:"timeouts,omitempty" yaml:"timeouts,omitempty" export:"true"`
+}
+
+// The placements of the middlewares of the entry point models relatively to the middlewares of the routers.
+const (
+	// ModelMiddlewaresBefore applies the middlewares of the models before the ones of the routers, by default.
+	ModelMiddlewaresBefore = "before"
+	// ModelMiddlewaresAfter applies the middlewares of the models after the ones of the routers.
+	ModelMiddlewaresAfter = "after"
+	// ModelMiddlewaresNone does not apply the middlewares of the models.
+	ModelMiddlewaresNone = "none"
+)
+
+// +k8s:deepcopy-gen=true
+
+// RouterTracing holds the tracing configuration of a router.
+type RouterTracing struct {
+	Sampling *types.TracingSampling `json:"sampling,omitempty" toml:"sampling,omitempty" yaml:"sampling,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterUpgrades holds the protocol upgrades and the CONNECT requests allowed on a router.
+type RouterUpgrades struct {
+	AllowedProtocols []string `json:"allowedProtocols,omitempty" toml:"allowedProtocols,omitempty" yaml:"allowedProtocols,omitempty" export:"true"`
+	DeniedProtocols  []string `json:"deniedProtocols,omitempty" toml:"deniedProtocols,omitempty" yaml:"deniedProtocols,omitempty" export:"true"`
+	AllowConnect     bool     `json:"allowConnect,omitempty" toml:"allowConnect,omitempty" yaml:"allowConnect,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// UpstreamTimeouts holds the timeouts of the requests forwarded to the servers, which apply on top of the ones of the servers transport.
+// The timeouts of a router override the ones of its service.
+type UpstreamTimeouts struct {
+	ResponseHeaderTimeout ptypes.Duration `json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
+	ReadTimeout           ptypes.Duration `json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
+	WriteTimeout          ptypes.Duration `json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -60,6 +105,34 @@ type RouterTLSConfig struct {
 
 // +k8s:deepcopy-gen=true
 
+// Redirection holds the configuration of a service redirecting the requests.
+// The location is a template, which is given the scheme, host, path and query of the request.
+type Redirection struct {
+	Location   string `json:"location,omitempty" toml:"location,omitempty" yaml:"location,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a Redirection.
+func (r *Redirection) SetDefaults() {
+	r.StatusCode = http.StatusFound
+}
+
+// +k8s:deepcopy-gen=true
+
+// StaticResponse holds the configuration of a service answering the requests with a fixed response.
+type StaticResponse struct {
+	StatusCode int               `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	Headers    map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       string            `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// SetDefaults Default values for a StaticResponse.
+func (s *StaticResponse) SetDefaults() {
+	s.StatusCode = http.StatusOK
+}
+
+// +k8s:deepcopy-gen=true
+
 // Mirroring holds the Mirroring configuration.
 type Mirroring struct {
 	Service     string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
@@ -87,6 +160,8 @@ type MirrorService struct {
 type WeightedRoundRobin struct {
 	Services []WRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
 	Sticky   *Sticky      `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" export:"true"`
+	// Promotion progressively shifts the requests to one of the services, overriding its weight.
+	Promotion *Promotion `json:"promotion,omitempty" toml:"promotion,omitempty" yaml:"promotion,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -105,6 +180,37 @@ func (w *WRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// Promotion holds the schedule of the promotion of the canary service of a weighted service.
+// The canary service receives the percentage of the requests of the current step,
+// and the other services share the other requests following their weights.
+type Promotion struct {
+	// Canary is the name of the promoted service, which must be one of the services of the weighted service.
+	Canary string          `json:"canary,omitempty" toml:"canary,omitempty" yaml:"canary,omitempty" export:"true"`
+	Steps  []PromotionStep `json:"steps,omitempty" toml:"steps,omitempty" yaml:"steps,omitempty" export:"true"`
+	// MaxErrorRate is the ratio of 5XX responses of the canary service during a step, between 0 and 1,
+	// above which the promotion is aborted. Zero disables the abort.
+	MaxErrorRate float64 `json:"maxErrorRate,omitempty" toml:"maxErrorRate,omitempty" yaml:"maxErrorRate,omitempty" export:"true"`
+	// MinRequests is the number of responses of the canary service during a step before its error rate is checked.
+	MinRequests int `json:"minRequests,omitempty" toml:"minRequests,omitempty" yaml:"minRequests,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a Promotion.
+func (p *Promotion) SetDefaults() {
+	p.MinRequests = 10
+}
+
+// +k8s:deepcopy-gen=true
+
+// PromotionStep is a step of the promotion of a canary service.
+type PromotionStep struct {
+	// Percent is the percentage of the requests forwarded to the canary service during the step.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// Duration is the duration of the step, the last step lasting until the promotion changes.
+	Duration ptypes.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Sticky holds the sticky configuration.
 type Sticky struct {
 	Cookie *Cookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -118,6 +224,9 @@ type Cookie struct {
 	Secure   bool   `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty" export:"true"`
 	HTTPOnly bool   `json:"httpOnly,omitempty" toml:"httpOnly,omitempty" yaml:"httpOnly,omitempty" export:"true"`
 	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty" export:"true"`
+	// DrainPeriod is the duration during which the servers removed from a servers load balancer
+	// keep receiving the requests of their sticky sessions.
+	DrainPeriod *ptypes.Duration `json:"drainPeriod,omitempty" toml:"drainPeriod,omitempty" yaml:"drainPeriod,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -130,6 +239,14 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// MaxConcurrentRequests is the maximum number of requests forwarded concurrently to each server, zero meaning no limit.
+	MaxConcurrentRequests *int `json:"maxConcurrentRequests,omitempty" toml:"maxConcurrentRequests,omitempty" yaml:"maxConcurrentRequests,omitempty" export:"true"`
+	// ConcurrencyQueue holds the requests waiting for a server to be below MaxConcurrentRequests.
+	ConcurrencyQueue *ConcurrencyQueue `json:"concurrencyQueue,omitempty" toml:"concurrencyQueue,omitempty" yaml:"concurrencyQueue,omitempty" export:"true"`
+	// LoadShedding adapts the maximum number of requests handled concurrently by the service to its latency.
+	LoadShedding *LoadShedding `json:"loadShedding,omitempty" toml:"loadShedding,omitempty" yaml:"loadShedding,omitempty" export:"true"`
+	// Timeouts holds the timeouts of the requests forwarded to the servers, unless overridden by the routers.
+	Timeouts *UpstreamTimeouts `json:"timeouts,omitempty" toml:"timeouts,omitempty" yaml:"timeouts,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -157,6 +274,51 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// ConcurrencyQueue holds the configuration of the queue of the requests waiting for a server,
+// when the server already handles the maximum number of concurrent requests.
+type ConcurrencyQueue struct {
+	// Size is the maximum number of requests waiting for each server, beyond which the requests are rejected.
+	Size int `json:"size,omitempty" toml:"size,omitempty" yaml:"size,omitempty" export:"true"`
+	// Timeout is the maximum duration a request waits for a server, after which it is rejected.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a ConcurrencyQueue.
+func (c *ConcurrencyQueue) SetDefaults() {
+	c.Timeout = ptypes.Duration(10 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// LoadShedding holds the configuration of the adaptive concurrency limit of a service.
+// The limit is increased additively while the latency of the service stays below the target,
+// and decreased multiplicatively when the latency exceeds it.
+// The requests beyond the limit wait in a bounded queue, and are rejected when it is full or when they waited for too long.
+type LoadShedding struct {
+	// LatencyTarget is the latency of the service beyond which the limit is decreased.
+	LatencyTarget ptypes.Duration `json:"latencyTarget,omitempty" toml:"latencyTarget,omitempty" yaml:"latencyTarget,omitempty" export:"true"`
+	InitialLimit  int             `json:"initialLimit,omitempty" toml:"initialLimit,omitempty" yaml:"initialLimit,omitempty" export:"true"`
+	MinLimit      int             `json:"minLimit,omitempty" toml:"minLimit,omitempty" yaml:"minLimit,omitempty" export:"true"`
+	MaxLimit      int             `json:"maxLimit,omitempty" toml:"maxLimit,omitempty" yaml:"maxLimit,omitempty" export:"true"`
+	// QueueSize is the maximum number of requests waiting for the service, beyond which the requests are rejected.
+	QueueSize int `json:"queueSize,omitempty" toml:"queueSize,omitempty" yaml:"queueSize,omitempty" export:"true"`
+	// QueueTimeout is the maximum duration a request waits for the service, after which it is rejected.
+	QueueTimeout ptypes.Duration `json:"queueTimeout,omitempty" toml:"queueTimeout,omitempty" yaml:"queueTimeout,omitempty" export:"true"`
+	// RetryAfter is the delay advertised to the clients of the rejected requests by the Retry-After header.
+	RetryAfter ptypes.Duration `json:"retryAfter,omitempty" toml:"retryAfter,omitempty" yaml:"retryAfter,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a LoadShedding.
+func (l *LoadShedding) SetDefaults() {
+	l.InitialLimit = 20
+	l.MinLimit = 1
+	l.MaxLimit = 1000
+	l.QueueTimeout = ptypes.Duration(time.Second)
+	l.RetryAfter = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds configuration for the forward of the response.
 type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
@@ -208,6 +370,35 @@ type ServersTransport struct {
 	Certificates        tls.Certificates    `description:"Certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
 	MaxIdleConnsPerHost int                 `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	Protocol            string              `description:"Protocol used to contact the servers: http/1.1, h2, h2c, or h3. If empty, HTTP/2 is negotiated with ALPN, and HTTP/1.1 is used otherwise." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+	Resolver            *ServersResolver    `description:"DNS resolver used to resolve the servers addresses, instead of the system resolver." json:"resolver,omitempty" toml:"resolver,omitempty" yaml:"resolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Hedging             *Hedging            `description:"Sends a second attempt of the idempotent requests to another server, when the first one is slow to respond." json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersResolver holds the DNS resolver configuration used to resolve the servers addresses.
+type ServersResolver struct {
+	Servers []string        `description:"DNS servers (host:port) to query. If empty, the servers of /etc/resolv.conf are queried." json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" export:"true"`
+	MinTTL  ptypes.Duration `description:"Minimum duration for which a DNS answer is cached, whatever its TTL." json:"minTTL,omitempty" toml:"minTTL,omitempty" yaml:"minTTL,omitempty" export:"true"`
+	MaxTTL  ptypes.Duration `description:"Maximum duration for which a DNS answer is cached, whatever its TTL." json:"maxTTL,omitempty" toml:"maxTTL,omitempty" yaml:"maxTTL,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *ServersResolver) SetDefaults() {
+	r.MaxTTL = ptypes.Duration(5 * time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
+// Hedging holds the hedging policy of the idempotent requests forwarded with a servers transport.
+type Hedging struct {
+	Delay ptypes.Duration `description:"Time to wait for the response headers of the first attempt, before sending the second attempt to another server." json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (h *Hedging) SetDefaults() {
+	h.Delay = ptypes.Duration(100 * time.Millisecond)
 }
 
 // +k8s:deepcopy-gen=true
@@ -217,6 +408,7 @@ type ForwardingTimeouts struct {
 	DialTimeout           ptypes.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
 	ResponseHeaderTimeout ptypes.Duration `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
 	IdleConnTimeout       ptypes.Duration `description:"The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself" json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty" export:"true"`
+	MaxConnLifetime       ptypes.Duration `description:"The maximum period for which a connection to a backend server is reused, before being closed once idle to dial a new one. If zero, the connections are reused without limit." json:"maxConnLifetime,omitempty" toml:"maxConnLifetime,omitempty" yaml:"maxConnLifetime,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"time"
 
@@ -38,6 +39,20 @@ type Middleware struct {
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	CORS              *CORS              `json:"cors,omitempty" toml:"cors,omitempty" yaml:"cors,omitempty" export:"true"`
+	RequestTimeout    *RequestTimeout    `json:"requestTimeout,omitempty" toml:"requestTimeout,omitempty" yaml:"requestTimeout,omitempty" export:"true"`
+	GeoIP             *GeoIP             `json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" export:"true"`
+	Experiment        *Experiment        `json:"experiment,omitempty" toml:"experiment,omitempty" yaml:"experiment,omitempty" export:"true"`
+	Maintenance       *Maintenance       `json:"maintenance,omitempty" toml:"maintenance,omitempty" yaml:"maintenance,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	GRPCWeb           *GRPCWeb           `json:"grpcWeb,omitempty" toml:"grpcWeb,omitempty" yaml:"grpcWeb,omitempty" export:"true"`
+	WebSocket         *WebSocket         `json:"webSocket,omitempty" toml:"webSocket,omitempty" yaml:"webSocket,omitempty" export:"true"`
+	ExternalProcessor *ExternalProcessor `json:"externalProcessor,omitempty" toml:"externalProcessor,omitempty" yaml:"externalProcessor,omitempty" export:"true"`
+	EarlyHints        *EarlyHints        `json:"earlyHints,omitempty" toml:"earlyHints,omitempty" yaml:"earlyHints,omitempty" export:"true"`
+	Limits            *Limits            `json:"limits,omitempty" toml:"limits,omitempty" yaml:"limits,omitempty" export:"true"`
+	SAML              *SAML              `json:"saml,omitempty" toml:"saml,omitempty" yaml:"saml,omitempty" export:"true"`
+	Redirects         *Redirects         `json:"redirects,omitempty" toml:"redirects,omitempty" yaml:"redirects,omitempty" export:"true"`
+	Decompress        *Decompress        `json:"decompress,omitempty" toml:"decompress,omitempty" yaml:"decompress,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	SecurityHeaders   *SecurityHeaders   `json:"securityHeaders,omitempty" toml:"securityHeaders,omitempty" yaml:"securityHeaders,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
 }
@@ -73,6 +88,29 @@ type BasicAuth struct {
 	Realm        string `json:"realm,omitempty" toml:"realm,omitempty" yaml:"realm,omitempty"`
 	RemoveHeader bool   `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
 	HeaderField  string `json:"headerField,omitempty" toml:"headerField,omitempty" yaml:"headerField,omitempty" export:"true"`
+	// LDAP authenticates the users which are not defined by Users or UsersFile with a bind to an LDAP server.
+	LDAP *LDAPAuth `json:"ldap,omitempty" toml:"ldap,omitempty" yaml:"ldap,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// LDAPAuth holds the LDAP bind authentication configuration.
+type LDAPAuth struct {
+	// URL is the URL of the LDAP server, with the ldap or ldaps scheme.
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// UserDN is the DN the users bind with, where {username} is replaced by the escaped user name.
+	UserDN   string     `json:"userDN,omitempty" toml:"userDN,omitempty" yaml:"userDN,omitempty"`
+	StartTLS bool       `json:"startTLS,omitempty" toml:"startTLS,omitempty" yaml:"startTLS,omitempty" export:"true"`
+	TLS      *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	// CacheDuration is the time the successful binds are cached, and NegativeCacheDuration the time the failed ones are.
+	CacheDuration         ptypes.Duration `json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty" export:"true"`
+	NegativeCacheDuration ptypes.Duration `json:"negativeCacheDuration,omitempty" toml:"negativeCacheDuration,omitempty" yaml:"negativeCacheDuration,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (l *LDAPAuth) SetDefaults() {
+	l.CacheDuration = ptypes.Duration(5 * time.Minute)
+	l.NegativeCacheDuration = ptypes.Duration(30 * time.Second)
 }
 
 // +k8s:deepcopy-gen=true
@@ -84,6 +122,29 @@ type Buffering struct {
 	MaxResponseBodyBytes int64  `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
 	MemResponseBodyBytes int64  `json:"memResponseBodyBytes,omitempty" toml:"memResponseBodyBytes,omitempty" yaml:"memResponseBodyBytes,omitempty" export:"true"`
 	RetryExpression      string `json:"retryExpression,omitempty" toml:"retryExpression,omitempty" yaml:"retryExpression,omitempty" export:"true"`
+	// RequestBodyHash computes, and optionally verifies, the hash of the request body while it is buffered.
+	RequestBodyHash *BodyHash `json:"requestBodyHash,omitempty" toml:"requestBodyHash,omitempty" yaml:"requestBodyHash,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// ResponseBodyHash computes the hash of the response body while it is buffered.
+	ResponseBodyHash *BodyHash `json:"responseBodyHash,omitempty" toml:"responseBodyHash,omitempty" yaml:"responseBodyHash,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyHash holds the body hash configuration.
+type BodyHash struct {
+	// Algorithm is the hash algorithm, md5 or sha256.
+	Algorithm string `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	// HeaderName is the header holding the base64 encoded hash,
+	// Content-MD5 for the md5 algorithm and X-Content-Sha256 for the sha256 one by default.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// Verify rejects the requests whose body does not match the hash sent in the header.
+	// It only applies to the request body.
+	Verify bool `json:"verify,omitempty" toml:"verify,omitempty" yaml:"verify,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (b *BodyHash) SetDefaults() {
+	b.Algorithm = "sha256"
 }
 
 // +k8s:deepcopy-gen=true
@@ -109,6 +170,35 @@ type Compress struct {
 
 // +k8s:deepcopy-gen=true
 
+// Decompress holds the decompress configuration.
+type Decompress struct {
+	// Encodings are the content codings of the responses decoded when the client does not accept them, gzip and deflate by default.
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CORS holds the CORS configuration.
+type CORS struct {
+	Policies []CORSPolicy `json:"policies,omitempty" toml:"policies,omitempty" yaml:"policies,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CORSPolicy holds the CORS configuration applied to the requests whose origin matches one of the allowed origins.
+type CORSPolicy struct {
+	// AllowOrigins is a list of origins (e.g. https://example.com) or origin patterns (e.g. https://*.example.com).
+	// The special "*" value allows any origin.
+	AllowOrigins     []string `json:"allowOrigins,omitempty" toml:"allowOrigins,omitempty" yaml:"allowOrigins,omitempty" export:"true"`
+	AllowMethods     []string `json:"allowMethods,omitempty" toml:"allowMethods,omitempty" yaml:"allowMethods,omitempty" export:"true"`
+	AllowHeaders     []string `json:"allowHeaders,omitempty" toml:"allowHeaders,omitempty" yaml:"allowHeaders,omitempty" export:"true"`
+	ExposeHeaders    []string `json:"exposeHeaders,omitempty" toml:"exposeHeaders,omitempty" yaml:"exposeHeaders,omitempty" export:"true"`
+	AllowCredentials bool     `json:"allowCredentials,omitempty" toml:"allowCredentials,omitempty" yaml:"allowCredentials,omitempty" export:"true"`
+	MaxAge           int64    `json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // DigestAuth holds the Digest HTTP authentication configuration.
 type DigestAuth struct {
 	Users        Users  `json:"users,omitempty" toml:"users,omitempty" yaml:"users,omitempty"`
@@ -120,11 +210,99 @@ type DigestAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// EarlyHints holds the early hints configuration.
+type EarlyHints struct {
+	// Links are the Link header values sent in a 103 (Early Hints) response, e.g. </style.css>; rel=preload; as=style,
+	// and added to the final response.
+	Links []string `json:"links,omitempty" toml:"links,omitempty" yaml:"links,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
 	Status  []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
 	Service string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	Query   string   `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	// Template is the template of the error page, rendered instead of calling a service.
+	Template string `json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty" export:"true"`
+	// ContentType is the content type of the rendered template.
+	ContentType string `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	// StatusPages are the error pages of specific status codes (e.g. 400-499), used instead of the one above.
+	StatusPages []StatusErrorPage `json:"statusPages,omitempty" toml:"statusPages,omitempty" yaml:"statusPages,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StatusErrorPage holds the error page of specific status codes.
+type StatusErrorPage struct {
+	Status      []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	Service     string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Query       string   `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	Template    string   `json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty" export:"true"`
+	ContentType string   `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Experiment holds the experiment configuration, splitting the traffic between variants (A/B testing).
+type Experiment struct {
+	Variants []ExperimentVariant `json:"variants,omitempty" toml:"variants,omitempty" yaml:"variants,omitempty" export:"true"`
+	// Header is the name of the request header identifying the clients.
+	// When not set, or missing from the request, the clients are identified by a cookie.
+	Header string  `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Cookie *Cookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// VariantHeader is the name of the request header holding the variant assigned to the client.
+	VariantHeader string `json:"variantHeader,omitempty" toml:"variantHeader,omitempty" yaml:"variantHeader,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an Experiment.
+func (e *Experiment) SetDefaults() {
+	e.VariantHeader = "X-Experiment-Variant"
+}
+
+// +k8s:deepcopy-gen=true
+
+// ExperimentVariant holds an experiment variant configuration.
+type ExperimentVariant struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Service is the service handling the requests of the clients assigned to the variant.
+	// When not set, the requests are handled by the router service.
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Weight  *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an ExperimentVariant.
+func (v *ExperimentVariant) SetDefaults() {
+	defaultWeight := 1
+	v.Weight = &defaultWeight
+}
+
+// +k8s:deepcopy-gen=true
+
+// ExternalProcessor holds the external processor configuration,
+// a gRPC service processing the requests and the responses.
+type ExternalProcessor struct {
+	// Address is the address (host:port) of the gRPC service.
+	Address string     `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS     *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	// Timeout is the maximum duration of each call to the processor.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// FailureMode defines what happens when the processor fails: "deny" (default) answers with a 500 status code,
+	// and "allow" forwards the request, or sends the response, without the processor mutations.
+	FailureMode         string `json:"failureMode,omitempty" toml:"failureMode,omitempty" yaml:"failureMode,omitempty" export:"true"`
+	ProcessRequestBody  bool   `json:"processRequestBody,omitempty" toml:"processRequestBody,omitempty" yaml:"processRequestBody,omitempty" export:"true"`
+	ProcessResponse     bool   `json:"processResponse,omitempty" toml:"processResponse,omitempty" yaml:"processResponse,omitempty" export:"true"`
+	ProcessResponseBody bool   `json:"processResponseBody,omitempty" toml:"processResponseBody,omitempty" yaml:"processResponseBody,omitempty" export:"true"`
+	// MaxBodySize is the maximum size of the bodies sent to the processor.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an ExternalProcessor.
+func (e *ExternalProcessor) SetDefaults() {
+	e.Timeout = ptypes.Duration(time.Second)
+	e.FailureMode = "deny"
+	e.MaxBodySize = 1024 * 1024
 }
 
 // +k8s:deepcopy-gen=true
@@ -141,10 +319,49 @@ type ForwardAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// GeoIP holds the GeoIP configuration.
+type GeoIP struct {
+	// CountryDatabase is the path to a MaxMind DB file providing the country of the IPs (e.g. GeoLite2-Country, DB-IP Lite Country).
+	CountryDatabase string `json:"countryDatabase,omitempty" toml:"countryDatabase,omitempty" yaml:"countryDatabase,omitempty"`
+	// ASNDatabase is the path to a MaxMind DB file providing the autonomous system of the IPs (e.g. GeoLite2-ASN).
+	ASNDatabase string `json:"asnDatabase,omitempty" toml:"asnDatabase,omitempty" yaml:"asnDatabase,omitempty"`
+	// CountryHeader is the name of the request header holding the ISO code of the client country.
+	CountryHeader string `json:"countryHeader,omitempty" toml:"countryHeader,omitempty" yaml:"countryHeader,omitempty" export:"true"`
+	// ASNHeader is the name of the request header holding the autonomous system number of the client.
+	ASNHeader string `json:"asnHeader,omitempty" toml:"asnHeader,omitempty" yaml:"asnHeader,omitempty" export:"true"`
+	// AllowedCountries are the ISO codes of the only countries allowed.
+	AllowedCountries []string `json:"allowedCountries,omitempty" toml:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty" export:"true"`
+	// DeniedCountries are the ISO codes of the countries denied.
+	DeniedCountries []string `json:"deniedCountries,omitempty" toml:"deniedCountries,omitempty" yaml:"deniedCountries,omitempty" export:"true"`
+	// CountryServices maps country ISO codes to the services handling the requests from these countries, instead of the router service.
+	CountryServices map[string]string `json:"countryServices,omitempty" toml:"countryServices,omitempty" yaml:"countryServices,omitempty" export:"true"`
+	IPStrategy      *IPStrategy       `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values on a GeoIP.
+func (g *GeoIP) SetDefaults() {
+	g.CountryHeader = "X-Geoip-Country"
+	g.ASNHeader = "X-Geoip-Asn"
+}
+
+// +k8s:deepcopy-gen=true
+
+// GRPCWeb holds the gRPC-Web configuration.
+type GRPCWeb struct {
+	// AllowOrigins is a list of origins allowed to send gRPC-Web requests, handling the CORS requests of browsers.
+	// The special "*" value allows any origin.
+	AllowOrigins []string `json:"allowOrigins,omitempty" toml:"allowOrigins,omitempty" yaml:"allowOrigins,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Headers holds the custom header configuration.
 type Headers struct {
 	CustomRequestHeaders  map[string]string `json:"customRequestHeaders,omitempty" toml:"customRequestHeaders,omitempty" yaml:"customRequestHeaders,omitempty" export:"true"`
 	CustomResponseHeaders map[string]string `json:"customResponseHeaders,omitempty" toml:"customResponseHeaders,omitempty" yaml:"customResponseHeaders,omitempty" export:"true"`
+	// TLSRequestHeaders sets request headers, by name, to a value of the TLS connection of the client:
+	// version, cipher, serverName, or clientCertFingerprint.
+	TLSRequestHeaders map[string]string `json:"tlsRequestHeaders,omitempty" toml:"tlsRequestHeaders,omitempty" yaml:"tlsRequestHeaders,omitempty" export:"true"`
 
 	// AccessControlAllowCredentials is only valid if true. false is ignored.
 	AccessControlAllowCredentials bool `json:"accessControlAllowCredentials,omitempty" toml:"accessControlAllowCredentials,omitempty" yaml:"accessControlAllowCredentials,omitempty" export:"true"`
@@ -191,7 +408,8 @@ type Headers struct {
 // HasCustomHeadersDefined checks to see if any of the custom header elements have been set.
 func (h *Headers) HasCustomHeadersDefined() bool {
 	return h != nil && (len(h.CustomResponseHeaders) != 0 ||
-		len(h.CustomRequestHeaders) != 0)
+		len(h.CustomRequestHeaders) != 0 ||
+		len(h.TLSRequestHeaders) != 0)
 }
 
 // HasCorsHeadersDefined checks to see if any of the cors header elements have been set.
@@ -241,12 +459,12 @@ type IPStrategy struct {
 }
 
 // Get an IP selection strategy.
-// If nil return the RemoteAddr strategy
+// If nil return the ClientIP strategy, i.e. the client IP derived by the entry point or the remote address,
 // else return a strategy base on the configuration using the X-Forwarded-For Header.
 // Depth override the ExcludedIPs.
 func (s *IPStrategy) Get() (ip.Strategy, error) {
 	if s == nil {
-		return &ip.RemoteAddrStrategy{}, nil
+		return &ip.ClientIPStrategy{}, nil
 	}
 
 	if s.Depth > 0 {
@@ -265,7 +483,7 @@ func (s *IPStrategy) Get() (ip.Strategy, error) {
 		}, nil
 	}
 
-	return &ip.RemoteAddrStrategy{}, nil
+	return &ip.ClientIPStrategy{}, nil
 }
 
 // +k8s:deepcopy-gen=true
@@ -274,6 +492,44 @@ func (s *IPStrategy) Get() (ip.Strategy, error) {
 type IPWhiteList struct {
 	SourceRange []string    `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 	IPStrategy  *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty"  label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// SourceRangeFeeds are external sources of IP ranges, allowed along with the SourceRange ones.
+	SourceRangeFeeds []SourceRangeFeed `json:"sourceRangeFeeds,omitempty" toml:"sourceRangeFeeds,omitempty" yaml:"sourceRangeFeeds,omitempty"`
+	// DenySourceRange are IP ranges which are denied, even when they are allowed by SourceRange or SourceRangeFeeds.
+	DenySourceRange []string `json:"denySourceRange,omitempty" toml:"denySourceRange,omitempty" yaml:"denySourceRange,omitempty"`
+	// DenySourceRangeFeeds are external sources of denied IP ranges.
+	DenySourceRangeFeeds []SourceRangeFeed `json:"denySourceRangeFeeds,omitempty" toml:"denySourceRangeFeeds,omitempty" yaml:"denySourceRangeFeeds,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SourceRangeFeed holds an external source of IP ranges, periodically refreshed.
+// Exactly one of URL, DNS, AWS or GCP must be defined.
+type SourceRangeFeed struct {
+	// URL is the HTTPS address of a document listing an IP or a CIDR per line.
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// DNS is a domain name whose A and AAAA records are allowed.
+	DNS string `json:"dns,omitempty" toml:"dns,omitempty" yaml:"dns,omitempty"`
+	// AWS allows the IP ranges published by Amazon Web Services.
+	AWS *AWSIPRanges `json:"aws,omitempty" toml:"aws,omitempty" yaml:"aws,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// GCP allows the IP ranges published by Google Cloud.
+	GCP *GCPIPRanges `json:"gcp,omitempty" toml:"gcp,omitempty" yaml:"gcp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// RefreshInterval is the interval between two refreshes of the feed. It defaults to 10 minutes.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// AWSIPRanges holds the filters applied on the IP ranges published by Amazon Web Services.
+type AWSIPRanges struct {
+	Regions  []string `json:"regions,omitempty" toml:"regions,omitempty" yaml:"regions,omitempty" export:"true"`
+	Services []string `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GCPIPRanges holds the filters applied on the IP ranges published by Google Cloud.
+type GCPIPRanges struct {
+	Scopes []string `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -286,6 +542,46 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// Limits holds the limits middleware configuration.
+// The limits are expressed in bytes, and a zero value disables the corresponding limit.
+type Limits struct {
+	// MaxRequestHeaderBytes is the maximum size of the request headers, computed as the sum of the lengths of their names and values.
+	MaxRequestHeaderBytes int64 `json:"maxRequestHeaderBytes,omitempty" toml:"maxRequestHeaderBytes,omitempty" yaml:"maxRequestHeaderBytes,omitempty" export:"true"`
+	MaxRequestBodyBytes   int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	MaxResponseBodyBytes  int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Maintenance holds the maintenance mode configuration.
+type Maintenance struct {
+	StatusCode int `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	// ContentType is the content type of the response body, detected from the body when not set.
+	ContentType string `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	Body        string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	// RetryAfter is the estimated duration of the maintenance, sent in the Retry-After header.
+	RetryAfter ptypes.Duration    `json:"retryAfter,omitempty" toml:"retryAfter,omitempty" yaml:"retryAfter,omitempty" export:"true"`
+	Bypass     *MaintenanceBypass `json:"bypass,omitempty" toml:"bypass,omitempty" yaml:"bypass,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Maintenance.
+func (m *Maintenance) SetDefaults() {
+	m.StatusCode = http.StatusServiceUnavailable
+}
+
+// +k8s:deepcopy-gen=true
+
+// MaintenanceBypass holds the configuration of the requests bypassing the maintenance mode.
+type MaintenanceBypass struct {
+	// HeaderName is the name of the request header holding the bypass token.
+	HeaderName  string      `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	Token       string      `json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	SourceRange []string    `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	IPStrategy  *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty" export:"true"`
@@ -341,6 +637,15 @@ type RedirectRegex struct {
 
 // +k8s:deepcopy-gen=true
 
+// Redirects holds the configuration of the redirects of a map of URLs.
+type Redirects struct {
+	// File is the path to the file mapping the URLs to their redirect target, one per line, which is reloaded when modified.
+	File      string `json:"file,omitempty" toml:"file,omitempty" yaml:"file,omitempty"`
+	Permanent bool   `json:"permanent,omitempty" toml:"permanent,omitempty" yaml:"permanent,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // RedirectScheme holds the scheme redirection configuration.
 type RedirectScheme struct {
 	Scheme    string `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
@@ -352,15 +657,41 @@ type RedirectScheme struct {
 
 // ReplacePath holds the ReplacePath configuration.
 type ReplacePath struct {
-	Path string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	Path          string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	DisableHeader bool   `json:"disableHeader,omitempty" toml:"disableHeader,omitempty" yaml:"disableHeader,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // ReplacePathRegex holds the ReplacePathRegex configuration.
 type ReplacePathRegex struct {
-	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
-	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+	Regex         string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	Replacement   string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+	DisableHeader bool   `json:"disableHeader,omitempty" toml:"disableHeader,omitempty" yaml:"disableHeader,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestTimeout holds the request timeout configuration.
+type RequestTimeout struct {
+	// Deadline is the maximum duration allowed to handle the request, until the response headers are sent.
+	Deadline ptypes.Duration `json:"deadline,omitempty" toml:"deadline,omitempty" yaml:"deadline,omitempty" export:"true"`
+
+	// BodyIdleTimeout is the maximum duration to wait for the next bytes of the request body.
+	BodyIdleTimeout ptypes.Duration `json:"bodyIdleTimeout,omitempty" toml:"bodyIdleTimeout,omitempty" yaml:"bodyIdleTimeout,omitempty" export:"true"`
+
+	// BodyMinRate is the minimum rate, in bytes/s, at which the request body must be received.
+	// It is enforced once BodyMinRateGracePeriod has elapsed.
+	BodyMinRate int64 `json:"bodyMinRate,omitempty" toml:"bodyMinRate,omitempty" yaml:"bodyMinRate,omitempty" export:"true"`
+
+	// BodyMinRateGracePeriod is the duration during which the BodyMinRate is not enforced.
+	// It defaults to 5 seconds.
+	BodyMinRateGracePeriod ptypes.Duration `json:"bodyMinRateGracePeriod,omitempty" toml:"bodyMinRateGracePeriod,omitempty" yaml:"bodyMinRateGracePeriod,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a RequestTimeout.
+func (r *RequestTimeout) SetDefaults() {
+	r.BodyMinRateGracePeriod = ptypes.Duration(5 * time.Second)
 }
 
 // +k8s:deepcopy-gen=true
@@ -373,10 +704,46 @@ type Retry struct {
 
 // +k8s:deepcopy-gen=true
 
+// SAML holds the SAML service provider authentication configuration.
+type SAML struct {
+	// EntityID is the entity ID of the service provider, as registered at the identity provider.
+	EntityID string `json:"entityID,omitempty" toml:"entityID,omitempty" yaml:"entityID,omitempty" export:"true"`
+	// IDPMetadata is the content of the metadata of the identity provider, and IDPMetadataFile the path of a file holding it.
+	IDPMetadata     string `json:"idpMetadata,omitempty" toml:"idpMetadata,omitempty" yaml:"idpMetadata,omitempty"`
+	IDPMetadataFile string `json:"idpMetadataFile,omitempty" toml:"idpMetadataFile,omitempty" yaml:"idpMetadataFile,omitempty"`
+	// ACSPath is the path of the assertion consumer service, receiving the responses of the identity provider.
+	ACSPath string `json:"acsPath,omitempty" toml:"acsPath,omitempty" yaml:"acsPath,omitempty" export:"true"`
+	// MetadataPath is the path serving the metadata of the service provider.
+	MetadataPath string `json:"metadataPath,omitempty" toml:"metadataPath,omitempty" yaml:"metadataPath,omitempty" export:"true"`
+	// NameIDHeader is the request header set to the NameID of the authenticated user.
+	NameIDHeader string `json:"nameIDHeader,omitempty" toml:"nameIDHeader,omitempty" yaml:"nameIDHeader,omitempty" export:"true"`
+	// AttributeHeaders maps the names of the attributes of the assertions to the request headers set to their values.
+	AttributeHeaders  map[string]string `json:"attributeHeaders,omitempty" toml:"attributeHeaders,omitempty" yaml:"attributeHeaders,omitempty" export:"true"`
+	AllowIDPInitiated bool              `json:"allowIDPInitiated,omitempty" toml:"allowIDPInitiated,omitempty" yaml:"allowIDPInitiated,omitempty" export:"true"`
+	SessionDuration   ptypes.Duration   `json:"sessionDuration,omitempty" toml:"sessionDuration,omitempty" yaml:"sessionDuration,omitempty" export:"true"`
+	// SessionSecret signs the session cookies, so that they are accepted by all the Traefik instances, and after a restart.
+	SessionSecret string `json:"sessionSecret,omitempty" toml:"sessionSecret,omitempty" yaml:"sessionSecret,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SecurityHeaders holds the security headers preset configuration.
+type SecurityHeaders struct {
+	// Preset is the name of the preset of security headers added to the responses.
+	Preset string `json:"preset,omitempty" toml:"preset,omitempty" yaml:"preset,omitempty" export:"true"`
+	// Version is the version of the preset, 1 by default, so that the headers of the new versions are only added once it is bumped.
+	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	// Headers override the headers of the preset, an empty value removing the header.
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // StripPrefix holds the StripPrefix configuration.
 type StripPrefix struct {
-	Prefixes   []string `json:"prefixes,omitempty" toml:"prefixes,omitempty" yaml:"prefixes,omitempty" export:"true"`
-	ForceSlash bool     `json:"forceSlash,omitempty" toml:"forceSlash,omitempty" yaml:"forceSlash,omitempty" export:"true"` // Deprecated
+	Prefixes      []string `json:"prefixes,omitempty" toml:"prefixes,omitempty" yaml:"prefixes,omitempty" export:"true"`
+	ForceSlash    bool     `json:"forceSlash,omitempty" toml:"forceSlash,omitempty" yaml:"forceSlash,omitempty" export:"true"` // Deprecated
+	DisableHeader bool     `json:"disableHeader,omitempty" toml:"disableHeader,omitempty" yaml:"disableHeader,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a StripPrefix.
@@ -388,7 +755,8 @@ func (s *StripPrefix) SetDefaults() {
 
 // StripPrefixRegex holds the StripPrefixRegex configuration.
 type StripPrefixRegex struct {
-	Regex []string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	Regex         []string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	DisableHeader bool     `json:"disableHeader,omitempty" toml:"disableHeader,omitempty" yaml:"disableHeader,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -419,6 +787,21 @@ type TLSCLientCertificateDNInfo struct {
 
 // +k8s:deepcopy-gen=true
 
+// WebSocket holds the WebSocket configuration.
+type WebSocket struct {
+	// AllowOrigins is a list of origins (e.g. https://example.com) allowed to open WebSocket connections.
+	// The special "*" value allows any origin.
+	AllowOrigins []string `json:"allowOrigins,omitempty" toml:"allowOrigins,omitempty" yaml:"allowOrigins,omitempty" export:"true"`
+	// Subprotocols is the list of the allowed subprotocols.
+	Subprotocols []string `json:"subprotocols,omitempty" toml:"subprotocols,omitempty" yaml:"subprotocols,omitempty" export:"true"`
+	// MaxMessageSize is the maximum size, in bytes, of the messages sent by the clients.
+	MaxMessageSize int64 `json:"maxMessageSize,omitempty" toml:"maxMessageSize,omitempty" yaml:"maxMessageSize,omitempty" export:"true"`
+	// IdleTimeout is the maximum duration a WebSocket connection can stay without any message.
+	IdleTimeout ptypes.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Users holds a list of users.
 type Users []string
 
@@ -10,8 +10,11 @@ import (
 
 // TCPConfiguration contains all the TCP configuration parameters.
 type TCPConfiguration struct {
-	Routers  map[string]*TCPRouter  `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty" export:"true"`
-	Services map[string]*TCPService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	Routers     map[string]*TCPRouter     `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty" export:"true"`
+	Middlewares map[string]*TCPMiddleware `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	Services    map[string]*TCPService    `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	// CIDRSets are the named sets of IP ranges referenced by the IPWhiteList middlewares.
+	CIDRSets map[string]*CIDRSet `json:"cidrSets,omitempty" toml:"cidrSets,omitempty" yaml:"cidrSets,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -48,6 +51,7 @@ func (w *TCPWRRService) SetDefaults() {
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Middlewares []string            `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
 	Service     string              `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	Rule        string              `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 	TLS         *RouterTCPTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -74,7 +78,11 @@ type TCPServersLoadBalancer struct {
 	// means an infinite deadline (i.e. the reading capability is never closed).
 	TerminationDelay *int           `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// ConnectRetry makes the load balancer dial the next servers when the connection to a server cannot be established.
+	ConnectRetry *TCPConnectRetry `json:"connectRetry,omitempty" toml:"connectRetry,omitempty" yaml:"connectRetry,omitempty" export:"true"`
+	// Transparent makes the load balancer connect to the servers from the IP address of the clients (Linux only).
+	Transparent *bool       `json:"transparent,omitempty" toml:"transparent,omitempty" yaml:"transparent,omitempty" export:"true"`
+	Servers     []TCPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer.
@@ -110,6 +118,14 @@ type TCPServer struct {
 
 // +k8s:deepcopy-gen=true
 
+// TCPConnectRetry holds the connect retry configuration of a TCP load balancer.
+type TCPConnectRetry struct {
+	// Attempts is the maximum number of servers dialed for a connection, including the first one.
+	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ProxyProtocol holds the ProxyProtocol configuration.
 type ProxyProtocol struct {
 	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
@@ -0,0 +1,73 @@
+package dynamic
+
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// +k8s:deepcopy-gen=true
+
+// TCPMiddleware holds the TCPMiddleware configuration.
+type TCPMiddleware struct {
+	SNIWhiteList *SNIWhiteList    `json:"sniWhiteList,omitempty" toml:"sniWhiteList,omitempty" yaml:"sniWhiteList,omitempty" export:"true"`
+	IPWhiteList  *TCPIPWhiteList  `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
+	RateLimit    *TCPRateLimit    `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SNIWhiteList holds the TLS server names allowed and denied on a TCP router.
+// The server names are matched exactly, or with a wildcard such as *.example.com matching a single label.
+type SNIWhiteList struct {
+	ServerNames []string `json:"serverNames,omitempty" toml:"serverNames,omitempty" yaml:"serverNames,omitempty"`
+	// DenyServerNames are server names which are denied, even when they are allowed by ServerNames.
+	DenyServerNames []string `json:"denyServerNames,omitempty" toml:"denyServerNames,omitempty" yaml:"denyServerNames,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPWhiteList holds the IP ranges allowed to connect on a TCP router.
+type TCPIPWhiteList struct {
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	// SourceRangeSets are the names of CIDR sets whose IP ranges are allowed along with the SourceRange ones.
+	SourceRangeSets []string `json:"sourceRangeSets,omitempty" toml:"sourceRangeSets,omitempty" yaml:"sourceRangeSets,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPInFlightConn holds the TCP in flight connection limit configuration.
+type TCPInFlightConn struct {
+	// Amount is the maximum number of simultaneous connections of a client IP.
+	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPRateLimit holds the TCP rate limiting configuration of the new connections of each client IP.
+type TCPRateLimit struct {
+	// Average is the maximum rate of new connections of a client IP, by Period.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the maximum rate of new connections. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of new connections of a client IP accepted in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// BanDuration is the duration during which all the new connections of a client IP exceeding the rate are closed.
+	// The client IPs are not banned when it is zero.
+	BanDuration ptypes.Duration `json:"banDuration,omitempty" toml:"banDuration,omitempty" yaml:"banDuration,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// CIDRSet holds a named set of IP ranges, shared by the middlewares referencing it.
+type CIDRSet struct {
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+}
@@ -2,6 +2,9 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
 )
 
 // +k8s:deepcopy-gen=true
@@ -53,7 +56,11 @@ type UDPRouter struct {
 
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
-	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// PassiveHealthCheck makes the load balancer stop forwarding sessions to the servers that failed to answer them.
+	PassiveHealthCheck *UDPPassiveHealthCheck `json:"passiveHealthCheck,omitempty" toml:"passiveHealthCheck,omitempty" yaml:"passiveHealthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// Transparent makes the load balancer send the datagrams to the servers from the IP address of the clients (Linux only).
+	Transparent *bool       `json:"transparent,omitempty" toml:"transparent,omitempty" yaml:"transparent,omitempty" export:"true"`
+	Servers     []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -75,6 +82,22 @@ func (l *UDPServersLoadBalancer) Mergeable(loadBalancer *UDPServersLoadBalancer)
 
 // +k8s:deepcopy-gen=true
 
+// UDPPassiveHealthCheck holds the passive health check configuration of a UDP load balancer.
+type UDPPassiveHealthCheck struct {
+	// MaxFailures is the number of consecutive failed sessions after which a server is considered unavailable.
+	MaxFailures int `json:"maxFailures,omitempty" toml:"maxFailures,omitempty" yaml:"maxFailures,omitempty" export:"true"`
+	// FailureTimeout is the duration during which an unavailable server does not receive new sessions.
+	FailureTimeout ptypes.Duration `json:"failureTimeout,omitempty" toml:"failureTimeout,omitempty" yaml:"failureTimeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a UDPPassiveHealthCheck.
+func (p *UDPPassiveHealthCheck) SetDefaults() {
+	p.MaxFailures = 1
+	p.FailureTimeout = ptypes.Duration(10 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // UDPServer defines a UDP server configuration.
 type UDPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
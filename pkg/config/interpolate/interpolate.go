@@ -0,0 +1,210 @@
+package interpolate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// defaultInterpolator replaces the ${env:VAR} and ${file:/path} placeholders.
+var defaultInterpolator = NewInterpolator([]string{"env", "file"}, Resolve)
+
+// Interpolate replaces, in the string values of the element, the ${env:VAR} placeholders with the value of the environment variable VAR,
+// and the ${file:/path} placeholders with the content of the file, without its trailing line breaks.
+// An escaped placeholder, such as $${env:VAR}, is replaced with the literal placeholder.
+// The element must be a pointer, and only its exported fields are interpolated.
+func Interpolate(element interface{}) error {
+	return defaultInterpolator.Interpolate(element)
+}
+
+// String replaces the placeholders of the string.
+func String(s string) (string, error) {
+	return defaultInterpolator.String(s)
+}
+
+// Resolve returns the value of an env or file placeholder.
+func Resolve(kind, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("empty %s placeholder", kind)
+	}
+
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		return value, nil
+
+	case "file":
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the file of the placeholder: %w", err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), nil
+
+	default:
+		return "", fmt.Errorf("unsupported placeholder kind %q", kind)
+	}
+}
+
+// Interpolator replaces the ${kind:name} placeholders of a set of kinds with their values.
+// The placeholders of the other kinds are kept as is.
+type Interpolator struct {
+	placeholderRegexp *regexp.Regexp
+	resolve           func(kind, name string) (string, error)
+	// keepEscaped keeps the escaped placeholders as is, instead of replacing them with the literal placeholders.
+	keepEscaped bool
+}
+
+// NewInterpolator creates an Interpolator replacing the placeholders of the given kinds with the values returned by resolve.
+func NewInterpolator(kinds []string, resolve func(kind, name string) (string, error)) *Interpolator {
+	quoted := make([]string, len(kinds))
+	for i, kind := range kinds {
+		quoted[i] = regexp.QuoteMeta(kind)
+	}
+
+	return &Interpolator{
+		// The placeholders are matched escaped or not.
+		placeholderRegexp: regexp.MustCompile(`\$?\$\{(` + strings.Join(quoted, "|") + `):([^}]*)\}`),
+		resolve:           resolve,
+	}
+}
+
+// NewRewriter creates an Interpolator replacing the placeholders of the given kinds with the placeholders returned by rewrite.
+// The escaped placeholders are kept as is, so that they are still escaped when the result is interpolated.
+func NewRewriter(kinds []string, rewrite func(kind, name string) (string, error)) *Interpolator {
+	interpolator := NewInterpolator(kinds, rewrite)
+	interpolator.keepEscaped = true
+
+	return interpolator
+}
+
+// Interpolate replaces the placeholders in the string values of the element.
+// An escaped placeholder, such as $${env:VAR}, is replaced with the literal placeholder.
+// The element must be a pointer, and only its exported fields are interpolated.
+func (i *Interpolator) Interpolate(element interface{}) error {
+	value := reflect.ValueOf(element)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("unable to interpolate %T, a non-nil pointer is expected", element)
+	}
+
+	return i.interpolateValue("", value.Elem())
+}
+
+// String replaces the placeholders of the string.
+func (i *Interpolator) String(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var err error
+	result := i.placeholderRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		if strings.HasPrefix(match, "$$") {
+			if i.keepEscaped {
+				return match
+			}
+
+			return match[1:]
+		}
+
+		groups := i.placeholderRegexp.FindStringSubmatch(match)
+
+		var value string
+		value, err = i.resolve(groups[1], groups[2])
+
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// interpolateValue interpolates the value, whose path is used to report the errors.
+func (i *Interpolator) interpolateValue(path string, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.String:
+		result, err := i.String(value.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if value.CanSet() {
+			value.SetString(result)
+		}
+
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return i.interpolateValue(path, value.Elem())
+		}
+
+	case reflect.Interface:
+		if value.IsNil() || !value.CanSet() {
+			return nil
+		}
+
+		// The value held by an interface is not addressable, it is interpolated through a copy.
+		elem := reflect.New(value.Elem().Type()).Elem()
+		elem.Set(value.Elem())
+
+		if err := i.interpolateValue(path, elem); err != nil {
+			return err
+		}
+
+		value.Set(elem)
+
+	case reflect.Struct:
+		for j := 0; j < value.NumField(); j++ {
+			field := value.Type().Field(j)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if err := i.interpolateValue(joinPath(path, field.Name), value.Field(j)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for j := 0; j < value.Len(); j++ {
+			if err := i.interpolateValue(fmt.Sprintf("%s[%d]", path, j), value.Index(j)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			// The map values are not addressable, they are interpolated through a copy.
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+
+			if err := i.interpolateValue(joinPath(path, fmt.Sprint(iter.Key().Interface())), elem); err != nil {
+				return err
+			}
+
+			value.SetMapIndex(iter.Key(), elem)
+		}
+	}
+
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,175 @@
+package interpolate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	require.NoError(t, os.Setenv("TRAEFIK_INTERPOLATE_TEST", "secret"))
+	t.Cleanup(func() { _ = os.Unsetenv("TRAEFIK_INTERPOLATE_TEST") })
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte("file-secret\n"), 0o600))
+
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      string
+		expectedError bool
+	}{
+		{
+			desc:     "without placeholder",
+			value:    "foo",
+			expected: "foo",
+		},
+		{
+			desc:     "environment variable",
+			value:    "${env:TRAEFIK_INTERPOLATE_TEST}",
+			expected: "secret",
+		},
+		{
+			desc:     "file",
+			value:    "${file:" + secretFile + "}",
+			expected: "file-secret",
+		},
+		{
+			desc:     "several placeholders",
+			value:    "user:${env:TRAEFIK_INTERPOLATE_TEST}@${file:" + secretFile + "}",
+			expected: "user:secret@file-secret",
+		},
+		{
+			desc:     "escaped placeholder",
+			value:    "$${env:TRAEFIK_INTERPOLATE_TEST}",
+			expected: "${env:TRAEFIK_INTERPOLATE_TEST}",
+		},
+		{
+			desc:     "regexp replacement",
+			value:    "https://${1}/$${2}",
+			expected: "https://${1}/$${2}",
+		},
+		{
+			desc:          "unset environment variable",
+			value:         "${env:TRAEFIK_INTERPOLATE_UNSET}",
+			expectedError: true,
+		},
+		{
+			desc:          "missing file",
+			value:         "${file:" + filepath.Join(t.TempDir(), "missing") + "}",
+			expectedError: true,
+		},
+		{
+			desc:          "empty placeholder",
+			value:         "${env:}",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := String(test.value)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+type namedString string
+
+type element struct {
+	Name     string
+	Named    namedString
+	Pointer  *element
+	List     []string
+	Map      map[string]string
+	Structs  map[string]element
+	Options  map[string]interface{}
+	Number   int
+	internal string
+}
+
+func TestInterpolate(t *testing.T) {
+	require.NoError(t, os.Setenv("TRAEFIK_INTERPOLATE_TEST", "secret"))
+	t.Cleanup(func() { _ = os.Unsetenv("TRAEFIK_INTERPOLATE_TEST") })
+
+	value := &element{
+		Name:    "${env:TRAEFIK_INTERPOLATE_TEST}",
+		Named:   "${env:TRAEFIK_INTERPOLATE_TEST}",
+		Pointer: &element{Name: "${env:TRAEFIK_INTERPOLATE_TEST}"},
+		List:    []string{"foo", "${env:TRAEFIK_INTERPOLATE_TEST}"},
+		Map:     map[string]string{"foo": "${env:TRAEFIK_INTERPOLATE_TEST}"},
+		Structs: map[string]element{"foo": {Name: "${env:TRAEFIK_INTERPOLATE_TEST}"}},
+		Options: map[string]interface{}{
+			"foo": "${env:TRAEFIK_INTERPOLATE_TEST}",
+			"bar": map[string]interface{}{"baz": "${env:TRAEFIK_INTERPOLATE_TEST}"},
+		},
+		Number:   42,
+		internal: "${env:TRAEFIK_INTERPOLATE_TEST}",
+	}
+
+	require.NoError(t, Interpolate(value))
+
+	expected := &element{
+		Name:    "secret",
+		Named:   "secret",
+		Pointer: &element{Name: "secret"},
+		List:    []string{"foo", "secret"},
+		Map:     map[string]string{"foo": "secret"},
+		Structs: map[string]element{"foo": {Name: "secret"}},
+		Options: map[string]interface{}{
+			"foo": "secret",
+			"bar": map[string]interface{}{"baz": "secret"},
+		},
+		Number:   42,
+		internal: "${env:TRAEFIK_INTERPOLATE_TEST}",
+	}
+
+	assert.Equal(t, expected, value)
+}
+
+func TestInterpolate_error(t *testing.T) {
+	value := &element{
+		Structs: map[string]element{"foo": {List: []string{"${env:TRAEFIK_INTERPOLATE_UNSET}"}}},
+	}
+
+	err := Interpolate(value)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Structs.foo.List[0]")
+
+	assert.Error(t, Interpolate(element{}))
+}
+
+func TestInterpolator_String(t *testing.T) {
+	interpolator := NewInterpolator([]string{"vault"}, func(kind, name string) (string, error) {
+		return kind + "/" + name, nil
+	})
+
+	result, err := interpolator.String("${vault:secret/app#password} ${env:TRAEFIK_INTERPOLATE_TEST} $${vault:secret/app#password}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "vault/secret/app#password ${env:TRAEFIK_INTERPOLATE_TEST} ${vault:secret/app#password}", result)
+}
+
+func TestRewriter_String(t *testing.T) {
+	rewriter := NewRewriter([]string{"kubernetes"}, func(kind, name string) (string, error) {
+		return "${" + kind + ":default/" + name + "}", nil
+	})
+
+	result, err := rewriter.String("${kubernetes:auth/users} ${env:TRAEFIK_INTERPOLATE_TEST} $${kubernetes:auth/users}")
+	require.NoError(t, err)
+
+	assert.Equal(t, "${kubernetes:default/auth/users} ${env:TRAEFIK_INTERPOLATE_TEST} $${kubernetes:auth/users}", result)
+}
@@ -119,12 +119,16 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware13b.redirectscheme.port":                               "80",
 		"traefik.http.middlewares.Middleware13b.redirectscheme.permanent":                          "true",
 		"traefik.http.middlewares.Middleware14.replacepath.path":                                   "foobar",
+		"traefik.http.middlewares.Middleware14.replacepath.disableheader":                          "true",
 		"traefik.http.middlewares.Middleware15.replacepathregex.regex":                             "foobar",
 		"traefik.http.middlewares.Middleware15.replacepathregex.replacement":                       "foobar",
+		"traefik.http.middlewares.Middleware15.replacepathregex.disableheader":                     "true",
 		"traefik.http.middlewares.Middleware16.retry.attempts":                                     "42",
 		"traefik.http.middlewares.Middleware16.retry.initialinterval":                              "1s",
 		"traefik.http.middlewares.Middleware17.stripprefix.prefixes":                               "foobar, fiibar",
+		"traefik.http.middlewares.Middleware17.stripprefix.disableheader":                          "true",
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                             "foobar, fiibar",
+		"traefik.http.middlewares.Middleware18.stripprefixregex.disableheader":                     "true",
 		"traefik.http.middlewares.Middleware19.compress":                                           "true",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.aaa":                                  "foo1",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.bbb":                                  "foo2",
@@ -410,13 +414,15 @@ func TestDecodeConfiguration(t *testing.T) {
 				},
 				"Middleware14": {
 					ReplacePath: &dynamic.ReplacePath{
-						Path: "foobar",
+						Path:          "foobar",
+						DisableHeader: true,
 					},
 				},
 				"Middleware15": {
 					ReplacePathRegex: &dynamic.ReplacePathRegex{
-						Regex:       "foobar",
-						Replacement: "foobar",
+						Regex:         "foobar",
+						Replacement:   "foobar",
+						DisableHeader: true,
 					},
 				},
 				"Middleware16": {
@@ -431,7 +437,8 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
-						ForceSlash: true,
+						ForceSlash:    true,
+						DisableHeader: true,
 					},
 				},
 				"Middleware18": {
@@ -440,6 +447,7 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						DisableHeader: true,
 					},
 				},
 				"Middleware19": {
@@ -879,13 +887,15 @@ func TestEncodeConfiguration(t *testing.T) {
 				},
 				"Middleware14": {
 					ReplacePath: &dynamic.ReplacePath{
-						Path: "foobar",
+						Path:          "foobar",
+						DisableHeader: true,
 					},
 				},
 				"Middleware15": {
 					ReplacePathRegex: &dynamic.ReplacePathRegex{
-						Regex:       "foobar",
-						Replacement: "foobar",
+						Regex:         "foobar",
+						Replacement:   "foobar",
+						DisableHeader: true,
 					},
 				},
 				"Middleware16": {
@@ -900,7 +910,8 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
-						ForceSlash: true,
+						ForceSlash:    true,
+						DisableHeader: true,
 					},
 				},
 				"Middleware18": {
@@ -909,6 +920,7 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						DisableHeader: true,
 					},
 				},
 				"Middleware19": {
@@ -1242,13 +1254,17 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware13b.RedirectScheme.Port":                               "80",
 		"traefik.HTTP.Middlewares.Middleware13b.RedirectScheme.Permanent":                          "true",
 		"traefik.HTTP.Middlewares.Middleware14.ReplacePath.Path":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware14.ReplacePath.DisableHeader":                          "true",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Regex":                             "foobar",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Replacement":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.DisableHeader":                     "true",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.Attempts":                                     "42",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.InitialInterval":                              "1000000000",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
+		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.DisableHeader":                          "true",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.DisableHeader":                     "true",
 		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.aaa":                                  "foo1",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.bbb":                                  "foo2",
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -143,6 +144,9 @@ type ServiceInfo struct {
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
+
+	promotionMu     sync.RWMutex
+	promotionStatus func() PromotionStatus
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -194,3 +198,51 @@ func (s *ServiceInfo) GetAllStatus() map[string]string {
 	}
 	return allStatus
 }
+
+// SetPromotionStatus sets the function returning the status of the promotion of the canary service of the weighted service.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) SetPromotionStatus(status func() PromotionStatus) {
+	s.promotionMu.Lock()
+	defer s.promotionMu.Unlock()
+
+	s.promotionStatus = status
+}
+
+// GetPromotionStatus returns the status of the promotion of the canary service, or nil when the service has no promotion.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetPromotionStatus() *PromotionStatus {
+	s.promotionMu.RLock()
+	defer s.promotionMu.RUnlock()
+
+	if s.promotionStatus == nil {
+		return nil
+	}
+
+	status := s.promotionStatus()
+	return &status
+}
+
+// Promotion states.
+const (
+	PromotionProgressing = "progressing"
+	PromotionPromoted    = "promoted"
+	PromotionAborted     = "aborted"
+)
+
+// PromotionStatus is the status of the promotion of the canary service of a weighted service.
+type PromotionStatus struct {
+	Canary string `json:"canary"`
+	// State is either progressing, promoted, or aborted.
+	State string `json:"state"`
+	// Step is the number of the current step, starting at 1.
+	Step  int `json:"step"`
+	Steps int `json:"steps"`
+	// Percent is the percentage of the requests currently forwarded to the canary service.
+	Percent       int        `json:"percent"`
+	StepStartedAt time.Time  `json:"stepStartedAt"`
+	NextStepAt    *time.Time `json:"nextStepAt,omitempty"`
+	// Requests and Errors are the numbers of responses and of 5XX responses of the canary service during the current step.
+	Requests    uint64 `json:"requests"`
+	Errors      uint64 `json:"errors"`
+	AbortReason string `json:"abortReason,omitempty"`
+}
@@ -81,6 +81,36 @@ func (r *TCPRouterInfo) AddError(err error, critical bool) {
 	}
 }
 
+// TCPMiddlewareInfo holds information about a currently running TCP middleware.
+type TCPMiddlewareInfo struct {
+	*dynamic.TCPMiddleware // dynamic configuration
+	// Err contains all the errors that occurred during middleware creation.
+	Err    []string `json:"error,omitempty"`
+	Status string   `json:"status,omitempty"`
+	UsedBy []string `json:"usedBy,omitempty"` // list of TCP routers using that middleware.
+}
+
+// AddError adds err to m.Err, if it does not already exist.
+// If critical is set, m is marked as disabled.
+func (m *TCPMiddlewareInfo) AddError(err error, critical bool) {
+	for _, value := range m.Err {
+		if value == err.Error() {
+			return
+		}
+	}
+
+	m.Err = append(m.Err, err.Error())
+	if critical {
+		m.Status = StatusDisabled
+		return
+	}
+
+	// only set it to "warning" if not already in a worse state
+	if m.Status != StatusDisabled {
+		m.Status = StatusWarning
+	}
+}
+
 // TCPServiceInfo holds information about a currently running TCP service.
 type TCPServiceInfo struct {
 	*dynamic.TCPService          // dynamic configuration
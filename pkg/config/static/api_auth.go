@@ -0,0 +1,70 @@
+package static
+
+import (
+	"errors"
+
+	"github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// APIAuth configures the built-in authentication of the API and the dashboard.
+type APIAuth struct {
+	Basic   *APIBasicAuth `description:"Basic authentication." json:"basic,omitempty" toml:"basic,omitempty" yaml:"basic,omitempty" export:"true"`
+	OIDC    *APIOIDCAuth  `description:"OpenID Connect authentication." json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty" export:"true"`
+	MTLS    *APIMTLSAuth  `description:"Mutual TLS authentication." json:"mtls,omitempty" toml:"mtls,omitempty" yaml:"mtls,omitempty" export:"true"`
+	Admins  []string      `description:"Identities granted the admin role, allowed to use all the endpoints, prefixed by their authentication method (basic:, mtls: or oidc:)." json:"admins,omitempty" toml:"admins,omitempty" yaml:"admins,omitempty"`
+	Viewers []string      `description:"Identities granted the read-only role, prefixed by their authentication method (basic:, mtls: or oidc:)." json:"viewers,omitempty" toml:"viewers,omitempty" yaml:"viewers,omitempty"`
+}
+
+func (a *APIAuth) validate() error {
+	if a == nil {
+		return nil
+	}
+
+	if a.Basic == nil && a.OIDC == nil && a.MTLS == nil {
+		return errors.New("at least one of basic, oidc or mtls must be configured")
+	}
+
+	if a.Basic != nil && len(a.Basic.Users) == 0 && a.Basic.UsersFile == "" {
+		return errors.New("basic: users or usersFile must be configured")
+	}
+
+	if a.OIDC != nil && (a.OIDC.Issuer == "" || a.OIDC.ClientID == "") {
+		return errors.New("oidc: issuer and clientID must be configured")
+	}
+
+	if a.MTLS != nil && len(a.MTLS.CAFiles) == 0 {
+		return errors.New("mtls: caFiles must be configured")
+	}
+
+	return nil
+}
+
+// APIBasicAuth configures the basic authentication of the API and the dashboard.
+// The identity of a request is the name of its user.
+type APIBasicAuth struct {
+	Users     []string `description:"Authorized users, in the htpasswd format." json:"users,omitempty" toml:"users,omitempty" yaml:"users,omitempty"`
+	UsersFile string   `description:"Path to a file of authorized users, in the htpasswd format." json:"usersFile,omitempty" toml:"usersFile,omitempty" yaml:"usersFile,omitempty"`
+	Realm     string   `description:"Realm of the authentication." json:"realm,omitempty" toml:"realm,omitempty" yaml:"realm,omitempty" export:"true"`
+}
+
+// APIOIDCAuth configures the OpenID Connect authentication of the API and the dashboard.
+// The identity of a request is the value of the configured claim of its ID token.
+type APIOIDCAuth struct {
+	Issuer       string   `description:"URL of the OpenID Connect issuer." json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ClientID     string   `description:"Client ID of Traefik at the issuer." json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty"`
+	ClientSecret string   `description:"Client secret of Traefik at the issuer." json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string `description:"Scopes requested to the issuer." json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+	Claim        string   `description:"Claim of the ID token holding the identity." json:"claim,omitempty" toml:"claim,omitempty" yaml:"claim,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *APIOIDCAuth) SetDefaults() {
+	o.Scopes = []string{"openid", "email"}
+	o.Claim = "email"
+}
+
+// APIMTLSAuth configures the mutual TLS authentication of the API and the dashboard.
+// The identity of a request is the common name of its client certificate.
+type APIMTLSAuth struct {
+	CAFiles []tls.FileOrContent `description:"Certificate authorities verifying the client certificates." json:"caFiles,omitempty" toml:"caFiles,omitempty" yaml:"caFiles,omitempty"`
+}
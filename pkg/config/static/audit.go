@@ -0,0 +1,31 @@
+package static
+
+import "errors"
+
+// Audit configures the audit log of the dynamic configurations applied for the providers.
+type Audit struct {
+	FilePath   string `description:"Audit log file path." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty" export:"true"`
+	MaxEntries int    `description:"Maximum number of entries kept in the audit log, the oldest ones being dropped." json:"maxEntries,omitempty" toml:"maxEntries,omitempty" yaml:"maxEntries,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *Audit) SetDefaults() {
+	a.FilePath = "audit.log"
+	a.MaxEntries = 1000
+}
+
+func (a *Audit) validate() error {
+	if a == nil {
+		return nil
+	}
+
+	if a.FilePath == "" {
+		return errors.New("the file path must be set")
+	}
+
+	if a.MaxEntries <= 0 {
+		return errors.New("the maximum number of entries must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// Drain configures the drain mode, which stops the entry points gracefully for the rotation of the Traefik instances.
+type Drain struct {
+	EntryPoints []string        `description:"Entry points drained by the signals, and by the API when no entry point is given. All the entry points but the traefik one when empty." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Signals     []string        `description:"Signals triggering the drain of the entry points (SIGHUP or SIGUSR2)." json:"signals,omitempty" toml:"signals,omitempty" yaml:"signals,omitempty" export:"true"`
+	Timeout     ptypes.Duration `description:"Duration given to the active requests and connections to finish, before they are closed." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	Endpoint    bool            `description:"Enable the endpoint of the API draining the entry points." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (d *Drain) SetDefaults() {
+	d.Timeout = ptypes.Duration(DefaultGraceTimeout)
+}
+
+func (d *Drain) validate(entryPoints EntryPoints) error {
+	if d == nil {
+		return nil
+	}
+
+	if d.Timeout <= 0 {
+		return errors.New("the timeout must be positive")
+	}
+
+	for _, name := range d.EntryPoints {
+		if _, ok := entryPoints[name]; !ok {
+			return fmt.Errorf("unknown entry point %q", name)
+		}
+	}
+
+	return nil
+}
@@ -11,13 +11,15 @@ import (
 
 // EntryPoint holds the entry point configuration.
 type EntryPoint struct {
-	Address          string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	Transport        *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
-	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	ForwardedHeaders *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
-	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
-	EnableHTTP3      bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
-	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Address             string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	AdditionalAddresses []string              `description:"Additional addresses the entry point listens on, sharing its routers." json:"additionalAddresses,omitempty" toml:"additionalAddresses,omitempty" yaml:"additionalAddresses,omitempty"`
+	Transport           *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
+	ProxyProtocol       *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ForwardedHeaders    *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
+	HTTP                HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
+	EnableHTTP3         bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
+	UDP                 *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Observability       *ObservabilityConfig  `description:"Enables or disables the observability signals of the entry point." json:"observability,omitempty" toml:"observability,omitempty" yaml:"observability,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -27,10 +29,47 @@ func (ep EntryPoint) GetAddress() string {
 	return splitN[0]
 }
 
+// GetAddresses returns the actual addresses the entry point listens on,
+// starting with the one of the address field, followed by the additional addresses.
+func (ep EntryPoint) GetAddresses() []string {
+	addresses := []string{ep.GetAddress()}
+	for _, address := range ep.AdditionalAddresses {
+		splitN := strings.SplitN(address, "/", 2)
+		addresses = append(addresses, splitN[0])
+	}
+
+	return addresses
+}
+
 // GetProtocol returns the protocol part of the address field of the entry point.
 // If none is specified, it defaults to "tcp".
+// The additional addresses inherit this protocol, and must not specify another one.
 func (ep EntryPoint) GetProtocol() (string, error) {
-	splitN := strings.SplitN(ep.Address, "/", 2)
+	protocol, err := getProtocol(ep.Address)
+	if err != nil {
+		return "", err
+	}
+
+	for _, address := range ep.AdditionalAddresses {
+		if !strings.Contains(address, "/") {
+			continue
+		}
+
+		addrProtocol, err := getProtocol(address)
+		if err != nil {
+			return "", err
+		}
+
+		if addrProtocol != protocol {
+			return "", fmt.Errorf("protocol of the additional address %s differs from the entry point one: %s", address, protocol)
+		}
+	}
+
+	return protocol, nil
+}
+
+func getProtocol(address string) (string, error) {
+	splitN := strings.SplitN(address, "/", 2)
 	if len(splitN) < 2 {
 		return "tcp", nil
 	}
@@ -54,9 +93,36 @@ func (ep *EntryPoint) SetDefaults() {
 
 // HTTPConfig is the HTTP configuration of an entry point.
 type HTTPConfig struct {
-	Redirections *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
-	Middlewares  []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"  export:"true"`
-	TLS          *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty"  export:"true"`
+	Redirections  *Redirections           `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
+	Middlewares   []string                `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"  export:"true"`
+	TLS           *TLSConfig              `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty"  export:"true"`
+	AccessLog     *types.RouterAccessLog  `description:"Default access log configuration for the routers linked to the entry point." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" export:"true"`
+	RequestID     *types.RequestID        `description:"Identifies the requests, with the ID received from the clients or a generated one." json:"requestId,omitempty" toml:"requestId,omitempty" yaml:"requestId,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	PathTemplates []string                `description:"Path templates normalizing the request paths in the metrics and access logs, matched before the ones of the router rules." json:"pathTemplates,omitempty" toml:"pathTemplates,omitempty" yaml:"pathTemplates,omitempty" export:"true"`
+	Mirroring     *types.TrafficMirroring `description:"Mirrors a percentage of the requests to a secondary destination, independently of the routers." json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" export:"true"`
+	Capture       *types.TrafficCapture   `description:"Records a percentage of the requests to a file, for their offline replay." json:"capture,omitempty" toml:"capture,omitempty" yaml:"capture,omitempty" export:"true"`
+}
+
+// ObservabilityConfig enables or disables the observability signals of an entry point, which are all enabled by default.
+type ObservabilityConfig struct {
+	AccessLogs *bool `description:"Enables the access logs of the requests of the entry point." json:"accessLogs,omitempty" toml:"accessLogs,omitempty" yaml:"accessLogs,omitempty" export:"true"`
+	Metrics    *bool `description:"Enables the metrics of the requests of the entry point." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
+	Tracing    *bool `description:"Enables the tracing of the requests of the entry point." json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" export:"true"`
+}
+
+// AccessLogsEnabled returns whether the requests of the entry point are access logged.
+func (o *ObservabilityConfig) AccessLogsEnabled() bool {
+	return o == nil || o.AccessLogs == nil || *o.AccessLogs
+}
+
+// MetricsEnabled returns whether the requests of the entry point are reported in the metrics.
+func (o *ObservabilityConfig) MetricsEnabled() bool {
+	return o == nil || o.Metrics == nil || *o.Metrics
+}
+
+// TracingEnabled returns whether the requests of the entry point are traced.
+func (o *ObservabilityConfig) TracingEnabled() bool {
+	return o == nil || o.Tracing == nil || *o.Tracing
 }
 
 // Redirections is a set of redirection for an entry point.
@@ -88,8 +154,15 @@ type TLSConfig struct {
 
 // ForwardedHeaders Trust client forwarding headers.
 type ForwardedHeaders struct {
-	Insecure   bool     `description:"Trust all forwarded headers." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	TrustedIPs []string `description:"Trust only forwarded headers from selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Insecure   bool               `description:"Trust all forwarded headers." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	TrustedIPs []string           `description:"Trust only forwarded headers from selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Forwarded  bool               `description:"Append the RFC 7239 Forwarded header to the forwarded requests." json:"forwarded,omitempty" toml:"forwarded,omitempty" yaml:"forwarded,omitempty" export:"true"`
+	ClientIP   *ForwardedClientIP `description:"Derive the client IP used by the middlewares and the access logs from the forwarded headers." json:"clientIP,omitempty" toml:"clientIP,omitempty" yaml:"clientIP,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// ForwardedClientIP configures how the client IP is derived from the forwarded headers.
+type ForwardedClientIP struct {
+	Depth int `description:"Select the IP at the given depth from the right of the forwarded headers, instead of the closest IP which is not a trusted one." json:"depth,omitempty" toml:"depth,omitempty" yaml:"depth,omitempty" export:"true"`
 }
 
 // ProxyProtocol contains Proxy-Protocol configuration.
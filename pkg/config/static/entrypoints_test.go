@@ -65,3 +65,63 @@ func TestEntryPointProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestEntryPointAdditionalAddresses(t *testing.T) {
+	tests := []struct {
+		name                string
+		address             string
+		additionalAddresses []string
+		expectedAddresses   []string
+		expectedProtocol    string
+		expectedError       bool
+	}{
+		{
+			name:              "Without additional addresses",
+			address:           "127.0.0.1:8080",
+			expectedAddresses: []string{"127.0.0.1:8080"},
+			expectedProtocol:  "tcp",
+		},
+		{
+			name:                "Inherited protocol",
+			address:             "0.0.0.0:8080/udp",
+			additionalAddresses: []string{"[::]:8080"},
+			expectedAddresses:   []string{"0.0.0.0:8080", "[::]:8080"},
+			expectedProtocol:    "udp",
+		},
+		{
+			name:                "Same protocol",
+			address:             "0.0.0.0:8080/udp",
+			additionalAddresses: []string{"[::]:8080/UDP"},
+			expectedAddresses:   []string{"0.0.0.0:8080", "[::]:8080"},
+			expectedProtocol:    "udp",
+		},
+		{
+			name:                "Different protocol",
+			address:             "0.0.0.0:8080",
+			additionalAddresses: []string{"[::]:8080/udp"},
+			expectedError:       true,
+		},
+		{
+			name:                "Invalid protocol",
+			address:             "0.0.0.0:8080",
+			additionalAddresses: []string{"[::]:8080/toto"},
+			expectedError:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := EntryPoint{
+				Address:             tt.address,
+				AdditionalAddresses: tt.additionalAddresses,
+			}
+			protocol, err := ep.GetProtocol()
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedProtocol, protocol)
+			require.Equal(t, tt.expectedAddresses, ep.GetAddresses())
+		})
+	}
+}
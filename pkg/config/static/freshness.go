@@ -0,0 +1,39 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// Freshness configures the watchdog reporting the providers which do not send any configuration within their expected interval,
+// such as a wedged polling provider.
+type Freshness struct {
+	MaxAge        map[string]ptypes.Duration `description:"Maximum duration without any configuration message from a provider, by provider name, beyond which the provider is reported as stale." json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+	CheckInterval ptypes.Duration            `description:"Interval between two checks of the freshness of the providers." json:"checkInterval,omitempty" toml:"checkInterval,omitempty" yaml:"checkInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (f *Freshness) SetDefaults() {
+	f.CheckInterval = ptypes.Duration(10 * time.Second)
+}
+
+func (f *Freshness) validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.CheckInterval <= 0 {
+		return errors.New("the check interval must be positive")
+	}
+
+	for name, maxAge := range f.MaxAge {
+		if maxAge <= 0 {
+			return fmt.Errorf("the maximum age of the provider %q must be positive", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package static
+
+import "fmt"
+
+// validateRequiredProviders checks that the providers required by the ready endpoint are configured.
+func (c *Configuration) validateRequiredProviders() error {
+	if c.Ping == nil || len(c.Ping.RequiredProviders) == 0 {
+		return nil
+	}
+
+	names := c.providerNames()
+	for _, name := range c.Ping.RequiredProviders {
+		if _, ok := names[name]; !ok {
+			return fmt.Errorf("unknown provider %q", name)
+		}
+	}
+
+	return nil
+}
+
+// providerNames returns the names of the providers configured in the static configuration,
+// as used to qualify the names of the elements of their dynamic configurations.
+func (c *Configuration) providerNames() map[string]struct{} {
+	names := map[string]struct{}{"internal": {}}
+
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.ACME != nil {
+			names[name+".acme"] = struct{}{}
+		}
+	}
+
+	p := c.Providers
+	if p == nil {
+		return names
+	}
+
+	configured := map[string]bool{
+		"file":              p.File != nil,
+		"docker":            p.Docker != nil,
+		"marathon":          p.Marathon != nil,
+		"rest":              p.Rest != nil,
+		"kubernetes":        p.KubernetesIngress != nil,
+		"kubernetescrd":     p.KubernetesCRD != nil,
+		"kubernetesgateway": p.KubernetesGateway != nil,
+		"kubernetesservice": p.KubernetesService != nil,
+		"rancher":           p.Rancher != nil,
+		"ecs":               p.Ecs != nil,
+		"consulcatalog":     p.ConsulCatalog != nil,
+		"local":             p.Local != nil,
+		"inventory":         p.Inventory != nil,
+		"consul":            p.Consul != nil,
+		"etcd":              p.Etcd != nil,
+		"zookeeper":         p.ZooKeeper != nil,
+		"redis":             p.Redis != nil,
+		"http":              p.HTTP != nil,
+	}
+
+	for name, ok := range configured {
+		if ok {
+			names[name] = struct{}{}
+		}
+	}
+
+	for name := range p.Plugin {
+		names["plugin-"+name] = struct{}{}
+	}
+
+	return names
+}
@@ -0,0 +1,78 @@
+package static
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/ping"
+	"github.com/traefik/traefik/v2/pkg/plugins"
+	acmeprovider "github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
+)
+
+func TestValidateRequiredProviders(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		requiredProviders []string
+		expectedErr       bool
+	}{
+		{
+			desc: "no required providers",
+		},
+		{
+			desc:              "configured providers",
+			requiredProviders: []string{"file", "kubernetescrd", "internal"},
+		},
+		{
+			desc:              "ACME resolver",
+			requiredProviders: []string{"myresolver.acme"},
+		},
+		{
+			desc:              "provider plugin instance",
+			requiredProviders: []string{"plugin-foo"},
+		},
+		{
+			desc:              "provider not configured",
+			requiredProviders: []string{"docker"},
+			expectedErr:       true,
+		},
+		{
+			desc:              "unknown provider",
+			requiredProviders: []string{"kubernetesCRD"},
+			expectedErr:       true,
+		},
+		{
+			desc:              "plugin instance without prefix",
+			requiredProviders: []string{"foo"},
+			expectedErr:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := &Configuration{
+				Ping: &ping.Handler{RequiredProviders: test.requiredProviders},
+				Providers: &Providers{
+					File:          &file.Provider{},
+					KubernetesCRD: &crd.Provider{},
+					Plugin:        map[string]plugins.ProviderConf{"foo": {"demo": {}}},
+				},
+				CertificatesResolvers: map[string]CertificateResolver{
+					"myresolver": {ACME: &acmeprovider.Configuration{}},
+				},
+			}
+
+			err := conf.validateRequiredProviders()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
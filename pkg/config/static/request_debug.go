@@ -0,0 +1,26 @@
+package static
+
+import ptypes "github.com/traefik/paerser/types"
+
+// RequestDebug configures the header enabling the verbose logging of a single request, to debug its routing.
+// The header values are signed with the secret, so that only the operators are able to use it.
+type RequestDebug struct {
+	Header   string          `description:"Name of the header enabling the debug logging of a request." json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Secret   string          `description:"Secret signing the header values." json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+	MaxAge   ptypes.Duration `description:"Maximum validity of a signed header value." json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+	FilePath string          `description:"Path of the file the debug logs are written to. The standard output when empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *RequestDebug) SetDefaults() {
+	r.Header = "X-Traefik-Debug"
+	r.MaxAge = defaultSignedHeaderMaxAge
+}
+
+func (r *RequestDebug) validate() error {
+	if r == nil {
+		return nil
+	}
+
+	return validateSignedHeader(r.Header, r.Secret, r.MaxAge)
+}
@@ -0,0 +1,55 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// ResourceGuard configures the monitor of the process resources,
+// which sheds the new connections of the entry points while a resource usage is beyond its threshold.
+type ResourceGuard struct {
+	CheckInterval             ptypes.Duration `description:"Interval between two samplings of the resource usage." json:"checkInterval,omitempty" toml:"checkInterval,omitempty" yaml:"checkInterval,omitempty" export:"true"`
+	MaxFileDescriptorsPercent int             `description:"Percentage of the open file descriptors limit beyond which the new connections are shed, 0 to disable." json:"maxFileDescriptorsPercent,omitempty" toml:"maxFileDescriptorsPercent,omitempty" yaml:"maxFileDescriptorsPercent,omitempty" export:"true"`
+	MaxMemory                 int64           `description:"Memory used by the process, in bytes, beyond which the new connections are shed, 0 to disable." json:"maxMemory,omitempty" toml:"maxMemory,omitempty" yaml:"maxMemory,omitempty" export:"true"`
+	MaxGoroutines             int             `description:"Number of goroutines beyond which the new connections are shed, 0 to disable." json:"maxGoroutines,omitempty" toml:"maxGoroutines,omitempty" yaml:"maxGoroutines,omitempty" export:"true"`
+	EntryPoints               []string        `description:"Entry points shedding their new connections. All the entry points but the traefik one when empty." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *ResourceGuard) SetDefaults() {
+	r.CheckInterval = ptypes.Duration(time.Second)
+	r.MaxFileDescriptorsPercent = 90
+}
+
+func (r *ResourceGuard) validate(entryPoints EntryPoints) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.CheckInterval <= 0 {
+		return errors.New("the check interval must be positive")
+	}
+
+	if r.MaxFileDescriptorsPercent < 0 || r.MaxFileDescriptorsPercent > 100 {
+		return errors.New("the maximum file descriptors percentage must be between 0 and 100")
+	}
+
+	if r.MaxMemory < 0 {
+		return errors.New("the maximum memory must not be negative")
+	}
+
+	if r.MaxGoroutines < 0 {
+		return errors.New("the maximum number of goroutines must not be negative")
+	}
+
+	for _, name := range r.EntryPoints {
+		if _, ok := entryPoints[name]; !ok {
+			return fmt.Errorf("unknown entry point %q", name)
+		}
+	}
+
+	return nil
+}
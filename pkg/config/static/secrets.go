@@ -0,0 +1,54 @@
+package static
+
+import (
+	"errors"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Secrets configures the resolution of the secret references in the middleware options.
+type Secrets struct {
+	RefreshInterval ptypes.Duration    `description:"Interval between two resolutions of the secret references, to follow their rotation." json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+	Providers       []string           `description:"Providers whose middlewares can reference secrets. None when empty." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	Vault           *VaultSecrets      `description:"Enable the references to Vault secrets." json:"vault,omitempty" toml:"vault,omitempty" yaml:"vault,omitempty" export:"true"`
+	Kubernetes      *KubernetesSecrets `description:"Enable the references to Kubernetes secrets." json:"kubernetes,omitempty" toml:"kubernetes,omitempty" yaml:"kubernetes,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *Secrets) SetDefaults() {
+	s.RefreshInterval = ptypes.Duration(time.Minute)
+}
+
+func (s *Secrets) validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.RefreshInterval <= 0 {
+		return errors.New("the refresh interval must be positive")
+	}
+
+	if s.Vault != nil && s.Vault.Endpoint == "" {
+		return errors.New("the Vault endpoint is required")
+	}
+
+	return nil
+}
+
+// VaultSecrets configures the access to the Vault secrets.
+type VaultSecrets struct {
+	Endpoint  string           `description:"Vault server endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty" export:"true"`
+	Token     string           `description:"Vault token." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	Namespace string           `description:"Vault namespace." json:"namespace,omitempty" toml:"namespace,omitempty" yaml:"namespace,omitempty" export:"true"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// KubernetesSecrets configures the access to the Kubernetes secrets.
+type KubernetesSecrets struct {
+	Endpoint         string   `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token            string   `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath string   `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces       []string `description:"Namespaces whose secrets can be referenced. All the namespaces when empty." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+}
@@ -0,0 +1,28 @@
+package static
+
+import (
+	"errors"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// defaultSignedHeaderMaxAge is the default maximum validity of the values of the headers signed by the operators.
+const defaultSignedHeaderMaxAge = ptypes.Duration(5 * time.Minute)
+
+// validateSignedHeader validates the configuration of a header whose values are signed by the operators.
+func validateSignedHeader(header, secret string, maxAge ptypes.Duration) error {
+	if header == "" {
+		return errors.New("the header is required")
+	}
+
+	if secret == "" {
+		return errors.New("the secret is required")
+	}
+
+	if maxAge <= 0 {
+		return errors.New("the max age must be positive")
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package static
 
 import (
+	"errors"
 	"fmt"
 	stdlog "log"
 	"strings"
@@ -12,19 +13,23 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/ping"
+	"github.com/traefik/traefik/v2/pkg/plugins"
 	acmeprovider "github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/consulcatalog"
 	"github.com/traefik/traefik/v2/pkg/provider/docker"
 	"github.com/traefik/traefik/v2/pkg/provider/ecs"
 	"github.com/traefik/traefik/v2/pkg/provider/file"
 	"github.com/traefik/traefik/v2/pkg/provider/http"
+	"github.com/traefik/traefik/v2/pkg/provider/inventory"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/gateway"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/ingress"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/service"
 	"github.com/traefik/traefik/v2/pkg/provider/kv/consul"
 	"github.com/traefik/traefik/v2/pkg/provider/kv/etcd"
 	"github.com/traefik/traefik/v2/pkg/provider/kv/redis"
 	"github.com/traefik/traefik/v2/pkg/provider/kv/zk"
+	"github.com/traefik/traefik/v2/pkg/provider/local"
 	"github.com/traefik/traefik/v2/pkg/provider/marathon"
 	"github.com/traefik/traefik/v2/pkg/provider/rancher"
 	"github.com/traefik/traefik/v2/pkg/provider/rest"
@@ -68,6 +73,20 @@ type Configuration struct {
 	API     *API           `description:"Enable api/dashboard." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Metrics *types.Metrics `description:"Enable a metrics exporter." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
 	Ping    *ping.Handler  `description:"Enable ping." json:"ping,omitempty" toml:"ping,omitempty" yaml:"ping,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Drain   *Drain         `description:"Enable the drain mode." json:"drain,omitempty" toml:"drain,omitempty" yaml:"drain,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Audit   *Audit         `description:"Enable the audit log of the applied dynamic configurations." json:"audit,omitempty" toml:"audit,omitempty" yaml:"audit,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	ResourceGuard *ResourceGuard `description:"Enable the shedding of the new connections while the process resources are close to exhaustion." json:"resourceGuard,omitempty" toml:"resourceGuard,omitempty" yaml:"resourceGuard,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	UpstreamOverride *UpstreamOverride `description:"Enable the header forcing the server of a service, for debugging." json:"upstreamOverride,omitempty" toml:"upstreamOverride,omitempty" yaml:"upstreamOverride,omitempty" export:"true"`
+
+	RequestDebug *RequestDebug `description:"Enable the header logging the routing of a request to a dedicated debug log, for debugging." json:"requestDebug,omitempty" toml:"requestDebug,omitempty" yaml:"requestDebug,omitempty" export:"true"`
+
+	Secrets *Secrets `description:"Enable the secret references in the middleware options." json:"secrets,omitempty" toml:"secrets,omitempty" yaml:"secrets,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	Tenants map[string]Tenant `description:"Tenants of the providers, with the entry points allowed to their routers and the quotas of their configuration." json:"tenants,omitempty" toml:"tenants,omitempty" yaml:"tenants,omitempty" export:"true"`
+
+	ErrorResponses *types.ErrorResponses `description:"Render the errors generated by Traefik as JSON or problem+json, as negotiated with the Accept header." json:"errorResponses,omitempty" toml:"errorResponses,omitempty" yaml:"errorResponses,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Log       *types.TraefikLog `description:"Traefik log settings." json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	AccessLog *types.AccessLog  `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -103,9 +122,12 @@ type ServersTransport struct {
 
 // API holds the API configuration.
 type API struct {
-	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
-	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Insecure    bool     `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Dashboard   bool     `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
+	Debug       bool     `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Overrides   bool     `description:"Enable the endpoints overriding the configuration at runtime." json:"overrides,omitempty" toml:"overrides,omitempty" yaml:"overrides,omitempty" export:"true"`
+	Connections bool     `description:"Enable the endpoints listing and closing the active connections of the entry points." json:"connections,omitempty" toml:"connections,omitempty" yaml:"connections,omitempty" export:"true"`
+	Auth        *APIAuth `description:"Built-in authentication of the API and the dashboard." json:"auth,omitempty" toml:"auth,omitempty" yaml:"auth,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
@@ -133,6 +155,7 @@ type ForwardingTimeouts struct {
 	DialTimeout           ptypes.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
 	ResponseHeaderTimeout ptypes.Duration `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
 	IdleConnTimeout       ptypes.Duration `description:"The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself" json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty" export:"true"`
+	MaxConnLifetime       ptypes.Duration `description:"The maximum period for which a connection to a backend server is reused, before being closed once idle to dial a new one. If zero, the connections are reused without limit." json:"maxConnLifetime,omitempty" toml:"maxConnLifetime,omitempty" yaml:"maxConnLifetime,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -154,14 +177,15 @@ func (a *LifeCycle) SetDefaults() {
 
 // Tracing holds the tracing configuration.
 type Tracing struct {
-	ServiceName   string           `description:"Set the name for this service." json:"serviceName,omitempty" toml:"serviceName,omitempty" yaml:"serviceName,omitempty" export:"true"`
-	SpanNameLimit int              `description:"Set the maximum character limit for Span names (default 0 = no limit)." json:"spanNameLimit,omitempty" toml:"spanNameLimit,omitempty" yaml:"spanNameLimit,omitempty" export:"true"`
-	Jaeger        *jaeger.Config   `description:"Settings for Jaeger." json:"jaeger,omitempty" toml:"jaeger,omitempty" yaml:"jaeger,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
-	Zipkin        *zipkin.Config   `description:"Settings for Zipkin." json:"zipkin,omitempty" toml:"zipkin,omitempty" yaml:"zipkin,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
-	Datadog       *datadog.Config  `description:"Settings for Datadog." json:"datadog,omitempty" toml:"datadog,omitempty" yaml:"datadog,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
-	Instana       *instana.Config  `description:"Settings for Instana." json:"instana,omitempty" toml:"instana,omitempty" yaml:"instana,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
-	Haystack      *haystack.Config `description:"Settings for Haystack." json:"haystack,omitempty" toml:"haystack,omitempty" yaml:"haystack,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
-	Elastic       *elastic.Config  `description:"Settings for Elastic." json:"elastic,omitempty" toml:"elastic,omitempty" yaml:"elastic,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	ServiceName   string                 `description:"Set the name for this service." json:"serviceName,omitempty" toml:"serviceName,omitempty" yaml:"serviceName,omitempty" export:"true"`
+	SpanNameLimit int                    `description:"Set the maximum character limit for Span names (default 0 = no limit)." json:"spanNameLimit,omitempty" toml:"spanNameLimit,omitempty" yaml:"spanNameLimit,omitempty" export:"true"`
+	Jaeger        *jaeger.Config         `description:"Settings for Jaeger." json:"jaeger,omitempty" toml:"jaeger,omitempty" yaml:"jaeger,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Zipkin        *zipkin.Config         `description:"Settings for Zipkin." json:"zipkin,omitempty" toml:"zipkin,omitempty" yaml:"zipkin,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Datadog       *datadog.Config        `description:"Settings for Datadog." json:"datadog,omitempty" toml:"datadog,omitempty" yaml:"datadog,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Instana       *instana.Config        `description:"Settings for Instana." json:"instana,omitempty" toml:"instana,omitempty" yaml:"instana,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Haystack      *haystack.Config       `description:"Settings for Haystack." json:"haystack,omitempty" toml:"haystack,omitempty" yaml:"haystack,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Elastic       *elastic.Config        `description:"Settings for Elastic." json:"elastic,omitempty" toml:"elastic,omitempty" yaml:"elastic,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	Sampling      *types.TracingSampling `description:"Overrides the sampling decisions of the tracing backend." json:"sampling,omitempty" toml:"sampling,omitempty" yaml:"sampling,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -180,16 +204,23 @@ type Providers struct {
 	KubernetesIngress *ingress.Provider       `description:"Enable Kubernetes backend with default settings." json:"kubernetesIngress,omitempty" toml:"kubernetesIngress,omitempty" yaml:"kubernetesIngress,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	KubernetesCRD     *crd.Provider           `description:"Enable Kubernetes backend with default settings." json:"kubernetesCRD,omitempty" toml:"kubernetesCRD,omitempty" yaml:"kubernetesCRD,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	KubernetesGateway *gateway.Provider       `description:"Enable Kubernetes gateway api provider with default settings." json:"kubernetesGateway,omitempty" toml:"kubernetesGateway,omitempty" yaml:"kubernetesGateway,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+	KubernetesService *service.Provider       `description:"Enable Kubernetes Service backend with default settings." json:"kubernetesService,omitempty" toml:"kubernetesService,omitempty" yaml:"kubernetesService,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	Rest              *rest.Provider          `description:"Enable Rest backend with default settings." json:"rest,omitempty" toml:"rest,omitempty" yaml:"rest,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	Rancher           *rancher.Provider       `description:"Enable Rancher backend with default settings." json:"rancher,omitempty" toml:"rancher,omitempty" yaml:"rancher,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	ConsulCatalog     *consulcatalog.Provider `description:"Enable ConsulCatalog backend with default settings." json:"consulCatalog,omitempty" toml:"consulCatalog,omitempty" yaml:"consulCatalog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Ecs               *ecs.Provider           `description:"Enable AWS ECS backend with default settings." json:"ecs,omitempty" toml:"ecs,omitempty" yaml:"ecs,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Local             *local.Provider         `description:"Enable Local backend (systemd units and process registry file) with default settings." json:"local,omitempty" toml:"local,omitempty" yaml:"local,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Inventory         *inventory.Provider     `description:"Enable Inventory backend (instances and labels listed by an inventory service) with default settings." json:"inventory,omitempty" toml:"inventory,omitempty" yaml:"inventory,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Consul    *consul.Provider `description:"Enable Consul backend with default settings." json:"consul,omitempty" toml:"consul,omitempty" yaml:"consul,omitempty" label:"allowEmpty" file:"allowEmpty"  export:"true"`
 	Etcd      *etcd.Provider   `description:"Enable Etcd backend with default settings." json:"etcd,omitempty" toml:"etcd,omitempty" yaml:"etcd,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	ZooKeeper *zk.Provider     `description:"Enable ZooKeeper backend with default settings." json:"zooKeeper,omitempty" toml:"zooKeeper,omitempty" yaml:"zooKeeper,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Redis     *redis.Provider  `description:"Enable Redis backend with default settings." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTP      *http.Provider   `description:"Enable HTTP backend with default settings." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	Plugin map[string]plugins.ProviderConf `description:"Plugins providers, by instance name." json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+
+	Freshness *Freshness `description:"Enable the watchdog reporting the stale providers." json:"freshness,omitempty" toml:"freshness,omitempty" yaml:"freshness,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetEffectiveConfiguration adds missing configuration parameters derived from existing ones.
@@ -206,7 +237,9 @@ func (c *Configuration) SetEffectiveConfiguration() {
 	if (c.API != nil && c.API.Insecure) ||
 		(c.Ping != nil && !c.Ping.ManualRouting && c.Ping.EntryPoint == DefaultInternalEntryPointName) ||
 		(c.Metrics != nil && c.Metrics.Prometheus != nil && !c.Metrics.Prometheus.ManualRouting && c.Metrics.Prometheus.EntryPoint == DefaultInternalEntryPointName) ||
-		(c.Providers != nil && c.Providers.Rest != nil && c.Providers.Rest.Insecure) {
+		(c.Providers != nil && c.Providers.Rest != nil && c.Providers.Rest.Insecure) ||
+		(c.Providers != nil && c.Providers.KubernetesCRD != nil && c.Providers.KubernetesCRD.Webhook != nil &&
+			!c.Providers.KubernetesCRD.Webhook.ManualRouting && c.Providers.KubernetesCRD.Webhook.EntryPoint == DefaultInternalEntryPointName) {
 		if _, ok := c.EntryPoints[DefaultInternalEntryPointName]; !ok {
 			ep := &EntryPoint{Address: ":8080"}
 			ep.SetDefaults()
@@ -272,12 +305,71 @@ func (c *Configuration) initACMEProvider() {
 
 // ValidateConfiguration validate that configuration is coherent.
 func (c *Configuration) ValidateConfiguration() error {
+	if c.API != nil {
+		if c.API.Overrides && c.API.Auth == nil {
+			return errors.New("the API overrides cannot be enabled without the API authentication, as their endpoints must be secured")
+		}
+
+		if c.API.Connections && c.API.Auth == nil {
+			return errors.New("the API connections endpoints cannot be enabled without the API authentication, as they must be secured")
+		}
+
+		if c.API.Insecure && c.Drain != nil && c.Drain.Endpoint && c.API.Auth == nil {
+			return errors.New("the drain endpoint cannot be enabled with the insecure API without authentication, as it must be secured")
+		}
+
+		if err := c.API.Auth.validate(); err != nil {
+			return fmt.Errorf("invalid API authentication: %w", err)
+		}
+	}
+
+	if err := c.Drain.validate(c.EntryPoints); err != nil {
+		return fmt.Errorf("invalid drain configuration: %w", err)
+	}
+
+	if err := c.ResourceGuard.validate(c.EntryPoints); err != nil {
+		return fmt.Errorf("invalid resource guard configuration: %w", err)
+	}
+
+	if err := validateTenants(c.Tenants, c.EntryPoints); err != nil {
+		return fmt.Errorf("invalid tenants configuration: %w", err)
+	}
+
+	if err := c.Audit.validate(); err != nil {
+		return fmt.Errorf("invalid audit configuration: %w", err)
+	}
+
+	if c.Providers != nil {
+		if err := c.Providers.Freshness.validate(); err != nil {
+			return fmt.Errorf("invalid providers freshness configuration: %w", err)
+		}
+	}
+
+	if err := c.UpstreamOverride.validate(); err != nil {
+		return fmt.Errorf("invalid upstream override configuration: %w", err)
+	}
+
+	if err := c.RequestDebug.validate(); err != nil {
+		return fmt.Errorf("invalid request debug configuration: %w", err)
+	}
+
+	if err := c.Secrets.validate(); err != nil {
+		return fmt.Errorf("invalid secrets configuration: %w", err)
+	}
+
+	if err := c.validateRequiredProviders(); err != nil {
+		return fmt.Errorf("invalid ping configuration: %w", err)
+	}
+
 	var acmeEmail string
+	acmePolicies := make(map[string]*acmeprovider.Policy)
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME == nil {
 			continue
 		}
 
+		acmePolicies[name] = resolver.ACME.Policy
+
 		if len(resolver.ACME.Storage) == 0 {
 			return fmt.Errorf("unable to initialize certificates resolver %q with no storage location for the certificates", name)
 		}
@@ -288,6 +380,10 @@ func (c *Configuration) ValidateConfiguration() error {
 		acmeEmail = resolver.ACME.Email
 	}
 
+	if _, err := acmeprovider.NewResolverSelector(acmePolicies); err != nil {
+		return fmt.Errorf("invalid ACME policies: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,58 @@
+package static
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tenant configures a tenant of a shared Traefik instance,
+// owning the configuration of some providers, restricted to some entry points and limited by quotas.
+type Tenant struct {
+	Providers      []string `description:"Providers whose configuration belongs to the tenant, by provider name." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	EntryPoints    []string `description:"Entry points allowed to the routers of the tenant. All the entry points when empty." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	MaxRouters     int      `description:"Maximum number of routers of the tenant, 0 for no limit." json:"maxRouters,omitempty" toml:"maxRouters,omitempty" yaml:"maxRouters,omitempty" export:"true"`
+	MaxMiddlewares int      `description:"Maximum number of middlewares of the tenant, 0 for no limit." json:"maxMiddlewares,omitempty" toml:"maxMiddlewares,omitempty" yaml:"maxMiddlewares,omitempty" export:"true"`
+	MaxServices    int      `description:"Maximum number of services of the tenant, 0 for no limit." json:"maxServices,omitempty" toml:"maxServices,omitempty" yaml:"maxServices,omitempty" export:"true"`
+}
+
+func (t Tenant) validate(entryPoints EntryPoints) error {
+	if len(t.Providers) == 0 {
+		return errors.New("no provider is assigned to the tenant")
+	}
+
+	for _, name := range t.EntryPoints {
+		if _, ok := entryPoints[name]; !ok {
+			return fmt.Errorf("unknown entry point %q", name)
+		}
+	}
+
+	if t.MaxRouters < 0 || t.MaxMiddlewares < 0 || t.MaxServices < 0 {
+		return errors.New("the quotas must not be negative")
+	}
+
+	return nil
+}
+
+func validateTenants(tenants map[string]Tenant, entryPoints EntryPoints) error {
+	// owners holds the tenant owning each provider.
+	owners := make(map[string]string)
+
+	for name, tenant := range tenants {
+		if err := tenant.validate(entryPoints); err != nil {
+			return fmt.Errorf("tenant %s: %w", name, err)
+		}
+
+		for _, providerName := range tenant.Providers {
+			if providerName == "internal" {
+				return fmt.Errorf("tenant %s: the internal provider cannot be assigned to a tenant", name)
+			}
+
+			if owner, ok := owners[providerName]; ok && owner != name {
+				return fmt.Errorf("the provider %s is assigned to both the tenants %s and %s", providerName, owner, name)
+			}
+			owners[providerName] = name
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package static
+
+import ptypes "github.com/traefik/paerser/types"
+
+// UpstreamOverride configures the header forcing the server a request is forwarded to, to debug a specific server of a service.
+// The header values are signed with the secret, so that only the operators are able to use it.
+type UpstreamOverride struct {
+	Header   string          `description:"Name of the header forcing the server of the service." json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Secret   string          `description:"Secret signing the header values." json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+	MaxAge   ptypes.Duration `description:"Maximum validity of a signed header value." json:"maxAge,omitempty" toml:"maxAge,omitempty" yaml:"maxAge,omitempty" export:"true"`
+	Services []string        `description:"Services accepting the header. All the services when empty." json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (u *UpstreamOverride) SetDefaults() {
+	u.Header = "X-Traefik-Upstream"
+	u.MaxAge = defaultSignedHeaderMaxAge
+}
+
+func (u *UpstreamOverride) validate() error {
+	if u == nil {
+		return nil
+	}
+
+	return validateSignedHeader(u.Header, u.Secret, u.MaxAge)
+}
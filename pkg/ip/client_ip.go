@@ -0,0 +1,17 @@
+package ip
+
+import "context"
+
+type clientIPKey struct{}
+
+// WithClientIP returns a context holding the client IP derived by the entry point from the forwarded headers.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
+// GetClientIP returns the client IP derived by the entry point from the forwarded headers,
+// or an empty string if the entry point does not derive it.
+func GetClientIP(ctx context.Context) string {
+	clientIP, _ := ctx.Value(clientIPKey{}).(string)
+	return clientIP
+}
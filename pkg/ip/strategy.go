@@ -27,6 +27,20 @@ func (s *RemoteAddrStrategy) GetIP(req *http.Request) string {
 	return ip
 }
 
+// ClientIPStrategy a strategy that returns the client IP derived by the entry point from the forwarded headers,
+// or the remote address if the entry point does not derive it.
+type ClientIPStrategy struct{}
+
+// GetIP returns the selected IP.
+func (s *ClientIPStrategy) GetIP(req *http.Request) string {
+	if clientIP := GetClientIP(req.Context()); clientIP != "" {
+		return clientIP
+	}
+
+	remoteAddrStrategy := RemoteAddrStrategy{}
+	return remoteAddrStrategy.GetIP(req)
+}
+
 // DepthStrategy a strategy based on the depth inside the X-Forwarded-For from right to left.
 type DepthStrategy struct {
 	Depth int
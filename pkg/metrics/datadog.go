@@ -22,17 +22,46 @@ var datadogTicker *time.Ticker
 const (
 	ddMetricsServiceReqsName        = "service.request.total"
 	ddMetricsServiceLatencyName     = "service.request.duration"
+	ddMetricsServicePathReqsName    = "service.path.request.total"
+	ddMetricsServicePathLatencyName = "service.path.request.duration"
 	ddRetriesTotalName              = "service.retries.total"
 	ddConfigReloadsName             = "config.reload.total"
 	ddConfigReloadsFailureTagName   = "failure"
 	ddLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
 	ddLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
+	ddProviderLastMessageName       = "config.provider.lastMessageTimestamp"
+	ddProviderStaleName             = "config.provider.stale"
+	ddConfigHashName                = "config.hash"
 	ddEntryPointReqsName            = "entrypoint.request.total"
 	ddEntryPointReqDurationName     = "entrypoint.request.duration"
 	ddEntryPointOpenConnsName       = "entrypoint.connections.open"
 	ddOpenConnsName                 = "service.connections.open"
 	ddServerUpName                  = "service.server.up"
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddAccessLogDroppedLinesName     = "accesslog.lines.dropped.total"
+	ddAccessLogBufferedLinesName    = "accesslog.lines.buffered"
+	ddGatewayAttachedRoutesName     = "gateway.listener.attachedRoutes"
+	ddResourceUsageName             = "resource.usage"
+	ddResourceOverloadedName        = "resource.overloaded"
+	ddResourceShedName              = "resource.shed.total"
+	ddExperimentAssignmentsName     = "router.experiment.assignments.total"
+	ddRouterOpenWebSocketsName      = "router.websockets.open"
+	ddRouterOpenUpgradedConnsName   = "router.upgraded.connections.open"
+	ddUpstreamOpenConnsName         = "service.upstream.connections.open"
+	ddUpstreamIdleConnsName         = "service.upstream.connections.idle"
+	ddUpstreamActiveConnsName       = "service.upstream.connections.active"
+	ddUpstreamConnsName             = "service.upstream.connections.total"
+	ddUpstreamDNSFailuresName       = "service.upstream.dns.failures.total"
+	ddProtocolDowngradesName        = "service.protocol.downgrades.total"
+	ddTCPConnectRetriesName         = "service.tcp.connect.retries.total"
+	ddCanaryWeightName              = "service.canary.weight"
+	ddCanaryAbortsName              = "service.canary.aborts.total"
+	ddCircuitBreakerTrippedName     = "middleware.circuitbreaker.tripped"
+	ddLimitsViolationsName          = "middleware.limits.violations.total"
+	ddMiddlewareReqsName            = "middleware.request.total"
+	ddMiddlewareLatencyName         = "middleware.request.duration"
+	ddMiddlewareShortCircuitsName   = "middleware.shortcircuits.total"
+	ddTCPRateLimitRejectedConnsName = "middleware.tcp.ratelimit.rejected.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -46,7 +75,16 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		configReloadsFailureCounter:    datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
 		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		providerLastMessageGauge:       datadogClient.NewGauge(ddProviderLastMessageName),
+		providerStaleGauge:             datadogClient.NewGauge(ddProviderStaleName),
+		configHashGauge:                datadogClient.NewGauge(ddConfigHashName),
 		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		accessLogDroppedLinesCounter:   datadogClient.NewCounter(ddAccessLogDroppedLinesName, 1.0),
+		accessLogBufferedLinesGauge:    datadogClient.NewGauge(ddAccessLogBufferedLinesName),
+		gatewayAttachedRoutesGauge:     datadogClient.NewGauge(ddGatewayAttachedRoutesName),
+		resourceUsageGauge:             datadogClient.NewGauge(ddResourceUsageName),
+		resourceOverloadedGauge:        datadogClient.NewGauge(ddResourceOverloadedName),
+		resourceShedCounter:            datadogClient.NewCounter(ddResourceShedName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -54,6 +92,7 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsCounter = datadogClient.NewCounter(ddEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0), time.Second)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.routerExperimentAssignmentsCounter = datadogClient.NewCounter(ddExperimentAssignmentsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -63,6 +102,33 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.routerOpenWebSocketsGauge = datadogClient.NewGauge(ddRouterOpenWebSocketsName)
+		registry.routerOpenUpgradedConnsGauge = datadogClient.NewGauge(ddRouterOpenUpgradedConnsName)
+		registry.serviceUpstreamOpenConnsGauge = datadogClient.NewGauge(ddUpstreamOpenConnsName)
+		registry.serviceUpstreamIdleConnsGauge = datadogClient.NewGauge(ddUpstreamIdleConnsName)
+		registry.serviceUpstreamActiveConnsGauge = datadogClient.NewGauge(ddUpstreamActiveConnsName)
+		registry.serviceUpstreamConnsCounter = datadogClient.NewCounter(ddUpstreamConnsName, 1.0)
+		registry.serviceUpstreamDNSFailuresCounter = datadogClient.NewCounter(ddUpstreamDNSFailuresName, 1.0)
+		registry.serviceProtocolDowngradesCounter = datadogClient.NewCounter(ddProtocolDowngradesName, 1.0)
+		registry.serviceTCPConnectRetriesCounter = datadogClient.NewCounter(ddTCPConnectRetriesName, 1.0)
+		registry.serviceCanaryWeightGauge = datadogClient.NewGauge(ddCanaryWeightName)
+		registry.serviceCanaryAbortsCounter = datadogClient.NewCounter(ddCanaryAbortsName, 1.0)
+		registry.circuitBreakerTrippedGauge = datadogClient.NewGauge(ddCircuitBreakerTrippedName)
+		registry.limitsViolationsCounter = datadogClient.NewCounter(ddLimitsViolationsName, 1.0)
+	}
+
+	if config.AddServicesLabels && config.AddPathTemplatesLabels {
+		registry.pathEnabled = true
+		registry.servicePathReqsCounter = datadogClient.NewCounter(ddMetricsServicePathReqsName, 1.0)
+		registry.servicePathReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddMetricsServicePathLatencyName, 1.0), time.Second)
+	}
+
+	if config.AddMiddlewaresLabels {
+		registry.middlewareEnabled = true
+		registry.middlewareReqsCounter = datadogClient.NewCounter(ddMiddlewareReqsName, 1.0)
+		registry.middlewareReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddMiddlewareLatencyName, 1.0), time.Second)
+		registry.middlewareShortCircuitsCounter = datadogClient.NewCounter(ddMiddlewareShortCircuitsName, 1.0)
+		registry.tcpRateLimitRejectedConnsCounter = datadogClient.NewCounter(ddTCPRateLimitRejectedConnsName, 1.0)
 	}
 
 	return registry
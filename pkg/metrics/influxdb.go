@@ -28,17 +28,46 @@ var influxDBTicker *time.Ticker
 const (
 	influxDBMetricsServiceReqsName        = "traefik.service.requests.total"
 	influxDBMetricsServiceLatencyName     = "traefik.service.request.duration"
+	influxDBMetricsServicePathReqsName    = "traefik.service.path.requests.total"
+	influxDBMetricsServicePathLatencyName = "traefik.service.path.request.duration"
 	influxDBRetriesTotalName              = "traefik.service.retries.total"
 	influxDBConfigReloadsName             = "traefik.config.reload.total"
 	influxDBConfigReloadsFailureName      = influxDBConfigReloadsName + ".failure"
 	influxDBLastConfigReloadSuccessName   = "traefik.config.reload.lastSuccessTimestamp"
 	influxDBLastConfigReloadFailureName   = "traefik.config.reload.lastFailureTimestamp"
+	influxDBProviderLastMessageName       = "traefik.config.provider.lastMessageTimestamp"
+	influxDBProviderStaleName             = "traefik.config.provider.stale"
+	influxDBConfigHashName                = "traefik.config.hash"
 	influxDBEntryPointReqsName            = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqDurationName     = "traefik.entrypoint.request.duration"
 	influxDBEntryPointOpenConnsName       = "traefik.entrypoint.connections.open"
 	influxDBOpenConnsName                 = "traefik.service.connections.open"
 	influxDBServerUpName                  = "traefik.service.server.up"
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBAccessLogDroppedLinesName     = "traefik.accesslog.lines.dropped.total"
+	influxDBAccessLogBufferedLinesName    = "traefik.accesslog.lines.buffered"
+	influxDBGatewayAttachedRoutesName     = "traefik.gateway.listener.attachedRoutes"
+	influxDBResourceUsageName             = "traefik.resource.usage"
+	influxDBResourceOverloadedName        = "traefik.resource.overloaded"
+	influxDBResourceShedName              = "traefik.resource.shed.total"
+	influxDBExperimentAssignmentsName     = "traefik.router.experiment.assignments.total"
+	influxDBRouterOpenWebSocketsName      = "traefik.router.websockets.open"
+	influxDBRouterOpenUpgradedConnsName   = "traefik.router.upgraded.connections.open"
+	influxDBUpstreamOpenConnsName         = "traefik.service.upstream.connections.open"
+	influxDBUpstreamIdleConnsName         = "traefik.service.upstream.connections.idle"
+	influxDBUpstreamActiveConnsName       = "traefik.service.upstream.connections.active"
+	influxDBUpstreamConnsName             = "traefik.service.upstream.connections.total"
+	influxDBUpstreamDNSFailuresName       = "traefik.service.upstream.dns.failures.total"
+	influxDBProtocolDowngradesName        = "traefik.service.protocol.downgrades.total"
+	influxDBTCPConnectRetriesName         = "traefik.service.tcp.connect.retries.total"
+	influxDBCanaryWeightName              = "traefik.service.canary.weight"
+	influxDBCanaryAbortsName              = "traefik.service.canary.aborts.total"
+	influxDBCircuitBreakerTrippedName     = "traefik.middleware.circuitbreaker.tripped"
+	influxDBLimitsViolationsName          = "traefik.middleware.limits.violations.total"
+	influxDBMiddlewareReqsName            = "traefik.middleware.requests.total"
+	influxDBMiddlewareLatencyName         = "traefik.middleware.request.duration"
+	influxDBMiddlewareShortCircuitsName   = "traefik.middleware.shortcircuits.total"
+	influxDBTCPRateLimitRejectedConnsName = "traefik.middleware.tcp.ratelimit.rejected.total"
 )
 
 const (
@@ -60,7 +89,16 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		configReloadsFailureCounter:    influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
 		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		providerLastMessageGauge:       influxDBClient.NewGauge(influxDBProviderLastMessageName),
+		providerStaleGauge:             influxDBClient.NewGauge(influxDBProviderStaleName),
+		configHashGauge:                influxDBClient.NewGauge(influxDBConfigHashName),
 		tlsCertsNotAfterTimestampGauge: influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		accessLogDroppedLinesCounter:   influxDBClient.NewCounter(influxDBAccessLogDroppedLinesName),
+		accessLogBufferedLinesGauge:    influxDBClient.NewGauge(influxDBAccessLogBufferedLinesName),
+		gatewayAttachedRoutesGauge:     influxDBClient.NewGauge(influxDBGatewayAttachedRoutesName),
+		resourceUsageGauge:             influxDBClient.NewGauge(influxDBResourceUsageName),
+		resourceOverloadedGauge:        influxDBClient.NewGauge(influxDBResourceOverloadedName),
+		resourceShedCounter:            influxDBClient.NewCounter(influxDBResourceShedName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -68,6 +106,7 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.routerExperimentAssignmentsCounter = influxDBClient.NewCounter(influxDBExperimentAssignmentsName)
 	}
 
 	if config.AddServicesLabels {
@@ -77,6 +116,33 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServerUpName)
+		registry.routerOpenWebSocketsGauge = influxDBClient.NewGauge(influxDBRouterOpenWebSocketsName)
+		registry.routerOpenUpgradedConnsGauge = influxDBClient.NewGauge(influxDBRouterOpenUpgradedConnsName)
+		registry.serviceUpstreamOpenConnsGauge = influxDBClient.NewGauge(influxDBUpstreamOpenConnsName)
+		registry.serviceUpstreamIdleConnsGauge = influxDBClient.NewGauge(influxDBUpstreamIdleConnsName)
+		registry.serviceUpstreamActiveConnsGauge = influxDBClient.NewGauge(influxDBUpstreamActiveConnsName)
+		registry.serviceUpstreamConnsCounter = influxDBClient.NewCounter(influxDBUpstreamConnsName)
+		registry.serviceUpstreamDNSFailuresCounter = influxDBClient.NewCounter(influxDBUpstreamDNSFailuresName)
+		registry.serviceProtocolDowngradesCounter = influxDBClient.NewCounter(influxDBProtocolDowngradesName)
+		registry.serviceTCPConnectRetriesCounter = influxDBClient.NewCounter(influxDBTCPConnectRetriesName)
+		registry.serviceCanaryWeightGauge = influxDBClient.NewGauge(influxDBCanaryWeightName)
+		registry.serviceCanaryAbortsCounter = influxDBClient.NewCounter(influxDBCanaryAbortsName)
+		registry.circuitBreakerTrippedGauge = influxDBClient.NewGauge(influxDBCircuitBreakerTrippedName)
+		registry.limitsViolationsCounter = influxDBClient.NewCounter(influxDBLimitsViolationsName)
+	}
+
+	if config.AddServicesLabels && config.AddPathTemplatesLabels {
+		registry.pathEnabled = true
+		registry.servicePathReqsCounter = influxDBClient.NewCounter(influxDBMetricsServicePathReqsName)
+		registry.servicePathReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBMetricsServicePathLatencyName), time.Second)
+	}
+
+	if config.AddMiddlewaresLabels {
+		registry.middlewareEnabled = true
+		registry.middlewareReqsCounter = influxDBClient.NewCounter(influxDBMiddlewareReqsName)
+		registry.middlewareReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBMiddlewareLatencyName), time.Second)
+		registry.middlewareShortCircuitsCounter = influxDBClient.NewCounter(influxDBMiddlewareShortCircuitsName)
+		registry.tcpRateLimitRejectedConnsCounter = influxDBClient.NewCounter(influxDBTCPRateLimitRejectedConnsName)
 	}
 
 	return registry
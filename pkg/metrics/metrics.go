@@ -14,16 +14,35 @@ type Registry interface {
 	IsEpEnabled() bool
 	// IsSvcEnabled shows whether metrics instrumentation is enabled on services.
 	IsSvcEnabled() bool
+	// IsPathEnabled shows whether metrics instrumentation is enabled on the path templates of services.
+	IsPathEnabled() bool
+	// IsMiddlewareEnabled shows whether metrics instrumentation is enabled on middlewares.
+	IsMiddlewareEnabled() bool
 
 	// server metrics
 	ConfigReloadsCounter() metrics.Counter
 	ConfigReloadsFailureCounter() metrics.Counter
 	LastConfigReloadSuccessGauge() metrics.Gauge
 	LastConfigReloadFailureGauge() metrics.Gauge
+	ProviderLastMessageGauge() metrics.Gauge
+	ProviderStaleGauge() metrics.Gauge
+	ConfigHashGauge() metrics.Gauge
 
 	// TLS
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
 
+	// access log metrics
+	AccessLogDroppedLinesCounter() metrics.Counter
+	AccessLogBufferedLinesGauge() metrics.Gauge
+
+	// Kubernetes Gateway API provider metrics
+	GatewayAttachedRoutesGauge() metrics.Gauge
+
+	// resource guard metrics
+	ResourceUsageGauge() metrics.Gauge
+	ResourceOverloadedGauge() metrics.Gauge
+	ResourceShedCounter() metrics.Counter
+
 	// entry point metrics
 	EntryPointReqsCounter() metrics.Counter
 	EntryPointReqsTLSCounter() metrics.Counter
@@ -34,9 +53,35 @@ type Registry interface {
 	ServiceReqsCounter() metrics.Counter
 	ServiceReqsTLSCounter() metrics.Counter
 	ServiceReqDurationHistogram() ScalableHistogram
+	ServicePathReqsCounter() metrics.Counter
+	ServicePathReqDurationHistogram() ScalableHistogram
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	RouterExperimentAssignmentsCounter() metrics.Counter
+	RouterOpenWebSocketsGauge() metrics.Gauge
+	RouterOpenUpgradedConnsGauge() metrics.Gauge
+
+	// upstream metrics
+	ServiceUpstreamOpenConnsGauge() metrics.Gauge
+	ServiceUpstreamIdleConnsGauge() metrics.Gauge
+	ServiceUpstreamActiveConnsGauge() metrics.Gauge
+	ServiceUpstreamConnsCounter() metrics.Counter
+	ServiceUpstreamDNSFailuresCounter() metrics.Counter
+	ServiceProtocolDowngradesCounter() metrics.Counter
+	ServiceTCPConnectRetriesCounter() metrics.Counter
+
+	// promotion metrics
+	ServiceCanaryWeightGauge() metrics.Gauge
+	ServiceCanaryAbortsCounter() metrics.Counter
+
+	// middleware metrics
+	CircuitBreakerTrippedGauge() metrics.Gauge
+	LimitsViolationsCounter() metrics.Counter
+	MiddlewareReqsCounter() metrics.Counter
+	MiddlewareReqDurationHistogram() ScalableHistogram
+	MiddlewareShortCircuitsCounter() metrics.Counter
+	TCPRateLimitRejectedConnsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -53,7 +98,16 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var configReloadsFailureCounter []metrics.Counter
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
+	var providerLastMessageGauge []metrics.Gauge
+	var providerStaleGauge []metrics.Gauge
+	var configHashGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var accessLogDroppedLinesCounter []metrics.Counter
+	var accessLogBufferedLinesGauge []metrics.Gauge
+	var gatewayAttachedRoutesGauge []metrics.Gauge
+	var resourceUsageGauge []metrics.Gauge
+	var resourceOverloadedGauge []metrics.Gauge
+	var resourceShedCounter []metrics.Counter
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -61,9 +115,29 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
+	var servicePathReqsCounter []metrics.Counter
+	var servicePathReqDurationHistogram []ScalableHistogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var routerExperimentAssignmentsCounter []metrics.Counter
+	var routerOpenWebSocketsGauge []metrics.Gauge
+	var routerOpenUpgradedConnsGauge []metrics.Gauge
+	var serviceUpstreamOpenConnsGauge []metrics.Gauge
+	var serviceUpstreamIdleConnsGauge []metrics.Gauge
+	var serviceUpstreamActiveConnsGauge []metrics.Gauge
+	var serviceUpstreamConnsCounter []metrics.Counter
+	var serviceUpstreamDNSFailuresCounter []metrics.Counter
+	var serviceProtocolDowngradesCounter []metrics.Counter
+	var serviceTCPConnectRetriesCounter []metrics.Counter
+	var serviceCanaryWeightGauge []metrics.Gauge
+	var serviceCanaryAbortsCounter []metrics.Counter
+	var circuitBreakerTrippedGauge []metrics.Gauge
+	var limitsViolationsCounter []metrics.Counter
+	var middlewareReqsCounter []metrics.Counter
+	var middlewareReqDurationHistogram []ScalableHistogram
+	var middlewareShortCircuitsCounter []metrics.Counter
+	var tcpRateLimitRejectedConnsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -78,9 +152,36 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.LastConfigReloadFailureGauge() != nil {
 			lastConfigReloadFailureGauge = append(lastConfigReloadFailureGauge, r.LastConfigReloadFailureGauge())
 		}
+		if r.ProviderLastMessageGauge() != nil {
+			providerLastMessageGauge = append(providerLastMessageGauge, r.ProviderLastMessageGauge())
+		}
+		if r.ProviderStaleGauge() != nil {
+			providerStaleGauge = append(providerStaleGauge, r.ProviderStaleGauge())
+		}
+		if r.ConfigHashGauge() != nil {
+			configHashGauge = append(configHashGauge, r.ConfigHashGauge())
+		}
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.AccessLogDroppedLinesCounter() != nil {
+			accessLogDroppedLinesCounter = append(accessLogDroppedLinesCounter, r.AccessLogDroppedLinesCounter())
+		}
+		if r.AccessLogBufferedLinesGauge() != nil {
+			accessLogBufferedLinesGauge = append(accessLogBufferedLinesGauge, r.AccessLogBufferedLinesGauge())
+		}
+		if r.GatewayAttachedRoutesGauge() != nil {
+			gatewayAttachedRoutesGauge = append(gatewayAttachedRoutesGauge, r.GatewayAttachedRoutesGauge())
+		}
+		if r.ResourceUsageGauge() != nil {
+			resourceUsageGauge = append(resourceUsageGauge, r.ResourceUsageGauge())
+		}
+		if r.ResourceOverloadedGauge() != nil {
+			resourceOverloadedGauge = append(resourceOverloadedGauge, r.ResourceOverloadedGauge())
+		}
+		if r.ResourceShedCounter() != nil {
+			resourceShedCounter = append(resourceShedCounter, r.ResourceShedCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -102,6 +203,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceReqDurationHistogram() != nil {
 			serviceReqDurationHistogram = append(serviceReqDurationHistogram, r.ServiceReqDurationHistogram())
 		}
+		if r.ServicePathReqsCounter() != nil {
+			servicePathReqsCounter = append(servicePathReqsCounter, r.ServicePathReqsCounter())
+		}
+		if r.ServicePathReqDurationHistogram() != nil {
+			servicePathReqDurationHistogram = append(servicePathReqDurationHistogram, r.ServicePathReqDurationHistogram())
+		}
 		if r.ServiceOpenConnsGauge() != nil {
 			serviceOpenConnsGauge = append(serviceOpenConnsGauge, r.ServiceOpenConnsGauge())
 		}
@@ -111,47 +218,163 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.RouterExperimentAssignmentsCounter() != nil {
+			routerExperimentAssignmentsCounter = append(routerExperimentAssignmentsCounter, r.RouterExperimentAssignmentsCounter())
+		}
+		if r.RouterOpenWebSocketsGauge() != nil {
+			routerOpenWebSocketsGauge = append(routerOpenWebSocketsGauge, r.RouterOpenWebSocketsGauge())
+		}
+		if r.RouterOpenUpgradedConnsGauge() != nil {
+			routerOpenUpgradedConnsGauge = append(routerOpenUpgradedConnsGauge, r.RouterOpenUpgradedConnsGauge())
+		}
+		if r.ServiceUpstreamOpenConnsGauge() != nil {
+			serviceUpstreamOpenConnsGauge = append(serviceUpstreamOpenConnsGauge, r.ServiceUpstreamOpenConnsGauge())
+		}
+		if r.ServiceUpstreamIdleConnsGauge() != nil {
+			serviceUpstreamIdleConnsGauge = append(serviceUpstreamIdleConnsGauge, r.ServiceUpstreamIdleConnsGauge())
+		}
+		if r.ServiceUpstreamActiveConnsGauge() != nil {
+			serviceUpstreamActiveConnsGauge = append(serviceUpstreamActiveConnsGauge, r.ServiceUpstreamActiveConnsGauge())
+		}
+		if r.ServiceUpstreamConnsCounter() != nil {
+			serviceUpstreamConnsCounter = append(serviceUpstreamConnsCounter, r.ServiceUpstreamConnsCounter())
+		}
+		if r.ServiceUpstreamDNSFailuresCounter() != nil {
+			serviceUpstreamDNSFailuresCounter = append(serviceUpstreamDNSFailuresCounter, r.ServiceUpstreamDNSFailuresCounter())
+		}
+		if r.ServiceProtocolDowngradesCounter() != nil {
+			serviceProtocolDowngradesCounter = append(serviceProtocolDowngradesCounter, r.ServiceProtocolDowngradesCounter())
+		}
+		if r.ServiceTCPConnectRetriesCounter() != nil {
+			serviceTCPConnectRetriesCounter = append(serviceTCPConnectRetriesCounter, r.ServiceTCPConnectRetriesCounter())
+		}
+		if r.ServiceCanaryWeightGauge() != nil {
+			serviceCanaryWeightGauge = append(serviceCanaryWeightGauge, r.ServiceCanaryWeightGauge())
+		}
+		if r.ServiceCanaryAbortsCounter() != nil {
+			serviceCanaryAbortsCounter = append(serviceCanaryAbortsCounter, r.ServiceCanaryAbortsCounter())
+		}
+		if r.CircuitBreakerTrippedGauge() != nil {
+			circuitBreakerTrippedGauge = append(circuitBreakerTrippedGauge, r.CircuitBreakerTrippedGauge())
+		}
+		if r.LimitsViolationsCounter() != nil {
+			limitsViolationsCounter = append(limitsViolationsCounter, r.LimitsViolationsCounter())
+		}
+		if r.MiddlewareReqsCounter() != nil {
+			middlewareReqsCounter = append(middlewareReqsCounter, r.MiddlewareReqsCounter())
+		}
+		if r.MiddlewareReqDurationHistogram() != nil {
+			middlewareReqDurationHistogram = append(middlewareReqDurationHistogram, r.MiddlewareReqDurationHistogram())
+		}
+		if r.MiddlewareShortCircuitsCounter() != nil {
+			middlewareShortCircuitsCounter = append(middlewareShortCircuitsCounter, r.MiddlewareShortCircuitsCounter())
+		}
+		if r.TCPRateLimitRejectedConnsCounter() != nil {
+			tcpRateLimitRejectedConnsCounter = append(tcpRateLimitRejectedConnsCounter, r.TCPRateLimitRejectedConnsCounter())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
-		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
-		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
-		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		epEnabled:                          len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0 || len(routerExperimentAssignmentsCounter) > 0,
+		svcEnabled:                         len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0 || len(routerOpenWebSocketsGauge) > 0 || len(routerOpenUpgradedConnsGauge) > 0 || len(serviceUpstreamOpenConnsGauge) > 0 || len(serviceUpstreamIdleConnsGauge) > 0 || len(serviceUpstreamActiveConnsGauge) > 0 || len(serviceUpstreamConnsCounter) > 0 || len(serviceUpstreamDNSFailuresCounter) > 0 || len(serviceProtocolDowngradesCounter) > 0 || len(serviceTCPConnectRetriesCounter) > 0 || len(serviceCanaryWeightGauge) > 0 || len(serviceCanaryAbortsCounter) > 0 || len(circuitBreakerTrippedGauge) > 0 || len(limitsViolationsCounter) > 0,
+		pathEnabled:                        len(servicePathReqsCounter) > 0 || len(servicePathReqDurationHistogram) > 0,
+		middlewareEnabled:                  len(middlewareReqsCounter) > 0 || len(middlewareReqDurationHistogram) > 0 || len(middlewareShortCircuitsCounter) > 0 || len(tcpRateLimitRejectedConnsCounter) > 0,
+		configReloadsCounter:               multi.NewCounter(configReloadsCounter...),
+		configReloadsFailureCounter:        multi.NewCounter(configReloadsFailureCounter...),
+		lastConfigReloadSuccessGauge:       multi.NewGauge(lastConfigReloadSuccessGauge...),
+		lastConfigReloadFailureGauge:       multi.NewGauge(lastConfigReloadFailureGauge...),
+		providerLastMessageGauge:           multi.NewGauge(providerLastMessageGauge...),
+		providerStaleGauge:                 multi.NewGauge(providerStaleGauge...),
+		configHashGauge:                    multi.NewGauge(configHashGauge...),
+		tlsCertsNotAfterTimestampGauge:     multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		accessLogDroppedLinesCounter:       multi.NewCounter(accessLogDroppedLinesCounter...),
+		accessLogBufferedLinesGauge:        multi.NewGauge(accessLogBufferedLinesGauge...),
+		gatewayAttachedRoutesGauge:         multi.NewGauge(gatewayAttachedRoutesGauge...),
+		resourceUsageGauge:                 multi.NewGauge(resourceUsageGauge...),
+		resourceOverloadedGauge:            multi.NewGauge(resourceOverloadedGauge...),
+		resourceShedCounter:                multi.NewCounter(resourceShedCounter...),
+		entryPointReqsCounter:              multi.NewCounter(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:           multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:     NewMultiHistogram(entryPointReqDurationHistogram...),
+		entryPointOpenConnsGauge:           multi.NewGauge(entryPointOpenConnsGauge...),
+		serviceReqsCounter:                 multi.NewCounter(serviceReqsCounter...),
+		serviceReqsTLSCounter:              multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:        NewMultiHistogram(serviceReqDurationHistogram...),
+		servicePathReqsCounter:             multi.NewCounter(servicePathReqsCounter...),
+		servicePathReqDurationHistogram:    NewMultiHistogram(servicePathReqDurationHistogram...),
+		serviceOpenConnsGauge:              multi.NewGauge(serviceOpenConnsGauge...),
+		serviceRetriesCounter:              multi.NewCounter(serviceRetriesCounter...),
+		serviceServerUpGauge:               multi.NewGauge(serviceServerUpGauge...),
+		routerExperimentAssignmentsCounter: multi.NewCounter(routerExperimentAssignmentsCounter...),
+		routerOpenWebSocketsGauge:          multi.NewGauge(routerOpenWebSocketsGauge...),
+		routerOpenUpgradedConnsGauge:       multi.NewGauge(routerOpenUpgradedConnsGauge...),
+		serviceUpstreamOpenConnsGauge:      multi.NewGauge(serviceUpstreamOpenConnsGauge...),
+		serviceUpstreamIdleConnsGauge:      multi.NewGauge(serviceUpstreamIdleConnsGauge...),
+		serviceUpstreamActiveConnsGauge:    multi.NewGauge(serviceUpstreamActiveConnsGauge...),
+		serviceUpstreamConnsCounter:        multi.NewCounter(serviceUpstreamConnsCounter...),
+		serviceUpstreamDNSFailuresCounter:  multi.NewCounter(serviceUpstreamDNSFailuresCounter...),
+		serviceProtocolDowngradesCounter:   multi.NewCounter(serviceProtocolDowngradesCounter...),
+		serviceTCPConnectRetriesCounter:    multi.NewCounter(serviceTCPConnectRetriesCounter...),
+		serviceCanaryWeightGauge:           multi.NewGauge(serviceCanaryWeightGauge...),
+		serviceCanaryAbortsCounter:         multi.NewCounter(serviceCanaryAbortsCounter...),
+		circuitBreakerTrippedGauge:         multi.NewGauge(circuitBreakerTrippedGauge...),
+		limitsViolationsCounter:            multi.NewCounter(limitsViolationsCounter...),
+		middlewareReqsCounter:              multi.NewCounter(middlewareReqsCounter...),
+		middlewareReqDurationHistogram:     NewMultiHistogram(middlewareReqDurationHistogram...),
+		middlewareShortCircuitsCounter:     multi.NewCounter(middlewareShortCircuitsCounter...),
+		tcpRateLimitRejectedConnsCounter:   multi.NewCounter(tcpRateLimitRejectedConnsCounter...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	configReloadsFailureCounter    metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	lastConfigReloadFailureGauge   metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          metrics.Counter
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointOpenConnsGauge       metrics.Gauge
-	serviceReqsCounter             metrics.Counter
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceOpenConnsGauge          metrics.Gauge
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
+	epEnabled                          bool
+	svcEnabled                         bool
+	pathEnabled                        bool
+	middlewareEnabled                  bool
+	configReloadsCounter               metrics.Counter
+	configReloadsFailureCounter        metrics.Counter
+	lastConfigReloadSuccessGauge       metrics.Gauge
+	lastConfigReloadFailureGauge       metrics.Gauge
+	providerLastMessageGauge           metrics.Gauge
+	providerStaleGauge                 metrics.Gauge
+	configHashGauge                    metrics.Gauge
+	tlsCertsNotAfterTimestampGauge     metrics.Gauge
+	accessLogDroppedLinesCounter       metrics.Counter
+	accessLogBufferedLinesGauge        metrics.Gauge
+	gatewayAttachedRoutesGauge         metrics.Gauge
+	resourceUsageGauge                 metrics.Gauge
+	resourceOverloadedGauge            metrics.Gauge
+	resourceShedCounter                metrics.Counter
+	entryPointReqsCounter              metrics.Counter
+	entryPointReqsTLSCounter           metrics.Counter
+	entryPointReqDurationHistogram     ScalableHistogram
+	entryPointOpenConnsGauge           metrics.Gauge
+	serviceReqsCounter                 metrics.Counter
+	serviceReqsTLSCounter              metrics.Counter
+	serviceReqDurationHistogram        ScalableHistogram
+	servicePathReqsCounter             metrics.Counter
+	servicePathReqDurationHistogram    ScalableHistogram
+	serviceOpenConnsGauge              metrics.Gauge
+	serviceRetriesCounter              metrics.Counter
+	serviceServerUpGauge               metrics.Gauge
+	routerExperimentAssignmentsCounter metrics.Counter
+	routerOpenWebSocketsGauge          metrics.Gauge
+	routerOpenUpgradedConnsGauge       metrics.Gauge
+	serviceUpstreamOpenConnsGauge      metrics.Gauge
+	serviceUpstreamIdleConnsGauge      metrics.Gauge
+	serviceUpstreamActiveConnsGauge    metrics.Gauge
+	serviceUpstreamConnsCounter        metrics.Counter
+	serviceUpstreamDNSFailuresCounter  metrics.Counter
+	serviceProtocolDowngradesCounter   metrics.Counter
+	serviceTCPConnectRetriesCounter    metrics.Counter
+	serviceCanaryWeightGauge           metrics.Gauge
+	serviceCanaryAbortsCounter         metrics.Counter
+	circuitBreakerTrippedGauge         metrics.Gauge
+	limitsViolationsCounter            metrics.Counter
+	middlewareReqsCounter              metrics.Counter
+	middlewareReqDurationHistogram     ScalableHistogram
+	middlewareShortCircuitsCounter     metrics.Counter
+	tcpRateLimitRejectedConnsCounter   metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -162,6 +385,14 @@ func (r *standardRegistry) IsSvcEnabled() bool {
 	return r.svcEnabled
 }
 
+func (r *standardRegistry) IsPathEnabled() bool {
+	return r.pathEnabled
+}
+
+func (r *standardRegistry) IsMiddlewareEnabled() bool {
+	return r.middlewareEnabled
+}
+
 func (r *standardRegistry) ConfigReloadsCounter() metrics.Counter {
 	return r.configReloadsCounter
 }
@@ -178,10 +409,46 @@ func (r *standardRegistry) LastConfigReloadFailureGauge() metrics.Gauge {
 	return r.lastConfigReloadFailureGauge
 }
 
+func (r *standardRegistry) ProviderLastMessageGauge() metrics.Gauge {
+	return r.providerLastMessageGauge
+}
+
+func (r *standardRegistry) ProviderStaleGauge() metrics.Gauge {
+	return r.providerStaleGauge
+}
+
+func (r *standardRegistry) ConfigHashGauge() metrics.Gauge {
+	return r.configHashGauge
+}
+
 func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) AccessLogDroppedLinesCounter() metrics.Counter {
+	return r.accessLogDroppedLinesCounter
+}
+
+func (r *standardRegistry) AccessLogBufferedLinesGauge() metrics.Gauge {
+	return r.accessLogBufferedLinesGauge
+}
+
+func (r *standardRegistry) GatewayAttachedRoutesGauge() metrics.Gauge {
+	return r.gatewayAttachedRoutesGauge
+}
+
+func (r *standardRegistry) ResourceUsageGauge() metrics.Gauge {
+	return r.resourceUsageGauge
+}
+
+func (r *standardRegistry) ResourceOverloadedGauge() metrics.Gauge {
+	return r.resourceOverloadedGauge
+}
+
+func (r *standardRegistry) ResourceShedCounter() metrics.Counter {
+	return r.resourceShedCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() metrics.Counter {
 	return r.entryPointReqsCounter
 }
@@ -210,6 +477,14 @@ func (r *standardRegistry) ServiceReqDurationHistogram() ScalableHistogram {
 	return r.serviceReqDurationHistogram
 }
 
+func (r *standardRegistry) ServicePathReqsCounter() metrics.Counter {
+	return r.servicePathReqsCounter
+}
+
+func (r *standardRegistry) ServicePathReqDurationHistogram() ScalableHistogram {
+	return r.servicePathReqDurationHistogram
+}
+
 func (r *standardRegistry) ServiceOpenConnsGauge() metrics.Gauge {
 	return r.serviceOpenConnsGauge
 }
@@ -222,12 +497,92 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) RouterExperimentAssignmentsCounter() metrics.Counter {
+	return r.routerExperimentAssignmentsCounter
+}
+
+func (r *standardRegistry) RouterOpenWebSocketsGauge() metrics.Gauge {
+	return r.routerOpenWebSocketsGauge
+}
+
+func (r *standardRegistry) RouterOpenUpgradedConnsGauge() metrics.Gauge {
+	return r.routerOpenUpgradedConnsGauge
+}
+
+func (r *standardRegistry) ServiceUpstreamOpenConnsGauge() metrics.Gauge {
+	return r.serviceUpstreamOpenConnsGauge
+}
+
+func (r *standardRegistry) ServiceUpstreamIdleConnsGauge() metrics.Gauge {
+	return r.serviceUpstreamIdleConnsGauge
+}
+
+func (r *standardRegistry) ServiceUpstreamActiveConnsGauge() metrics.Gauge {
+	return r.serviceUpstreamActiveConnsGauge
+}
+
+func (r *standardRegistry) ServiceUpstreamConnsCounter() metrics.Counter {
+	return r.serviceUpstreamConnsCounter
+}
+
+func (r *standardRegistry) ServiceUpstreamDNSFailuresCounter() metrics.Counter {
+	return r.serviceUpstreamDNSFailuresCounter
+}
+
+func (r *standardRegistry) ServiceProtocolDowngradesCounter() metrics.Counter {
+	return r.serviceProtocolDowngradesCounter
+}
+
+func (r *standardRegistry) CircuitBreakerTrippedGauge() metrics.Gauge {
+	return r.circuitBreakerTrippedGauge
+}
+
+func (r *standardRegistry) ServiceTCPConnectRetriesCounter() metrics.Counter {
+	return r.serviceTCPConnectRetriesCounter
+}
+
+func (r *standardRegistry) ServiceCanaryWeightGauge() metrics.Gauge {
+	return r.serviceCanaryWeightGauge
+}
+
+func (r *standardRegistry) ServiceCanaryAbortsCounter() metrics.Counter {
+	return r.serviceCanaryAbortsCounter
+}
+
+func (r *standardRegistry) LimitsViolationsCounter() metrics.Counter {
+	return r.limitsViolationsCounter
+}
+
+func (r *standardRegistry) MiddlewareReqsCounter() metrics.Counter {
+	return r.middlewareReqsCounter
+}
+
+func (r *standardRegistry) MiddlewareReqDurationHistogram() ScalableHistogram {
+	return r.middlewareReqDurationHistogram
+}
+
+func (r *standardRegistry) MiddlewareShortCircuitsCounter() metrics.Counter {
+	return r.middlewareShortCircuitsCounter
+}
+
+func (r *standardRegistry) TCPRateLimitRejectedConnsCounter() metrics.Counter {
+	return r.tcpRateLimitRejectedConnsCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
 	With(labelValues ...string) ScalableHistogram
 	Observe(v float64)
 	ObserveFromStart(start time.Time)
+	// ObserveFromStartWithTraceID is like ObserveFromStart,
+	// but attaches the trace ID as an exemplar of the observation, for the backends supporting exemplars.
+	ObserveFromStartWithTraceID(start time.Time, traceID string)
+}
+
+// exemplarObserver is implemented by the histograms able to attach an exemplar to an observation.
+type exemplarObserver interface {
+	ObserveWithExemplar(value float64, exemplar map[string]string)
 }
 
 // HistogramWithScale is a histogram that will convert its observed value to the specified unit.
@@ -248,11 +603,30 @@ func (s *HistogramWithScale) ObserveFromStart(start time.Time) {
 		return
 	}
 
+	s.histogram.Observe(s.sinceStart(start))
+}
+
+// ObserveFromStartWithTraceID implements ScalableHistogram.
+func (s *HistogramWithScale) ObserveFromStartWithTraceID(start time.Time, traceID string) {
+	if s.unit <= 0 {
+		return
+	}
+
+	eo, ok := s.histogram.(exemplarObserver)
+	if !ok || traceID == "" {
+		s.histogram.Observe(s.sinceStart(start))
+		return
+	}
+
+	eo.ObserveWithExemplar(s.sinceStart(start), map[string]string{"trace_id": traceID})
+}
+
+func (s *HistogramWithScale) sinceStart(start time.Time) float64 {
 	d := float64(time.Since(start).Nanoseconds()) / float64(s.unit)
 	if d < 0 {
 		d = 0
 	}
-	s.histogram.Observe(d)
+	return d
 }
 
 // Observe implements ScalableHistogram.
@@ -286,6 +660,13 @@ func (h MultiHistogram) ObserveFromStart(start time.Time) {
 	}
 }
 
+// ObserveFromStartWithTraceID implements ScalableHistogram.
+func (h MultiHistogram) ObserveFromStartWithTraceID(start time.Time, traceID string) {
+	for _, histogram := range h {
+		histogram.ObserveFromStartWithTraceID(start, traceID)
+	}
+}
+
 // Observe implements ScalableHistogram.
 func (h MultiHistogram) Observe(v float64) {
 	for _, histogram := range h {
@@ -101,6 +101,8 @@ func (c *histogramMock) Start() {}
 
 func (c *histogramMock) ObserveFromStart(t time.Time) {}
 
+func (c *histogramMock) ObserveFromStartWithTraceID(t time.Time, traceID string) {}
+
 func (c *histogramMock) Observe(v float64) {
 	c.lastHistogramValue = v
 }
@@ -0,0 +1,611 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/golang/protobuf/proto"
+	"github.com/traefik/traefik/v2/pkg/log"
+	collectorpb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/collector/metrics/v1"
+	commonpb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/common/v1"
+	metricspb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/metrics/v1"
+	resourcepb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/resource/v1"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/version"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	otlpMeterInstance *otlpMeter
+	otlpTicker        *time.Ticker
+)
+
+// Metric names following the OpenTelemetry naming guidelines.
+const (
+	otlpConfigReloadsName              = "traefik.config.reloads"
+	otlpConfigReloadsFailureName       = otlpConfigReloadsName + ".failure"
+	otlpLastConfigReloadSuccessName    = "traefik.config.reload.last_success_timestamp"
+	otlpLastConfigReloadFailureName    = "traefik.config.reload.last_failure_timestamp"
+	otlpProviderLastMessageName        = "traefik.config.provider.last_message_timestamp"
+	otlpProviderStaleName              = "traefik.config.provider.stale"
+	otlpConfigHashName                 = "traefik.config.hash"
+	otlpTLSCertsNotAfterTimestampName  = "traefik.tls.certs.not_after_timestamp"
+	otlpAccessLogDroppedLinesName      = "traefik.accesslog.lines.dropped"
+	otlpAccessLogBufferedLinesName     = "traefik.accesslog.lines.buffered"
+	otlpGatewayAttachedRoutesName      = "traefik.gateway.listener.attached_routes"
+	otlpResourceUsageName              = "traefik.resource.usage"
+	otlpResourceOverloadedName         = "traefik.resource.overloaded"
+	otlpResourceShedName               = "traefik.resource.shed"
+	otlpEntryPointReqsName             = "traefik.entrypoint.requests"
+	otlpEntryPointReqsTLSName          = "traefik.entrypoint.requests.tls"
+	otlpEntryPointReqDurationName      = "traefik.entrypoint.request.duration"
+	otlpEntryPointOpenConnsName        = "traefik.entrypoint.connections.open"
+	otlpServiceReqsName                = "traefik.service.requests"
+	otlpServiceReqsTLSName             = "traefik.service.requests.tls"
+	otlpServiceReqDurationName         = "traefik.service.request.duration"
+	otlpServicePathReqsName            = "traefik.service.path.requests"
+	otlpServicePathReqDurationName     = "traefik.service.path.request.duration"
+	otlpServiceOpenConnsName           = "traefik.service.connections.open"
+	otlpServiceRetriesName             = "traefik.service.retries"
+	otlpServiceServerUpName            = "traefik.service.server.up"
+	otlpRouterExperimentAssignments    = "traefik.router.experiment.assignments"
+	otlpRouterOpenWebSocketsName       = "traefik.router.websockets.open"
+	otlpRouterOpenUpgradedConnsName    = "traefik.router.upgraded.connections.open"
+	otlpServiceUpstreamOpenConnsName   = "traefik.service.upstream.connections.open"
+	otlpServiceUpstreamIdleConnsName   = "traefik.service.upstream.connections.idle"
+	otlpServiceUpstreamActiveConnsName = "traefik.service.upstream.connections.active"
+	otlpServiceUpstreamConnsName       = "traefik.service.upstream.connections"
+	otlpServiceUpstreamDNSFailuresName = "traefik.service.upstream.dns.failures"
+	otlpServiceProtocolDowngradesName  = "traefik.service.protocol.downgrades"
+	otlpServiceTCPConnectRetriesName   = "traefik.service.tcp.connect.retries"
+	otlpServiceCanaryWeightName        = "traefik.service.canary.weight"
+	otlpServiceCanaryAbortsName        = "traefik.service.canary.aborts"
+	otlpCircuitBreakerTrippedName      = "traefik.middleware.circuitbreaker.tripped"
+	otlpLimitsViolationsName           = "traefik.middleware.limits.violations"
+	otlpMiddlewareReqsName             = "traefik.middleware.requests"
+	otlpMiddlewareReqDurationName      = "traefik.middleware.request.duration"
+	otlpMiddlewareShortCircuitsName    = "traefik.middleware.short_circuits"
+	otlpTCPRateLimitRejectedConnsName  = "traefik.middleware.tcp.ratelimit.rejected"
+)
+
+const (
+	otlpProtocolGRPC = "grpc"
+	otlpProtocolHTTP = "http"
+)
+
+// otlpExportMethod is the gRPC method of the OTLP metrics service.
+// The messages of the protocol are generated in the otlpproto package.
+const otlpExportMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+
+// RegisterOTLP registers the metrics pusher if this didn't happen yet and creates an OTLP Registry instance.
+// It returns nil if the configuration is invalid.
+func RegisterOTLP(ctx context.Context, config *types.OTLP) Registry {
+	if otlpMeterInstance == nil {
+		meter, err := newOTLPMeter(config)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Unable to create OTLP metrics: %v", err)
+			return nil
+		}
+
+		exporter, err := newOTLPExporter(config)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Unable to create OTLP exporter: %v", err)
+			return nil
+		}
+
+		otlpMeterInstance = meter
+		otlpTicker = initOTLPTicker(ctx, config, meter, exporter)
+	}
+
+	return newOTLPRegistry(otlpMeterInstance, config)
+}
+
+func newOTLPRegistry(meter *otlpMeter, config *types.OTLP) Registry {
+	registry := &standardRegistry{
+		configReloadsCounter:           meter.newCounter(otlpConfigReloadsName, ""),
+		configReloadsFailureCounter:    meter.newCounter(otlpConfigReloadsFailureName, ""),
+		lastConfigReloadSuccessGauge:   meter.newGauge(otlpLastConfigReloadSuccessName, "s"),
+		lastConfigReloadFailureGauge:   meter.newGauge(otlpLastConfigReloadFailureName, "s"),
+		providerLastMessageGauge:       meter.newGauge(otlpProviderLastMessageName, "s"),
+		providerStaleGauge:             meter.newGauge(otlpProviderStaleName, ""),
+		configHashGauge:                meter.newGauge(otlpConfigHashName, ""),
+		tlsCertsNotAfterTimestampGauge: meter.newGauge(otlpTLSCertsNotAfterTimestampName, "s"),
+		accessLogDroppedLinesCounter:   meter.newCounter(otlpAccessLogDroppedLinesName, ""),
+		accessLogBufferedLinesGauge:    meter.newGauge(otlpAccessLogBufferedLinesName, ""),
+		gatewayAttachedRoutesGauge:     meter.newGauge(otlpGatewayAttachedRoutesName, ""),
+		resourceUsageGauge:             meter.newGauge(otlpResourceUsageName, ""),
+		resourceOverloadedGauge:        meter.newGauge(otlpResourceOverloadedName, ""),
+		resourceShedCounter:            meter.newCounter(otlpResourceShedName, ""),
+	}
+
+	if config.AddEntryPointsLabels {
+		registry.epEnabled = config.AddEntryPointsLabels
+		registry.entryPointReqsCounter = meter.newCounter(otlpEntryPointReqsName, "")
+		registry.entryPointReqsTLSCounter = meter.newCounter(otlpEntryPointReqsTLSName, "")
+		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(meter.newHistogram(otlpEntryPointReqDurationName, "s"), time.Second)
+		registry.entryPointOpenConnsGauge = meter.newGauge(otlpEntryPointOpenConnsName, "")
+		registry.routerExperimentAssignmentsCounter = meter.newCounter(otlpRouterExperimentAssignments, "")
+	}
+
+	if config.AddServicesLabels {
+		registry.svcEnabled = config.AddServicesLabels
+		registry.serviceReqsCounter = meter.newCounter(otlpServiceReqsName, "")
+		registry.serviceReqsTLSCounter = meter.newCounter(otlpServiceReqsTLSName, "")
+		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(meter.newHistogram(otlpServiceReqDurationName, "s"), time.Second)
+		registry.serviceOpenConnsGauge = meter.newGauge(otlpServiceOpenConnsName, "")
+		registry.serviceRetriesCounter = meter.newCounter(otlpServiceRetriesName, "")
+		registry.serviceServerUpGauge = meter.newGauge(otlpServiceServerUpName, "")
+		registry.routerOpenWebSocketsGauge = meter.newGauge(otlpRouterOpenWebSocketsName, "")
+		registry.routerOpenUpgradedConnsGauge = meter.newGauge(otlpRouterOpenUpgradedConnsName, "")
+		registry.serviceUpstreamOpenConnsGauge = meter.newGauge(otlpServiceUpstreamOpenConnsName, "")
+		registry.serviceUpstreamIdleConnsGauge = meter.newGauge(otlpServiceUpstreamIdleConnsName, "")
+		registry.serviceUpstreamActiveConnsGauge = meter.newGauge(otlpServiceUpstreamActiveConnsName, "")
+		registry.serviceUpstreamConnsCounter = meter.newCounter(otlpServiceUpstreamConnsName, "")
+		registry.serviceUpstreamDNSFailuresCounter = meter.newCounter(otlpServiceUpstreamDNSFailuresName, "")
+		registry.serviceProtocolDowngradesCounter = meter.newCounter(otlpServiceProtocolDowngradesName, "")
+		registry.serviceTCPConnectRetriesCounter = meter.newCounter(otlpServiceTCPConnectRetriesName, "")
+		registry.serviceCanaryWeightGauge = meter.newGauge(otlpServiceCanaryWeightName, "%")
+		registry.serviceCanaryAbortsCounter = meter.newCounter(otlpServiceCanaryAbortsName, "")
+		registry.circuitBreakerTrippedGauge = meter.newGauge(otlpCircuitBreakerTrippedName, "")
+		registry.limitsViolationsCounter = meter.newCounter(otlpLimitsViolationsName, "")
+	}
+
+	if config.AddServicesLabels && config.AddPathTemplatesLabels {
+		registry.pathEnabled = true
+		registry.servicePathReqsCounter = meter.newCounter(otlpServicePathReqsName, "")
+		registry.servicePathReqDurationHistogram, _ = NewHistogramWithScale(meter.newHistogram(otlpServicePathReqDurationName, "s"), time.Second)
+	}
+
+	if config.AddMiddlewaresLabels {
+		registry.middlewareEnabled = true
+		registry.middlewareReqsCounter = meter.newCounter(otlpMiddlewareReqsName, "")
+		registry.middlewareReqDurationHistogram, _ = NewHistogramWithScale(meter.newHistogram(otlpMiddlewareReqDurationName, "s"), time.Second)
+		registry.middlewareShortCircuitsCounter = meter.newCounter(otlpMiddlewareShortCircuitsName, "")
+		registry.tcpRateLimitRejectedConnsCounter = meter.newCounter(otlpTCPRateLimitRejectedConnsName, "")
+	}
+
+	return registry
+}
+
+func initOTLPTicker(ctx context.Context, config *types.OTLP, meter *otlpMeter, exporter otlpExporter) *time.Ticker {
+	report := time.NewTicker(time.Duration(config.PushInterval))
+
+	safe.Go(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-report.C:
+				if err := exporter.export(ctx, meter.collect(time.Now())); err != nil {
+					log.FromContext(ctx).Errorf("Unable to export metrics: %v", err)
+				}
+			}
+		}
+	})
+
+	return report
+}
+
+// StopOTLP stops internal otlpTicker which controls the pushing of metrics to the OTLP collector and resets it to `nil`.
+func StopOTLP() {
+	if otlpTicker != nil {
+		otlpTicker.Stop()
+	}
+	otlpTicker = nil
+	otlpMeterInstance = nil
+}
+
+// otlpExporter sends the metrics to an OTLP collector.
+type otlpExporter interface {
+	export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) error
+}
+
+func newOTLPExporter(config *types.OTLP) (otlpExporter, error) {
+	switch config.Protocol {
+	case "", otlpProtocolGRPC:
+		opt := grpc.WithInsecure()
+		if !config.Insecure {
+			opt = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+		}
+
+		conn, err := grpc.Dial(config.Address, opt)
+		if err != nil {
+			return nil, fmt.Errorf("unable to dial %s: %w", config.Address, err)
+		}
+
+		return &otlpGRPCExporter{conn: conn, headers: config.Headers}, nil
+
+	case otlpProtocolHTTP:
+		scheme := "https"
+		if config.Insecure {
+			scheme = "http"
+		}
+
+		return &otlpHTTPExporter{
+			client:  &http.Client{Timeout: time.Duration(config.PushInterval)},
+			url:     fmt.Sprintf("%s://%s/v1/metrics", scheme, config.Address),
+			headers: config.Headers,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported protocol: %q", config.Protocol)
+	}
+}
+
+// otlpGRPCExporter exports the metrics with OTLP over gRPC.
+type otlpGRPCExporter struct {
+	conn    *grpc.ClientConn
+	headers map[string]string
+}
+
+func (e *otlpGRPCExporter) export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) error {
+	for name, value := range e.headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, name, value)
+	}
+
+	return e.conn.Invoke(ctx, otlpExportMethod, req, &collectorpb.ExportMetricsServiceResponse{})
+}
+
+// otlpHTTPExporter exports the metrics with OTLP over HTTP, encoded with protobuf.
+type otlpHTTPExporter struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func (e *otlpHTTPExporter) export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("unable to encode metrics: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for name, value := range e.headers {
+		httpReq.Header.Set(name, value)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code from %s: %d", e.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+type otlpInstrumentKind int
+
+const (
+	otlpCounterKind otlpInstrumentKind = iota
+	otlpGaugeKind
+	otlpHistogramKind
+)
+
+// otlpMeter holds the values of the metrics between two exports.
+type otlpMeter struct {
+	temporality metricspb.AggregationTemporality
+	boundaries  []float64
+
+	mu          sync.Mutex
+	instruments map[string]*otlpInstrument
+}
+
+func newOTLPMeter(config *types.OTLP) (*otlpMeter, error) {
+	meter := &otlpMeter{
+		boundaries:  []float64{0.1, 0.3, 1.2, 5.0},
+		instruments: make(map[string]*otlpInstrument),
+	}
+
+	if config.ExplicitBoundaries != nil {
+		meter.boundaries = config.ExplicitBoundaries
+	}
+
+	if !sort.Float64sAreSorted(meter.boundaries) {
+		return nil, fmt.Errorf("explicit boundaries must be sorted: %v", meter.boundaries)
+	}
+
+	switch config.Temporality {
+	case "", types.OTLPTemporalityCumulative:
+		meter.temporality = metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	case types.OTLPTemporalityDelta:
+		meter.temporality = metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	default:
+		return nil, fmt.Errorf("unsupported temporality: %q", config.Temporality)
+	}
+
+	return meter, nil
+}
+
+// instrument returns the instrument with the given name, and creates it if needed,
+// as the registries created for each configuration share the meter.
+func (m *otlpMeter) instrument(name, unit string, kind otlpInstrumentKind) *otlpInstrument {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if instrument, ok := m.instruments[name]; ok {
+		return instrument
+	}
+
+	instrument := &otlpInstrument{
+		name:   name,
+		unit:   unit,
+		kind:   kind,
+		series: make(map[string]*otlpSeries),
+	}
+	m.instruments[name] = instrument
+
+	return instrument
+}
+
+func (m *otlpMeter) newCounter(name, unit string) metrics.Counter {
+	return &otlpCounter{instrument: m.instrument(name, unit, otlpCounterKind)}
+}
+
+func (m *otlpMeter) newGauge(name, unit string) metrics.Gauge {
+	return &otlpGauge{instrument: m.instrument(name, unit, otlpGaugeKind)}
+}
+
+func (m *otlpMeter) newHistogram(name, unit string) metrics.Histogram {
+	return &otlpHistogram{instrument: m.instrument(name, unit, otlpHistogramKind), boundaries: m.boundaries}
+}
+
+// collect returns the export request of the metrics,
+// and resets the counters and the histograms when exporting deltas.
+func (m *otlpMeter) collect(now time.Time) *collectorpb.ExportMetricsServiceRequest {
+	m.mu.Lock()
+	instruments := make([]*otlpInstrument, 0, len(m.instruments))
+	for _, instrument := range m.instruments {
+		instruments = append(instruments, instrument)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(instruments, func(i, j int) bool {
+		return instruments[i].name < instruments[j].name
+	})
+
+	var otlpMetrics []*metricspb.Metric
+	for _, instrument := range instruments {
+		if metric := instrument.collect(now, m.temporality, m.boundaries); metric != nil {
+			otlpMetrics = append(otlpMetrics, metric)
+		}
+	}
+
+	return &collectorpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{newOTLPKeyValue("service.name", "traefik")},
+			},
+			InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{{
+				InstrumentationLibrary: &commonpb.InstrumentationLibrary{Name: "traefik", Version: version.Version},
+				Metrics:                otlpMetrics,
+			}},
+		}},
+	}
+}
+
+type otlpInstrument struct {
+	name string
+	unit string
+	kind otlpInstrumentKind
+
+	mu     sync.Mutex
+	series map[string]*otlpSeries
+}
+
+// otlpSeries holds the values of an instrument for a set of labels.
+type otlpSeries struct {
+	labels       []string
+	start        time.Time
+	value        float64
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+func (i *otlpInstrument) update(labels []string, fn func(s *otlpSeries)) {
+	key := strings.Join(labels, "\x00")
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	s, ok := i.series[key]
+	if !ok {
+		s = &otlpSeries{labels: labels, start: time.Now()}
+		i.series[key] = s
+	}
+
+	fn(s)
+}
+
+func (i *otlpInstrument) collect(now time.Time, temporality metricspb.AggregationTemporality, boundaries []float64) *metricspb.Metric {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.series) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(i.series))
+	for key := range i.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		sum       *metricspb.Sum
+		gauge     *metricspb.Gauge
+		histogram *metricspb.Histogram
+	)
+
+	metric := &metricspb.Metric{Name: i.name, Unit: i.unit}
+
+	switch i.kind {
+	case otlpCounterKind:
+		sum = &metricspb.Sum{AggregationTemporality: temporality, IsMonotonic: true}
+		metric.Data = &metricspb.Metric_Sum{Sum: sum}
+	case otlpGaugeKind:
+		gauge = &metricspb.Gauge{}
+		metric.Data = &metricspb.Metric_Gauge{Gauge: gauge}
+	case otlpHistogramKind:
+		histogram = &metricspb.Histogram{AggregationTemporality: temporality}
+		metric.Data = &metricspb.Metric_Histogram{Histogram: histogram}
+	}
+
+	for _, key := range keys {
+		s := i.series[key]
+
+		switch i.kind {
+		case otlpCounterKind:
+			sum.DataPoints = append(sum.DataPoints, &metricspb.NumberDataPoint{
+				StartTimeUnixNano: uint64(s.start.UnixNano()),
+				TimeUnixNano:      uint64(now.UnixNano()),
+				Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: s.value},
+				Attributes:        newOTLPAttributes(s.labels),
+			})
+		case otlpGaugeKind:
+			gauge.DataPoints = append(gauge.DataPoints, &metricspb.NumberDataPoint{
+				TimeUnixNano: uint64(now.UnixNano()),
+				Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: s.value},
+				Attributes:   newOTLPAttributes(s.labels),
+			})
+		case otlpHistogramKind:
+			histogram.DataPoints = append(histogram.DataPoints, &metricspb.HistogramDataPoint{
+				StartTimeUnixNano: uint64(s.start.UnixNano()),
+				TimeUnixNano:      uint64(now.UnixNano()),
+				Count:             s.count,
+				Sum:               s.sum,
+				BucketCounts:      append([]uint64{}, s.bucketCounts...),
+				ExplicitBounds:    boundaries,
+				Attributes:        newOTLPAttributes(s.labels),
+			})
+		}
+
+		if temporality == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA && i.kind != otlpGaugeKind {
+			s.start = now
+			s.value = 0
+			s.count = 0
+			s.sum = 0
+			s.bucketCounts = make([]uint64, len(s.bucketCounts))
+		}
+	}
+
+	return metric
+}
+
+// newOTLPAttributes converts the label pairs to attributes, ignoring an incomplete pair.
+func newOTLPAttributes(labels []string) []*commonpb.KeyValue {
+	var attributes []*commonpb.KeyValue
+	for i := 0; i+1 < len(labels); i += 2 {
+		attributes = append(attributes, newOTLPKeyValue(labels[i], labels[i+1]))
+	}
+
+	return attributes
+}
+
+func newOTLPKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+type otlpCounter struct {
+	instrument *otlpInstrument
+	labels     []string
+}
+
+// With returns a new otlpCounter with the given labels.
+func (c *otlpCounter) With(labelValues ...string) metrics.Counter {
+	return &otlpCounter{
+		instrument: c.instrument,
+		labels:     append(append([]string{}, c.labels...), labelValues...),
+	}
+}
+
+// Add adds the given delta to the counter.
+func (c *otlpCounter) Add(delta float64) {
+	c.instrument.update(c.labels, func(s *otlpSeries) {
+		s.value += delta
+	})
+}
+
+type otlpGauge struct {
+	instrument *otlpInstrument
+	labels     []string
+}
+
+// With returns a new otlpGauge with the given labels.
+func (g *otlpGauge) With(labelValues ...string) metrics.Gauge {
+	return &otlpGauge{
+		instrument: g.instrument,
+		labels:     append(append([]string{}, g.labels...), labelValues...),
+	}
+}
+
+// Set sets the gauge value.
+func (g *otlpGauge) Set(value float64) {
+	g.instrument.update(g.labels, func(s *otlpSeries) {
+		s.value = value
+	})
+}
+
+// Add adds the given delta to the gauge.
+func (g *otlpGauge) Add(delta float64) {
+	g.instrument.update(g.labels, func(s *otlpSeries) {
+		s.value += delta
+	})
+}
+
+type otlpHistogram struct {
+	instrument *otlpInstrument
+	boundaries []float64
+	labels     []string
+}
+
+// With returns a new otlpHistogram with the given labels.
+func (h *otlpHistogram) With(labelValues ...string) metrics.Histogram {
+	return &otlpHistogram{
+		instrument: h.instrument,
+		boundaries: h.boundaries,
+		labels:     append(append([]string{}, h.labels...), labelValues...),
+	}
+}
+
+// Observe records the value in the first bucket whose upper boundary is greater or equal.
+func (h *otlpHistogram) Observe(value float64) {
+	bucket := sort.SearchFloat64s(h.boundaries, value)
+
+	h.instrument.update(h.labels, func(s *otlpSeries) {
+		if s.bucketCounts == nil {
+			s.bucketCounts = make([]uint64, len(h.boundaries)+1)
+		}
+
+		s.bucketCounts[bucket]++
+		s.count++
+		s.sum += value
+	})
+}
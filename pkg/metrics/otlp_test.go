@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	collectorpb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/collector/metrics/v1"
+	metricspb "github.com/traefik/traefik/v2/pkg/metrics/otlpproto/metrics/v1"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestOTLP_HTTP(t *testing.T) {
+	c := make(chan *collectorpb.ExportMetricsServiceRequest, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/metrics", req.URL.Path)
+		assert.Equal(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+		assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		exportReq := &collectorpb.ExportMetricsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, exportReq))
+
+		select {
+		case c <- exportReq:
+		default:
+		}
+	}))
+	defer ts.Close()
+
+	registry := RegisterOTLP(context.Background(), &types.OTLP{
+		Address:              ts.Listener.Addr().String(),
+		Protocol:             "http",
+		Insecure:             true,
+		Headers:              map[string]string{"X-Foo": "bar"},
+		PushInterval:         ptypes.Duration(time.Second),
+		AddEntryPointsLabels: true,
+		AddServicesLabels:    true,
+	})
+	require.NotNil(t, registry)
+	defer StopOTLP()
+
+	if !registry.IsEpEnabled() || !registry.IsSvcEnabled() {
+		t.Fatalf("OTLP registry must be epEnabled")
+	}
+
+	registry.ServiceReqsCounter().With("service", "test", "code", "200").Add(1)
+	registry.ServiceReqDurationHistogram().With("service", "test").Observe(2)
+	registry.EntryPointOpenConnsGauge().With("entrypoint", "test").Set(2)
+
+	var exportReq *collectorpb.ExportMetricsServiceRequest
+	select {
+	case exportReq = <-c:
+	case <-time.After(5 * time.Second):
+		t.Fatal("metrics were not exported")
+	}
+
+	require.Equal(t, 1, len(exportReq.ResourceMetrics))
+	require.Equal(t, 1, len(exportReq.ResourceMetrics[0].InstrumentationLibraryMetrics))
+
+	exported := make(map[string]*metricspb.Metric)
+	for _, metric := range exportReq.ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics {
+		exported[metric.Name] = metric
+	}
+
+	reqs := exported[otlpServiceReqsName]
+	require.NotNil(t, reqs)
+	require.NotNil(t, reqs.GetSum())
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, reqs.GetSum().AggregationTemporality)
+	assert.True(t, reqs.GetSum().IsMonotonic)
+	require.Equal(t, 1, len(reqs.GetSum().DataPoints))
+	assert.Equal(t, float64(1), reqs.GetSum().DataPoints[0].GetAsDouble())
+
+	attributes := make(map[string]string)
+	for _, attribute := range reqs.GetSum().DataPoints[0].Attributes {
+		attributes[attribute.Key] = attribute.Value.GetStringValue()
+	}
+	assert.Equal(t, map[string]string{"service": "test", "code": "200"}, attributes)
+
+	duration := exported[otlpServiceReqDurationName]
+	require.NotNil(t, duration)
+	require.NotNil(t, duration.GetHistogram())
+	require.Equal(t, 1, len(duration.GetHistogram().DataPoints))
+	assert.Equal(t, "s", duration.Unit)
+	assert.Equal(t, uint64(1), duration.GetHistogram().DataPoints[0].Count)
+	assert.Equal(t, float64(2), duration.GetHistogram().DataPoints[0].Sum)
+	assert.Equal(t, []uint64{0, 0, 0, 1, 0}, duration.GetHistogram().DataPoints[0].BucketCounts)
+
+	openConns := exported[otlpEntryPointOpenConnsName]
+	require.NotNil(t, openConns)
+	require.NotNil(t, openConns.GetGauge())
+	require.Equal(t, 1, len(openConns.GetGauge().DataPoints))
+	assert.Equal(t, float64(2), openConns.GetGauge().DataPoints[0].GetAsDouble())
+}
+
+func TestOTLPMeter_collect(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		temporality         string
+		expectedTemporality metricspb.AggregationTemporality
+		expectedCounter     float64
+		expectedCount       uint64
+		expectedGauge       float64
+	}{
+		{
+			desc:                "cumulative",
+			temporality:         types.OTLPTemporalityCumulative,
+			expectedTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			expectedCounter:     3,
+			expectedCount:       2,
+			expectedGauge:       5,
+		},
+		{
+			desc:                "delta",
+			temporality:         types.OTLPTemporalityDelta,
+			expectedTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+			expectedCounter:     2,
+			expectedCount:       1,
+			expectedGauge:       5,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			meter, err := newOTLPMeter(&types.OTLP{Temporality: test.temporality, ExplicitBoundaries: []float64{1}})
+			require.NoError(t, err)
+
+			counter := meter.newCounter("counter", "")
+			histogram := meter.newHistogram("histogram", "s")
+			gauge := meter.newGauge("gauge", "")
+
+			counter.Add(1)
+			histogram.Observe(0.5)
+			gauge.Set(5)
+
+			start := time.Now()
+			meter.collect(start)
+
+			counter.Add(2)
+			histogram.Observe(1.5)
+
+			metrics := meter.collect(start.Add(time.Second)).ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics
+			require.Equal(t, 3, len(metrics))
+
+			// The metrics are sorted by name.
+			counterPoint := metrics[0].GetSum().DataPoints[0]
+			assert.Equal(t, test.expectedTemporality, metrics[0].GetSum().AggregationTemporality)
+			assert.Equal(t, test.expectedCounter, counterPoint.GetAsDouble())
+
+			gaugePoint := metrics[1].GetGauge().DataPoints[0]
+			assert.Equal(t, test.expectedGauge, gaugePoint.GetAsDouble())
+
+			histogramPoint := metrics[2].GetHistogram().DataPoints[0]
+			assert.Equal(t, test.expectedTemporality, metrics[2].GetHistogram().AggregationTemporality)
+			assert.Equal(t, test.expectedCount, histogramPoint.Count)
+			assert.Equal(t, []float64{1}, histogramPoint.ExplicitBounds)
+			assert.Equal(t, uint64(1), histogramPoint.BucketCounts[1])
+
+			if test.temporality == types.OTLPTemporalityDelta {
+				assert.Equal(t, uint64(start.UnixNano()), counterPoint.StartTimeUnixNano)
+				assert.Equal(t, uint64(0), histogramPoint.BucketCounts[0])
+			} else {
+				assert.True(t, counterPoint.StartTimeUnixNano <= uint64(start.UnixNano()))
+				assert.Equal(t, uint64(1), histogramPoint.BucketCounts[0])
+			}
+		})
+	}
+}
+
+func TestOTLP_invalidConfig(t *testing.T) {
+	_, err := newOTLPMeter(&types.OTLP{Temporality: "foo"})
+	assert.Error(t, err)
+
+	_, err = newOTLPMeter(&types.OTLP{ExplicitBoundaries: []float64{2, 1}})
+	assert.Error(t, err)
+
+	_, err = newOTLPExporter(&types.OTLP{Protocol: "foo"})
+	assert.Error(t, err)
+}
@@ -16,6 +16,24 @@ const (
 	pilotConfigReloadsFailuresTotalName = pilotConfigPrefix + "ReloadsFailureTotal"
 	pilotConfigLastReloadSuccessName    = pilotConfigPrefix + "LastReloadSuccess"
 	pilotConfigLastReloadFailureName    = pilotConfigPrefix + "LastReloadFailure"
+	pilotConfigProviderLastMessageName  = pilotConfigPrefix + "ProviderLastMessage"
+	pilotConfigProviderStaleName        = pilotConfigPrefix + "ProviderStale"
+	pilotConfigHashName                 = pilotConfigPrefix + "Hash"
+
+	// access log.
+	pilotAccessLogPrefix            = "accessLog"
+	pilotAccessLogDroppedLinesName  = pilotAccessLogPrefix + "DroppedLinesTotal"
+	pilotAccessLogBufferedLinesName = pilotAccessLogPrefix + "BufferedLines"
+
+	// Kubernetes Gateway API provider.
+	pilotGatewayPrefix             = "gateway"
+	pilotGatewayAttachedRoutesName = pilotGatewayPrefix + "ListenerAttachedRoutes"
+
+	// resource guard.
+	pilotResourcePrefix         = "resource"
+	pilotResourceUsageName      = pilotResourcePrefix + "Usage"
+	pilotResourceOverloadedName = pilotResourcePrefix + "Overloaded"
+	pilotResourceShedTotalName  = pilotResourcePrefix + "ShedTotal"
 
 	// entry point.
 	pilotEntryPointPrefix           = "entrypoint"
@@ -32,6 +50,30 @@ const (
 	pilotServiceOpenConnsName    = pilotServicePrefix + "OpenConnections"
 	pilotServiceRetriesTotalName = pilotServicePrefix + "RetriesTotal"
 	pilotServiceServerUpName     = pilotServicePrefix + "ServerUp"
+
+	// router level.
+	pilotRouterPrefix                         = "router"
+	pilotRouterOpenWebSocketsName             = pilotRouterPrefix + "OpenWebSockets"
+	pilotRouterOpenUpgradedConnsName          = pilotRouterPrefix + "OpenUpgradedConns"
+	pilotRouterExperimentAssignmentsTotalName = pilotRouterPrefix + "ExperimentAssignmentsTotal"
+
+	// upstream level.
+	pilotServiceUpstreamOpenConnsName        = pilotServicePrefix + "UpstreamOpenConnections"
+	pilotServiceUpstreamIdleConnsName        = pilotServicePrefix + "UpstreamIdleConnections"
+	pilotServiceUpstreamActiveConnsName      = pilotServicePrefix + "UpstreamActiveConnections"
+	pilotServiceUpstreamConnsTotalName       = pilotServicePrefix + "UpstreamConnectionsTotal"
+	pilotServiceUpstreamDNSFailuresTotalName = pilotServicePrefix + "UpstreamDNSFailuresTotal"
+	pilotServiceProtocolDowngradesTotalName  = pilotServicePrefix + "ProtocolDowngradesTotal"
+	pilotServiceTCPConnectRetriesTotalName   = pilotServicePrefix + "TCPConnectRetriesTotal"
+
+	// promotion level.
+	pilotServiceCanaryWeightName      = pilotServicePrefix + "CanaryWeight"
+	pilotServiceCanaryAbortsTotalName = pilotServicePrefix + "CanaryAbortsTotal"
+
+	// middleware level.
+	pilotMiddlewarePrefix          = "middleware"
+	pilotCircuitBreakerTrippedName = pilotMiddlewarePrefix + "CircuitBreakerTripped"
+	pilotLimitsViolationsTotalName = pilotMiddlewarePrefix + "LimitsViolationsTotal"
 )
 
 const root = "value"
@@ -54,11 +96,24 @@ func RegisterPilot() *PilotRegistry {
 	standardRegistry.configReloadsFailureCounter = pr.newCounter(pilotConfigReloadsFailuresTotalName)
 	standardRegistry.lastConfigReloadSuccessGauge = pr.newGauge(pilotConfigLastReloadSuccessName)
 	standardRegistry.lastConfigReloadFailureGauge = pr.newGauge(pilotConfigLastReloadFailureName)
+	standardRegistry.providerLastMessageGauge = pr.newGauge(pilotConfigProviderLastMessageName)
+	standardRegistry.providerStaleGauge = pr.newGauge(pilotConfigProviderStaleName)
+	standardRegistry.configHashGauge = pr.newGauge(pilotConfigHashName)
+
+	standardRegistry.accessLogDroppedLinesCounter = pr.newCounter(pilotAccessLogDroppedLinesName)
+	standardRegistry.accessLogBufferedLinesGauge = pr.newGauge(pilotAccessLogBufferedLinesName)
+
+	standardRegistry.gatewayAttachedRoutesGauge = pr.newGauge(pilotGatewayAttachedRoutesName)
+
+	standardRegistry.resourceUsageGauge = pr.newGauge(pilotResourceUsageName)
+	standardRegistry.resourceOverloadedGauge = pr.newGauge(pilotResourceOverloadedName)
+	standardRegistry.resourceShedCounter = pr.newCounter(pilotResourceShedTotalName)
 
 	standardRegistry.entryPointReqsCounter = pr.newCounter(pilotEntryPointReqsTotalName)
 	standardRegistry.entryPointReqsTLSCounter = pr.newCounter(pilotEntryPointReqsTLSTotalName)
 	standardRegistry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(pr.newHistogram(pilotEntryPointReqDurationName), time.Millisecond)
 	standardRegistry.entryPointOpenConnsGauge = pr.newGauge(pilotEntryPointOpenConnsName)
+	standardRegistry.routerExperimentAssignmentsCounter = pr.newCounter(pilotRouterExperimentAssignmentsTotalName)
 
 	standardRegistry.serviceReqsCounter = pr.newCounter(pilotServiceReqsTotalName)
 	standardRegistry.serviceReqsTLSCounter = pr.newCounter(pilotServiceReqsTLSTotalName)
@@ -66,6 +121,19 @@ func RegisterPilot() *PilotRegistry {
 	standardRegistry.serviceOpenConnsGauge = pr.newGauge(pilotServiceOpenConnsName)
 	standardRegistry.serviceRetriesCounter = pr.newCounter(pilotServiceRetriesTotalName)
 	standardRegistry.serviceServerUpGauge = pr.newGauge(pilotServiceServerUpName)
+	standardRegistry.routerOpenWebSocketsGauge = pr.newGauge(pilotRouterOpenWebSocketsName)
+	standardRegistry.routerOpenUpgradedConnsGauge = pr.newGauge(pilotRouterOpenUpgradedConnsName)
+	standardRegistry.serviceUpstreamOpenConnsGauge = pr.newGauge(pilotServiceUpstreamOpenConnsName)
+	standardRegistry.serviceUpstreamIdleConnsGauge = pr.newGauge(pilotServiceUpstreamIdleConnsName)
+	standardRegistry.serviceUpstreamActiveConnsGauge = pr.newGauge(pilotServiceUpstreamActiveConnsName)
+	standardRegistry.serviceUpstreamConnsCounter = pr.newCounter(pilotServiceUpstreamConnsTotalName)
+	standardRegistry.serviceUpstreamDNSFailuresCounter = pr.newCounter(pilotServiceUpstreamDNSFailuresTotalName)
+	standardRegistry.serviceProtocolDowngradesCounter = pr.newCounter(pilotServiceProtocolDowngradesTotalName)
+	standardRegistry.serviceTCPConnectRetriesCounter = pr.newCounter(pilotServiceTCPConnectRetriesTotalName)
+	standardRegistry.serviceCanaryWeightGauge = pr.newGauge(pilotServiceCanaryWeightName)
+	standardRegistry.serviceCanaryAbortsCounter = pr.newCounter(pilotServiceCanaryAbortsTotalName)
+	standardRegistry.circuitBreakerTrippedGauge = pr.newGauge(pilotCircuitBreakerTrippedName)
+	standardRegistry.limitsViolationsCounter = pr.newCounter(pilotLimitsViolationsTotalName)
 
 	return pr
 }
@@ -28,11 +28,29 @@ const (
 	configReloadsFailuresTotalName = metricConfigPrefix + "reloads_failure_total"
 	configLastReloadSuccessName    = metricConfigPrefix + "last_reload_success"
 	configLastReloadFailureName    = metricConfigPrefix + "last_reload_failure"
+	configProviderLastMessageName  = metricConfigPrefix + "provider_last_message"
+	configProviderStaleName        = metricConfigPrefix + "provider_stale"
+	configHashName                 = metricConfigPrefix + "hash"
 
 	// TLS.
 	metricsTLSPrefix          = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
 
+	// access log.
+	metricAccessLogPrefix        = MetricNamePrefix + "accesslog_"
+	accessLogDroppedLinesTotName = metricAccessLogPrefix + "dropped_lines_total"
+	accessLogBufferedLinesName   = metricAccessLogPrefix + "buffered_lines"
+
+	// Kubernetes Gateway API provider.
+	metricGatewayPrefix       = MetricNamePrefix + "gateway_"
+	gatewayAttachedRoutesName = metricGatewayPrefix + "listener_attached_routes"
+
+	// resource guard.
+	metricResourcePrefix   = MetricNamePrefix + "resource_"
+	resourceUsageName      = metricResourcePrefix + "usage"
+	resourceOverloadedName = metricResourcePrefix + "overloaded"
+	resourceShedTotalName  = metricResourcePrefix + "shed_total"
+
 	// entry point.
 	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
 	entryPointReqsTotalName    = metricEntryPointPrefix + "requests_total"
@@ -43,13 +61,43 @@ const (
 	// service level.
 
 	// MetricServicePrefix prefix of all service metric names.
-	MetricServicePrefix     = MetricNamePrefix + "service_"
-	serviceReqsTotalName    = MetricServicePrefix + "requests_total"
-	serviceReqsTLSTotalName = MetricServicePrefix + "requests_tls_total"
-	serviceReqDurationName  = MetricServicePrefix + "request_duration_seconds"
-	serviceOpenConnsName    = MetricServicePrefix + "open_connections"
-	serviceRetriesTotalName = MetricServicePrefix + "retries_total"
-	serviceServerUpName     = MetricServicePrefix + "server_up"
+	MetricServicePrefix        = MetricNamePrefix + "service_"
+	serviceReqsTotalName       = MetricServicePrefix + "requests_total"
+	serviceReqsTLSTotalName    = MetricServicePrefix + "requests_tls_total"
+	serviceReqDurationName     = MetricServicePrefix + "request_duration_seconds"
+	servicePathReqsTotalName   = MetricServicePrefix + "path_requests_total"
+	servicePathReqDurationName = MetricServicePrefix + "path_request_duration_seconds"
+	serviceOpenConnsName       = MetricServicePrefix + "open_connections"
+	serviceRetriesTotalName    = MetricServicePrefix + "retries_total"
+	serviceServerUpName        = MetricServicePrefix + "server_up"
+
+	// router level.
+	metricRouterPrefix                   = MetricNamePrefix + "router_"
+	routerOpenWebSocketsName             = metricRouterPrefix + "open_websockets"
+	routerOpenUpgradedConnsName          = metricRouterPrefix + "open_upgraded_connections"
+	routerExperimentAssignmentsTotalName = metricRouterPrefix + "experiment_assignments_total"
+
+	// upstream level.
+	serviceUpstreamOpenConnsName        = MetricServicePrefix + "upstream_open_connections"
+	serviceUpstreamIdleConnsName        = MetricServicePrefix + "upstream_idle_connections"
+	serviceUpstreamActiveConnsName      = MetricServicePrefix + "upstream_active_connections"
+	serviceUpstreamConnsTotalName       = MetricServicePrefix + "upstream_connections_total"
+	serviceUpstreamDNSFailuresTotalName = MetricServicePrefix + "upstream_dns_failures_total"
+	serviceProtocolDowngradesTotalName  = MetricServicePrefix + "protocol_downgrades_total"
+	serviceTCPConnectRetriesTotalName   = MetricServicePrefix + "tcp_connect_retries_total"
+
+	// promotion level.
+	serviceCanaryWeightName      = MetricServicePrefix + "canary_weight"
+	serviceCanaryAbortsTotalName = MetricServicePrefix + "canary_aborts_total"
+
+	// middleware level.
+	metricMiddlewarePrefix         = MetricNamePrefix + "middleware_"
+	circuitBreakerTrippedName      = metricMiddlewarePrefix + "circuit_breaker_tripped"
+	limitsViolationsTotalName      = metricMiddlewarePrefix + "limits_violations_total"
+	middlewareReqsTotalName        = metricMiddlewarePrefix + "requests_total"
+	middlewareReqDurationName      = metricMiddlewarePrefix + "request_duration_seconds"
+	middlewareShortCircuitsTotName = metricMiddlewarePrefix + "short_circuits_total"
+	tcpRateLimitRejectedConnsName  = metricMiddlewarePrefix + "tcp_ratelimit_rejected_connections_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -70,7 +118,7 @@ var promRegistry = stdprometheus.NewRegistry()
 
 // PrometheusHandler exposes Prometheus routes.
 func PrometheusHandler() http.Handler {
-	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{})
+	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true})
 }
 
 // RegisterPrometheus registers all Prometheus metrics.
@@ -125,17 +173,62 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: configLastReloadFailureName,
 		Help: "Last config reload failure",
 	}, []string{})
+	providerLastMessage := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: configProviderLastMessageName,
+		Help: "Timestamp of the last configuration message received from a provider.",
+	}, []string{"provider"})
+	providerStale := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: configProviderStaleName,
+		Help: "Whether a provider has not sent any configuration message within its expected interval, 1 when stale.",
+	}, []string{"provider"})
+	configHash := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: configHashName,
+		Help: "Hash of the applied dynamic configuration.",
+	}, []string{})
 	tlsCertsNotAfterTimesptamp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	accessLogDroppedLines := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: accessLogDroppedLinesTotName,
+		Help: "How many access log lines were dropped because the access log buffer was full.",
+	}, []string{})
+	accessLogBufferedLines := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: accessLogBufferedLinesName,
+		Help: "How many access log lines are waiting to be written.",
+	}, []string{})
+	gatewayAttachedRoutes := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: gatewayAttachedRoutesName,
+		Help: "How many routes are attached to a listener of a Kubernetes Gateway.",
+	}, []string{"namespace", "gateway", "port"})
+	resourceUsage := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: resourceUsageName,
+		Help: "Usage of a process resource watched by the resource guard: open file descriptors, memory bytes, or goroutines.",
+	}, []string{"resource"})
+	resourceOverloaded := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: resourceOverloadedName,
+		Help: "Whether the usage of a process resource is beyond its threshold, 1 when the new connections are shed.",
+	}, []string{})
+	resourceShed := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: resourceShedTotalName,
+		Help: "How many connections and requests were shed by an entrypoint because of the resource usage.",
+	}, []string{"entrypoint", "type"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		providerLastMessage.gv.Describe,
+		providerStale.gv.Describe,
+		configHash.gv.Describe,
 		tlsCertsNotAfterTimesptamp.gv.Describe,
+		accessLogDroppedLines.cv.Describe,
+		accessLogBufferedLines.gv.Describe,
+		gatewayAttachedRoutes.gv.Describe,
+		resourceUsage.gv.Describe,
+		resourceOverloaded.gv.Describe,
+		resourceShed.cv.Describe,
 	}
 
 	reg := &standardRegistry{
@@ -145,7 +238,16 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloadsFailureCounter:    configReloadsFailures,
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
+		providerLastMessageGauge:       providerLastMessage,
+		providerStaleGauge:             providerStale,
+		configHashGauge:                configHash,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimesptamp,
+		accessLogDroppedLinesCounter:   accessLogDroppedLines,
+		accessLogBufferedLinesGauge:    accessLogBufferedLines,
+		gatewayAttachedRoutesGauge:     gatewayAttachedRoutes,
+		resourceUsageGauge:             resourceUsage,
+		resourceOverloadedGauge:        resourceOverloaded,
+		resourceShedCounter:            resourceShed,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -166,18 +268,24 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		routerExperimentAssignments := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerExperimentAssignmentsTotalName,
+			Help: "How many requests were assigned to an experiment variant.",
+		}, []string{"experiment", "variant"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			routerExperimentAssignments.cv.Describe,
 		}...)
 
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.routerExperimentAssignmentsCounter = routerExperimentAssignments
 	}
 
 	if config.AddServicesLabels {
@@ -206,6 +314,58 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		routerOpenWebSockets := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: routerOpenWebSocketsName,
+			Help: "How many open WebSocket connections there are on a router.",
+		}, []string{"router"})
+		routerOpenUpgradedConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: routerOpenUpgradedConnsName,
+			Help: "How many upgraded connections and CONNECT tunnels are open on a router, partitioned by protocol.",
+		}, []string{"router", "protocol"})
+		serviceUpstreamOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceUpstreamOpenConnsName,
+			Help: "How many connections to the servers of a service are open.",
+		}, []string{"service"})
+		serviceUpstreamIdleConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceUpstreamIdleConnsName,
+			Help: "How many open connections to the servers of a service are idle, waiting to be reused.",
+		}, []string{"service"})
+		serviceUpstreamActiveConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceUpstreamActiveConnsName,
+			Help: "How many open connections to the servers of a service are in use by requests.",
+		}, []string{"service"})
+		serviceUpstreamConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceUpstreamConnsTotalName,
+			Help: "How many connections to the servers of a service were used, partitioned by whether they were reused or dialed.",
+		}, []string{"service", "reused"})
+		serviceUpstreamDNSFailures := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceUpstreamDNSFailuresTotalName,
+			Help: "How many resolutions of the servers of a service failed.",
+		}, []string{"service"})
+		serviceProtocolDowngrades := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceProtocolDowngradesTotalName,
+			Help: "How many requests to the servers of a service fell back on another protocol than the preferred one.",
+		}, []string{"service", "from", "to"})
+		serviceTCPConnectRetries := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceTCPConnectRetriesTotalName,
+			Help: "How many connections to the servers of a TCP service were retried against another server.",
+		}, []string{"service"})
+		serviceCanaryWeight := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceCanaryWeightName,
+			Help: "Percentage of the requests of a weighted service forwarded to its canary service by its promotion.",
+		}, []string{"service", "canary"})
+		serviceCanaryAborts := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceCanaryAbortsTotalName,
+			Help: "How many promotions of the canary service of a weighted service were aborted.",
+		}, []string{"service", "canary"})
+		circuitBreakerTripped := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: circuitBreakerTrippedName,
+			Help: "Circuit breaker is tripped, described by gauge value of 0 or 1.",
+		}, []string{"middleware"})
+		limitsViolations := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: limitsViolationsTotalName,
+			Help: "How many requests were rejected by a limits middleware, partitioned by the exceeded limit.",
+		}, []string{"middleware", "limit"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -214,6 +374,19 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			routerOpenWebSockets.gv.Describe,
+			routerOpenUpgradedConns.gv.Describe,
+			serviceUpstreamOpenConns.gv.Describe,
+			serviceUpstreamIdleConns.gv.Describe,
+			serviceUpstreamActiveConns.gv.Describe,
+			serviceUpstreamConns.cv.Describe,
+			serviceUpstreamDNSFailures.cv.Describe,
+			serviceProtocolDowngrades.cv.Describe,
+			serviceTCPConnectRetries.cv.Describe,
+			serviceCanaryWeight.gv.Describe,
+			serviceCanaryAborts.cv.Describe,
+			circuitBreakerTripped.gv.Describe,
+			limitsViolations.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -222,6 +395,73 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.routerOpenWebSocketsGauge = routerOpenWebSockets
+		reg.routerOpenUpgradedConnsGauge = routerOpenUpgradedConns
+		reg.serviceUpstreamOpenConnsGauge = serviceUpstreamOpenConns
+		reg.serviceUpstreamIdleConnsGauge = serviceUpstreamIdleConns
+		reg.serviceUpstreamActiveConnsGauge = serviceUpstreamActiveConns
+		reg.serviceUpstreamConnsCounter = serviceUpstreamConns
+		reg.serviceUpstreamDNSFailuresCounter = serviceUpstreamDNSFailures
+		reg.serviceProtocolDowngradesCounter = serviceProtocolDowngrades
+		reg.serviceTCPConnectRetriesCounter = serviceTCPConnectRetries
+		reg.serviceCanaryWeightGauge = serviceCanaryWeight
+		reg.serviceCanaryAbortsCounter = serviceCanaryAborts
+		reg.circuitBreakerTrippedGauge = circuitBreakerTripped
+		reg.limitsViolationsCounter = limitsViolations
+	}
+
+	if config.AddServicesLabels && config.AddPathTemplatesLabels {
+		servicePathReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: servicePathReqsTotalName,
+			Help: "How many HTTP requests processed on a service, partitioned by path template, status code, and method.",
+		}, []string{"code", "method", "path", "service"})
+		servicePathReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    servicePathReqDurationName,
+			Help:    "How long it took to process the request on a service, partitioned by path template, status code, and method.",
+			Buckets: buckets,
+		}, []string{"code", "method", "path", "service"})
+
+		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
+			servicePathReqs.cv.Describe,
+			servicePathReqDurations.hv.Describe,
+		}...)
+
+		reg.pathEnabled = true
+		reg.servicePathReqsCounter = servicePathReqs
+		reg.servicePathReqDurationHistogram, _ = NewHistogramWithScale(servicePathReqDurations, time.Second)
+	}
+
+	if config.AddMiddlewaresLabels {
+		middlewareReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: middlewareReqsTotalName,
+			Help: "How many HTTP requests went through a middleware.",
+		}, []string{"middleware"})
+		middlewareReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    middlewareReqDurationName,
+			Help:    "How long a middleware took to process the request, excluding the time spent in the next handlers.",
+			Buckets: buckets,
+		}, []string{"middleware"})
+		middlewareShortCircuits := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: middlewareShortCircuitsTotName,
+			Help: "How many HTTP requests were answered by a middleware without being forwarded, partitioned by status code.",
+		}, []string{"code", "middleware"})
+		tcpRateLimitRejectedConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpRateLimitRejectedConnsName,
+			Help: "How many TCP connections were closed by a rate limit middleware, partitioned by reason.",
+		}, []string{"middleware", "reason"})
+
+		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
+			middlewareReqs.cv.Describe,
+			middlewareReqDurations.hv.Describe,
+			middlewareShortCircuits.cv.Describe,
+			tcpRateLimitRejectedConns.cv.Describe,
+		}...)
+
+		reg.middlewareEnabled = true
+		reg.middlewareReqsCounter = middlewareReqs
+		reg.middlewareReqDurationHistogram, _ = NewHistogramWithScale(middlewareReqDurations, time.Second)
+		reg.middlewareShortCircuitsCounter = middlewareShortCircuits
+		reg.tcpRateLimitRejectedConnsCounter = tcpRateLimitRejectedConns
 	}
 
 	return reg
@@ -343,6 +583,10 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		return true
 	}
 
+	if routerName, ok := labels["router"]; ok && !ps.dynamicConfig.hasRouter(routerName) {
+		return true
+	}
+
 	if serviceName, ok := labels["service"]; ok {
 		if !ps.dynamicConfig.hasService(serviceName) {
 			return true
@@ -378,6 +622,11 @@ func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
 	return ok
 }
 
+func (d *dynamicConfig) hasRouter(routerName string) bool {
+	_, ok := d.routers[routerName]
+	return ok
+}
+
 func (d *dynamicConfig) hasService(serviceName string) bool {
 	_, ok := d.services[serviceName]
 	return ok
@@ -541,6 +790,23 @@ func (h *histogram) Observe(value float64) {
 	labels := h.labelNamesValues.ToLabels()
 	observer := h.hv.With(labels)
 	observer.Observe(value)
+	h.collect(labels, observer)
+}
+
+// ObserveWithExemplar observes the value with the given exemplar,
+// which is only exposed with the OpenMetrics format.
+func (h *histogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+	labels := h.labelNamesValues.ToLabels()
+	observer := h.hv.With(labels)
+	if eo, ok := observer.(stdprometheus.ExemplarObserver); ok {
+		eo.ObserveWithExemplar(value, exemplar)
+	} else {
+		observer.Observe(value)
+	}
+	h.collect(labels, observer)
+}
+
+func (h *histogram) collect(labels stdprometheus.Labels, observer stdprometheus.Observer) {
 	// Do a type assertion to be sure that prometheus will be able to call the Collect method.
 	if collector, ok := observer.(stdprometheus.Histogram); ok {
 		h.collectors <- newCollector(h.name, labels, collector, func() {
@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/types"
@@ -104,10 +105,10 @@ func TestPrometheus(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true, AddPathTemplatesLabels: true, AddMiddlewaresLabels: true})
 	defer promRegistry.Unregister(promState)
 
-	if !prometheusRegistry.IsEpEnabled() || !prometheusRegistry.IsSvcEnabled() {
+	if !prometheusRegistry.IsEpEnabled() || !prometheusRegistry.IsSvcEnabled() || !prometheusRegistry.IsPathEnabled() || !prometheusRegistry.IsMiddlewareEnabled() {
 		t.Errorf("PrometheusRegistry should return true for IsEnabled()")
 	}
 
@@ -115,12 +116,22 @@ func TestPrometheus(t *testing.T) {
 	prometheusRegistry.ConfigReloadsFailureCounter().Add(1)
 	prometheusRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 	prometheusRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
+	prometheusRegistry.ProviderLastMessageGauge().With("provider", "file").Set(float64(time.Now().Unix()))
+	prometheusRegistry.ProviderStaleGauge().With("provider", "file").Set(1)
+	prometheusRegistry.ConfigHashGauge().Set(42)
 
 	prometheusRegistry.
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
 
+	prometheusRegistry.AccessLogDroppedLinesCounter().Add(1)
+	prometheusRegistry.AccessLogBufferedLinesGauge().Set(1)
+	prometheusRegistry.GatewayAttachedRoutesGauge().With("namespace", "default", "gateway", "my-gateway", "port", "80").Set(2)
+	prometheusRegistry.ResourceUsageGauge().With("resource", "goroutines").Set(3)
+	prometheusRegistry.ResourceOverloadedGauge().Set(1)
+	prometheusRegistry.ResourceShedCounter().With("entrypoint", "http", "type", "connection").Add(1)
+
 	prometheusRegistry.
 		EntryPointReqsCounter().
 		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
@@ -142,6 +153,14 @@ func TestPrometheus(t *testing.T) {
 		ServiceReqDurationHistogram().
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Observe(10000)
+	prometheusRegistry.
+		ServicePathReqsCounter().
+		With("service", "service1", "path", "/users/{id}", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).
+		Add(1)
+	prometheusRegistry.
+		ServicePathReqDurationHistogram().
+		With("service", "service1", "path", "/users/{id}", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).
+		Observe(10000)
 	prometheusRegistry.
 		ServiceOpenConnsGauge().
 		With("service", "service1", "method", http.MethodGet, "protocol", "http").
@@ -154,6 +173,78 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		RouterExperimentAssignmentsCounter().
+		With("experiment", "experiment1", "variant", "variant1").
+		Add(1)
+	prometheusRegistry.
+		RouterOpenWebSocketsGauge().
+		With("router", "router1").
+		Set(1)
+	prometheusRegistry.
+		RouterOpenUpgradedConnsGauge().
+		With("router", "router1", "protocol", "websocket").
+		Set(1)
+	prometheusRegistry.
+		ServiceUpstreamOpenConnsGauge().
+		With("service", "service1").
+		Set(1)
+	prometheusRegistry.
+		ServiceUpstreamIdleConnsGauge().
+		With("service", "service1").
+		Set(1)
+	prometheusRegistry.
+		ServiceUpstreamActiveConnsGauge().
+		With("service", "service1").
+		Set(1)
+	prometheusRegistry.
+		ServiceUpstreamConnsCounter().
+		With("service", "service1", "reused", "true").
+		Add(1)
+	prometheusRegistry.
+		ServiceUpstreamDNSFailuresCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceProtocolDowngradesCounter().
+		With("service", "service1", "from", "h3", "to", "h2").
+		Add(1)
+	prometheusRegistry.
+		ServiceTCPConnectRetriesCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceCanaryWeightGauge().
+		With("service", "service1", "canary", "canary1").
+		Set(25)
+	prometheusRegistry.
+		ServiceCanaryAbortsCounter().
+		With("service", "service1", "canary", "canary1").
+		Add(1)
+	prometheusRegistry.
+		CircuitBreakerTrippedGauge().
+		With("middleware", "middleware1").
+		Set(1)
+	prometheusRegistry.
+		LimitsViolationsCounter().
+		With("middleware", "middleware1", "limit", "requestBody").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareReqsCounter().
+		With("middleware", "middleware1").
+		Add(1)
+	prometheusRegistry.
+		MiddlewareReqDurationHistogram().
+		With("middleware", "middleware1").
+		Observe(1)
+	prometheusRegistry.
+		MiddlewareShortCircuitsCounter().
+		With("middleware", "middleware1", "code", "429").
+		Add(1)
+	prometheusRegistry.
+		TCPRateLimitRejectedConnsCounter().
+		With("middleware", "middleware1", "reason", "banned").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -180,6 +271,20 @@ func TestPrometheus(t *testing.T) {
 			name:   configLastReloadFailureName,
 			assert: buildTimestampAssert(t, configLastReloadFailureName),
 		},
+		{
+			name:   configProviderLastMessageName,
+			labels: map[string]string{"provider": "file"},
+			assert: buildTimestampAssert(t, configProviderLastMessageName),
+		},
+		{
+			name:   configProviderStaleName,
+			labels: map[string]string{"provider": "file"},
+			assert: buildGaugeAssert(t, configProviderStaleName, 1),
+		},
+		{
+			name:   configHashName,
+			assert: buildGaugeAssert(t, configHashName, 42),
+		},
 		{
 			name: tlsCertsNotAfterTimestamp,
 			labels: map[string]string{
@@ -189,6 +294,40 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestamp),
 		},
+		{
+			name:   accessLogDroppedLinesTotName,
+			assert: buildCounterAssert(t, accessLogDroppedLinesTotName, 1),
+		},
+		{
+			name:   accessLogBufferedLinesName,
+			assert: buildGaugeAssert(t, accessLogBufferedLinesName, 1),
+		},
+		{
+			name: gatewayAttachedRoutesName,
+			labels: map[string]string{
+				"namespace": "default",
+				"gateway":   "my-gateway",
+				"port":      "80",
+			},
+			assert: buildGaugeAssert(t, gatewayAttachedRoutesName, 2),
+		},
+		{
+			name:   resourceUsageName,
+			labels: map[string]string{"resource": "goroutines"},
+			assert: buildGaugeAssert(t, resourceUsageName, 3),
+		},
+		{
+			name:   resourceOverloadedName,
+			assert: buildGaugeAssert(t, resourceOverloadedName, 1),
+		},
+		{
+			name: resourceShedTotalName,
+			labels: map[string]string{
+				"entrypoint": "http",
+				"type":       "connection",
+			},
+			assert: buildCounterAssert(t, resourceShedTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -238,6 +377,26 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildHistogramAssert(t, serviceReqDurationName, 1),
 		},
+		{
+			name: servicePathReqsTotalName,
+			labels: map[string]string{
+				"code":    "200",
+				"method":  http.MethodGet,
+				"path":    "/users/{id}",
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, servicePathReqsTotalName, 1),
+		},
+		{
+			name: servicePathReqDurationName,
+			labels: map[string]string{
+				"code":    "200",
+				"method":  http.MethodGet,
+				"path":    "/users/{id}",
+				"service": "service1",
+			},
+			assert: buildHistogramAssert(t, servicePathReqDurationName, 1),
+		},
 		{
 			name: serviceOpenConnsName,
 			labels: map[string]string{
@@ -262,6 +421,142 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: routerExperimentAssignmentsTotalName,
+			labels: map[string]string{
+				"experiment": "experiment1",
+				"variant":    "variant1",
+			},
+			assert: buildCounterAssert(t, routerExperimentAssignmentsTotalName, 1),
+		},
+		{
+			name: routerOpenWebSocketsName,
+			labels: map[string]string{
+				"router": "router1",
+			},
+			assert: buildGaugeAssert(t, routerOpenWebSocketsName, 1),
+		},
+		{
+			name: routerOpenUpgradedConnsName,
+			labels: map[string]string{
+				"router":   "router1",
+				"protocol": "websocket",
+			},
+			assert: buildGaugeAssert(t, routerOpenUpgradedConnsName, 1),
+		},
+		{
+			name: serviceUpstreamOpenConnsName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGaugeAssert(t, serviceUpstreamOpenConnsName, 1),
+		},
+		{
+			name: serviceUpstreamIdleConnsName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGaugeAssert(t, serviceUpstreamIdleConnsName, 1),
+		},
+		{
+			name: serviceUpstreamActiveConnsName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGaugeAssert(t, serviceUpstreamActiveConnsName, 1),
+		},
+		{
+			name: serviceUpstreamConnsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"reused":  "true",
+			},
+			assert: buildCounterAssert(t, serviceUpstreamConnsTotalName, 1),
+		},
+		{
+			name: serviceUpstreamDNSFailuresTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceUpstreamDNSFailuresTotalName, 1),
+		},
+		{
+			name: serviceProtocolDowngradesTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"from":    "h3",
+				"to":      "h2",
+			},
+			assert: buildCounterAssert(t, serviceProtocolDowngradesTotalName, 1),
+		},
+		{
+			name: serviceTCPConnectRetriesTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceTCPConnectRetriesTotalName, 1),
+		},
+		{
+			name: serviceCanaryWeightName,
+			labels: map[string]string{
+				"service": "service1",
+				"canary":  "canary1",
+			},
+			assert: buildGaugeAssert(t, serviceCanaryWeightName, 25),
+		},
+		{
+			name: serviceCanaryAbortsTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"canary":  "canary1",
+			},
+			assert: buildCounterAssert(t, serviceCanaryAbortsTotalName, 1),
+		},
+		{
+			name: circuitBreakerTrippedName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+			},
+			assert: buildGaugeAssert(t, circuitBreakerTrippedName, 1),
+		},
+		{
+			name: limitsViolationsTotalName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+				"limit":      "requestBody",
+			},
+			assert: buildCounterAssert(t, limitsViolationsTotalName, 1),
+		},
+		{
+			name: middlewareReqsTotalName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+			},
+			assert: buildCounterAssert(t, middlewareReqsTotalName, 1),
+		},
+		{
+			name: middlewareReqDurationName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+			},
+			assert: buildHistogramAssert(t, middlewareReqDurationName, 1),
+		},
+		{
+			name: middlewareShortCircuitsTotName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+				"code":       "429",
+			},
+			assert: buildCounterAssert(t, middlewareShortCircuitsTotName, 1),
+		},
+		{
+			name: tcpRateLimitRejectedConnsName,
+			labels: map[string]string{
+				"middleware": "middleware1",
+				"reason":     "banned",
+			},
+			assert: buildCounterAssert(t, tcpRateLimitRejectedConnsName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -386,6 +681,45 @@ func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	assertCounterValue(t, 1, findMetricFamily(serviceReqsTotalName, metricsFamilies), labelNamesValues...)
 }
 
+func TestPrometheusExemplar(t *testing.T) {
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	labelNamesValues := []string{
+		"service", "service",
+		"code", strconv.Itoa(http.StatusOK),
+		"method", http.MethodGet,
+		"protocol", "http",
+	}
+	prometheusRegistry.
+		ServiceReqDurationHistogram().
+		With(labelNamesValues...).
+		ObserveFromStartWithTraceID(time.Now(), "0af7651916cd43dd8448eb211c80319c")
+
+	delayForTrackingCompletion()
+
+	family := findMetricFamily(serviceReqDurationName, mustScrape())
+	require.NotNil(t, family)
+
+	metric := findMetricByLabelNamesValues(family, labelNamesValues...)
+	require.NotNil(t, metric)
+
+	var exemplar *dto.Exemplar
+	for _, bucket := range metric.Histogram.Bucket {
+		if bucket.Exemplar != nil {
+			exemplar = bucket.Exemplar
+		}
+	}
+	require.NotNil(t, exemplar)
+
+	require.Len(t, exemplar.Label, 1)
+	assert.Equal(t, "trace_id", exemplar.Label[0].GetName())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", exemplar.Label[0].GetValue())
+}
+
 // Tracking and gathering the metrics happens concurrently.
 // In practice this is no problem, because in case a tracked metric would miss
 // the current scrape, it would just be there in the next one.
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// ratesWindow is the number of seconds over which the request rates are averaged.
+const ratesWindow = 10
+
+// Rate is a live request rate, in requests per second.
+type Rate struct {
+	Requests float64 `json:"requests"`
+	// Errors is the rate of the requests answered with a server error (5xx).
+	Errors float64 `json:"errors"`
+}
+
+// RatesRegistry is an in-memory registry tracking the live request rates of the entry points and the services.
+type RatesRegistry struct {
+	*standardRegistry
+
+	entryPoints *rateCounter
+	services    *rateCounter
+}
+
+// RegisterRates registers the request rates metrics.
+func RegisterRates() *RatesRegistry {
+	rr := &RatesRegistry{
+		entryPoints: newRateCounter("entrypoint"),
+		services:    newRateCounter("service"),
+	}
+
+	rr.standardRegistry = &standardRegistry{
+		epEnabled:             true,
+		svcEnabled:            true,
+		entryPointReqsCounter: rr.entryPoints,
+		serviceReqsCounter:    rr.services,
+	}
+
+	return rr
+}
+
+// EntryPointRates returns the request rates of the entry points, by name.
+func (rr *RatesRegistry) EntryPointRates() map[string]Rate {
+	return rr.entryPoints.rates(time.Now())
+}
+
+// ServiceRates returns the request rates of the services, by name.
+func (rr *RatesRegistry) ServiceRates() map[string]Rate {
+	return rr.services.rates(time.Now())
+}
+
+// rateCounter is a counter recording the requests in one second buckets,
+// keyed by the value of its name label.
+type rateCounter struct {
+	nameLabel   string
+	labelValues []string
+	windows     *rateWindows
+}
+
+type rateWindows struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+func newRateCounter(nameLabel string) *rateCounter {
+	return &rateCounter{
+		nameLabel: nameLabel,
+		windows:   &rateWindows{windows: make(map[string]*rateWindow)},
+	}
+}
+
+// With returns a new rateCounter with the given labels.
+func (c *rateCounter) With(labelValues ...string) metrics.Counter {
+	return &rateCounter{
+		nameLabel:   c.nameLabel,
+		labelValues: append(append([]string{}, c.labelValues...), labelValues...),
+		windows:     c.windows,
+	}
+}
+
+// Add records the given number of requests.
+func (c *rateCounter) Add(delta float64) {
+	var name string
+	var serverError bool
+	for i := 0; i+1 < len(c.labelValues); i += 2 {
+		switch c.labelValues[i] {
+		case c.nameLabel:
+			name = c.labelValues[i+1]
+		case "code":
+			code, _ := strconv.Atoi(c.labelValues[i+1])
+			serverError = code >= 500
+		}
+	}
+
+	if name == "" {
+		return
+	}
+
+	c.windows.add(name, delta, serverError, time.Now())
+}
+
+func (w *rateWindows) add(name string, delta float64, serverError bool, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	window, ok := w.windows[name]
+	if !ok {
+		window = &rateWindow{}
+		w.windows[name] = window
+	}
+
+	window.add(delta, serverError, now)
+}
+
+func (c *rateCounter) rates(now time.Time) map[string]Rate {
+	c.windows.mu.Lock()
+	defer c.windows.mu.Unlock()
+
+	rates := make(map[string]Rate, len(c.windows.windows))
+	for name, window := range c.windows.windows {
+		rate, ok := window.rate(now)
+		if !ok {
+			// Forgets the entry points and services which did not receive requests recently.
+			delete(c.windows.windows, name)
+			continue
+		}
+
+		rates[name] = rate
+	}
+
+	return rates
+}
+
+type rateBucket struct {
+	second   int64
+	requests float64
+	errors   float64
+}
+
+// rateWindow is a ring of one second buckets, covering the rates window.
+type rateWindow struct {
+	buckets [ratesWindow]rateBucket
+}
+
+func (w *rateWindow) add(delta float64, serverError bool, now time.Time) {
+	second := now.Unix()
+
+	bucket := &w.buckets[second%ratesWindow]
+	if bucket.second != second {
+		*bucket = rateBucket{second: second}
+	}
+
+	bucket.requests += delta
+	if serverError {
+		bucket.errors += delta
+	}
+}
+
+// rate returns the average rate over the window, and whether requests were recorded during the window.
+func (w *rateWindow) rate(now time.Time) (Rate, bool) {
+	second := now.Unix()
+
+	var rate Rate
+	var recorded bool
+	for _, bucket := range w.buckets {
+		if bucket.second <= second-ratesWindow || bucket.second > second {
+			continue
+		}
+
+		recorded = true
+		rate.Requests += bucket.requests
+		rate.Errors += bucket.errors
+	}
+
+	rate.Requests /= ratesWindow
+	rate.Errors /= ratesWindow
+
+	return rate, recorded
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatesRegistry(t *testing.T) {
+	registry := RegisterRates()
+
+	assert.True(t, registry.IsEpEnabled())
+	assert.True(t, registry.IsSvcEnabled())
+
+	for i := 0; i < 20; i++ {
+		registry.EntryPointReqsCounter().With("entrypoint", "web", "method", "GET").With("code", "200").Add(1)
+	}
+	registry.EntryPointReqsCounter().With("entrypoint", "web", "method", "GET", "code", "502").Add(1)
+	registry.ServiceReqsCounter().With("service", "whoami@docker", "code", "503").Add(1)
+
+	// Without the name label, the requests are not recorded.
+	registry.ServiceReqsCounter().With("code", "200").Add(1)
+
+	assert.Equal(t, map[string]Rate{"web": {Requests: 2.1, Errors: 0.1}}, registry.EntryPointRates())
+	assert.Equal(t, map[string]Rate{"whoami@docker": {Requests: 0.1, Errors: 0.1}}, registry.ServiceRates())
+}
+
+func TestRateCounter_window(t *testing.T) {
+	counter := newRateCounter("service")
+
+	start := time.Unix(1000, 0)
+
+	counter.windows.add("foo", 5, false, start)
+	counter.windows.add("foo", 5, true, start.Add(time.Second))
+	counter.windows.add("bar", 10, false, start.Add(time.Second))
+
+	assert.Equal(t, map[string]Rate{
+		"foo": {Requests: 1, Errors: 0.5},
+		"bar": {Requests: 1},
+	}, counter.rates(start.Add(2*time.Second)))
+
+	// The first bucket of foo leaves the window.
+	assert.Equal(t, map[string]Rate{
+		"foo": {Requests: 0.5, Errors: 0.5},
+		"bar": {Requests: 1},
+	}, counter.rates(start.Add(ratesWindow*time.Second)))
+
+	// The bucket is reused for the same second of the next window.
+	counter.windows.add("foo", 2, false, start.Add(ratesWindow*time.Second))
+
+	assert.Equal(t, map[string]Rate{
+		"foo": {Requests: 0.7, Errors: 0.5},
+		"bar": {Requests: 1},
+	}, counter.rates(start.Add(ratesWindow*time.Second)))
+
+	// The names without requests during the window are forgotten.
+	assert.Equal(t, map[string]Rate{
+		"foo": {Requests: 0.2},
+	}, counter.rates(start.Add((ratesWindow+2)*time.Second)))
+
+	assert.Len(t, counter.windows.windows, 1)
+}
@@ -19,17 +19,46 @@ var (
 const (
 	statsdMetricsServiceReqsName        = "service.request.total"
 	statsdMetricsServiceLatencyName     = "service.request.duration"
+	statsdMetricsServicePathReqsName    = "service.path.request.total"
+	statsdMetricsServicePathLatencyName = "service.path.request.duration"
 	statsdRetriesTotalName              = "service.retries.total"
 	statsdConfigReloadsName             = "config.reload.total"
 	statsdConfigReloadsFailureName      = statsdConfigReloadsName + ".failure"
 	statsdLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
 	statsdLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
+	statsdProviderLastMessageName       = "config.provider.lastMessageTimestamp"
+	statsdProviderStaleName             = "config.provider.stale"
+	statsdConfigHashName                = "config.hash"
 	statsdEntryPointReqsName            = "entrypoint.request.total"
 	statsdEntryPointReqDurationName     = "entrypoint.request.duration"
 	statsdEntryPointOpenConnsName       = "entrypoint.connections.open"
 	statsdOpenConnsName                 = "service.connections.open"
 	statsdServerUpName                  = "service.server.up"
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdAccessLogDroppedLinesName     = "accesslog.lines.dropped.total"
+	statsdAccessLogBufferedLinesName    = "accesslog.lines.buffered"
+	statsdGatewayAttachedRoutesName     = "gateway.listener.attachedRoutes"
+	statsdResourceUsageName             = "resource.usage"
+	statsdResourceOverloadedName        = "resource.overloaded"
+	statsdResourceShedName              = "resource.shed.total"
+	statsdExperimentAssignmentsName     = "router.experiment.assignments.total"
+	statsdRouterOpenWebSocketsName      = "router.websockets.open"
+	statsdRouterOpenUpgradedConnsName   = "router.upgraded.connections.open"
+	statsdUpstreamOpenConnsName         = "service.upstream.connections.open"
+	statsdUpstreamIdleConnsName         = "service.upstream.connections.idle"
+	statsdUpstreamActiveConnsName       = "service.upstream.connections.active"
+	statsdUpstreamConnsName             = "service.upstream.connections.total"
+	statsdUpstreamDNSFailuresName       = "service.upstream.dns.failures.total"
+	statsdProtocolDowngradesName        = "service.protocol.downgrades.total"
+	statsdTCPConnectRetriesName         = "service.tcp.connect.retries.total"
+	statsdCanaryWeightName              = "service.canary.weight"
+	statsdCanaryAbortsName              = "service.canary.aborts.total"
+	statsdCircuitBreakerTrippedName     = "middleware.circuitbreaker.tripped"
+	statsdLimitsViolationsName          = "middleware.limits.violations.total"
+	statsdMiddlewareReqsName            = "middleware.request.total"
+	statsdMiddlewareLatencyName         = "middleware.request.duration"
+	statsdMiddlewareShortCircuitsName   = "middleware.shortcircuits.total"
+	statsdTCPRateLimitRejectedConnsName = "middleware.tcp.ratelimit.rejected.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -53,7 +82,16 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		configReloadsFailureCounter:    statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
 		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		providerLastMessageGauge:       statsdClient.NewGauge(statsdProviderLastMessageName),
+		providerStaleGauge:             statsdClient.NewGauge(statsdProviderStaleName),
+		configHashGauge:                statsdClient.NewGauge(statsdConfigHashName),
 		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		accessLogDroppedLinesCounter:   statsdClient.NewCounter(statsdAccessLogDroppedLinesName, 1.0),
+		accessLogBufferedLinesGauge:    statsdClient.NewGauge(statsdAccessLogBufferedLinesName),
+		gatewayAttachedRoutesGauge:     statsdClient.NewGauge(statsdGatewayAttachedRoutesName),
+		resourceUsageGauge:             statsdClient.NewGauge(statsdResourceUsageName),
+		resourceOverloadedGauge:        statsdClient.NewGauge(statsdResourceOverloadedName),
+		resourceShedCounter:            statsdClient.NewCounter(statsdResourceShedName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -61,6 +99,7 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsCounter = statsdClient.NewCounter(statsdEntryPointReqsName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0), time.Millisecond)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.routerExperimentAssignmentsCounter = statsdClient.NewCounter(statsdExperimentAssignmentsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -70,6 +109,33 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServerUpName)
+		registry.routerOpenWebSocketsGauge = statsdClient.NewGauge(statsdRouterOpenWebSocketsName)
+		registry.routerOpenUpgradedConnsGauge = statsdClient.NewGauge(statsdRouterOpenUpgradedConnsName)
+		registry.serviceUpstreamOpenConnsGauge = statsdClient.NewGauge(statsdUpstreamOpenConnsName)
+		registry.serviceUpstreamIdleConnsGauge = statsdClient.NewGauge(statsdUpstreamIdleConnsName)
+		registry.serviceUpstreamActiveConnsGauge = statsdClient.NewGauge(statsdUpstreamActiveConnsName)
+		registry.serviceUpstreamConnsCounter = statsdClient.NewCounter(statsdUpstreamConnsName, 1.0)
+		registry.serviceUpstreamDNSFailuresCounter = statsdClient.NewCounter(statsdUpstreamDNSFailuresName, 1.0)
+		registry.serviceProtocolDowngradesCounter = statsdClient.NewCounter(statsdProtocolDowngradesName, 1.0)
+		registry.serviceTCPConnectRetriesCounter = statsdClient.NewCounter(statsdTCPConnectRetriesName, 1.0)
+		registry.serviceCanaryWeightGauge = statsdClient.NewGauge(statsdCanaryWeightName)
+		registry.serviceCanaryAbortsCounter = statsdClient.NewCounter(statsdCanaryAbortsName, 1.0)
+		registry.circuitBreakerTrippedGauge = statsdClient.NewGauge(statsdCircuitBreakerTrippedName)
+		registry.limitsViolationsCounter = statsdClient.NewCounter(statsdLimitsViolationsName, 1.0)
+	}
+
+	if config.AddServicesLabels && config.AddPathTemplatesLabels {
+		registry.pathEnabled = true
+		registry.servicePathReqsCounter = statsdClient.NewCounter(statsdMetricsServicePathReqsName, 1.0)
+		registry.servicePathReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdMetricsServicePathLatencyName, 1.0), time.Millisecond)
+	}
+
+	if config.AddMiddlewaresLabels {
+		registry.middlewareEnabled = true
+		registry.middlewareReqsCounter = statsdClient.NewCounter(statsdMiddlewareReqsName, 1.0)
+		registry.middlewareReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdMiddlewareLatencyName, 1.0), time.Millisecond)
+		registry.middlewareShortCircuitsCounter = statsdClient.NewCounter(statsdMiddlewareShortCircuitsName, 1.0)
+		registry.tcpRateLimitRejectedConnsCounter = statsdClient.NewCounter(statsdTCPRateLimitRejectedConnsName, 1.0)
 	}
 
 	return registry
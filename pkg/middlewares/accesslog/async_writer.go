@@ -0,0 +1,215 @@
+package accesslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// asyncWriter writes the log lines to the output from its own goroutine,
+// so that a slow output does not stall the request handling.
+// The lines are held in a bounded in-memory queue, and in an optional disk buffer when the queue is full.
+// When both are full, the lines are either dropped or the writes are blocked, depending on the overflow policy.
+type asyncWriter struct {
+	mu             sync.Mutex
+	cond           *sync.Cond
+	queue          [][]byte
+	queueSize      int
+	ring           *ringBuffer
+	dropOnOverflow bool
+	closed         bool
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	droppedLines  gokitmetrics.Counter
+	bufferedLines gokitmetrics.Gauge
+
+	done chan struct{}
+}
+
+func newAsyncWriter(out io.Writer, config *types.AccessLogWriter, metricsRegistry metrics.Registry) (*asyncWriter, error) {
+	if config.QueueSize <= 0 {
+		return nil, fmt.Errorf("queue size must be positive: %d", config.QueueSize)
+	}
+
+	w := &asyncWriter{
+		queueSize:     config.QueueSize,
+		out:           out,
+		droppedLines:  metricsRegistry.AccessLogDroppedLinesCounter(),
+		bufferedLines: metricsRegistry.AccessLogBufferedLinesGauge(),
+		done:          make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	switch config.OverflowPolicy {
+	case "", types.AccessLogOverflowBlock:
+	case types.AccessLogOverflowDrop:
+		w.dropOnOverflow = true
+	default:
+		return nil, fmt.Errorf("unsupported overflow policy: %q", config.OverflowPolicy)
+	}
+
+	if config.DiskBuffer != nil {
+		var err error
+		w.ring, err = newRingBuffer(config.DiskBuffer.FilePath, config.DiskBuffer.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Write queues a copy of the line, as the given slice may be reused by the caller.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		if w.closed {
+			return 0, errors.New("access log writer is closed")
+		}
+
+		queued, err := w.enqueue(line)
+		if err != nil {
+			log.WithoutContext().Errorf("Error buffering access log line: %v", err)
+		}
+
+		if queued {
+			w.cond.Broadcast()
+			w.updateBufferedLines()
+			return len(p), nil
+		}
+
+		if w.dropOnOverflow {
+			w.droppedLines.Add(1)
+			return len(p), nil
+		}
+
+		w.cond.Wait()
+	}
+}
+
+// enqueue adds the line to the queue, or to the disk buffer when the queue is full.
+// To keep the lines ordered, they go to the disk buffer as long as it is not empty.
+func (w *asyncWriter) enqueue(line []byte) (bool, error) {
+	if w.ringLen() == 0 && len(w.queue) < w.queueSize {
+		w.queue = append(w.queue, line)
+		return true, nil
+	}
+
+	if w.ring == nil {
+		return false, nil
+	}
+
+	return w.ring.push(line)
+}
+
+// dequeue removes the oldest lines, starting with the whole queue as it is older than the disk buffer.
+func (w *asyncWriter) dequeue() ([][]byte, error) {
+	if len(w.queue) > 0 {
+		lines := w.queue
+		w.queue = make([][]byte, 0, len(lines))
+		return lines, nil
+	}
+
+	line, err := w.ring.pop()
+	if err != nil {
+		// The disk buffer cannot be trusted anymore, its lines are lost.
+		w.droppedLines.Add(float64(w.ring.len()))
+		w.ring.reset()
+		return nil, err
+	}
+
+	return [][]byte{line}, nil
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+
+	var buf bytes.Buffer
+
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.ringLen() == 0 && !w.closed {
+			w.cond.Wait()
+		}
+
+		if len(w.queue) == 0 && w.ringLen() == 0 {
+			w.mu.Unlock()
+			return
+		}
+
+		lines, err := w.dequeue()
+		w.cond.Broadcast()
+		w.updateBufferedLines()
+		w.mu.Unlock()
+
+		if err != nil {
+			log.WithoutContext().Errorf("Error reading access log disk buffer: %v", err)
+			continue
+		}
+
+		buf.Reset()
+		for _, line := range lines {
+			buf.Write(line)
+		}
+
+		w.outMu.Lock()
+		_, err = w.out.Write(buf.Bytes())
+		w.outMu.Unlock()
+
+		if err != nil {
+			log.WithoutContext().Errorf("Error writing access log lines: %v", err)
+		}
+	}
+}
+
+func (w *asyncWriter) ringLen() int {
+	if w.ring == nil {
+		return 0
+	}
+
+	return w.ring.len()
+}
+
+func (w *asyncWriter) updateBufferedLines() {
+	w.bufferedLines.Set(float64(len(w.queue) + w.ringLen()))
+}
+
+// setOutput replaces the output the lines are written to.
+func (w *asyncWriter) setOutput(out io.Writer) {
+	w.outMu.Lock()
+	defer w.outMu.Unlock()
+
+	w.out = out
+}
+
+// Close writes the remaining lines, and stops the writer.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+
+	if w.ring != nil {
+		return w.ring.close()
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestAsyncWriter_overflow(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		policy          string
+		diskBuffer      bool
+		expectedLines   int
+		expectedDropped float64
+	}{
+		{
+			desc:            "drop",
+			policy:          types.AccessLogOverflowDrop,
+			expectedLines:   3,
+			expectedDropped: 8,
+		},
+		{
+			desc:            "drop with disk buffer",
+			policy:          types.AccessLogOverflowDrop,
+			diskBuffer:      true,
+			expectedLines:   6,
+			expectedDropped: 5,
+		},
+		{
+			desc:          "block",
+			policy:        types.AccessLogOverflowBlock,
+			expectedLines: 11,
+		},
+		{
+			desc:          "block with disk buffer",
+			policy:        types.AccessLogOverflowBlock,
+			diskBuffer:    true,
+			expectedLines: 11,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			out := newBlockingWriter()
+			registry := &testAccessLogRegistry{Registry: metrics.NewVoidRegistry(), dropped: generic.NewCounter("dropped")}
+
+			config := &types.AccessLogWriter{QueueSize: 2, OverflowPolicy: test.policy}
+			if test.diskBuffer {
+				// Each line takes 12 bytes in the disk buffer: its length and its content.
+				config.DiskBuffer = &types.AccessLogDiskBuffer{
+					FilePath: filepath.Join(t.TempDir(), "buffer"),
+					MaxSize:  36,
+				}
+			}
+
+			writer, err := newAsyncWriter(out, config, registry)
+			require.NoError(t, err)
+
+			// Blocks the writer goroutine on the first line, to fill the queue and the disk buffer.
+			_, err = writer.Write([]byte("line-00\n"))
+			require.NoError(t, err)
+			<-out.writing
+
+			written := make(chan struct{})
+			go func() {
+				defer close(written)
+
+				for i := 1; i <= 10; i++ {
+					_, err := fmt.Fprintf(writer, "line-%02d\n", i)
+					assert.NoError(t, err)
+				}
+			}()
+
+			if test.policy == types.AccessLogOverflowBlock {
+				select {
+				case <-written:
+					t.Fatal("writes should be blocked")
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+
+			close(out.release)
+			<-written
+
+			require.NoError(t, writer.Close())
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			assert.Equal(t, test.expectedLines, len(lines))
+			assert.Equal(t, test.expectedDropped, registry.dropped.Value())
+
+			// The kept lines are written in order.
+			for i := 1; i < len(lines); i++ {
+				assert.True(t, lines[i-1] < lines[i], "%s is written before %s", lines[i-1], lines[i])
+			}
+		})
+	}
+}
+
+func TestAsyncWriter_invalidConfig(t *testing.T) {
+	_, err := newAsyncWriter(ioutil.Discard, &types.AccessLogWriter{QueueSize: 0}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+
+	_, err = newAsyncWriter(ioutil.Discard, &types.AccessLogWriter{QueueSize: 1, OverflowPolicy: "foo"}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+
+	_, err = newAsyncWriter(ioutil.Discard, &types.AccessLogWriter{
+		QueueSize:  1,
+		DiskBuffer: &types.AccessLogDiskBuffer{FilePath: filepath.Join(t.TempDir(), "buffer"), MaxSize: 1},
+	}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
+func TestRingBuffer(t *testing.T) {
+	ring, err := newRingBuffer(filepath.Join(t.TempDir(), "buffer"), 20)
+	require.NoError(t, err)
+
+	for _, line := range []string{"aaaa", "bbbb"} {
+		pushed, err := ring.push([]byte(line))
+		require.NoError(t, err)
+		require.True(t, pushed)
+	}
+
+	line, err := ring.pop()
+	require.NoError(t, err)
+	assert.Equal(t, "aaaa", string(line))
+
+	// Wraps around the end of the file.
+	pushed, err := ring.push([]byte("cccc"))
+	require.NoError(t, err)
+	require.True(t, pushed)
+
+	pushed, err = ring.push([]byte("dddd"))
+	require.NoError(t, err)
+	assert.False(t, pushed)
+
+	assert.Equal(t, 2, ring.len())
+
+	for _, expected := range []string{"bbbb", "cccc"} {
+		line, err = ring.pop()
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(line))
+	}
+
+	_, err = ring.pop()
+	assert.Error(t, err)
+
+	require.NoError(t, ring.close())
+
+	_, err = os.Stat(ring.file.Name())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestHandler_asyncWriter(t *testing.T) {
+	dir := t.TempDir()
+
+	config := &types.AccessLog{
+		FilePath: filepath.Join(dir, "access.log"),
+		Format:   CommonFormat,
+		AsyncWriter: &types.AccessLogWriter{
+			QueueSize:      1,
+			OverflowPolicy: types.AccessLogOverflowBlock,
+			DiskBuffer: &types.AccessLogDiskBuffer{
+				FilePath: filepath.Join(dir, "buffer"),
+				MaxSize:  1024,
+			},
+		},
+	}
+
+	logger, err := NewHandler(config, nil)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost/%d", i), nil)
+		logger.ServeHTTP(httptest.NewRecorder(), req, next)
+	}
+
+	require.NoError(t, logger.Close())
+
+	content, err := ioutil.ReadFile(config.FilePath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Equal(t, 5, len(lines))
+
+	for i, line := range lines {
+		assert.Contains(t, line, fmt.Sprintf("GET /%d HTTP/1.1", i))
+	}
+
+	_, err = os.Stat(config.AsyncWriter.DiskBuffer.FilePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+type testAccessLogRegistry struct {
+	metrics.Registry
+
+	dropped *generic.Counter
+}
+
+func (r *testAccessLogRegistry) AccessLogDroppedLinesCounter() gokitmetrics.Counter {
+	return r.dropped
+}
+
+// blockingWriter blocks all the writes until it is released.
+type blockingWriter struct {
+	writing chan struct{}
+	release chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{
+		writing: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (b *blockingWriter) Write(p []byte) (int, error) {
+	b.once.Do(func() { close(b.writing) })
+
+	<-b.release
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *blockingWriter) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
@@ -40,6 +40,8 @@ const (
 	RequestMethod = "RequestMethod"
 	// RequestPath is the map key used for the HTTP request URI, not including the scheme, host or port.
 	RequestPath = "RequestPath"
+	// RequestPathTemplate is the map key used for the path template matched by the HTTP request URI, such as /users/{id}.
+	RequestPathTemplate = "RequestPathTemplate"
 	// RequestProtocol is the map key used for the version of HTTP requested.
 	RequestProtocol = "RequestProtocol"
 	// RequestScheme is the map key used for the HTTP request scheme.
@@ -70,11 +72,19 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// RequestID is the map key used for the ID of the request, when the request IDs are enabled on the entry point.
+	RequestID = "RequestID"
+	// UpstreamOverride is the map key used for the URL of the server forced by the upstream override header.
+	UpstreamOverride = "UpstreamOverride"
 
 	// TLSVersion is the version of TLS used in the request.
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+	// TLSServerName is the server name (SNI) sent by the client in the TLS handshake.
+	TLSServerName = "TLSServerName"
+	// TLSClientCertFingerprint is the SHA-256 fingerprint of the certificate sent by the client in the TLS handshake.
+	TLSClientCertFingerprint = "TLSClientCertFingerprint"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -116,8 +126,13 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[RequestID] = struct{}{}
+	allCoreKeys[UpstreamOverride] = struct{}{}
+	allCoreKeys[RequestPathTemplate] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[TLSServerName] = struct{}{}
+	allCoreKeys[TLSClientCertFingerprint] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -129,6 +144,8 @@ type LogData struct {
 	Request            request
 	OriginResponse     http.Header
 	DownstreamResponse downstreamResponse
+
+	router *routerConfig
 }
 
 type downstreamResponse struct {
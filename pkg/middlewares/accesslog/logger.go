@@ -17,7 +17,9 @@ import (
 	"github.com/containous/alice"
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -57,6 +59,7 @@ type Handler struct {
 	config         *types.AccessLog
 	logger         *logrus.Logger
 	file           io.WriteCloser
+	asyncWriter    *asyncWriter
 	mu             sync.Mutex
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
@@ -73,7 +76,7 @@ func WrapHandler(handler *Handler) alice.Constructor {
 }
 
 // NewHandler creates a new Handler.
-func NewHandler(config *types.AccessLog) (*Handler, error) {
+func NewHandler(config *types.AccessLog, metricsRegistry metrics.Registry) (*Handler, error) {
 	var file io.WriteCloser = noopCloser{os.Stdout}
 	if len(config.FilePath) > 0 {
 		f, err := openAccessLogFile(config.FilePath)
@@ -82,6 +85,24 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		}
 		file = f
 	}
+
+	var out io.Writer = file
+
+	var writer *asyncWriter
+	if config.AsyncWriter != nil {
+		if metricsRegistry == nil {
+			metricsRegistry = metrics.NewVoidRegistry()
+		}
+
+		var err error
+		writer, err = newAsyncWriter(file, config.AsyncWriter, metricsRegistry)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("error creating access log async writer: %w", err)
+		}
+		out = writer
+	}
+
 	logHandlerChan := make(chan handlerParams, config.BufferingSize)
 
 	var formatter logrus.Formatter
@@ -97,27 +118,19 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	}
 
 	logger := &logrus.Logger{
-		Out:       file,
+		Out:       out,
 		Formatter: formatter,
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
 	}
 
-	// Transform headers names in config to a canonical form, to be used as is without further transformations.
-	if config.Fields != nil && config.Fields.Headers != nil && len(config.Fields.Headers.Names) > 0 {
-		fields := map[string]string{}
-
-		for h, v := range config.Fields.Headers.Names {
-			fields[textproto.CanonicalMIMEHeaderKey(h)] = v
-		}
-
-		config.Fields.Headers.Names = fields
-	}
+	canonicalizeHeaderNames(config.Fields)
 
 	logHandler := &Handler{
 		config:         config,
 		logger:         logger,
 		file:           file,
+		asyncWriter:    writer,
 		logHandlerChan: logHandlerChan,
 	}
 
@@ -142,6 +155,22 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	return logHandler, nil
 }
 
+// canonicalizeHeaderNames transforms the headers names in config to a canonical form,
+// to be used as is without further transformations.
+func canonicalizeHeaderNames(config *types.AccessLogFields) {
+	if config == nil || config.Headers == nil || len(config.Headers.Names) == 0 {
+		return
+	}
+
+	fields := map[string]string{}
+
+	for h, v := range config.Headers.Names {
+		fields[textproto.CanonicalMIMEHeaderKey(h)] = v
+	}
+
+	config.Headers.Names = fields
+}
+
 func openAccessLogFile(filePath string) (*os.File, error) {
 	dir := filepath.Dir(filePath)
 
@@ -212,12 +241,22 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[RequestScheme] = "https"
 		core[TLSVersion] = traefiktls.GetVersion(req.TLS)
 		core[TLSCipher] = traefiktls.GetCipherName(req.TLS)
+
+		if req.TLS.ServerName != "" {
+			core[TLSServerName] = req.TLS.ServerName
+		}
+
+		if fingerprint := traefiktls.GetClientCertFingerprint(req.TLS); fingerprint != "" {
+			core[TLSClientCertFingerprint] = fingerprint
+		}
 	}
 
 	core[ClientAddr] = req.RemoteAddr
 	core[ClientHost], core[ClientPort] = silentSplitHostPort(req.RemoteAddr)
 
-	if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+	if clientIP := ip.GetClientIP(req.Context()); clientIP != "" {
+		core[ClientHost] = clientIP
+	} else if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
 		core[ClientHost] = forwardedFor
 	}
 
@@ -251,6 +290,13 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 func (h *Handler) Close() error {
 	close(h.logHandlerChan)
 	h.wg.Wait()
+
+	if h.asyncWriter != nil {
+		if err := h.asyncWriter.Close(); err != nil {
+			log.WithoutContext().Errorf("Error closing access log async writer: %v", err)
+		}
+	}
+
 	return h.file.Close()
 }
 
@@ -269,6 +315,11 @@ func (h *Handler) Rotate() error {
 	if err != nil {
 		return err
 	}
+	if h.asyncWriter != nil {
+		h.asyncWriter.setOutput(h.file)
+		return nil
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.logger.Out = h.file
@@ -310,7 +361,7 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 	totalDuration := time.Now().UTC().Sub(core[StartUTC].(time.Time))
 	core[Duration] = totalDuration
 
-	if h.keepAccessLog(status, retryAttempts, totalDuration) {
+	if h.keepAccessLog(status, retryAttempts, totalDuration) && logDataTable.router.keep(status) {
 		size := logDataTable.DownstreamResponse.size
 		core[DownstreamContentSize] = size
 		if original, ok := core[OriginContentSize]; ok {
@@ -325,17 +376,22 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 			core[Overhead] = totalDuration - origin.(time.Duration)
 		}
 
+		fieldsConfig := h.config.Fields
+		if logDataTable.router != nil && logDataTable.router.fields != nil {
+			fieldsConfig = logDataTable.router.fields
+		}
+
 		fields := logrus.Fields{}
 
 		for k, v := range logDataTable.Core {
-			if h.config.Fields.Keep(k) {
+			if fieldsConfig.Keep(k) {
 				fields[k] = v
 			}
 		}
 
-		h.redactHeaders(logDataTable.Request.headers, fields, "request_")
-		h.redactHeaders(logDataTable.OriginResponse, fields, "origin_")
-		h.redactHeaders(logDataTable.DownstreamResponse.headers, fields, "downstream_")
+		redactHeaders(fieldsConfig, logDataTable.Request.headers, fields, "request_")
+		redactHeaders(fieldsConfig, logDataTable.OriginResponse, fields, "origin_")
+		redactHeaders(fieldsConfig, logDataTable.DownstreamResponse.headers, fields, "downstream_")
 
 		h.mu.Lock()
 		defer h.mu.Unlock()
@@ -343,17 +399,6 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 	}
 }
 
-func (h *Handler) redactHeaders(headers http.Header, fields logrus.Fields, prefix string) {
-	for k := range headers {
-		v := h.config.Fields.KeepHeader(k)
-		if v == types.AccessLogKeep {
-			fields[prefix+k] = headers.Get(k)
-		} else if v == types.AccessLogRedact {
-			fields[prefix+k] = "REDACTED"
-		}
-	}
-}
-
 func (h *Handler) keepAccessLog(statusCode, retryAttempts int, duration time.Duration) bool {
 	if h.config.Filters == nil {
 		// no filters were specified
@@ -2,6 +2,7 @@ package accesslog
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -48,7 +49,7 @@ func TestLogRotation(t *testing.T) {
 	rotatedFileName := fileName + ".rotated"
 
 	config := &types.AccessLog{FilePath: fileName, Format: CommonFormat}
-	logHandler, err := NewHandler(config)
+	logHandler, err := NewHandler(config, nil)
 	if err != nil {
 		t.Fatalf("Error creating new log handler: %s", err)
 	}
@@ -181,7 +182,7 @@ func TestLoggerHeaderFields(t *testing.T) {
 				Fields:   &test.accessLogFields,
 			}
 
-			logger, err := NewHandler(config)
+			logger, err := NewHandler(config, nil)
 			require.NoError(t, err)
 			defer logger.Close()
 
@@ -357,6 +358,8 @@ func TestLoggerJSON(t *testing.T) {
 				RetryAttempts:             assertFloat64(float64(testRetryAttempts)),
 				TLSVersion:                assertString("1.3"),
 				TLSCipher:                 assertString("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"),
+				TLSServerName:             assertString(testHostname),
+				TLSClientCertFingerprint:  assertString("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"),
 				"time":                    assertNotEmpty(),
 				StartLocal:                assertNotEmpty(),
 				StartUTC:                  assertNotEmpty(),
@@ -750,7 +753,7 @@ func createTempDir(t *testing.T, prefix string) string {
 func doLoggingTLSOpt(t *testing.T, config *types.AccessLog, enableTLS bool) {
 	t.Helper()
 
-	logger, err := NewHandler(config)
+	logger, err := NewHandler(config, nil)
 	require.NoError(t, err)
 	defer logger.Close()
 
@@ -775,8 +778,10 @@ func doLoggingTLSOpt(t *testing.T, config *types.AccessLog, enableTLS bool) {
 	}
 	if enableTLS {
 		req.TLS = &tls.ConnectionState{
-			Version:     tls.VersionTLS13,
-			CipherSuite: tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			Version:          tls.VersionTLS13,
+			CipherSuite:      tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			ServerName:       testHostname,
+			PeerCertificates: []*x509.Certificate{{Raw: []byte("foo")}},
 		}
 	}
 
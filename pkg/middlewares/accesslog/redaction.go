@@ -0,0 +1,108 @@
+package accesslog
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	redactedValue = "REDACTED"
+	maskedValue   = "****"
+
+	// hashLength is the number of hexadecimal characters of the SHA-256 hash kept in the logs.
+	hashLength = 16
+)
+
+// redactHeaders adds the headers to the fields, according to their mode.
+func redactHeaders(config *types.AccessLogFields, headers http.Header, fields logrus.Fields, prefix string) {
+	for k := range headers {
+		if value, ok := redactHeader(config, k, headers.Get(k)); ok {
+			fields[prefix+k] = value
+		}
+	}
+}
+
+// redactHeader returns the value to log for the given header, or false if it must be dropped.
+func redactHeader(config *types.AccessLogFields, name, value string) (string, bool) {
+	switch config.KeepHeader(name) {
+	case types.AccessLogKeep:
+		if name == "Cookie" || name == "Set-Cookie" {
+			return redactCookies(config, name == "Set-Cookie", value)
+		}
+		return value, true
+	case types.AccessLogRedact:
+		return redactedValue, true
+	case types.AccessLogHash:
+		return hashValue(value), true
+	case types.AccessLogMask:
+		return maskValue(value), true
+	default:
+		return "", false
+	}
+}
+
+// redactCookies applies the cookies modes to a Cookie, or a Set-Cookie, header value.
+// The attributes of a Set-Cookie header are kept as is.
+func redactCookies(config *types.AccessLogFields, setCookie bool, value string) (string, bool) {
+	var parts []string
+
+	for i, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if setCookie && i > 0 {
+			parts = append(parts, part)
+			continue
+		}
+
+		var name, cookieValue string
+		if j := strings.IndexByte(part, '='); j >= 0 {
+			name, cookieValue = part[:j], part[j+1:]
+		} else {
+			name = part
+		}
+
+		switch config.KeepCookie(name) {
+		case types.AccessLogKeep:
+			parts = append(parts, part)
+		case types.AccessLogRedact:
+			parts = append(parts, name+"="+redactedValue)
+		case types.AccessLogHash:
+			parts = append(parts, name+"="+hashValue(cookieValue))
+		case types.AccessLogMask:
+			parts = append(parts, name+"="+maskedValue)
+		default:
+			if setCookie {
+				return "", false
+			}
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", false
+	}
+
+	return strings.Join(parts, "; "), true
+}
+
+// hashValue returns a truncated SHA-256 hash of the value,
+// allowing to correlate the requests without logging the value.
+func hashValue(value string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(value)))[:len("sha256:")+hashLength]
+}
+
+// maskValue masks the value, only keeping its first word, such as the scheme of an Authorization header.
+func maskValue(value string) string {
+	if i := strings.IndexByte(value, ' '); i > 0 {
+		return value[:i] + " " + maskedValue
+	}
+
+	return maskedValue
+}
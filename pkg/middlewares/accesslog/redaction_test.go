@@ -0,0 +1,232 @@
+package accesslog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestRedactHeader(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        *types.AccessLogFields
+		name          string
+		value         string
+		expectedValue string
+		expectedKept  bool
+	}{
+		{
+			desc:          "keep",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogKeep}},
+			name:          "X-Foo",
+			value:         "bar",
+			expectedValue: "bar",
+			expectedKept:  true,
+		},
+		{
+			desc:   "drop",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogDrop}},
+			name:   "X-Foo",
+			value:  "bar",
+		},
+		{
+			desc:          "redact",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogRedact}},
+			name:          "X-Foo",
+			value:         "bar",
+			expectedValue: "REDACTED",
+			expectedKept:  true,
+		},
+		{
+			desc:          "hash",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogHash}},
+			name:          "X-Foo",
+			value:         "bar",
+			expectedValue: "sha256:fcde2b2edba56bf4",
+			expectedKept:  true,
+		},
+		{
+			desc:          "mask",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogMask}},
+			name:          "X-Foo",
+			value:         "bar",
+			expectedValue: "****",
+			expectedKept:  true,
+		},
+		{
+			desc:          "mask with scheme",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogMask}},
+			name:          "Authorization",
+			value:         "Bearer secret",
+			expectedValue: "Bearer ****",
+			expectedKept:  true,
+		},
+		{
+			desc:          "credentials redacted by default",
+			config:        &types.AccessLogFields{Headers: &types.FieldHeaders{DefaultMode: types.AccessLogKeep}},
+			name:          "Authorization",
+			value:         "Bearer secret",
+			expectedValue: "REDACTED",
+			expectedKept:  true,
+		},
+		{
+			desc: "credentials explicitly kept",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Names:       map[string]string{"Authorization": types.AccessLogKeep},
+			}},
+			name:          "Authorization",
+			value:         "Bearer secret",
+			expectedValue: "Bearer secret",
+			expectedKept:  true,
+		},
+		{
+			desc: "cookies",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Cookies: &types.FieldCookies{
+					DefaultMode: types.AccessLogKeep,
+					Names: map[string]string{
+						"session": types.AccessLogMask,
+						"token":   types.AccessLogDrop,
+						"user":    types.AccessLogHash,
+						"csrf":    types.AccessLogRedact,
+					},
+				},
+			}},
+			name:          "Cookie",
+			value:         "theme=dark; session=secret; token=secret; user=bar; csrf=secret",
+			expectedValue: "theme=dark; session=****; user=sha256:fcde2b2edba56bf4; csrf=REDACTED",
+			expectedKept:  true,
+		},
+		{
+			desc: "all cookies dropped",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Cookies:     &types.FieldCookies{DefaultMode: types.AccessLogDrop},
+			}},
+			name:  "Cookie",
+			value: "session=secret; token=secret",
+		},
+		{
+			desc: "set cookie",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Cookies:     &types.FieldCookies{DefaultMode: types.AccessLogMask},
+			}},
+			name:          "Set-Cookie",
+			value:         "session=secret; Path=/; HttpOnly",
+			expectedValue: "session=****; Path=/; HttpOnly",
+			expectedKept:  true,
+		},
+		{
+			desc: "set cookie dropped",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Cookies: &types.FieldCookies{
+					Names: map[string]string{"session": types.AccessLogDrop},
+				},
+			}},
+			name:  "Set-Cookie",
+			value: "session=secret; Path=/; HttpOnly",
+		},
+		{
+			desc: "cookie header redacted",
+			config: &types.AccessLogFields{Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogRedact,
+				Cookies:     &types.FieldCookies{DefaultMode: types.AccessLogKeep},
+			}},
+			name:          "Cookie",
+			value:         "session=secret",
+			expectedValue: "REDACTED",
+			expectedKept:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, kept := redactHeader(test.config, test.name, test.value)
+			assert.Equal(t, test.expectedKept, kept)
+			assert.Equal(t, test.expectedValue, value)
+		})
+	}
+}
+
+// TestAuthorizationNeverLeaks checks that the credentials are never logged,
+// whatever the headers modes, unless the credentials headers are explicitly kept.
+func TestAuthorizationNeverLeaks(t *testing.T) {
+	const (
+		basicCredentials  = "dXNlcjpzM2NyM3RwYXNzd29yZA=="
+		bearerCredentials = "eyJhbGciOiJIUzI1NiJ9.c2VjcmV0.s3cr3t"
+	)
+
+	modes := []string{"", types.AccessLogKeep, types.AccessLogDrop, types.AccessLogRedact, types.AccessLogHash, types.AccessLogMask}
+
+	for _, format := range []string{CommonFormat, JSONFormat} {
+		for _, defaultMode := range modes {
+			for _, headerMode := range modes {
+				if headerMode == types.AccessLogKeep {
+					continue
+				}
+
+				format := format
+				defaultMode := defaultMode
+				headerMode := headerMode
+
+				t.Run(fmt.Sprintf("%s-%q-%q", format, defaultMode, headerMode), func(t *testing.T) {
+					t.Parallel()
+
+					config := &types.AccessLog{
+						FilePath: filepath.Join(t.TempDir(), "access.log"),
+						Format:   format,
+					}
+
+					if defaultMode != "" || headerMode != "" {
+						config.Fields = &types.AccessLogFields{
+							Headers: &types.FieldHeaders{DefaultMode: defaultMode},
+						}
+
+						if headerMode != "" {
+							config.Fields.Headers.Names = map[string]string{
+								"authorization":       headerMode,
+								"Proxy-Authorization": headerMode,
+							}
+						}
+					}
+
+					logger, err := NewHandler(config, nil)
+					require.NoError(t, err)
+
+					next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+						rw.WriteHeader(http.StatusOK)
+					})
+
+					req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+					req.Header.Set("Authorization", "Basic "+basicCredentials)
+					req.Header.Set("Proxy-Authorization", "Bearer "+bearerCredentials)
+
+					logger.ServeHTTP(httptest.NewRecorder(), req, next)
+
+					require.NoError(t, logger.Close())
+
+					content, err := ioutil.ReadFile(config.FilePath)
+					require.NoError(t, err)
+					require.NotEmpty(t, content)
+
+					assert.NotContains(t, string(content), basicCredentials)
+					assert.NotContains(t, string(content), bearerCredentials)
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package accesslog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lineHeaderSize is the size of the header storing the length of a line in the ring buffer.
+const lineHeaderSize = 4
+
+// ringBuffer is a fixed size FIFO queue of log lines, backed by a file.
+// Each line is stored as its length, followed by its content,
+// and wraps around to the beginning of the file when reaching its end.
+type ringBuffer struct {
+	file  *os.File
+	size  int64
+	head  int64
+	used  int64
+	count int
+}
+
+func newRingBuffer(filePath string, size int64) (*ringBuffer, error) {
+	if size <= lineHeaderSize {
+		return nil, fmt.Errorf("ring buffer size is too small: %d", size)
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create ring buffer path %s: %w", dir, err)
+	}
+
+	// The lines of a previous run are not recovered, the file is truncated.
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening ring buffer file %s: %w", filePath, err)
+	}
+
+	return &ringBuffer{file: file, size: size}, nil
+}
+
+// len returns the number of lines in the ring buffer.
+func (r *ringBuffer) len() int {
+	return r.count
+}
+
+// push appends the line to the ring buffer, and returns false if there is not enough space left for it.
+func (r *ringBuffer) push(line []byte) (bool, error) {
+	n := int64(lineHeaderSize + len(line))
+	if n > r.size-r.used {
+		return false, nil
+	}
+
+	record := make([]byte, n)
+	binary.BigEndian.PutUint32(record, uint32(len(line)))
+	copy(record[lineHeaderSize:], line)
+
+	if err := r.writeAt(record, (r.head+r.used)%r.size); err != nil {
+		return false, err
+	}
+
+	r.used += n
+	r.count++
+
+	return true, nil
+}
+
+// pop removes the oldest line from the ring buffer and returns it.
+func (r *ringBuffer) pop() ([]byte, error) {
+	if r.count == 0 {
+		return nil, errors.New("ring buffer is empty")
+	}
+
+	header := make([]byte, lineHeaderSize)
+	if err := r.readAt(header, r.head); err != nil {
+		return nil, err
+	}
+
+	line := make([]byte, binary.BigEndian.Uint32(header))
+	if err := r.readAt(line, (r.head+lineHeaderSize)%r.size); err != nil {
+		return nil, err
+	}
+
+	n := int64(lineHeaderSize + len(line))
+	r.head = (r.head + n) % r.size
+	r.used -= n
+	r.count--
+
+	if r.count == 0 {
+		// Restarts from the beginning of the file to avoid needlessly wrapping around.
+		r.head = 0
+	}
+
+	return line, nil
+}
+
+// reset empties the ring buffer.
+func (r *ringBuffer) reset() {
+	r.head = 0
+	r.used = 0
+	r.count = 0
+}
+
+func (r *ringBuffer) writeAt(p []byte, off int64) error {
+	tail := r.size - off
+	if int64(len(p)) <= tail {
+		_, err := r.file.WriteAt(p, off)
+		return err
+	}
+
+	if _, err := r.file.WriteAt(p[:tail], off); err != nil {
+		return err
+	}
+
+	_, err := r.file.WriteAt(p[tail:], 0)
+	return err
+}
+
+func (r *ringBuffer) readAt(p []byte, off int64) error {
+	tail := r.size - off
+	if int64(len(p)) <= tail {
+		_, err := r.file.ReadAt(p, off)
+		return err
+	}
+
+	if _, err := r.file.ReadAt(p[:tail], off); err != nil {
+		return err
+	}
+
+	_, err := r.file.ReadAt(p[tail:], 0)
+	return err
+}
+
+// close closes and removes the ring buffer file.
+func (r *ringBuffer) close() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(r.file.Name())
+}
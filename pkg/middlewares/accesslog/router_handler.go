@@ -0,0 +1,95 @@
+package accesslog
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// routerConfig is the access log configuration of a router, overriding the global one.
+type routerConfig struct {
+	disabled        bool
+	fields          *types.AccessLogFields
+	sampleRate      float64
+	keepStatusCodes types.HTTPCodeRanges
+}
+
+func newRouterConfig(config *types.RouterAccessLog) (*routerConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	rc := &routerConfig{
+		disabled:   !config.IsEnabled(),
+		fields:     config.Fields.DeepCopy(),
+		sampleRate: 1,
+	}
+
+	canonicalizeHeaderNames(rc.fields)
+
+	if config.Sampling != nil {
+		if config.Sampling.Rate < 0 || config.Sampling.Rate > 1 {
+			return nil, fmt.Errorf("sampling rate must be between 0 and 1: %v", config.Sampling.Rate)
+		}
+		rc.sampleRate = config.Sampling.Rate
+
+		var err error
+		rc.keepStatusCodes, err = types.NewHTTPCodeRanges(config.Sampling.StatusCodes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling status codes: %w", err)
+		}
+	}
+
+	return rc, nil
+}
+
+// keep returns whether the access log of a request handled by the router must be kept.
+func (r *routerConfig) keep(statusCode int) bool {
+	if r == nil {
+		return true
+	}
+
+	if r.disabled {
+		return false
+	}
+
+	if r.keepStatusCodes.Contains(statusCode) {
+		return true
+	}
+
+	return r.sampleRate >= 1 || rand.Float64() < r.sampleRate
+}
+
+// routerHandler sends the router name, and the access log configuration of the router, to the logger.
+type routerHandler struct {
+	next   http.Handler
+	name   string
+	config *routerConfig
+}
+
+// NewRouterHandler creates a handler sending the router name, and the access log configuration of the router, to the logger.
+func NewRouterHandler(next http.Handler, routerName string, config *types.RouterAccessLog) (http.Handler, error) {
+	rc, err := newRouterConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &routerHandler{next: next, name: routerName, config: rc}, nil
+}
+
+func (r *routerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	table := GetLogData(req)
+	if table != nil {
+		table.Core[RouterName] = r.name
+		table.router = r.config
+
+		if template := middlewares.GetPathTemplate(req.Context()); template != "" {
+			table.Core[RequestPathTemplate] = template
+		}
+	}
+
+	r.next.ServeHTTP(rw, req)
+}
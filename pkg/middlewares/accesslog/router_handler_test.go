@@ -0,0 +1,163 @@
+package accesslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestRouterHandler(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	testCases := []struct {
+		desc           string
+		config         *types.RouterAccessLog
+		pathTemplate   string
+		status         int
+		expectedFields []map[string]interface{}
+	}{
+		{
+			desc:   "no router configuration",
+			status: http.StatusOK,
+			expectedFields: []map[string]interface{}{
+				{RouterName: "foo", DownstreamStatus: float64(http.StatusOK), "request_X-Foo": "bar"},
+			},
+		},
+		{
+			desc:         "path template",
+			pathTemplate: "/users/{id}",
+			status:       http.StatusOK,
+			expectedFields: []map[string]interface{}{
+				{RouterName: "foo", RequestPathTemplate: "/users/{id}"},
+			},
+		},
+		{
+			desc:   "enabled",
+			config: &types.RouterAccessLog{Enabled: &enabled},
+			status: http.StatusOK,
+			expectedFields: []map[string]interface{}{
+				{RouterName: "foo"},
+			},
+		},
+		{
+			desc:   "disabled",
+			config: &types.RouterAccessLog{Enabled: &disabled},
+			status: http.StatusInternalServerError,
+		},
+		{
+			desc: "sampled out",
+			config: &types.RouterAccessLog{
+				Sampling: &types.AccessLogSampling{Rate: 0, StatusCodes: []string{"500-599"}},
+			},
+			status: http.StatusOK,
+		},
+		{
+			desc: "kept by status code",
+			config: &types.RouterAccessLog{
+				Sampling: &types.AccessLogSampling{Rate: 0, StatusCodes: []string{"500-599"}},
+			},
+			status: http.StatusServiceUnavailable,
+			expectedFields: []map[string]interface{}{
+				{RouterName: "foo", DownstreamStatus: float64(http.StatusServiceUnavailable)},
+			},
+		},
+		{
+			desc: "fields override",
+			config: &types.RouterAccessLog{
+				Fields: &types.AccessLogFields{
+					DefaultMode: types.AccessLogDrop,
+					Names:       map[string]string{RouterName: types.AccessLogKeep},
+					Headers: &types.FieldHeaders{
+						DefaultMode: types.AccessLogDrop,
+						Names:       map[string]string{"x-foo": types.AccessLogRedact},
+					},
+				},
+			},
+			status: http.StatusOK,
+			expectedFields: []map[string]interface{}{
+				{RouterName: "foo", DownstreamStatus: nil, "request_X-Foo": "REDACTED"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+			logger, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat}, nil)
+			require.NoError(t, err)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(test.status)
+			})
+
+			handler, err := NewRouterHandler(next, "foo", test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			req.Header.Set("X-Foo", "bar")
+			req = req.WithContext(middlewares.WithPathTemplate(req.Context(), test.pathTemplate))
+
+			logger.ServeHTTP(httptest.NewRecorder(), req, handler)
+
+			require.NoError(t, logger.Close())
+
+			lines := readJSONLines(t, logFilePath)
+			require.Len(t, lines, len(test.expectedFields))
+
+			for i, expected := range test.expectedFields {
+				for key, value := range expected {
+					assert.Equal(t, value, lines[i][key], key)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRouterHandler_invalidConfig(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := NewRouterHandler(next, "foo", &types.RouterAccessLog{
+		Sampling: &types.AccessLogSampling{Rate: 2},
+	})
+	assert.Error(t, err)
+
+	_, err = NewRouterHandler(next, "foo", &types.RouterAccessLog{
+		Sampling: &types.AccessLogSampling{Rate: 1, StatusCodes: []string{"foo"}},
+	})
+	assert.Error(t, err)
+}
+
+func readJSONLines(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	var lines []map[string]interface{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+
+	return lines
+}
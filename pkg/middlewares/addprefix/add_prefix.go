@@ -48,13 +48,16 @@ func (a *addPrefix) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (a *addPrefix) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName))
 
+	// The prefix can refer to the named capture groups of the previous regex middlewares.
+	prefix := middlewares.ExpandPathCaptures(req.Context(), a.prefix)
+
 	oldURLPath := req.URL.Path
-	req.URL.Path = ensureLeadingSlash(a.prefix + req.URL.Path)
+	req.URL.Path = ensureLeadingSlash(prefix + req.URL.Path)
 	logger.Debugf("URL.Path is now %s (was %s).", req.URL.Path, oldURLPath)
 
 	if req.URL.RawPath != "" {
 		oldURLRawPath := req.URL.RawPath
-		req.URL.RawPath = ensureLeadingSlash(a.prefix + req.URL.RawPath)
+		req.URL.RawPath = ensureLeadingSlash(prefix + req.URL.RawPath)
 		logger.Debugf("URL.RawPath is now %s (was %s).", req.URL.RawPath, oldURLRawPath)
 	}
 	req.RequestURI = req.URL.RequestURI()
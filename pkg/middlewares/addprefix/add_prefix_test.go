@@ -3,11 +3,13 @@ package addprefix
 import (
 	"context"
 	"net/http"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -106,3 +108,22 @@ func TestAddPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestAddPrefix_pathCaptures(t *testing.T) {
+	exp := regexp.MustCompile(`^/(?P<tenant>[a-z]+)/`)
+
+	var actualPath string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualPath = r.URL.Path
+	})
+
+	handler, err := New(context.Background(), next, dynamic.AddPrefix{Prefix: "/tenants/${tenant}"}, "foo-add-prefix")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/foo/bar", nil)
+	req = req.WithContext(middlewares.WithPathCaptures(req.Context(), exp, exp.FindStringSubmatch(req.URL.Path)))
+
+	handler.ServeHTTP(nil, req)
+
+	assert.Equal(t, "/tenants/foo/foo/bar", actualPath)
+}
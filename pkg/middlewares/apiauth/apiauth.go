@@ -0,0 +1,275 @@
+package apiauth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	goauth "github.com/abbot/go-http-auth"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+)
+
+const defaultRealm = "traefik"
+
+// The roles granted to the authenticated identities.
+const (
+	roleAdmin  = "admin"
+	roleViewer = "read-only"
+)
+
+// The prefixes of the identities, naming the authentication method which authenticated them,
+// so an identity cannot be impersonated through another method.
+const (
+	prefixBasic = "basic:"
+	prefixMTLS  = "mtls:"
+	prefixOIDC  = "oidc:"
+)
+
+type identityKey struct{}
+
+// Identity returns the identity of the request authenticated by the Authenticator,
+// prefixed by its authentication method.
+func Identity(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
+
+// Authenticator authenticates the requests to the API and the dashboard,
+// and authorizes them according to the role granted to their identity.
+type Authenticator struct {
+	users  map[string]string
+	realm  string
+	oidc   *oidcAuth
+	caPool *x509.CertPool
+
+	admins  map[string]struct{}
+	viewers map[string]struct{}
+}
+
+// New creates an Authenticator from its configuration.
+// It returns nil when no authentication is configured.
+func New(config *static.APIAuth) (*Authenticator, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	a := &Authenticator{
+		admins:  toSet(config.Admins),
+		viewers: toSet(config.Viewers),
+	}
+
+	if config.Basic != nil {
+		users, err := loadUsers(config.Basic)
+		if err != nil {
+			return nil, fmt.Errorf("basic: %w", err)
+		}
+
+		a.users = users
+
+		a.realm = defaultRealm
+		if config.Basic.Realm != "" {
+			a.realm = config.Basic.Realm
+		}
+	}
+
+	if config.OIDC != nil {
+		oidc, err := newOIDCAuth(config.OIDC)
+		if err != nil {
+			return nil, fmt.Errorf("oidc: %w", err)
+		}
+
+		a.oidc = oidc
+	}
+
+	if config.MTLS != nil {
+		a.caPool = x509.NewCertPool()
+
+		for _, caFile := range config.MTLS.CAFiles {
+			data, err := caFile.Read()
+			if err != nil {
+				return nil, fmt.Errorf("mtls: %w", err)
+			}
+
+			if !a.caPool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("mtls: invalid certificate authority: %s", caFile)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// Wrap returns a handler authenticating and authorizing the requests before passing them to next.
+// It returns next when the Authenticator is nil.
+func (a *Authenticator) Wrap(next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		a.serveHTTP(rw, req, next)
+	})
+}
+
+func (a *Authenticator) serveHTTP(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+	if a.oidc != nil {
+		switch req.URL.Path {
+		case callbackPath:
+			a.oidc.callback(rw, req)
+			return
+		case logoutPath:
+			a.oidc.logout(rw, req)
+			return
+		}
+	}
+
+	logger := log.FromContext(req.Context())
+
+	identity, ok := a.identity(req)
+	if !ok {
+		logger.Debug("API authentication failed")
+		a.requireAuth(rw, req)
+		return
+	}
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.ClientUsername] = identity
+	}
+
+	switch a.role(identity) {
+	case roleAdmin:
+	case roleViewer:
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			logger.Debugf("API request %s %s forbidden to the read-only identity %q", req.Method, req.URL.Path, identity)
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+	default:
+		logger.Debugf("No role granted to the identity %q", identity)
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), identityKey{}, identity)))
+}
+
+// identity returns the identity of the request, prefixed by its authentication method,
+// trying the client certificate first, then the basic authentication, and finally the OpenID Connect tokens.
+func (a *Authenticator) identity(req *http.Request) (string, bool) {
+	if a.caPool != nil {
+		if identity, ok := a.certificateIdentity(req); ok {
+			return prefixMTLS + identity, true
+		}
+	}
+
+	if a.users != nil {
+		if user, password, ok := req.BasicAuth(); ok {
+			secret, exists := a.users[user]
+			return prefixBasic + user, exists && goauth.CheckSecret(password, secret)
+		}
+	}
+
+	if a.oidc != nil {
+		identity, ok := a.oidc.identity(req)
+		return prefixOIDC + identity, ok
+	}
+
+	return "", false
+}
+
+// certificateIdentity returns the common name of the client certificate of the request,
+// verified with the configured certificate authorities, regardless of the client authentication of the TLS options.
+func (a *Authenticator) certificateIdentity(req *http.Request) (string, bool) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return "", false
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range req.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	cert := req.TLS.PeerCertificates[0]
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         a.caPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		log.FromContext(req.Context()).Debugf("Invalid client certificate: %v", err)
+		return "", false
+	}
+
+	return cert.Subject.CommonName, cert.Subject.CommonName != ""
+}
+
+func (a *Authenticator) role(identity string) string {
+	if _, ok := a.admins[identity]; ok {
+		return roleAdmin
+	}
+
+	if _, ok := a.viewers[identity]; ok {
+		return roleViewer
+	}
+
+	return ""
+}
+
+// requireAuth asks the client to authenticate:
+// the browsers are redirected to the OpenID Connect issuer, and the other clients are asked for their credentials.
+func (a *Authenticator) requireAuth(rw http.ResponseWriter, req *http.Request) {
+	if a.oidc != nil && req.Method == http.MethodGet && strings.Contains(req.Header.Get("Accept"), "text/html") {
+		a.oidc.login(rw, req)
+		return
+	}
+
+	if a.users != nil {
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", a.realm))
+	}
+
+	http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+func loadUsers(config *static.APIBasicAuth) (map[string]string, error) {
+	lines := append([]string{}, config.Users...)
+
+	if config.UsersFile != "" {
+		data, err := ioutil.ReadFile(config.UsersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	users := make(map[string]string, len(lines))
+	for _, line := range lines {
+		parts := strings.Split(line, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid user: %s", line)
+		}
+
+		users[parts[0]] = parts[1]
+	}
+
+	return users, nil
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		set[value] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,265 @@
+package apiauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// testUserHash is the hash of the "test" password.
+const testUserHash = "$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"
+
+func TestNew_noAuth(t *testing.T) {
+	auth, err := New(nil)
+	require.NoError(t, err)
+	assert.Nil(t, auth)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	assert.NotNil(t, auth.Wrap(next))
+}
+
+func TestAuthenticator_basic(t *testing.T) {
+	auth, err := New(&static.APIAuth{
+		Basic: &static.APIBasicAuth{
+			Users: []string{"admin:" + testUserHash, "viewer:" + testUserHash, "other:" + testUserHash},
+		},
+		Admins:  []string{"basic:admin"},
+		Viewers: []string{"basic:viewer", "mtls:other"},
+	})
+	require.NoError(t, err)
+
+	handler := auth.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		identity, _ := Identity(req.Context())
+		_, _ = rw.Write([]byte(identity))
+	}))
+
+	testCases := []struct {
+		desc               string
+		method             string
+		user               string
+		password           string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "no credentials",
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "invalid password",
+			method:             http.MethodGet,
+			user:               "admin",
+			password:           "foo",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "unknown user",
+			method:             http.MethodGet,
+			user:               "foo",
+			password:           "test",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "admin reading",
+			method:             http.MethodGet,
+			user:               "admin",
+			password:           "test",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "basic:admin",
+		},
+		{
+			desc:               "admin writing",
+			method:             http.MethodPost,
+			user:               "admin",
+			password:           "test",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "basic:admin",
+		},
+		{
+			desc:               "viewer reading",
+			method:             http.MethodGet,
+			user:               "viewer",
+			password:           "test",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "basic:viewer",
+		},
+		{
+			desc:               "viewer writing",
+			method:             http.MethodDelete,
+			user:               "viewer",
+			password:           "test",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "user granted a role with another authentication method",
+			method:             http.MethodGet,
+			user:               "other",
+			password:           "test",
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, "http://traefik.example.com/api/overrides", nil)
+			if test.user != "" {
+				req.SetBasicAuth(test.user, test.password)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+
+			if test.expectedStatusCode == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="traefik"`, rw.Header().Get("WWW-Authenticate"))
+			}
+
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticator_mTLS(t *testing.T) {
+	caCert, caKey := generateCertificate(t, "ca", nil, nil)
+	otherCACert, otherCAKey := generateCertificate(t, "other-ca", nil, nil)
+
+	clientCert, _ := generateCertificate(t, "admin", caCert, caKey)
+	otherClientCert, _ := generateCertificate(t, "admin", otherCACert, otherCAKey)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+
+	auth, err := New(&static.APIAuth{
+		MTLS:   &static.APIMTLSAuth{CAFiles: []traefiktls.FileOrContent{traefiktls.FileOrContent(caPEM)}},
+		Admins: []string{"mtls:admin"},
+	})
+	require.NoError(t, err)
+
+	handler := auth.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		identity, _ := Identity(req.Context())
+		_, _ = rw.Write([]byte(identity))
+	}))
+
+	testCases := []struct {
+		desc               string
+		certificate        *x509.Certificate
+		expectedStatusCode int
+	}{
+		{
+			desc:               "no client certificate",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "client certificate signed by the CA",
+			certificate:        clientCert,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "client certificate signed by another CA",
+			certificate:        otherClientCert,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "https://traefik.example.com/api/overrides", nil)
+			if test.certificate != nil {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{test.certificate}}
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, "mtls:admin", rw.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticator_noViewers(t *testing.T) {
+	auth, err := New(&static.APIAuth{
+		Basic:  &static.APIBasicAuth{Users: []string{"admin:" + testUserHash, "other:" + testUserHash}},
+		Admins: []string{"basic:admin"},
+	})
+	require.NoError(t, err)
+
+	handler := auth.Wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	// The authenticated identities are denied when they are not granted a role.
+	req := httptest.NewRequest(http.MethodGet, "http://traefik.example.com/api/rawdata", nil)
+	req.SetBasicAuth("other", "test")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	_, err := New(&static.APIAuth{Basic: &static.APIBasicAuth{Users: []string{"foo"}}})
+	assert.Error(t, err)
+
+	_, err = New(&static.APIAuth{MTLS: &static.APIMTLSAuth{CAFiles: []traefiktls.FileOrContent{"not a certificate"}}})
+	assert.Error(t, err)
+}
+
+// generateCertificate generates a certificate with the given common name,
+// signed by the given parent, or self-signed when the parent is nil.
+func generateCertificate(t *testing.T, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+
+		parent, parentKey = template, key
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return cert, key
+}
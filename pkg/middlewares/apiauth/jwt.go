@@ -0,0 +1,60 @@
+package apiauth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// clockSkew is the tolerance applied when checking the validity period of the tokens.
+const clockSkew = time.Minute
+
+// signatureAlgorithms are the asymmetric algorithms accepted for the signature of the ID tokens.
+var signatureAlgorithms = map[string]struct{}{
+	string(jose.RS256): {}, string(jose.RS384): {}, string(jose.RS512): {},
+	string(jose.PS256): {}, string(jose.PS384): {}, string(jose.PS512): {},
+	string(jose.ES256): {}, string(jose.ES384): {}, string(jose.ES512): {},
+}
+
+// idTokenClaims are the claims of an ID token verified by the authentication.
+type idTokenClaims struct {
+	jwt.Claims
+
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// parseIDToken parses a signed ID token, without verifying its signature.
+// It returns the token and the ID of the key which signed it.
+func parseIDToken(token string) (*jwt.JSONWebToken, string, error) {
+	tok, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(tok.Headers) != 1 {
+		return nil, "", errors.New("unexpected number of signatures")
+	}
+
+	header := tok.Headers[0]
+	if _, ok := signatureAlgorithms[header.Algorithm]; !ok {
+		return nil, "", fmt.Errorf("unsupported signing algorithm: %q", header.Algorithm)
+	}
+
+	return tok, header.KeyID, nil
+}
+
+// verifyClaims verifies the issuer, the audience and the validity period of a token.
+func verifyClaims(claims jwt.Claims, issuer, audience string, now time.Time) error {
+	if claims.Expiry == nil {
+		return errors.New("missing expiration time")
+	}
+
+	return claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:   issuer,
+		Audience: jwt.Audience{audience},
+		Time:     now,
+	}, clockSkew)
+}
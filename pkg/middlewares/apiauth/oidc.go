@@ -0,0 +1,502 @@
+package apiauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	callbackPath = "/api/auth/callback"
+	logoutPath   = "/api/auth/logout"
+
+	sessionCookieName = "traefik_api_session"
+	stateCookieName   = "traefik_api_state"
+
+	// stateTTL is the time given to the users to log in at the issuer.
+	stateTTL = 10 * time.Minute
+
+	// minKeysRefreshInterval limits the fetches of the keys of the issuer, when a token is signed by an unknown key.
+	minKeysRefreshInterval = time.Minute
+)
+
+// providerMetadata is the subset of the OpenID Connect discovery document used by the authentication.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcAuth authenticates the requests with the ID tokens of an OpenID Connect issuer.
+// The browsers log in with the authorization code flow, and get a session cookie holding their identity,
+// while the other clients send the ID token as a bearer token.
+type oidcAuth struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	claim        string
+
+	client *http.Client
+
+	// sessionKey signs the session and state cookies.
+	// It is generated on startup, so the users have to log in again when Traefik restarts.
+	sessionKey []byte
+
+	mu            sync.Mutex
+	metadata      *providerMetadata
+	keys          map[string]jose.JSONWebKey
+	keysFetchedAt time.Time
+}
+
+func newOIDCAuth(config *static.APIOIDCAuth) (*oidcAuth, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return nil, err
+	}
+
+	claim := config.Claim
+	if claim == "" {
+		claim = "email"
+	}
+
+	return &oidcAuth{
+		issuer:       strings.TrimSuffix(config.Issuer, "/"),
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		scopes:       config.Scopes,
+		claim:        claim,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		sessionKey:   sessionKey,
+	}, nil
+}
+
+// identity returns the identity of the request, from its bearer token or its session cookie.
+func (o *oidcAuth) identity(req *http.Request) (string, bool) {
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != req.Header.Get("Authorization") {
+		// The bearer tokens are not issued for a login of Traefik, so they have no nonce to check.
+		identity, _, err := o.verifyIDToken(req.Context(), token, "")
+		if err != nil {
+			log.FromContext(req.Context()).Debugf("Invalid bearer token: %v", err)
+			return "", false
+		}
+
+		return identity, true
+	}
+
+	cookie, err := req.Cookie(sessionCookieName)
+	if err != nil {
+		return "", false
+	}
+
+	return o.verifySession(cookie.Value, time.Now())
+}
+
+// login redirects the browser to the issuer, to log in with the authorization code flow.
+func (o *oidcAuth) login(rw http.ResponseWriter, req *http.Request) {
+	metadata, err := o.providerMetadata(req.Context())
+	if err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to get the OpenID Connect provider metadata: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	state, err := randomString()
+	if err != nil {
+		log.FromContext(req.Context()).Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		log.FromContext(req.Context()).Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The state cookie binds the authorization response to the browser, holds the nonce expected in the ID token,
+	// and the page to go back to.
+	payload := state + "|" + nonce + "|" + strconv.FormatInt(time.Now().Add(stateTTL).Unix(), 10) + "|" + req.URL.RequestURI()
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     stateCookieName,
+		Value:    o.sign(base64.RawURLEncoding.EncodeToString([]byte(payload))),
+		Path:     callbackPath,
+		MaxAge:   int(stateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {o.clientID},
+		"redirect_uri":  {redirectURI(req)},
+		"scope":         {strings.Join(o.scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	http.Redirect(rw, req, metadata.AuthorizationEndpoint+"?"+query.Encode(), http.StatusFound)
+}
+
+// callback handles the authorization response of the issuer, and opens the session of the user.
+func (o *oidcAuth) callback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(req.Context())
+
+	cookie, err := req.Cookie(stateCookieName)
+	if err != nil {
+		http.Error(rw, "missing state", http.StatusBadRequest)
+		return
+	}
+
+	value, ok := o.verify(cookie.Value)
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	parts := strings.SplitN(string(payload), "|", 4)
+	if !ok || err != nil || len(parts) != 4 || parts[0] != req.URL.Query().Get("state") {
+		http.Error(rw, "invalid state", http.StatusBadRequest)
+		return
+	}
+
+	if expiresAt, _ := strconv.ParseInt(parts[2], 10, 64); time.Now().After(time.Unix(expiresAt, 0)) {
+		http.Error(rw, "expired state", http.StatusBadRequest)
+		return
+	}
+
+	if errCode := req.URL.Query().Get("error"); errCode != "" {
+		logger.Debugf("OpenID Connect authorization error: %s", errCode)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	idToken, err := o.exchangeCode(req.Context(), req.URL.Query().Get("code"), redirectURI(req))
+	if err != nil {
+		logger.Errorf("Unable to exchange the OpenID Connect authorization code: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	// The session lasts as long as the ID token.
+	identity, expiresAt, err := o.verifyIDToken(req.Context(), idToken, parts[1])
+	if err != nil {
+		logger.Errorf("Invalid OpenID Connect ID token: %v", err)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(rw, &http.Cookie{Name: stateCookieName, Path: callbackPath, MaxAge: -1})
+	http.SetCookie(rw, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    o.newSession(identity, expiresAt),
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(rw, req, safeRedirect(parts[3]), http.StatusFound)
+}
+
+// logout closes the session of the user.
+func (o *oidcAuth) logout(rw http.ResponseWriter, req *http.Request) {
+	http.SetCookie(rw, &http.Cookie{Name: sessionCookieName, Path: "/", MaxAge: -1})
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (o *oidcAuth) exchangeCode(ctx context.Context, code, redirectURI string) (string, error) {
+	if code == "" {
+		return "", errors.New("missing authorization code")
+	}
+
+	metadata, err := o.providerMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = o.getJSON(req, &tokens); err != nil {
+		return "", err
+	}
+
+	if tokens.IDToken == "" {
+		return "", errors.New("no ID token in the token response")
+	}
+
+	return tokens.IDToken, nil
+}
+
+// verifyIDToken verifies the given ID token, and the nonce it holds when the expected nonce is not empty.
+// It returns the identity held by the configured claim of the token, and its expiration time.
+func (o *oidcAuth) verifyIDToken(ctx context.Context, token, nonce string) (string, time.Time, error) {
+	tok, keyID, err := parseIDToken(token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	key, err := o.publicKey(ctx, keyID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var verified idTokenClaims
+	var claims map[string]interface{}
+	if err = tok.Claims(key.Key, &verified, &claims); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err = verifyClaims(verified.Claims, o.issuer, o.clientID, time.Now()); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if nonce != "" && subtle.ConstantTimeCompare([]byte(verified.Nonce), []byte(nonce)) != 1 {
+		return "", time.Time{}, errors.New("invalid nonce")
+	}
+
+	identity, _ := claims[o.claim].(string)
+	if identity == "" {
+		return "", time.Time{}, fmt.Errorf("missing %q claim", o.claim)
+	}
+
+	return identity, verified.Expiry.Time(), nil
+}
+
+// publicKey returns the key of the issuer with the given ID, fetching the keys of the issuer if it is unknown.
+func (o *oidcAuth) publicKey(ctx context.Context, keyID string) (jose.JSONWebKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if key, ok := o.findKey(keyID); ok {
+		return key, nil
+	}
+
+	if time.Since(o.keysFetchedAt) < minKeysRefreshInterval {
+		return jose.JSONWebKey{}, fmt.Errorf("unknown key: %q", keyID)
+	}
+
+	metadata, err := o.providerMetadataLocked(ctx)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.JWKSURI, nil)
+	if err != nil {
+		return jose.JSONWebKey{}, err
+	}
+
+	// The keys are decoded one by one, so the keys of an unsupported type do not prevent the use of the others.
+	var keySet struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err = o.getJSON(req, &keySet); err != nil {
+		return jose.JSONWebKey{}, fmt.Errorf("unable to get the keys of the issuer: %w", err)
+	}
+
+	o.keys = make(map[string]jose.JSONWebKey)
+	o.keysFetchedAt = time.Now()
+
+	for _, raw := range keySet.Keys {
+		var jwk jose.JSONWebKey
+		if err := jwk.UnmarshalJSON(raw); err != nil {
+			log.FromContext(ctx).Debugf("Skipping a key of the issuer: %v", err)
+			continue
+		}
+
+		if !jwk.IsPublic() || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		o.keys[jwk.KeyID] = jwk
+	}
+
+	if key, ok := o.findKey(keyID); ok {
+		return key, nil
+	}
+
+	return jose.JSONWebKey{}, fmt.Errorf("unknown key: %q", keyID)
+}
+
+func (o *oidcAuth) findKey(keyID string) (jose.JSONWebKey, bool) {
+	// The key ID is optional when the issuer has a single key.
+	if keyID == "" && len(o.keys) == 1 {
+		for _, key := range o.keys {
+			return key, true
+		}
+	}
+
+	key, ok := o.keys[keyID]
+	return key, ok
+}
+
+func (o *oidcAuth) providerMetadata(ctx context.Context) (*providerMetadata, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.providerMetadataLocked(ctx)
+}
+
+// providerMetadataLocked returns the discovery document of the issuer, which is fetched once.
+func (o *oidcAuth) providerMetadataLocked(ctx context.Context) (*providerMetadata, error) {
+	if o.metadata != nil {
+		return o.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &providerMetadata{}
+	if err = o.getJSON(req, metadata); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("issuer mismatch: %q", metadata.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("incomplete provider metadata")
+	}
+
+	o.metadata = metadata
+
+	return metadata, nil
+}
+
+func (o *oidcAuth) getJSON(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, req.URL, body)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+// newSession returns the value of the session cookie of the given identity.
+func (o *oidcAuth) newSession(identity string, expiresAt time.Time) string {
+	return o.sign(base64.RawURLEncoding.EncodeToString([]byte(identity)) + "|" + strconv.FormatInt(expiresAt.Unix(), 10))
+}
+
+func (o *oidcAuth) verifySession(value string, now time.Time) (string, bool) {
+	payload, ok := o.verify(value)
+	if !ok {
+		return "", false
+	}
+
+	parts := strings.SplitN(payload, "|", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.After(time.Unix(expiresAt, 0)) {
+		return "", false
+	}
+
+	identity, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(identity) == 0 {
+		return "", false
+	}
+
+	return string(identity), true
+}
+
+// sign returns the given payload followed by its signature.
+func (o *oidcAuth) sign(payload string) string {
+	mac := hmac.New(sha256.New, o.sessionKey)
+	_, _ = mac.Write([]byte(payload))
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns the payload of the given signed value, if its signature is valid.
+func (o *oidcAuth) verify(value string) (string, bool) {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return "", false
+	}
+
+	payload := value[:i]
+	if !hmac.Equal([]byte(o.sign(payload)), []byte(value)) {
+		return "", false
+	}
+
+	return payload, true
+}
+
+// randomString returns a random string, suitable for the state and the nonce of the authorization requests.
+func randomString() (string, error) {
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+// redirectURI returns the URL of the callback, on the host used by the request.
+func redirectURI(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + req.Host + callbackPath
+}
+
+// safeRedirect returns the given URI if it is a local path, to prevent open redirects.
+func safeRedirect(uri string) string {
+	if !strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "//") || strings.HasPrefix(uri, "/\\") {
+		return "/dashboard/"
+	}
+
+	return uri
+}
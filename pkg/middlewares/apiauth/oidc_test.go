@@ -0,0 +1,335 @@
+package apiauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// fakeIssuer is an OpenID Connect issuer issuing ID tokens for the "traefik" client.
+type fakeIssuer struct {
+	*httptest.Server
+
+	key  *rsa.PrivateKey
+	code string
+
+	// nonce is the nonce of the authorization request, held by the issued ID tokens.
+	nonce atomic.Value
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &fakeIssuer{key: key, code: "authorization-code"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(providerMetadata{
+			Issuer:                issuer.URL,
+			AuthorizationEndpoint: issuer.URL + "/authorize",
+			TokenEndpoint:         issuer.URL + "/token",
+			JWKSURI:               issuer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "key", Use: "sig", Algorithm: string(jose.RS256)}},
+		})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, req *http.Request) {
+		clientID, clientSecret, _ := req.BasicAuth()
+		if clientID != "traefik" || clientSecret != "secret" {
+			http.Error(rw, "invalid_client", http.StatusUnauthorized)
+			return
+		}
+
+		if req.PostFormValue("code") != issuer.code || req.PostFormValue("redirect_uri") != "http://traefik.example.com"+callbackPath {
+			http.Error(rw, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+
+		claims := issuer.claims("admin@example.com")
+		claims["nonce"], _ = issuer.nonce.Load().(string)
+
+		_ = json.NewEncoder(rw).Encode(map[string]string{
+			"id_token": issuer.token(t, "key", claims),
+		})
+	})
+
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+
+	return issuer
+}
+
+func (f *fakeIssuer) claims(email string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   f.URL,
+		"aud":   "traefik",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": email,
+	}
+}
+
+func (f *fakeIssuer) token(t *testing.T, keyID string, claims map[string]interface{}) string {
+	t.Helper()
+
+	opts := &jose.SignerOptions{}
+	if keyID != "" {
+		opts.WithHeader("kid", keyID)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: f.key}, opts)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func newOIDCAuthenticator(t *testing.T, issuer *fakeIssuer) *Authenticator {
+	t.Helper()
+
+	config := &static.APIOIDCAuth{Issuer: issuer.URL, ClientID: "traefik", ClientSecret: "secret"}
+	config.SetDefaults()
+
+	auth, err := New(&static.APIAuth{OIDC: config, Admins: []string{"oidc:admin@example.com"}})
+	require.NoError(t, err)
+
+	return auth
+}
+
+func TestAuthenticator_oidcBearerToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+
+	testCases := []struct {
+		desc               string
+		keyID              string
+		claims             func(claims map[string]interface{})
+		expectedStatusCode int
+	}{
+		{
+			desc:               "valid token",
+			keyID:              "key",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "valid token without key ID",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "valid token of a read-only identity",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { claims["email"] = "viewer@example.com" },
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			desc:               "unknown key",
+			keyID:              "other",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "wrong audience",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { claims["aud"] = []string{"other"} },
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "wrong issuer",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { claims["iss"] = "https://issuer.example.com" },
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "expired token",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "missing expiration time",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { delete(claims, "exp") },
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:               "missing identity claim",
+			keyID:              "key",
+			claims:             func(claims map[string]interface{}) { delete(claims, "email") },
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			auth := newOIDCAuthenticator(t, issuer)
+
+			handler := auth.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+
+			claims := issuer.claims("admin@example.com")
+			if test.claims != nil {
+				test.claims(claims)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://traefik.example.com/api/overrides", nil)
+			req.Header.Set("Authorization", "Bearer "+issuer.token(t, test.keyID, claims))
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatusCode, rw.Code)
+		})
+	}
+}
+
+func TestAuthenticator_oidcLogin(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	auth := newOIDCAuthenticator(t, issuer)
+
+	handler := auth.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		identity, _ := Identity(req.Context())
+		_, _ = rw.Write([]byte(identity))
+	}))
+
+	// The API clients are not redirected.
+	req := httptest.NewRequest(http.MethodGet, "http://traefik.example.com/api/rawdata", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	// The browsers are redirected to the issuer.
+	req = httptest.NewRequest(http.MethodGet, "http://traefik.example.com/dashboard/?foo=bar", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+
+	assert.Equal(t, issuer.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "traefik", location.Query().Get("client_id"))
+	assert.Equal(t, "code", location.Query().Get("response_type"))
+	assert.Equal(t, "openid email", location.Query().Get("scope"))
+	assert.Equal(t, "http://traefik.example.com"+callbackPath, location.Query().Get("redirect_uri"))
+
+	state := location.Query().Get("state")
+	require.NotEmpty(t, state)
+
+	nonce := location.Query().Get("nonce")
+	require.NotEmpty(t, nonce)
+	assert.NotEqual(t, state, nonce)
+
+	cookies := rw.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	stateCookie := cookies[0]
+	assert.Equal(t, stateCookieName, stateCookie.Name)
+
+	// A forged state is rejected.
+	req = httptest.NewRequest(http.MethodGet, "http://traefik.example.com"+callbackPath+"?code="+issuer.code+"&state=forged", nil)
+	req.AddCookie(stateCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+
+	// An ID token without the nonce of the authorization request is rejected.
+	issuer.nonce.Store("forged")
+
+	req = httptest.NewRequest(http.MethodGet, "http://traefik.example.com"+callbackPath+"?code="+issuer.code+"&state="+state, nil)
+	req.AddCookie(stateCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	// The issuer redirects the browser to the callback.
+	issuer.nonce.Store(nonce)
+
+	req = httptest.NewRequest(http.MethodGet, "http://traefik.example.com"+callbackPath+"?code="+issuer.code+"&state="+state, nil)
+	req.AddCookie(stateCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+	assert.Equal(t, "/dashboard/?foo=bar", rw.Header().Get("Location"))
+
+	var sessionCookie *http.Cookie
+	for _, cookie := range rw.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			sessionCookie = cookie
+		}
+	}
+	require.NotNil(t, sessionCookie)
+
+	// The session cookie authenticates the following requests.
+	req = httptest.NewRequest(http.MethodDelete, "http://traefik.example.com/api/overrides", nil)
+	req.AddCookie(sessionCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "oidc:admin@example.com", rw.Body.String())
+
+	// A session cookie with a tampered identity is rejected.
+	tampered := base64.RawURLEncoding.EncodeToString([]byte("other@example.com")) + sessionCookie.Value[strings.Index(sessionCookie.Value, "|"):]
+
+	req = httptest.NewRequest(http.MethodGet, "http://traefik.example.com/api/rawdata", nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: tampered})
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	// The logout removes the session cookie.
+	req = httptest.NewRequest(http.MethodPost, "http://traefik.example.com"+logoutPath, nil)
+	req.AddCookie(sessionCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+
+	cookies = rw.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, sessionCookieName, cookies[0].Name)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+}
+
+func TestSafeRedirect(t *testing.T) {
+	testCases := map[string]string{
+		"/dashboard/#/http/routers": "/dashboard/#/http/routers",
+		"/api/rawdata":              "/api/rawdata",
+		"//evil.example.com":        "/dashboard/",
+		"/\\evil.example.com":       "/dashboard/",
+		"https://evil.example.com":  "/dashboard/",
+		"":                          "/dashboard/",
+	}
+
+	for uri, expected := range testCases {
+		assert.Equal(t, expected, safeRedirect(uri), uri)
+	}
+}
@@ -2,7 +2,13 @@ package auth
 
 import (
 	"io/ioutil"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
 )
 
 // UserParser Parses a string and return a userName/userHash. An error if the format of the string is incorrect.
@@ -13,14 +19,56 @@ const (
 	authorizationHeader = "Authorization"
 )
 
-func getUsers(fileName string, appendUsers []string, parser UserParser) (map[string]string, error) {
-	users, err := loadUsers(fileName, appendUsers)
+// usersFileCheckInterval is the minimum interval between two checks of a users file modification.
+var usersFileCheckInterval = 5 * time.Second
+
+var (
+	usersFilesMu sync.Mutex
+	// usersFiles are shared by all the middlewares using the same file with the same format,
+	// so that they are not loaded again each time the configuration is reloaded.
+	usersFiles = make(map[string]*usersFile)
+)
+
+// users holds the users of an authentication middleware, by user key.
+// The inline users take precedence over the users of the file.
+type users struct {
+	inline map[string]string
+	file   *usersFile
+}
+
+func newUsers(fileName string, appendUsers []string, format string, parser UserParser) (*users, error) {
+	inline, err := parseUsers(appendUsers, parser)
 	if err != nil {
 		return nil, err
 	}
 
+	u := &users{inline: inline}
+	if fileName != "" {
+		u.file, err = getUsersFile(fileName, format, parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+// secret returns the secret of the given user, or an empty string if the user is unknown.
+func (u *users) secret(key string) string {
+	if secret, ok := u.inline[key]; ok {
+		return secret
+	}
+
+	if u.file == nil {
+		return ""
+	}
+
+	return u.file.secret(key)
+}
+
+func parseUsers(lines []string, parser UserParser) (map[string]string, error) {
 	userMap := make(map[string]string)
-	for _, user := range users {
+	for _, user := range lines {
 		userName, userHash, err := parser(user)
 		if err != nil {
 			return nil, err
@@ -31,18 +79,103 @@ func getUsers(fileName string, appendUsers []string, parser UserParser) (map[str
 	return userMap, nil
 }
 
-func loadUsers(fileName string, appendUsers []string) ([]string, error) {
-	var users []string
-	var err error
+// usersFile is a users file, which is reloaded when it is modified.
+type usersFile struct {
+	path   string
+	parser UserParser
 
-	if fileName != "" {
-		users, err = getLinesFromFile(fileName)
-		if err != nil {
-			return nil, err
-		}
+	mu        sync.RWMutex
+	users     map[string]string
+	modTime   time.Time
+	checkedAt time.Time
+	checking  int32
+}
+
+// getUsersFile returns the users file of the given path and format, loading it if needed.
+func getUsersFile(path, format string, parser UserParser) (*usersFile, error) {
+	usersFilesMu.Lock()
+	defer usersFilesMu.Unlock()
+
+	key := format + ":" + path
+	if f, ok := usersFiles[key]; ok {
+		return f, nil
+	}
+
+	f := &usersFile{path: path, parser: parser}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	f.checkedAt = time.Now()
+	usersFiles[key] = f
+
+	return f, nil
+}
+
+func (f *usersFile) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
 	}
 
-	return append(users, appendUsers...), nil
+	f.mu.RLock()
+	unchanged := f.users != nil && info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	lines, err := getLinesFromFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	users, err := parseUsers(lines, f.parser)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.users = users
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfNeeded reloads the users in the background if the file has been modified.
+// The file is checked at most once per usersFileCheckInterval.
+func (f *usersFile) reloadIfNeeded() {
+	f.mu.RLock()
+	checkedAt := f.checkedAt
+	f.mu.RUnlock()
+
+	if time.Since(checkedAt) < usersFileCheckInterval || !atomic.CompareAndSwapInt32(&f.checking, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&f.checking, 0)
+
+		if err := f.load(); err != nil {
+			// The previous users are kept.
+			log.WithoutContext().Errorf("Unable to reload the users file %s: %v", f.path, err)
+		}
+
+		f.mu.Lock()
+		f.checkedAt = time.Now()
+		f.mu.Unlock()
+	}()
+}
+
+func (f *usersFile) secret(key string) string {
+	f.reloadIfNeeded()
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.users[key]
 }
 
 func getLinesFromFile(filename string) ([]string, error) {
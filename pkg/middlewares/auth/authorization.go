@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// The messages and the service below are defined in authorization.proto,
+// which can be used to generate the authorization servers in any language supported by gRPC.
+
+const checkMethod = "/traefik.auth.v1.Authorization/Check"
+
+// Header is an HTTP header.
+type Header struct {
+	Key    string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+
+// CheckRequest is the request to check.
+type CheckRequest struct {
+	Method     string    `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Scheme     string    `protobuf:"bytes,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	Host       string    `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	Path       string    `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	RemoteAddr string    `protobuf:"bytes,5,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
+	Headers    []*Header `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty"`
+}
+
+func (m *CheckRequest) Reset()         { *m = CheckRequest{} }
+func (m *CheckRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckRequest) ProtoMessage()    {}
+
+// CheckResponse tells whether the request is allowed.
+type CheckResponse struct {
+	Allowed    bool      `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Headers    []*Header `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty"`
+	StatusCode int32     `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Body       []byte    `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (m *CheckResponse) Reset()         { *m = CheckResponse{} }
+func (m *CheckResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckResponse) ProtoMessage()    {}
+
+// AuthorizationServer is the server API of a gRPC authorization server.
+type AuthorizationServer interface {
+	Check(ctx context.Context, req *CheckRequest) (*CheckResponse, error)
+}
+
+// RegisterAuthorizationServer registers an authorization server on a gRPC server.
+func RegisterAuthorizationServer(s *grpc.Server, srv AuthorizationServer) {
+	s.RegisterService(&authorizationServiceDesc, srv)
+}
+
+var authorizationServiceDesc = grpc.ServiceDesc{
+	ServiceName: "traefik.auth.v1.Authorization",
+	HandlerType: (*AuthorizationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Check",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(CheckRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+
+				if interceptor == nil {
+					return srv.(AuthorizationServer).Check(ctx, in)
+				}
+
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: checkMethod}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(AuthorizationServer).Check(ctx, req.(*CheckRequest))
+				}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "authorization.proto",
+}
@@ -23,7 +23,8 @@ const (
 type basicAuth struct {
 	next         http.Handler
 	auth         *goauth.BasicAuth
-	users        map[string]string
+	users        *users
+	ldap         *ldapAuth
 	headerField  string
 	removeHeader bool
 	name         string
@@ -32,7 +33,7 @@ type basicAuth struct {
 // NewBasic creates a basicAuth middleware.
 func NewBasic(ctx context.Context, next http.Handler, authConfig dynamic.BasicAuth, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, basicTypeName)).Debug("Creating middleware")
-	users, err := getUsers(authConfig.UsersFile, authConfig.Users, basicUserParser)
+	users, err := newUsers(authConfig.UsersFile, authConfig.Users, "basic", basicUserParser)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +46,13 @@ func NewBasic(ctx context.Context, next http.Handler, authConfig dynamic.BasicAu
 		name:         name,
 	}
 
+	if authConfig.LDAP != nil {
+		ba.ldap, err = newLDAPAuth(authConfig.LDAP)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	realm := defaultRealm
 	if len(authConfig.Realm) > 0 {
 		realm = authConfig.Realm
@@ -64,10 +72,7 @@ func (b *basicAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	user, password, ok := req.BasicAuth()
 	if ok {
-		secret := b.auth.Secrets(user, b.auth.Realm)
-		if secret == "" || !goauth.CheckSecret(password, secret) {
-			ok = false
-		}
+		ok = b.authenticate(req, user, password)
 	}
 
 	logData := accesslog.GetLogData(req)
@@ -97,12 +102,27 @@ func (b *basicAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	b.next.ServeHTTP(rw, req)
 }
 
-func (b *basicAuth) secretBasic(user, realm string) string {
-	if secret, ok := b.users[user]; ok {
-		return secret
+// authenticate checks the password of the user, against its secret if it is defined, or with a bind to the LDAP server.
+func (b *basicAuth) authenticate(req *http.Request, user, password string) bool {
+	if secret := b.auth.Secrets(user, b.auth.Realm); secret != "" {
+		return goauth.CheckSecret(password, secret)
+	}
+
+	if b.ldap == nil {
+		return false
 	}
 
-	return ""
+	ok, err := b.ldap.authenticate(req.Context(), user, password)
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.name, basicTypeName)).Errorf("Unable to authenticate with LDAP: %v", err)
+		return false
+	}
+
+	return ok
+}
+
+func (b *basicAuth) secretBasic(user, realm string) string {
+	return b.users.secret(user)
 }
 
 func basicUserParser(user string) (string, string, error) {
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -279,3 +280,49 @@ func TestBasicAuthUsersFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestBasicAuthUsersFileReload(t *testing.T) {
+	usersFile, err := ioutil.TempFile("", "auth-users")
+	require.NoError(t, err)
+	defer os.Remove(usersFile.Name())
+
+	_, err = usersFile.Write([]byte("test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/\n"))
+	require.NoError(t, err)
+	require.NoError(t, usersFile.Close())
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "traefik")
+	})
+
+	authenticator, err := NewBasic(context.Background(), next, dynamic.BasicAuth{UsersFile: usersFile.Name()}, "authName")
+	require.NoError(t, err)
+
+	isAuthorized := func(user, password string) bool {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.SetBasicAuth(user, password)
+
+		rw := httptest.NewRecorder()
+		authenticator.ServeHTTP(rw, req)
+
+		return rw.Code == http.StatusOK
+	}
+
+	assert.True(t, isAuthorized("test", "test"))
+	assert.False(t, isAuthorized("test2", "test2"))
+
+	err = ioutil.WriteFile(usersFile.Name(), []byte("test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0\n"), 0o600)
+	require.NoError(t, err)
+
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(usersFile.Name(), modTime, modTime))
+
+	// Forces the next check of the file.
+	file := authenticator.(*basicAuth).users.file
+	file.mu.Lock()
+	file.checkedAt = time.Time{}
+	file.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return isAuthorized("test2", "test2") && !isAuthorized("test", "test")
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -23,7 +23,7 @@ const (
 type digestAuth struct {
 	next         http.Handler
 	auth         *goauth.DigestAuth
-	users        map[string]string
+	users        *users
 	headerField  string
 	removeHeader bool
 	name         string
@@ -32,7 +32,7 @@ type digestAuth struct {
 // NewDigest creates a digest auth middleware.
 func NewDigest(ctx context.Context, next http.Handler, authConfig dynamic.DigestAuth, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, digestTypeName)).Debug("Creating middleware")
-	users, err := getUsers(authConfig.UsersFile, authConfig.Users, digestUserParser)
+	users, err := newUsers(authConfig.UsersFile, authConfig.Users, "digest", digestUserParser)
 	if err != nil {
 		return nil, err
 	}
@@ -109,11 +109,7 @@ func (d *digestAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 }
 
 func (d *digestAuth) secretDigest(user, realm string) string {
-	if secret, ok := d.users[user+":"+realm]; ok {
-		return secret
-	}
-
-	return ""
+	return d.users.secret(user + ":" + realm)
 }
 
 func digestUserParser(user string) (string, string, error) {
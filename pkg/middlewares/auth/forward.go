@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/forward"
 	"github.com/vulcand/oxy/utils"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -26,13 +28,24 @@ const (
 	forwardedTypeName = "ForwardedAuthType"
 )
 
+const forwardAuthTimeout = 30 * time.Second
+
+// The address schemes of the authorization servers listening on a unix socket, or implementing the gRPC Check API.
+const (
+	schemeUnix     = "unix"
+	schemeGRPC     = "grpc"
+	schemeGRPCUnix = "grpc+unix"
+)
+
 type forwardAuth struct {
 	address                  string
+	url                      string
 	authResponseHeaders      []string
 	authResponseHeadersRegex *regexp.Regexp
 	next                     http.Handler
 	name                     string
 	client                   http.Client
+	grpcConn                 *grpc.ClientConn
 	trustForwardHeader       bool
 	authRequestHeaders       []string
 }
@@ -43,6 +56,7 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 
 	fa := &forwardAuth{
 		address:             config.Address,
+		url:                 config.Address,
 		authResponseHeaders: config.AuthResponseHeaders,
 		next:                next,
 		name:                name,
@@ -55,10 +69,47 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
-		Timeout: 30 * time.Second,
+		Timeout: forwardAuthTimeout,
 	}
 
-	if config.TLS != nil {
+	switch {
+	case strings.HasPrefix(config.Address, schemeGRPC+"://"), strings.HasPrefix(config.Address, schemeGRPCUnix+"://"):
+		conn, err := getGRPCConn(config.Address, config.TLS)
+		if err != nil {
+			return nil, err
+		}
+		fa.grpcConn = conn
+
+	case strings.HasPrefix(config.Address, schemeUnix+"://"):
+		if config.TLS != nil {
+			return nil, errors.New("TLS is not supported with a unix socket address")
+		}
+
+		u, err := url.Parse(config.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %w", config.Address, err)
+		}
+
+		socketPath := u.Host + u.Path
+		if socketPath == "" {
+			return nil, fmt.Errorf("invalid address %s: missing socket path", config.Address)
+		}
+
+		// The request is sent on the socket, to the path given by the path query parameter.
+		path := u.Query().Get("path")
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		fa.url = "http://localhost" + path
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+		fa.client.Transport = tr
+
+	case config.TLS != nil:
 		tlsConfig, err := config.TLS.CreateTLSConfig()
 		if err != nil {
 			return nil, err
@@ -87,7 +138,7 @@ func (fa *forwardAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), fa.name, forwardedTypeName))
 
-	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, nil)
+	forwardReq, err := http.NewRequest(http.MethodGet, fa.url, nil)
 	tracing.LogRequest(tracing.GetSpan(req), forwardReq)
 	if err != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause %s", fa.address, err)
@@ -104,7 +155,7 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	writeHeader(req, forwardReq, fa.trustForwardHeader, fa.authRequestHeaders)
 
-	forwardResponse, forwardErr := fa.client.Do(forwardReq)
+	forwardResponse, forwardErr := fa.do(req, forwardReq)
 	if forwardErr != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause: %s", fa.address, forwardErr)
 		logger.Debug(logMessage)
@@ -185,6 +236,15 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	fa.next.ServeHTTP(rw, req)
 }
 
+// do sends the forward request to the authorization server.
+func (fa *forwardAuth) do(req, forwardReq *http.Request) (*http.Response, error) {
+	if fa.grpcConn != nil {
+		return fa.check(req, forwardReq)
+	}
+
+	return fa.client.Do(forwardReq)
+}
+
 func writeHeader(req, forwardReq *http.Request, trustForwardHeader bool, allowedHeaders []string) {
 	utils.CopyHeaders(forwardReq.Header, req.Header)
 	utils.RemoveHeaders(forwardReq.Header, forward.HopHeaders...)
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/forward"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// The connections are shared by the middlewares calling the same authorization server,
+// as the middlewares are created again on each configuration change.
+var (
+	grpcConnsMu sync.Mutex
+	grpcConns   = make(map[string]*grpc.ClientConn)
+)
+
+// getGRPCConn returns the connection to the gRPC authorization server of the given grpc:// or grpc+unix:// address.
+func getGRPCConn(address string, clientTLS *dynamic.ClientTLS) (*grpc.ClientConn, error) {
+	key := address
+	if clientTLS != nil {
+		key += fmt.Sprintf("|%+v", *clientTLS)
+	}
+
+	grpcConnsMu.Lock()
+	defer grpcConnsMu.Unlock()
+
+	if conn, ok := grpcConns[key]; ok {
+		return conn, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if clientTLS != nil {
+		tlsConfig, err := clientTLS.CreateTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+		}
+		opts[0] = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	target := u.Host
+	if u.Scheme == schemeGRPCUnix {
+		target = u.Host + u.Path
+		opts = append(opts,
+			grpc.WithAuthority("localhost"),
+			grpc.WithContextDialer(func(ctx context.Context, socketPath string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			}),
+		)
+	}
+
+	if target == "" {
+		return nil, fmt.Errorf("invalid address %s: missing host or socket path", address)
+	}
+
+	// The connection is established in the background, and re-established when needed.
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the authorization server %s: %w", address, err)
+	}
+
+	grpcConns[key] = conn
+
+	return conn, nil
+}
+
+// check calls the gRPC authorization server with the forward request,
+// and converts its answer to the response an HTTP authorization server would have sent.
+func (fa *forwardAuth) check(req, forwardReq *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), forwardAuthTimeout)
+	defer cancel()
+
+	checkReq := &CheckRequest{
+		Method:     forwardReq.Header.Get(xForwardedMethod),
+		Scheme:     forwardReq.Header.Get(forward.XForwardedProto),
+		Host:       forwardReq.Header.Get(forward.XForwardedHost),
+		Path:       forwardReq.Header.Get(xForwardedURI),
+		RemoteAddr: req.RemoteAddr,
+	}
+
+	for key, values := range forwardReq.Header {
+		checkReq.Headers = append(checkReq.Headers, &Header{Key: key, Values: values})
+	}
+
+	sort.Slice(checkReq.Headers, func(i, j int) bool {
+		return checkReq.Headers[i].Key < checkReq.Headers[j].Key
+	})
+
+	checkResp := &CheckResponse{}
+	if err := fa.grpcConn.Invoke(ctx, checkMethod, checkReq, checkResp); err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	for _, h := range checkResp.Headers {
+		for _, value := range h.Values {
+			header.Add(h.Key, value)
+		}
+	}
+
+	code := http.StatusOK
+	if !checkResp.Allowed {
+		code = int(checkResp.StatusCode)
+		if code < http.StatusMultipleChoices || code > 599 {
+			code = http.StatusForbidden
+		}
+	}
+
+	return &http.Response{
+		StatusCode:    code,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(checkResp.Body)),
+		ContentLength: int64(len(checkResp.Body)),
+	}, nil
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/opentracing/opentracing-go"
@@ -18,6 +21,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/forward"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestForwardAuthFail(t *testing.T) {
@@ -480,6 +486,170 @@ func TestForwardAuthUsesTracing(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 }
 
+func TestForwardAuthUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "forward-auth")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "auth.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify" || r.Header.Get("X-Forwarded-Uri") != "/allowed" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("X-Auth-User", "user@example.com")
+	}))
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "user@example.com", r.Header.Get("X-Auth-User"))
+	})
+
+	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+		Address:             "unix://" + socketPath + "?path=/verify",
+		AuthResponseHeaders: []string{"X-Auth-User"},
+	}, "authTest")
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/allowed", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	rw = httptest.NewRecorder()
+	middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://localhost/denied", nil))
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Equal(t, "Forbidden\n", rw.Body.String())
+}
+
+type authorizationFunc func(ctx context.Context, req *CheckRequest) (*CheckResponse, error)
+
+func (a authorizationFunc) Check(ctx context.Context, req *CheckRequest) (*CheckResponse, error) {
+	return a(ctx, req)
+}
+
+func startAuthorizationServer(t *testing.T, network, address string, authorization authorizationFunc) net.Addr {
+	t.Helper()
+
+	listener, err := net.Listen(network, address)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	RegisterAuthorizationServer(server, authorization)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr()
+}
+
+func TestForwardAuthGRPC(t *testing.T) {
+	authorization := func(_ context.Context, req *CheckRequest) (*CheckResponse, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "http", req.Scheme)
+		assert.Equal(t, "example.com", req.Host)
+
+		var token string
+		for _, h := range req.Headers {
+			if h.Key == "Authorization" {
+				token = h.Values[0]
+			}
+		}
+
+		switch {
+		case req.Path == "/error":
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		case token != "Bearer token":
+			return &CheckResponse{
+				StatusCode: http.StatusFound,
+				Headers:    []*Header{{Key: "Location", Values: []string{"http://example.com/login"}}},
+			}, nil
+		case req.Path == "/admin":
+			return &CheckResponse{Body: []byte("Forbidden")}, nil
+		default:
+			return &CheckResponse{
+				Allowed: true,
+				Headers: []*Header{{Key: "X-Auth-User", Values: []string{"user@example.com"}}},
+			}, nil
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "forward-auth")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	tcpAddr := startAuthorizationServer(t, "tcp", "127.0.0.1:0", authorization)
+	unixAddr := startAuthorizationServer(t, "unix", filepath.Join(dir, "auth.sock"), authorization)
+
+	testCases := []struct {
+		desc             string
+		path             string
+		token            string
+		expectedCode     int
+		expectedLocation string
+		expectedBody     string
+	}{
+		{
+			desc:         "allowed",
+			path:         "/",
+			token:        "Bearer token",
+			expectedCode: http.StatusOK,
+			expectedBody: "traefik\n",
+		},
+		{
+			desc:             "redirected",
+			path:             "/",
+			expectedCode:     http.StatusFound,
+			expectedLocation: "http://example.com/login",
+		},
+		{
+			desc:         "denied with the default status code",
+			path:         "/admin",
+			token:        "Bearer token",
+			expectedCode: http.StatusForbidden,
+			expectedBody: "Forbidden",
+		},
+		{
+			desc:         "error",
+			path:         "/error",
+			token:        "Bearer token",
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, address := range []string{"grpc://" + tcpAddr.String(), "grpc+unix://" + unixAddr.String()} {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "user@example.com", r.Header.Get("X-Auth-User"))
+			fmt.Fprintln(w, "traefik")
+		})
+
+		middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+			Address:             address,
+			AuthResponseHeaders: []string{"X-Auth-User"},
+		}, "authTest")
+		require.NoError(t, err)
+
+		for _, test := range testCases {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com"+test.path, nil)
+			if test.token != "" {
+				req.Header.Set("Authorization", test.token)
+			}
+
+			rw := httptest.NewRecorder()
+			middleware.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code, "%s: %s", address, test.desc)
+			assert.Equal(t, test.expectedLocation, rw.Header().Get("Location"), "%s: %s", address, test.desc)
+			assert.Equal(t, test.expectedBody, rw.Body.String(), "%s: %s", address, test.desc)
+		}
+	}
+}
+
 type mockBackend struct {
 	opentracing.Tracer
 }
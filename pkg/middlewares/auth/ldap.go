@@ -0,0 +1,452 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const (
+	defaultLDAPCacheDuration         = 5 * time.Minute
+	defaultLDAPNegativeCacheDuration = 30 * time.Second
+
+	ldapTimeout = 10 * time.Second
+
+	// maxLDAPCacheEntries bounds the memory used by the cache, which is filled by the clients.
+	maxLDAPCacheEntries = 10000
+
+	// maxLDAPMessageSize bounds the size of the messages read from the server.
+	maxLDAPMessageSize = 1 << 20
+
+	ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+)
+
+// BER identifiers of the LDAP messages (RFC 4511).
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berEnumerated  = 0x0a
+	berSequence    = 0x30
+
+	ldapBindRequest      = 0x60
+	ldapBindResponse     = 0x61
+	ldapUnbindRequest    = 0x42
+	ldapExtendedRequest  = 0x77
+	ldapExtendedResponse = 0x78
+
+	ldapSimpleAuthentication = 0x80
+	ldapExtendedRequestName  = 0x80
+)
+
+// ldapAuth authenticates the users with a simple bind to an LDAP server.
+// The results of the binds are cached, so that the server is not queried on each request,
+// and a brute force attack does not reach it for each attempt.
+type ldapAuth struct {
+	address   string
+	ldaps     bool
+	startTLS  bool
+	tlsConfig *tls.Config
+	userDN    string
+
+	cacheDuration         time.Duration
+	negativeCacheDuration time.Duration
+
+	mu    sync.Mutex
+	cache map[string]ldapCacheEntry
+}
+
+type ldapCacheEntry struct {
+	ok        bool
+	expiresAt time.Time
+}
+
+func newLDAPAuth(config *dynamic.LDAPAuth) (*ldapAuth, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP URL: %w", err)
+	}
+
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q, only ldap and ldaps are allowed", u.Scheme)
+	}
+
+	if u.Scheme == "ldaps" && config.StartTLS {
+		return nil, errors.New("startTLS cannot be used with the ldaps scheme")
+	}
+
+	if !strings.Contains(config.UserDN, "{username}") {
+		return nil, errors.New("the LDAP user DN must contain the {username} placeholder")
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		port := "389"
+		if u.Scheme == "ldaps" {
+			port = "636"
+		}
+		address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	tlsConfig, err := config.TLS.CreateTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	l := &ldapAuth{
+		address:               address,
+		ldaps:                 u.Scheme == "ldaps",
+		startTLS:              config.StartTLS,
+		tlsConfig:             tlsConfig,
+		userDN:                config.UserDN,
+		cacheDuration:         time.Duration(config.CacheDuration),
+		negativeCacheDuration: time.Duration(config.NegativeCacheDuration),
+		cache:                 make(map[string]ldapCacheEntry),
+	}
+
+	if l.cacheDuration == 0 {
+		l.cacheDuration = defaultLDAPCacheDuration
+	}
+	if l.negativeCacheDuration == 0 {
+		l.negativeCacheDuration = defaultLDAPNegativeCacheDuration
+	}
+
+	return l, nil
+}
+
+// authenticate reports whether the user can bind with the given password.
+// An error is returned when the server cannot tell, and the result is then not cached.
+func (l *ldapAuth) authenticate(ctx context.Context, user, password string) (bool, error) {
+	// An empty password would be an unauthenticated bind, which succeeds for any DN.
+	if user == "" || password == "" {
+		return false, nil
+	}
+
+	key := ldapCacheKey(user, password)
+	now := time.Now()
+
+	if ok, cached := l.cached(key, now); cached {
+		return ok, nil
+	}
+
+	ok, err := l.bind(ctx, user, password)
+	if err != nil {
+		return false, err
+	}
+
+	duration := l.cacheDuration
+	if !ok {
+		duration = l.negativeCacheDuration
+	}
+
+	l.store(key, ldapCacheEntry{ok: ok, expiresAt: now.Add(duration)}, now)
+
+	return ok, nil
+}
+
+func (l *ldapAuth) cached(key string, now time.Time) (bool, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.cache[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return false, false
+	}
+
+	return entry.ok, true
+}
+
+func (l *ldapAuth) store(key string, entry ldapCacheEntry, now time.Time) {
+	if !now.Before(entry.expiresAt) {
+		// A negative duration disables the cache.
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.cache) >= maxLDAPCacheEntries {
+		for k, e := range l.cache {
+			if !now.Before(e.expiresAt) {
+				delete(l.cache, k)
+			}
+		}
+	}
+
+	if len(l.cache) >= maxLDAPCacheEntries {
+		l.cache = make(map[string]ldapCacheEntry)
+	}
+
+	l.cache[key] = entry
+}
+
+// bind binds to the server with the DN of the user.
+func (l *ldapAuth) bind(ctx context.Context, user, password string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, ldapTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", l.address)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if l.ldaps {
+		conn = tls.Client(conn, l.tlsConfig)
+	}
+
+	reader := bufio.NewReader(conn)
+	var messageID int64
+
+	if l.startTLS {
+		messageID++
+		request := berEncode(ldapExtendedRequest, berEncode(ldapExtendedRequestName, []byte(ldapStartTLSOID)))
+		code, err := ldapRoundTrip(conn, reader, messageID, request, ldapExtendedResponse)
+		if err != nil {
+			return false, err
+		}
+		if code != ldapResultSuccess {
+			return false, fmt.Errorf("StartTLS failed with the result code %d", code)
+		}
+
+		conn = tls.Client(conn, l.tlsConfig)
+		reader = bufio.NewReader(conn)
+	}
+
+	messageID++
+	request := berEncode(ldapBindRequest,
+		berEncodeInteger(berInteger, 3),
+		berEncode(berOctetString, []byte(strings.ReplaceAll(l.userDN, "{username}", escapeDN(user)))),
+		berEncode(ldapSimpleAuthentication, []byte(password)),
+	)
+
+	code, err := ldapRoundTrip(conn, reader, messageID, request, ldapBindResponse)
+	if err != nil {
+		return false, err
+	}
+
+	messageID++
+	_, _ = conn.Write(berEncode(berSequence, berEncodeInteger(berInteger, messageID), berEncode(ldapUnbindRequest)))
+
+	switch code {
+	case ldapResultSuccess:
+		return true, nil
+	case ldapResultInvalidCredentials:
+		return false, nil
+	default:
+		return false, fmt.Errorf("bind failed with the result code %d", code)
+	}
+}
+
+// ldapRoundTrip sends the given protocol operation, and returns the result code of its response.
+func ldapRoundTrip(w io.Writer, r *bufio.Reader, messageID int64, op []byte, responseTag byte) (int64, error) {
+	if _, err := w.Write(berEncode(berSequence, berEncodeInteger(berInteger, messageID), op)); err != nil {
+		return 0, err
+	}
+
+	tag, message, err := berRead(r)
+	if err != nil {
+		return 0, err
+	}
+	if tag != berSequence {
+		return 0, fmt.Errorf("unexpected LDAP message tag 0x%x", tag)
+	}
+
+	tag, value, message, err := berNext(message)
+	if err != nil {
+		return 0, err
+	}
+	if id := berInt(value); tag != berInteger || id != messageID {
+		return 0, fmt.Errorf("unexpected LDAP message ID %d", id)
+	}
+
+	tag, response, _, err := berNext(message)
+	if err != nil {
+		return 0, err
+	}
+	if tag != responseTag {
+		return 0, fmt.Errorf("unexpected LDAP response tag 0x%x", tag)
+	}
+
+	tag, value, _, err = berNext(response)
+	if err != nil {
+		return 0, err
+	}
+	if tag != berEnumerated {
+		return 0, fmt.Errorf("unexpected LDAP result code tag 0x%x", tag)
+	}
+
+	return berInt(value), nil
+}
+
+// berEncode encodes the given value, or the concatenation of the given encoded elements.
+func berEncode(tag byte, elements ...[]byte) []byte {
+	var length int
+	for _, element := range elements {
+		length += len(element)
+	}
+
+	encoded := append([]byte{tag}, berLength(length)...)
+	for _, element := range elements {
+		encoded = append(encoded, element...)
+	}
+
+	return encoded
+}
+
+func berEncodeInteger(tag byte, value int64) []byte {
+	var encoded []byte
+	for {
+		encoded = append([]byte{byte(value)}, encoded...)
+		value >>= 8
+		if (value == 0 && encoded[0]&0x80 == 0) || (value == -1 && encoded[0]&0x80 != 0) {
+			break
+		}
+	}
+
+	return berEncode(tag, encoded)
+}
+
+func berLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	var encoded []byte
+	for ; length > 0; length >>= 8 {
+		encoded = append([]byte{byte(length)}, encoded...)
+	}
+
+	return append([]byte{0x80 | byte(len(encoded))}, encoded...)
+}
+
+// berRead reads an element, and returns its tag and value.
+func berRead(r *bufio.Reader) (byte, []byte, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	size := int(length)
+	if length&0x80 != 0 {
+		if length&0x7f > 4 {
+			return 0, nil, errors.New("invalid BER length")
+		}
+
+		size = 0
+		for i := 0; i < int(length&0x7f); i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return 0, nil, err
+			}
+			size = size<<8 | int(b)
+		}
+	}
+
+	if size > maxLDAPMessageSize {
+		return 0, nil, errors.New("LDAP message too large")
+	}
+
+	value := make([]byte, size)
+	if _, err = io.ReadFull(r, value); err != nil {
+		return 0, nil, err
+	}
+
+	return tag, value, nil
+}
+
+// berNext returns the tag and value of the first element of the given data, and the remaining data.
+func berNext(data []byte) (byte, []byte, []byte, error) {
+	if len(data) < 2 {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	tag, length := data[0], data[1]
+	data = data[2:]
+
+	size := int(length)
+	if length&0x80 != 0 {
+		n := int(length & 0x7f)
+		if n > 4 || len(data) < n {
+			return 0, nil, nil, errors.New("invalid BER length")
+		}
+
+		size = 0
+		for _, b := range data[:n] {
+			size = size<<8 | int(b)
+		}
+		data = data[n:]
+	}
+
+	if size < 0 || size > len(data) {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	return tag, data[:size], data[size:], nil
+}
+
+func berInt(value []byte) int64 {
+	var result int64
+	for i, b := range value {
+		if i == 0 && b&0x80 != 0 {
+			result = -1
+		}
+		result = result<<8 | int64(b)
+	}
+
+	return result
+}
+
+// escapeDN escapes the given value as an attribute value of a DN (RFC 4514).
+func escapeDN(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == ',' || c == '+' || c == '"' || c == '\\' || c == '<' || c == '>' || c == ';' || c == '=':
+			escaped.WriteByte('\\')
+			escaped.WriteByte(c)
+		case c == 0 || (c == ' ' && (i == 0 || i == len(value)-1)) || (c == '#' && i == 0):
+			escaped.WriteString(fmt.Sprintf("\\%02x", c))
+		default:
+			escaped.WriteByte(c)
+		}
+	}
+
+	return escaped.String()
+}
+
+func ldapCacheKey(user, password string) string {
+	sum := sha256.Sum256([]byte(user + "\x00" + password))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// fakeLDAPServer answers the bind requests, accepting the given password for the given DN.
+type fakeLDAPServer struct {
+	listener net.Listener
+	dn       string
+	password string
+
+	mu    sync.Mutex
+	binds []string
+}
+
+func newFakeLDAPServer(t *testing.T, dn, password string) *fakeLDAPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &fakeLDAPServer{listener: listener, dn: dn, password: password}
+	go server.serve()
+
+	return server
+}
+
+func (s *fakeLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeLDAPServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	for {
+		_, message, err := berRead(reader)
+		if err != nil {
+			return
+		}
+
+		_, id, message, err := berNext(message)
+		if err != nil {
+			return
+		}
+
+		tag, op, _, err := berNext(message)
+		if err != nil || tag != ldapBindRequest {
+			return
+		}
+
+		_, _, op, _ = berNext(op)
+		_, dn, op, _ := berNext(op)
+		_, password, _, _ := berNext(op)
+
+		s.mu.Lock()
+		s.binds = append(s.binds, string(dn))
+		s.mu.Unlock()
+
+		code := int64(ldapResultInvalidCredentials)
+		if string(dn) == s.dn && string(password) == s.password {
+			code = ldapResultSuccess
+		}
+
+		// The lengths are encoded in the long form, as some servers do.
+		response := berEncode(ldapBindResponse, berEncodeInteger(berEnumerated, code), berEncode(berOctetString), berEncode(berOctetString))
+		_, _ = conn.Write(append([]byte{berSequence, 0x84, 0, 0, 0, byte(len(response) + len(id) + 2), berInteger, byte(len(id))}, append(id, response...)...))
+	}
+}
+
+func (s *fakeLDAPServer) bindCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.binds)
+}
+
+func TestLDAPAuth_authenticate(t *testing.T) {
+	server := newFakeLDAPServer(t, `uid=jane\,doe,ou=people,dc=example,dc=com`, "secret")
+
+	ldap, err := newLDAPAuth(&dynamic.LDAPAuth{
+		URL:    "ldap://" + server.listener.Addr().String(),
+		UserDN: "uid={username},ou=people,dc=example,dc=com",
+	})
+	require.NoError(t, err)
+
+	ok, err := ldap.authenticate(context.Background(), "jane,doe", "secret")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = ldap.authenticate(context.Background(), "jane,doe", "wrong")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The empty passwords are rejected without binding, as they would be unauthenticated binds.
+	ok, err = ldap.authenticate(context.Background(), "jane,doe", "")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, server.bindCount())
+
+	// The positive and negative results are cached.
+	ok, err = ldap.authenticate(context.Background(), "jane,doe", "secret")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = ldap.authenticate(context.Background(), "jane,doe", "wrong")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, server.bindCount())
+}
+
+func TestLDAPAuth_cacheDisabled(t *testing.T) {
+	server := newFakeLDAPServer(t, "uid=jane,dc=example,dc=com", "secret")
+
+	ldap, err := newLDAPAuth(&dynamic.LDAPAuth{
+		URL:                   "ldap://" + server.listener.Addr().String(),
+		UserDN:                "uid={username},dc=example,dc=com",
+		CacheDuration:         ptypes.Duration(-1),
+		NegativeCacheDuration: ptypes.Duration(-1),
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		ok, err := ldap.authenticate(context.Background(), "jane", "secret")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	assert.Equal(t, 2, server.bindCount())
+}
+
+func TestLDAPAuth_unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	ldap, err := newLDAPAuth(&dynamic.LDAPAuth{
+		URL:    "ldap://" + listener.Addr().String(),
+		UserDN: "uid={username},dc=example,dc=com",
+	})
+	require.NoError(t, err)
+
+	_, err = ldap.authenticate(context.Background(), "jane", "secret")
+	assert.Error(t, err)
+
+	// The errors are not cached.
+	assert.Empty(t, ldap.cache)
+}
+
+func TestNewLDAPAuth_invalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.LDAPAuth
+	}{
+		{
+			desc:   "unsupported scheme",
+			config: dynamic.LDAPAuth{URL: "http://ldap.example.com", UserDN: "uid={username}"},
+		},
+		{
+			desc:   "StartTLS with ldaps",
+			config: dynamic.LDAPAuth{URL: "ldaps://ldap.example.com", UserDN: "uid={username}", StartTLS: true},
+		},
+		{
+			desc:   "user DN without placeholder",
+			config: dynamic.LDAPAuth{URL: "ldap://ldap.example.com", UserDN: "uid=jane"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newLDAPAuth(&test.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBasicAuthLDAP(t *testing.T) {
+	server := newFakeLDAPServer(t, "uid=jane,dc=example,dc=com", "secret")
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	authMiddleware, err := NewBasic(context.Background(), next, dynamic.BasicAuth{
+		// The local users take precedence over LDAP.
+		Users: []string{"john:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"},
+		LDAP: &dynamic.LDAPAuth{
+			URL:    "ldap://" + server.listener.Addr().String(),
+			UserDN: "uid={username},dc=example,dc=com",
+		},
+	}, "authName")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		user         string
+		password     string
+		expectedCode int
+	}{
+		{user: "jane", password: "secret", expectedCode: http.StatusOK},
+		{user: "jane", password: "wrong", expectedCode: http.StatusUnauthorized},
+		{user: "john", password: "test", expectedCode: http.StatusOK},
+		{user: "john", password: "secret", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.SetBasicAuth(test.user, test.password)
+
+		rw := httptest.NewRecorder()
+		authMiddleware.ServeHTTP(rw, req)
+
+		assert.Equal(t, test.expectedCode, rw.Code, "%s:%s", test.user, test.password)
+	}
+
+	// John is not looked up in LDAP, as he is a local user.
+	assert.Equal(t, 2, server.bindCount())
+}
+
+func TestEscapeDN(t *testing.T) {
+	assert.Equal(t, `jane`, escapeDN("jane"))
+	assert.Equal(t, `\,jane\+\"\\\<\>\;\=`, escapeDN(`,jane+"\<>;=`))
+	assert.Equal(t, `\23jane\20`, escapeDN("#jane "))
+	assert.Equal(t, `\20jane\00`, escapeDN(" jane\x00"))
+}
+
+func TestBER(t *testing.T) {
+	for _, value := range []int64{0, 1, 127, 128, 255, 256, 65535, -1, -128, -129} {
+		tag, encoded, rest, err := berNext(berEncodeInteger(berInteger, value))
+		require.NoError(t, err)
+		assert.Equal(t, byte(berInteger), tag)
+		assert.Empty(t, rest)
+		assert.Equal(t, value, berInt(encoded), value)
+	}
+
+	long := make([]byte, 300)
+	tag, value, _, err := berNext(berEncode(berOctetString, long))
+	require.NoError(t, err)
+	assert.Equal(t, byte(berOctetString), tag)
+	assert.Len(t, value, 300)
+
+	_, _, _, err = berNext([]byte{berOctetString, 0x05, 0x01})
+	assert.Error(t, err)
+}
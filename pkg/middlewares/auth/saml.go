@@ -0,0 +1,779 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	samlTypeName = "SAMLAuth"
+
+	samlAssertionNS = "urn:oasis:names:tc:SAML:2.0:assertion"
+	samlProtocolNS  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	samlMetadataNS  = "urn:oasis:names:tc:SAML:2.0:metadata"
+
+	samlHTTPRedirectBinding = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlHTTPPostBinding     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	samlStatusSuccess       = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	samlBearer              = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+
+	defaultACSPath         = "/saml/acs"
+	defaultMetadataPath    = "/saml/metadata"
+	defaultSessionDuration = 8 * time.Hour
+
+	samlSessionCookieName = "traefik_saml_session"
+	samlStateCookieName   = "traefik_saml_state"
+
+	// samlStateTTL is the time given to the users to log in at the identity provider.
+	samlStateTTL = 10 * time.Minute
+
+	// maxClockSkew is the tolerated difference between the clocks of Traefik and of the identity provider.
+	maxClockSkew = 90 * time.Second
+)
+
+var (
+	// samlSessionKey signs the session and state cookies, when no session secret is configured.
+	// It is shared by the middlewares, so that the sessions survive the configuration reloads,
+	// but the users have to log in again when Traefik restarts.
+	samlSessionKey     []byte
+	samlSessionKeyOnce sync.Once
+	samlSessionKeyErr  error
+)
+
+// samlIdentityProvider is the subset of the metadata of the identity provider used by the authentication.
+type samlIdentityProvider struct {
+	entityID string
+	ssoURL   string
+	certs    []*x509.Certificate
+}
+
+type samlAuth struct {
+	next http.Handler
+	name string
+
+	entityID          string
+	idp               samlIdentityProvider
+	acsPath           string
+	metadataPath      string
+	nameIDHeader      string
+	attributeHeaders  map[string]string
+	allowIDPInitiated bool
+	sessionDuration   time.Duration
+	sessionKey        []byte
+
+	// assertions holds the IDs of the consumed assertions until their expiration, to prevent their replay.
+	mu         sync.Mutex
+	assertions map[string]time.Time
+}
+
+// NewSAML creates a SAML service provider authentication middleware.
+func NewSAML(ctx context.Context, next http.Handler, config dynamic.SAML, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, samlTypeName)).Debug("Creating middleware")
+
+	if config.EntityID == "" {
+		return nil, errors.New("the entity ID of the service provider is required")
+	}
+
+	metadata := []byte(config.IDPMetadata)
+	if config.IDPMetadataFile != "" {
+		var err error
+		metadata, err = ioutil.ReadFile(config.IDPMetadataFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the metadata of the identity provider: %w", err)
+		}
+	}
+
+	idp, err := parseIDPMetadata(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata of the identity provider: %w", err)
+	}
+
+	sessionKey, err := newSAMLSessionKey(config.SessionSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	sa := &samlAuth{
+		next:              next,
+		name:              name,
+		entityID:          config.EntityID,
+		idp:               *idp,
+		acsPath:           config.ACSPath,
+		metadataPath:      config.MetadataPath,
+		nameIDHeader:      config.NameIDHeader,
+		attributeHeaders:  config.AttributeHeaders,
+		allowIDPInitiated: config.AllowIDPInitiated,
+		sessionDuration:   time.Duration(config.SessionDuration),
+		sessionKey:        sessionKey,
+		assertions:        make(map[string]time.Time),
+	}
+
+	if sa.acsPath == "" {
+		sa.acsPath = defaultACSPath
+	}
+	if sa.metadataPath == "" {
+		sa.metadataPath = defaultMetadataPath
+	}
+	if sa.sessionDuration <= 0 {
+		sa.sessionDuration = defaultSessionDuration
+	}
+
+	return sa, nil
+}
+
+func newSAMLSessionKey(secret string) ([]byte, error) {
+	if secret != "" {
+		key := sha256.Sum256([]byte(secret))
+		return key[:], nil
+	}
+
+	samlSessionKeyOnce.Do(func() {
+		samlSessionKey = make([]byte, 32)
+		_, samlSessionKeyErr = rand.Read(samlSessionKey)
+	})
+
+	return samlSessionKey, samlSessionKeyErr
+}
+
+func (s *samlAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *samlAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, samlTypeName))
+
+	switch req.URL.Path {
+	case s.acsPath:
+		s.consumeAssertion(rw, req)
+		return
+	case s.metadataPath:
+		s.serveMetadata(rw, req)
+		return
+	}
+
+	// The headers set from the session are removed from the requests, so that the clients cannot forge them.
+	if s.nameIDHeader != "" {
+		req.Header.Del(s.nameIDHeader)
+	}
+	for _, header := range s.attributeHeaders {
+		req.Header.Del(header)
+	}
+
+	if session, ok := s.session(req, time.Now()); ok {
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = session.NameID
+		}
+
+		for header, value := range session.Headers {
+			req.Header.Set(header, value)
+		}
+
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The requests other than the page loads cannot be resumed after the login.
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		logger.Debug("Authentication failed")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	s.login(rw, req)
+}
+
+// login redirects the browser to the identity provider with an authentication request (SP-initiated flow).
+func (s *samlAuth) login(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, samlTypeName))
+
+	requestID, err := samlRandomID()
+	if err != nil {
+		logger.Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	relayState, err := samlRandomID()
+	if err != nil {
+		logger.Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	authnRequest, err := s.authnRequest(requestID, s.acsURL(req), time.Now())
+	if err != nil {
+		logger.Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The state cookie binds the response of the identity provider to the browser,
+	// and holds the ID of the authentication request and the page to go back to.
+	payload := relayState + "|" + requestID + "|" + strconv.FormatInt(time.Now().Add(samlStateTTL).Unix(), 10) + "|" + req.URL.RequestURI()
+
+	// The response of the identity provider is a cross-site POST request,
+	// which only gets the cookies allowing it.
+	var sameSite http.SameSite
+	if req.TLS != nil {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     samlStateCookieName,
+		Value:    s.sign(base64.RawURLEncoding.EncodeToString([]byte(payload))),
+		Path:     s.acsPath,
+		MaxAge:   int(samlStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: sameSite,
+	})
+
+	query := url.Values{
+		"SAMLRequest": {authnRequest},
+		"RelayState":  {relayState},
+	}
+
+	separator := "?"
+	if strings.Contains(s.idp.ssoURL, "?") {
+		separator = "&"
+	}
+
+	http.Redirect(rw, req, s.idp.ssoURL+separator+query.Encode(), http.StatusFound)
+}
+
+// authnRequest returns the authentication request, encoded for the HTTP-Redirect binding.
+func (s *samlAuth) authnRequest(requestID, acsURL string, now time.Time) (string, error) {
+	var request bytes.Buffer
+	request.WriteString(`<samlp:AuthnRequest xmlns:samlp="` + samlProtocolNS + `" xmlns:saml="` + samlAssertionNS + `"`)
+	request.WriteString(` ID="` + requestID + `" Version="2.0" IssueInstant="` + now.UTC().Format(time.RFC3339) + `"`)
+	request.WriteString(` Destination="` + xmlEscape(s.idp.ssoURL) + `" AssertionConsumerServiceURL="` + xmlEscape(acsURL) + `"`)
+	request.WriteString(` ProtocolBinding="` + samlHTTPPostBinding + `">`)
+	request.WriteString(`<saml:Issuer>` + xmlEscape(s.entityID) + `</saml:Issuer>`)
+	request.WriteString(`<samlp:NameIDPolicy AllowCreate="true"/>`)
+	request.WriteString(`</samlp:AuthnRequest>`)
+
+	var deflated bytes.Buffer
+	writer, err := flate.NewWriter(&deflated, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = writer.Write(request.Bytes()); err != nil {
+		return "", err
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(deflated.Bytes()), nil
+}
+
+// consumeAssertion handles the responses of the identity provider, posted to the assertion consumer service,
+// and opens the session of the user.
+func (s *samlAuth) consumeAssertion(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, samlTypeName))
+
+	if req.Method != http.MethodPost {
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	encoded := req.PostFormValue("SAMLResponse")
+	if encoded == "" {
+		http.Error(rw, "missing SAML response", http.StatusBadRequest)
+		return
+	}
+
+	response, err := decodeBase64(encoded)
+	if err != nil {
+		http.Error(rw, "invalid SAML response", http.StatusBadRequest)
+		return
+	}
+
+	// Without a valid state cookie for the relay state, the response is unsolicited (IdP-initiated flow).
+	var requestID string
+	redirect := "/"
+	if parts, ok := s.state(req, req.PostFormValue("RelayState"), time.Now()); ok {
+		requestID = parts[1]
+		redirect = parts[3]
+	} else if !s.allowIDPInitiated {
+		logger.Debug("Unsolicited SAML response")
+		http.Error(rw, "invalid state", http.StatusBadRequest)
+		return
+	} else if relayState := req.PostFormValue("RelayState"); relayState != "" {
+		redirect = relayState
+	}
+
+	session, err := s.verifyResponse(response, requestID, s.acsURL(req), time.Now())
+	if err != nil {
+		logger.Debugf("Invalid SAML response: %v", err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	value, err := s.newSession(session)
+	if err != nil {
+		logger.Error(err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(rw, &http.Cookie{Name: samlStateCookieName, Path: s.acsPath, MaxAge: -1})
+	http.SetCookie(rw, &http.Cookie{
+		Name:     samlSessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  time.Unix(session.ExpiresAt, 0),
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(rw, req, samlSafeRedirect(redirect), http.StatusFound)
+}
+
+// serveMetadata serves the metadata of the service provider, to register it at the identity provider.
+func (s *samlAuth) serveMetadata(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Content-Type", "application/samlmetadata+xml")
+
+	_, _ = fmt.Fprintf(rw, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<md:EntityDescriptor xmlns:md="%s" entityID="%s">`+
+		`<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="%s">`+
+		`<md:AssertionConsumerService Binding="%s" Location="%s" index="0" isDefault="true"/>`+
+		`</md:SPSSODescriptor>`+
+		`</md:EntityDescriptor>`+"\n",
+		samlMetadataNS, xmlEscape(s.entityID), samlProtocolNS, samlHTTPPostBinding, xmlEscape(s.acsURL(req)))
+}
+
+// samlSession is the identity of an authenticated user, with the headers to set on its requests.
+type samlSession struct {
+	NameID    string            `json:"n"`
+	Headers   map[string]string `json:"h,omitempty"`
+	ExpiresAt int64             `json:"e"`
+}
+
+func (s *samlAuth) newSession(session *samlSession) (string, error) {
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	return s.sign(base64.RawURLEncoding.EncodeToString(payload)), nil
+}
+
+// session returns the session of the request, from its session cookie.
+func (s *samlAuth) session(req *http.Request, now time.Time) (*samlSession, bool) {
+	cookie, err := req.Cookie(samlSessionCookieName)
+	if err != nil {
+		return nil, false
+	}
+
+	value, ok := s.verify(cookie.Value)
+	if !ok {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, false
+	}
+
+	var session samlSession
+	if err = json.Unmarshal(payload, &session); err != nil || now.After(time.Unix(session.ExpiresAt, 0)) {
+		return nil, false
+	}
+
+	return &session, true
+}
+
+// state returns the parts of the state cookie of the request, if it matches the given relay state.
+func (s *samlAuth) state(req *http.Request, relayState string, now time.Time) ([]string, bool) {
+	cookie, err := req.Cookie(samlStateCookieName)
+	if err != nil || relayState == "" {
+		return nil, false
+	}
+
+	value, ok := s.verify(cookie.Value)
+	if !ok {
+		return nil, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	parts := strings.SplitN(string(payload), "|", 4)
+	if err != nil || len(parts) != 4 || parts[0] != relayState {
+		return nil, false
+	}
+
+	if expiresAt, _ := strconv.ParseInt(parts[2], 10, 64); now.After(time.Unix(expiresAt, 0)) {
+		return nil, false
+	}
+
+	return parts, true
+}
+
+// sign returns the given payload followed by its signature.
+// The signature covers the name of the middleware, so that a cookie is only accepted by the middleware which issued it.
+func (s *samlAuth) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.sessionKey)
+	_, _ = mac.Write([]byte(s.name + "|" + s.entityID + "|" + payload))
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns the payload of the given signed value, if its signature is valid.
+func (s *samlAuth) verify(value string) (string, bool) {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return "", false
+	}
+
+	payload := value[:i]
+	if !hmac.Equal([]byte(s.sign(payload)), []byte(value)) {
+		return "", false
+	}
+
+	return payload, true
+}
+
+// verifyResponse verifies the given SAML response, answering the given authentication request,
+// or unsolicited when the request ID is empty, and returns the session of the authenticated user.
+func (s *samlAuth) verifyResponse(data []byte, requestID, acsURL string, now time.Time) (*samlSession, error) {
+	response, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.is(samlProtocolNS, "Response") {
+		return nil, fmt.Errorf("unexpected %s element", response.name.Local)
+	}
+
+	status, err := response.element(samlProtocolNS, "Status")
+	if err != nil {
+		return nil, err
+	}
+
+	statusCode, err := status.element(samlProtocolNS, "StatusCode")
+	if err != nil {
+		return nil, err
+	}
+
+	if value := statusCode.attr("Value"); value != samlStatusSuccess {
+		return nil, fmt.Errorf("authentication failed with the status %q", value)
+	}
+
+	if destination := response.attr("Destination"); destination != "" && destination != acsURL {
+		return nil, fmt.Errorf("unexpected destination %q", destination)
+	}
+
+	if len(response.elements(samlAssertionNS, "EncryptedAssertion")) > 0 {
+		return nil, errors.New("the encrypted assertions are not supported")
+	}
+
+	assertionNode, err := response.element(samlAssertionNS, "Assertion")
+	if err != nil {
+		return nil, err
+	}
+
+	// Either the response or the assertion is signed.
+	// The assertion is read from the verified tree, so that no unsigned element can be substituted to the signed one.
+	responseErr := verifySignature(response, s.idp.certs)
+	if responseErr != nil && !errors.Is(responseErr, errNotSigned) {
+		return nil, fmt.Errorf("invalid signature of the response: %w", responseErr)
+	}
+
+	assertionErr := verifySignature(assertionNode, s.idp.certs)
+	if assertionErr != nil && !errors.Is(assertionErr, errNotSigned) {
+		return nil, fmt.Errorf("invalid signature of the assertion: %w", assertionErr)
+	}
+
+	if responseErr != nil && assertionErr != nil {
+		return nil, errors.New("neither the response nor the assertion is signed")
+	}
+
+	var excluded *xmlNode
+	if signatures := assertionNode.elements(xmldsigNS, "Signature"); len(signatures) > 0 {
+		excluded = signatures[0]
+	}
+
+	var assertion samlAssertion
+	if err = xml.Unmarshal(canonicalize(assertionNode, excluded, nil), &assertion); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := s.validateAssertion(&assertion, requestID, acsURL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.consume(assertion.ID, expiresAt, now); err != nil {
+		return nil, err
+	}
+
+	session := &samlSession{NameID: assertion.Subject.NameID, Headers: make(map[string]string)}
+	if s.nameIDHeader != "" {
+		session.Headers[s.nameIDHeader] = assertion.Subject.NameID
+	}
+
+	for _, attribute := range assertion.Attributes {
+		if header, ok := s.attributeHeaders[attribute.Name]; ok {
+			session.Headers[header] = strings.Join(attribute.Values, ",")
+		}
+	}
+
+	sessionExpiresAt := now.Add(s.sessionDuration)
+	if end := assertion.AuthnStatement.SessionNotOnOrAfter; !end.IsZero() && end.Before(sessionExpiresAt) {
+		sessionExpiresAt = end
+	}
+	session.ExpiresAt = sessionExpiresAt.Unix()
+
+	return session, nil
+}
+
+// samlAssertion is the subset of a SAML assertion used by the authentication.
+type samlAssertion struct {
+	ID      string `xml:"ID,attr"`
+	Issuer  string `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	Subject struct {
+		NameID        string `xml:"urn:oasis:names:tc:SAML:2.0:assertion NameID"`
+		Confirmations []struct {
+			Method string `xml:"Method,attr"`
+			Data   struct {
+				Recipient    string    `xml:"Recipient,attr"`
+				InResponseTo string    `xml:"InResponseTo,attr"`
+				NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+			} `xml:"urn:oasis:names:tc:SAML:2.0:assertion SubjectConfirmationData"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:assertion SubjectConfirmation"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Subject"`
+	Conditions *struct {
+		NotBefore    time.Time `xml:"NotBefore,attr"`
+		NotOnOrAfter time.Time `xml:"NotOnOrAfter,attr"`
+		Audiences    []string  `xml:"urn:oasis:names:tc:SAML:2.0:assertion AudienceRestriction>Audience"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Conditions"`
+	AuthnStatement struct {
+		SessionNotOnOrAfter time.Time `xml:"SessionNotOnOrAfter,attr"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion AuthnStatement"`
+	Attributes []struct {
+		Name   string   `xml:"Name,attr"`
+		Values []string `xml:"urn:oasis:names:tc:SAML:2.0:assertion AttributeValue"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:assertion AttributeStatement>Attribute"`
+}
+
+// validateAssertion checks the assertion is issued by the identity provider, for the service provider,
+// and is currently valid, and returns its expiration.
+func (s *samlAuth) validateAssertion(assertion *samlAssertion, requestID, acsURL string, now time.Time) (time.Time, error) {
+	if assertion.Issuer != s.idp.entityID {
+		return time.Time{}, fmt.Errorf("unexpected issuer %q", assertion.Issuer)
+	}
+
+	if assertion.ID == "" || assertion.Subject.NameID == "" {
+		return time.Time{}, errors.New("missing assertion ID or NameID")
+	}
+
+	if assertion.Conditions == nil {
+		return time.Time{}, errors.New("missing conditions")
+	}
+
+	conditions := assertion.Conditions
+	if !conditions.NotBefore.IsZero() && now.Add(maxClockSkew).Before(conditions.NotBefore) {
+		return time.Time{}, errors.New("the assertion is not yet valid")
+	}
+	if !conditions.NotOnOrAfter.IsZero() && !now.Add(-maxClockSkew).Before(conditions.NotOnOrAfter) {
+		return time.Time{}, errors.New("the assertion is expired")
+	}
+
+	var audience bool
+	for _, value := range conditions.Audiences {
+		audience = audience || value == s.entityID
+	}
+	if !audience {
+		return time.Time{}, fmt.Errorf("the assertion is not intended for %q", s.entityID)
+	}
+
+	for _, confirmation := range assertion.Subject.Confirmations {
+		data := confirmation.Data
+		if confirmation.Method != samlBearer || data.Recipient != acsURL || data.InResponseTo != requestID {
+			continue
+		}
+
+		if data.NotOnOrAfter.IsZero() || !now.Add(-maxClockSkew).Before(data.NotOnOrAfter) {
+			continue
+		}
+
+		return data.NotOnOrAfter.Add(maxClockSkew), nil
+	}
+
+	return time.Time{}, errors.New("no valid bearer subject confirmation")
+}
+
+// consume records the use of the assertion, and fails if it has already been used.
+func (s *samlAuth) consume(id string, expiresAt, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for assertionID, assertionExpiresAt := range s.assertions {
+		if now.After(assertionExpiresAt) {
+			delete(s.assertions, assertionID)
+		}
+	}
+
+	if _, ok := s.assertions[id]; ok {
+		return fmt.Errorf("the assertion %q has already been used", id)
+	}
+
+	s.assertions[id] = expiresAt
+
+	return nil
+}
+
+// acsURL returns the URL of the assertion consumer service, on the host used by the request.
+func (s *samlAuth) acsURL(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + req.Host + s.acsPath
+}
+
+type samlEntityDescriptor struct {
+	EntityID          string `xml:"entityID,attr"`
+	IDPSSODescriptors []struct {
+		KeyDescriptors []struct {
+			Use          string   `xml:"use,attr"`
+			Certificates []string `xml:"http://www.w3.org/2000/09/xmldsig# KeyInfo>X509Data>X509Certificate"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleSignOnService"`
+	} `xml:"urn:oasis:names:tc:SAML:2.0:metadata IDPSSODescriptor"`
+}
+
+// parseIDPMetadata returns the identity provider described by the given metadata,
+// which is either an EntityDescriptor, or an EntitiesDescriptor holding it.
+func parseIDPMetadata(data []byte) (*samlIdentityProvider, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var descriptors []samlEntityDescriptor
+	switch root.XMLName {
+	case xml.Name{Space: samlMetadataNS, Local: "EntitiesDescriptor"}:
+		var entities struct {
+			EntityDescriptors []samlEntityDescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+		}
+		if err := xml.Unmarshal(data, &entities); err != nil {
+			return nil, err
+		}
+		descriptors = entities.EntityDescriptors
+
+	case xml.Name{Space: samlMetadataNS, Local: "EntityDescriptor"}:
+		var entity samlEntityDescriptor
+		if err := xml.Unmarshal(data, &entity); err != nil {
+			return nil, err
+		}
+		descriptors = append(descriptors, entity)
+
+	default:
+		return nil, fmt.Errorf("unexpected %s element", root.XMLName.Local)
+	}
+
+	for _, descriptor := range descriptors {
+		if len(descriptor.IDPSSODescriptors) == 0 {
+			continue
+		}
+
+		idp := &samlIdentityProvider{entityID: descriptor.EntityID}
+		for _, sso := range descriptor.IDPSSODescriptors[0].SingleSignOnServices {
+			if sso.Binding == samlHTTPRedirectBinding {
+				idp.ssoURL = sso.Location
+				break
+			}
+		}
+
+		for _, key := range descriptor.IDPSSODescriptors[0].KeyDescriptors {
+			if key.Use == "encryption" {
+				continue
+			}
+
+			for _, value := range key.Certificates {
+				der, err := decodeBase64(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid certificate: %w", err)
+				}
+
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					return nil, fmt.Errorf("invalid certificate: %w", err)
+				}
+
+				idp.certs = append(idp.certs, cert)
+			}
+		}
+
+		if idp.entityID == "" || idp.ssoURL == "" || len(idp.certs) == 0 {
+			return nil, errors.New("the identity provider needs an entity ID, an HTTP-Redirect single sign-on service, and a signing certificate")
+		}
+
+		return idp, nil
+	}
+
+	return nil, errors.New("no identity provider")
+}
+
+// samlRandomID returns a random identifier, valid as an XML ID.
+func samlRandomID() (string, error) {
+	value := make([]byte, 20)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+
+	return "_" + hex.EncodeToString(value), nil
+}
+
+// samlSafeRedirect returns the given URI if it is a local path, to prevent open redirects.
+func samlSafeRedirect(uri string) string {
+	if !strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "//") || strings.HasPrefix(uri, "/\\") {
+		return "/"
+	}
+
+	return uri
+}
+
+func xmlEscape(value string) string {
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(value))
+
+	return escaped.String()
+}
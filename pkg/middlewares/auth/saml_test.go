@@ -0,0 +1,556 @@
+package auth
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const (
+	samlTestIDP    = "https://idp.example.com/metadata"
+	samlTestSP     = "https://app.example.com/saml"
+	samlTestSSOURL = "https://idp.example.com/sso"
+	samlTestACSURL = "http://app.example.com/saml/acs"
+
+	// The placeholders mark where the signatures of the elements are inserted.
+	samlAssertionSignature = "<!--assertion signature-->"
+	samlResponseSignature  = "<!--response signature-->"
+)
+
+// samlTestAssertion describes a SAML response holding an assertion.
+type samlTestAssertion struct {
+	issuer       string
+	audience     string
+	recipient    string
+	inResponseTo string
+	nameID       string
+	notOnOrAfter time.Time
+	signResponse bool
+	signAssert   bool
+}
+
+func newSAMLTestAssertion(inResponseTo string) samlTestAssertion {
+	return samlTestAssertion{
+		issuer:       samlTestIDP,
+		audience:     samlTestSP,
+		recipient:    samlTestACSURL,
+		inResponseTo: inResponseTo,
+		nameID:       "jane@example.com",
+		notOnOrAfter: time.Now().Add(5 * time.Minute),
+		signAssert:   true,
+	}
+}
+
+// response returns the XML of the response, signed with the given key.
+func (a samlTestAssertion) response(t *testing.T, key *rsa.PrivateKey, assertionID string) string {
+	t.Helper()
+
+	inResponseTo := ""
+	if a.inResponseTo != "" {
+		inResponseTo = ` InResponseTo="` + a.inResponseTo + `"`
+	}
+
+	assertion := fmt.Sprintf(`<saml:Assertion xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ID="%[1]s" Version="2.0" IssueInstant="%[2]s">`+
+		`<saml:Issuer>%[3]s</saml:Issuer>%[9]s`+
+		`<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">%[4]s</saml:NameID>`+
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer"><saml:SubjectConfirmationData%[5]s NotOnOrAfter="%[6]s" Recipient="%[7]s"/></saml:SubjectConfirmation></saml:Subject>`+
+		`<saml:Conditions NotBefore="%[2]s" NotOnOrAfter="%[6]s"><saml:AudienceRestriction><saml:Audience>%[8]s</saml:Audience></saml:AudienceRestriction></saml:Conditions>`+
+		`<saml:AuthnStatement AuthnInstant="%[2]s" SessionIndex="_session"/>`+
+		"<saml:AttributeStatement>\n"+
+		`  <saml:Attribute Name="groups"><saml:AttributeValue xsi:type="xs:string">admins</saml:AttributeValue><saml:AttributeValue xsi:type="xs:string">users</saml:AttributeValue></saml:Attribute>`+"\n"+
+		`  <saml:Attribute Name="displayName"><saml:AttributeValue xsi:type="xs:string">Jane &amp; Co</saml:AttributeValue></saml:Attribute>`+"\n"+
+		`</saml:AttributeStatement></saml:Assertion>`,
+		assertionID, time.Now().Add(-time.Minute).UTC().Format(time.RFC3339), a.issuer, a.nameID, inResponseTo,
+		a.notOnOrAfter.UTC().Format(time.RFC3339), a.recipient, a.audience, samlAssertionSignature)
+
+	response := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_response" Version="2.0" IssueInstant="%s" Destination="%s"%s>`+
+		`<saml:Issuer>%s</saml:Issuer>%s`+
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>%s</samlp:Response>`,
+		time.Now().UTC().Format(time.RFC3339), samlTestACSURL, inResponseTo, a.issuer, samlResponseSignature, assertion)
+
+	// The assertion is signed first, as its signature is covered by the signature of the response.
+	if a.signAssert {
+		response = signSAMLTestElement(t, key, response, assertionID, samlAssertionSignature)
+	}
+
+	if a.signResponse {
+		response = signSAMLTestElement(t, key, response, "_response", samlResponseSignature)
+	}
+
+	return response
+}
+
+// signSAMLTestElement replaces the given placeholder of the document by the enveloped signature of the element with the given ID.
+func signSAMLTestElement(t *testing.T, key *rsa.PrivateKey, document, id, placeholder string) string {
+	t.Helper()
+
+	root, err := parseXML([]byte(document))
+	require.NoError(t, err)
+
+	element := findSAMLTestElement(root, id)
+	require.NotNil(t, element)
+
+	digest := sha256.Sum256(canonicalize(element, nil, []string{"xs"}))
+
+	signedInfo := `<ds:SignedInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` +
+		`<ds:CanonicalizationMethod Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"/>` +
+		`<ds:SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"/>` +
+		`<ds:Reference URI="#` + id + `"><ds:Transforms>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"/>` +
+		`<ds:Transform Algorithm="http://www.w3.org/2001/10/xml-exc-c14n#"><ec:InclusiveNamespaces xmlns:ec="http://www.w3.org/2001/10/xml-exc-c14n#" PrefixList="xs"/></ds:Transform>` +
+		`</ds:Transforms><ds:DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"/>` +
+		`<ds:DigestValue>` + base64.StdEncoding.EncodeToString(digest[:]) + `</ds:DigestValue></ds:Reference></ds:SignedInfo>`
+
+	signedInfoNode, err := parseXML([]byte(signedInfo))
+	require.NoError(t, err)
+
+	hashed := sha256.Sum256(canonicalize(signedInfoNode, nil, nil))
+	value, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+
+	signature := `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#">` + signedInfo +
+		"<ds:SignatureValue>\n" + base64.StdEncoding.EncodeToString(value) + "\n</ds:SignatureValue></ds:Signature>"
+
+	return strings.Replace(document, placeholder, signature, 1)
+}
+
+func findSAMLTestElement(n *xmlNode, id string) *xmlNode {
+	if n.attr("ID") == id {
+		return n
+	}
+
+	for _, child := range n.children {
+		if node, ok := child.(*xmlNode); ok {
+			if found := findSAMLTestElement(node, id); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+func generateSAMLTestKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return key, base64.StdEncoding.EncodeToString(raw)
+}
+
+func samlTestMetadata(cert string) string {
+	return `<?xml version="1.0"?>
+<md:EntitiesDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+  <md:EntityDescriptor entityID="https://sp.example.com">
+    <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol"/>
+  </md:EntityDescriptor>
+  <md:EntityDescriptor entityID="` + samlTestIDP + `">
+    <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+      <md:KeyDescriptor use="signing">
+        <ds:KeyInfo><ds:X509Data><ds:X509Certificate>
+          ` + cert + `
+        </ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+      </md:KeyDescriptor>
+      <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso/post"/>
+      <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="` + samlTestSSOURL + `"/>
+    </md:IDPSSODescriptor>
+  </md:EntityDescriptor>
+</md:EntitiesDescriptor>`
+}
+
+func newSAMLTestMiddleware(t *testing.T, cert string, allowIDPInitiated bool) *samlAuth {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, "%s|%s|%s", req.Header.Get("X-Auth-User"), req.Header.Get("X-Auth-Groups"), req.Header.Get("X-Auth-Name"))
+	})
+
+	middleware, err := NewSAML(context.Background(), next, dynamic.SAML{
+		EntityID:          samlTestSP,
+		IDPMetadata:       samlTestMetadata(cert),
+		NameIDHeader:      "X-Auth-User",
+		AttributeHeaders:  map[string]string{"groups": "X-Auth-Groups", "displayName": "X-Auth-Name"},
+		AllowIDPInitiated: allowIDPInitiated,
+	}, "saml@file")
+	require.NoError(t, err)
+
+	return middleware.(*samlAuth)
+}
+
+func TestSAML_spInitiated(t *testing.T) {
+	key, cert := generateSAMLTestKey(t)
+	middleware := newSAMLTestMiddleware(t, cert, false)
+
+	// The unauthenticated page loads are redirected to the identity provider.
+	rw := httptest.NewRecorder()
+	middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://app.example.com/foo?bar=baz", nil))
+	require.Equal(t, http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, samlTestSSOURL, location.Scheme+"://"+location.Host+location.Path)
+
+	deflated, err := base64.StdEncoding.DecodeString(location.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+
+	request, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(deflated)))
+	require.NoError(t, err)
+
+	authnRequest, err := parseXML(request)
+	require.NoError(t, err)
+	assert.True(t, authnRequest.is(samlProtocolNS, "AuthnRequest"))
+	assert.Equal(t, samlTestACSURL, authnRequest.attr("AssertionConsumerServiceURL"))
+
+	issuer, err := authnRequest.element(samlAssertionNS, "Issuer")
+	require.NoError(t, err)
+	assert.Equal(t, samlTestSP, issuer.text())
+
+	stateCookies := rw.Result().Cookies()
+	require.Len(t, stateCookies, 1)
+
+	// The identity provider posts the response to the assertion consumer service.
+	response := newSAMLTestAssertion(authnRequest.attr("ID")).response(t, key, "_assertion")
+
+	form := url.Values{
+		"SAMLResponse": {base64.StdEncoding.EncodeToString([]byte(response))},
+		"RelayState":   {location.Query().Get("RelayState")},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, samlTestACSURL, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(stateCookies[0])
+
+	rw = httptest.NewRecorder()
+	middleware.ServeHTTP(rw, req)
+	require.Equal(t, http.StatusFound, rw.Code)
+	assert.Equal(t, "/foo?bar=baz", rw.Header().Get("Location"))
+
+	var session *http.Cookie
+	for _, cookie := range rw.Result().Cookies() {
+		if cookie.Name == samlSessionCookieName {
+			session = cookie
+		}
+	}
+	require.NotNil(t, session)
+
+	// The session sets the headers of the requests, replacing the forged ones.
+	req = httptest.NewRequest(http.MethodGet, "http://app.example.com/foo", nil)
+	req.Header.Set("X-Auth-User", "admin@example.com")
+	req.AddCookie(session)
+
+	rw = httptest.NewRecorder()
+	middleware.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "jane@example.com|admins,users|Jane & Co", rw.Body.String())
+
+	// The response cannot be replayed.
+	req = httptest.NewRequest(http.MethodPost, samlTestACSURL, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(stateCookies[0])
+
+	rw = httptest.NewRecorder()
+	middleware.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+}
+
+func TestSAML_idpInitiated(t *testing.T) {
+	key, cert := generateSAMLTestKey(t)
+
+	testCases := []struct {
+		desc              string
+		allowIDPInitiated bool
+		expectedStatus    int
+	}{
+		{
+			desc:              "allowed",
+			allowIDPInitiated: true,
+			expectedStatus:    http.StatusFound,
+		},
+		{
+			desc:           "not allowed",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			middleware := newSAMLTestMiddleware(t, cert, test.allowIDPInitiated)
+
+			form := url.Values{
+				"SAMLResponse": {base64.StdEncoding.EncodeToString([]byte(newSAMLTestAssertion("").response(t, key, "_assertion")))},
+				"RelayState":   {"/dashboard"},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, samlTestACSURL, strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			rw := httptest.NewRecorder()
+			middleware.ServeHTTP(rw, req)
+			require.Equal(t, test.expectedStatus, rw.Code)
+
+			if test.expectedStatus == http.StatusFound {
+				assert.Equal(t, "/dashboard", rw.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestSAML_unauthenticated(t *testing.T) {
+	_, cert := generateSAMLTestKey(t)
+	middleware := newSAMLTestMiddleware(t, cert, false)
+
+	rw := httptest.NewRecorder()
+	middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "http://app.example.com/foo", nil))
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+
+	rw = httptest.NewRecorder()
+	middleware.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://app.example.com/saml/metadata", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `entityID="`+samlTestSP+`"`)
+	assert.Contains(t, rw.Body.String(), `Location="`+samlTestACSURL+`"`)
+}
+
+func TestSAML_verifyResponse(t *testing.T) {
+	key, cert := generateSAMLTestKey(t)
+	otherKey, _ := generateSAMLTestKey(t)
+
+	testCases := []struct {
+		desc        string
+		response    func(t *testing.T) string
+		expectedErr bool
+	}{
+		{
+			desc: "signed assertion",
+			response: func(t *testing.T) string {
+				t.Helper()
+				return newSAMLTestAssertion("_request").response(t, key, "_assertion")
+			},
+		},
+		{
+			desc: "signed response",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.signAssert = false
+				assertion.signResponse = true
+				return assertion.response(t, key, "_assertion")
+			},
+		},
+		{
+			desc: "signed response and assertion",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.signResponse = true
+				return assertion.response(t, key, "_assertion")
+			},
+		},
+		{
+			desc: "not signed",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.signAssert = false
+				return assertion.response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "signed by another key",
+			response: func(t *testing.T) string {
+				t.Helper()
+				return newSAMLTestAssertion("_request").response(t, otherKey, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "tampered assertion",
+			response: func(t *testing.T) string {
+				t.Helper()
+				response := newSAMLTestAssertion("_request").response(t, key, "_assertion")
+				return strings.Replace(response, "jane@example.com", "admin@example.com", 1)
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "wrapped assertion",
+			response: func(t *testing.T) string {
+				t.Helper()
+				response := newSAMLTestAssertion("_request").response(t, key, "_assertion")
+				forged := strings.Replace(response, "jane@example.com", "admin@example.com", 1)
+
+				// The forged assertion is placed before the signed one, which is moved under the Status element.
+				i := strings.Index(forged, "<saml:Assertion ")
+				j := strings.Index(response, "<saml:Assertion ")
+				return strings.Replace(forged[:i], "</samlp:Status>", response[j:strings.LastIndex(response, "</samlp:Response>")]+"</samlp:Status>", 1) + forged[i:]
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "wrong issuer",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.issuer = "https://evil.example.com"
+				return assertion.response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "wrong audience",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.audience = "https://other.example.com"
+				return assertion.response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "wrong recipient",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.recipient = "https://other.example.com/saml/acs"
+				return assertion.response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "answer to another request",
+			response: func(t *testing.T) string {
+				t.Helper()
+				return newSAMLTestAssertion("_other").response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "expired",
+			response: func(t *testing.T) string {
+				t.Helper()
+				assertion := newSAMLTestAssertion("_request")
+				assertion.notOnOrAfter = time.Now().Add(-5 * time.Minute)
+				return assertion.response(t, key, "_assertion")
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "reference to another element",
+			response: func(t *testing.T) string {
+				t.Helper()
+				response := newSAMLTestAssertion("_request").response(t, key, "_assertion")
+				return strings.Replace(response, `ID="_assertion"`, `ID="_other"`, 1)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			middleware := newSAMLTestMiddleware(t, cert, false)
+
+			session, err := middleware.verifyResponse([]byte(test.response(t)), "_request", samlTestACSURL, time.Now())
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "jane@example.com", session.NameID)
+			assert.Equal(t, map[string]string{
+				"X-Auth-User":   "jane@example.com",
+				"X-Auth-Groups": "admins,users",
+				"X-Auth-Name":   "Jane & Co",
+			}, session.Headers)
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		document  string
+		inclusive []string
+		expected  string
+	}{
+		{
+			desc:     "exclusive canonicalization specification example",
+			document: `<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`,
+			expected: `<n0:local xmlns:n0="foo:bar"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2></n0:local>`,
+		},
+		{
+			desc: "attributes, namespaces and escaping",
+			document: `<?xml version="1.0"?>
+<!-- comment -->
+<doc xmlns="urn:d" xmlns:a="urn:a" xmlns:b="urn:b" b:z="1" a:y="2" x="3&amp;&lt;&gt;&quot;&#9;&#10;"><e1   /><a:e2 xml:lang="fr"><e3 xmlns="">t&amp;x&gt;<![CDATA[<y>]]></e3></a:e2><b:e4 xmlns:b="urn:b"/><!-- comment --><e5 xmlns="urn:d"/></doc>`,
+			expected: `<doc xmlns="urn:d" xmlns:a="urn:a" xmlns:b="urn:b" x="3&amp;&lt;>&quot;&#x9;&#xA;" a:y="2" b:z="1"><e1></e1><a:e2 xml:lang="fr"><e3 xmlns="">t&amp;x&gt;&lt;y&gt;</e3></a:e2><b:e4></b:e4><e5></e5></doc>`,
+		},
+		{
+			desc:     "namespaces not visibly utilized",
+			document: `<saml:Assertion xmlns:saml="urn:a" xmlns:xs="urn:xs" xmlns:xsi="urn:xsi"><saml:AttributeValue xsi:type="xs:string">v</saml:AttributeValue></saml:Assertion>`,
+			expected: `<saml:Assertion xmlns:saml="urn:a"><saml:AttributeValue xmlns:xsi="urn:xsi" xsi:type="xs:string">v</saml:AttributeValue></saml:Assertion>`,
+		},
+		{
+			desc:      "inclusive namespaces",
+			document:  `<saml:Assertion xmlns:saml="urn:a" xmlns:xs="urn:xs" xmlns:xsi="urn:xsi"><saml:AttributeValue xsi:type="xs:string">v</saml:AttributeValue></saml:Assertion>`,
+			inclusive: []string{"xs"},
+			expected:  `<saml:Assertion xmlns:saml="urn:a" xmlns:xs="urn:xs"><saml:AttributeValue xmlns:xsi="urn:xsi" xsi:type="xs:string">v</saml:AttributeValue></saml:Assertion>`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			node, err := parseXML([]byte(test.document))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(canonicalize(node, nil, test.inclusive)))
+		})
+	}
+}
+
+func TestParseXML_DTD(t *testing.T) {
+	_, err := parseXML([]byte(`<!DOCTYPE foo [<!ENTITY bar "baz">]><foo>&bar;</foo>`))
+	assert.Error(t, err)
+}
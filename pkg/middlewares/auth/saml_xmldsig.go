@@ -0,0 +1,469 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	// Registers the hash functions of the supported signature algorithms.
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+const (
+	xmlNS               = "http://www.w3.org/XML/1998/namespace"
+	xmldsigNS           = "http://www.w3.org/2000/09/xmldsig#"
+	excC14NAlgorithm    = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	envelopedSignature  = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	inclusiveNamespaces = "InclusiveNamespaces"
+)
+
+var (
+	errNotSigned = errors.New("missing signature")
+
+	signatureAlgorithms = map[string]crypto.Hash{
+		"http://www.w3.org/2000/09/xmldsig#rsa-sha1":        crypto.SHA1,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+		"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+	}
+
+	digestAlgorithms = map[string]crypto.Hash{
+		"http://www.w3.org/2000/09/xmldsig#sha1":  crypto.SHA1,
+		"http://www.w3.org/2001/04/xmlenc#sha256": crypto.SHA256,
+		"http://www.w3.org/2001/04/xmlenc#sha512": crypto.SHA512,
+	}
+)
+
+// xmlNode is an element of a parsed XML document, keeping the namespace prefixes needed by the canonicalization.
+type xmlNode struct {
+	parent *xmlNode
+	name   xml.Name // The Space of the name is the prefix of the element.
+	attrs  []xml.Attr
+	// ns holds the namespaces declared by the element, by prefix, the default namespace having an empty prefix.
+	ns map[string]string
+	// children holds the child elements as *xmlNode, and the character data as string.
+	children []interface{}
+}
+
+// parseXML parses the given XML document.
+// The comments and processing instructions are dropped, and the documents with a DTD are rejected.
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root, current *xmlNode
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := token.(type) {
+		case xml.StartElement:
+			if current == nil && root != nil {
+				return nil, errors.New("multiple root elements")
+			}
+
+			node := &xmlNode{parent: current, name: tok.Name, ns: make(map[string]string)}
+			for _, attr := range tok.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					node.ns[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					node.ns[attr.Name.Local] = attr.Value
+				default:
+					node.attrs = append(node.attrs, attr)
+				}
+			}
+
+			if current == nil {
+				root = node
+			} else {
+				current.children = append(current.children, node)
+			}
+			current = node
+
+		case xml.EndElement:
+			if current == nil || current.name != tok.Name {
+				return nil, fmt.Errorf("unexpected end element %s", qualifiedName(tok.Name))
+			}
+			current = current.parent
+
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, string(tok))
+			}
+
+		case xml.Directive:
+			return nil, errors.New("DTDs are not supported")
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return root, nil
+}
+
+// lookupNamespace returns the namespace bound to the given prefix in the scope of the node.
+func (n *xmlNode) lookupNamespace(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNS, true
+	}
+
+	for node := n; node != nil; node = node.parent {
+		if uri, ok := node.ns[prefix]; ok {
+			return uri, true
+		}
+	}
+
+	return "", prefix == ""
+}
+
+// is reports whether the node is the given element.
+func (n *xmlNode) is(space, local string) bool {
+	uri, _ := n.lookupNamespace(n.name.Space)
+	return n.name.Local == local && uri == space
+}
+
+// attr returns the value of the given unqualified attribute.
+func (n *xmlNode) attr(local string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// elements returns the child elements matching the given name.
+func (n *xmlNode) elements(space, local string) []*xmlNode {
+	var elements []*xmlNode
+	for _, child := range n.children {
+		if node, ok := child.(*xmlNode); ok && node.is(space, local) {
+			elements = append(elements, node)
+		}
+	}
+
+	return elements
+}
+
+// element returns the only child element matching the given name.
+func (n *xmlNode) element(space, local string) (*xmlNode, error) {
+	elements := n.elements(space, local)
+	if len(elements) != 1 {
+		return nil, fmt.Errorf("expected one %s element in %s, got %d", local, n.name.Local, len(elements))
+	}
+
+	return elements[0], nil
+}
+
+// text returns the character data of the node.
+func (n *xmlNode) text() string {
+	var text strings.Builder
+	for _, child := range n.children {
+		if data, ok := child.(string); ok {
+			text.WriteString(data)
+		}
+	}
+
+	return text.String()
+}
+
+// canonicalize returns the exclusive XML canonicalization, without comments, of the node and its descendants,
+// except the excluded node (https://www.w3.org/TR/xml-exc-c14n/).
+// The inclusive prefixes are the prefixes of the InclusiveNamespaces PrefixList of the canonicalization,
+// where #default is the default namespace.
+func canonicalize(n, excluded *xmlNode, inclusivePrefixes []string) []byte {
+	c := &canonicalizer{excluded: excluded, inclusive: make(map[string]bool)}
+	for _, prefix := range inclusivePrefixes {
+		if prefix == "#default" {
+			prefix = ""
+		}
+		c.inclusive[prefix] = true
+	}
+
+	c.writeElement(n, map[string]string{})
+
+	return c.buf.Bytes()
+}
+
+type canonicalizer struct {
+	buf       bytes.Buffer
+	excluded  *xmlNode
+	inclusive map[string]bool
+}
+
+type canonicalAttr struct {
+	space string
+	name  string
+	value string
+}
+
+func (c *canonicalizer) writeElement(n *xmlNode, rendered map[string]string) {
+	// The namespaces are rendered when they are visibly utilized by the element or its attributes,
+	// or are inclusive, and not already rendered with the same value by an output ancestor.
+	used := map[string]bool{n.name.Space: true}
+	for _, attr := range n.attrs {
+		if attr.Name.Space != "" {
+			used[attr.Name.Space] = true
+		}
+	}
+
+	for prefix := range c.inclusive {
+		if _, ok := n.lookupNamespace(prefix); ok {
+			used[prefix] = true
+		}
+	}
+
+	var prefixes []string
+	scope := make(map[string]string, len(rendered))
+	for prefix, uri := range rendered {
+		scope[prefix] = uri
+	}
+
+	for prefix := range used {
+		if prefix == "xml" {
+			continue
+		}
+
+		uri, _ := n.lookupNamespace(prefix)
+		if previous, ok := rendered[prefix]; uri == previous && (ok || prefix == "") {
+			continue
+		}
+
+		scope[prefix] = uri
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Strings(prefixes)
+
+	attrs := make([]canonicalAttr, 0, len(n.attrs))
+	for _, attr := range n.attrs {
+		space := ""
+		if attr.Name.Space != "" {
+			space, _ = n.lookupNamespace(attr.Name.Space)
+		}
+		attrs = append(attrs, canonicalAttr{space: space, name: qualifiedName(attr.Name), value: attr.Value})
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		if attrs[i].space != attrs[j].space {
+			return attrs[i].space < attrs[j].space
+		}
+		return localName(attrs[i].name) < localName(attrs[j].name)
+	})
+
+	name := qualifiedName(n.name)
+
+	c.buf.WriteString("<" + name)
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			c.buf.WriteString(` xmlns="`)
+		} else {
+			c.buf.WriteString(` xmlns:` + prefix + `="`)
+		}
+		c.writeEscaped(scope[prefix], true)
+		c.buf.WriteByte('"')
+	}
+	for _, attr := range attrs {
+		c.buf.WriteString(" " + attr.name + `="`)
+		c.writeEscaped(attr.value, true)
+		c.buf.WriteByte('"')
+	}
+	c.buf.WriteByte('>')
+
+	for _, child := range n.children {
+		switch value := child.(type) {
+		case string:
+			c.writeEscaped(value, false)
+		case *xmlNode:
+			if value != c.excluded {
+				c.writeElement(value, scope)
+			}
+		}
+	}
+
+	c.buf.WriteString("</" + name + ">")
+}
+
+func (c *canonicalizer) writeEscaped(value string, attr bool) {
+	for _, r := range value {
+		switch {
+		case r == '&':
+			c.buf.WriteString("&amp;")
+		case r == '<':
+			c.buf.WriteString("&lt;")
+		case r == '>' && !attr:
+			c.buf.WriteString("&gt;")
+		case r == '"' && attr:
+			c.buf.WriteString("&quot;")
+		case r == '\t' && attr:
+			c.buf.WriteString("&#x9;")
+		case r == '\n' && attr:
+			c.buf.WriteString("&#xA;")
+		case r == '\r':
+			c.buf.WriteString("&#xD;")
+		default:
+			c.buf.WriteRune(r)
+		}
+	}
+}
+
+// verifySignature verifies the enveloped signature of the node with the given certificates.
+// Only the enveloped signatures referencing the node by its ID, and using the exclusive canonicalization, are supported.
+func verifySignature(n *xmlNode, certs []*x509.Certificate) error {
+	signatures := n.elements(xmldsigNS, "Signature")
+	if len(signatures) == 0 {
+		return errNotSigned
+	}
+	if len(signatures) > 1 {
+		return errors.New("multiple signatures")
+	}
+	signature := signatures[0]
+
+	signedInfo, err := signature.element(xmldsigNS, "SignedInfo")
+	if err != nil {
+		return err
+	}
+
+	reference, err := signedInfo.element(xmldsigNS, "Reference")
+	if err != nil {
+		return err
+	}
+
+	id := n.attr("ID")
+	if id == "" || reference.attr("URI") != "#"+id {
+		return fmt.Errorf("the signature reference %q does not match the signed element", reference.attr("URI"))
+	}
+
+	var canonicalized bool
+	var prefixes []string
+	if transforms := reference.elements(xmldsigNS, "Transforms"); len(transforms) == 1 {
+		for _, transform := range transforms[0].elements(xmldsigNS, "Transform") {
+			switch algorithm := transform.attr("Algorithm"); algorithm {
+			case envelopedSignature:
+			case excC14NAlgorithm:
+				canonicalized = true
+				prefixes = inclusivePrefixList(transform)
+			default:
+				return fmt.Errorf("unsupported transform %q", algorithm)
+			}
+		}
+	}
+	if !canonicalized {
+		return errors.New("the signed element must be canonicalized with the exclusive canonicalization")
+	}
+
+	digestMethod, err := reference.element(xmldsigNS, "DigestMethod")
+	if err != nil {
+		return err
+	}
+
+	digestHash, ok := digestAlgorithms[digestMethod.attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("unsupported digest algorithm %q", digestMethod.attr("Algorithm"))
+	}
+
+	digestValue, err := reference.element(xmldsigNS, "DigestValue")
+	if err != nil {
+		return err
+	}
+
+	expectedDigest, err := decodeBase64(digestValue.text())
+	if err != nil {
+		return fmt.Errorf("invalid digest: %w", err)
+	}
+
+	digest := digestHash.New()
+	_, _ = digest.Write(canonicalize(n, signature, prefixes))
+	if subtle.ConstantTimeCompare(digest.Sum(nil), expectedDigest) != 1 {
+		return errors.New("the digest of the signed element does not match")
+	}
+
+	canonicalizationMethod, err := signedInfo.element(xmldsigNS, "CanonicalizationMethod")
+	if err != nil {
+		return err
+	}
+	if algorithm := canonicalizationMethod.attr("Algorithm"); algorithm != excC14NAlgorithm {
+		return fmt.Errorf("unsupported canonicalization algorithm %q", algorithm)
+	}
+
+	signatureMethod, err := signedInfo.element(xmldsigNS, "SignatureMethod")
+	if err != nil {
+		return err
+	}
+
+	signatureHash, ok := signatureAlgorithms[signatureMethod.attr("Algorithm")]
+	if !ok {
+		return fmt.Errorf("unsupported signature algorithm %q", signatureMethod.attr("Algorithm"))
+	}
+
+	signatureValue, err := signature.element(xmldsigNS, "SignatureValue")
+	if err != nil {
+		return err
+	}
+
+	value, err := decodeBase64(signatureValue.text())
+	if err != nil {
+		return fmt.Errorf("invalid signature value: %w", err)
+	}
+
+	hashed := signatureHash.New()
+	_, _ = hashed.Write(canonicalize(signedInfo, nil, inclusivePrefixList(canonicalizationMethod)))
+	sum := hashed.Sum(nil)
+
+	for _, cert := range certs {
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if ok && rsa.VerifyPKCS1v15(key, signatureHash, sum, value) == nil {
+			return nil
+		}
+	}
+
+	return errors.New("the signature does not match the certificates of the identity provider")
+}
+
+// inclusivePrefixList returns the prefixes of the InclusiveNamespaces of the given canonicalization algorithm.
+func inclusivePrefixList(n *xmlNode) []string {
+	elements := n.elements(excC14NAlgorithm, inclusiveNamespaces)
+	if len(elements) == 0 {
+		return nil
+	}
+
+	return strings.Fields(elements[0].attr("PrefixList"))
+}
+
+// decodeBase64 decodes the given base64 value, ignoring the whitespaces.
+func decodeBase64(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+func localName(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
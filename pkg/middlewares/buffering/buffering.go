@@ -2,6 +2,7 @@ package buffering
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/opentracing/opentracing-go/ext"
@@ -17,8 +18,11 @@ const (
 )
 
 type buffer struct {
-	name   string
-	buffer *oxybuffer.Buffer
+	name         string
+	next         http.Handler
+	buffer       *oxybuffer.Buffer
+	requestHash  *bodyHash
+	responseHash *bodyHash
 }
 
 // New creates a buffering middleware.
@@ -28,8 +32,26 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 	logger.Debugf("Setting up buffering: request limits: %d (mem), %d (max), response limits: %d (mem), %d (max) with retry: '%s'",
 		config.MemRequestBodyBytes, config.MaxRequestBodyBytes, config.MemResponseBodyBytes, config.MaxResponseBodyBytes, config.RetryExpression)
 
-	oxyBuffer, err := oxybuffer.New(
-		next,
+	requestHash, err := newBodyHash(config.RequestBodyHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request body hash: %w", err)
+	}
+
+	responseHash, err := newBodyHash(config.ResponseBodyHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response body hash: %w", err)
+	}
+
+	b := &buffer{
+		name:         name,
+		next:         next,
+		requestHash:  requestHash,
+		responseHash: responseHash,
+	}
+
+	// The buffered request is forwarded once its body has been entirely read, so its hash can be checked.
+	b.buffer, err = oxybuffer.New(
+		http.HandlerFunc(b.serveBuffered),
 		oxybuffer.MemRequestBodyBytes(config.MemRequestBodyBytes),
 		oxybuffer.MaxRequestBodyBytes(config.MaxRequestBodyBytes),
 		oxybuffer.MemResponseBodyBytes(config.MemResponseBodyBytes),
@@ -40,10 +62,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 		return nil, err
 	}
 
-	return &buffer{
-		name:   name,
-		buffer: oxyBuffer,
-	}, nil
+	return b, nil
 }
 
 func (b *buffer) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -51,5 +70,33 @@ func (b *buffer) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (b *buffer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.requestHash != nil {
+		req = b.requestHash.withRequestHash(req)
+	}
+
 	b.buffer.ServeHTTP(rw, req)
 }
+
+// serveBuffered forwards the buffered request.
+func (b *buffer) serveBuffered(rw http.ResponseWriter, req *http.Request) {
+	if b.requestHash != nil {
+		if err := b.requestHash.checkRequestHash(req); err != nil {
+			logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.name, typeName))
+			logger.Debug(err)
+			tracing.SetErrorWithEvent(req, "request body hash mismatch: %v", err)
+
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if b.responseHash == nil {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	hrw := &hashResponseWriter{rw: rw, hash: b.responseHash.newHash()}
+	b.next.ServeHTTP(hrw, req)
+
+	rw.Header().Set(b.responseHash.headerName, hrw.sum())
+}
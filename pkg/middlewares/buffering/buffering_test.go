@@ -0,0 +1,127 @@
+package buffering
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew_invalidBodyHash(t *testing.T) {
+	config := dynamic.Buffering{RequestBodyHash: &dynamic.BodyHash{Algorithm: "crc32"}}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	_, err := New(context.Background(), next, config, "traefikTest")
+	assert.Error(t, err)
+}
+
+func TestBuffer_requestBodyHash(t *testing.T) {
+	// Hashes of "foo".
+	const (
+		fooMD5    = "rL0Y20zC+Fzt72VPzMSk2A=="
+		fooSHA256 = "LCa0a2j/xo/5m0U8HTBBNBNCLXBkg7+g+YpeiGJm564="
+	)
+
+	testCases := []struct {
+		desc           string
+		bodyHash       dynamic.BodyHash
+		reqHeaders     map[string]string
+		expectedStatus int
+		expectedHeader string
+		expectedHash   string
+	}{
+		{
+			desc:           "sha256 computed",
+			bodyHash:       dynamic.BodyHash{Algorithm: "sha256"},
+			expectedStatus: http.StatusOK,
+			expectedHeader: "X-Content-Sha256",
+			expectedHash:   fooSHA256,
+		},
+		{
+			desc:           "md5 computed in a custom header",
+			bodyHash:       dynamic.BodyHash{Algorithm: "md5", HeaderName: "X-Body-Md5"},
+			expectedStatus: http.StatusOK,
+			expectedHeader: "X-Body-Md5",
+			expectedHash:   fooMD5,
+		},
+		{
+			desc:           "md5 verified",
+			bodyHash:       dynamic.BodyHash{Algorithm: "md5", Verify: true},
+			reqHeaders:     map[string]string{"Content-MD5": fooMD5},
+			expectedStatus: http.StatusOK,
+			expectedHeader: "Content-MD5",
+			expectedHash:   fooMD5,
+		},
+		{
+			desc:           "md5 mismatch",
+			bodyHash:       dynamic.BodyHash{Algorithm: "md5", Verify: true},
+			reqHeaders:     map[string]string{"Content-MD5": fooSHA256},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "md5 mismatch without verification",
+			bodyHash:       dynamic.BodyHash{Algorithm: "md5"},
+			reqHeaders:     map[string]string{"Content-MD5": fooSHA256},
+			expectedStatus: http.StatusOK,
+			expectedHeader: "Content-MD5",
+			expectedHash:   fooMD5,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, "foo", string(body))
+				assert.Equal(t, test.expectedHash, req.Header.Get(test.expectedHeader))
+			})
+
+			bodyHash := test.bodyHash
+			config := dynamic.Buffering{MemRequestBodyBytes: 1, RequestBodyHash: &bodyHash}
+
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("foo"))
+			for k, v := range test.reqHeaders {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestBuffer_responseBodyHash(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte("f"))
+		_, _ = rw.Write([]byte("oo"))
+	})
+
+	config := dynamic.Buffering{ResponseBodyHash: &dynamic.BodyHash{Algorithm: "md5"}}
+
+	handler, err := New(context.Background(), next, config, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "foo", recorder.Body.String())
+	assert.Equal(t, "rL0Y20zC+Fzt72VPzMSk2A==", recorder.Header().Get("Content-MD5"))
+}
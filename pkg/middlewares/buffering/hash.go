@@ -0,0 +1,148 @@
+package buffering
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type bodyHashKey struct{}
+
+// bodyHash computes the hash of a body, while it is read or written.
+type bodyHash struct {
+	newHash    func() hash.Hash
+	headerName string
+	verify     bool
+}
+
+func newBodyHash(config *dynamic.BodyHash) (*bodyHash, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	h := &bodyHash{
+		headerName: config.HeaderName,
+		verify:     config.Verify,
+	}
+
+	var defaultHeaderName string
+	switch strings.ToLower(config.Algorithm) {
+	case "md5":
+		h.newHash = md5.New
+		defaultHeaderName = "Content-MD5"
+	case "", "sha256":
+		h.newHash = sha256.New
+		defaultHeaderName = "X-Content-Sha256"
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q, md5 or sha256 are supported", config.Algorithm)
+	}
+
+	if h.headerName == "" {
+		h.headerName = defaultHeaderName
+	}
+
+	return h, nil
+}
+
+// hashReader computes the hash of the request body as it is read by the buffer.
+type hashReader struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (r *hashReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.hash.Write(p[:n])
+	return n, err
+}
+
+func (r *hashReader) sum() string {
+	return base64.StdEncoding.EncodeToString(r.hash.Sum(nil))
+}
+
+// withRequestHash sets up the hash computation of the request body.
+func (h *bodyHash) withRequestHash(req *http.Request) *http.Request {
+	body := req.Body
+	if body == nil {
+		body = http.NoBody
+	}
+
+	reader := &hashReader{ReadCloser: body, hash: h.newHash()}
+	req.Body = reader
+
+	return req.WithContext(context.WithValue(req.Context(), bodyHashKey{}, reader))
+}
+
+// checkRequestHash verifies the hash of the fully buffered request body, and forwards it in the header.
+func (h *bodyHash) checkRequestHash(req *http.Request) error {
+	reader, ok := req.Context().Value(bodyHashKey{}).(*hashReader)
+	if !ok {
+		return nil
+	}
+
+	sum := reader.sum()
+
+	expected := req.Header.Get(h.headerName)
+	if h.verify && expected != "" && expected != sum {
+		return fmt.Errorf("%s header does not match the body hash", h.headerName)
+	}
+
+	req.Header.Set(h.headerName, sum)
+
+	return nil
+}
+
+// hashResponseWriter computes the hash of the response body as it is written in the buffer.
+// The buffer sends the response headers only once the response has been entirely written,
+// so the hash can be added to them.
+type hashResponseWriter struct {
+	rw   http.ResponseWriter
+	hash hash.Hash
+}
+
+func (w *hashResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *hashResponseWriter) WriteHeader(code int) {
+	w.rw.WriteHeader(code)
+}
+
+func (w *hashResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.rw.Write(b)
+	_, _ = w.hash.Write(b[:n])
+	return n, err
+}
+
+func (w *hashResponseWriter) sum() string {
+	return base64.StdEncoding.EncodeToString(w.hash.Sum(nil))
+}
+
+// Hijack hijacks the connection.
+func (w *hashResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", w.rw)
+}
+
+// CloseNotify returns a channel that receives at most a single value (true)
+// when the client connection has gone away.
+func (w *hashResponseWriter) CloseNotify() <-chan bool {
+	if n, ok := w.rw.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+
+	return make(<-chan bool)
+}
@@ -3,10 +3,14 @@ package circuitbreaker
 import (
 	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/cbreaker"
@@ -17,26 +21,64 @@ const (
 )
 
 type circuitBreaker struct {
+	mu             sync.RWMutex
 	circuitBreaker *cbreaker.CircuitBreaker
 	name           string
+
+	// resets counts the resets of the middleware in the registry, and is nil without registry.
+	// generation is the number of resets already applied to circuitBreaker.
+	resets     *uint64
+	generation uint64
+	create     func() (*cbreaker.CircuitBreaker, error)
+	onReset    func()
 }
 
 // New creates a new circuit breaker middleware.
-func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, name string) (http.Handler, error) {
+// When the registry is not nil, the circuit breaker is reset through the registry, by the name of the middleware.
+func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, metricsRegistry metrics.Registry, registry *Registry, name string) (http.Handler, error) {
 	expression := confCircuitBreaker.Expression
 
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
 	logger.Debug("Setting up with expression: %s", expression)
 
-	oxyCircuitBreaker, err := cbreaker.New(next, expression, createCircuitBreakerOptions(expression))
+	options := []cbreaker.CircuitBreakerOption{createCircuitBreakerOptions(expression)}
+
+	onReset := func() {}
+	if metricsRegistry != nil && metricsRegistry.IsSvcEnabled() {
+		tripped := metricsRegistry.CircuitBreakerTrippedGauge().With("middleware", name)
+		tripped.Set(0)
+
+		options = append(options,
+			cbreaker.OnTripped(stateGauge{gauge: tripped, value: 1}),
+			cbreaker.OnStandby(stateGauge{gauge: tripped, value: 0}),
+		)
+
+		onReset = func() { tripped.Set(0) }
+	}
+
+	create := func() (*cbreaker.CircuitBreaker, error) {
+		return cbreaker.New(next, expression, options...)
+	}
+
+	oxyCircuitBreaker, err := create()
 	if err != nil {
 		return nil, err
 	}
-	return &circuitBreaker{
+
+	cb := &circuitBreaker{
 		circuitBreaker: oxyCircuitBreaker,
 		name:           name,
-	}, nil
+		create:         create,
+		onReset:        onReset,
+	}
+
+	if registry != nil {
+		cb.resets = registry.counter(name)
+		cb.generation = atomic.LoadUint64(cb.resets)
+	}
+
+	return cb, nil
 }
 
 // NewCircuitBreakerOptions returns a new CircuitBreakerOption.
@@ -51,10 +93,56 @@ func createCircuitBreakerOptions(expression string) cbreaker.CircuitBreakerOptio
 	}))
 }
 
+// stateGauge is a side effect of a circuit breaker state change, setting the gauge to the value of the new state.
+type stateGauge struct {
+	gauge gokitmetrics.Gauge
+	value float64
+}
+
+func (s stateGauge) Exec() error {
+	s.gauge.Set(s.value)
+	return nil
+}
+
 func (c *circuitBreaker) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
 func (c *circuitBreaker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	c.circuitBreaker.ServeHTTP(rw, req)
+	c.current(req.Context()).ServeHTTP(rw, req)
+}
+
+// current returns the circuit breaker, replaced by a new one in its standby state when it has been reset.
+func (c *circuitBreaker) current(ctx context.Context) *cbreaker.CircuitBreaker {
+	if c.resets == nil {
+		return c.circuitBreaker
+	}
+
+	generation := atomic.LoadUint64(c.resets)
+
+	c.mu.RLock()
+	oxyCircuitBreaker, upToDate := c.circuitBreaker, c.generation == generation
+	c.mu.RUnlock()
+
+	if upToDate {
+		return oxyCircuitBreaker
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		newCircuitBreaker, err := c.create()
+		if err != nil {
+			// Should never happen, as the expression has already been parsed.
+			log.FromContext(middlewares.GetLoggerCtx(ctx, c.name, typeName)).Errorf("Unable to reset the circuit breaker: %v", err)
+		} else {
+			c.circuitBreaker = newCircuitBreaker
+			c.onReset()
+		}
+
+		c.generation = generation
+	}
+
+	return c.circuitBreaker
 }
@@ -0,0 +1,45 @@
+package circuitbreaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestCircuitBreaker_reset(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+
+	registry := NewRegistry()
+
+	conf := dynamic.CircuitBreaker{Expression: "ResponseCodeRatio(500, 600, 0, 600) > 0.5"}
+
+	handler, err := New(context.Background(), next, conf, nil, registry, "cb@file")
+	require.NoError(t, err)
+
+	other, err := New(context.Background(), next, conf, nil, registry, "other@file")
+	require.NoError(t, err)
+
+	serve := func(h http.Handler) int {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		return recorder.Code
+	}
+
+	// The first error trips the circuit breakers, which then serve the fallback.
+	for _, h := range []http.Handler{handler, other} {
+		assert.Equal(t, http.StatusInternalServerError, serve(h))
+		assert.Equal(t, http.StatusServiceUnavailable, serve(h))
+	}
+
+	registry.Reset("cb@file")
+
+	assert.Equal(t, http.StatusInternalServerError, serve(handler))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(other))
+}
@@ -0,0 +1,38 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Registry resets the circuit breakers by middleware name.
+// A reset is applied by the circuit breakers on their next request,
+// so the registry does not hold the circuit breakers of the previous configurations.
+type Registry struct {
+	mu     sync.Mutex
+	resets map[string]*uint64
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{resets: make(map[string]*uint64)}
+}
+
+// Reset puts the circuit breakers of the middleware back in their standby state.
+func (r *Registry) Reset(middlewareName string) {
+	atomic.AddUint64(r.counter(middlewareName), 1)
+}
+
+// counter returns the number of resets of the circuit breakers of the middleware.
+func (r *Registry) counter(middlewareName string) *uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.resets[middlewareName]
+	if !ok {
+		counter = new(uint64)
+		r.resets[middlewareName] = counter
+	}
+
+	return counter
+}
@@ -1,9 +1,12 @@
 package compress
 
 import (
+	"bufio"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"mime"
+	"net"
 	"net/http"
 
 	"github.com/NYTimes/gziphandler"
@@ -52,7 +55,7 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		c.next.ServeHTTP(rw, req)
 	} else {
 		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		gzipHandler(ctx, c.next).ServeHTTP(rw, req)
+		gzipHandler(ctx, informationalBypass(c.next, rw)).ServeHTTP(rw, req)
 	}
 }
 
@@ -71,6 +74,43 @@ func gzipHandler(ctx context.Context, h http.Handler) http.Handler {
 	return wrapper(h)
 }
 
+// informationalBypass writes the informational responses of next to rw directly,
+// as the gzip response writer takes the first status code written for the one of the final response.
+func informationalBypass(next http.Handler, rw http.ResponseWriter) http.Handler {
+	return http.HandlerFunc(func(gzipRW http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(&informationalWriter{ResponseWriter: gzipRW, rw: rw}, req)
+	})
+}
+
+type informationalWriter struct {
+	http.ResponseWriter
+	rw http.ResponseWriter
+}
+
+func (w *informationalWriter) WriteHeader(code int) {
+	if middlewares.IsInformational(code) {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *informationalWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *informationalWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
 func contains(values []string, val string) bool {
 	for _, v := range values {
 		if v == val {
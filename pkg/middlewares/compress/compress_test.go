@@ -227,6 +227,40 @@ func TestShouldWriteHeaderWhenFlush(t *testing.T) {
 	assert.Equal(t, acceptEncodingHeader, resp.Header.Get(varyHeader))
 }
 
+func TestShouldForwardInformationalResponses(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusEarlyHints)
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, err := rw.Write(generateBytes(gziphandler.DefaultMinSize))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	handler := &compress{next: next}
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Add(acceptEncodingHeader, gzipValue)
+
+	rw := &codesRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, []int{http.StatusEarlyHints, http.StatusUnauthorized}, rw.codes)
+	assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+}
+
+// codesRecorder records all the status codes written, including the informational ones.
+type codesRecorder struct {
+	*httptest.ResponseRecorder
+	codes []int
+}
+
+func (r *codesRecorder) WriteHeader(code int) {
+	r.codes = append(r.codes, code)
+	if code >= http.StatusOK {
+		r.ResponseRecorder.WriteHeader(code)
+	}
+}
+
 func TestIntegrationShouldCompress(t *testing.T) {
 	fakeBody := generateBytes(100000)
 
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+)
+
+type connKey struct{}
+
+// WithConn returns a copy of ctx holding the client connection.
+// It is used as the ConnContext of the entry points HTTP servers.
+func WithConn(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, conn)
+}
+
+// GetConn returns the client connection held by ctx, if any.
+func GetConn(ctx context.Context) net.Conn {
+	conn, _ := ctx.Value(connKey{}).(net.Conn)
+	return conn
+}
@@ -0,0 +1,284 @@
+package cors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "CORS"
+)
+
+// defaultAllowMethods are the CORS-safelisted methods allowed when a policy does not define any.
+var defaultAllowMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+// cors is a middleware handling the CORS headers and answering the preflight requests,
+// according to the first policy matching the request origin.
+type cors struct {
+	next     http.Handler
+	name     string
+	policies []*policy
+}
+
+// New creates a CORS middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.CORS, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if len(config.Policies) == 0 {
+		return nil, errors.New("no CORS policy defined")
+	}
+
+	var policies []*policy
+	for i, cfg := range config.Policies {
+		p, err := newPolicy(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CORS policy at index %d: %w", i, err)
+		}
+		policies = append(policies, p)
+	}
+
+	return &cors{
+		next:     next,
+		name:     name,
+		policies: policies,
+	}, nil
+}
+
+func (c *cors) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("Origin")
+
+	if isPreflight(req) {
+		// The preflight is answered by the middleware itself,
+		// the backend is never reached, whether the origin is allowed or not.
+		if p := c.match(origin); p != nil {
+			p.writePreflightHeaders(rw.Header(), req, origin)
+		}
+		rw.Header().Add("Vary", "Origin")
+		rw.Header().Add("Vary", "Access-Control-Request-Method")
+		rw.Header().Add("Vary", "Access-Control-Request-Headers")
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if origin != "" {
+		if p := c.match(origin); p != nil {
+			p.writeHeaders(rw.Header(), origin)
+		}
+		rw.Header().Add("Vary", "Origin")
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+// match returns the first policy allowing the given origin, nil if none.
+func (c *cors) match(origin string) *policy {
+	if origin == "" {
+		return nil
+	}
+
+	for _, p := range c.policies {
+		if p.allowOrigin(origin) {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// isPreflight returns whether the request is a CORS preflight request: https://fetch.spec.whatwg.org/#cors-preflight-request
+func isPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions &&
+		req.Header.Get("Origin") != "" &&
+		req.Header.Get("Access-Control-Request-Method") != ""
+}
+
+type policy struct {
+	allowAnyOrigin   bool
+	origins          map[string]struct{}
+	originPatterns   []originPattern
+	allowMethods     []string
+	allowAnyHeader   bool
+	allowHeaders     map[string]struct{}
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           int64
+}
+
+func newPolicy(cfg dynamic.CORSPolicy) (*policy, error) {
+	if len(cfg.AllowOrigins) == 0 {
+		return nil, errors.New("allowOrigins is empty")
+	}
+
+	p := &policy{
+		origins:          make(map[string]struct{}),
+		allowHeaders:     make(map[string]struct{}),
+		exposeHeaders:    strings.Join(cfg.ExposeHeaders, ","),
+		allowCredentials: cfg.AllowCredentials,
+		maxAge:           cfg.MaxAge,
+	}
+
+	for _, origin := range cfg.AllowOrigins {
+		switch strings.Count(origin, "*") {
+		case 0:
+			p.origins[strings.ToLower(origin)] = struct{}{}
+
+		case 1:
+			if origin == "*" {
+				p.allowAnyOrigin = true
+				continue
+			}
+
+			i := strings.Index(origin, "*")
+			p.originPatterns = append(p.originPatterns, originPattern{
+				prefix: strings.ToLower(origin[:i]),
+				suffix: strings.ToLower(origin[i+1:]),
+			})
+
+		default:
+			return nil, fmt.Errorf("invalid origin pattern %q: only one wildcard is allowed", origin)
+		}
+	}
+
+	for _, method := range cfg.AllowMethods {
+		p.allowMethods = append(p.allowMethods, strings.ToUpper(method))
+	}
+	if len(p.allowMethods) == 0 {
+		p.allowMethods = defaultAllowMethods
+	}
+
+	for _, header := range cfg.AllowHeaders {
+		if header == "*" {
+			p.allowAnyHeader = true
+			continue
+		}
+		p.allowHeaders[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	return p, nil
+}
+
+func (p *policy) allowOrigin(origin string) bool {
+	if p.allowAnyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	for _, pattern := range p.originPatterns {
+		if pattern.match(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *policy) allowMethod(method string) bool {
+	for _, m := range p.allowMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *policy) allowHeader(header string) bool {
+	if p.allowAnyHeader {
+		return true
+	}
+
+	_, ok := p.allowHeaders[http.CanonicalHeaderKey(header)]
+	return ok
+}
+
+// writePreflightHeaders writes the preflight response headers,
+// only if the requested method and headers are allowed by the policy.
+func (p *policy) writePreflightHeaders(header http.Header, req *http.Request, origin string) {
+	if !p.allowMethod(strings.ToUpper(req.Header.Get("Access-Control-Request-Method"))) {
+		return
+	}
+
+	var requestedHeaders []string
+	for _, value := range req.Header.Values("Access-Control-Request-Headers") {
+		for _, h := range strings.Split(value, ",") {
+			h = strings.TrimSpace(h)
+			if h == "" {
+				continue
+			}
+
+			if !p.allowHeader(h) {
+				return
+			}
+			requestedHeaders = append(requestedHeaders, h)
+		}
+	}
+
+	header.Set("Access-Control-Allow-Origin", p.allowOriginValue(origin))
+	header.Set("Access-Control-Allow-Methods", strings.Join(p.allowMethods, ","))
+
+	if len(requestedHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(requestedHeaders, ","))
+	}
+
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if p.maxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.FormatInt(p.maxAge, 10))
+	}
+}
+
+// writeHeaders writes the CORS headers of an actual (non-preflight) request.
+func (p *policy) writeHeaders(header http.Header, origin string) {
+	header.Set("Access-Control-Allow-Origin", p.allowOriginValue(origin))
+
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if p.exposeHeaders != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposeHeaders)
+	}
+}
+
+// allowOriginValue returns the value of the Access-Control-Allow-Origin header.
+// The wildcard cannot be used with credentials, hence the request origin is sent back instead.
+func (p *policy) allowOriginValue(origin string) string {
+	if p.allowAnyOrigin && !p.allowCredentials {
+		return "*"
+	}
+
+	return origin
+}
+
+type originPattern struct {
+	prefix string
+	suffix string
+}
+
+func (o originPattern) match(origin string) bool {
+	return len(origin) >= len(o.prefix)+len(o.suffix) &&
+		strings.HasPrefix(origin, o.prefix) &&
+		strings.HasSuffix(origin, o.suffix)
+}
@@ -0,0 +1,295 @@
+package cors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.CORS
+		expectedError bool
+	}{
+		{
+			desc:          "no policy",
+			config:        dynamic.CORS{},
+			expectedError: true,
+		},
+		{
+			desc: "policy without origin",
+			config: dynamic.CORS{
+				Policies: []dynamic.CORSPolicy{{AllowMethods: []string{http.MethodGet}}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "origin pattern with several wildcards",
+			config: dynamic.CORS{
+				Policies: []dynamic.CORSPolicy{{AllowOrigins: []string{"https://*.*.example.com"}}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid policies",
+			config: dynamic.CORS{
+				Policies: []dynamic.CORSPolicy{
+					{AllowOrigins: []string{"https://example.com", "https://*.example.com"}},
+					{AllowOrigins: []string{"*"}},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestCORS_ServeHTTP(t *testing.T) {
+	config := dynamic.CORS{
+		Policies: []dynamic.CORSPolicy{
+			{
+				AllowOrigins:     []string{"https://admin.example.com"},
+				AllowMethods:     []string{http.MethodGet, http.MethodPut, http.MethodDelete},
+				AllowHeaders:     []string{"X-Token"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				AllowCredentials: true,
+				MaxAge:           600,
+			},
+			{
+				AllowOrigins: []string{"https://*.example.com"},
+				AllowHeaders: []string{"*"},
+			},
+			{
+				AllowOrigins: []string{"https://*.example.org", "https://example.net"},
+				MaxAge:       60,
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc            string
+		method          string
+		reqHeaders      map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBackend bool
+	}{
+		{
+			desc:   "preflight with credentials",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin":                         "https://admin.example.com",
+				"Access-Control-Request-Method":  http.MethodPut,
+				"Access-Control-Request-Headers": "x-token",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://admin.example.com",
+				"Access-Control-Allow-Methods":     "GET,PUT,DELETE",
+				"Access-Control-Allow-Headers":     "x-token",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			desc:   "preflight matching an origin pattern",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin":                         "https://api.example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "X-Foo, X-Bar",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://api.example.com",
+				"Access-Control-Allow-Methods":     "GET,HEAD,POST",
+				"Access-Control-Allow-Headers":     "X-Foo,X-Bar",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Max-Age":           "",
+			},
+		},
+		{
+			desc:   "preflight with a method not allowed",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin":                        "https://example.net",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			desc:   "preflight with a header not allowed",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin":                         "https://www.example.org",
+				"Access-Control-Request-Method":  http.MethodGet,
+				"Access-Control-Request-Headers": "X-Token",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			desc:   "preflight from an unknown origin",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin":                        "https://example.com.evil.io",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			desc:   "options request without CORS headers",
+			method: http.MethodOptions,
+			reqHeaders: map[string]string{
+				"Origin": "https://example.net",
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "https://example.net",
+			},
+			expectedBackend: true,
+		},
+		{
+			desc:   "actual request",
+			method: http.MethodGet,
+			reqHeaders: map[string]string{
+				"Origin": "https://admin.example.com",
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://admin.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-Id",
+				"Vary":                             "Origin",
+			},
+			expectedBackend: true,
+		},
+		{
+			desc:   "actual request from an unknown origin",
+			method: http.MethodGet,
+			reqHeaders: map[string]string{
+				"Origin": "https://example.io",
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+			expectedBackend: true,
+		},
+		{
+			desc:           "request without origin",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "",
+			},
+			expectedBackend: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var backendCalled bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				backendCalled = true
+			})
+
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, "http://localhost", nil)
+			for k, v := range test.reqHeaders {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBackend, backendCalled)
+			for k, v := range test.expectedHeaders {
+				assert.Equal(t, v, recorder.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func TestCORS_ServeHTTP_anyOrigin(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		allowCredentials bool
+		expected         string
+	}{
+		{
+			desc:     "without credentials",
+			expected: "*",
+		},
+		{
+			desc:             "with credentials",
+			allowCredentials: true,
+			expected:         "https://example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.CORS{
+				Policies: []dynamic.CORSPolicy{{
+					AllowOrigins:     []string{"*"},
+					AllowCredentials: test.allowCredentials,
+				}},
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set("Origin", "https://example.com")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
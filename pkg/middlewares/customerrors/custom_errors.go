@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestid"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/utils"
@@ -40,32 +42,100 @@ type serviceBuilder interface {
 type customErrors struct {
 	name           string
 	next           http.Handler
-	backendHandler http.Handler
+	routerName     string
+	httpCodeRanges types.HTTPCodeRanges
+	// pages are the error pages, the ones of specific status codes first.
+	pages []errorPage
+}
+
+// errorPage is an error page served by a backend, or rendered from a template.
+type errorPage struct {
 	httpCodeRanges types.HTTPCodeRanges
+	backendHandler http.Handler
 	backendQuery   string
+	template       pageTemplate
+	contentType    string
 }
 
 // New creates a new custom error pages middleware.
 func New(ctx context.Context, next http.Handler, config dynamic.ErrorPage, serviceBuilder serviceBuilder, name string) (http.Handler, error) {
-	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	c := &customErrors{
+		name: name,
+		next: next,
+	}
+
+	// Middlewares are built for each router, with the router name in the logger fields.
+	if routerName, ok := logger.Data[log.RouterName].(string); ok {
+		c.routerName = routerName
+	}
+
+	for i, statusPage := range config.StatusPages {
+		page, err := newErrorPage(ctx, serviceBuilder, statusPage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status page at index %d: %w", i, err)
+		}
+
+		c.pages = append(c.pages, page)
+	}
+
+	if len(config.Status) > 0 {
+		page, err := newErrorPage(ctx, serviceBuilder, dynamic.StatusErrorPage{
+			Status:      config.Status,
+			Service:     config.Service,
+			Query:       config.Query,
+			Template:    config.Template,
+			ContentType: config.ContentType,
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		c.pages = append(c.pages, page)
+	}
+
+	for _, page := range c.pages {
+		c.httpCodeRanges = append(c.httpCodeRanges, page.httpCodeRanges...)
+	}
+
+	return c, nil
+}
+
+func newErrorPage(ctx context.Context, serviceBuilder serviceBuilder, config dynamic.StatusErrorPage) (errorPage, error) {
 	httpCodeRanges, err := types.NewHTTPCodeRanges(config.Status)
 	if err != nil {
-		return nil, err
+		return errorPage{}, err
+	}
+
+	page := errorPage{httpCodeRanges: httpCodeRanges}
+
+	if config.Template != "" {
+		if config.Service != "" {
+			return errorPage{}, errors.New("service and template cannot be both defined")
+		}
+
+		page.contentType = config.ContentType
+		if page.contentType == "" {
+			page.contentType = defaultContentType
+		}
+
+		page.template, err = newPageTemplate(config.Template, page.contentType)
+		if err != nil {
+			return errorPage{}, err
+		}
+
+		return page, nil
 	}
 
-	backend, err := serviceBuilder.BuildHTTP(ctx, config.Service)
+	page.backendHandler, err = serviceBuilder.BuildHTTP(ctx, config.Service)
 	if err != nil {
-		return nil, err
+		return errorPage{}, err
 	}
+	page.backendQuery = config.Query
 
-	return &customErrors{
-		name:           name,
-		next:           next,
-		backendHandler: backend,
-		httpCodeRanges: httpCodeRanges,
-		backendQuery:   config.Query,
-	}, nil
+	return page, nil
 }
 
 func (c *customErrors) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -76,9 +146,9 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
 	logger := log.FromContext(ctx)
 
-	if c.backendHandler == nil {
-		logger.Error("Error pages: no backend handler.")
-		tracing.SetErrorWithEvent(req, "Error pages: no backend handler.")
+	if len(c.pages) == 0 {
+		logger.Error("Error pages: no error page.")
+		tracing.SetErrorWithEvent(req, "Error pages: no error page.")
 		c.next.ServeHTTP(rw, req)
 		return
 	}
@@ -91,40 +161,78 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	// check the recorder code against the configured http status code ranges
 	code := catcher.getCode()
-	for _, block := range c.httpCodeRanges {
-		if code >= block[0] && code <= block[1] {
-			logger.Debugf("Caught HTTP Status Code %d, returning error page", code)
-
-			var query string
-			if len(c.backendQuery) > 0 {
-				query = "/" + strings.TrimPrefix(c.backendQuery, "/")
-				query = strings.ReplaceAll(query, "{status}", strconv.Itoa(code))
-			}
+	for _, page := range c.pages {
+		if !page.httpCodeRanges.Contains(code) {
+			continue
+		}
 
-			pageReq, err := newRequest(backendURL + query)
-			if err != nil {
-				logger.Error(err)
-				rw.WriteHeader(code)
-				_, err = fmt.Fprint(rw, http.StatusText(code))
-				if err != nil {
-					http.Error(rw, err.Error(), http.StatusInternalServerError)
-				}
-				return
-			}
+		logger.Debugf("Caught HTTP Status Code %d, returning error page", code)
 
-			recorderErrorPage := newResponseRecorder(ctx, rw)
-			utils.CopyHeaders(pageReq.Header, req.Header)
+		if page.template != nil {
+			c.renderTemplate(rw, req, page, code)
+			return
+		}
 
-			c.backendHandler.ServeHTTP(recorderErrorPage, pageReq.WithContext(req.Context()))
+		var query string
+		if len(page.backendQuery) > 0 {
+			query = "/" + strings.TrimPrefix(page.backendQuery, "/")
+			query = strings.ReplaceAll(query, "{status}", strconv.Itoa(code))
+		}
 
-			utils.CopyHeaders(rw.Header(), recorderErrorPage.Header())
+		pageReq, err := newRequest(backendURL + query)
+		if err != nil {
+			logger.Error(err)
 			rw.WriteHeader(code)
-
-			if _, err = rw.Write(recorderErrorPage.GetBody().Bytes()); err != nil {
-				logger.Error(err)
+			_, err = fmt.Fprint(rw, http.StatusText(code))
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusInternalServerError)
 			}
 			return
 		}
+
+		recorderErrorPage := newResponseRecorder(ctx, rw)
+		utils.CopyHeaders(pageReq.Header, req.Header)
+
+		page.backendHandler.ServeHTTP(recorderErrorPage, pageReq.WithContext(req.Context()))
+
+		utils.CopyHeaders(rw.Header(), recorderErrorPage.Header())
+		rw.WriteHeader(code)
+
+		if _, err = rw.Write(recorderErrorPage.GetBody().Bytes()); err != nil {
+			logger.Error(err)
+		}
+		return
+	}
+}
+
+func (c *customErrors) renderTemplate(rw http.ResponseWriter, req *http.Request, page errorPage, code int) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+
+	data := templateData{
+		Status:     code,
+		StatusText: http.StatusText(code),
+		Method:     req.Method,
+		Host:       req.Host,
+		Path:       req.URL.Path,
+		RequestID:  requestid.Get(req),
+		Router:     c.routerName,
+	}
+
+	body := new(bytes.Buffer)
+	if err := page.template.Execute(body, data); err != nil {
+		logger.Errorf("Error while rendering the error page template: %v", err)
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(code)
+		_, _ = fmt.Fprint(rw, http.StatusText(code))
+		return
+	}
+
+	rw.Header().Set("Content-Type", page.contentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	rw.WriteHeader(code)
+
+	if _, err := rw.Write(body.Bytes()); err != nil {
+		logger.Error(err)
 	}
 }
 
@@ -233,6 +341,12 @@ func (cc *codeCatcher) WriteHeader(code int) {
 		return
 	}
 
+	// The informational responses precede the final one, which is the one caught.
+	if middlewares.IsInformational(code) {
+		middlewares.WriteInformational(cc.responseWriter, code, cc.Header())
+		return
+	}
+
 	cc.code = code
 	for _, block := range cc.httpCodeRanges {
 		if cc.code >= block[0] && cc.code <= block[1] {
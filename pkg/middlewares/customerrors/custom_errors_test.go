@@ -146,6 +146,178 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandler_template(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		errorPage           dynamic.ErrorPage
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc: "html template",
+			errorPage: dynamic.ErrorPage{
+				Status:   []string{"500-599"},
+				Template: `<h1>{{ .Status }} {{ .StatusText }}</h1><p>{{ .Path }} {{ .RequestID }} {{ .Router }}</p>`,
+			},
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        `<h1>502 Bad Gateway</h1><p>/&lt;script&gt; 42 </p>`,
+		},
+		{
+			desc: "json template",
+			errorPage: dynamic.ErrorPage{
+				Status:      []string{"500-599"},
+				Template:    `{"status":{{ .Status }},"path":"{{ .Path }}","method":"{{ .Method }}"}`,
+				ContentType: "application/json",
+			},
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":502,"path":"/<script>","method":"GET"}`,
+		},
+		{
+			desc: "template execution error",
+			errorPage: dynamic.ErrorPage{
+				Status:   []string{"500-599"},
+				Template: `{{ .Unknown }}`,
+			},
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        http.StatusText(http.StatusBadGateway),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			})
+			errorPageHandler, err := New(context.Background(), handler, test.errorPage, &mockServiceBuilder{}, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/<script>", nil)
+			req.Header.Set("X-Request-Id", "42")
+
+			recorder := httptest.NewRecorder()
+			errorPageHandler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusBadGateway, recorder.Code)
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHandler_statusPages(t *testing.T) {
+	errorPage := dynamic.ErrorPage{
+		Status:  []string{"400-599"},
+		Service: "default",
+		StatusPages: []dynamic.StatusErrorPage{
+			{Status: []string{"404"}, Template: "not found", ContentType: "text/plain"},
+			{Status: []string{"400-499"}, Service: "client", Query: "/{status}"},
+		},
+	}
+
+	serviceBuilder := mockServicesBuilder{
+		"default": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "default page")
+		}),
+		"client": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "client page "+r.RequestURI)
+		}),
+	}
+
+	testCases := []struct {
+		desc         string
+		backendCode  int
+		expectedBody string
+	}{
+		{
+			desc:         "specific status",
+			backendCode:  http.StatusNotFound,
+			expectedBody: "not found",
+		},
+		{
+			desc:         "status class",
+			backendCode:  http.StatusForbidden,
+			expectedBody: "client page /403",
+		},
+		{
+			desc:         "default page",
+			backendCode:  http.StatusServiceUnavailable,
+			expectedBody: "default page",
+		},
+		{
+			desc:         "not an error",
+			backendCode:  http.StatusOK,
+			expectedBody: "OK",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.backendCode)
+				fmt.Fprint(w, http.StatusText(test.backendCode))
+			})
+			errorPageHandler, err := New(context.Background(), handler, errorPage, serviceBuilder, "test")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			errorPageHandler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost/test", nil))
+
+			assert.Equal(t, test.backendCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestNew_invalidPages(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		errorPage dynamic.ErrorPage
+	}{
+		{
+			desc:      "service and template",
+			errorPage: dynamic.ErrorPage{Status: []string{"500"}, Service: "error", Template: "error"},
+		},
+		{
+			desc:      "invalid template",
+			errorPage: dynamic.ErrorPage{Status: []string{"500"}, Template: "{{ .Status "},
+		},
+		{
+			desc: "invalid status page",
+			errorPage: dynamic.ErrorPage{
+				StatusPages: []dynamic.StatusErrorPage{{Status: []string{"foo"}, Template: "error"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			_, err := New(context.Background(), next, test.errorPage, &mockServiceBuilder{}, "test")
+			assert.Error(t, err)
+		})
+	}
+}
+
+type mockServicesBuilder map[string]http.Handler
+
+func (m mockServicesBuilder) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	handler, ok := m[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", serviceName)
+	}
+
+	return handler, nil
+}
+
 type mockServiceBuilder struct {
 	handler http.Handler
 }
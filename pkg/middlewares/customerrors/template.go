@@ -0,0 +1,36 @@
+package customerrors
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+)
+
+const defaultContentType = "text/html; charset=utf-8"
+
+// templateData is the data given to the error page templates.
+type templateData struct {
+	Status     int
+	StatusText string
+	Method     string
+	Host       string
+	Path       string
+	RequestID  string
+	Router     string
+}
+
+// pageTemplate is implemented by both the html and text templates.
+type pageTemplate interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// newPageTemplate parses the error page template.
+// HTML templates escape the request data, as it is controlled by the client.
+func newPageTemplate(text, contentType string) (pageTemplate, error) {
+	if strings.Contains(strings.ToLower(contentType), "html") {
+		return htmltemplate.New("errorPage").Parse(text)
+	}
+
+	return texttemplate.New("errorPage").Parse(text)
+}
@@ -0,0 +1,143 @@
+package decompress
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Decompress"
+)
+
+// supportedEncodings are the content codings which can be decoded and encoded, by order of preference.
+var supportedEncodings = []string{"gzip", "deflate"}
+
+// decompress is a middleware decoding the responses whose content coding is not accepted by the client,
+// and encoding them again with a content coding accepted by the client, if any.
+type decompress struct {
+	next      http.Handler
+	name      string
+	encodings map[string]struct{}
+}
+
+// New creates a decompress middleware.
+func New(ctx context.Context, next http.Handler, conf dynamic.Decompress, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	encodings := make(map[string]struct{})
+	for _, encoding := range conf.Encodings {
+		encoding = normalizeEncoding(encoding)
+		if !isSupported(encoding) {
+			return nil, fmt.Errorf("unsupported encoding %q", encoding)
+		}
+
+		encodings[encoding] = struct{}{}
+	}
+
+	if len(encodings) == 0 {
+		for _, encoding := range supportedEncodings {
+			encodings[encoding] = struct{}{}
+		}
+	}
+
+	return &decompress{
+		next:      next,
+		name:      name,
+		encodings: encodings,
+	}, nil
+}
+
+func (d *decompress) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return d.name, tracing.SpanKindNoneEnum
+}
+
+func (d *decompress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	dw := &decodingWriter{
+		rw:        rw,
+		method:    req.Method,
+		encodings: d.encodings,
+		accepted:  parseAcceptEncoding(req.Header.Values("Accept-Encoding")),
+	}
+
+	d.next.ServeHTTP(dw, req)
+
+	if err := dw.close(); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), d.name, typeName)).Debugf("Error while decoding the response: %v", err)
+	}
+}
+
+// acceptEncoding holds the content codings of an Accept-Encoding header, and whether they are accepted.
+type acceptEncoding map[string]bool
+
+// parseAcceptEncoding parses the values of the Accept-Encoding header of a request.
+// The invalid elements are ignored.
+func parseAcceptEncoding(values []string) acceptEncoding {
+	accepted := make(acceptEncoding)
+
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			params := strings.Split(element, ";")
+
+			encoding := normalizeEncoding(params[0])
+			if encoding == "" {
+				continue
+			}
+
+			q := 1.0
+			for _, param := range params[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(strings.ToLower(param), "q=") {
+					continue
+				}
+
+				var err error
+				if q, err = strconv.ParseFloat(param[2:], 64); err != nil {
+					q = 0
+				}
+			}
+
+			accepted[encoding] = q > 0
+		}
+	}
+
+	return accepted
+}
+
+// accepts reports whether the content coding is accepted, explicitly or by the * wildcard.
+// Without Accept-Encoding header, only the identity coding is accepted,
+// as the clients without support of the content codings do not send one.
+func (a acceptEncoding) accepts(encoding string) bool {
+	if accepted, ok := a[encoding]; ok {
+		return accepted
+	}
+
+	return a["*"]
+}
+
+func normalizeEncoding(encoding string) string {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if encoding == "x-gzip" {
+		return "gzip"
+	}
+
+	return encoding
+}
+
+func isSupported(encoding string) bool {
+	for _, e := range supportedEncodings {
+		if e == encoding {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,333 @@
+package decompress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const content = "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+
+func TestDecompress(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		encodings        []string
+		method           string
+		acceptEncoding   string
+		contentEncoding  string
+		statusCode       int
+		expectedEncoding string
+		expectedVary     string
+		expectedETag     string
+	}{
+		{
+			desc:             "gzip accepted",
+			acceptEncoding:   "gzip, deflate",
+			contentEncoding:  "gzip",
+			expectedEncoding: "gzip",
+			expectedVary:     "Accept-Encoding",
+			expectedETag:     `"foo"`,
+		},
+		{
+			desc:             "gzip accepted by wildcard",
+			acceptEncoding:   "*",
+			contentEncoding:  "gzip",
+			expectedEncoding: "gzip",
+			expectedVary:     "Accept-Encoding",
+			expectedETag:     `"foo"`,
+		},
+		{
+			desc:            "no Accept-Encoding",
+			contentEncoding: "gzip",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+		{
+			desc:            "gzip refused",
+			acceptEncoding:  "gzip;q=0, identity",
+			contentEncoding: "gzip",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+		{
+			desc:            "gzip refused by wildcard",
+			acceptEncoding:  "*;q=0",
+			contentEncoding: "x-gzip",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+		{
+			desc:             "gzip re-encoded with deflate",
+			acceptEncoding:   "deflate",
+			contentEncoding:  "gzip",
+			expectedEncoding: "deflate",
+			expectedVary:     "Accept-Encoding",
+			expectedETag:     `W/"foo"`,
+		},
+		{
+			desc:             "deflate re-encoded with gzip",
+			acceptEncoding:   "GZIP",
+			contentEncoding:  "deflate",
+			expectedEncoding: "gzip",
+			expectedVary:     "Accept-Encoding",
+			expectedETag:     `W/"foo"`,
+		},
+		{
+			desc:            "raw deflate",
+			contentEncoding: "raw-deflate",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+		{
+			desc:             "encoding not decoded",
+			encodings:        []string{"deflate"},
+			contentEncoding:  "gzip",
+			expectedEncoding: "gzip",
+			expectedETag:     `"foo"`,
+		},
+		{
+			desc:            "deflate not used to re-encode",
+			encodings:       []string{"gzip"},
+			acceptEncoding:  "deflate",
+			contentEncoding: "gzip",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+		{
+			desc:             "unsupported encoding",
+			contentEncoding:  "br",
+			expectedEncoding: "br",
+			expectedETag:     `"foo"`,
+		},
+		{
+			desc:             "partial content",
+			contentEncoding:  "gzip",
+			statusCode:       http.StatusPartialContent,
+			expectedEncoding: "gzip",
+			expectedETag:     `"foo"`,
+		},
+		{
+			desc:         "identity",
+			expectedETag: `"foo"`,
+		},
+		{
+			desc:            "HEAD",
+			method:          http.MethodHead,
+			contentEncoding: "gzip",
+			expectedVary:    "Accept-Encoding",
+			expectedETag:    `W/"foo"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			body := encode(t, test.contentEncoding, content)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.contentEncoding == "raw-deflate" {
+					rw.Header().Set("Content-Encoding", "deflate")
+				} else if test.contentEncoding != "" {
+					rw.Header().Set("Content-Encoding", test.contentEncoding)
+				}
+				rw.Header().Set("Content-Length", "1024")
+				rw.Header().Set("ETag", `"foo"`)
+
+				statusCode := http.StatusOK
+				if test.statusCode != 0 {
+					statusCode = test.statusCode
+				}
+				rw.WriteHeader(statusCode)
+
+				if req.Method == http.MethodHead {
+					return
+				}
+
+				// The body is written in chunks, as a proxied response.
+				for i := 0; i < len(body); i += 10 {
+					end := i + 10
+					if end > len(body) {
+						end = len(body)
+					}
+
+					_, err := rw.Write(body[i:end])
+					require.NoError(t, err)
+				}
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Decompress{Encodings: test.encodings}, "traefikTest")
+			require.NoError(t, err)
+
+			method := http.MethodGet
+			if test.method != "" {
+				method = test.method
+			}
+
+			req := httptest.NewRequest(method, "http://localhost", nil)
+			if test.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", test.acceptEncoding)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedEncoding, recorder.Header().Get("Content-Encoding"))
+			assert.Equal(t, test.expectedVary, recorder.Header().Get("Vary"))
+			assert.Equal(t, test.expectedETag, recorder.Header().Get("ETag"))
+
+			if test.method == http.MethodHead {
+				assert.Empty(t, recorder.Body.Bytes())
+				return
+			}
+
+			if test.expectedEncoding == "br" || test.statusCode == http.StatusPartialContent {
+				assert.Equal(t, body, recorder.Body.Bytes())
+				return
+			}
+
+			if test.expectedEncoding == test.contentEncoding {
+				assert.Equal(t, "1024", recorder.Header().Get("Content-Length"))
+			} else {
+				assert.Empty(t, recorder.Header().Get("Content-Length"))
+			}
+
+			assert.Equal(t, content, decode(t, test.expectedEncoding, recorder.Body.Bytes()))
+		})
+	}
+}
+
+func TestDecompress_emptyBody(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Encoding", "gzip")
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Decompress{}, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("Content-Encoding"))
+	assert.Empty(t, recorder.Body.Bytes())
+}
+
+func TestDecompress_invalidBody(t *testing.T) {
+	body := encode(t, "gzip", content)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Encoding", "gzip")
+
+		_, err := rw.Write(body)
+		require.NoError(t, err)
+
+		// The decoding stops at the invalid data, and the following writes fail.
+		_, err = rw.Write(bytes.Repeat([]byte("invalid"), 64*1024))
+		assert.Error(t, err)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Decompress{}, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, content, recorder.Body.String())
+}
+
+func TestDecompress_truncatedBody(t *testing.T) {
+	body := encode(t, "gzip", content)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Encoding", "gzip")
+
+		_, err := rw.Write(body[:len(body)-10])
+		require.NoError(t, err)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Decompress{}, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(content, recorder.Body.String()))
+}
+
+func TestDecompress_unsupportedEncoding(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Decompress{Encodings: []string{"br"}}, "traefikTest")
+	assert.Error(t, err)
+}
+
+func TestParseAcceptEncoding(t *testing.T) {
+	accepted := parseAcceptEncoding([]string{"gzip;q=0.5, deflate;q=0", "X-GZIP, br;q=invalid, *;q=0.1"})
+
+	assert.True(t, accepted.accepts("gzip"))
+	assert.False(t, accepted.accepts("deflate"))
+	assert.False(t, accepted.accepts("br"))
+	assert.True(t, accepted.accepts("compress"))
+}
+
+func encode(t *testing.T, encoding, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	var writer io.WriteCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		var err error
+		writer, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+	default:
+		// Opaque data for the unsupported encodings.
+		return []byte(data)
+	}
+
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, encoding string, data []byte) string {
+	t.Helper()
+
+	var reader io.Reader = bytes.NewReader(data)
+
+	var err error
+	switch encoding {
+	case "gzip":
+		reader, err = gzip.NewReader(reader)
+	case "deflate":
+		reader, err = zlib.NewReader(reader)
+	}
+	require.NoError(t, err)
+
+	decoded, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(decoded)
+}
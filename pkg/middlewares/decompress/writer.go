@@ -0,0 +1,283 @@
+package decompress
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+// decodingWriter decodes the body of the response when its content coding is not accepted by the client.
+// The body is decoded in a goroutine fed by the writes of the response,
+// as the decoders read the encoded data instead of having it written to them.
+type decodingWriter struct {
+	rw        http.ResponseWriter
+	method    string
+	encodings map[string]struct{}
+	accepted  acceptEncoding
+
+	wroteHeader bool
+
+	// pipe receives the encoded body of the response, when it is decoded.
+	pipe *io.PipeWriter
+	done chan struct{}
+	err  error
+
+	// mu guards the writes to rw and to the encoder, once the body is decoded.
+	mu      sync.Mutex
+	encoder encoder
+}
+
+func (w *decodingWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *decodingWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	if middlewares.IsInformational(code) {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.wroteHeader = true
+
+	header := w.rw.Header()
+
+	encoding, ok := w.decodedEncoding(code)
+	if !ok {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	// The content coding of the response depends on the Accept-Encoding header of the request.
+	addVary(header, "Accept-Encoding")
+
+	if w.accepted.accepts(encoding) {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	target := w.targetEncoding()
+
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	if target != "" {
+		header.Set("Content-Encoding", target)
+	}
+
+	// The decoded representation is not byte-for-byte the one of a strong validator.
+	if etag := header.Get("ETag"); strings.HasPrefix(etag, `"`) {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	if w.method == http.MethodHead || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	reader, writer := io.Pipe()
+	w.pipe = writer
+	w.done = make(chan struct{})
+
+	w.rw.WriteHeader(code)
+
+	go w.transcode(reader, encoding, target)
+}
+
+// decodedEncoding returns the content coding of the response, if it is one of the decoded ones.
+func (w *decodingWriter) decodedEncoding(code int) (string, bool) {
+	header := w.rw.Header()
+
+	// A range of an encoded representation cannot be decoded.
+	if code == http.StatusPartialContent || header.Get("Content-Range") != "" {
+		return "", false
+	}
+
+	// Only the responses with a single content coding are decoded.
+	values := header.Values("Content-Encoding")
+	if len(values) != 1 || strings.Contains(values[0], ",") {
+		return "", false
+	}
+
+	encoding := normalizeEncoding(values[0])
+	_, ok := w.encodings[encoding]
+
+	return encoding, ok
+}
+
+// targetEncoding returns the content coding accepted by the client the decoded body is encoded with,
+// or an empty string to send it decoded.
+func (w *decodingWriter) targetEncoding() string {
+	for _, encoding := range supportedEncodings {
+		if _, ok := w.encodings[encoding]; ok && w.accepted.accepts(encoding) {
+			return encoding
+		}
+	}
+
+	return ""
+}
+
+func (w *decodingWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.pipe == nil {
+		return w.rw.Write(p)
+	}
+
+	return w.pipe.Write(p)
+}
+
+// Flush sends the body decoded so far to the client.
+func (w *decodingWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *decodingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+
+	return hijacker.Hijack()
+}
+
+// close waits for the end of the decoding of the body, and returns its error.
+func (w *decodingWriter) close() error {
+	if w.pipe == nil {
+		return nil
+	}
+
+	_ = w.pipe.Close()
+	<-w.done
+
+	return w.err
+}
+
+func (w *decodingWriter) transcode(reader *io.PipeReader, encoding, target string) {
+	defer close(w.done)
+
+	w.err = w.copy(reader, encoding, target)
+
+	// Stops the writes of the next handler when the decoding ends before its body.
+	_ = reader.CloseWithError(w.err)
+}
+
+func (w *decodingWriter) copy(reader io.Reader, encoding, target string) error {
+	decoder, err := newDecoder(encoding, reader)
+	if errors.Is(err, io.EOF) {
+		// The body is empty.
+		decoder = ioutil.NopCloser(strings.NewReader(""))
+	} else if err != nil {
+		return err
+	}
+	defer func() { _ = decoder.Close() }()
+
+	if target == "" {
+		_, err = io.Copy(&lockedWriter{mu: &w.mu, w: w.rw}, decoder)
+		return err
+	}
+
+	w.mu.Lock()
+	w.encoder = newEncoder(target, w.rw)
+	w.mu.Unlock()
+
+	if _, err = io.Copy(&lockedWriter{mu: &w.mu, w: w.encoder}, decoder); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.encoder.Close()
+}
+
+// newDecoder returns the decoder of a body with the given content coding.
+// The deflate content coding is the zlib format, but some servers send raw deflate data instead,
+// which is detected from the zlib header.
+func newDecoder(encoding string, reader io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip":
+		return gzip.NewReader(reader)
+
+	case "deflate":
+		br := bufio.NewReader(reader)
+
+		header, err := br.Peek(2)
+		if len(header) == 0 {
+			return nil, err
+		}
+
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+
+		return flate.NewReader(br), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// newEncoder returns the encoder of a body with the given content coding.
+func newEncoder(encoding string, writer io.Writer) encoder {
+	if encoding == "deflate" {
+		return zlib.NewWriter(writer)
+	}
+
+	return gzip.NewWriter(writer)
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.w.Write(p)
+}
+
+// addVary adds the header name to the Vary header, unless it is already listed.
+func addVary(header http.Header, name string) {
+	for _, value := range header.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "*" || strings.EqualFold(field, name) {
+				return
+			}
+		}
+	}
+
+	header.Add("Vary", name)
+}
@@ -0,0 +1,66 @@
+package earlyhints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	typeName = "EarlyHints"
+)
+
+// earlyHints is a middleware sending the configured links in a 103 (Early Hints) response,
+// so that the clients can preload the assets while the final response is being prepared.
+type earlyHints struct {
+	next  http.Handler
+	name  string
+	links []string
+}
+
+// New creates an early hints middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.EarlyHints, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Links) == 0 {
+		return nil, errors.New("at least one link must be defined")
+	}
+
+	for _, link := range config.Links {
+		if link == "" || !httpguts.ValidHeaderFieldValue(link) {
+			return nil, fmt.Errorf("invalid link %q", link)
+		}
+	}
+
+	return &earlyHints{
+		next:  next,
+		name:  name,
+		links: config.Links,
+	}, nil
+}
+
+func (e *earlyHints) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return e.name, tracing.SpanKindNoneEnum
+}
+
+func (e *earlyHints) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The links are kept in the final response, for the clients ignoring the informational responses.
+	for _, link := range e.links {
+		rw.Header().Add("Link", link)
+	}
+
+	// HTTP/1.0 clients do not expect informational responses.
+	if req.ProtoAtLeast(1, 1) {
+		writeEarlyHints(rw)
+	}
+
+	e.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,10 @@
+// +build go1.19
+
+package earlyhints
+
+import "net/http"
+
+// writeEarlyHints sends the headers of rw in a 103 (Early Hints) response.
+func writeEarlyHints(rw http.ResponseWriter) {
+	rw.WriteHeader(http.StatusEarlyHints)
+}
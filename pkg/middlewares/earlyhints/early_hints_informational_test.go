@@ -0,0 +1,57 @@
+// +build go1.19
+
+package earlyhints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestEarlyHints(t *testing.T) {
+	links := []string{"</style.css>; rel=preload; as=style", "</script.js>; rel=preload; as=script"}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.EarlyHints{Links: links}, "traefikTest")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var hints []http.Header
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, http.Header(header))
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Len(t, hints, 1)
+	assert.Equal(t, links, hints[0].Values("Link"))
+	assert.Empty(t, hints[0].Get("Content-Type"))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, links, resp.Header.Values("Link"))
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+}
@@ -0,0 +1,9 @@
+// +build !go1.19
+
+package earlyhints
+
+import "net/http"
+
+// writeEarlyHints does nothing, as the HTTP server takes the first status code written for the one of the final response
+// before Go 1.19, the links being only sent in the final response.
+func writeEarlyHints(http.ResponseWriter) {}
@@ -0,0 +1,74 @@
+package earlyhints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		links         []string
+		expectedError bool
+	}{
+		{
+			desc:  "links",
+			links: []string{"</style.css>; rel=preload; as=style", "</script.js>; rel=preload; as=script"},
+		},
+		{
+			desc:          "no link",
+			expectedError: true,
+		},
+		{
+			desc:          "empty link",
+			links:         []string{""},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid link",
+			links:         []string{"</style.css>\r\nX-Foo: bar"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			_, err := New(context.Background(), next, dynamic.EarlyHints{Links: test.links}, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEarlyHints_HTTP10(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusAccepted)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.EarlyHints{Links: []string{"</style.css>; rel=preload; as=style"}}, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Proto = "HTTP/1.0"
+	req.ProtoMajor, req.ProtoMinor = 1, 0
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	// No informational response is sent to an HTTP/1.0 client, the links are only in the final response.
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, []string{"</style.css>; rel=preload; as=style"}, recorder.Header().Values("Link"))
+}
@@ -0,0 +1,299 @@
+package errorresponses
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const typeName = "ErrorResponses"
+
+// The media types of the structured error bodies.
+const (
+	mediaTypeJSON        = "application/json"
+	mediaTypeProblemJSON = "application/problem+json"
+)
+
+// maxDetailSize is the maximum size of the plain-text body of an error kept as its detail.
+const maxDetailSize = 1024
+
+const defaultJSONTemplate = `{"status":{{ .Status }},"title":{{ json .Title }},"detail":{{ json .Detail }}}`
+
+const defaultProblemJSONTemplate = `{"type":"about:blank","title":{{ json .Title }},"status":{{ .Status }},"detail":{{ json .Detail }},"instance":{{ json .Instance }}}`
+
+// Error is the data given to the templates of the error bodies.
+type Error struct {
+	// Status is the status code of the response.
+	Status int
+	// Title is the text of the status code.
+	Title string
+	// Detail is the plain-text body written by Traefik.
+	Detail string
+	// Instance is the path of the request.
+	Instance string
+}
+
+type key struct{}
+
+// origin tells whether the response of a request comes from a server.
+type origin struct {
+	upstream int32
+}
+
+// MarkUpstream records that the response of the request comes from a server,
+// so that its errors are forwarded unchanged.
+func MarkUpstream(req *http.Request) {
+	if o, ok := req.Context().Value(key{}).(*origin); ok {
+		atomic.StoreInt32(&o.upstream, 1)
+	}
+}
+
+// Renderer renders the errors generated by Traefik in the format negotiated with the Accept header of the requests.
+type Renderer struct {
+	templates map[string]*template.Template // keyed by media type
+}
+
+// New creates a new Renderer.
+func New(config types.ErrorResponses) (*Renderer, error) {
+	jsonTemplate, problemJSONTemplate := defaultJSONTemplate, defaultProblemJSONTemplate
+	if config.Templates != nil {
+		if config.Templates.JSON != "" {
+			jsonTemplate = config.Templates.JSON
+		}
+		if config.Templates.ProblemJSON != "" {
+			problemJSONTemplate = config.Templates.ProblemJSON
+		}
+	}
+
+	templates := make(map[string]*template.Template)
+	for mediaType, text := range map[string]string{mediaTypeJSON: jsonTemplate, mediaTypeProblemJSON: problemJSONTemplate} {
+		tmpl, err := template.New(mediaType).Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of the %s error bodies: %w", mediaType, err)
+		}
+		templates[mediaType] = tmpl
+	}
+
+	return &Renderer{templates: templates}, nil
+}
+
+// WrapHandler wraps the error responses middleware into an alice.Constructor.
+func (r *Renderer) WrapHandler(ctx context.Context) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		log.FromContext(middlewares.GetLoggerCtx(ctx, "errorresponses", typeName)).Debug("Creating middleware")
+
+		return &errorResponses{renderer: r, next: next}, nil
+	}
+}
+
+// errorResponses replaces the plain-text bodies of the errors generated by Traefik,
+// such as the ones of the unknown routes, of the unreachable servers, or of the rejections of the middlewares,
+// with the structured ones negotiated with the Accept header.
+type errorResponses struct {
+	renderer *Renderer
+	next     http.Handler
+}
+
+func (e *errorResponses) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mediaType := negotiate(req.Header.Get("Accept"))
+	if mediaType == "" {
+		e.next.ServeHTTP(rw, req)
+		return
+	}
+
+	o := &origin{}
+	writer := &responseWriter{rw: rw, origin: o}
+
+	e.next.ServeHTTP(writer, req.WithContext(context.WithValue(req.Context(), key{}, o)))
+
+	if !writer.intercepted {
+		return
+	}
+
+	e.renderer.render(rw, mediaType, Error{
+		Status:   writer.code,
+		Title:    http.StatusText(writer.code),
+		Detail:   strings.TrimSpace(writer.detail.String()),
+		Instance: req.URL.Path,
+	})
+}
+
+func (r *Renderer) render(rw http.ResponseWriter, mediaType string, data Error) {
+	var body bytes.Buffer
+	if err := r.templates[mediaType].Execute(&body, data); err != nil {
+		log.WithoutContext().Errorf("Unable to render the %s error body: %v", mediaType, err)
+		body.Reset()
+		body.WriteString(data.Title)
+		mediaType = "text/plain; charset=utf-8"
+	}
+
+	rw.Header().Set("Content-Type", mediaType)
+	rw.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	rw.WriteHeader(data.Status)
+
+	if _, err := rw.Write(body.Bytes()); err != nil {
+		log.WithoutContext().Debugf("Error while writing the error body: %v", err)
+	}
+}
+
+// negotiate returns the media type of the structured error bodies preferred by the client, if any.
+// The structured formats are only chosen when the client explicitly accepts them,
+// at least as much as plain text, so that the browsers and the other clients accepting */* keep the plain-text errors.
+func negotiate(accept string) string {
+	if accept == "" {
+		return ""
+	}
+
+	var qJSON, qProblemJSON, qText float64
+	var textSpecificity int
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case mediaTypeJSON:
+			qJSON = q
+		case mediaTypeProblemJSON:
+			qProblemJSON = q
+		case "text/plain":
+			qText, textSpecificity = q, 3
+		case "text/*":
+			if textSpecificity < 2 {
+				qText, textSpecificity = q, 2
+			}
+		case "*/*":
+			if textSpecificity < 1 {
+				qText, textSpecificity = q, 1
+			}
+		}
+	}
+
+	switch {
+	case qProblemJSON > 0 && qProblemJSON >= qJSON && qProblemJSON >= qText:
+		return mediaTypeProblemJSON
+	case qJSON > 0 && qJSON >= qText:
+		return mediaTypeJSON
+	default:
+		return ""
+	}
+}
+
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// responseWriter intercepts the plain-text error responses generated by Traefik,
+// keeping the beginning of their body as the detail of the error.
+type responseWriter struct {
+	rw     http.ResponseWriter
+	origin *origin
+
+	headerWritten bool
+	intercepted   bool
+	code          int
+	detail        bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.headerWritten {
+		return
+	}
+
+	// The informational responses precede the final one, which can still be replaced.
+	if middlewares.IsInformational(code) {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.headerWritten = true
+
+	if code >= http.StatusBadRequest && atomic.LoadInt32(&w.origin.upstream) == 0 && isPlainText(w.rw.Header().Get("Content-Type")) {
+		w.intercepted = true
+		w.code = code
+		return
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.intercepted {
+		if remaining := maxDetailSize - w.detail.Len(); remaining > 0 {
+			if len(b) > remaining {
+				w.detail.Write(b[:remaining])
+			} else {
+				w.detail.Write(b)
+			}
+		}
+		return len(b), nil
+	}
+
+	return w.rw.Write(b)
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.rw.(http.Hijacker); ok {
+		w.headerWritten = true
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", w.rw)
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.intercepted {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// isPlainText reports whether the content type is the one of the errors generated by Traefik,
+// which are either written with http.Error, or without any content type.
+func isPlainText(contentType string) bool {
+	return contentType == "" || strings.HasPrefix(contentType, "text/plain")
+}
@@ -0,0 +1,200 @@
+package errorresponses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		accept   string
+		expected string
+	}{
+		{
+			desc: "no Accept header",
+		},
+		{
+			desc:   "any media type",
+			accept: "*/*",
+		},
+		{
+			desc:   "browser",
+			accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		},
+		{
+			desc:     "JSON",
+			accept:   "application/json",
+			expected: "application/json",
+		},
+		{
+			desc:     "JSON preferred over any media type",
+			accept:   "application/json, text/plain, */*",
+			expected: "application/json",
+		},
+		{
+			desc:   "plain text preferred over JSON",
+			accept: "application/json;q=0.5, text/plain",
+		},
+		{
+			desc:   "plain text preferred over JSON with a wildcard",
+			accept: "application/json;q=0.5, text/*",
+		},
+		{
+			desc:     "JSON preferred over a less specific plain text",
+			accept:   "application/json;q=0.9, text/*;q=0.5, */*",
+			expected: "application/json",
+		},
+		{
+			desc:     "problem+json",
+			accept:   "application/problem+json, application/json",
+			expected: "application/problem+json",
+		},
+		{
+			desc:     "JSON preferred over problem+json",
+			accept:   "application/problem+json;q=0.5, application/json",
+			expected: "application/json",
+		},
+		{
+			desc:   "JSON not acceptable",
+			accept: "application/json;q=0",
+		},
+		{
+			desc:   "invalid media range",
+			accept: "application/json;q=foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, negotiate(test.accept))
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		config              types.ErrorResponses
+		accept              string
+		next                http.HandlerFunc
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:   "problem+json error generated by Traefik",
+			accept: "application/problem+json",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Retry-After", "1")
+				http.Error(rw, "Service Unavailable", http.StatusServiceUnavailable)
+			},
+			expectedStatus:      http.StatusServiceUnavailable,
+			expectedContentType: "application/problem+json",
+			expectedBody:        `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"Service Unavailable","instance":"/foo"}`,
+		},
+		{
+			desc:   "JSON error without content type",
+			accept: "application/json",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusBadGateway)
+				_, _ = rw.Write([]byte(`Bad "Gateway"`))
+			},
+			expectedStatus:      http.StatusBadGateway,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":502,"title":"Bad Gateway","detail":"Bad \"Gateway\""}`,
+		},
+		{
+			desc: "template override",
+			config: types.ErrorResponses{
+				Templates: &types.ErrorResponseTemplates{
+					JSON: `{"code":{{ .Status }},"message":{{ json .Title }}}`,
+				},
+			},
+			accept:              "application/json",
+			next:                http.NotFound,
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":404,"message":"Not Found"}`,
+		},
+		{
+			desc:                "plain-text error negotiated",
+			accept:              "text/plain",
+			next:                http.NotFound,
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "404 page not found\n",
+		},
+		{
+			desc:   "error of a server",
+			accept: "application/json",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				MarkUpstream(req)
+				http.Error(rw, "boom", http.StatusInternalServerError)
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "boom\n",
+		},
+		{
+			desc:   "structured error",
+			accept: "application/json",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "application/json")
+				rw.WriteHeader(http.StatusNotFound)
+				_, _ = rw.Write([]byte(`{"message":"not found"}`))
+			},
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: "application/json",
+			expectedBody:        `{"message":"not found"}`,
+		},
+		{
+			desc:   "success",
+			accept: "application/json",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("OK"))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			renderer, err := New(test.config)
+			require.NoError(t, err)
+
+			handler, err := renderer.WrapHandler(context.Background())(test.next)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/foo", nil)
+			req.Header.Set("Accept", test.accept)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+			assert.Equal(t, test.expectedContentType, rw.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, rw.Body.String())
+		})
+	}
+}
+
+func TestNew_invalidTemplate(t *testing.T) {
+	_, err := New(types.ErrorResponses{
+		Templates: &types.ErrorResponseTemplates{ProblemJSON: "{{ .Status"},
+	})
+	require.Error(t, err)
+}
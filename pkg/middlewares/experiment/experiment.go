@@ -0,0 +1,206 @@
+package experiment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/server/cookie"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Experiment"
+)
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+type variant struct {
+	name    string
+	weight  uint64
+	handler http.Handler
+}
+
+// experiment is a middleware that deterministically assigns the clients to variants,
+// and forwards their requests to the service of their variant.
+type experiment struct {
+	name          string
+	variants      []variant
+	totalWeight   uint64
+	header        string
+	cookie        dynamic.Cookie
+	variantHeader string
+	assignments   gokitmetrics.Counter
+}
+
+// New creates an experiment middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Experiment, serviceBuilder serviceBuilder, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Variants) == 0 {
+		return nil, errors.New("at least one variant must be defined")
+	}
+
+	e := &experiment{
+		name:          name,
+		header:        config.Header,
+		variantHeader: config.VariantHeader,
+	}
+
+	if config.Cookie != nil {
+		e.cookie = *config.Cookie
+	}
+	e.cookie.Name = cookie.GetName(e.cookie.Name, name)
+
+	names := make(map[string]struct{})
+	for _, cfg := range config.Variants {
+		if cfg.Name == "" {
+			return nil, errors.New("variant name is required")
+		}
+
+		if _, ok := names[cfg.Name]; ok {
+			return nil, fmt.Errorf("variant %q is defined several times", cfg.Name)
+		}
+		names[cfg.Name] = struct{}{}
+
+		weight := 1
+		if cfg.Weight != nil {
+			weight = *cfg.Weight
+		}
+
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of variant %q must be positive", cfg.Name)
+		}
+
+		handler := next
+		if cfg.Service != "" {
+			var err error
+			handler, err = serviceBuilder.BuildHTTP(ctx, cfg.Service)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		e.variants = append(e.variants, variant{name: cfg.Name, weight: uint64(weight), handler: handler})
+		e.totalWeight += uint64(weight)
+	}
+
+	if e.totalWeight == 0 {
+		return nil, errors.New("at least one variant must have a positive weight")
+	}
+
+	if metricsRegistry != nil && metricsRegistry.IsEpEnabled() {
+		e.assignments = metricsRegistry.RouterExperimentAssignmentsCounter()
+	}
+
+	return e, nil
+}
+
+func (e *experiment) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return e.name, tracing.SpanKindNoneEnum
+}
+
+func (e *experiment) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	clientID := e.clientID(rw, req)
+	if clientID == "" {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), e.name, typeName)).Error("Unable to identify the client")
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	v := e.assign(clientID)
+
+	if e.assignments != nil {
+		e.assignments.With("experiment", e.name, "variant", v.name).Add(1)
+	}
+
+	if e.variantHeader != "" {
+		req.Header.Set(e.variantHeader, v.name)
+	}
+
+	v.handler.ServeHTTP(rw, req)
+}
+
+// clientID returns the identifier of the client, from the configured header or cookie.
+// A new identifier is issued in a cookie if the client has none.
+func (e *experiment) clientID(rw http.ResponseWriter, req *http.Request) string {
+	if e.header != "" {
+		if id := req.Header.Get(e.header); id != "" {
+			return id
+		}
+	}
+
+	if c, err := req.Cookie(e.cookie.Name); err == nil && c.Value != "" {
+		return c.Value
+	}
+
+	id, err := newClientID()
+	if err != nil {
+		return ""
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     e.cookie.Name,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: e.cookie.HTTPOnly,
+		Secure:   e.cookie.Secure,
+		SameSite: convertSameSite(e.cookie.SameSite),
+	})
+
+	return id
+}
+
+// assign returns the variant of the client, which only depends on the client identifier,
+// the experiment name, and the variant weights.
+func (e *experiment) assign(clientID string) variant {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(e.name))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(clientID))
+
+	bucket := hash.Sum64() % e.totalWeight
+	for _, v := range e.variants {
+		if bucket < v.weight {
+			return v
+		}
+		bucket -= v.weight
+	}
+
+	// Never happens as the bucket is lower than the total weight.
+	return e.variants[len(e.variants)-1]
+}
+
+func newClientID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func convertSameSite(sameSite string) http.SameSite {
+	switch sameSite {
+	case "none":
+		return http.SameSiteNoneMode
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,192 @@
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type serviceBuilderMock map[string]http.Handler
+
+func (s serviceBuilderMock) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	if handler, ok := s[serviceName]; ok {
+		return handler, nil
+	}
+
+	return nil, errors.New("unknown service")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Experiment
+		expectedError bool
+	}{
+		{
+			desc:          "no variant",
+			config:        dynamic.Experiment{},
+			expectedError: true,
+		},
+		{
+			desc: "variant without name",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Service: "a"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "duplicated variant",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Name: "a"}, {Name: "a"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "negative weight",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Name: "a", Weight: intPtr(-1)}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "only null weights",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Name: "a", Weight: intPtr(0)}, {Name: "b", Weight: intPtr(0)}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "unknown service",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Name: "a", Service: "unknown"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid variants",
+			config: dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Name: "a"}, {Name: "b", Service: "b", Weight: intPtr(3)}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			services := serviceBuilderMock{"b": next}
+
+			handler, err := New(context.Background(), next, test.config, services, nil, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestExperiment_ServeHTTP(t *testing.T) {
+	newHandler := func(name string) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = fmt.Fprintf(rw, "%s:%s", name, req.Header.Get("X-Experiment-Variant"))
+		})
+	}
+
+	config := dynamic.Experiment{
+		Header: "X-User-Id",
+		Variants: []dynamic.ExperimentVariant{
+			{Name: "control", Weight: intPtr(1)},
+			{Name: "new-checkout", Service: "checkout-v2", Weight: intPtr(1)},
+		},
+	}
+	config.SetDefaults()
+
+	handler, err := New(context.Background(), newHandler("router"), config, serviceBuilderMock{"checkout-v2": newHandler("checkout-v2")}, nil, "traefikTest")
+	require.NoError(t, err)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// A new client gets an identifier cookie.
+	recorder := serve(httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	require.Len(t, recorder.Result().Cookies(), 1)
+
+	clientCookie := recorder.Result().Cookies()[0]
+	assert.NotEmpty(t, clientCookie.Value)
+	firstBody := recorder.Body.String()
+
+	// The client keeps its variant.
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.AddCookie(clientCookie)
+
+		recorder = serve(req)
+		assert.Empty(t, recorder.Result().Cookies())
+		assert.Equal(t, firstBody, recorder.Body.String())
+	}
+
+	// The clients identified by header are assigned to both variants,
+	// and the variant header sent by the client is overridden.
+	bodies := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("X-User-Id", fmt.Sprintf("user-%d", i))
+		req.Header.Set("X-Experiment-Variant", "spoofed")
+
+		recorder = serve(req)
+		assert.Empty(t, recorder.Result().Cookies())
+		bodies[recorder.Body.String()]++
+	}
+
+	assert.Len(t, bodies, 2)
+	assert.NotZero(t, bodies["router:control"])
+	assert.NotZero(t, bodies["checkout-v2:new-checkout"])
+}
+
+func TestExperiment_assign(t *testing.T) {
+	config := dynamic.Experiment{
+		Variants: []dynamic.ExperimentVariant{
+			{Name: "a", Weight: intPtr(3)},
+			{Name: "b", Weight: intPtr(1)},
+			{Name: "disabled", Weight: intPtr(0)},
+		},
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	handler, err := New(context.Background(), next, config, serviceBuilderMock{}, nil, "traefikTest")
+	require.NoError(t, err)
+
+	e := handler.(*experiment)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		clientID := fmt.Sprintf("client-%d", i)
+
+		v := e.assign(clientID)
+		assert.Equal(t, v.name, e.assign(clientID).name)
+		counts[v.name]++
+	}
+
+	assert.Zero(t, counts["disabled"])
+	assert.InDelta(t, 7500, counts["a"], 300)
+	assert.InDelta(t, 2500, counts["b"], 300)
+}
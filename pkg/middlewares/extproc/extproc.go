@@ -0,0 +1,299 @@
+package extproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	typeName = "ExternalProcessor"
+)
+
+const (
+	failureModeAllow = "allow"
+	failureModeDeny  = "deny"
+
+	defaultTimeout     = time.Second
+	defaultMaxBodySize = 1024 * 1024
+)
+
+// The connections are shared by the middlewares calling the same processor,
+// as the middlewares are created again on each configuration change.
+var (
+	connsMu sync.Mutex
+	conns   = make(map[string]*grpc.ClientConn)
+)
+
+// externalProcessor is a middleware calling an external gRPC service to process the requests and the responses.
+type externalProcessor struct {
+	name                string
+	next                http.Handler
+	conn                *grpc.ClientConn
+	timeout             time.Duration
+	failOpen            bool
+	processRequestBody  bool
+	processResponse     bool
+	processResponseBody bool
+	maxBodySize         int64
+}
+
+// New creates an external processor middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ExternalProcessor, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+
+	e := &externalProcessor{
+		name:                name,
+		next:                next,
+		timeout:             time.Duration(config.Timeout),
+		processRequestBody:  config.ProcessRequestBody,
+		processResponse:     config.ProcessResponse || config.ProcessResponseBody,
+		processResponseBody: config.ProcessResponseBody,
+		maxBodySize:         config.MaxBodySize,
+	}
+
+	switch config.FailureMode {
+	case "", failureModeDeny:
+	case failureModeAllow:
+		e.failOpen = true
+	default:
+		return nil, fmt.Errorf("unknown failure mode: %q", config.FailureMode)
+	}
+
+	if e.timeout <= 0 {
+		e.timeout = defaultTimeout
+	}
+
+	if e.maxBodySize <= 0 {
+		e.maxBodySize = defaultMaxBodySize
+	}
+
+	var err error
+	e.conn, err = getConn(config.Address, config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func getConn(address string, clientTLS *dynamic.ClientTLS) (*grpc.ClientConn, error) {
+	key := address
+	if clientTLS != nil {
+		key += fmt.Sprintf("|%+v", *clientTLS)
+	}
+
+	connsMu.Lock()
+	defer connsMu.Unlock()
+
+	if conn, ok := conns[key]; ok {
+		return conn, nil
+	}
+
+	opt := grpc.WithInsecure()
+	if clientTLS != nil {
+		tlsConfig, err := clientTLS.CreateTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("unable to create client TLS configuration: %w", err)
+		}
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	// The connection is established in the background, and re-established when needed.
+	conn, err := grpc.Dial(address, opt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the processor %s: %w", address, err)
+	}
+
+	conns[key] = conn
+
+	return conn, nil
+}
+
+func (e *externalProcessor) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return e.name, ext.SpanKindRPCClientEnum
+}
+
+func (e *externalProcessor) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), e.name, typeName))
+
+	procReq := newProcessingRequest(req, PhaseRequest)
+	procReq.Headers = toHeaders(req.Header)
+
+	if e.processRequestBody && req.Body != nil {
+		body, err := ioutil.ReadAll(io.LimitReader(req.Body, e.maxBodySize+1))
+		if err != nil {
+			logger.Debugf("Error while reading the request body: %v", err)
+			http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if int64(len(body)) > e.maxBodySize {
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		procReq.Body = body
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	resp, err := e.process(req.Context(), procReq)
+	if err != nil {
+		logger.Debugf("Error while processing the request: %v", err)
+		tracing.SetErrorWithEvent(req, "error while processing the request: %v", err)
+
+		if !e.failOpen {
+			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		resp = &ProcessingResponse{}
+	}
+
+	if resp.ImmediateResponse != nil {
+		writeImmediateResponse(rw, resp.ImmediateResponse)
+		return
+	}
+
+	applyHeaders(req.Header, resp)
+
+	if resp.ReplaceBody {
+		req.Body = ioutil.NopCloser(bytes.NewReader(resp.Body))
+		req.ContentLength = int64(len(resp.Body))
+		req.TransferEncoding = nil
+		req.Header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	}
+
+	if !e.processResponse {
+		e.next.ServeHTTP(rw, req)
+		return
+	}
+
+	prw := &responseWriter{
+		rw:     rw,
+		header: make(http.Header),
+		ep:     e,
+		req:    req,
+	}
+
+	e.next.ServeHTTP(prw, req)
+
+	prw.finish()
+}
+
+// process calls the processor.
+func (e *externalProcessor) process(ctx context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	resp := &ProcessingResponse{}
+	if err := e.conn.Invoke(ctx, processMethod, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// processBackendResponse calls the processor with the response of the backend, and returns the mutations to apply.
+// It returns nil when the processor failed and the failure mode denies the response.
+func (e *externalProcessor) processBackendResponse(req *http.Request, code int, header http.Header, body []byte) *ProcessingResponse {
+	procReq := newProcessingRequest(req, PhaseResponse)
+	procReq.Headers = toHeaders(header)
+	procReq.Body = body
+	procReq.StatusCode = int32(code)
+
+	resp, err := e.process(req.Context(), procReq)
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), e.name, typeName)).Debugf("Error while processing the response: %v", err)
+		tracing.SetErrorWithEvent(req, "error while processing the response: %v", err)
+
+		if !e.failOpen {
+			return nil
+		}
+		return &ProcessingResponse{}
+	}
+
+	return resp
+}
+
+func newProcessingRequest(req *http.Request, phase int32) *ProcessingRequest {
+	procReq := &ProcessingRequest{
+		Phase:      phase,
+		Method:     req.Method,
+		Scheme:     "http",
+		Host:       req.Host,
+		Path:       req.URL.RequestURI(),
+		RemoteAddr: req.RemoteAddr,
+	}
+
+	if req.TLS != nil {
+		procReq.Scheme = "https"
+	}
+
+	return procReq
+}
+
+// toHeaders converts HTTP headers to the processor ones, sorted by key.
+func toHeaders(header http.Header) []*Header {
+	headers := make([]*Header, 0, len(header))
+	for key, values := range header {
+		headers = append(headers, &Header{Key: key, Values: values})
+	}
+
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].Key < headers[j].Key
+	})
+
+	return headers
+}
+
+// applyHeaders applies the header mutations of the processor.
+func applyHeaders(header http.Header, resp *ProcessingResponse) {
+	for _, key := range resp.RemoveHeaders {
+		header.Del(key)
+	}
+
+	for _, h := range resp.SetHeaders {
+		header.Del(h.Key)
+		for _, value := range h.Values {
+			header.Add(h.Key, value)
+		}
+	}
+}
+
+func writeImmediateResponse(rw http.ResponseWriter, resp *ImmediateResponse) {
+	for _, h := range resp.Headers {
+		rw.Header().Del(h.Key)
+		for _, value := range h.Values {
+			rw.Header().Add(h.Key, value)
+		}
+	}
+
+	code := int(resp.StatusCode)
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	rw.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	rw.WriteHeader(code)
+	_, _ = rw.Write(resp.Body)
+}
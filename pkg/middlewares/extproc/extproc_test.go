@@ -0,0 +1,268 @@
+package extproc
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type processorFunc func(ctx context.Context, req *ProcessingRequest) (*ProcessingResponse, error)
+
+func (p processorFunc) Process(ctx context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+	return p(ctx, req)
+}
+
+func startProcessor(t *testing.T, processor processorFunc) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	RegisterProcessorServer(server, processor)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestExternalProcessor_request(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.ExternalProcessor
+		processor      processorFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc: "header mutations",
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				assert.Equal(t, PhaseRequest, req.Phase)
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, "/foo?bar=baz", req.Path)
+				assert.Empty(t, req.Body)
+
+				return &ProcessingResponse{
+					SetHeaders:    []*Header{{Key: "X-Foo", Values: []string{"processed"}}},
+					RemoveHeaders: []string{"X-Remove"},
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "processed  body",
+		},
+		{
+			desc:   "body mutation",
+			config: dynamic.ExternalProcessor{ProcessRequestBody: true},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				assert.Equal(t, []byte("body"), req.Body)
+
+				return &ProcessingResponse{ReplaceBody: true, Body: []byte("new body")}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   " foo new body",
+		},
+		{
+			desc: "immediate response",
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return &ProcessingResponse{
+					ImmediateResponse: &ImmediateResponse{StatusCode: http.StatusForbidden, Body: []byte("denied")},
+				}, nil
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "denied",
+		},
+		{
+			desc: "processor failure",
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return nil, status.Error(codes.Unavailable, "unavailable")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error\n",
+		},
+		{
+			desc:   "processor failure allowed",
+			config: dynamic.ExternalProcessor{FailureMode: "allow"},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return nil, status.Error(codes.Unavailable, "unavailable")
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   " foo body",
+		},
+		{
+			desc:   "body too big",
+			config: dynamic.ExternalProcessor{ProcessRequestBody: true, MaxBodySize: 3},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return &ProcessingResponse{}, nil
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   "Request Entity Too Large\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				_, _ = rw.Write([]byte(strings.Join([]string{req.Header.Get("X-Foo"), req.Header.Get("X-Remove"), string(body)}, " ")))
+			})
+
+			config := test.config
+			config.Address = startProcessor(t, test.processor)
+
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/foo?bar=baz", bytes.NewReader([]byte("body")))
+			req.Header.Set("X-Remove", "foo")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestExternalProcessor_response(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.ExternalProcessor
+		processor       processorFunc
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    string
+	}{
+		{
+			desc:   "header mutations",
+			config: dynamic.ExternalProcessor{ProcessResponse: true},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				if req.Phase == PhaseRequest {
+					return &ProcessingResponse{}, nil
+				}
+
+				assert.Equal(t, int32(http.StatusCreated), req.StatusCode)
+				assert.Equal(t, []*Header{{Key: "X-Backend", Values: []string{"foo"}}}, req.Headers)
+				assert.Empty(t, req.Body)
+
+				return &ProcessingResponse{
+					SetHeaders:    []*Header{{Key: "X-Foo", Values: []string{"processed"}}},
+					RemoveHeaders: []string{"X-Backend"},
+				}, nil
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedHeaders: map[string]string{"X-Foo": "processed", "X-Backend": ""},
+			expectedBody:    "backend body",
+		},
+		{
+			desc:   "body mutation",
+			config: dynamic.ExternalProcessor{ProcessResponseBody: true},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				if req.Phase == PhaseRequest {
+					return &ProcessingResponse{}, nil
+				}
+
+				assert.Equal(t, []byte("backend body"), req.Body)
+
+				return &ProcessingResponse{ReplaceBody: true, Body: []byte("new body")}, nil
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedHeaders: map[string]string{"X-Backend": "foo", "Content-Length": "8"},
+			expectedBody:    "new body",
+		},
+		{
+			desc:   "immediate response",
+			config: dynamic.ExternalProcessor{ProcessResponseBody: true},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				if req.Phase == PhaseRequest {
+					return &ProcessingResponse{}, nil
+				}
+
+				return &ProcessingResponse{
+					ImmediateResponse: &ImmediateResponse{StatusCode: http.StatusBadGateway, Body: []byte("replaced")},
+				}, nil
+			},
+			expectedStatus:  http.StatusBadGateway,
+			expectedHeaders: map[string]string{"X-Backend": ""},
+			expectedBody:    "replaced",
+		},
+		{
+			desc:   "body too big",
+			config: dynamic.ExternalProcessor{ProcessResponseBody: true, MaxBodySize: 5},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return &ProcessingResponse{}, nil
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error\n",
+		},
+		{
+			desc:   "body too big allowed",
+			config: dynamic.ExternalProcessor{ProcessResponseBody: true, MaxBodySize: 5, FailureMode: "allow"},
+			processor: func(_ context.Context, req *ProcessingRequest) (*ProcessingResponse, error) {
+				return &ProcessingResponse{}, nil
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedHeaders: map[string]string{"X-Backend": "foo"},
+			expectedBody:    "backend body",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Backend", "foo")
+				rw.WriteHeader(http.StatusCreated)
+				_, _ = rw.Write([]byte("backend"))
+				_, _ = rw.Write([]byte(" body"))
+			})
+
+			config := test.config
+			config.Address = startProcessor(t, test.processor)
+
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			for key, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(key), key)
+			}
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestNew_invalidConfig(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.ExternalProcessor{}, "traefikTest")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.ExternalProcessor{Address: "localhost:1234", FailureMode: "foo"}, "traefikTest")
+	assert.Error(t, err)
+}
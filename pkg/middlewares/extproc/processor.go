@@ -0,0 +1,109 @@
+package extproc
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// The messages and the service below are defined in processor.proto,
+// which can be used to generate the processors in any language supported by gRPC.
+
+const processMethod = "/traefik.extproc.v1.Processor/Process"
+
+// The phases of the processing.
+const (
+	PhaseRequest  int32 = 0
+	PhaseResponse int32 = 1
+)
+
+// Header is an HTTP header.
+type Header struct {
+	Key    string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *Header) Reset()         { *m = Header{} }
+func (m *Header) String() string { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()    {}
+
+// ProcessingRequest is the request, or the response, to process.
+type ProcessingRequest struct {
+	Phase      int32     `protobuf:"varint,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Method     string    `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Scheme     string    `protobuf:"bytes,3,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	Host       string    `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	Path       string    `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	RemoteAddr string    `protobuf:"bytes,6,opt,name=remote_addr,json=remoteAddr,proto3" json:"remote_addr,omitempty"`
+	Headers    []*Header `protobuf:"bytes,7,rep,name=headers,proto3" json:"headers,omitempty"`
+	Body       []byte    `protobuf:"bytes,8,opt,name=body,proto3" json:"body,omitempty"`
+	StatusCode int32     `protobuf:"varint,9,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+}
+
+func (m *ProcessingRequest) Reset()         { *m = ProcessingRequest{} }
+func (m *ProcessingRequest) String() string { return proto.CompactTextString(m) }
+func (*ProcessingRequest) ProtoMessage()    {}
+
+// ProcessingResponse holds the mutations to apply on the request, or on the response.
+type ProcessingResponse struct {
+	SetHeaders        []*Header          `protobuf:"bytes,1,rep,name=set_headers,json=setHeaders,proto3" json:"set_headers,omitempty"`
+	RemoveHeaders     []string           `protobuf:"bytes,2,rep,name=remove_headers,json=removeHeaders,proto3" json:"remove_headers,omitempty"`
+	ReplaceBody       bool               `protobuf:"varint,3,opt,name=replace_body,json=replaceBody,proto3" json:"replace_body,omitempty"`
+	Body              []byte             `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	ImmediateResponse *ImmediateResponse `protobuf:"bytes,5,opt,name=immediate_response,json=immediateResponse,proto3" json:"immediate_response,omitempty"`
+}
+
+func (m *ProcessingResponse) Reset()         { *m = ProcessingResponse{} }
+func (m *ProcessingResponse) String() string { return proto.CompactTextString(m) }
+func (*ProcessingResponse) ProtoMessage()    {}
+
+// ImmediateResponse is the response sent to the client instead of forwarding the request,
+// or instead of sending the response of the backend.
+type ImmediateResponse struct {
+	StatusCode int32     `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Headers    []*Header `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty"`
+	Body       []byte    `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+}
+
+func (m *ImmediateResponse) Reset()         { *m = ImmediateResponse{} }
+func (m *ImmediateResponse) String() string { return proto.CompactTextString(m) }
+func (*ImmediateResponse) ProtoMessage()    {}
+
+// ProcessorServer is the server API of an external processor.
+type ProcessorServer interface {
+	Process(ctx context.Context, req *ProcessingRequest) (*ProcessingResponse, error)
+}
+
+// RegisterProcessorServer registers an external processor on a gRPC server.
+func RegisterProcessorServer(s *grpc.Server, srv ProcessorServer) {
+	s.RegisterService(&processorServiceDesc, srv)
+}
+
+var processorServiceDesc = grpc.ServiceDesc{
+	ServiceName: "traefik.extproc.v1.Processor",
+	HandlerType: (*ProcessorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Process",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(ProcessingRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+
+				if interceptor == nil {
+					return srv.(ProcessorServer).Process(ctx, in)
+				}
+
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: processMethod}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(ProcessorServer).Process(ctx, req.(*ProcessingRequest))
+				}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "processor.proto",
+}
@@ -0,0 +1,183 @@
+package extproc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+// responseWriter holds the response of the backend until it has been processed.
+// Only the headers are held when the body is not processed.
+type responseWriter struct {
+	rw     http.ResponseWriter
+	header http.Header
+	ep     *externalProcessor
+	req    *http.Request
+
+	code          int
+	headerWritten bool
+	body          bytes.Buffer
+
+	// passThrough is true once the processed headers have been sent, and the body is forwarded as is.
+	passThrough bool
+	// done is true once the response has been sent, and the rest of the backend response must be discarded.
+	done bool
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.header
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if r.headerWritten {
+		return
+	}
+
+	// The informational responses precede the final one, which is the one processed.
+	if middlewares.IsInformational(code) {
+		middlewares.WriteInformational(r.rw, code, r.header)
+		return
+	}
+
+	r.headerWritten = true
+	r.code = code
+
+	// The headers are processed with the body, once the backend response is complete.
+	if r.ep.processResponseBody {
+		return
+	}
+
+	r.send(r.ep.processBackendResponse(r.req, code, r.header, nil), nil, false)
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.done {
+		return len(p), nil
+	}
+
+	if r.passThrough {
+		return r.rw.Write(p)
+	}
+
+	if int64(r.body.Len()+len(p)) <= r.ep.maxBodySize {
+		return r.body.Write(p)
+	}
+
+	log.FromContext(middlewares.GetLoggerCtx(r.req.Context(), r.ep.name, typeName)).
+		Debugf("Response body bigger than %d bytes, not processed", r.ep.maxBodySize)
+
+	if !r.ep.failOpen {
+		r.send(nil, nil, false)
+		return len(p), nil
+	}
+
+	// The response is sent without the processor mutations.
+	r.send(&ProcessingResponse{}, r.body.Bytes(), false)
+	r.body.Reset()
+
+	return r.rw.Write(p)
+}
+
+// finish processes the response, if it has not been done yet, once the backend response is complete.
+func (r *responseWriter) finish() {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.done || r.passThrough {
+		return
+	}
+
+	r.send(r.ep.processBackendResponse(r.req, r.code, r.header, r.body.Bytes()), r.body.Bytes(), true)
+}
+
+// send sends the response with the processor mutations to the client.
+// The given body is the backend one, which is complete when buffered is true.
+func (r *responseWriter) send(resp *ProcessingResponse, body []byte, buffered bool) {
+	if resp == nil {
+		r.done = true
+		http.Error(r.rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if resp.ImmediateResponse != nil {
+		r.done = true
+		writeImmediateResponse(r.rw, resp.ImmediateResponse)
+		return
+	}
+
+	applyHeaders(r.header, resp)
+
+	header := r.rw.Header()
+	for key, values := range r.header {
+		header[key] = values
+	}
+
+	// The headers set afterwards by the backend, such as the trailers, are sent to the client.
+	r.header = header
+
+	if resp.ReplaceBody {
+		r.done = true
+		header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+		r.rw.WriteHeader(r.code)
+		_, _ = r.rw.Write(resp.Body)
+		return
+	}
+
+	if buffered {
+		r.done = true
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	} else {
+		r.passThrough = true
+	}
+
+	r.rw.WriteHeader(r.code)
+
+	if len(body) > 0 {
+		_, _ = r.rw.Write(body)
+	}
+}
+
+// Flush sends any buffered data to the client, unless the body has to be processed.
+func (r *responseWriter) Flush() {
+	if !r.ep.processResponseBody && !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.passThrough {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+}
+
+// CloseNotify returns a channel that receives at most a single value (true)
+// when the client connection has gone away.
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if n, ok := r.rw.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+
+	return make(<-chan bool)
+}
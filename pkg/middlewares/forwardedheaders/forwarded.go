@@ -0,0 +1,178 @@
+package forwardedheaders
+
+import (
+	"net"
+	"strings"
+)
+
+// forwardedElement is a forwarded-element of an RFC 7239 Forwarded header,
+// i.e. the information added by one of the proxies of the forwarding chain.
+type forwardedElement struct {
+	For   string
+	Host  string
+	Proto string
+}
+
+// String formats the element as a forwarded-element of an RFC 7239 Forwarded header.
+func (e forwardedElement) String() string {
+	var pairs []string
+	for _, pair := range []struct{ key, value string }{
+		{key: "for", value: e.For},
+		{key: "host", value: e.Host},
+		{key: "proto", value: e.Proto},
+	} {
+		if pair.value != "" {
+			pairs = append(pairs, pair.key+"="+quoteForwardedValue(pair.value))
+		}
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+// forwardedNode formats an IP as a node of a Forwarded header, where IPv6 addresses are enclosed in brackets.
+func forwardedNode(ip string) string {
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]"
+	}
+
+	return ip
+}
+
+// nodeIP returns the IP of a node of a Forwarded header, without its brackets and port.
+// Obfuscated identifiers and the unknown identifier are returned as is.
+func nodeIP(node string) string {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+}
+
+// quoteForwardedValue returns the value as a token, or as a quoted-string when it contains non-token characters.
+func quoteForwardedValue(value string) string {
+	if isToken(value) {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		if value[i] == '"' || value[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(value[i])
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// parseForwarded parses the values of RFC 7239 Forwarded headers,
+// and returns their forwarded-elements from the closest to the client to the closest to Traefik.
+// The malformed pairs are ignored.
+func parseForwarded(values []string) []forwardedElement {
+	var elements []forwardedElement
+	for _, value := range values {
+		for _, rawElement := range splitQuoted(value, ',') {
+			var element forwardedElement
+			for _, pair := range splitQuoted(rawElement, ';') {
+				key, val, ok := parsePair(pair)
+				if !ok {
+					continue
+				}
+
+				switch strings.ToLower(key) {
+				case "for":
+					element.For = val
+				case "host":
+					element.Host = val
+				case "proto":
+					element.Proto = strings.ToLower(val)
+				}
+			}
+
+			elements = append(elements, element)
+		}
+	}
+
+	return elements
+}
+
+// parsePair parses a forwarded-pair, unquoting its value.
+func parsePair(pair string) (string, string, bool) {
+	i := strings.IndexByte(pair, '=')
+	if i <= 0 {
+		return "", "", false
+	}
+
+	key := strings.TrimSpace(pair[:i])
+	value := strings.TrimSpace(pair[i+1:])
+	if !isToken(key) {
+		return "", "", false
+	}
+
+	if !strings.HasPrefix(value, `"`) {
+		return key, value, isToken(value)
+	}
+
+	if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+		return "", "", false
+	}
+
+	var b strings.Builder
+	escaped := false
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteByte(value[i])
+	}
+
+	return key, b.String(), true
+}
+
+// splitQuoted splits the value on the separator, except within quoted-strings.
+func splitQuoted(value string, sep byte) []string {
+	var parts []string
+
+	start := 0
+	quoted := false
+	escaped := false
+	for i := 0; i < len(value); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case value[i] == '\\' && quoted:
+			escaped = true
+		case value[i] == '"':
+			quoted = !quoted
+		case value[i] == sep && !quoted:
+			parts = append(parts, strings.TrimSpace(value[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(value[start:]))
+}
+
+// isToken reports whether the value is a non-empty token as defined by RFC 7230.
+func isToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+			continue
+		}
+
+		if !strings.ContainsRune("!#$%&'*+-.^_`|~", rune(c)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -20,6 +20,7 @@ const (
 	xForwardedTLSClientCert     = "X-Forwarded-Tls-Client-Cert"
 	xForwardedTLSClientCertInfo = "X-Forwarded-Tls-Client-Cert-Info"
 	xRealIP                     = "X-Real-Ip"
+	forwarded                   = "Forwarded"
 	connection                  = "Connection"
 	upgrade                     = "Upgrade"
 )
@@ -35,6 +36,7 @@ var xHeaders = []string{
 	xForwardedTLSClientCert,
 	xForwardedTLSClientCertInfo,
 	xRealIP,
+	forwarded,
 }
 
 // XForwarded is an HTTP handler wrapper that sets the X-Forwarded headers,
@@ -47,6 +49,10 @@ type XForwarded struct {
 	ipChecker  *ip.Checker
 	next       http.Handler
 	hostname   string
+
+	forwarded     bool
+	clientIP      bool
+	clientIPDepth int
 }
 
 // NewXForwarded creates a new XForwarded.
@@ -74,6 +80,20 @@ func NewXForwarded(insecure bool, trustedIps []string, next http.Handler) (*XFor
 	}, nil
 }
 
+// SetForwarded makes the handler append a forwarded-element describing the request to the RFC 7239 Forwarded header.
+func (x *XForwarded) SetForwarded(forwarded bool) {
+	x.forwarded = forwarded
+}
+
+// SetClientIP makes the handler derive the client IP of the requests from the forwarded headers,
+// and hold it in the request context for the middlewares and the access logs.
+// With a zero depth, the client IP is the closest IP of the forwarding chain which is not a trusted one,
+// otherwise it is the IP at the given depth from the right of the forwarding chain.
+func (x *XForwarded) SetClientIP(depth int) {
+	x.clientIP = true
+	x.clientIPDepth = depth
+}
+
 func (x *XForwarded) isTrustedIP(ip string) bool {
 	if x.ipChecker == nil {
 		return false
@@ -158,6 +178,84 @@ func (x *XForwarded) rewrite(outreq *http.Request) {
 	if x.hostname != "" {
 		outreq.Header.Set(xForwardedServer, x.hostname)
 	}
+
+	if x.forwarded {
+		x.appendForwarded(outreq)
+	}
+}
+
+// appendForwarded appends the forwarded-element describing the request to the Forwarded header.
+func (x *XForwarded) appendForwarded(outreq *http.Request) {
+	element := forwardedElement{
+		Host:  outreq.Host,
+		Proto: "http",
+	}
+
+	if outreq.TLS != nil {
+		element.Proto = "https"
+	}
+
+	if clientIP, _, err := net.SplitHostPort(outreq.RemoteAddr); err == nil {
+		element.For = forwardedNode(removeIPv6Zone(clientIP))
+	}
+
+	value := element.String()
+	if prior := strings.Join(outreq.Header.Values(forwarded), ", "); prior != "" {
+		value = prior + ", " + value
+	}
+
+	outreq.Header.Set(forwarded, value)
+}
+
+// forwardingChain returns the IPs of the forwarding chain of the request, from the client to the remote address.
+// The chain is read from the Forwarded header if any, otherwise from the X-Forwarded-For header.
+func forwardingChain(req *http.Request, remoteIP string) []string {
+	var chain []string
+	if values := req.Header.Values(forwarded); len(values) > 0 {
+		for _, element := range parseForwarded(values) {
+			if element.For != "" {
+				chain = append(chain, nodeIP(element.For))
+			}
+		}
+	} else {
+		for _, value := range req.Header.Values(xForwardedFor) {
+			for _, xff := range strings.Split(value, ",") {
+				if xff = strings.TrimSpace(xff); xff != "" {
+					chain = append(chain, xff)
+				}
+			}
+		}
+	}
+
+	return append(chain, remoteIP)
+}
+
+// getClientIP derives the client IP of the request from its forwarding chain,
+// whose headers have already been removed if the remote address is not trusted.
+func (x *XForwarded) getClientIP(req *http.Request) string {
+	remoteIP := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		remoteIP = removeIPv6Zone(host)
+	}
+
+	chain := forwardingChain(req, remoteIP)
+
+	if x.clientIPDepth > 0 {
+		// The depth is counted from the right of the forwarded headers, like the one of the IP strategies,
+		// so the remote address at the end of the chain is skipped.
+		if len(chain)-1 < x.clientIPDepth {
+			return remoteIP
+		}
+		return chain[len(chain)-1-x.clientIPDepth]
+	}
+
+	for i := len(chain) - 1; i > 0; i-- {
+		if !x.insecure && !x.isTrustedIP(chain[i]) {
+			return chain[i]
+		}
+	}
+
+	return chain[0]
 }
 
 // ServeHTTP implements http.Handler.
@@ -168,6 +266,10 @@ func (x *XForwarded) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if x.clientIP {
+		r = r.WithContext(ip.WithClientIP(r.Context(), x.getClientIP(r)))
+	}
+
 	x.rewrite(r)
 
 	x.next.ServeHTTP(w, r)
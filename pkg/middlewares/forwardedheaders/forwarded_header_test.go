@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/ip"
 )
 
 func TestServeHTTP(t *testing.T) {
@@ -299,3 +300,173 @@ func TestServeHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestServeHTTP_forwarded(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		trustedIps []string
+		remoteAddr string
+		tls        bool
+		incoming   []string
+		expected   string
+	}{
+		{
+			desc:       "new Forwarded header",
+			remoteAddr: "10.0.1.101:80",
+			expected:   `for=10.0.1.101;host=foo.bar;proto=http`,
+		},
+		{
+			desc:       "new Forwarded header with IPv6 and TLS",
+			remoteAddr: "[2001:db8::1]:443",
+			tls:        true,
+			expected:   `for="[2001:db8::1]";host=foo.bar;proto=https`,
+		},
+		{
+			desc:       "untrusted Forwarded header removed",
+			remoteAddr: "10.0.1.101:80",
+			incoming:   []string{`for=192.0.2.43`},
+			expected:   `for=10.0.1.101;host=foo.bar;proto=http`,
+		},
+		{
+			desc:       "trusted Forwarded headers appended",
+			trustedIps: []string{"10.0.1.101"},
+			remoteAddr: "10.0.1.101:80",
+			incoming:   []string{`for=192.0.2.43`, `for="[2001:db8:cafe::17]:4711"`},
+			expected:   `for=192.0.2.43, for="[2001:db8:cafe::17]:4711", for=10.0.1.101;host=foo.bar;proto=http`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, "http://foo.bar/", nil)
+			require.NoError(t, err)
+
+			req.RemoteAddr = test.remoteAddr
+			if test.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			for _, value := range test.incoming {
+				req.Header.Add(forwarded, value)
+			}
+
+			m, err := NewXForwarded(false, test.trustedIps,
+				http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+			require.NoError(t, err)
+
+			m.SetForwarded(true)
+			m.ServeHTTP(nil, req)
+
+			assert.Equal(t, test.expected, req.Header.Get(forwarded))
+		})
+	}
+}
+
+func TestServeHTTP_clientIP(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		insecure        bool
+		trustedIps      []string
+		depth           int
+		remoteAddr      string
+		incomingHeaders map[string]string
+		expected        string
+	}{
+		{
+			desc:       "untrusted remote address",
+			trustedIps: []string{"10.0.1.0/24"},
+			remoteAddr: "192.0.2.1:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "203.0.113.1",
+			},
+			expected: "192.0.2.1",
+		},
+		{
+			desc:       "trust chain with X-Forwarded-For",
+			trustedIps: []string{"10.0.1.0/24"},
+			remoteAddr: "10.0.1.101:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "203.0.113.1, 198.51.100.1, 10.0.1.12",
+			},
+			expected: "198.51.100.1",
+		},
+		{
+			desc:       "trust chain with Forwarded",
+			trustedIps: []string{"10.0.1.0/24"},
+			remoteAddr: "10.0.1.101:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "192.0.2.1",
+				forwarded:     `for=203.0.113.1, for="[2001:db8::1]:4711";proto=https, for=10.0.1.12`,
+			},
+			expected: "2001:db8::1",
+		},
+		{
+			desc:       "trust chain with only trusted IPs",
+			trustedIps: []string{"10.0.1.0/24"},
+			remoteAddr: "10.0.1.101:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "10.0.1.11, 10.0.1.12",
+			},
+			expected: "10.0.1.11",
+		},
+		{
+			desc:       "insecure trust chain",
+			insecure:   true,
+			remoteAddr: "192.0.2.1:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "203.0.113.1, 198.51.100.1",
+			},
+			expected: "203.0.113.1",
+		},
+		{
+			desc:       "depth",
+			trustedIps: []string{"10.0.1.0/24"},
+			depth:      2,
+			remoteAddr: "10.0.1.101:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "203.0.113.1, 198.51.100.1, 10.0.1.12",
+			},
+			expected: "198.51.100.1",
+		},
+		{
+			desc:       "depth greater than the forwarding chain",
+			trustedIps: []string{"10.0.1.0/24"},
+			depth:      3,
+			remoteAddr: "10.0.1.101:80",
+			incomingHeaders: map[string]string{
+				xForwardedFor: "198.51.100.1, 10.0.1.12",
+			},
+			expected: "10.0.1.101",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, "http://foo.bar/", nil)
+			require.NoError(t, err)
+
+			req.RemoteAddr = test.remoteAddr
+			for k, v := range test.incomingHeaders {
+				req.Header.Set(k, v)
+			}
+
+			var clientIP string
+			m, err := NewXForwarded(test.insecure, test.trustedIps,
+				http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+					clientIP = ip.GetClientIP(req.Context())
+				}))
+			require.NoError(t, err)
+
+			m.SetClientIP(test.depth)
+			m.ServeHTTP(nil, req)
+
+			assert.Equal(t, test.expected, clientIP)
+		})
+	}
+}
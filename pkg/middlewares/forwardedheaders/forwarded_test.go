@@ -0,0 +1,91 @@
+package forwardedheaders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForwarded(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		values   []string
+		expected []forwardedElement
+	}{
+		{
+			desc:     "single element",
+			values:   []string{`for=192.0.2.60;proto=http;by=203.0.113.43`},
+			expected: []forwardedElement{{For: "192.0.2.60", Proto: "http"}},
+		},
+		{
+			desc:   "case insensitive keys and quoted values",
+			values: []string{`For="[2001:db8:cafe::17]:4711"; Host="example.com:8080"; Proto=HTTPS`},
+			expected: []forwardedElement{
+				{For: "[2001:db8:cafe::17]:4711", Host: "example.com:8080", Proto: "https"},
+			},
+		},
+		{
+			desc:   "multiple elements and headers",
+			values: []string{`for=192.0.2.43, for="_hidden"`, `for=unknown`},
+			expected: []forwardedElement{
+				{For: "192.0.2.43"},
+				{For: "_hidden"},
+				{For: "unknown"},
+			},
+		},
+		{
+			desc:     "separators and escapes within quoted-strings",
+			values:   []string{`for="a,b;c\"d"`},
+			expected: []forwardedElement{{For: `a,b;c"d`}},
+		},
+		{
+			desc:     "malformed pairs ignored",
+			values:   []string{`for=192.0.2.43;host;proto="http;for=[2001:db8::1]`},
+			expected: []forwardedElement{{For: "192.0.2.43"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, parseForwarded(test.values))
+		})
+	}
+}
+
+func TestForwardedElement_String(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		element  forwardedElement
+		expected string
+	}{
+		{
+			desc:     "tokens",
+			element:  forwardedElement{For: "192.0.2.60", Host: "example.com", Proto: "http"},
+			expected: `for=192.0.2.60;host=example.com;proto=http`,
+		},
+		{
+			desc:     "quoted-strings",
+			element:  forwardedElement{For: forwardedNode("2001:db8::1"), Host: "example.com:8080"},
+			expected: `for="[2001:db8::1]";host="example.com:8080"`,
+		},
+		{
+			desc:     "escapes",
+			element:  forwardedElement{Host: `a"b\c`},
+			expected: `host="a\"b\\c"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			element := test.element
+			assert.Equal(t, test.expected, element.String())
+			assert.Equal(t, []forwardedElement{element}, parseForwarded([]string{element.String()}))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package geoip
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// reloadCheckInterval is the minimum interval between two checks of a database file modification.
+var reloadCheckInterval = time.Minute
+
+var (
+	databasesMu sync.Mutex
+	// databases are shared by all the middlewares using the same file,
+	// so that they are not loaded again each time the configuration is reloaded.
+	databases = make(map[string]*database)
+)
+
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+type asnRecord struct {
+	AutonomousSystemNumber       uint   `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// database is a MaxMind DB file, which is reloaded when it is modified.
+type database struct {
+	path string
+
+	mu        sync.RWMutex
+	reader    *maxminddb.Reader
+	modTime   time.Time
+	checkedAt time.Time
+	checking  int32
+}
+
+// getDatabase returns the database of the given file, loading it if needed.
+func getDatabase(path string) (*database, error) {
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+
+	if db, ok := databases[path]; ok {
+		return db, nil
+	}
+
+	db := &database{path: path}
+	if err := db.load(); err != nil {
+		return nil, err
+	}
+
+	db.checkedAt = time.Now()
+	databases[path] = db
+
+	return db, nil
+}
+
+func (d *database) load() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	unchanged := d.reader != nil && info.ModTime().Equal(d.modTime)
+	d.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	// The file is read in memory rather than memory-mapped,
+	// so that it can safely be replaced while being used.
+	content, err := ioutil.ReadFile(d.path)
+	if err != nil {
+		return err
+	}
+
+	reader, err := maxminddb.FromBytes(content)
+	if err != nil {
+		return fmt.Errorf("invalid database %s: %w", d.path, err)
+	}
+
+	d.mu.Lock()
+	d.reader = reader
+	d.modTime = info.ModTime()
+	d.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfNeeded reloads the database in the background if its file has been modified.
+// The file is checked at most once per reloadCheckInterval.
+func (d *database) reloadIfNeeded() {
+	d.mu.RLock()
+	checkedAt := d.checkedAt
+	d.mu.RUnlock()
+
+	if time.Since(checkedAt) < reloadCheckInterval || !atomic.CompareAndSwapInt32(&d.checking, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&d.checking, 0)
+
+		if err := d.load(); err != nil {
+			// The previous database is kept.
+			log.WithoutContext().Errorf("Unable to reload the GeoIP database %s: %v", d.path, err)
+		}
+
+		d.mu.Lock()
+		d.checkedAt = time.Now()
+		d.mu.Unlock()
+	}()
+}
+
+func (d *database) lookup(ip net.IP, result interface{}) error {
+	d.reloadIfNeeded()
+
+	d.mu.RLock()
+	reader := d.reader
+	d.mu.RUnlock()
+
+	return reader.Lookup(ip, result)
+}
+
+// country returns the ISO code of the country of the IP, or an empty string if unknown.
+func (d *database) country(ip net.IP) (string, error) {
+	var record countryRecord
+	if err := d.lookup(ip, &record); err != nil {
+		return "", err
+	}
+
+	return record.Country.ISOCode, nil
+}
+
+// asn returns the autonomous system number of the IP, or zero if unknown.
+func (d *database) asn(ip net.IP) (uint, error) {
+	var record asnRecord
+	if err := d.lookup(ip, &record); err != nil {
+		return 0, err
+	}
+
+	return record.AutonomousSystemNumber, nil
+}
@@ -0,0 +1,193 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "GeoIP"
+)
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+// geoIP is a middleware that looks up the country and the autonomous system of the client IP,
+// forwards them as request headers, and filters or routes the requests according to the country.
+type geoIP struct {
+	next     http.Handler
+	name     string
+	strategy ip.Strategy
+
+	country func(ip net.IP) (string, error)
+	asn     func(ip net.IP) (uint, error)
+
+	countryHeader   string
+	asnHeader       string
+	allowed         map[string]struct{}
+	denied          map[string]struct{}
+	countryServices map[string]http.Handler
+}
+
+// New creates a GeoIP middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.GeoIP, serviceBuilder serviceBuilder, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.CountryDatabase == "" && config.ASNDatabase == "" {
+		return nil, errors.New("at least one of countryDatabase or asnDatabase must be defined")
+	}
+
+	var country func(ip net.IP) (string, error)
+	if config.CountryDatabase != "" {
+		db, err := getDatabase(config.CountryDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load country database: %w", err)
+		}
+		country = db.country
+	}
+
+	var asn func(ip net.IP) (uint, error)
+	if config.ASNDatabase != "" {
+		db, err := getDatabase(config.ASNDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load ASN database: %w", err)
+		}
+		asn = db.asn
+	}
+
+	countryServices := make(map[string]http.Handler)
+	for code, serviceName := range config.CountryServices {
+		handler, err := serviceBuilder.BuildHTTP(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		countryServices[strings.ToUpper(code)] = handler
+	}
+
+	return newGeoIP(next, config, country, asn, countryServices, name)
+}
+
+func newGeoIP(next http.Handler, config dynamic.GeoIP, country func(net.IP) (string, error), asn func(net.IP) (uint, error), countryServices map[string]http.Handler, name string) (*geoIP, error) {
+	if len(config.AllowedCountries) > 0 && len(config.DeniedCountries) > 0 {
+		return nil, errors.New("allowedCountries and deniedCountries cannot be both defined")
+	}
+
+	if country == nil && (len(config.AllowedCountries) > 0 || len(config.DeniedCountries) > 0 || len(countryServices) > 0) {
+		return nil, errors.New("countryDatabase is required to filter or route the requests by country")
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return &geoIP{
+		next:            next,
+		name:            name,
+		strategy:        strategy,
+		country:         country,
+		asn:             asn,
+		countryHeader:   config.CountryHeader,
+		asnHeader:       config.ASNHeader,
+		allowed:         toSet(config.AllowedCountries),
+		denied:          toSet(config.DeniedCountries),
+		countryServices: countryServices,
+	}, nil
+}
+
+func (g *geoIP) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return g.name, tracing.SpanKindNoneEnum
+}
+
+func (g *geoIP) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), g.name, typeName))
+
+	// The headers sent by the client cannot be trusted.
+	if g.countryHeader != "" {
+		req.Header.Del(g.countryHeader)
+	}
+	if g.asnHeader != "" {
+		req.Header.Del(g.asnHeader)
+	}
+
+	clientIP := net.ParseIP(g.strategy.GetIP(req))
+
+	var country string
+	if g.country != nil && clientIP != nil {
+		var err error
+		country, err = g.country(clientIP)
+		if err != nil {
+			logger.Debugf("Unable to look up the country of %s: %v", clientIP, err)
+		}
+	}
+
+	if !g.isAllowed(country) {
+		logger.Debugf("Rejecting request from %s, country %q not allowed", clientIP, country)
+		tracing.SetErrorWithEvent(req, "request from %s rejected, country %q not allowed", clientIP, country)
+		rw.WriteHeader(http.StatusForbidden)
+		_, err := rw.Write([]byte(http.StatusText(http.StatusForbidden)))
+		if err != nil {
+			logger.Error(err)
+		}
+		return
+	}
+
+	if country != "" && g.countryHeader != "" {
+		req.Header.Set(g.countryHeader, country)
+	}
+
+	if g.asn != nil && clientIP != nil && g.asnHeader != "" {
+		asn, err := g.asn(clientIP)
+		if err != nil {
+			logger.Debugf("Unable to look up the autonomous system of %s: %v", clientIP, err)
+		}
+		if asn != 0 {
+			req.Header.Set(g.asnHeader, strconv.FormatUint(uint64(asn), 10))
+		}
+	}
+
+	if handler, ok := g.countryServices[country]; ok && country != "" {
+		handler.ServeHTTP(rw, req)
+		return
+	}
+
+	g.next.ServeHTTP(rw, req)
+}
+
+// isAllowed returns whether the requests from the given country are allowed.
+// An unknown country is only rejected when the allowed countries are restricted.
+func (g *geoIP) isAllowed(country string) bool {
+	if len(g.allowed) > 0 {
+		_, ok := g.allowed[country]
+		return ok
+	}
+
+	_, denied := g.denied[country]
+	return !denied
+}
+
+func toSet(codes []string) map[string]struct{} {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		set[strings.ToUpper(code)] = struct{}{}
+	}
+
+	return set
+}
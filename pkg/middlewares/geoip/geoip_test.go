@@ -0,0 +1,231 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type serviceBuilderMock map[string]http.Handler
+
+func (s serviceBuilderMock) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	if handler, ok := s[serviceName]; ok {
+		return handler, nil
+	}
+
+	return nil, errors.New("unknown service")
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.GeoIP
+	}{
+		{
+			desc:   "no database",
+			config: dynamic.GeoIP{},
+		},
+		{
+			desc:   "missing database file",
+			config: dynamic.GeoIP{CountryDatabase: filepath.Join(t.TempDir(), "missing.mmdb")},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			_, err := New(context.Background(), next, test.config, serviceBuilderMock{}, "traefikTest")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewGeoIP(t *testing.T) {
+	country := func(net.IP) (string, error) { return "", nil }
+
+	testCases := []struct {
+		desc          string
+		config        dynamic.GeoIP
+		country       func(net.IP) (string, error)
+		expectedError bool
+	}{
+		{
+			desc:    "allowed countries",
+			config:  dynamic.GeoIP{AllowedCountries: []string{"FR"}},
+			country: country,
+		},
+		{
+			desc:          "allowed and denied countries",
+			config:        dynamic.GeoIP{AllowedCountries: []string{"FR"}, DeniedCountries: []string{"US"}},
+			country:       country,
+			expectedError: true,
+		},
+		{
+			desc:          "denied countries without country database",
+			config:        dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			_, err := newGeoIP(next, test.config, test.country, nil, nil, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGeoIP_ServeHTTP(t *testing.T) {
+	countries := map[string]string{
+		"1.1.1.1": "FR",
+		"2.2.2.2": "US",
+		"3.3.3.3": "DE",
+	}
+	country := func(ip net.IP) (string, error) {
+		return countries[ip.String()], nil
+	}
+	asn := func(ip net.IP) (uint, error) {
+		if ip.String() == "1.1.1.1" {
+			return 13335, nil
+		}
+		return 0, nil
+	}
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.GeoIP
+		remoteAddr      string
+		expectedStatus  int
+		expectedCountry string
+		expectedASN     string
+		expectedHandler string
+	}{
+		{
+			desc:            "headers",
+			remoteAddr:      "1.1.1.1:1234",
+			expectedStatus:  http.StatusOK,
+			expectedCountry: "FR",
+			expectedASN:     "13335",
+			expectedHandler: "next",
+		},
+		{
+			desc:            "unknown IP",
+			remoteAddr:      "4.4.4.4:1234",
+			expectedStatus:  http.StatusOK,
+			expectedHandler: "next",
+		},
+		{
+			desc:            "allowed country",
+			config:          dynamic.GeoIP{AllowedCountries: []string{"fr", "DE"}},
+			remoteAddr:      "1.1.1.1:1234",
+			expectedStatus:  http.StatusOK,
+			expectedCountry: "FR",
+			expectedASN:     "13335",
+			expectedHandler: "next",
+		},
+		{
+			desc:           "country not allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR", "DE"}},
+			remoteAddr:     "2.2.2.2:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown country not allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR", "DE"}},
+			remoteAddr:     "4.4.4.4:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "denied country",
+			config:         dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:     "2.2.2.2:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:            "unknown country not denied",
+			config:          dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:      "4.4.4.4:1234",
+			expectedStatus:  http.StatusOK,
+			expectedHandler: "next",
+		},
+		{
+			desc:            "country service",
+			config:          dynamic.GeoIP{CountryServices: map[string]string{"de": "eu"}},
+			remoteAddr:      "3.3.3.3:1234",
+			expectedStatus:  http.StatusOK,
+			expectedCountry: "DE",
+			expectedHandler: "eu",
+		},
+		{
+			desc: "IP strategy",
+			config: dynamic.GeoIP{
+				IPStrategy: &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:      "4.4.4.4:1234",
+			expectedStatus:  http.StatusOK,
+			expectedCountry: "US",
+			expectedHandler: "next",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var handler string
+			newHandler := func(name string) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					handler = name
+					assert.Equal(t, test.expectedCountry, req.Header.Get("X-Geoip-Country"))
+					assert.Equal(t, test.expectedASN, req.Header.Get("X-Geoip-Asn"))
+				})
+			}
+
+			config := test.config
+			config.SetDefaults()
+
+			countryServices := make(map[string]http.Handler)
+			for code, service := range config.CountryServices {
+				countryServices[strings.ToUpper(code)] = newHandler(service)
+			}
+
+			geo, err := newGeoIP(newHandler("next"), config, country, asn, countryServices, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-For", "2.2.2.2")
+			// Spoofed headers are removed.
+			req.Header.Set("X-Geoip-Country", "XX")
+			req.Header.Set("X-Geoip-Asn", "1")
+
+			recorder := httptest.NewRecorder()
+			geo.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedHandler, handler)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package grpcweb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/cors"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "GRPCWeb"
+)
+
+const (
+	grpcContentType    = "application/grpc"
+	grpcWebContentType = "application/grpc-web"
+	grpcWebTextSuffix  = "-text"
+
+	// trailerFrameFlag is the flag of the gRPC-Web frame holding the trailers.
+	trailerFrameFlag = 0x80
+)
+
+// grpcWeb is a middleware translating the gRPC-Web requests to gRPC ones.
+type grpcWeb struct {
+	name string
+	next http.Handler
+}
+
+// New creates a gRPC-Web middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.GRPCWeb, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	var handler http.Handler = &grpcWeb{name: name, next: next}
+
+	if len(config.AllowOrigins) == 0 {
+		return handler, nil
+	}
+
+	// The browsers send CORS requests, and only give access to the gRPC response headers exposed by CORS.
+	return cors.New(ctx, handler, dynamic.CORS{
+		Policies: []dynamic.CORSPolicy{{
+			AllowOrigins:  config.AllowOrigins,
+			AllowMethods:  []string{http.MethodPost},
+			AllowHeaders:  []string{"*"},
+			ExposeHeaders: []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"},
+		}},
+	}, name)
+}
+
+func (g *grpcWeb) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return g.name, tracing.SpanKindNoneEnum
+}
+
+func (g *grpcWeb) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	contentType := req.Header.Get("Content-Type")
+	if req.Method != http.MethodPost || !strings.HasPrefix(contentType, grpcWebContentType) {
+		g.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// e.g. application/grpc-web-text+proto
+	subtype := strings.TrimPrefix(contentType, grpcWebContentType)
+	text := strings.HasPrefix(subtype, grpcWebTextSuffix)
+	if text {
+		subtype = strings.TrimPrefix(subtype, grpcWebTextSuffix)
+	}
+
+	outReq := req.Clone(req.Context())
+	outReq.Header.Set("Content-Type", grpcContentType+subtype)
+	outReq.Header.Set("Te", "trailers")
+	outReq.Header.Del("Content-Length")
+	outReq.ContentLength = -1
+
+	if text {
+		outReq.Body = &textReader{r: bufio.NewReader(req.Body), closer: req.Body}
+	}
+
+	wrw := &responseWriter{
+		rw:          rw,
+		text:        text,
+		contentType: grpcWebContentType + subtype,
+	}
+	if text {
+		wrw.contentType = grpcWebContentType + grpcWebTextSuffix + subtype
+	}
+
+	g.next.ServeHTTP(wrw, outReq)
+
+	if err := wrw.writeTrailers(); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), g.name, typeName)).Debugf("Error while writing the gRPC-Web trailers: %v", err)
+	}
+}
+
+// textReader decodes a gRPC-Web-Text request body.
+// The body may be the concatenation of several base64 encoded chunks, each one with its own padding,
+// so it is decoded by groups of 4 bytes.
+type textReader struct {
+	r       *bufio.Reader
+	closer  io.Closer
+	decoded []byte
+}
+
+func (t *textReader) Read(p []byte) (int, error) {
+	for len(t.decoded) == 0 {
+		var group [4]byte
+		if _, err := io.ReadFull(t.r, group[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return 0, fmt.Errorf("invalid base64 body: %w", err)
+			}
+			return 0, err
+		}
+
+		decoded := make([]byte, 3)
+		n, err := base64.StdEncoding.Decode(decoded, group[:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid base64 body: %w", err)
+		}
+		t.decoded = decoded[:n]
+	}
+
+	n := copy(p, t.decoded)
+	t.decoded = t.decoded[n:]
+
+	return n, nil
+}
+
+func (t *textReader) Close() error {
+	return t.closer.Close()
+}
+
+// responseWriter translates a gRPC response to a gRPC-Web one,
+// by moving the trailers into the response body.
+type responseWriter struct {
+	rw          http.ResponseWriter
+	text        bool
+	contentType string
+
+	headerWritten bool
+	trailers      []string
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if r.headerWritten {
+		return
+	}
+
+	// The informational responses precede the final one, which is the one translated.
+	if middlewares.IsInformational(code) {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	r.headerWritten = true
+
+	header := r.rw.Header()
+
+	// The announced trailers must not be sent as HTTP trailers,
+	// as they are sent in the response body.
+	for _, values := range header["Trailer"] {
+		for _, key := range strings.Split(values, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				r.trailers = append(r.trailers, http.CanonicalHeaderKey(key))
+			}
+		}
+	}
+	header.Del("Trailer")
+
+	if strings.HasPrefix(header.Get("Content-Type"), grpcContentType) {
+		header.Set("Content-Type", r.contentType)
+	}
+	header.Del("Content-Length")
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.text {
+		return r.rw.Write(p)
+	}
+
+	// Each write is encoded on its own, with its own padding, so that it can be flushed.
+	if _, err := r.rw.Write([]byte(base64.StdEncoding.EncodeToString(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeTrailers writes the trailers, announced or not, in a trailer frame.
+func (r *responseWriter) writeTrailers() error {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	header := r.rw.Header()
+	trailers := make(map[string][]string)
+
+	for _, key := range r.trailers {
+		if values, ok := header[key]; ok {
+			trailers[key] = values
+			delete(header, key)
+		}
+	}
+
+	for key, values := range header {
+		if strings.HasPrefix(key, http.TrailerPrefix) {
+			trailers[http.CanonicalHeaderKey(strings.TrimPrefix(key, http.TrailerPrefix))] = values
+			delete(header, key)
+		}
+	}
+
+	if len(trailers) == 0 {
+		return nil
+	}
+
+	_, err := r.Write(trailerFrame(trailers))
+	return err
+}
+
+// trailerFrame encodes the trailers as a gRPC-Web frame: a flag byte, the length of the data on 4 bytes,
+// and the data, made of a line per trailer with lowercase keys.
+func trailerFrame(trailers map[string][]string) []byte {
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	data := new(bytes.Buffer)
+	for _, key := range keys {
+		for _, value := range trailers[key] {
+			fmt.Fprintf(data, "%s: %s\r\n", strings.ToLower(key), value)
+		}
+	}
+
+	frame := make([]byte, 5, 5+data.Len())
+	frame[0] = trailerFrameFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(data.Len()))
+
+	return append(frame, data.Bytes()...)
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if !r.headerWritten {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+}
+
+// CloseNotify returns a channel that receives at most a single value (true)
+// when the client connection has gone away.
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if n, ok := r.rw.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+
+	return make(<-chan bool)
+}
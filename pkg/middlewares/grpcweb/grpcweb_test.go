@@ -0,0 +1,124 @@
+package grpcweb
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// message is a gRPC frame holding a 3 bytes message.
+var message = []byte{0, 0, 0, 0, 3, 'f', 'o', 'o'}
+
+// trailers is the gRPC-Web frame holding the trailers sent by the backend below.
+var trailers = append([]byte{trailerFrameFlag, 0, 0, 0, 46}, "grpc-message: OK\r\ngrpc-status: 0\r\nx-foo: bar\r\n"...)
+
+func grpcBackend(t *testing.T) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/grpc+proto", req.Header.Get("Content-Type"))
+		assert.Equal(t, "trailers", req.Header.Get("Te"))
+
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, message, body)
+
+		rw.Header().Set("Content-Type", "application/grpc+proto")
+		rw.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(message)
+
+		rw.Header().Set("Grpc-Status", "0")
+		rw.Header().Set("Grpc-Message", "OK")
+		rw.Header().Set(http.TrailerPrefix+"X-Foo", "bar")
+	})
+}
+
+func TestGRPCWeb_binary(t *testing.T) {
+	handler, err := New(context.Background(), grpcBackend(t), dynamic.GRPCWeb{}, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Service/Method", strings.NewReader(string(message)))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/grpc-web+proto", recorder.Header().Get("Content-Type"))
+	assert.Empty(t, recorder.Header().Get("Trailer"))
+	assert.Empty(t, recorder.Header().Get("Grpc-Status"))
+	assert.Equal(t, append(append([]byte{}, message...), trailers...), recorder.Body.Bytes())
+}
+
+func TestGRPCWeb_text(t *testing.T) {
+	handler, err := New(context.Background(), grpcBackend(t), dynamic.GRPCWeb{}, "traefikTest")
+	require.NoError(t, err)
+
+	// The message is sent in two base64 chunks, each one with its own padding.
+	body := base64.StdEncoding.EncodeToString(message[:5]) + base64.StdEncoding.EncodeToString(message[5:])
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/foo.Service/Method", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web-text+proto")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	expected := base64.StdEncoding.EncodeToString(message) + base64.StdEncoding.EncodeToString(trailers)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/grpc-web-text+proto", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestGRPCWeb_notGRPCWeb(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		_, _ = rw.Write([]byte("OK"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.GRPCWeb{}, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "OK", recorder.Body.String())
+}
+
+func TestGRPCWeb_cors(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler, err := New(context.Background(), next, dynamic.GRPCWeb{AllowOrigins: []string{"https://example.com"}}, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodOptions, "http://localhost/foo.Service/Method", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "https://example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "content-type,x-grpc-web", recorder.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func TestTextReader_invalid(t *testing.T) {
+	reader := &textReader{r: bufio.NewReader(strings.NewReader("Zm9v!")), closer: ioutil.NopCloser(nil)}
+
+	_, err := ioutil.ReadAll(reader)
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package headers
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -10,8 +11,17 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 )
 
+// tlsFields are the values of the TLS connection the TLS request headers can be set to.
+var tlsFields = map[string]func(*tls.ConnectionState) string{
+	"version":               traefiktls.GetVersion,
+	"cipher":                traefiktls.GetCipherName,
+	"serverName":            func(connState *tls.ConnectionState) string { return connState.ServerName },
+	"clientCertFingerprint": traefiktls.GetClientCertFingerprint,
+}
+
 // Header is a middleware that helps setup a few basic security features.
 // A single headerOptions struct can be provided to configure which features should be enabled,
 // and the ability to override a few of the default values.
@@ -40,6 +50,12 @@ func NewHeader(next http.Handler, cfg dynamic.Headers) (*Header, error) {
 		regexes[i] = reg
 	}
 
+	for header, field := range cfg.TLSRequestHeaders {
+		if _, ok := tlsFields[field]; !ok {
+			return nil, fmt.Errorf("unknown TLS field %q for the header %s", field, header)
+		}
+	}
+
 	return &Header{
 		next:               next,
 		headers:            &cfg,
@@ -57,6 +73,7 @@ func (s *Header) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if s.hasCustomHeaders {
 		s.modifyCustomRequestHeaders(req)
+		s.modifyTLSRequestHeaders(req)
 	}
 
 	// If there is a next, call it.
@@ -82,6 +99,25 @@ func (s *Header) modifyCustomRequestHeaders(req *http.Request) {
 	}
 }
 
+// modifyTLSRequestHeaders sets the TLS request headers to the values of the TLS connection.
+// The headers are deleted when the value is not available, such as for the non-TLS requests,
+// so that the clients cannot set them.
+func (s *Header) modifyTLSRequestHeaders(req *http.Request) {
+	for header, field := range s.headers.TLSRequestHeaders {
+		var value string
+		if req.TLS != nil {
+			value = tlsFields[field](req.TLS)
+		}
+
+		if value == "" {
+			req.Header.Del(header)
+			continue
+		}
+
+		req.Header.Set(header, value)
+	}
+}
+
 // PostRequestModifyResponseHeaders set or delete response headers.
 // This method is called AFTER the response is generated from the backend
 // and can merge/override headers from the backend response.
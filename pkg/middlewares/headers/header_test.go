@@ -1,6 +1,8 @@
 package headers
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -112,6 +114,81 @@ func TestNewHeader_customRequestHeader_Host(t *testing.T) {
 	}
 }
 
+func TestNewHeader_tlsRequestHeaders(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		tls      *tls.ConnectionState
+		expected http.Header
+	}{
+		{
+			desc: "TLS request",
+			tls: &tls.ConnectionState{
+				Version:          tls.VersionTLS12,
+				CipherSuite:      tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				ServerName:       "example.com",
+				PeerCertificates: []*x509.Certificate{{Raw: []byte("foo")}},
+			},
+			expected: http.Header{
+				"X-Tls-Version":     []string{"1.2"},
+				"X-Tls-Cipher":      []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				"X-Tls-Sni":         []string{"example.com"},
+				"X-Tls-Fingerprint": []string{"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+			},
+		},
+		{
+			desc: "TLS request without client certificate",
+			tls: &tls.ConnectionState{
+				Version:     tls.VersionTLS10,
+				CipherSuite: tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+			},
+			expected: http.Header{
+				"X-Tls-Version": []string{"1.0"},
+				"X-Tls-Cipher":  []string{"TLS_RSA_WITH_3DES_EDE_CBC_SHA"},
+			},
+		},
+		{
+			desc:     "non-TLS request",
+			expected: http.Header{},
+		},
+	}
+
+	cfg := dynamic.Headers{
+		TLSRequestHeaders: map[string]string{
+			"X-TLS-Version":     "version",
+			"X-TLS-Cipher":      "cipher",
+			"X-TLS-SNI":         "serverName",
+			"X-TLS-Fingerprint": "clientCertFingerprint",
+		},
+	}
+
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mid, err := NewHeader(emptyHandler, cfg)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/foo", nil)
+			req.TLS = test.tls
+			// The values sent by the client are not trusted.
+			req.Header.Set("X-TLS-Version", "1.3")
+			req.Header.Set("X-TLS-Fingerprint", "foo")
+
+			mid.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expected, req.Header)
+		})
+	}
+}
+
+func TestNewHeader_unknownTLSField(t *testing.T) {
+	_, err := NewHeader(nil, dynamic.Headers{TLSRequestHeaders: map[string]string{"X-TLS": "foo"}})
+	assert.Error(t, err)
+}
+
 func TestNewHeader_CORSPreflights(t *testing.T) {
 	testCases := []struct {
 		desc           string
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 )
 
 type responseModifier struct {
@@ -35,6 +36,13 @@ func (w *responseModifier) WriteHeader(code int) {
 	if w.headersSent {
 		return
 	}
+
+	// The informational responses precede the final one, which is the one modified.
+	if middlewares.IsInformational(code) {
+		w.w.WriteHeader(code)
+		return
+	}
+
 	defer func() {
 		w.code = code
 		w.headersSent = true
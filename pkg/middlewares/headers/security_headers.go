@@ -0,0 +1,163 @@
+package headers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	typeSecurityHeadersName = "SecurityHeaders"
+
+	stsHeader = "Strict-Transport-Security"
+)
+
+// securityPresets are the response headers of the security presets, by name and version.
+// The headers of a released version never change:
+// the new headers are added to a new version, so that they are only sent once the version is explicitly bumped.
+var securityPresets = map[string]map[int]map[string]string{
+	"basic": {
+		1: {
+			stsHeader:                    "max-age=31536000",
+			"X-Content-Type-Options":     "nosniff",
+			"X-Frame-Options":            "SAMEORIGIN",
+			"Referrer-Policy":            "strict-origin-when-cross-origin",
+			"Cross-Origin-Opener-Policy": "same-origin-allow-popups",
+		},
+	},
+	"strict": {
+		1: {
+			stsHeader:                      "max-age=63072000; includeSubDomains",
+			"Content-Security-Policy":      "default-src 'self'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'; object-src 'none'",
+			"Cross-Origin-Opener-Policy":   "same-origin",
+			"Cross-Origin-Embedder-Policy": "require-corp",
+			"Cross-Origin-Resource-Policy": "same-origin",
+			"X-Content-Type-Options":       "nosniff",
+			"X-Frame-Options":              "DENY",
+			"Referrer-Policy":              "no-referrer",
+		},
+	},
+	"api": {
+		1: {
+			stsHeader:                      "max-age=63072000; includeSubDomains",
+			"Content-Security-Policy":      "default-src 'none'; frame-ancestors 'none'",
+			"Cross-Origin-Resource-Policy": "same-origin",
+			"X-Content-Type-Options":       "nosniff",
+			"X-Frame-Options":              "DENY",
+			"Referrer-Policy":              "no-referrer",
+		},
+	},
+}
+
+// securityHeaders is a middleware adding the response headers of a security preset.
+type securityHeaders struct {
+	next http.Handler
+	name string
+	// defaults are the headers of the preset, which are only added when the response does not have them.
+	defaults map[string]string
+	// overrides are the headers configured explicitly, which replace the ones of the response.
+	overrides map[string]string
+}
+
+// NewSecurityHeaders creates a security headers middleware.
+func NewSecurityHeaders(ctx context.Context, next http.Handler, cfg dynamic.SecurityHeaders, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeSecurityHeadersName)).Debug("Creating middleware")
+
+	if cfg.Preset == "" {
+		return nil, errors.New("a preset must be defined")
+	}
+
+	versions, ok := securityPresets[cfg.Preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, expected one of %s", cfg.Preset, strings.Join(presetNames(), ", "))
+	}
+
+	version := cfg.Version
+	if version == 0 {
+		version = 1
+	}
+
+	preset, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown version %d of the preset %q", version, cfg.Preset)
+	}
+
+	defaults := make(map[string]string, len(preset))
+	for key, value := range preset {
+		defaults[key] = value
+	}
+
+	overrides := make(map[string]string)
+	for key, value := range cfg.Headers {
+		if !httpguts.ValidHeaderFieldName(key) || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("invalid header %q: %q", key, value)
+		}
+
+		key = http.CanonicalHeaderKey(key)
+		delete(defaults, key)
+
+		// An empty value removes the header from the preset.
+		if value != "" {
+			overrides[key] = value
+		}
+	}
+
+	return &securityHeaders{
+		next:      next,
+		name:      name,
+		defaults:  defaults,
+		overrides: overrides,
+	}, nil
+}
+
+func (s *securityHeaders) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *securityHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The Strict-Transport-Security header is ignored by the browsers over plain HTTP.
+	secure := req.TLS != nil || strings.EqualFold(req.Header.Get("X-Forwarded-Proto"), "https")
+
+	s.next.ServeHTTP(newResponseModifier(rw, req, func(resp *http.Response) error {
+		for key, value := range s.defaults {
+			if key == stsHeader && !secure {
+				continue
+			}
+
+			if _, ok := resp.Header[key]; !ok {
+				resp.Header.Set(key, value)
+			}
+		}
+
+		for key, value := range s.overrides {
+			if key == stsHeader && !secure {
+				continue
+			}
+
+			resp.Header.Set(key, value)
+		}
+
+		return nil
+	}), req)
+}
+
+func presetNames() []string {
+	var names []string
+	for name := range securityPresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
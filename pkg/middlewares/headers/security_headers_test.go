@@ -0,0 +1,146 @@
+package headers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.SecurityHeaders
+		forwardedProto  string
+		responseHeaders map[string]string
+		expected        map[string]string
+	}{
+		{
+			desc:   "strict preset",
+			config: dynamic.SecurityHeaders{Preset: "strict"},
+			expected: map[string]string{
+				"Content-Security-Policy":      "default-src 'self'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'; object-src 'none'",
+				"Cross-Origin-Opener-Policy":   "same-origin",
+				"Cross-Origin-Embedder-Policy": "require-corp",
+				"Cross-Origin-Resource-Policy": "same-origin",
+				"X-Content-Type-Options":       "nosniff",
+				"X-Frame-Options":              "DENY",
+				"Referrer-Policy":              "no-referrer",
+				"Strict-Transport-Security":    "",
+			},
+		},
+		{
+			desc:           "strict preset over HTTPS",
+			config:         dynamic.SecurityHeaders{Preset: "strict", Version: 1},
+			forwardedProto: "https",
+			expected: map[string]string{
+				"Strict-Transport-Security": "max-age=63072000; includeSubDomains",
+				"X-Frame-Options":           "DENY",
+			},
+		},
+		{
+			desc:   "headers of the response kept",
+			config: dynamic.SecurityHeaders{Preset: "api"},
+			responseHeaders: map[string]string{
+				"Content-Security-Policy": "default-src 'self'",
+			},
+			expected: map[string]string{
+				"Content-Security-Policy": "default-src 'self'",
+				"X-Content-Type-Options":  "nosniff",
+			},
+		},
+		{
+			desc: "overridden headers",
+			config: dynamic.SecurityHeaders{
+				Preset: "basic",
+				Headers: map[string]string{
+					"x-frame-options":           "DENY",
+					"Referrer-Policy":           "",
+					"Permissions-Policy":        "camera=()",
+					"Strict-Transport-Security": "max-age=60",
+				},
+			},
+			responseHeaders: map[string]string{
+				"X-Frame-Options": "SAMEORIGIN",
+				"Referrer-Policy": "origin",
+			},
+			expected: map[string]string{
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "origin",
+				"Permissions-Policy":        "camera=()",
+				"X-Content-Type-Options":    "nosniff",
+				"Strict-Transport-Security": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				for key, value := range test.responseHeaders {
+					rw.Header().Set(key, value)
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := NewSecurityHeaders(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.forwardedProto != "" {
+				req.Header.Set("X-Forwarded-Proto", test.forwardedProto)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			for key, value := range test.expected {
+				assert.Equal(t, value, recorder.Header().Get(key), key)
+			}
+		})
+	}
+}
+
+func TestSecurityHeaders_invalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.SecurityHeaders
+	}{
+		{
+			desc: "no preset",
+		},
+		{
+			desc:   "unknown preset",
+			config: dynamic.SecurityHeaders{Preset: "foo"},
+		},
+		{
+			desc:   "unknown version",
+			config: dynamic.SecurityHeaders{Preset: "strict", Version: 42},
+		},
+		{
+			desc:   "invalid header name",
+			config: dynamic.SecurityHeaders{Preset: "strict", Headers: map[string]string{"Foo Bar": "foo"}},
+		},
+		{
+			desc:   "invalid header value",
+			config: dynamic.SecurityHeaders{Preset: "strict", Headers: map[string]string{"Foo": "foo\nbar"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSecurityHeaders(context.Background(), http.NotFoundHandler(), test.config, "traefikTest")
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import "net/http"
+
+// IsInformational reports whether the status code is the one of an informational (1xx) response,
+// such as 100 (Continue) or 103 (Early Hints), which precedes the final response of the request.
+// The 101 (Switching Protocols) response is excluded, as it is the final response of an upgraded connection.
+func IsInformational(code int) bool {
+	return code >= http.StatusContinue && code < http.StatusOK && code != http.StatusSwitchingProtocols
+}
+
+// WriteInformational writes an informational response with the given headers,
+// for the response writers holding the headers of the response apart from the ones of rw.
+// The headers of rw are restored afterwards, as the ones of an informational response are not the ones of the final response.
+func WriteInformational(rw http.ResponseWriter, code int, header http.Header) {
+	dst := rw.Header()
+	saved := dst.Clone()
+
+	for key := range dst {
+		delete(dst, key)
+	}
+	for key, values := range header {
+		dst[key] = values
+	}
+
+	rw.WriteHeader(code)
+
+	for key := range dst {
+		delete(dst, key)
+	}
+	for key, values := range saved {
+		dst[key] = values
+	}
+}
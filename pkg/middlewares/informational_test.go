@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInformational(t *testing.T) {
+	assert.True(t, IsInformational(http.StatusContinue))
+	assert.True(t, IsInformational(http.StatusEarlyHints))
+	assert.False(t, IsInformational(http.StatusSwitchingProtocols))
+	assert.False(t, IsInformational(http.StatusOK))
+}
+
+func TestWriteInformational(t *testing.T) {
+	rw := &headersRecorder{ResponseRecorder: httptest.NewRecorder()}
+	rw.Header().Set("X-Final", "foo")
+
+	WriteInformational(rw, http.StatusEarlyHints, http.Header{"Link": []string{"</style.css>; rel=preload"}})
+
+	assert.Equal(t, http.Header{"Link": []string{"</style.css>; rel=preload"}}, rw.written)
+	assert.Equal(t, http.Header{"X-Final": []string{"foo"}}, rw.Header())
+}
+
+// headersRecorder records the headers sent with the informational response.
+type headersRecorder struct {
+	*httptest.ResponseRecorder
+	written http.Header
+}
+
+func (r *headersRecorder) WriteHeader(code int) {
+	r.written = r.Header().Clone()
+}
@@ -0,0 +1,446 @@
+package ipwhitelist
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const (
+	defaultRefreshInterval = 10 * time.Minute
+	fetchTimeout           = 30 * time.Second
+	maxFeedSize            = 10 << 20
+	maxFeedRedirects       = 3
+)
+
+var (
+	awsIPRangesURL = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+	gcpIPRangesURL = "https://www.gstatic.com/ipranges/cloud.json"
+)
+
+// feedClient fetches the URL and cloud provider feeds.
+// As the feeds can be defined by any provider, including the Kubernetes namespaces users,
+// it only connects to public addresses.
+var feedClient = newFeedClient(isPublicIP, nil)
+
+var (
+	feedsMu sync.Mutex
+	// feeds are shared by all the middlewares using the same source,
+	// so that they are not fetched again each time the configuration is reloaded.
+	// A feed is removed once no middleware references it anymore.
+	feeds = make(map[string]*feed)
+)
+
+// feed holds the IP ranges of an external source.
+// The IP ranges are refreshed in the background, when they are requested and outdated.
+type feed struct {
+	key      string
+	name     string
+	interval time.Duration
+	fetch    func(ctx context.Context) ([]string, error)
+	refs     int // guarded by feedsMu
+
+	mu         sync.RWMutex
+	checker    *ip.Checker
+	expiresAt  time.Time
+	refreshing int32
+}
+
+// acquireFeed returns the feed matching the given configuration, creating it if needed.
+// The feed must be released once it is not used anymore.
+func acquireFeed(cfg dynamic.SourceRangeFeed) (*feed, error) {
+	name, fetch, err := newFetcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(cfg.RefreshInterval)
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+
+	key := fmt.Sprintf("%s@%s", name, interval)
+
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+
+	f, ok := feeds[key]
+	if !ok {
+		f = &feed{key: key, name: name, interval: interval, fetch: fetch}
+		feeds[key] = f
+	}
+
+	f.refs++
+
+	return f, nil
+}
+
+// release releases a reference to the feed, which is removed when it is not referenced anymore.
+func (f *feed) release() {
+	feedsMu.Lock()
+	defer feedsMu.Unlock()
+
+	f.refs--
+	if f.refs <= 0 && feeds[f.key] == f {
+		delete(feeds, f.key)
+	}
+}
+
+// getChecker returns the current IP ranges checker, which is nil until the feed has been fetched once.
+// It triggers a refresh if the IP ranges are outdated.
+func (f *feed) getChecker() *ip.Checker {
+	f.mu.RLock()
+	checker, expiresAt := f.checker, f.expiresAt
+	f.mu.RUnlock()
+
+	if time.Now().After(expiresAt) && atomic.CompareAndSwapInt32(&f.refreshing, 0, 1) {
+		go f.refresh()
+	}
+
+	return checker
+}
+
+func (f *feed) refresh() {
+	defer atomic.StoreInt32(&f.refreshing, 0)
+
+	logger := log.WithoutContext().WithField("sourceRangeFeed", f.name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	var checker *ip.Checker
+	ranges, err := f.fetch(ctx)
+	if err == nil {
+		checker, err = ip.NewChecker(ranges)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The feed is not fetched again before the next interval, even on failure,
+	// to avoid flooding the source.
+	f.expiresAt = time.Now().Add(f.interval)
+
+	if err != nil {
+		// The previous IP ranges, if any, are kept.
+		logger.Errorf("Unable to refresh the source range feed: %v", err)
+		return
+	}
+
+	logger.Debugf("Source range feed refreshed with %d IP ranges", len(ranges))
+	f.checker = checker
+}
+
+func newFetcher(cfg dynamic.SourceRangeFeed) (string, func(ctx context.Context) ([]string, error), error) {
+	var name string
+	var fetch func(ctx context.Context) ([]string, error)
+	var count int
+
+	if cfg.URL != "" {
+		u, err := url.Parse(cfg.URL)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid URL: %w", err)
+		}
+
+		if u.Scheme != "https" {
+			return "", nil, fmt.Errorf("unsupported URL scheme %q, only https is allowed", u.Scheme)
+		}
+
+		count++
+		name = "url:" + cfg.URL
+		fetch = func(ctx context.Context) ([]string, error) {
+			return fetchURL(ctx, cfg.URL)
+		}
+	}
+
+	if cfg.DNS != "" {
+		count++
+		name = "dns:" + cfg.DNS
+		fetch = func(ctx context.Context) ([]string, error) {
+			return lookupDNS(ctx, cfg.DNS)
+		}
+	}
+
+	if cfg.AWS != nil {
+		count++
+		filters := *cfg.AWS
+		name = fmt.Sprintf("aws:regions=%s;services=%s", strings.Join(filters.Regions, ","), strings.Join(filters.Services, ","))
+		fetch = func(ctx context.Context) ([]string, error) {
+			return fetchAWS(ctx, filters)
+		}
+	}
+
+	if cfg.GCP != nil {
+		count++
+		filters := *cfg.GCP
+		name = fmt.Sprintf("gcp:scopes=%s", strings.Join(filters.Scopes, ","))
+		fetch = func(ctx context.Context) ([]string, error) {
+			return fetchGCP(ctx, filters)
+		}
+	}
+
+	switch count {
+	case 0:
+		return "", nil, errors.New("no source defined, one of url, dns, aws or gcp is required")
+	case 1:
+		return name, fetch, nil
+	default:
+		return "", nil, errors.New("only one source among url, dns, aws or gcp can be defined")
+	}
+}
+
+func get(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := feedClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return resp.Body, nil
+}
+
+// fetchURL fetches a document listing an IP or a CIDR per line.
+// Empty lines and comments, starting with #, are ignored.
+func fetchURL(ctx context.Context, url string) ([]string, error) {
+	body, err := get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	var ranges []string
+
+	scanner := bufio.NewScanner(io.LimitReader(body, maxFeedSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		line = strings.TrimSpace(line)
+		if line != "" {
+			ranges = append(ranges, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ranges, nil
+}
+
+func lookupDNS(ctx context.Context, host string) ([]string, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []string
+	for _, addr := range addrs {
+		ranges = append(ranges, addr.IP.String())
+	}
+
+	return ranges, nil
+}
+
+type awsIPRanges struct {
+	Prefixes []struct {
+		IPPrefix string `json:"ip_prefix"`
+		Region   string `json:"region"`
+		Service  string `json:"service"`
+	} `json:"prefixes"`
+	IPv6Prefixes []struct {
+		IPv6Prefix string `json:"ipv6_prefix"`
+		Region     string `json:"region"`
+		Service    string `json:"service"`
+	} `json:"ipv6_prefixes"`
+}
+
+func fetchAWS(ctx context.Context, filters dynamic.AWSIPRanges) ([]string, error) {
+	body, err := get(ctx, awsIPRangesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	var doc awsIPRanges
+	if err := json.NewDecoder(io.LimitReader(body, maxFeedSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to decode AWS IP ranges: %w", err)
+	}
+
+	var ranges []string
+	for _, prefix := range doc.Prefixes {
+		if matchFilter(filters.Regions, prefix.Region) && matchFilter(filters.Services, prefix.Service) {
+			ranges = append(ranges, prefix.IPPrefix)
+		}
+	}
+	for _, prefix := range doc.IPv6Prefixes {
+		if matchFilter(filters.Regions, prefix.Region) && matchFilter(filters.Services, prefix.Service) {
+			ranges = append(ranges, prefix.IPv6Prefix)
+		}
+	}
+
+	return ranges, nil
+}
+
+type gcpIPRanges struct {
+	Prefixes []struct {
+		IPv4Prefix string `json:"ipv4Prefix"`
+		IPv6Prefix string `json:"ipv6Prefix"`
+		Scope      string `json:"scope"`
+	} `json:"prefixes"`
+}
+
+func fetchGCP(ctx context.Context, filters dynamic.GCPIPRanges) ([]string, error) {
+	body, err := get(ctx, gcpIPRangesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	var doc gcpIPRanges
+	if err := json.NewDecoder(io.LimitReader(body, maxFeedSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to decode GCP IP ranges: %w", err)
+	}
+
+	var ranges []string
+	for _, prefix := range doc.Prefixes {
+		if !matchFilter(filters.Scopes, prefix.Scope) {
+			continue
+		}
+
+		if prefix.IPv4Prefix != "" {
+			ranges = append(ranges, prefix.IPv4Prefix)
+		}
+		if prefix.IPv6Prefix != "" {
+			ranges = append(ranges, prefix.IPv6Prefix)
+		}
+	}
+
+	return ranges, nil
+}
+
+// matchFilter returns whether the value is one of the filter values, or true if there is no filter.
+func matchFilter(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, f := range filter {
+		if strings.EqualFold(f, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newFeedClient creates the client fetching the feeds, which only connects to the allowed IPs, over HTTPS.
+// The proxy settings of the environment are not used, as the destination would then be the proxy.
+func newFeedClient(allowed func(net.IP) bool, tlsConfig *tls.Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		// The destination is checked once resolved, so that a domain cannot be resolved to a forbidden address.
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("feed destination %s is not allowed", host)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: fetchTimeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost:   1,
+			IdleConnTimeout:       time.Minute,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxFeedRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxFeedRedirects)
+			}
+
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to a non HTTPS URL: %s", req.URL.Redacted())
+			}
+
+			return nil
+		},
+	}
+}
+
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"fc00::/7",
+)
+
+// isPublicIP returns whether the IP is a public unicast address,
+// rejecting the loopback, link-local (including the cloud metadata endpoints), private and shared addresses.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks
+}
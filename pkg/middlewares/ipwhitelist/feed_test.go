@@ -0,0 +1,325 @@
+package ipwhitelist
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewFetcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.SourceRangeFeed
+		expectedName  string
+		expectedError bool
+	}{
+		{
+			desc:          "no source",
+			config:        dynamic.SourceRangeFeed{},
+			expectedError: true,
+		},
+		{
+			desc: "several sources",
+			config: dynamic.SourceRangeFeed{
+				URL: "https://example.com",
+				DNS: "example.com",
+			},
+			expectedError: true,
+		},
+		{
+			desc:         "URL",
+			config:       dynamic.SourceRangeFeed{URL: "https://example.com"},
+			expectedName: "url:https://example.com",
+		},
+		{
+			desc:          "HTTP URL",
+			config:        dynamic.SourceRangeFeed{URL: "http://example.com"},
+			expectedError: true,
+		},
+		{
+			desc:          "file URL",
+			config:        dynamic.SourceRangeFeed{URL: "file:///etc/passwd"},
+			expectedError: true,
+		},
+		{
+			desc:         "DNS",
+			config:       dynamic.SourceRangeFeed{DNS: "example.com"},
+			expectedName: "dns:example.com",
+		},
+		{
+			desc: "AWS",
+			config: dynamic.SourceRangeFeed{
+				AWS: &dynamic.AWSIPRanges{Regions: []string{"eu-west-1", "eu-west-3"}, Services: []string{"CLOUDFRONT"}},
+			},
+			expectedName: "aws:regions=eu-west-1,eu-west-3;services=CLOUDFRONT",
+		},
+		{
+			desc:         "GCP",
+			config:       dynamic.SourceRangeFeed{GCP: &dynamic.GCPIPRanges{}},
+			expectedName: "gcp:scopes=",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			name, fetch, err := newFetcher(test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedName, name)
+			assert.NotNil(t, fetch)
+		})
+	}
+}
+
+// useTestServer makes the feed client trust the test server, and allows it to connect to the loopback address.
+func useTestServer(t *testing.T, server *httptest.Server) {
+	t.Helper()
+
+	previous := feedClient
+	t.Cleanup(func() { feedClient = previous })
+
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+	feedClient = newFeedClient(func(net.IP) bool { return true }, tlsConfig)
+}
+
+func TestFetchURL(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/ranges" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = fmt.Fprint(rw, "# Allowed ranges\n10.0.0.0/8\n\n  192.168.1.1  # gateway\n2001:db8::/32\n")
+	}))
+	defer server.Close()
+
+	useTestServer(t, server)
+
+	ranges, err := fetchURL(context.Background(), server.URL+"/ranges")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"}, ranges)
+
+	_, err = fetchURL(context.Background(), server.URL+"/unknown")
+	assert.Error(t, err)
+}
+
+func TestFetchAWS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, `{
+  "prefixes": [
+    {"ip_prefix": "3.5.140.0/22", "region": "ap-northeast-2", "service": "AMAZON"},
+    {"ip_prefix": "13.32.0.0/15", "region": "GLOBAL", "service": "CLOUDFRONT"},
+    {"ip_prefix": "15.188.0.0/16", "region": "eu-west-3", "service": "EC2"}
+  ],
+  "ipv6_prefixes": [
+    {"ipv6_prefix": "2600:9000::/28", "region": "GLOBAL", "service": "CLOUDFRONT"}
+  ]
+}`)
+	}))
+	defer server.Close()
+
+	useTestServer(t, server)
+	awsIPRangesURL = server.URL
+
+	testCases := []struct {
+		desc     string
+		filters  dynamic.AWSIPRanges
+		expected []string
+	}{
+		{
+			desc:     "no filter",
+			expected: []string{"3.5.140.0/22", "13.32.0.0/15", "15.188.0.0/16", "2600:9000::/28"},
+		},
+		{
+			desc:     "filtered by service",
+			filters:  dynamic.AWSIPRanges{Services: []string{"cloudfront"}},
+			expected: []string{"13.32.0.0/15", "2600:9000::/28"},
+		},
+		{
+			desc:     "filtered by region and service",
+			filters:  dynamic.AWSIPRanges{Regions: []string{"eu-west-3", "ap-northeast-2"}, Services: []string{"EC2"}},
+			expected: []string{"15.188.0.0/16"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			ranges, err := fetchAWS(context.Background(), test.filters)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ranges)
+		})
+	}
+}
+
+func TestFetchGCP(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, `{
+  "prefixes": [
+    {"ipv4Prefix": "34.80.0.0/15", "service": "Google Cloud", "scope": "asia-east1"},
+    {"ipv6Prefix": "2600:1900:4030::/44", "service": "Google Cloud", "scope": "asia-east1"},
+    {"ipv4Prefix": "34.140.0.0/16", "service": "Google Cloud", "scope": "europe-west1"}
+  ]
+}`)
+	}))
+	defer server.Close()
+
+	useTestServer(t, server)
+	gcpIPRangesURL = server.URL
+
+	ranges, err := fetchGCP(context.Background(), dynamic.GCPIPRanges{Scopes: []string{"asia-east1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"34.80.0.0/15", "2600:1900:4030::/44"}, ranges)
+}
+
+func TestFeedClient_forbiddenDestination(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, "30.30.30.0/24\n")
+	}))
+	defer server.Close()
+
+	_, err := fetchURL(context.Background(), server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not allowed")
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testCases := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "8.8.8.8", expected: true},
+		{ip: "2001:4860:4860::8888", expected: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "0.0.0.0"},
+		{ip: "10.1.2.3"},
+		{ip: "172.20.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "100.64.0.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00::1"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "224.0.0.1"},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, isPublicIP(net.ParseIP(test.ip)), test.ip)
+	}
+}
+
+func TestAcquireFeed_release(t *testing.T) {
+	config := dynamic.SourceRangeFeed{DNS: "release.example.com"}
+
+	f1, err := acquireFeed(config)
+	require.NoError(t, err)
+
+	f2, err := acquireFeed(config)
+	require.NoError(t, err)
+	assert.Same(t, f1, f2)
+
+	f1.release()
+
+	feedsMu.Lock()
+	assert.Contains(t, feeds, f1.key)
+	feedsMu.Unlock()
+
+	f2.release()
+
+	feedsMu.Lock()
+	assert.NotContains(t, feeds, f1.key)
+	feedsMu.Unlock()
+}
+
+func TestIPWhiteLister_ServeHTTP_sourceRangeFeeds(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/allow":
+			_, _ = fmt.Fprint(rw, "30.30.30.0/24\n")
+		case "/deny":
+			_, _ = fmt.Fprint(rw, "30.30.30.30\n")
+		}
+	}))
+	defer server.Close()
+
+	useTestServer(t, server)
+
+	config := dynamic.IPWhiteList{
+		SourceRange:          []string{"20.20.20.0/24"},
+		SourceRangeFeeds:     []dynamic.SourceRangeFeed{{URL: server.URL + "/allow"}},
+		DenySourceRange:      []string{"20.20.20.20"},
+		DenySourceRangeFeeds: []dynamic.SourceRangeFeed{{URL: server.URL + "/deny"}},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	whiteLister, err := New(context.Background(), next, config, "traefikTest")
+	require.NoError(t, err)
+
+	wl := whiteLister.(*ipWhiteLister)
+	assert.Eventually(t, func() bool {
+		return wl.feeds[0].getChecker() != nil && wl.denyFeeds[0].getChecker() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	testCases := []struct {
+		remoteAddr string
+		expected   int
+	}{
+		{remoteAddr: "20.20.20.21:1234", expected: http.StatusOK},
+		{remoteAddr: "20.20.20.20:1234", expected: http.StatusForbidden},
+		{remoteAddr: "30.30.30.31:1234", expected: http.StatusOK},
+		{remoteAddr: "30.30.30.30:1234", expected: http.StatusForbidden},
+		{remoteAddr: "40.40.40.40:1234", expected: http.StatusForbidden},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://10.10.10.10", nil)
+		req.RemoteAddr = test.remoteAddr
+
+		recorder := httptest.NewRecorder()
+		whiteLister.ServeHTTP(recorder, req)
+
+		assert.Equal(t, test.expected, recorder.Code, test.remoteAddr)
+	}
+}
+
+func TestIPWhiteLister_ServeHTTP_denyOnly(t *testing.T) {
+	config := dynamic.IPWhiteList{DenySourceRange: []string{"20.20.20.20"}}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	whiteLister, err := New(context.Background(), next, config, "traefikTest")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		remoteAddr string
+		expected   int
+	}{
+		{remoteAddr: "20.20.20.20:1234", expected: http.StatusForbidden},
+		{remoteAddr: "40.40.40.40:1234", expected: http.StatusOK},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "http://10.10.10.10", nil)
+		req.RemoteAddr = test.remoteAddr
+
+		recorder := httptest.NewRecorder()
+		whiteLister.ServeHTTP(recorder, req)
+
+		assert.Equal(t, test.expected, recorder.Code, test.remoteAddr)
+	}
+}
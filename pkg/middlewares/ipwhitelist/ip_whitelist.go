@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -22,6 +23,9 @@ const (
 type ipWhiteLister struct {
 	next        http.Handler
 	whiteLister *ip.Checker
+	feeds       []*feed
+	denyLister  *ip.Checker
+	denyFeeds   []*feed
 	strategy    ip.Strategy
 	name        string
 }
@@ -31,13 +35,26 @@ func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, nam
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
 
-	if len(config.SourceRange) == 0 {
-		return nil, errors.New("sourceRange is empty, IPWhiteLister not created")
+	if len(config.SourceRange) == 0 && len(config.SourceRangeFeeds) == 0 && len(config.DenySourceRange) == 0 && len(config.DenySourceRangeFeeds) == 0 {
+		return nil, errors.New("sourceRange, sourceRangeFeeds, denySourceRange and denySourceRangeFeeds are empty, IPWhiteLister not created")
 	}
 
-	checker, err := ip.NewChecker(config.SourceRange)
-	if err != nil {
-		return nil, fmt.Errorf("cannot parse CIDR whitelist %s: %w", config.SourceRange, err)
+	var checker *ip.Checker
+	if len(config.SourceRange) > 0 {
+		var err error
+		checker, err = ip.NewChecker(config.SourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CIDR whitelist %s: %w", config.SourceRange, err)
+		}
+	}
+
+	var denyChecker *ip.Checker
+	if len(config.DenySourceRange) > 0 {
+		var err error
+		denyChecker, err = ip.NewChecker(config.DenySourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CIDR deny list %s: %w", config.DenySourceRange, err)
+		}
 	}
 
 	strategy, err := config.IPStrategy.Get()
@@ -45,14 +62,58 @@ func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, nam
 		return nil, err
 	}
 
-	logger.Debugf("Setting up IPWhiteLister with sourceRange: %s", config.SourceRange)
-
-	return &ipWhiteLister{
+	wl := &ipWhiteLister{
 		strategy:    strategy,
 		whiteLister: checker,
+		denyLister:  denyChecker,
 		next:        next,
 		name:        name,
-	}, nil
+	}
+
+	wl.feeds, err = acquireFeeds(config.SourceRangeFeeds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source range feed %w", err)
+	}
+
+	wl.denyFeeds, err = acquireFeeds(config.DenySourceRangeFeeds)
+	if err != nil {
+		releaseFeeds(wl.feeds)
+		return nil, fmt.Errorf("invalid deny source range feed %w", err)
+	}
+
+	// The middlewares are not closed when the configuration is reloaded,
+	// so the feeds are released once the middleware is garbage collected.
+	runtime.SetFinalizer(wl, func(wl *ipWhiteLister) {
+		releaseFeeds(wl.feeds)
+		releaseFeeds(wl.denyFeeds)
+	})
+
+	logger.Debugf("Setting up IPWhiteLister with sourceRange: %s, denySourceRange: %s", config.SourceRange, config.DenySourceRange)
+
+	return wl, nil
+}
+
+// acquireFeeds acquires the feeds of the given configurations, and triggers their first fetch if needed.
+func acquireFeeds(configs []dynamic.SourceRangeFeed) ([]*feed, error) {
+	var feeds []*feed
+	for i, feedConfig := range configs {
+		f, err := acquireFeed(feedConfig)
+		if err != nil {
+			releaseFeeds(feeds)
+			return nil, fmt.Errorf("at index %d: %w", i, err)
+		}
+
+		f.getChecker()
+		feeds = append(feeds, f)
+	}
+
+	return feeds, nil
+}
+
+func releaseFeeds(feeds []*feed) {
+	for _, f := range feeds {
+		f.release()
+	}
 }
 
 func (wl *ipWhiteLister) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -63,7 +124,7 @@ func (wl *ipWhiteLister) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := middlewares.GetLoggerCtx(req.Context(), wl.name, typeName)
 	logger := log.FromContext(ctx)
 
-	err := wl.whiteLister.IsAuthorized(wl.strategy.GetIP(req))
+	err := wl.isAuthorized(wl.strategy.GetIP(req))
 	if err != nil {
 		logMessage := fmt.Sprintf("rejecting request %+v: %v", req, err)
 		logger.Debug(logMessage)
@@ -76,6 +137,47 @@ func (wl *ipWhiteLister) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	wl.next.ServeHTTP(rw, req)
 }
 
+// isAuthorized checks that the address is not denied by the deny source range or feeds,
+// and that it is allowed by the source range, or by one of the source range feeds.
+// When only deny lists are defined, any address which is not denied is allowed.
+func (wl *ipWhiteLister) isAuthorized(addr string) error {
+	if wl.denyLister != nil && wl.denyLister.IsAuthorized(addr) == nil {
+		return fmt.Errorf("%q matched the deny source range", addr)
+	}
+
+	for _, f := range wl.denyFeeds {
+		// The feed checker is nil until the feed has been fetched successfully.
+		if checker := f.getChecker(); checker != nil && checker.IsAuthorized(addr) == nil {
+			return fmt.Errorf("%q matched the deny source range feed %s", addr, f.name)
+		}
+	}
+
+	if wl.whiteLister == nil && len(wl.feeds) == 0 {
+		return nil
+	}
+
+	err := errors.New("no source range available")
+
+	if wl.whiteLister != nil {
+		if err = wl.whiteLister.IsAuthorized(addr); err == nil {
+			return nil
+		}
+	}
+
+	for _, f := range wl.feeds {
+		checker := f.getChecker()
+		if checker == nil {
+			continue
+		}
+
+		if err = checker.IsAuthorized(addr); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
 func reject(ctx context.Context, rw http.ResponseWriter) {
 	statusCode := http.StatusForbidden
 
@@ -0,0 +1,317 @@
+package limits
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Limits"
+)
+
+// Names of the limits, used in the error bodies and as the limit label of the violations metric.
+const (
+	limitRequestHeader = "requestHeader"
+	limitRequestBody   = "requestBody"
+	limitResponseBody  = "responseBody"
+)
+
+// errRequestBodyTooLarge is returned when reading a request body exceeding the limit.
+var errRequestBodyTooLarge = errors.New("request body too large")
+
+// errResponseBodyTooLarge is returned when writing a response body exceeding the limit,
+// which aborts the response already sent to the client.
+var errResponseBodyTooLarge = errors.New("response body too large")
+
+// limits is a middleware enforcing size limits on the request headers, the request body, and the response body.
+// The requests exceeding a limit are answered with a JSON error body.
+type limits struct {
+	next                  http.Handler
+	name                  string
+	maxRequestHeaderBytes int64
+	maxRequestBodyBytes   int64
+	maxResponseBodyBytes  int64
+	violations            gokitmetrics.Counter
+}
+
+// New creates a limits middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Limits, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.MaxRequestHeaderBytes < 0 || config.MaxRequestBodyBytes < 0 || config.MaxResponseBodyBytes < 0 {
+		return nil, errors.New("limits must be positive")
+	}
+
+	if config.MaxRequestHeaderBytes == 0 && config.MaxRequestBodyBytes == 0 && config.MaxResponseBodyBytes == 0 {
+		return nil, errors.New("at least one of maxRequestHeaderBytes, maxRequestBodyBytes or maxResponseBodyBytes must be defined")
+	}
+
+	l := &limits{
+		next:                  next,
+		name:                  name,
+		maxRequestHeaderBytes: config.MaxRequestHeaderBytes,
+		maxRequestBodyBytes:   config.MaxRequestBodyBytes,
+		maxResponseBodyBytes:  config.MaxResponseBodyBytes,
+	}
+
+	if metricsRegistry != nil && metricsRegistry.IsSvcEnabled() {
+		l.violations = metricsRegistry.LimitsViolationsCounter()
+	}
+
+	return l, nil
+}
+
+func (l *limits) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return l.name, tracing.SpanKindNoneEnum
+}
+
+func (l *limits) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if l.maxRequestHeaderBytes > 0 && headerSize(req.Header) > l.maxRequestHeaderBytes {
+		l.reject(rw, req, http.StatusRequestHeaderFieldsTooLarge, limitRequestHeader, l.maxRequestHeaderBytes)
+		return
+	}
+
+	var body *bodyReader
+	if l.maxRequestBodyBytes > 0 && req.Body != nil && req.Body != http.NoBody {
+		if req.ContentLength > l.maxRequestBodyBytes {
+			l.reject(rw, req, http.StatusRequestEntityTooLarge, limitRequestBody, l.maxRequestBodyBytes)
+			return
+		}
+
+		body = &bodyReader{ReadCloser: req.Body, remaining: l.maxRequestBodyBytes}
+		req.Body = body
+	}
+
+	lrw := &responseWriter{rw: rw, limits: l, req: req, body: body}
+	l.next.ServeHTTP(lrw, req)
+
+	// The next handler may not have written any response after failing to read the request body.
+	if body != nil && body.isExceeded() && !lrw.headerWritten {
+		lrw.headerWritten = true
+		lrw.rejected = true
+		l.reject(rw, req, http.StatusRequestEntityTooLarge, limitRequestBody, l.maxRequestBodyBytes)
+	}
+}
+
+// reject answers the request with a JSON error body describing the exceeded limit,
+// in place of any response of the next handler.
+func (l *limits) reject(rw http.ResponseWriter, req *http.Request, statusCode int, limit string, maxBytes int64) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), l.name, typeName))
+	logger.Debugf("Limit %s of %d bytes exceeded", limit, maxBytes)
+	tracing.SetErrorWithEvent(req, "limit %s of %d bytes exceeded", limit, maxBytes)
+
+	l.countViolation(limit)
+
+	for k := range rw.Header() {
+		rw.Header().Del(k)
+	}
+
+	// The request body may not have been fully read, so the connection cannot be reused.
+	if limit == limitRequestBody {
+		rw.Header().Set("Connection", "close")
+	}
+
+	writeError(rw, statusCode, limit, maxBytes)
+}
+
+func (l *limits) countViolation(limit string) {
+	if l.violations != nil {
+		l.violations.With("middleware", l.name, "limit", limit).Add(1)
+	}
+}
+
+// errorBody is the JSON body of the responses to the requests exceeding a limit.
+type errorBody struct {
+	Error    string `json:"error"`
+	Limit    string `json:"limit"`
+	MaxBytes int64  `json:"maxBytes"`
+}
+
+func writeError(rw http.ResponseWriter, statusCode int, limit string, maxBytes int64) {
+	data, err := json.Marshal(errorBody{
+		Error:    http.StatusText(statusCode),
+		Limit:    limit,
+		MaxBytes: maxBytes,
+	})
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to marshal the limit error: %v", err)
+		rw.WriteHeader(statusCode)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	rw.WriteHeader(statusCode)
+
+	if _, err = rw.Write(data); err != nil {
+		log.WithoutContext().Debugf("Error while writing the limit error: %v", err)
+	}
+}
+
+// headerSize returns the size of the headers, as the sum of the lengths of their names and values.
+func headerSize(header http.Header) int64 {
+	var size int64
+	for name, values := range header {
+		for _, value := range values {
+			size += int64(len(name) + len(value))
+		}
+	}
+
+	return size
+}
+
+// bodyReader fails the reads of a request body exceeding the limit.
+// The request body is read by the proxy transport in its own goroutine, hence the atomic flag.
+type bodyReader struct {
+	io.ReadCloser
+
+	remaining int64
+	exceeded  int32
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	if b.isExceeded() {
+		return 0, errRequestBodyTooLarge
+	}
+
+	// Reading one more byte than remaining tells whether the body exceeds the limit.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		atomic.StoreInt32(&b.exceeded, 1)
+		return int(b.remaining), errRequestBodyTooLarge
+	}
+
+	b.remaining -= int64(n)
+
+	return n, err
+}
+
+func (b *bodyReader) isExceeded() bool {
+	return atomic.LoadInt32(&b.exceeded) == 1
+}
+
+// responseWriter replaces the response of the next handler by a JSON error,
+// when the request body or the response body exceeds its limit before the response headers are written.
+// Once the response headers are written, a response body exceeding the limit aborts the response.
+type responseWriter struct {
+	rw     http.ResponseWriter
+	limits *limits
+	req    *http.Request
+	body   *bodyReader
+
+	headerWritten bool
+	rejected      bool
+	aborted       bool
+	written       int64
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.headerWritten {
+		return
+	}
+
+	// The informational responses precede the final one, which can still be replaced.
+	if middlewares.IsInformational(code) {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.headerWritten = true
+
+	if w.body != nil && w.body.isExceeded() {
+		w.rejected = true
+		w.limits.reject(w.rw, w.req, http.StatusRequestEntityTooLarge, limitRequestBody, w.limits.maxRequestBodyBytes)
+		return
+	}
+
+	if w.limits.maxResponseBodyBytes > 0 {
+		contentLength, err := strconv.ParseInt(w.rw.Header().Get("Content-Length"), 10, 64)
+		if err == nil && contentLength > w.limits.maxResponseBodyBytes {
+			w.rejected = true
+			w.limits.reject(w.rw, w.req, http.StatusBadGateway, limitResponseBody, w.limits.maxResponseBodyBytes)
+			return
+		}
+	}
+
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.aborted {
+		return 0, errResponseBodyTooLarge
+	}
+
+	// The response of the next handler is discarded.
+	if w.rejected {
+		return len(b), nil
+	}
+
+	if w.limits.maxResponseBodyBytes > 0 && w.written+int64(len(b)) > w.limits.maxResponseBodyBytes {
+		w.limits.countViolation(limitResponseBody)
+
+		logger := log.FromContext(middlewares.GetLoggerCtx(w.req.Context(), w.limits.name, typeName))
+		logger.Debugf("Limit %s of %d bytes exceeded, aborting the response", limitResponseBody, w.limits.maxResponseBodyBytes)
+		tracing.SetErrorWithEvent(w.req, "limit %s of %d bytes exceeded", limitResponseBody, w.limits.maxResponseBodyBytes)
+
+		w.aborted = true
+		return 0, errResponseBodyTooLarge
+	}
+
+	n, err := w.rw.Write(b)
+	w.written += int64(n)
+
+	return n, err
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.rw.(http.Hijacker); ok {
+		w.headerWritten = true
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", w.rw)
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.rejected || w.aborted {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
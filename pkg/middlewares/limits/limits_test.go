@@ -0,0 +1,205 @@
+package limits
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// collectingCounter is a metrics.Counter recording the label values of the last increment.
+type collectingCounter struct {
+	value           float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.value += delta
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Limits
+		expectedError bool
+	}{
+		{
+			desc:          "no limit",
+			config:        dynamic.Limits{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative limit",
+			config:        dynamic.Limits{MaxRequestBodyBytes: -1},
+			expectedError: true,
+		},
+		{
+			desc:   "request body limit",
+			config: dynamic.Limits{MaxRequestBodyBytes: 1024},
+		},
+		{
+			desc: "all limits",
+			config: dynamic.Limits{
+				MaxRequestHeaderBytes: 1024,
+				MaxRequestBodyBytes:   1024,
+				MaxResponseBodyBytes:  1024,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, nil, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestLimits_ServeHTTP(t *testing.T) {
+	config := dynamic.Limits{
+		MaxRequestHeaderBytes: 64,
+		MaxRequestBodyBytes:   10,
+		MaxResponseBodyBytes:  10,
+	}
+
+	testCases := []struct {
+		desc           string
+		reqHeaders     map[string]string
+		reqBody        string
+		chunked        bool
+		resContentLen  bool
+		resBody        string
+		expectedStatus int
+		expectedBody   string
+		expectedLimit  string
+	}{
+		{
+			desc:           "within limits",
+			reqBody:        "foo",
+			resBody:        "bar",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "foo:bar",
+		},
+		{
+			desc:           "request headers too large",
+			reqHeaders:     map[string]string{"X-Large": strings.Repeat("a", 64)},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+			expectedBody:   `{"error":"Request Header Fields Too Large","limit":"requestHeader","maxBytes":64}`,
+			expectedLimit:  limitRequestHeader,
+		},
+		{
+			desc:           "request body content length too large",
+			reqBody:        "foobarfoobar",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request Entity Too Large","limit":"requestBody","maxBytes":10}`,
+			expectedLimit:  limitRequestBody,
+		},
+		{
+			desc:           "chunked request body too large",
+			reqBody:        "foobarfoobar",
+			chunked:        true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request Entity Too Large","limit":"requestBody","maxBytes":10}`,
+			expectedLimit:  limitRequestBody,
+		},
+		{
+			desc:           "response content length too large",
+			resBody:        "foobarfoobar",
+			resContentLen:  true,
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   `{"error":"Bad Gateway","limit":"responseBody","maxBytes":10}`,
+			expectedLimit:  limitResponseBody,
+		},
+		{
+			desc:           "streamed response too large",
+			resBody:        "foobarfoobar",
+			expectedStatus: http.StatusOK,
+			expectedBody:   ":",
+			expectedLimit:  limitResponseBody,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var writeErr error
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				if test.resContentLen {
+					rw.Header().Set("Content-Length", strconv.Itoa(len(body)+1+len(test.resBody)))
+				}
+
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.WriteHeader(http.StatusOK)
+
+				_, _ = rw.Write(append(body, ':'))
+				_, writeErr = rw.Write([]byte(test.resBody))
+			})
+
+			counter := &collectingCounter{}
+
+			handler, err := New(context.Background(), next, config, nil, "traefikTest")
+			require.NoError(t, err)
+			handler.(*limits).violations = counter
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(test.reqBody))
+			if test.chunked {
+				req.ContentLength = -1
+			}
+			for name, value := range test.reqHeaders {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+
+			if test.expectedLimit == "" {
+				assert.Zero(t, counter.value)
+				assert.NoError(t, writeErr)
+				return
+			}
+
+			assert.Equal(t, float64(1), counter.value)
+			assert.Equal(t, []string{"middleware", "traefikTest", "limit", test.expectedLimit}, counter.lastLabelValues)
+
+			if test.expectedStatus != http.StatusOK {
+				assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+			} else {
+				assert.Equal(t, errResponseBodyTooLarge, writeErr)
+			}
+		})
+	}
+}
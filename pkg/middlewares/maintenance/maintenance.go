@@ -0,0 +1,163 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Maintenance"
+)
+
+// maintenance is a middleware answering the requests with a static response,
+// except for the requests allowed to bypass the maintenance mode.
+type maintenance struct {
+	next        http.Handler
+	name        string
+	statusCode  int
+	contentType string
+	body        []byte
+	retryAfter  string
+
+	bypassHeader string
+	bypassToken  []byte
+	bypassRange  *ip.Checker
+	strategy     ip.Strategy
+}
+
+// New creates a maintenance middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Maintenance, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	statusCode := config.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if statusCode < 100 || statusCode > 599 {
+		return nil, fmt.Errorf("invalid status code %d", statusCode)
+	}
+
+	if config.RetryAfter < 0 {
+		return nil, errors.New("retryAfter must be positive")
+	}
+
+	m := &maintenance{
+		next:        next,
+		name:        name,
+		statusCode:  statusCode,
+		contentType: config.ContentType,
+		body:        []byte(config.Body),
+	}
+
+	if len(m.body) == 0 {
+		m.body = []byte(http.StatusText(statusCode))
+	}
+
+	if m.contentType == "" {
+		m.contentType = http.DetectContentType(m.body)
+	}
+
+	if config.RetryAfter > 0 {
+		// Retry-After is a number of seconds, rounded up.
+		seconds := (time.Duration(config.RetryAfter) + time.Second - 1) / time.Second
+		m.retryAfter = strconv.FormatInt(int64(seconds), 10)
+	}
+
+	if config.Bypass != nil {
+		if err := m.setBypass(*config.Bypass); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *maintenance) setBypass(config dynamic.MaintenanceBypass) error {
+	if (config.HeaderName == "") != (config.Token == "") {
+		return errors.New("bypass headerName and token must be both defined")
+	}
+
+	if config.HeaderName == "" && len(config.SourceRange) == 0 {
+		return errors.New("bypass requires a headerName and a token, or a sourceRange")
+	}
+
+	m.bypassHeader = config.HeaderName
+	m.bypassToken = []byte(config.Token)
+
+	if len(config.SourceRange) > 0 {
+		checker, err := ip.NewChecker(config.SourceRange)
+		if err != nil {
+			return fmt.Errorf("cannot parse bypass sourceRange %s: %w", config.SourceRange, err)
+		}
+		m.bypassRange = checker
+
+		m.strategy, err = config.IPStrategy.Get()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *maintenance) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return m.name, tracing.SpanKindNoneEnum
+}
+
+func (m *maintenance) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), m.name, typeName))
+
+	if m.isBypassed(req) {
+		logger.Debug("Bypassing maintenance mode")
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	rw.Header().Set("Content-Type", m.contentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(len(m.body)))
+	rw.Header().Set("Cache-Control", "no-store")
+	if m.retryAfter != "" {
+		rw.Header().Set("Retry-After", m.retryAfter)
+	}
+
+	rw.WriteHeader(m.statusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(m.body); err != nil {
+		logger.Debugf("Error while writing maintenance response: %v", err)
+	}
+}
+
+// isBypassed returns whether the request holds the bypass token, or comes from the bypass source range.
+func (m *maintenance) isBypassed(req *http.Request) bool {
+	if m.bypassHeader != "" {
+		token := req.Header.Get(m.bypassHeader)
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), m.bypassToken) == 1 {
+			// The token is not forwarded to the service.
+			req.Header.Del(m.bypassHeader)
+			return true
+		}
+	}
+
+	if m.bypassRange != nil {
+		return m.bypassRange.IsAuthorized(m.strategy.GetIP(req)) == nil
+	}
+
+	return false
+}
@@ -0,0 +1,204 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Maintenance
+		expectedError bool
+	}{
+		{
+			desc:   "default configuration",
+			config: dynamic.Maintenance{},
+		},
+		{
+			desc:          "invalid status code",
+			config:        dynamic.Maintenance{StatusCode: 1000},
+			expectedError: true,
+		},
+		{
+			desc:          "bypass header without token",
+			config:        dynamic.Maintenance{Bypass: &dynamic.MaintenanceBypass{HeaderName: "X-Bypass"}},
+			expectedError: true,
+		},
+		{
+			desc:          "empty bypass",
+			config:        dynamic.Maintenance{Bypass: &dynamic.MaintenanceBypass{}},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid bypass source range",
+			config:        dynamic.Maintenance{Bypass: &dynamic.MaintenanceBypass{SourceRange: []string{"foo"}}},
+			expectedError: true,
+		},
+		{
+			desc: "bypass",
+			config: dynamic.Maintenance{
+				Bypass: &dynamic.MaintenanceBypass{HeaderName: "X-Bypass", Token: "secret", SourceRange: []string{"10.0.0.0/8"}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestMaintenance_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.Maintenance
+		method          string
+		remoteAddr      string
+		reqHeaders      map[string]string
+		expectedStatus  int
+		expectedBody    string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:           "default response",
+			config:         dynamic.Maintenance{},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "Service Unavailable",
+			expectedHeaders: map[string]string{
+				"Content-Type":  "text/plain; charset=utf-8",
+				"Cache-Control": "no-store",
+				"Retry-After":   "",
+			},
+		},
+		{
+			desc: "HTML response",
+			config: dynamic.Maintenance{
+				Body:       "<html><body>Back soon</body></html>",
+				RetryAfter: ptypes.Duration(90*time.Second + time.Millisecond),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "<html><body>Back soon</body></html>",
+			expectedHeaders: map[string]string{
+				"Content-Type": "text/html; charset=utf-8",
+				"Retry-After":  "91",
+			},
+		},
+		{
+			desc: "JSON response",
+			config: dynamic.Maintenance{
+				StatusCode:  http.StatusOK,
+				ContentType: "application/json",
+				Body:        `{"status":"maintenance"}`,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"maintenance"}`,
+			expectedHeaders: map[string]string{
+				"Content-Type": "application/json",
+			},
+		},
+		{
+			desc:           "HEAD request",
+			config:         dynamic.Maintenance{},
+			method:         http.MethodHead,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "bypass with token",
+			config: dynamic.Maintenance{
+				Bypass: &dynamic.MaintenanceBypass{HeaderName: "X-Bypass", Token: "secret"},
+			},
+			reqHeaders:     map[string]string{"X-Bypass": "secret"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "next",
+		},
+		{
+			desc: "bypass with wrong token",
+			config: dynamic.Maintenance{
+				Bypass: &dynamic.MaintenanceBypass{HeaderName: "X-Bypass", Token: "secret"},
+			},
+			reqHeaders:     map[string]string{"X-Bypass": "secre"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "Service Unavailable",
+		},
+		{
+			desc: "bypass with source range",
+			config: dynamic.Maintenance{
+				Bypass: &dynamic.MaintenanceBypass{SourceRange: []string{"10.0.0.0/8"}},
+			},
+			remoteAddr:     "10.1.2.3:1234",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "next",
+		},
+		{
+			desc: "bypass with source range and IP strategy",
+			config: dynamic.Maintenance{
+				Bypass: &dynamic.MaintenanceBypass{
+					SourceRange: []string{"10.0.0.0/8"},
+					IPStrategy:  &dynamic.IPStrategy{Depth: 1},
+				},
+			},
+			remoteAddr:     "10.1.2.3:1234",
+			reqHeaders:     map[string]string{"X-Forwarded-For": "20.20.20.20"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "Service Unavailable",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Empty(t, req.Header.Get("X-Bypass"))
+				_, _ = rw.Write([]byte("next"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://localhost", nil)
+			if test.remoteAddr != "" {
+				req.RemoteAddr = test.remoteAddr
+			}
+			for k, v := range test.reqHeaders {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			for k, v := range test.expectedHeaders {
+				assert.Equal(t, v, recorder.Header().Get(k), k)
+			}
+		})
+	}
+}
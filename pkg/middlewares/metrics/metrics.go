@@ -15,6 +15,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/tracing"
 )
 
 const (
@@ -33,6 +34,11 @@ type metricsMiddleware struct {
 	reqDurationHistogram metrics.ScalableHistogram
 	openConnsGauge       gokitmetrics.Gauge
 	baseLabels           []string
+
+	// pathReqsCounter and pathReqDurationHistogram are only set on services,
+	// when the metrics on the path templates are enabled.
+	pathReqsCounter          gokitmetrics.Counter
+	pathReqDurationHistogram metrics.ScalableHistogram
 }
 
 // NewEntryPointMiddleware creates a new metrics middleware for an Entrypoint.
@@ -53,7 +59,7 @@ func NewEntryPointMiddleware(ctx context.Context, next http.Handler, registry me
 func NewServiceMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, serviceName string) http.Handler {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, nameService, typeName)).Debug("Creating middleware")
 
-	return &metricsMiddleware{
+	m := &metricsMiddleware{
 		next:                 next,
 		reqsCounter:          registry.ServiceReqsCounter(),
 		reqsTLSCounter:       registry.ServiceReqsTLSCounter(),
@@ -61,6 +67,13 @@ func NewServiceMiddleware(ctx context.Context, next http.Handler, registry metri
 		openConnsGauge:       registry.ServiceOpenConnsGauge(),
 		baseLabels:           []string{"service", serviceName},
 	}
+
+	if registry.IsPathEnabled() {
+		m.pathReqsCounter = registry.ServicePathReqsCounter()
+		m.pathReqDurationHistogram = registry.ServicePathReqDurationHistogram()
+	}
+
+	return m
 }
 
 // WrapEntryPointHandler Wraps metrics entrypoint to alice.Constructor.
@@ -70,6 +83,16 @@ func WrapEntryPointHandler(ctx context.Context, registry metrics.Registry, entry
 	}
 }
 
+// WrapDisabledHandler marks the requests as excluded from the metrics,
+// so that the services and middlewares they go through do not report them either.
+func WrapDisabledHandler() alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(rw, req.WithContext(middlewares.WithoutMetrics(req.Context())))
+		}), nil
+	}
+}
+
 // WrapServiceHandler Wraps metrics service to alice.Constructor.
 func WrapServiceHandler(ctx context.Context, registry metrics.Registry, serviceName string) alice.Constructor {
 	return func(next http.Handler) (http.Handler, error) {
@@ -78,6 +101,11 @@ func WrapServiceHandler(ctx context.Context, registry metrics.Registry, serviceN
 }
 
 func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if middlewares.MetricsDisabled(req.Context()) {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
 	var labels []string
 	labels = append(labels, m.baseLabels...)
 	labels = append(labels, "method", getMethod(req), "protocol", getRequestProtocol(req))
@@ -102,9 +130,23 @@ func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 	labels = append(labels, "code", strconv.Itoa(recorder.getCode()))
 
 	histograms := m.reqDurationHistogram.With(labels...)
-	histograms.ObserveFromStart(start)
+	histograms.ObserveFromStartWithTraceID(start, tracing.GetTraceID(req))
 
 	m.reqsCounter.With(labels...).Add(1)
+
+	// The requests without path template are not reported,
+	// as using their raw path would defeat the purpose of the templates.
+	template := middlewares.GetPathTemplate(req.Context())
+	if m.pathReqsCounter == nil || template == "" {
+		return
+	}
+
+	var pathLabels []string
+	pathLabels = append(pathLabels, m.baseLabels...)
+	pathLabels = append(pathLabels, "path", template, "method", getMethod(req), "code", strconv.Itoa(recorder.getCode()))
+
+	m.pathReqDurationHistogram.With(pathLabels...).ObserveFromStartWithTraceID(start, tracing.GetTraceID(req))
+	m.pathReqsCounter.With(pathLabels...).Add(1)
 }
 
 func getRequestProtocol(req *http.Request) string {
@@ -164,5 +206,9 @@ type RetryListener struct {
 
 // Retried tracks the retry in the RequestMetrics implementation.
 func (m *RetryListener) Retried(req *http.Request, attempt int) {
+	if middlewares.MetricsDisabled(req.Context()) {
+		return
+	}
+
 	m.retryMetrics.ServiceRetriesCounter().With("service", m.serviceName).Add(1)
 }
@@ -8,6 +8,9 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	traefikmetrics "github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 )
 
 // CollectingCounter is a metrics.Counter implementation that enables access to the CounterValue and LastLabelValues.
@@ -98,3 +101,73 @@ func TestCloseNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsMiddleware_pathTemplate(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		template       string
+		expectedValue  float64
+		expectedLabels []string
+	}{
+		{
+			desc:           "path template",
+			template:       "/users/{id}",
+			expectedValue:  1,
+			expectedLabels: []string{"service", "service1", "path", "/users/{id}", "method", http.MethodGet, "code", "200"},
+		},
+		{
+			desc: "no path template",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pathReqsCounter := &CollectingCounter{}
+
+			void := traefikmetrics.NewVoidRegistry()
+			handler := &metricsMiddleware{
+				next:                     http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}),
+				reqsCounter:              void.ServiceReqsCounter(),
+				reqsTLSCounter:           void.ServiceReqsTLSCounter(),
+				reqDurationHistogram:     void.ServiceReqDurationHistogram(),
+				openConnsGauge:           void.ServiceOpenConnsGauge(),
+				baseLabels:               []string{"service", "service1"},
+				pathReqsCounter:          pathReqsCounter,
+				pathReqDurationHistogram: void.ServicePathReqDurationHistogram(),
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/users/42", nil)
+			req = req.WithContext(middlewares.WithPathTemplate(req.Context(), test.template))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedValue, pathReqsCounter.CounterValue)
+			assert.Equal(t, test.expectedLabels, pathReqsCounter.LastLabelValues)
+		})
+	}
+}
+
+func TestWrapDisabledHandler(t *testing.T) {
+	reqsCounter := &CollectingCounter{}
+
+	void := traefikmetrics.NewVoidRegistry()
+	service := &metricsMiddleware{
+		next:                 http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}),
+		reqsCounter:          reqsCounter,
+		reqsTLSCounter:       void.ServiceReqsTLSCounter(),
+		reqDurationHistogram: void.ServiceReqDurationHistogram(),
+		openConnsGauge:       void.ServiceOpenConnsGauge(),
+		baseLabels:           []string{"service", "service1"},
+	}
+
+	service.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, float64(1), reqsCounter.CounterValue)
+
+	handler, err := WrapDisabledHandler()(service)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	assert.Equal(t, float64(1), reqsCounter.CounterValue)
+}
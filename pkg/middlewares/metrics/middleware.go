@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+// middlewareMetrics reports the metrics of a middleware instance,
+// measuring the time spent in the middleware itself by timing the handler it forwards the requests to.
+type middlewareMetrics struct {
+	handler              http.Handler
+	reqsCounter          gokitmetrics.Counter
+	reqDurationHistogram metrics.ScalableHistogram
+	shortCircuitsCounter gokitmetrics.Counter
+	middlewareName       string
+}
+
+// middlewareCall holds the time spent in the next handler during a request,
+// and whether the request has been forwarded at all.
+type middlewareCall struct {
+	forwarded   bool
+	nextElapsed time.Duration
+}
+
+// WrapMiddlewareHandler wraps the constructor of a middleware into an alice.Constructor
+// reporting its invocations, the latency it adds to the requests, and the responses it writes without forwarding the requests.
+func WrapMiddlewareHandler(registry metrics.Registry, middlewareName string, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		m := &middlewareMetrics{
+			reqsCounter:          registry.MiddlewareReqsCounter(),
+			reqDurationHistogram: registry.MiddlewareReqDurationHistogram(),
+			shortCircuitsCounter: registry.MiddlewareShortCircuitsCounter(),
+			middlewareName:       middlewareName,
+		}
+
+		handler, err := constructor(&nextTimer{middleware: m, next: next})
+		if err != nil {
+			return nil, err
+		}
+
+		m.handler = handler
+
+		return m, nil
+	}
+}
+
+func (m *middlewareMetrics) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if middlewares.MetricsDisabled(req.Context()) {
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	call := &middlewareCall{}
+	recorder := newResponseRecorder(rw)
+	start := time.Now()
+
+	m.handler.ServeHTTP(recorder, req.WithContext(context.WithValue(req.Context(), m, call)))
+
+	m.reqsCounter.With("middleware", m.middlewareName).Add(1)
+	m.reqDurationHistogram.With("middleware", m.middlewareName).ObserveFromStart(start.Add(call.nextElapsed))
+
+	if !call.forwarded {
+		m.shortCircuitsCounter.With("middleware", m.middlewareName, "code", strconv.Itoa(recorder.getCode())).Add(1)
+	}
+}
+
+// nextTimer measures the time spent in the handler following a middleware.
+type nextTimer struct {
+	middleware *middlewareMetrics
+	next       http.Handler
+}
+
+func (t *nextTimer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The call is missing when the middleware forwards a request it did not receive, which is then not timed.
+	call, ok := req.Context().Value(t.middleware).(*middlewareCall)
+	if !ok {
+		t.next.ServeHTTP(rw, req)
+		return
+	}
+
+	call.forwarded = true
+	start := time.Now()
+
+	t.next.ServeHTTP(rw, req)
+
+	call.nextElapsed += time.Since(start)
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	traefikmetrics "github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// collectingHistogram is a traefikmetrics.ScalableHistogram implementation that enables access to the last observed duration.
+type collectingHistogram struct {
+	LastDuration    time.Duration
+	LastLabelValues []string
+}
+
+func (h *collectingHistogram) With(labelValues ...string) traefikmetrics.ScalableHistogram {
+	h.LastLabelValues = labelValues
+	return h
+}
+
+func (h *collectingHistogram) Observe(v float64) {}
+
+func (h *collectingHistogram) ObserveFromStart(start time.Time) {
+	h.LastDuration = time.Since(start)
+}
+
+func (h *collectingHistogram) ObserveFromStartWithTraceID(start time.Time, _ string) {
+	h.ObserveFromStart(start)
+}
+
+type middlewareRegistry struct {
+	traefikmetrics.Registry
+	reqsCounter          *CollectingCounter
+	reqDurationHistogram *collectingHistogram
+	shortCircuitsCounter *CollectingCounter
+}
+
+func (r *middlewareRegistry) MiddlewareReqsCounter() metrics.Counter {
+	return r.reqsCounter
+}
+
+func (r *middlewareRegistry) MiddlewareReqDurationHistogram() traefikmetrics.ScalableHistogram {
+	return r.reqDurationHistogram
+}
+
+func (r *middlewareRegistry) MiddlewareShortCircuitsCounter() metrics.Counter {
+	return r.shortCircuitsCounter
+}
+
+func TestWrapMiddlewareHandler(t *testing.T) {
+	testCases := []struct {
+		desc                        string
+		statusCode                  int
+		expectedShortCircuits       float64
+		expectedShortCircuitsLabels []string
+	}{
+		{
+			desc: "forwarded request",
+		},
+		{
+			desc:                        "unauthorized request",
+			statusCode:                  http.StatusUnauthorized,
+			expectedShortCircuits:       1,
+			expectedShortCircuitsLabels: []string{"middleware", "auth@file", "code", "401"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := &middlewareRegistry{
+				Registry:             traefikmetrics.NewVoidRegistry(),
+				reqsCounter:          &CollectingCounter{},
+				reqDurationHistogram: &collectingHistogram{},
+				shortCircuitsCounter: &CollectingCounter{},
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+			})
+
+			constructor := func(next http.Handler) (http.Handler, error) {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					if test.statusCode != 0 {
+						rw.WriteHeader(test.statusCode)
+						return
+					}
+
+					next.ServeHTTP(rw, req)
+				}), nil
+			}
+
+			handler, err := WrapMiddlewareHandler(registry, "auth@file", constructor)(next)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+			assert.Equal(t, float64(1), registry.reqsCounter.CounterValue)
+			assert.Equal(t, []string{"middleware", "auth@file"}, registry.reqsCounter.LastLabelValues)
+
+			// The time spent in the next handler is not added to the middleware latency.
+			assert.Equal(t, []string{"middleware", "auth@file"}, registry.reqDurationHistogram.LastLabelValues)
+			assert.Less(t, int64(registry.reqDurationHistogram.LastDuration), int64(50*time.Millisecond))
+
+			assert.Equal(t, test.expectedShortCircuits, registry.shortCircuitsCounter.CounterValue)
+			assert.Equal(t, test.expectedShortCircuitsLabels, registry.shortCircuitsCounter.LastLabelValues)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"net"
 	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 )
 
 type recorder interface {
@@ -47,6 +49,9 @@ func (r *responseRecorder) getCode() int {
 // WriteHeader captures the status code for later retrieval.
 func (r *responseRecorder) WriteHeader(status int) {
 	r.ResponseWriter.WriteHeader(status)
+	if middlewares.IsInformational(status) {
+		return
+	}
 	r.statusCode = status
 }
 
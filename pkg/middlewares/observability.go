@@ -0,0 +1,17 @@
+package middlewares
+
+import "context"
+
+type metricsDisabledKey struct{}
+
+// WithoutMetrics returns a context marking the request as excluded from the metrics,
+// which is the case of the requests of the entry points whose metrics are disabled.
+func WithoutMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, metricsDisabledKey{}, true)
+}
+
+// MetricsDisabled returns whether the request is excluded from the metrics.
+func MetricsDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(metricsDisabledKey{}).(bool)
+	return disabled
+}
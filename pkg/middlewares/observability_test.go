@@ -0,0 +1,15 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithoutMetrics(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, MetricsDisabled(ctx))
+
+	assert.True(t, MetricsDisabled(WithoutMetrics(ctx)))
+}
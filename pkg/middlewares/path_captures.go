@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"context"
+	"regexp"
+)
+
+type pathCapturesKey struct{}
+
+// placeholderRegexp matches the ${name} placeholders referring to a named capture group.
+var placeholderRegexp = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// WithPathCaptures returns a context holding the named capture groups of the given path regular expression,
+// in addition to the ones captured by the previous middlewares.
+func WithPathCaptures(ctx context.Context, exp *regexp.Regexp, match []string) context.Context {
+	captures := make(map[string]string)
+	for name, value := range GetPathCaptures(ctx) {
+		captures[name] = value
+	}
+
+	added := false
+	for i, name := range exp.SubexpNames() {
+		if name != "" && i < len(match) {
+			captures[name] = match[i]
+			added = true
+		}
+	}
+
+	if !added {
+		return ctx
+	}
+
+	return context.WithValue(ctx, pathCapturesKey{}, captures)
+}
+
+// GetPathCaptures returns the named capture groups matched on the request path by the previous middlewares.
+func GetPathCaptures(ctx context.Context) map[string]string {
+	captures, _ := ctx.Value(pathCapturesKey{}).(map[string]string)
+	return captures
+}
+
+// ExpandPathCaptures replaces the ${name} placeholders of the given string by the corresponding named capture groups.
+// The placeholders which do not refer to a known capture group are left as is.
+func ExpandPathCaptures(ctx context.Context, s string) string {
+	captures := GetPathCaptures(ctx)
+	if len(captures) == 0 {
+		return s
+	}
+
+	return placeholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+		if value, ok := captures[placeholder[2:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPathCaptures(t *testing.T) {
+	exp := regexp.MustCompile(`^/(?P<tenant>[a-z]+)/(?P<version>v[0-9]+)/(.*)`)
+
+	ctx := WithPathCaptures(context.Background(), exp, exp.FindStringSubmatch("/foo/v1/bar"))
+
+	testCases := []struct {
+		desc     string
+		ctx      context.Context
+		value    string
+		expected string
+	}{
+		{
+			desc:     "no captures",
+			ctx:      context.Background(),
+			value:    "/api/${version}",
+			expected: "/api/${version}",
+		},
+		{
+			desc:     "known captures",
+			ctx:      ctx,
+			value:    "/${tenant}/api/${version}",
+			expected: "/foo/api/v1",
+		},
+		{
+			desc:     "unknown capture",
+			ctx:      ctx,
+			value:    "/api/${foo}/$version",
+			expected: "/api/${foo}/$version",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ExpandPathCaptures(test.ctx, test.value))
+		})
+	}
+}
+
+func TestWithPathCaptures(t *testing.T) {
+	first := regexp.MustCompile(`^/(?P<tenant>[a-z]+)/(?P<version>v[0-9]+)`)
+	second := regexp.MustCompile(`^/(?P<version>v[0-9]+)`)
+	unnamed := regexp.MustCompile(`^/([a-z]+)`)
+
+	ctx := WithPathCaptures(context.Background(), first, first.FindStringSubmatch("/foo/v1"))
+	assert.Equal(t, map[string]string{"tenant": "foo", "version": "v1"}, GetPathCaptures(ctx))
+
+	// The captures of the previous middlewares are kept, but can be overridden.
+	ctx = WithPathCaptures(ctx, second, second.FindStringSubmatch("/v2"))
+	assert.Equal(t, map[string]string{"tenant": "foo", "version": "v2"}, GetPathCaptures(ctx))
+
+	assert.Equal(t, ctx, WithPathCaptures(ctx, unnamed, unnamed.FindStringSubmatch("/foo")))
+}
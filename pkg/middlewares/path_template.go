@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"context"
+	"strings"
+)
+
+type pathTemplateKey struct{}
+
+// WithPathTemplate returns a context holding the path template matched by the request,
+// such as /users/{id}, whose variable patterns are removed to keep a low cardinality.
+// The path template already held by the context, if any, is kept.
+func WithPathTemplate(ctx context.Context, template string) context.Context {
+	if template == "" || GetPathTemplate(ctx) != "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, pathTemplateKey{}, cleanPathTemplate(template))
+}
+
+// GetPathTemplate returns the path template matched by the request, or an empty string if none was matched.
+func GetPathTemplate(ctx context.Context) string {
+	template, _ := ctx.Value(pathTemplateKey{}).(string)
+	return template
+}
+
+// cleanPathTemplate removes the patterns of the variables of a path template,
+// so that /users/{id:[0-9]+} becomes /users/{id}.
+func cleanPathTemplate(template string) string {
+	if !strings.Contains(template, ":") {
+		return template
+	}
+
+	var b strings.Builder
+	b.Grow(len(template))
+
+	depth := 0
+	inPattern := false
+	for _, r := range template {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+			if depth == 0 {
+				inPattern = false
+			}
+		case r == ':' && depth == 1:
+			inPattern = true
+		}
+
+		if !inPattern {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPathTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ctx      context.Context
+		template string
+		expected string
+	}{
+		{
+			desc:     "no template",
+			ctx:      context.Background(),
+			expected: "",
+		},
+		{
+			desc:     "static template",
+			ctx:      context.Background(),
+			template: "/api",
+			expected: "/api",
+		},
+		{
+			desc:     "variables",
+			ctx:      context.Background(),
+			template: "/users/{id}/orders/{order}",
+			expected: "/users/{id}/orders/{order}",
+		},
+		{
+			desc:     "variable patterns",
+			ctx:      context.Background(),
+			template: "/users/{id:[0-9]{1,5}}/orders/{order:[a-z]+}",
+			expected: "/users/{id}/orders/{order}",
+		},
+		{
+			desc:     "template already matched",
+			ctx:      WithPathTemplate(context.Background(), "/users/{id}"),
+			template: "/users",
+			expected: "/users/{id}",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := WithPathTemplate(test.ctx, test.template)
+			assert.Equal(t, test.expected, GetPathTemplate(ctx))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package pathtemplate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+const (
+	typeName = "PathTemplate"
+)
+
+// pathTemplate is a middleware matching the request path against custom path templates,
+// such as /users/{id:[0-9]+}, to report it in the metrics and the access logs with a low cardinality.
+// The first matching template takes precedence over the path template of the matched router rule.
+type pathTemplate struct {
+	next      http.Handler
+	templates *mux.Router
+}
+
+// New creates a path template middleware.
+func New(ctx context.Context, next http.Handler, templates []string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, "pathtemplate", typeName)).Debug("Creating middleware")
+
+	router := mux.NewRouter().SkipClean(true)
+	for _, template := range templates {
+		if err := router.Path(template).GetError(); err != nil {
+			return nil, fmt.Errorf("invalid path template %q: %w", template, err)
+		}
+	}
+
+	return &pathTemplate{
+		next:      next,
+		templates: router,
+	}, nil
+}
+
+// WrapHandler wraps the path template middleware into an alice.Constructor.
+func WrapHandler(ctx context.Context, templates []string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, templates)
+	}
+}
+
+func (p *pathTemplate) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var match mux.RouteMatch
+	if p.templates.Match(req, &match) {
+		if template, err := match.Route.GetPathTemplate(); err == nil {
+			req = req.WithContext(middlewares.WithPathTemplate(req.Context(), template))
+		}
+	}
+
+	p.next.ServeHTTP(rw, req)
+}
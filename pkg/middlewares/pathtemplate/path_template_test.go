@@ -0,0 +1,76 @@
+package pathtemplate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, []string{"/users/{id:[0-9]+}"})
+	require.NoError(t, err)
+
+	_, err = New(context.Background(), next, []string{"/users/{id"})
+	assert.Error(t, err)
+}
+
+func TestPathTemplate_ServeHTTP(t *testing.T) {
+	templates := []string{
+		"/users/{id:[0-9]+}",
+		"/users/{id}/orders/{order}",
+		"/users/{name}",
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "first matching template",
+			path:     "/users/42",
+			expected: "/users/{id}",
+		},
+		{
+			desc:     "variable patterns",
+			path:     "/users/john",
+			expected: "/users/{name}",
+		},
+		{
+			desc:     "several variables",
+			path:     "/users/42/orders/7",
+			expected: "/users/{id}/orders/{order}",
+		},
+		{
+			desc: "no matching template",
+			path: "/orders/7",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var template string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				template = middlewares.GetPathTemplate(req.Context())
+			})
+
+			handler, err := New(context.Background(), next, templates)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expected, template)
+		})
+	}
+}
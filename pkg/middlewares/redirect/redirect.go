@@ -81,17 +81,7 @@ type moveHandler struct {
 func (m *moveHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	rw.Header().Set("Location", m.location.String())
 
-	status := http.StatusFound
-	if req.Method != http.MethodGet {
-		status = http.StatusTemporaryRedirect
-	}
-
-	if m.permanent {
-		status = http.StatusMovedPermanently
-		if req.Method != http.MethodGet {
-			status = http.StatusPermanentRedirect
-		}
-	}
+	status := redirectStatus(req.Method, m.permanent)
 	rw.WriteHeader(status)
 	_, err := rw.Write([]byte(http.StatusText(status)))
 	if err != nil {
@@ -99,6 +89,22 @@ func (m *moveHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// redirectStatus returns the status code of a redirect,
+// the non-GET requests being redirected with a status code preserving their method.
+func redirectStatus(method string, permanent bool) int {
+	if permanent {
+		if method != http.MethodGet {
+			return http.StatusPermanentRedirect
+		}
+		return http.StatusMovedPermanently
+	}
+
+	if method != http.MethodGet {
+		return http.StatusTemporaryRedirect
+	}
+	return http.StatusFound
+}
+
 func rawURL(req *http.Request) string {
 	scheme := "http"
 	host := req.Host
@@ -0,0 +1,87 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeRedirectsName = "Redirects"
+)
+
+// redirects redirects the requests whose URL is a source of a redirects file to its target.
+type redirects struct {
+	next      http.Handler
+	file      *redirectsFile
+	permanent bool
+	name      string
+}
+
+// NewRedirects creates a redirects middleware.
+func NewRedirects(ctx context.Context, next http.Handler, conf dynamic.Redirects, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeRedirectsName))
+	logger.Debug("Creating middleware")
+
+	if conf.File == "" {
+		return nil, errors.New("you must provide a redirects file")
+	}
+
+	file, err := getRedirectsFile(conf.File)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the redirects file %s: %w", conf.File, err)
+	}
+
+	return &redirects{
+		next:      next,
+		file:      file,
+		permanent: conf.Permanent,
+		name:      name,
+	}, nil
+}
+
+func (r *redirects) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *redirects) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	target, rest := r.file.lookup(strings.ToLower(host), req.URL.EscapedPath())
+	if target == nil {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	location := target.location
+	if target.prefix {
+		location = strings.Replace(location, "*", rest, 1)
+	}
+	if req.URL.RawQuery != "" && !strings.Contains(location, "?") {
+		location += "?" + req.URL.RawQuery
+	}
+
+	status := target.statusCode
+	if status == 0 {
+		status = redirectStatus(req.Method, r.permanent)
+	}
+
+	rw.Header().Set("Location", location)
+	rw.WriteHeader(status)
+	_, err := rw.Write([]byte(http.StatusText(status)))
+	if err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeRedirectsName)).Debugf("Error while writing the redirect response: %v", err)
+	}
+}
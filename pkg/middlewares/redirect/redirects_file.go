@@ -0,0 +1,329 @@
+package redirect
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// redirectsFileCheckInterval is the minimum interval between two checks of a redirects file modification.
+var redirectsFileCheckInterval = 5 * time.Second
+
+var (
+	redirectsFilesMu sync.Mutex
+	// redirectsFiles are shared by all the middlewares using the same file,
+	// so that they are not loaded again each time the configuration is reloaded.
+	redirectsFiles = make(map[string]*redirectsFile)
+)
+
+// redirectTarget is the target of a redirect source.
+type redirectTarget struct {
+	// location is the URL the requests are redirected to,
+	// in which the first * is replaced by the rest of the path for the prefix sources.
+	location string
+	// statusCode is the status code of the redirect, 0 to choose it from the method and the permanent option.
+	statusCode int
+	// prefix tells whether the source matches all the subpaths of its path.
+	prefix bool
+}
+
+// redirectsNode is a node of the trie of the redirect sources, indexed by path segment.
+type redirectsNode struct {
+	children map[string]*redirectsNode
+	// exact is the target of the source ending at the node, and prefix the one of the source matching all its subpaths.
+	exact  *redirectTarget
+	prefix *redirectTarget
+}
+
+func (n *redirectsNode) child(segment string) *redirectsNode {
+	if n.children == nil {
+		n.children = make(map[string]*redirectsNode)
+	}
+
+	child, ok := n.children[segment]
+	if !ok {
+		child = &redirectsNode{}
+		n.children[segment] = child
+	}
+
+	return child
+}
+
+// lookup returns the target of the longest source matching the path segments,
+// and the rest of the path not matched by a prefix source.
+// An exact source takes precedence over a prefix source ending at the same node.
+func (n *redirectsNode) lookup(segments []string, trailingSlash bool) (*redirectTarget, string) {
+	var match *redirectTarget
+	var rest string
+
+	node := n
+	for i, segment := range segments {
+		if node.prefix != nil {
+			match, rest = node.prefix, strings.Join(segments[i:], "/")
+			if trailingSlash {
+				rest += "/"
+			}
+		}
+
+		node = node.children[segment]
+		if node == nil {
+			return match, rest
+		}
+	}
+
+	if node.exact != nil {
+		return node.exact, ""
+	}
+
+	if node.prefix != nil {
+		return node.prefix, ""
+	}
+
+	return match, rest
+}
+
+// redirectsMap holds the redirect sources of a file, by host.
+type redirectsMap struct {
+	hosts map[string]*redirectsNode
+	// anyHost holds the sources without host, which match all the hosts.
+	anyHost *redirectsNode
+}
+
+// lookup returns the target of the longest source matching the host and the path,
+// the sources of the host taking precedence over the ones without host.
+func (m *redirectsMap) lookup(host, path string) (*redirectTarget, string) {
+	trailingSlash := len(path) > 1 && strings.HasSuffix(path, "/")
+	segments := splitPath(path)
+
+	if root, ok := m.hosts[host]; ok {
+		if target, rest := root.lookup(segments, trailingSlash); target != nil {
+			return target, rest
+		}
+	}
+
+	return m.anyHost.lookup(segments, trailingSlash)
+}
+
+// parseRedirects parses the lines of a redirects file.
+// Each line holds a source, its target, and optionally the status code of the redirect, separated by spaces.
+// The empty lines and the ones starting with # are ignored.
+func parseRedirects(lines []string) (*redirectsMap, error) {
+	m := &redirectsMap{
+		hosts:   make(map[string]*redirectsNode),
+		anyHost: &redirectsNode{},
+	}
+
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := m.add(strings.Fields(line)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *redirectsMap) add(fields []string) error {
+	if len(fields) < 2 || len(fields) > 3 {
+		return fmt.Errorf("expected a source, a target and an optional status code, got %d fields", len(fields))
+	}
+
+	target := &redirectTarget{location: fields[1]}
+
+	if len(fields) == 3 {
+		statusCode, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("invalid status code %q", fields[2])
+		}
+
+		switch statusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return fmt.Errorf("invalid redirect status code %d", statusCode)
+		}
+
+		target.statusCode = statusCode
+	}
+
+	source := fields[0]
+
+	root := m.anyHost
+	if !strings.HasPrefix(source, "/") {
+		host := source
+		source = "/"
+
+		if i := strings.Index(host, "/"); i >= 0 {
+			host, source = host[:i], host[i:]
+		}
+
+		if host == "" {
+			return fmt.Errorf("invalid source %q", fields[0])
+		}
+
+		host = strings.ToLower(host)
+
+		var ok bool
+		if root, ok = m.hosts[host]; !ok {
+			root = &redirectsNode{}
+			m.hosts[host] = root
+		}
+	}
+
+	prefix := source == "/*" || strings.HasSuffix(source, "/*")
+	if prefix {
+		source = strings.TrimSuffix(source, "*")
+		target.prefix = true
+	}
+
+	node := root
+	for _, segment := range splitPath(source) {
+		node = node.child(segment)
+	}
+
+	if prefix {
+		if node.prefix != nil {
+			return fmt.Errorf("duplicate source %q", fields[0])
+		}
+		node.prefix = target
+
+		return nil
+	}
+
+	if node.exact != nil {
+		return fmt.Errorf("duplicate source %q", fields[0])
+	}
+	node.exact = target
+
+	return nil
+}
+
+// splitPath returns the segments of a path, ignoring the leading and trailing slashes.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// redirectsFile is a redirects file, which is reloaded when it is modified.
+type redirectsFile struct {
+	path string
+
+	mu        sync.RWMutex
+	redirects *redirectsMap
+	modTime   time.Time
+	checkedAt time.Time
+	checking  int32
+}
+
+// getRedirectsFile returns the redirects file of the given path, loading it if needed.
+func getRedirectsFile(path string) (*redirectsFile, error) {
+	redirectsFilesMu.Lock()
+	defer redirectsFilesMu.Unlock()
+
+	if f, ok := redirectsFiles[path]; ok {
+		return f, nil
+	}
+
+	f := &redirectsFile{path: path}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	f.checkedAt = time.Now()
+	redirectsFiles[path] = f
+
+	return f, nil
+}
+
+func (f *redirectsFile) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.RLock()
+	unchanged := f.redirects != nil && info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	redirects, err := parseRedirects(lines)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.redirects = redirects
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfNeeded reloads the redirects in the background if the file has been modified.
+// The file is checked at most once per redirectsFileCheckInterval.
+func (f *redirectsFile) reloadIfNeeded() {
+	f.mu.RLock()
+	checkedAt := f.checkedAt
+	f.mu.RUnlock()
+
+	if time.Since(checkedAt) < redirectsFileCheckInterval || !atomic.CompareAndSwapInt32(&f.checking, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&f.checking, 0)
+
+		if err := f.load(); err != nil {
+			// The previous redirects are kept.
+			log.WithoutContext().Errorf("Unable to reload the redirects file %s: %v", f.path, err)
+		}
+
+		f.mu.Lock()
+		f.checkedAt = time.Now()
+		f.mu.Unlock()
+	}()
+}
+
+func (f *redirectsFile) lookup(host, path string) (*redirectTarget, string) {
+	f.reloadIfNeeded()
+
+	f.mu.RLock()
+	redirects := f.redirects
+	f.mu.RUnlock()
+
+	return redirects.lookup(host, path)
+}
@@ -0,0 +1,238 @@
+package redirect
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const redirectsContent = `
+# Site migration
+example.com/old-page        https://example.com/new-page
+example.com/blog/*          https://blog.example.com/*
+example.com/blog/archive    https://archive.example.com/ 308
+example.com/docs/*          https://docs.example.com/v2/*?lang=en
+/legacy/*                   /new/*
+/legacy/kept                https://example.org/kept 303
+foo.com/                    https://bar.com/
+`
+
+func TestRedirects(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		permanent        bool
+		method           string
+		url              string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			desc:             "exact source",
+			url:              "http://example.com/old-page",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/new-page",
+		},
+		{
+			desc:             "exact source with a trailing slash",
+			url:              "http://example.com/old-page/",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/new-page",
+		},
+		{
+			desc:             "host with port and upper case",
+			url:              "http://EXAMPLE.com:8080/old-page",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/new-page",
+		},
+		{
+			desc:             "prefix source",
+			url:              "http://example.com/blog/2020/01/post/",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://blog.example.com/2020/01/post/",
+		},
+		{
+			desc:             "prefix source path",
+			url:              "http://example.com/blog",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://blog.example.com/",
+		},
+		{
+			desc:             "exact source taking precedence over the prefix one",
+			url:              "http://example.com/blog/archive",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://archive.example.com/",
+		},
+		{
+			desc:             "query preserved",
+			url:              "http://example.com/old-page?foo=bar",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/new-page?foo=bar",
+		},
+		{
+			desc:             "query of the target",
+			url:              "http://example.com/docs/install?foo=bar",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://docs.example.com/v2/install?lang=en",
+		},
+		{
+			desc:             "source without host",
+			url:              "http://other.com/legacy/foo/bar",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "/new/foo/bar",
+		},
+		{
+			desc:             "source without host and status code",
+			url:              "http://example.com/legacy/kept",
+			expectedStatus:   http.StatusSeeOther,
+			expectedLocation: "https://example.org/kept",
+		},
+		{
+			desc:             "root source",
+			url:              "http://foo.com",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://bar.com/",
+		},
+		{
+			desc:           "root source not matching the subpaths",
+			url:            "http://foo.com/foo",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "no source",
+			url:            "http://example.com/new-page",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:             "permanent",
+			permanent:        true,
+			url:              "http://example.com/old-page",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "https://example.com/new-page",
+		},
+		{
+			desc:             "permanent with method preserved",
+			permanent:        true,
+			method:           http.MethodPost,
+			url:              "http://example.com/old-page",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://example.com/new-page",
+		},
+		{
+			desc:             "method preserved",
+			method:           http.MethodPost,
+			url:              "http://example.com/old-page",
+			expectedStatus:   http.StatusTemporaryRedirect,
+			expectedLocation: "https://example.com/new-page",
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "redirects.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte(redirectsContent), 0o600))
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewRedirects(context.Background(), next, dynamic.Redirects{File: path, Permanent: test.permanent}, "traefikTest")
+			require.NoError(t, err)
+
+			method := http.MethodGet
+			if test.method != "" {
+				method = test.method
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(method, test.url, nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedLocation, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestRedirects_invalid(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		content string
+	}{
+		{
+			desc:    "missing target",
+			content: "example.com/foo",
+		},
+		{
+			desc:    "too many fields",
+			content: "example.com/foo https://bar.com 301 foo",
+		},
+		{
+			desc:    "invalid status code",
+			content: "example.com/foo https://bar.com 200",
+		},
+		{
+			desc:    "duplicate source",
+			content: "example.com/foo https://bar.com\nEXAMPLE.com/foo/ https://baz.com",
+		},
+		{
+			desc:    "duplicate prefix source",
+			content: "/foo/* https://bar.com/*\n/foo/* https://baz.com/*",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "redirects.txt")
+			require.NoError(t, ioutil.WriteFile(path, []byte(test.content), 0o600))
+
+			_, err := NewRedirects(context.Background(), http.NotFoundHandler(), dynamic.Redirects{File: path}, "traefikTest")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRedirects_reload(t *testing.T) {
+	checkInterval := redirectsFileCheckInterval
+	redirectsFileCheckInterval = 0
+	t.Cleanup(func() { redirectsFileCheckInterval = checkInterval })
+
+	path := filepath.Join(t.TempDir(), "redirects.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("/foo https://foo.com"), 0o600))
+
+	handler, err := NewRedirects(context.Background(), http.NotFoundHandler(), dynamic.Redirects{File: path}, "traefikTest")
+	require.NoError(t, err)
+
+	location := func() string {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil))
+		return recorder.Header().Get("Location")
+	}
+
+	assert.Equal(t, "https://foo.com", location())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("/foo https://bar.com"), 0o600))
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	assert.Eventually(t, func() bool { return location() == "https://bar.com" }, time.Second, 10*time.Millisecond)
+
+	// An invalid file keeps the previous redirects.
+	require.NoError(t, ioutil.WriteFile(path, []byte("/foo"), 0o600))
+	modTime = modTime.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "https://bar.com", location())
+}
@@ -20,9 +20,10 @@ const (
 
 // ReplacePath is a middleware used to replace the path of a URL request.
 type replacePath struct {
-	next http.Handler
-	path string
-	name string
+	next          http.Handler
+	path          string
+	disableHeader bool
+	name          string
 }
 
 // New creates a new replace path middleware.
@@ -30,9 +31,10 @@ func New(ctx context.Context, next http.Handler, config dynamic.ReplacePath, nam
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	return &replacePath{
-		next: next,
-		path: config.Path,
-		name: name,
+		next:          next,
+		path:          config.Path,
+		disableHeader: config.DisableHeader,
+		name:          name,
 	}, nil
 }
 
@@ -41,13 +43,16 @@ func (r *replacePath) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (r *replacePath) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	if req.URL.RawPath == "" {
-		req.Header.Add(ReplacedPathHeader, req.URL.Path)
-	} else {
-		req.Header.Add(ReplacedPathHeader, req.URL.RawPath)
+	if !r.disableHeader {
+		if req.URL.RawPath == "" {
+			SetReplacedPathHeader(req, req.URL.Path)
+		} else {
+			SetReplacedPathHeader(req, req.URL.RawPath)
+		}
 	}
 
-	req.URL.RawPath = r.path
+	// The path can refer to the named capture groups of the previous regex middlewares.
+	req.URL.RawPath = middlewares.ExpandPathCaptures(req.Context(), r.path)
 
 	var err error
 	req.URL.Path, err = url.PathUnescape(req.URL.RawPath)
@@ -61,3 +66,11 @@ func (r *replacePath) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	r.next.ServeHTTP(rw, req)
 }
+
+// SetReplacedPathHeader sets the header holding the path before its replacement,
+// unless a previous middleware already replaced it, so that the header always holds the path sent by the client.
+func SetReplacedPathHeader(req *http.Request, path string) {
+	if req.Header.Get(ReplacedPathHeader) == "" {
+		req.Header.Set(ReplacedPathHeader, path)
+	}
+}
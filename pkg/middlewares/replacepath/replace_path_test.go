@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 )
 
 func TestReplacePath(t *testing.T) {
@@ -60,6 +62,17 @@ func TestReplacePath(t *testing.T) {
 			expectedRawPath: "/foo%2Fbar",
 			expectedHeader:  "/path",
 		},
+		{
+			desc: "header disabled",
+			path: "/example",
+			config: dynamic.ReplacePath{
+				Path:          "/replacement-path",
+				DisableHeader: true,
+			},
+			expectedPath:    "/replacement-path",
+			expectedRawPath: "",
+			expectedHeader:  "",
+		},
 	}
 
 	for _, test := range testCases {
@@ -93,3 +106,27 @@ func TestReplacePath(t *testing.T) {
 		})
 	}
 }
+
+func TestReplacePath_pathCaptures(t *testing.T) {
+	exp := regexp.MustCompile(`^/(?P<tenant>[a-z]+)/`)
+
+	var actualPath, actualHeader string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualPath = r.URL.Path
+		actualHeader = r.Header.Get(ReplacedPathHeader)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.ReplacePath{Path: "/tenants/${tenant}/${foo}"}, "foo-replace-path")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo/bar", nil)
+	req = req.WithContext(middlewares.WithPathCaptures(req.Context(), exp, exp.FindStringSubmatch(req.URL.Path)))
+
+	// The path has already been replaced by a previous middleware.
+	req.Header.Set(ReplacedPathHeader, "/original")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "/tenants/foo/${foo}", actualPath)
+	assert.Equal(t, "/original", actualHeader)
+}
@@ -22,10 +22,11 @@ const (
 
 // ReplacePathRegex is a middleware used to replace the path of a URL request with a regular expression.
 type replacePathRegex struct {
-	next        http.Handler
-	regexp      *regexp.Regexp
-	replacement string
-	name        string
+	next          http.Handler
+	regexp        *regexp.Regexp
+	replacement   string
+	disableHeader bool
+	name          string
 }
 
 // New creates a new replace path regex middleware.
@@ -38,10 +39,11 @@ func New(ctx context.Context, next http.Handler, config dynamic.ReplacePathRegex
 	}
 
 	return &replacePathRegex{
-		regexp:      exp,
-		replacement: strings.TrimSpace(config.Replacement),
-		next:        next,
-		name:        name,
+		regexp:        exp,
+		replacement:   strings.TrimSpace(config.Replacement),
+		disableHeader: config.DisableHeader,
+		next:          next,
+		name:          name,
 	}, nil
 }
 
@@ -57,8 +59,18 @@ func (rp *replacePathRegex) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 		currentPath = req.URL.RawPath
 	}
 
-	if rp.regexp != nil && len(rp.replacement) > 0 && rp.regexp.MatchString(currentPath) {
-		req.Header.Add(replacepath.ReplacedPathHeader, currentPath)
+	if rp.regexp == nil || len(rp.replacement) == 0 {
+		rp.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if match := rp.regexp.FindStringSubmatch(currentPath); match != nil {
+		if !rp.disableHeader {
+			replacepath.SetReplacedPathHeader(req, currentPath)
+		}
+
+		// The named capture groups can be used by the next middlewares.
+		req = req.WithContext(middlewares.WithPathCaptures(req.Context(), rp.regexp, match))
 
 		req.URL.RawPath = rp.regexp.ReplaceAllString(currentPath, rp.replacement)
 
@@ -106,6 +106,17 @@ func TestReplacePathRegex(t *testing.T) {
 			expectedPath:    "/aaa/bbb",
 			expectedRawPath: "/aaa%2Fbbb",
 		},
+		{
+			desc: "named capture groups",
+			path: "/api/v1/users",
+			config: dynamic.ReplacePathRegex{
+				Replacement: "/${version}/${resource}",
+				Regex:       `^/api/(?P<version>v[0-9]+)/(?P<resource>.*)`,
+			},
+			expectedPath:    "/v1/users",
+			expectedRawPath: "/v1/users",
+			expectedHeader:  "/api/v1/users",
+		},
 	}
 
 	for _, test := range testCases {
@@ -148,3 +159,46 @@ func TestReplacePathRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestReplacePathRegex_chain(t *testing.T) {
+	var actualPath, actualHeader string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualPath = r.URL.Path
+		actualHeader = r.Header.Get(replacepath.ReplacedPathHeader)
+	})
+
+	// The second middleware uses the groups captured by the first one, and keeps the original path in the header.
+	replacePath, err := replacepath.New(context.Background(), next, dynamic.ReplacePath{Path: "/tenants/${tenant}"}, "foo-replace-path")
+	require.NoError(t, err)
+
+	handler, err := New(context.Background(), replacePath, dynamic.ReplacePathRegex{
+		Regex:       `^/(?P<tenant>[a-z]+)/(.*)`,
+		Replacement: "/$2",
+	}, "foo-replace-path-regexp")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo/bar", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "/tenants/foo", actualPath)
+	assert.Equal(t, "/foo/bar", actualHeader)
+}
+
+func TestReplacePathRegex_headerDisabled(t *testing.T) {
+	var actualHeader []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualHeader = r.Header.Values(replacepath.ReplacedPathHeader)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.ReplacePathRegex{
+		Regex:         `^/foo`,
+		Replacement:   "/bar",
+		DisableHeader: true,
+	}, "foo-replace-path-regexp")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, actualHeader)
+}
@@ -0,0 +1,271 @@
+package requestdebug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestid"
+	"github.com/traefik/traefik/v2/pkg/signedtoken"
+)
+
+const typeName = "RequestDebug"
+
+// domain is the domain of the tokens of the debug header.
+const domain = "debug"
+
+type key struct{}
+
+// requestState is the debug state of a request, in its context.
+type requestState struct {
+	logger   *logrus.Entry
+	attempts int32
+}
+
+// Debugger logs the routing of the requests carrying a valid debug header to a dedicated debug log,
+// without enabling the debug level of the Traefik log.
+// The header value is a token of the debug domain, without fields, signed with the secret.
+type Debugger struct {
+	header   string
+	verifier *signedtoken.Verifier
+	now      func() time.Time
+
+	logger *logrus.Logger
+	file   *os.File
+}
+
+// New creates a debugger, writing the debug logs to its file.
+func New(config static.RequestDebug) (*Debugger, error) {
+	if config.Secret == "" {
+		return nil, errors.New("the secret is missing")
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.DebugLevel)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	var file *os.File
+	if config.FilePath != "" {
+		if err := os.MkdirAll(filepath.Dir(config.FilePath), 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create the directory of the debug log file: %w", err)
+		}
+
+		var err error
+		file, err = os.OpenFile(config.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the debug log file: %w", err)
+		}
+
+		logger.SetOutput(file)
+	} else {
+		logger.SetOutput(os.Stdout)
+	}
+
+	return &Debugger{
+		header:   config.Header,
+		verifier: signedtoken.NewVerifier(config.Secret, time.Duration(config.MaxAge)),
+		now:      time.Now,
+		logger:   logger,
+		file:     file,
+	}, nil
+}
+
+// WrapHandler wraps the debugger into an alice.Constructor.
+func (d *Debugger) WrapHandler(ctx context.Context, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		log.FromContext(middlewares.GetLoggerCtx(ctx, entryPointName, typeName)).Debug("Creating middleware")
+
+		return &handler{debugger: d, next: next, entryPointName: entryPointName}, nil
+	}
+}
+
+// Close closes the debug log file.
+func (d *Debugger) Close() {
+	if d.file == nil {
+		return
+	}
+
+	if err := d.file.Close(); err != nil {
+		log.WithoutContext().Errorf("Could not close the debug log file: %v", err)
+	}
+}
+
+// verify checks the signature and the expiration of a header value.
+func (d *Debugger) verify(value string) error {
+	_, err := d.verifier.Verify(d.now(), value, domain, 0)
+	return err
+}
+
+type handler struct {
+	debugger       *Debugger
+	next           http.Handler
+	entryPointName string
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	value := req.Header.Get(h.debugger.header)
+	if value == "" {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The header is meant for Traefik only.
+	req.Header.Del(h.debugger.header)
+
+	if err := h.debugger.verify(value); err != nil {
+		log.FromContext(req.Context()).Debugf("Ignoring the debug header of the request: %v", err)
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	fields := logrus.Fields{
+		log.EntryPointName: h.entryPointName,
+		"method":           req.Method,
+		"host":             req.Host,
+		"path":             req.URL.Path,
+		"remoteAddr":       req.RemoteAddr,
+	}
+	if id := requestid.Get(req); id != "" {
+		fields["requestId"] = id
+	}
+
+	state := &requestState{logger: h.debugger.logger.WithFields(fields)}
+	state.logger.Debug("Request received")
+
+	recorder := &responseRecorder{ResponseWriter: rw}
+	start := time.Now()
+
+	h.next.ServeHTTP(recorder, req.WithContext(context.WithValue(req.Context(), key{}, state)))
+
+	state.logger.WithFields(logrus.Fields{
+		"code":     recorder.getCode(),
+		"duration": time.Since(start).String(),
+	}).Debug("Response sent")
+}
+
+// getState returns the debug state of a request, which is nil when the request is not debugged.
+func getState(req *http.Request) *requestState {
+	state, _ := req.Context().Value(key{}).(*requestState)
+	return state
+}
+
+// WrapRouterHandler returns an alice.Constructor logging the router matched by the debugged requests.
+func WrapRouterHandler(routerName, serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if state := getState(req); state != nil {
+				state.logger.WithFields(logrus.Fields{
+					log.RouterName:  routerName,
+					log.ServiceName: serviceName,
+				}).Debug("Request matched the router")
+			}
+
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+}
+
+// WrapMiddlewareHandler wraps the constructor of a middleware into an alice.Constructor
+// logging whether the middleware forwarded the debugged requests, or answered them itself.
+func WrapMiddlewareHandler(middlewareName string, constructor alice.Constructor) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		m := &middlewareDebug{middlewareName: middlewareName}
+
+		handler, err := constructor(&nextMarker{middleware: m, next: next})
+		if err != nil {
+			return nil, err
+		}
+
+		m.handler = handler
+
+		return m, nil
+	}
+}
+
+// middlewareDebug logs the decision of a middleware instance for the debugged requests.
+type middlewareDebug struct {
+	handler        http.Handler
+	middlewareName string
+}
+
+// middlewareCall records whether a middleware forwarded a request.
+type middlewareCall struct {
+	forwarded bool
+}
+
+func (m *middlewareDebug) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	state := getState(req)
+	if state == nil {
+		m.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := state.logger.WithField(log.MiddlewareName, m.middlewareName)
+	logger.Debug("Entering the middleware")
+
+	call := &middlewareCall{}
+	recorder := &responseRecorder{ResponseWriter: rw}
+
+	m.handler.ServeHTTP(recorder, req.WithContext(context.WithValue(req.Context(), m, call)))
+
+	if !call.forwarded {
+		logger.WithField("code", recorder.getCode()).Debug("The middleware answered the request")
+	}
+}
+
+// nextMarker records that a middleware forwarded a request to the next handler.
+type nextMarker struct {
+	middleware *middlewareDebug
+	next       http.Handler
+}
+
+func (n *nextMarker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The call is missing when the middleware forwards a request it did not receive.
+	if call, ok := req.Context().Value(n.middleware).(*middlewareCall); ok {
+		call.forwarded = true
+
+		if state := getState(req); state != nil {
+			state.logger.WithField(log.MiddlewareName, n.middleware.middlewareName).Debug("The middleware forwarded the request")
+		}
+	}
+
+	n.next.ServeHTTP(rw, req)
+}
+
+// NewUpstream wraps the forwarder of a service, to log the attempts of the debugged requests to reach its servers.
+func NewUpstream(next http.Handler, serviceName string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		state := getState(req)
+		if state == nil {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		logger := state.logger.WithFields(logrus.Fields{
+			log.ServiceName: serviceName,
+			"serverURL":     req.URL.Scheme + "://" + req.URL.Host,
+			"attempt":       atomic.AddInt32(&state.attempts, 1),
+		})
+		logger.Debug("Forwarding the request to the server")
+
+		recorder := &responseRecorder{ResponseWriter: rw}
+		start := time.Now()
+
+		next.ServeHTTP(recorder, req)
+
+		logger.WithFields(logrus.Fields{
+			"code":     recorder.getCode(),
+			"duration": time.Since(start).String(),
+		}).Debug("Response received from the server")
+	})
+}
@@ -0,0 +1,161 @@
+package requestdebug
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/signedtoken"
+)
+
+func TestDebugger(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sign := func(expiresAt time.Time) string {
+		return signedtoken.Sign("secret", domain, expiresAt)
+	}
+
+	testCases := []struct {
+		desc     string
+		header   string
+		expected []string
+	}{
+		{
+			desc: "no header",
+		},
+		{
+			desc:   "valid header",
+			header: sign(now.Add(time.Minute)),
+			expected: []string{
+				"Request received",
+				"Request matched the router",
+				"Entering the middleware",
+				"The middleware forwarded the request",
+				"Forwarding the request to the server",
+				"Response received from the server",
+				"Response sent",
+			},
+		},
+		{
+			desc:   "invalid signature",
+			header: strconv.FormatInt(now.Add(time.Minute).Unix(), 10) + ";deadbeef",
+		},
+		{
+			desc:   "malformed header",
+			header: "foo",
+		},
+		{
+			desc:   "expired header",
+			header: sign(now.Add(-time.Second)),
+		},
+		{
+			desc:   "expiration beyond the max age",
+			header: sign(now.Add(time.Hour)),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			debugger, err := New(static.RequestDebug{Header: "X-Traefik-Debug", Secret: "secret", MaxAge: ptypes.Duration(5 * time.Minute)})
+			require.NoError(t, err)
+
+			var output bytes.Buffer
+			debugger.logger.SetOutput(&output)
+			debugger.now = func() time.Time { return now }
+
+			var forwardedHeader string
+			upstream := NewUpstream(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwardedHeader = req.Header.Get("X-Traefik-Debug")
+				rw.WriteHeader(http.StatusTeapot)
+			}), "service")
+
+			forwarding := func(next http.Handler) (http.Handler, error) {
+				return next, nil
+			}
+
+			handler, err := alice.New(
+				debugger.WrapHandler(context.Background(), "web"),
+				WrapRouterHandler("router", "service"),
+				WrapMiddlewareHandler("forwarding", forwarding),
+			).Then(upstream)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+			if test.header != "" {
+				req.Header.Set("X-Traefik-Debug", test.header)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusTeapot, recorder.Code)
+			assert.Empty(t, forwardedHeader)
+
+			var messages []string
+			scanner := bufio.NewScanner(&output)
+			for scanner.Scan() {
+				var entry map[string]interface{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+
+				assert.Equal(t, "web", entry["entryPointName"])
+				assert.Equal(t, "/foo", entry["path"])
+
+				messages = append(messages, entry["msg"].(string))
+
+				if entry["msg"] == "Response sent" {
+					assert.Equal(t, float64(http.StatusTeapot), entry["code"])
+				}
+			}
+
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+}
+
+func TestWrapMiddlewareHandler_answered(t *testing.T) {
+	debugger, err := New(static.RequestDebug{Header: "X-Traefik-Debug", Secret: "secret", MaxAge: ptypes.Duration(5 * time.Minute)})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	debugger.logger.SetOutput(&output)
+
+	answering := func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusForbidden)
+		}), nil
+	}
+
+	handler, err := alice.New(
+		debugger.WrapHandler(context.Background(), "web"),
+		WrapMiddlewareHandler("answering", answering),
+	).Then(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+	req.Header.Set("X-Traefik-Debug", signedtoken.Sign("secret", domain, time.Now().Add(time.Minute)))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Contains(t, output.String(), `"code":403,"entryPointName":"web","host":"localhost","level":"debug","method":"GET","middlewareName":"answering","msg":"The middleware answered the request"`)
+}
+
+func TestNew_missingSecret(t *testing.T) {
+	_, err := New(static.RequestDebug{Header: "X-Traefik-Debug"})
+	assert.Error(t, err)
+}
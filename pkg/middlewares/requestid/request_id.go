@@ -0,0 +1,160 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	typeName = "RequestID"
+
+	// defaultHeaderName is the header read when the request ID is not in the context of the request.
+	defaultHeaderName = "X-Request-Id"
+)
+
+type key struct{}
+
+// requestID is a middleware that identifies each request,
+// with the ID received in the header of the request, or with a generated one.
+// The ID is forwarded to the servers in the same header, and recorded in the access logs and the traces.
+type requestID struct {
+	headerName string
+	generate   func() (string, error)
+	next       http.Handler
+}
+
+// New creates a new request ID middleware.
+func New(ctx context.Context, next http.Handler, config types.RequestID) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, "requestid", typeName)).Debug("Creating middleware")
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = defaultHeaderName
+	}
+
+	var generate func() (string, error)
+	switch config.Generator {
+	case types.RequestIDGeneratorUUID, "":
+		generate = newUUID
+	case types.RequestIDGeneratorULID:
+		generate = newULID
+	default:
+		return nil, fmt.Errorf("unknown request ID generator: %s", config.Generator)
+	}
+
+	return &requestID{
+		headerName: http.CanonicalHeaderKey(headerName),
+		generate:   generate,
+		next:       next,
+	}, nil
+}
+
+// WrapHandler wraps the request ID middleware into an alice.Constructor.
+func WrapHandler(ctx context.Context, config types.RequestID) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, config)
+	}
+}
+
+func (r *requestID) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	id := req.Header.Get(r.headerName)
+	if id == "" {
+		var err error
+		id, err = r.generate()
+		if err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), "requestid", typeName)).
+				Errorf("Unable to generate a request ID: %v", err)
+			r.next.ServeHTTP(rw, req)
+			return
+		}
+
+		req.Header.Set(r.headerName, id)
+	}
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.RequestID] = id
+	}
+
+	if span := tracing.GetSpan(req); span != nil {
+		span.SetTag("request.id", id)
+	}
+
+	r.next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), key{}, id)))
+}
+
+// Get returns the ID of the request.
+// Without request ID middleware on the entry point of the request, the ID is read from the X-Request-Id header.
+func Get(req *http.Request) string {
+	if id, ok := req.Context().Value(key{}).(string); ok {
+		return id
+	}
+
+	return req.Header.Get(defaultHeaderName)
+}
+
+// newUUID generates a random (version 4) UUID.
+func newUUID() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", err
+	}
+
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], uuid[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], uuid[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], uuid[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], uuid[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], uuid[10:])
+
+	return string(buf[:]), nil
+}
+
+// crockfordAlphabet is the Base32 alphabet of the ULIDs.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID generates a ULID (https://github.com/ulid/spec): a millisecond timestamp followed by 80 random bits,
+// which makes the IDs sortable by creation time.
+func newULID() (string, error) {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixNano()/int64(time.Millisecond))<<16)
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", err
+	}
+
+	return encodeULID(id), nil
+}
+
+// encodeULID encodes the 128 bits of a ULID in 26 characters, 5 bits per character, starting with the 2 leading padding bits.
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
@@ -0,0 +1,124 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRegexp = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         types.RequestID
+		headers        map[string]string
+		expectedHeader string
+		expectedID     *regexp.Regexp
+	}{
+		{
+			desc:           "generated UUID",
+			config:         types.RequestID{Generator: types.RequestIDGeneratorUUID},
+			expectedHeader: "X-Request-Id",
+			expectedID:     uuidRegexp,
+		},
+		{
+			desc:           "generated ULID",
+			config:         types.RequestID{Generator: types.RequestIDGeneratorULID},
+			expectedHeader: "X-Request-Id",
+			expectedID:     ulidRegexp,
+		},
+		{
+			desc:           "received ID",
+			config:         types.RequestID{Generator: types.RequestIDGeneratorUUID},
+			headers:        map[string]string{"X-Request-Id": "foo"},
+			expectedHeader: "X-Request-Id",
+			expectedID:     regexp.MustCompile(`^foo$`),
+		},
+		{
+			desc:           "custom header",
+			config:         types.RequestID{HeaderName: "x-correlation-id"},
+			headers:        map[string]string{"X-Request-Id": "foo"},
+			expectedHeader: "X-Correlation-Id",
+			expectedID:     uuidRegexp,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var id, header string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				id = Get(req)
+				header = req.Header.Get(test.expectedHeader)
+			})
+
+			handler, err := New(context.Background(), next, test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Regexp(t, test.expectedID, id)
+			assert.Equal(t, id, header)
+			assert.Equal(t, id, logData.Core[accesslog.RequestID])
+		})
+	}
+}
+
+func TestRequestID_invalidGenerator(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), types.RequestID{Generator: "foo"})
+	assert.Error(t, err)
+}
+
+func TestGet_withoutMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("X-Request-Id", "foo")
+
+	assert.Equal(t, "foo", Get(req))
+}
+
+func TestNewULID(t *testing.T) {
+	before, err := newULID()
+	require.NoError(t, err)
+
+	time.Sleep(2 * time.Millisecond)
+
+	after, err := newULID()
+	require.NoError(t, err)
+
+	assert.True(t, before < after, "%s must be sorted before %s", before, after)
+}
+
+func TestEncodeULID(t *testing.T) {
+	var id [16]byte
+	assert.Equal(t, "00000000000000000000000000", encodeULID(id))
+
+	for i := range id {
+		id[i] = 0xff
+	}
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(id))
+
+	id = [16]byte{15: 0x21}
+	assert.Equal(t, "00000000000000000000000011", encodeULID(id))
+}
@@ -0,0 +1,322 @@
+package requesttimeout
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RequestTimeout"
+)
+
+// checkInterval is the interval at which the request body progression is checked.
+var checkInterval = 100 * time.Millisecond
+
+// errTimeout is returned when reading a request body which has been timed out.
+var errTimeout = errors.New("request timeout")
+
+// requestTimeout is a middleware that answers with a 408 Request Timeout
+// when the request takes too long to be handled, or when its body is received too slowly.
+type requestTimeout struct {
+	next            http.Handler
+	name            string
+	deadline        time.Duration
+	bodyIdleTimeout time.Duration
+	bodyMinRate     int64
+	gracePeriod     time.Duration
+}
+
+// New creates a request timeout middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestTimeout, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.Deadline < 0 || config.BodyIdleTimeout < 0 || config.BodyMinRate < 0 || config.BodyMinRateGracePeriod < 0 {
+		return nil, errors.New("timeouts and rate must be positive")
+	}
+
+	if config.Deadline == 0 && config.BodyIdleTimeout == 0 && config.BodyMinRate == 0 {
+		return nil, errors.New("at least one of deadline, bodyIdleTimeout or bodyMinRate must be defined")
+	}
+
+	return &requestTimeout{
+		next:            next,
+		name:            name,
+		deadline:        time.Duration(config.Deadline),
+		bodyIdleTimeout: time.Duration(config.BodyIdleTimeout),
+		bodyMinRate:     config.BodyMinRate,
+		gracePeriod:     time.Duration(config.BodyMinRateGracePeriod),
+	}, nil
+}
+
+func (r *requestTimeout) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *requestTimeout) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	state := &timeoutState{cancel: cancel}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body := &bodyReader{ReadCloser: req.Body, state: state, lastRead: time.Now().UnixNano()}
+		req.Body = body
+
+		state.interrupt = func() {
+			if !body.isDone() {
+				interruptBody(req, body)
+			}
+		}
+
+		if r.bodyIdleTimeout > 0 || r.bodyMinRate > 0 {
+			done := make(chan struct{})
+			defer close(done)
+
+			go r.watchBody(body, state, done)
+		}
+	}
+
+	if r.deadline > 0 {
+		timer := time.AfterFunc(r.deadline, func() { state.timeout(fmt.Sprintf("deadline of %s exceeded", r.deadline)) })
+		defer timer.Stop()
+	}
+
+	trw := &responseWriter{rw: rw, state: state}
+	r.next.ServeHTTP(trw, req.WithContext(ctx))
+
+	if reason := state.reason(); reason != "" && !trw.headerWritten {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+		logger.Debugf("Request timeout: %s", reason)
+		tracing.SetErrorWithEvent(req, "request timeout: %s", reason)
+
+		trw.writeTimeout()
+	}
+}
+
+// watchBody checks the request body progression until it has been entirely read, or until done is closed.
+func (r *requestTimeout) watchBody(body *bodyReader, state *timeoutState, done <-chan struct{}) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case now := <-ticker.C:
+			if body.isDone() {
+				return
+			}
+
+			if r.bodyIdleTimeout > 0 {
+				idle := now.Sub(time.Unix(0, atomic.LoadInt64(&body.lastRead)))
+				if idle > r.bodyIdleTimeout {
+					state.timeout(fmt.Sprintf("no request body received for %s", idle))
+					return
+				}
+			}
+
+			elapsed := now.Sub(start)
+			if r.bodyMinRate > 0 && elapsed > r.gracePeriod {
+				rate := float64(atomic.LoadInt64(&body.read)) / elapsed.Seconds()
+				if rate < float64(r.bodyMinRate) {
+					state.timeout(fmt.Sprintf("request body received at %.0f bytes/s, below the minimum of %d bytes/s", rate, r.bodyMinRate))
+					return
+				}
+			}
+		}
+	}
+}
+
+// interruptBody unblocks the pending reads of the request body,
+// which canceling the request context does not do.
+// For HTTP/1, the read deadline of the client connection is expired,
+// which is fine as the connection is closed after the 408 response.
+// For HTTP/2, only the stream of the request is closed.
+func interruptBody(req *http.Request, body *bodyReader) {
+	if req.ProtoMajor < 2 {
+		if conn := middlewares.GetConn(req.Context()); conn != nil {
+			if err := conn.SetReadDeadline(time.Now()); err != nil {
+				log.WithoutContext().Debugf("Error while interrupting the request body read: %v", err)
+			}
+			return
+		}
+	}
+
+	if err := body.ReadCloser.Close(); err != nil {
+		log.WithoutContext().Debugf("Error while interrupting the request body read: %v", err)
+	}
+}
+
+// timeoutState holds whether, and why, the request has been timed out.
+type timeoutState struct {
+	mu        sync.RWMutex
+	why       string
+	cancel    context.CancelFunc
+	interrupt func()
+}
+
+func (s *timeoutState) timeout(reason string) {
+	s.mu.Lock()
+	first := s.why == ""
+	if first {
+		s.why = reason
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+
+	if first && s.interrupt != nil {
+		s.interrupt()
+	}
+}
+
+func (s *timeoutState) reason() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.why
+}
+
+type bodyReader struct {
+	io.ReadCloser
+
+	state    *timeoutState
+	read     int64
+	lastRead int64
+	done     int32
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	if b.state.reason() != "" {
+		return 0, errTimeout
+	}
+
+	n, err := b.ReadCloser.Read(p)
+
+	atomic.AddInt64(&b.read, int64(n))
+	atomic.StoreInt64(&b.lastRead, time.Now().UnixNano())
+
+	if err != nil {
+		atomic.StoreInt32(&b.done, 1)
+	}
+
+	return n, err
+}
+
+func (b *bodyReader) Close() error {
+	atomic.StoreInt32(&b.done, 1)
+	return b.ReadCloser.Close()
+}
+
+func (b *bodyReader) isDone() bool {
+	return atomic.LoadInt32(&b.done) == 1
+}
+
+// responseWriter replaces the response of the next handler by a 408 Request Timeout,
+// as long as the request has been timed out before the response headers are written.
+type responseWriter struct {
+	rw            http.ResponseWriter
+	state         *timeoutState
+	headerWritten bool
+	timedOut      bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.headerWritten {
+		return
+	}
+
+	// The informational responses precede the final one, which can still be replaced.
+	if middlewares.IsInformational(code) {
+		if w.state.reason() == "" {
+			w.rw.WriteHeader(code)
+		}
+		return
+	}
+
+	if w.state.reason() != "" {
+		w.writeTimeout()
+		return
+	}
+
+	w.headerWritten = true
+	w.rw.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.timedOut {
+		return len(b), nil
+	}
+
+	return w.rw.Write(b)
+}
+
+func (w *responseWriter) writeTimeout() {
+	w.headerWritten = true
+	w.timedOut = true
+
+	// The response of the next handler is discarded, so are its headers.
+	for k := range w.rw.Header() {
+		w.rw.Header().Del(k)
+	}
+
+	// The request body may not have been fully read, so the connection cannot be reused.
+	w.rw.Header().Set("Connection", "close")
+
+	w.rw.WriteHeader(http.StatusRequestTimeout)
+	_, err := w.rw.Write([]byte(http.StatusText(http.StatusRequestTimeout)))
+	if err != nil {
+		log.WithoutContext().Debugf("Error while writing request timeout response: %v", err)
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.rw.(http.Hijacker); ok {
+		w.headerWritten = true
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", w.rw)
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if !w.headerWritten {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.timedOut {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,252 @@
+package requesttimeout
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.RequestTimeout
+		expectedError bool
+	}{
+		{
+			desc:          "empty configuration",
+			config:        dynamic.RequestTimeout{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative deadline",
+			config:        dynamic.RequestTimeout{Deadline: ptypes.Duration(-time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:   "deadline",
+			config: dynamic.RequestTimeout{Deadline: ptypes.Duration(time.Second)},
+		},
+		{
+			desc:   "body min rate",
+			config: dynamic.RequestTimeout{BodyMinRate: 1024},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestRequestTimeout_deadline(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		delay          time.Duration
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "response sent before the deadline",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "OK",
+		},
+		{
+			desc:           "deadline exceeded",
+			delay:          time.Second,
+			expectedStatus: http.StatusRequestTimeout,
+			expectedBody:   http.StatusText(http.StatusRequestTimeout),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				select {
+				case <-time.After(test.delay):
+				case <-req.Context().Done():
+					// Mimics the reverse proxy behavior on a canceled request.
+					rw.WriteHeader(499)
+					return
+				}
+
+				rw.Header().Set("X-Foo", "bar")
+				_, _ = rw.Write([]byte("OK"))
+			})
+
+			config := dynamic.RequestTimeout{Deadline: ptypes.Duration(100 * time.Millisecond)}
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestRequestTimeout_body(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.RequestTimeout
+		body           func(w *io.PipeWriter)
+		expectedStatus int
+	}{
+		{
+			desc:   "body received in time",
+			config: dynamic.RequestTimeout{BodyIdleTimeout: ptypes.Duration(500 * time.Millisecond)},
+			body: func(w *io.PipeWriter) {
+				_, _ = w.Write([]byte("foo"))
+				_ = w.Close()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "body idle timeout",
+			config: dynamic.RequestTimeout{BodyIdleTimeout: ptypes.Duration(200 * time.Millisecond)},
+			body: func(w *io.PipeWriter) {
+				_, _ = w.Write([]byte("foo"))
+				time.Sleep(time.Second)
+				_ = w.Close()
+			},
+			expectedStatus: http.StatusRequestTimeout,
+		},
+		{
+			desc: "body received too slowly",
+			config: dynamic.RequestTimeout{
+				BodyMinRate:            1024,
+				BodyMinRateGracePeriod: ptypes.Duration(200 * time.Millisecond),
+			},
+			body: func(w *io.PipeWriter) {
+				for i := 0; i < 10; i++ {
+					_, _ = w.Write([]byte("foo"))
+					time.Sleep(100 * time.Millisecond)
+				}
+				_ = w.Close()
+			},
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if _, err := ioutil.ReadAll(req.Body); err != nil {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			pr, pw := io.Pipe()
+			go test.body(pw)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", ioutil.NopCloser(pr))
+
+			recorder := httptest.NewRecorder()
+
+			done := make(chan struct{})
+			go func() {
+				handler.ServeHTTP(recorder, req)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("request not timed out")
+			}
+
+			// Unblocks the body writer, if any.
+			_ = pr.Close()
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusRequestTimeout {
+				assert.Equal(t, "close", recorder.Header().Get("Connection"))
+				assert.True(t, strings.HasPrefix(recorder.Body.String(), http.StatusText(http.StatusRequestTimeout)))
+			}
+		})
+	}
+}
+
+func TestRequestTimeout_stalledBody(t *testing.T) {
+	handlerDone := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer close(handlerDone)
+
+		if _, err := ioutil.ReadAll(req.Body); err != nil {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	config := dynamic.RequestTimeout{BodyIdleTimeout: ptypes.Duration(200 * time.Millisecond)}
+	handler, err := New(context.Background(), next, config, "traefikTest")
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ConnContext = middlewares.WithConn
+	server.Start()
+	t.Cleanup(server.Close)
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The body is never completed, and the connection is kept open by the client.
+	_, err = fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nContent-Length: 100\r\n\r\nfoo")
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusRequestTimeout, resp.StatusCode)
+
+	select {
+	case <-handlerDone:
+	case <-time.After(time.Second):
+		t.Fatal("handler still blocked on the request body")
+	}
+}
@@ -102,7 +102,7 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			}
 			newCtx := httptrace.WithClientTrace(req.Context(), trace)
 
-			r.next.ServeHTTP(retryResponseWriter, req.WithContext(newCtx))
+			r.serveAttempt(retryResponseWriter, req.WithContext(newCtx), attempts)
 
 			if !retryResponseWriter.ShouldRetry() {
 				return
@@ -123,6 +123,26 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// serveAttempt forwards an attempt of the request, in its own span when the request is traced,
+// to break down the time spent on each attempt.
+func (r *retry) serveAttempt(rw responseWriter, req *http.Request, attempt int) {
+	if _, err := tracing.FromContext(req.Context()); err != nil {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	span, req, finish := tracing.StartSpan(req, "retry attempt", tracing.SpanKindNoneEnum)
+	defer finish()
+
+	span.SetTag("retry.attempt", attempt)
+
+	r.next.ServeHTTP(rw, req)
+
+	if rw.ShouldRetry() {
+		span.LogKV("event", "Attempt failed, retrying")
+	}
+}
+
 func (r *retry) newBackOff() nexter {
 	if r.attempts < 2 || r.initialInterval <= 0 {
 		return &backoff.ZeroBackOff{}
@@ -199,6 +219,13 @@ func (r *responseWriterWithoutCloseNotify) Write(buf []byte) (int, error) {
 }
 
 func (r *responseWriterWithoutCloseNotify) WriteHeader(code int) {
+	// The informational responses are sent by the server of the current attempt, and precede its final response.
+	if middlewares.IsInformational(code) {
+		r.DisableRetries()
+		middlewares.WriteInformational(r.responseWriter, code, r.Header())
+		return
+	}
+
 	if r.ShouldRetry() && code == http.StatusServiceUnavailable {
 		// We get a 503 HTTP Status Code when there is no backend server in the pool
 		// to which the request could be sent.  Also, note that r.ShouldRetry()
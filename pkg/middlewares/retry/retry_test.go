@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -12,12 +13,15 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/middlewares/emptybackendhandler"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
+	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/forward"
 	"github.com/vulcand/oxy/roundrobin"
 )
@@ -234,6 +238,52 @@ func TestMultipleRetriesShouldNotLooseHeaders(t *testing.T) {
 	}
 }
 
+func TestRetryTracing(t *testing.T) {
+	tracer := mocktracer.New()
+
+	tr, err := tracing.NewTracing("test", 0, &tracingBackendMock{tracer: tracer})
+	require.NoError(t, err)
+
+	attempt := 0
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempt++
+		if attempt < 2 {
+			return
+		}
+
+		httptrace.ContextClientTrace(req.Context()).WroteHeaders()
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, &countingRetryListener{}, "traefikTest")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://test", http.NoBody)
+	req = req.WithContext(tracing.WithTracing(req.Context(), tr))
+
+	retry.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.FinishedSpans()
+	require.Equal(t, 2, len(spans))
+
+	for i, span := range spans {
+		assert.Equal(t, "retry attempt", span.OperationName)
+		assert.Equal(t, i+1, span.Tag("retry.attempt"))
+	}
+
+	require.Equal(t, 1, len(spans[0].Logs()))
+	assert.Equal(t, 0, len(spans[1].Logs()))
+}
+
+type tracingBackendMock struct {
+	tracer opentracing.Tracer
+}
+
+func (t *tracingBackendMock) Setup(string) (opentracing.Tracer, io.Closer, error) {
+	opentracing.SetGlobalTracer(t.tracer)
+	return t.tracer, nil, nil
+}
+
 // countingRetryListener is a Listener implementation to count the times the Retried fn is called.
 type countingRetryListener struct {
 	timesCalled int
@@ -20,20 +20,22 @@ const (
 
 // stripPrefix is a middleware used to strip prefix from an URL request.
 type stripPrefix struct {
-	next       http.Handler
-	prefixes   []string
-	forceSlash bool // TODO Must be removed (breaking), the default behavior must be forceSlash=false
-	name       string
+	next          http.Handler
+	prefixes      []string
+	forceSlash    bool // TODO Must be removed (breaking), the default behavior must be forceSlash=false
+	disableHeader bool
+	name          string
 }
 
 // New creates a new strip prefix middleware.
 func New(ctx context.Context, next http.Handler, config dynamic.StripPrefix, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 	return &stripPrefix{
-		prefixes:   config.Prefixes,
-		forceSlash: config.ForceSlash,
-		next:       next,
-		name:       name,
+		prefixes:      config.Prefixes,
+		forceSlash:    config.ForceSlash,
+		disableHeader: config.DisableHeader,
+		next:          next,
+		name:          name,
 	}, nil
 }
 
@@ -56,7 +58,9 @@ func (s *stripPrefix) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 }
 
 func (s *stripPrefix) serveRequest(rw http.ResponseWriter, req *http.Request, prefix string) {
-	req.Header.Add(ForwardedPrefixHeader, prefix)
+	if !s.disableHeader {
+		SetForwardedPrefixHeader(req, prefix)
+	}
 	req.RequestURI = req.URL.RequestURI()
 	s.next.ServeHTTP(rw, req)
 }
@@ -72,6 +76,16 @@ func (s *stripPrefix) getPrefixStripped(urlPath, prefix string) string {
 	return ensureLeadingSlash(strings.TrimPrefix(urlPath, prefix))
 }
 
+// SetForwardedPrefixHeader sets the header holding the stripped prefix,
+// appended to the prefix stripped by a previous middleware, so that the header always holds the whole stripped prefix.
+func SetForwardedPrefixHeader(req *http.Request, prefix string) {
+	if previous := req.Header.Get(ForwardedPrefixHeader); previous != "" {
+		prefix = strings.TrimSuffix(previous, "/") + ensureLeadingSlash(prefix)
+	}
+
+	req.Header.Set(ForwardedPrefixHeader, prefix)
+}
+
 func ensureLeadingSlash(str string) string {
 	if str == "" {
 		return str
@@ -176,6 +176,16 @@ func TestStripPrefix(t *testing.T) {
 			expectedRawPath:    "/a%2Fb",
 			expectedHeader:     "/stat",
 		},
+		{
+			desc: "header disabled",
+			config: dynamic.StripPrefix{
+				Prefixes:      []string{"/stat"},
+				DisableHeader: true,
+			},
+			path:               "/stat/us",
+			expectedStatusCode: http.StatusOK,
+			expectedPath:       "/us",
+		},
 	}
 
 	for _, test := range testCases {
@@ -218,3 +228,46 @@ func TestStripPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestSetForwardedPrefixHeader(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		previous string
+		prefix   string
+		expected string
+	}{
+		{
+			desc:     "no previous prefix",
+			prefix:   "/stat",
+			expected: "/stat",
+		},
+		{
+			desc:     "previous prefix",
+			previous: "/api",
+			prefix:   "/stat",
+			expected: "/api/stat",
+		},
+		{
+			desc:     "previous prefix with trailing slash",
+			previous: "/api/",
+			prefix:   "stat/",
+			expected: "/api/stat/",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.previous != "" {
+				req.Header.Set(ForwardedPrefixHeader, test.previous)
+			}
+
+			SetForwardedPrefixHeader(req, test.prefix)
+
+			assert.Equal(t, []string{test.expected}, req.Header.Values(ForwardedPrefixHeader))
+		})
+	}
+}
@@ -20,9 +20,10 @@ const (
 
 // StripPrefixRegex is a middleware used to strip prefix from an URL request.
 type stripPrefixRegex struct {
-	next        http.Handler
-	expressions []*regexp.Regexp
-	name        string
+	next          http.Handler
+	expressions   []*regexp.Regexp
+	disableHeader bool
+	name          string
 }
 
 // New builds a new StripPrefixRegex middleware.
@@ -30,8 +31,9 @@ func New(ctx context.Context, next http.Handler, config dynamic.StripPrefixRegex
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
 	stripPrefix := stripPrefixRegex{
-		next: next,
-		name: name,
+		next:          next,
+		disableHeader: config.DisableHeader,
+		name:          name,
 	}
 
 	for _, exp := range config.Regex {
@@ -58,7 +60,12 @@ func (s *stripPrefixRegex) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 				continue
 			}
 
-			req.Header.Add(stripprefix.ForwardedPrefixHeader, prefix)
+			if !s.disableHeader {
+				stripprefix.SetForwardedPrefixHeader(req, prefix)
+			}
+
+			// The named capture groups can be used by the next middlewares.
+			req = req.WithContext(middlewares.WithPathCaptures(req.Context(), exp, parts))
 
 			req.URL.Path = ensureLeadingSlash(strings.Replace(req.URL.Path, prefix, "", 1))
 			if req.URL.RawPath != "" {
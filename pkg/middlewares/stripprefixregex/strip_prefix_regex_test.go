@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -146,3 +147,26 @@ func TestStripPrefixRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestStripPrefixRegex_pathCaptures(t *testing.T) {
+	var actualPath, actualHeader string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualPath = r.URL.Path
+		actualHeader = r.Header.Get(stripprefix.ForwardedPrefixHeader)
+	})
+
+	// The captured version is added back by the next middleware.
+	addPrefix, err := addprefix.New(context.Background(), next, dynamic.AddPrefix{Prefix: "/${version}"}, "foo-add-prefix")
+	require.NoError(t, err)
+
+	handler, err := New(context.Background(), addPrefix, dynamic.StripPrefixRegex{Regex: []string{`^/(?P<tenant>[a-z]+)/(?P<version>v[0-9]+)`}}, "foo-strip-prefix-regex")
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/foo/v1/users", nil)
+	req.Header.Set(stripprefix.ForwardedPrefixHeader, "/api")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "/v1/users", actualPath)
+	assert.Equal(t, "/api/foo/v1", actualHeader)
+}
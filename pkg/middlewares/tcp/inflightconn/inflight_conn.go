@@ -0,0 +1,90 @@
+package inflightconn
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const (
+	typeName = "InFlightConnTCP"
+)
+
+// inFlightConn is a middleware limiting the number of simultaneous connections of each client IP.
+type inFlightConn struct {
+	next tcp.Handler
+	name string
+
+	mu          sync.Mutex
+	connections map[string]int64
+	amount      int64
+}
+
+// New creates a max connections middleware.
+// The connections are counted until the next handler returns, which is when the connection is closed for the TCP services.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn, name string) (tcp.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Amount <= 0 {
+		return nil, fmt.Errorf("invalid amount %d, it must be greater than zero", config.Amount)
+	}
+
+	return &inFlightConn{
+		next:        next,
+		name:        name,
+		connections: make(map[string]int64),
+		amount:      config.Amount,
+	}, nil
+}
+
+func (i *inFlightConn) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), i.name, typeName)
+	logger := log.FromContext(ctx)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr %s: %v", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+
+	if !i.increment(ip) {
+		logger.Debugf("Connection from %s rejected: max connections reached", conn.RemoteAddr())
+		_ = conn.Close()
+		return
+	}
+
+	defer i.decrement(ip)
+
+	i.next.ServeTCP(conn)
+}
+
+// increment counts a new connection of the IP, unless its limit is reached.
+func (i *inFlightConn) increment(ip string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.connections[ip] >= i.amount {
+		return false
+	}
+
+	i.connections[ip]++
+
+	return true
+}
+
+func (i *inFlightConn) decrement(ip string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.connections[ip]--
+	if i.connections[ip] <= 0 {
+		delete(i.connections, ip)
+	}
+}
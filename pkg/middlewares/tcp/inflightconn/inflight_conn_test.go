@@ -0,0 +1,84 @@
+package inflightconn
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	_, err := New(context.Background(), next, dynamic.TCPInFlightConn{}, "traefikTest")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.TCPInFlightConn{Amount: -1}, "traefikTest")
+	assert.Error(t, err)
+}
+
+func TestInFlightConn_ServeTCP(t *testing.T) {
+	proceedCh := make(chan struct{})
+	servedCh := make(chan struct{})
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		servedCh <- struct{}{}
+		<-proceedCh
+	})
+
+	handler, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: 1}, "traefikTest")
+	require.NoError(t, err)
+
+	// The first connection is served, and held until proceedCh is closed.
+	first := &fakeConn{remoteAddr: "10.10.10.1:1234"}
+	done := make(chan struct{})
+	go func() {
+		handler.ServeTCP(first)
+		close(done)
+	}()
+	<-servedCh
+
+	// The second connection of the same IP is rejected.
+	second := &fakeConn{remoteAddr: "10.10.10.1:1235"}
+	handler.ServeTCP(second)
+	assert.True(t, second.closed)
+
+	// The connections of the other IPs are not limited.
+	other := &fakeConn{remoteAddr: "10.10.10.2:1234"}
+	go handler.ServeTCP(other)
+	<-servedCh
+
+	close(proceedCh)
+	<-done
+
+	// The connection of the IP is served again once the first one is done.
+	third := &fakeConn{remoteAddr: "10.10.10.1:1236"}
+	go handler.ServeTCP(third)
+	<-servedCh
+	assert.False(t, third.closed)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	remoteAddr string
+	closed     bool
+}
+
+func (f *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", f.remoteAddr)
+	return addr
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
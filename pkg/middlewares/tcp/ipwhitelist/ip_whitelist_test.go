@@ -0,0 +1,127 @@
+package ipwhitelist
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNewIPWhiteLister(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		whiteList     dynamic.TCPIPWhiteList
+		expectedError bool
+	}{
+		{
+			desc:          "empty source range",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid source range",
+			whiteList:     dynamic.TCPIPWhiteList{SourceRange: []string{"foo"}},
+			expectedError: true,
+		},
+		{
+			desc: "unknown CIDR set",
+			whiteList: dynamic.TCPIPWhiteList{
+				SourceRange:     []string{"10.10.10.10"},
+				SourceRangeSets: []string{"foo"},
+			},
+			expectedError: true,
+		},
+		{
+			desc:      "valid source range",
+			whiteList: dynamic.TCPIPWhiteList{SourceRange: []string{"10.10.10.10", "20.20.20.0/24"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+			handler, err := New(context.Background(), next, test.whiteList, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
+
+func TestIPWhiteLister_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		expected   bool
+	}{
+		{
+			desc:       "allowed IP",
+			remoteAddr: "10.10.10.10:1234",
+			expected:   true,
+		},
+		{
+			desc:       "allowed IP range",
+			remoteAddr: "20.20.20.21:1234",
+			expected:   true,
+		},
+		{
+			desc:       "not allowed IP",
+			remoteAddr: "20.20.21.21:1234",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var served bool
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				served = true
+			})
+
+			handler, err := New(context.Background(), next, dynamic.TCPIPWhiteList{
+				SourceRange: []string{"10.10.10.10", "20.20.20.0/24"},
+			}, "traefikTest")
+			require.NoError(t, err)
+
+			conn := &fakeConn{remoteAddr: test.remoteAddr}
+			handler.ServeTCP(conn)
+
+			assert.Equal(t, test.expected, served)
+			assert.Equal(t, !test.expected, conn.closed)
+		})
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+
+	remoteAddr string
+	closed     bool
+}
+
+func (f *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", f.remoteAddr)
+	return addr
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,175 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// The reasons of the rejected connections, reported by the metrics.
+const (
+	reasonRate   = "rate"
+	reasonBanned = "banned"
+)
+
+// source holds the token bucket of a client IP, and the end of its ban.
+type source struct {
+	mu          sync.Mutex
+	bucket      *rate.Limiter
+	bannedUntil time.Time
+}
+
+// rateLimiter is a middleware limiting the rate of the new connections of each client IP,
+// and banning the client IPs exceeding it for a while.
+type rateLimiter struct {
+	next tcp.Handler
+	name string
+
+	rate        rate.Limit
+	burst       int
+	banDuration time.Duration
+	// ttl is the number of seconds the state of a client IP is kept after its last connection,
+	// which is long enough for its bucket to be full again and its ban to be over.
+	ttl     int
+	sources *ttlmap.TtlMap
+
+	rejected gokitmetrics.Counter
+	now      func() time.Time
+}
+
+// New creates a TCP rate limiter middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, metricsRegistry metrics.Registry, name string) (tcp.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Average <= 0 {
+		return nil, fmt.Errorf("invalid average %d, it must be greater than zero", config.Average)
+	}
+
+	if config.Period < 0 || config.Burst < 0 || config.BanDuration < 0 {
+		return nil, errors.New("the period, burst and ban duration must not be negative")
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	period := time.Duration(config.Period)
+	if period == 0 {
+		period = time.Second
+	}
+
+	sources, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := rate.Limit(float64(config.Average) / period.Seconds())
+	refill := time.Duration(float64(burst) / float64(limit) * float64(time.Second))
+
+	r := &rateLimiter{
+		next:        next,
+		name:        name,
+		rate:        limit,
+		burst:       int(burst),
+		banDuration: time.Duration(config.BanDuration),
+		ttl:         int(math.Ceil((refill + time.Duration(config.BanDuration)).Seconds())),
+		sources:     sources,
+		now:         time.Now,
+	}
+
+	if metricsRegistry != nil && metricsRegistry.IsMiddlewareEnabled() {
+		r.rejected = metricsRegistry.TCPRateLimitRejectedConnsCounter()
+	}
+
+	return r, nil
+}
+
+func (r *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), r.name, typeName)
+	logger := log.FromContext(ctx)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot parse IP from remote addr %s: %v", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+
+	src, err := r.source(ip)
+	if err != nil {
+		logger.Errorf("Cannot keep the rate limit state of %s: %v", ip, err)
+		_ = conn.Close()
+		return
+	}
+
+	if reason := r.reject(src); reason != "" {
+		logger.Debugf("Connection from %s rejected: %s", conn.RemoteAddr(), reason)
+
+		if r.rejected != nil {
+			r.rejected.With("middleware", r.name, "reason", reason).Add(1)
+		}
+
+		_ = conn.Close()
+		return
+	}
+
+	r.next.ServeTCP(conn)
+}
+
+// source returns the state of a client IP, which is kept for the ttl after each of its connections.
+func (r *rateLimiter) source(ip string) (*source, error) {
+	var src *source
+	if value, ok := r.sources.Get(ip); ok {
+		src = value.(*source)
+	} else {
+		src = &source{bucket: rate.NewLimiter(r.rate, r.burst)}
+	}
+
+	if err := r.sources.Set(ip, src, r.ttl); err != nil {
+		return nil, err
+	}
+
+	return src, nil
+}
+
+// reject tells why a new connection of a client IP is rejected, or returns an empty string when it is accepted.
+// A client IP exceeding the rate is banned for the ban duration.
+func (r *rateLimiter) reject(src *source) string {
+	now := r.now()
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if now.Before(src.bannedUntil) {
+		return reasonBanned
+	}
+
+	if src.bucket.AllowN(now, 1) {
+		return ""
+	}
+
+	if r.banDuration > 0 {
+		src.bannedUntil = now.Add(r.banDuration)
+	}
+
+	return reasonRate
+}
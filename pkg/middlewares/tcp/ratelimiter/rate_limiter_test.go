@@ -0,0 +1,173 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// collectingCounter is a metrics.Counter recording the increments by label values.
+type collectingCounter struct {
+	values          map[string]float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.values[c.lastLabelValues[len(c.lastLabelValues)-1]] += delta
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPRateLimit
+		expectedError bool
+	}{
+		{
+			desc:          "no average",
+			config:        dynamic.TCPRateLimit{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative average",
+			config:        dynamic.TCPRateLimit{Average: -1},
+			expectedError: true,
+		},
+		{
+			desc:          "negative ban duration",
+			config:        dynamic.TCPRateLimit{Average: 1, BanDuration: ptypes.Duration(-time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:   "average only",
+			config: dynamic.TCPRateLimit{Average: 1},
+		},
+		{
+			desc:   "all options",
+			config: dynamic.TCPRateLimit{Average: 10, Period: ptypes.Duration(time.Minute), Burst: 5, BanDuration: ptypes.Duration(time.Hour)},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+			_, err := New(context.Background(), next, test.config, nil, "traefikTest")
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	var served int
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served++
+	})
+
+	config := dynamic.TCPRateLimit{
+		Average:     1,
+		Period:      ptypes.Duration(time.Minute),
+		Burst:       2,
+		BanDuration: ptypes.Duration(10 * time.Second),
+	}
+
+	handler, err := New(context.Background(), next, config, nil, "traefikTest")
+	require.NoError(t, err)
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	counter := &collectingCounter{values: make(map[string]float64)}
+
+	limiter := handler.(*rateLimiter)
+	limiter.now = func() time.Time { return now }
+	limiter.rejected = counter
+
+	serve := func(remoteAddr string) bool {
+		conn := &fakeConn{remoteAddr: remoteAddr}
+		handler.ServeTCP(conn)
+		return !conn.closed
+	}
+
+	// The burst of connections is accepted, and the next connection exceeding the rate bans the IP.
+	assert.True(t, serve("10.10.10.1:1234"))
+	assert.True(t, serve("10.10.10.1:1235"))
+	assert.False(t, serve("10.10.10.1:1236"))
+
+	// The connections of the other IPs are not limited.
+	assert.True(t, serve("10.10.10.2:1234"))
+
+	// The banned IP is rejected, even once its rate allows a new connection.
+	now = now.Add(5 * time.Second)
+	assert.False(t, serve("10.10.10.1:1237"))
+
+	// Once the ban is over, the IP is limited by its rate only.
+	now = now.Add(time.Minute)
+	assert.True(t, serve("10.10.10.1:1238"))
+
+	assert.Equal(t, 4, served)
+	assert.Equal(t, map[string]float64{reasonRate: 1, reasonBanned: 1}, counter.values)
+	assert.Equal(t, []string{"middleware", "traefikTest", "reason", reasonBanned}, counter.lastLabelValues)
+}
+
+func TestRateLimiter_ServeTCP_withoutBan(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	handler, err := New(context.Background(), next, dynamic.TCPRateLimit{Average: 1, Period: ptypes.Duration(time.Second)}, nil, "traefikTest")
+	require.NoError(t, err)
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler.(*rateLimiter).now = func() time.Time { return now }
+
+	serve := func(remoteAddr string) bool {
+		conn := &fakeConn{remoteAddr: remoteAddr}
+		handler.ServeTCP(conn)
+		return !conn.closed
+	}
+
+	assert.True(t, serve("10.10.10.1:1234"))
+	assert.False(t, serve("10.10.10.1:1235"))
+
+	// The IP is accepted again as soon as its rate allows it.
+	now = now.Add(time.Second)
+	assert.True(t, serve("10.10.10.1:1236"))
+}
+
+type fakeConn struct {
+	net.Conn
+
+	remoteAddr string
+	closed     bool
+}
+
+func (f *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", f.remoteAddr)
+	return addr
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConn) CloseWrite() error {
+	return nil
+}
@@ -0,0 +1,117 @@
+package sniwhitelist
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	typeName = "SNIWhiteListerTCP"
+)
+
+// sniWhiteLister is a middleware checking the TLS server name of the connections against a set of allowed and denied server names.
+type sniWhiteLister struct {
+	next        tcp.Handler
+	serverNames *serverNames
+	denyNames   *serverNames
+	name        string
+}
+
+// New builds a new TCP SNIWhiteLister given the server names to allow and to deny.
+func New(ctx context.Context, next tcp.Handler, config dynamic.SNIWhiteList, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if len(config.ServerNames) == 0 && len(config.DenyServerNames) == 0 {
+		return nil, errors.New("serverNames and denyServerNames are empty, SNIWhiteLister not created")
+	}
+
+	wl := &sniWhiteLister{
+		next: next,
+		name: name,
+	}
+
+	if len(config.ServerNames) > 0 {
+		wl.serverNames = newServerNames(config.ServerNames)
+	}
+
+	if len(config.DenyServerNames) > 0 {
+		wl.denyNames = newServerNames(config.DenyServerNames)
+	}
+
+	logger.Debugf("Setting up SNIWhiteLister with serverNames: %s, denyServerNames: %s", config.ServerNames, config.DenyServerNames)
+
+	return wl, nil
+}
+
+func (wl *sniWhiteLister) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), wl.name, typeName)
+	logger := log.FromContext(ctx)
+
+	serverName := tcp.ServerName(conn)
+
+	if wl.denyNames != nil && wl.denyNames.match(serverName) {
+		logger.Debugf("Connection from %s with server name %q rejected: denied", conn.RemoteAddr(), serverName)
+		_ = conn.Close()
+		return
+	}
+
+	if wl.serverNames != nil && !wl.serverNames.match(serverName) {
+		logger.Debugf("Connection from %s with server name %q rejected: not allowed", conn.RemoteAddr(), serverName)
+		_ = conn.Close()
+		return
+	}
+
+	wl.next.ServeTCP(conn)
+}
+
+// serverNames matches server names exactly, or with a wildcard matching a single label, such as *.example.com.
+type serverNames struct {
+	exact     map[string]struct{}
+	wildcards map[string]struct{} // keyed by the parent domain of the wildcards
+}
+
+func newServerNames(names []string) *serverNames {
+	s := &serverNames{
+		exact:     make(map[string]struct{}),
+		wildcards: make(map[string]struct{}),
+	}
+
+	for _, name := range names {
+		name = types.CanonicalDomain(name)
+		if strings.HasPrefix(name, "*.") {
+			s.wildcards[name[2:]] = struct{}{}
+			continue
+		}
+
+		s.exact[name] = struct{}{}
+	}
+
+	return s
+}
+
+// match returns whether serverName is matched. An empty server name is never matched.
+func (s *serverNames) match(serverName string) bool {
+	if serverName == "" {
+		return false
+	}
+
+	if _, ok := s.exact[serverName]; ok {
+		return true
+	}
+
+	i := strings.Index(serverName, ".")
+	if i <= 0 {
+		return false
+	}
+
+	_, ok := s.wildcards[serverName[i+1:]]
+	return ok
+}
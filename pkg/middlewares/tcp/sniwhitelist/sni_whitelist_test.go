@@ -0,0 +1,157 @@
+package sniwhitelist
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestNewSNIWhiteLister(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	_, err := New(context.Background(), next, dynamic.SNIWhiteList{}, "traefikTest")
+	require.Error(t, err)
+
+	handler, err := New(context.Background(), next, dynamic.SNIWhiteList{ServerNames: []string{"foo.bar"}}, "traefikTest")
+	require.NoError(t, err)
+	assert.NotNil(t, handler)
+}
+
+func TestSNIWhiteLister_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		whiteList  dynamic.SNIWhiteList
+		serverName string
+		expected   bool
+	}{
+		{
+			desc:       "allowed server name",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"foo.bar", "bar.baz"}},
+			serverName: "foo.bar",
+			expected:   true,
+		},
+		{
+			desc:       "allowed server name in another case",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"Foo.Bar"}},
+			serverName: "FOO.bar",
+			expected:   true,
+		},
+		{
+			desc:       "not allowed server name",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"foo.bar"}},
+			serverName: "bar.baz",
+		},
+		{
+			desc:      "missing server name",
+			whiteList: dynamic.SNIWhiteList{ServerNames: []string{"foo.bar"}},
+		},
+		{
+			desc:       "wildcard",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"*.foo.bar"}},
+			serverName: "api.foo.bar",
+			expected:   true,
+		},
+		{
+			desc:       "wildcard matching a single label",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"*.foo.bar"}},
+			serverName: "v1.api.foo.bar",
+		},
+		{
+			desc:       "wildcard not matching the parent domain",
+			whiteList:  dynamic.SNIWhiteList{ServerNames: []string{"*.foo.bar"}},
+			serverName: "foo.bar",
+		},
+		{
+			desc:       "denied server name",
+			whiteList:  dynamic.SNIWhiteList{DenyServerNames: []string{"admin.foo.bar"}},
+			serverName: "admin.foo.bar",
+		},
+		{
+			desc:       "server name not denied",
+			whiteList:  dynamic.SNIWhiteList{DenyServerNames: []string{"admin.foo.bar"}},
+			serverName: "api.foo.bar",
+			expected:   true,
+		},
+		{
+			desc:      "missing server name not denied",
+			whiteList: dynamic.SNIWhiteList{DenyServerNames: []string{"admin.foo.bar"}},
+			expected:  true,
+		},
+		{
+			desc: "denied server name allowed by a wildcard",
+			whiteList: dynamic.SNIWhiteList{
+				ServerNames:     []string{"*.foo.bar"},
+				DenyServerNames: []string{"admin.foo.bar"},
+			},
+			serverName: "admin.foo.bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			served := make(chan bool, 1)
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				served <- true
+				_ = conn.Close()
+			})
+
+			handler, err := New(context.Background(), next, test.whiteList, "traefikTest")
+			require.NoError(t, err)
+
+			router := &tcp.Router{}
+			router.AddRoute("*", handler)
+
+			client, server := tcpConnPair(t)
+
+			go func() {
+				_ = tls.Client(client, &tls.Config{
+					ServerName: test.serverName,
+					// The handshake is not completed by the handlers.
+					InsecureSkipVerify: true,
+				}).Handshake()
+			}()
+
+			router.ServeTCP(server)
+
+			select {
+			case <-served:
+				assert.True(t, test.expected, "the connection must be rejected")
+			default:
+				assert.False(t, test.expected, "the connection must be served")
+			}
+		})
+	}
+}
+
+func tcpConnPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	server := <-accepted
+	require.NotNil(t, server)
+	t.Cleanup(func() { _ = server.Close() })
+
+	return client.(*net.TCPConn), server.(*net.TCPConn)
+}
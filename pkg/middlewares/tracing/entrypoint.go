@@ -3,6 +3,7 @@ package tracing
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/containous/alice"
 	"github.com/opentracing/opentracing-go"
@@ -40,9 +41,13 @@ func (e *entryPointMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Reque
 			Debugf("Failed to extract the context: %v", err)
 	}
 
+	start := time.Now()
+
 	span, req, finish := e.StartSpanf(req, ext.SpanKindRPCServerEnum, "EntryPoint", []string{e.entryPoint, req.Host}, " ", ext.RPCServerOption(spanCtx))
 	defer finish()
 
+	e.SamplingPolicy().Start(span)
+
 	ext.Component.Set(span, e.ServiceName)
 	tracing.LogRequest(span, req)
 
@@ -52,6 +57,8 @@ func (e *entryPointMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Reque
 	e.next.ServeHTTP(recorder, req)
 
 	tracing.LogResponseCode(span, recorder.Status())
+
+	e.SamplingPolicy().Finish(span, req, recorder.Status(), time.Since(start))
 }
 
 // WrapEntryPointHandler Wraps tracing to alice.Constructor.
@@ -2,9 +2,12 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
@@ -15,21 +18,33 @@ const (
 )
 
 type forwarderMiddleware struct {
-	router  string
-	service string
-	next    http.Handler
+	router   string
+	service  string
+	sampling *tracing.SamplingPolicy
+	next     http.Handler
 }
 
 // NewForwarder creates a new forwarder middleware that traces the outgoing request.
-func NewForwarder(ctx context.Context, router, service string, next http.Handler) http.Handler {
+// The sampling configuration of the router, if any, overrides the sampling decisions taken for the whole entry point.
+func NewForwarder(ctx context.Context, router, service string, config *dynamic.RouterTracing, next http.Handler) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, "tracing", forwarderTypeName)).
 		Debugf("Added outgoing tracing middleware %s", service)
 
-	return &forwarderMiddleware{
-		router:  router,
-		service: service,
-		next:    next,
+	var sampling *tracing.SamplingPolicy
+	if config != nil {
+		var err error
+		sampling, err = tracing.NewSamplingPolicy(config.Sampling)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tracing sampling configuration: %w", err)
+		}
 	}
+
+	return &forwarderMiddleware{
+		router:   router,
+		service:  service,
+		sampling: sampling,
+		next:     next,
+	}, nil
 }
 
 func (f *forwarderMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -39,10 +54,14 @@ func (f *forwarderMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	start := time.Now()
+
 	opParts := []string{f.service, f.router}
 	span, req, finish := tr.StartSpanf(req, ext.SpanKindRPCClientEnum, "forward", opParts, "/")
 	defer finish()
 
+	f.sampling.Start(span)
+
 	span.SetTag("service.name", f.service)
 	span.SetTag("router.name", f.router)
 	ext.HTTPMethod.Set(span, req.Method)
@@ -56,4 +75,6 @@ func (f *forwarderMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Reques
 	f.next.ServeHTTP(recorder, req)
 
 	tracing.LogResponseCode(span, recorder.Status())
+
+	f.sampling.Finish(span, req, recorder.Status(), time.Since(start))
 }
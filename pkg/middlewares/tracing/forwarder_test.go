@@ -9,7 +9,9 @@ import (
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 func TestNewForwarder(t *testing.T) {
@@ -129,8 +131,65 @@ func TestNewForwarder(t *testing.T) {
 				assert.Equal(t, test.expected.OperationName, span.OpName)
 			})
 
-			handler := NewForwarder(context.Background(), test.router, test.service, next)
+			handler, err := NewForwarder(context.Background(), test.router, test.service, nil, next)
+			require.NoError(t, err)
 			handler.ServeHTTP(rw, req)
 		})
 	}
 }
+
+func TestNewForwarder_sampling(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		statusCode       int
+		expectedPriority interface{}
+	}{
+		{
+			desc:       "status code not matching",
+			statusCode: http.StatusOK,
+		},
+		{
+			desc:             "status code matching",
+			statusCode:       http.StatusBadGateway,
+			expectedPriority: uint16(1),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			backend := &trackingBackenMock{
+				tracer: &MockTracer{Span: &MockSpan{Tags: make(map[string]interface{})}},
+			}
+
+			newTracing, err := tracing.NewTracing("", 0, backend)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://www.test.com/toto", nil)
+			req = req.WithContext(tracing.WithTracing(req.Context(), newTracing))
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(test.statusCode)
+			})
+
+			config := &dynamic.RouterTracing{
+				Sampling: &types.TracingSampling{StatusCodes: []string{"500-599"}},
+			}
+
+			handler, err := NewForwarder(context.Background(), "router", "service", config, next)
+			require.NoError(t, err)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			span := backend.tracer.(*MockTracer).Span
+			assert.Equal(t, test.expectedPriority, span.Tags["sampling.priority"])
+		})
+	}
+}
+
+func TestNewForwarder_invalidSampling(t *testing.T) {
+	config := &dynamic.RouterTracing{
+		Sampling: &types.TracingSampling{StatusCodes: []string{"foo"}},
+	}
+
+	_, err := NewForwarder(context.Background(), "router", "service", config, http.NotFoundHandler())
+	assert.Error(t, err)
+}
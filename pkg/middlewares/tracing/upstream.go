@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	upstreamTypeName = "TracingUpstream"
+)
+
+type upstreamMiddleware struct {
+	service string
+	next    http.Handler
+}
+
+// NewUpstream creates a new middleware that traces each request forwarded to a server of the service.
+// As a request is forwarded once per attempt, the retried requests get a span per attempt,
+// in which the events of the connection to the server break down the time spent before the response.
+func NewUpstream(ctx context.Context, service string, next http.Handler) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, "tracing", upstreamTypeName)).
+		Debugf("Added upstream tracing middleware %s", service)
+
+	return &upstreamMiddleware{
+		service: service,
+		next:    next,
+	}
+}
+
+func (u *upstreamMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	tr, err := tracing.FromContext(req.Context())
+	if err != nil {
+		u.next.ServeHTTP(rw, req)
+		return
+	}
+
+	span, req, finish := tr.StartSpanf(req, ext.SpanKindRPCClientEnum, "upstream", []string{u.service}, "/")
+	defer finish()
+
+	span.SetTag("service.name", u.service)
+	ext.HTTPMethod.Set(span, req.Method)
+	ext.HTTPUrl.Set(span, req.URL.String())
+	ext.PeerHostname.Set(span, req.URL.Hostname())
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), newClientTrace(span)))
+
+	// The server gets the context of this span, instead of the one of the forwarder, as its parent.
+	tracing.InjectRequestHeaders(req)
+
+	recorder := newStatusCodeRecoder(rw, http.StatusOK)
+
+	u.next.ServeHTTP(recorder, req)
+
+	tracing.LogResponseCode(span, recorder.Status())
+}
+
+// newClientTrace returns a httptrace.ClientTrace logging the events of the connection to the server in the span.
+func newClientTrace(span opentracing.Span) *httptrace.ClientTrace {
+	logEvent := func(event string, err error) {
+		if err != nil {
+			span.LogKV("event", event, "error", err.Error())
+			return
+		}
+
+		span.LogKV("event", event)
+	}
+
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			logEvent("Get connection", nil)
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			logEvent("DNS start", nil)
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			logEvent("DNS done", info.Err)
+		},
+		ConnectStart: func(_, _ string) {
+			logEvent("Connect start", nil)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			logEvent("Connect done", err)
+		},
+		TLSHandshakeStart: func() {
+			logEvent("TLS handshake start", nil)
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			logEvent("TLS handshake done", err)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.SetTag("upstream.connection.reused", info.Reused)
+			logEvent("Got connection", nil)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			logEvent("Wrote request", info.Err)
+		},
+		GotFirstResponseByte: func() {
+			logEvent("First response byte", nil)
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+func TestNewUpstream(t *testing.T) {
+	tracer := mocktracer.New()
+
+	newTracing, err := tracing.NewTracing("", 0, &trackingBackenMock{tracer: tracer})
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		trace := httptrace.ContextClientTrace(req.Context())
+		require.NotNil(t, trace)
+
+		trace.GotConn(httptrace.GotConnInfo{Reused: true})
+		trace.GotFirstResponseByte()
+
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/toto", nil)
+	req = req.WithContext(tracing.WithTracing(req.Context(), newTracing))
+
+	NewUpstream(context.Background(), "service", next).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.FinishedSpans()
+	require.Equal(t, 1, len(spans))
+
+	span := spans[0]
+	assert.Equal(t, "upstream service", span.OperationName)
+	assert.Equal(t, map[string]interface{}{
+		"service.name":               "service",
+		"http.method":                http.MethodGet,
+		"http.url":                   "http://10.0.0.1:8080/toto",
+		"peer.hostname":              "10.0.0.1",
+		"span.kind":                  ext.SpanKindRPCClientEnum,
+		"upstream.connection.reused": true,
+		"http.status_code":           uint16(http.StatusBadGateway),
+		"error":                      true,
+	}, span.Tags())
+
+	require.Equal(t, 2, len(span.Logs()))
+	assert.Equal(t, "Got connection", span.Logs()[0].Fields[0].ValueString)
+	assert.Equal(t, "First response byte", span.Logs()[1].Fields[0].ValueString)
+}
+
+func TestNewUpstream_withoutTracing(t *testing.T) {
+	var called bool
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "http://10.0.0.1:8080/toto", nil)
+
+	NewUpstream(context.Background(), "service", next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, called)
+}
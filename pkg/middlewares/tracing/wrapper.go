@@ -16,7 +16,8 @@ type Tracable interface {
 }
 
 // Wrap adds tracability to an alice.Constructor.
-func Wrap(ctx context.Context, constructor alice.Constructor) alice.Constructor {
+// The handlers which do not provide their tracing information, like the plugins, are traced with the name of the middleware.
+func Wrap(ctx context.Context, middlewareName string, constructor alice.Constructor) alice.Constructor {
 	return func(next http.Handler) (http.Handler, error) {
 		if constructor == nil {
 			return nil, nil
@@ -31,7 +32,9 @@ func Wrap(ctx context.Context, constructor alice.Constructor) alice.Constructor
 			log.FromContext(ctx).WithField(log.MiddlewareName, name).Debug("Adding tracing to middleware")
 			return NewWrapper(handler, name, spanKind), nil
 		}
-		return handler, nil
+
+		log.FromContext(ctx).WithField(log.MiddlewareName, middlewareName).Debug("Adding tracing to middleware")
+		return NewWrapper(handler, middlewareName, tracing.SpanKindNoneEnum), nil
 	}
 }
 
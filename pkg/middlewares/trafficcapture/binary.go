@@ -0,0 +1,284 @@
+package trafficcapture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+// binaryMagic starts the capture files in the binary format.
+const binaryMagic = "TRFKCAP1"
+
+// maxBinaryRecordSize is the maximum size of a record read from a capture file in the binary format.
+const maxBinaryRecordSize = 64 * 1024 * 1024
+
+// The binary format is a compact format of the captured requests, made of the binaryMagic followed by the records.
+// Each record is prefixed by its size, and made of the fields of the Record, in order,
+// encoded as varints for the numbers, and as length prefixed bytes for the strings and the body.
+// The headers are encoded as their number of values, followed by the name and value of each of them.
+
+// binaryWriter writes the captured requests in the binary format.
+type binaryWriter struct {
+	file *os.File
+}
+
+// newBinaryWriter creates a binaryWriter appending the records to the ones of the file.
+func newBinaryWriter(file *os.File) (*binaryWriter, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		if _, err = file.WriteString(binaryMagic); err != nil {
+			return nil, err
+		}
+
+		return &binaryWriter{file: file}, nil
+	}
+
+	magic := make([]byte, len(binaryMagic))
+	if _, err = file.ReadAt(magic, 0); err != nil || string(magic) != binaryMagic {
+		return nil, errors.New("the capture file is not a binary capture written by Traefik")
+	}
+
+	if _, err = file.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	return &binaryWriter{file: file}, nil
+}
+
+func (w *binaryWriter) Write(record *Record) error {
+	var e encoder
+	e.varint(record.Time.UnixNano())
+	e.varint(int64(record.Duration))
+	e.string(record.EntryPoint)
+	e.string(record.Method)
+	e.string(record.URL)
+	e.string(record.Proto)
+	e.string(record.RemoteAddr)
+	e.header(record.Header)
+	e.bytes(record.Body)
+	e.bool(record.BodyTruncated)
+	e.varint(int64(record.StatusCode))
+	e.header(record.ResponseHeader)
+
+	buf := appendUvarint(make([]byte, 0, len(e.buf)+binary.MaxVarintLen64), uint64(len(e.buf)))
+	buf = append(buf, e.buf...)
+
+	if _, err := w.file.Write(buf); err != nil {
+		return fmt.Errorf("unable to write the record: %w", err)
+	}
+
+	return nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) varint(v int64) {
+	e.buf = appendVarint(e.buf, v)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.buf = appendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) string(s string) {
+	e.bytes([]byte(s))
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.buf = append(e.buf, 1)
+		return
+	}
+
+	e.buf = append(e.buf, 0)
+}
+
+func (e *encoder) header(header http.Header) {
+	names := make([]string, 0, len(header))
+	var count int
+	for name, values := range header {
+		names = append(names, name)
+		count += len(values)
+	}
+	sort.Strings(names)
+
+	e.buf = appendUvarint(e.buf, uint64(count))
+	for _, name := range names {
+		for _, value := range header[name] {
+			e.string(name)
+			e.string(value)
+		}
+	}
+}
+
+// Reader reads the captured requests of a capture file in the binary format, to replay them.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a Reader, reading the captured requests from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != binaryMagic {
+		return nil, errors.New("not a binary capture written by Traefik")
+	}
+
+	return &Reader{r: br}, nil
+}
+
+// Read reads the next captured request, and returns io.EOF once all of them have been read.
+func (r *Reader) Read() (*Record, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > maxBinaryRecordSize {
+		return nil, fmt.Errorf("record too large: %d bytes", size)
+	}
+
+	buf := make([]byte, size)
+	if _, err = io.ReadFull(r.r, buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	d := decoder{buf: buf}
+	record := &Record{
+		Time:       time.Unix(0, d.varint()),
+		Duration:   time.Duration(d.varint()),
+		EntryPoint: d.string(),
+		Method:     d.string(),
+		URL:        d.string(),
+		Proto:      d.string(),
+		RemoteAddr: d.string(),
+		Header:     d.header(),
+	}
+	record.Body = d.bytes()
+	record.BodyTruncated = d.bool()
+	record.StatusCode = int(d.varint())
+	record.ResponseHeader = d.header()
+
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid record: %w", d.err)
+	}
+
+	return record, nil
+}
+
+// decoder decodes the fields of a record, the first error being kept in err.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("invalid varint")
+		return 0
+	}
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errors.New("invalid uvarint")
+		return 0
+	}
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	size := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+
+	if size > uint64(len(d.buf)) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	if size == 0 {
+		return nil
+	}
+
+	b := d.buf[:size]
+	d.buf = d.buf[size:]
+
+	return b
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *decoder) bool() bool {
+	if d.err != nil {
+		return false
+	}
+
+	if len(d.buf) == 0 {
+		d.err = io.ErrUnexpectedEOF
+		return false
+	}
+
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+
+	return b != 0
+}
+
+func (d *decoder) header() http.Header {
+	count := d.uvarint()
+
+	header := http.Header{}
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		name := d.string()
+		value := d.string()
+		header[name] = append(header[name], value)
+	}
+
+	return header
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutVarint(b[:], v)]...)
+}
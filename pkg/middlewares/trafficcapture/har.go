@@ -0,0 +1,237 @@
+package trafficcapture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/traefik/traefik/v2/pkg/version"
+)
+
+// harTrailer closes the entries of the HAR file.
+// It is overwritten by each new entry, so that the file is a valid HAR document after each write.
+const harTrailer = "\n]}}\n"
+
+// harWriter writes the captured requests as the entries of a HAR (HTTP Archive) document.
+type harWriter struct {
+	file   *os.File
+	offset int64
+	empty  bool
+}
+
+// newHARWriter creates a harWriter appending the entries to the HAR document of the file,
+// which is created when the file is empty.
+func newHARWriter(file *os.File) (*harWriter, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		header, err := json.Marshal(harCreator{Name: "traefik", Version: version.Version})
+		if err != nil {
+			return nil, err
+		}
+
+		start := `{"log":{"version":"1.2","creator":` + string(header) + `,"entries":[`
+		if _, err = file.WriteString(start + harTrailer); err != nil {
+			return nil, err
+		}
+
+		return &harWriter{file: file, offset: int64(len(start)), empty: true}, nil
+	}
+
+	// The end of the document is read to append the entries to the ones of the file.
+	end := make([]byte, len(harTrailer)+1)
+	if info.Size() < int64(len(end)) {
+		return nil, errors.New("the capture file is not a HAR document written by Traefik")
+	}
+
+	if _, err = file.ReadAt(end, info.Size()-int64(len(end))); err != nil {
+		return nil, err
+	}
+
+	if string(end[1:]) != harTrailer {
+		return nil, errors.New("the capture file is not a HAR document written by Traefik")
+	}
+
+	return &harWriter{
+		file:   file,
+		offset: info.Size() - int64(len(harTrailer)),
+		empty:  end[0] == '[',
+	}, nil
+}
+
+func (w *harWriter) Write(record *Record) error {
+	entry, err := json.Marshal(newHAREntry(record))
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if !w.empty {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\n")
+	buf.Write(entry)
+
+	written := int64(buf.Len())
+	buf.WriteString(harTrailer)
+
+	if _, err = w.file.WriteAt(buf.Bytes(), w.offset); err != nil {
+		return fmt.Errorf("unable to write the HAR entry: %w", err)
+	}
+
+	w.offset += written
+	w.empty = false
+
+	return nil
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	EntryPoint      string      `json:"_entryPoint,omitempty"`
+	RemoteAddr      string      `json:"_remoteAddr,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text"`
+	Encoding  string `json:"_encoding,omitempty"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newHAREntry(record *Record) harEntry {
+	duration := float64(record.Duration) / float64(time.Millisecond)
+
+	entry := harEntry{
+		StartedDateTime: record.Time.UTC().Format(time.RFC3339Nano),
+		Time:            duration,
+		Request: harRequest{
+			Method:      record.Method,
+			URL:         record.URL,
+			HTTPVersion: record.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(record.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(record.Body),
+		},
+		Response: harResponse{
+			Status:      record.StatusCode,
+			StatusText:  http.StatusText(record.StatusCode),
+			HTTPVersion: record.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(record.ResponseHeader),
+			Content:     harContent{Size: -1, MimeType: record.ResponseHeader.Get("Content-Type")},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings:    harTimings{Wait: duration},
+		EntryPoint: record.EntryPoint,
+		RemoteAddr: record.RemoteAddr,
+	}
+
+	if u, err := url.Parse(record.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, value := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+			}
+		}
+
+		sort.SliceStable(entry.Request.QueryString, func(i, j int) bool {
+			return entry.Request.QueryString[i].Name < entry.Request.QueryString[j].Name
+		})
+	}
+
+	if len(record.Body) > 0 || record.BodyTruncated {
+		entry.Request.PostData = &harPostData{
+			MimeType:  record.Header.Get("Content-Type"),
+			Text:      string(record.Body),
+			Truncated: record.BodyTruncated,
+		}
+
+		// The binary bodies are encoded, as the text of the HAR documents must be valid UTF-8.
+		if !utf8.Valid(record.Body) {
+			entry.Request.PostData.Text = base64.StdEncoding.EncodeToString(record.Body)
+			entry.Request.PostData.Encoding = "base64"
+		}
+	}
+
+	return entry
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := []harNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return headers
+}
@@ -0,0 +1,167 @@
+package trafficcapture
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// redacted replaces the values of the redacted headers, query parameters and body fields.
+const redacted = "REDACTED"
+
+// redactor removes the personal data from the captured requests and responses.
+type redactor struct {
+	headers     map[string]struct{}
+	queryParams map[string]struct{}
+	bodyFields  map[string]struct{}
+}
+
+func newRedactor(config *types.CaptureRedaction) *redactor {
+	r := &redactor{
+		headers:     make(map[string]struct{}),
+		queryParams: make(map[string]struct{}),
+		bodyFields:  make(map[string]struct{}),
+	}
+
+	if config == nil {
+		return r
+	}
+
+	for _, name := range config.Headers {
+		r.headers[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	for _, name := range config.QueryParams {
+		r.queryParams[name] = struct{}{}
+	}
+
+	for _, name := range config.BodyFields {
+		r.bodyFields[name] = struct{}{}
+	}
+
+	return r
+}
+
+// header returns a copy of the headers, with the values of the redacted ones replaced.
+func (r *redactor) header(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	if redactedHeader == nil {
+		return http.Header{}
+	}
+
+	for name, values := range redactedHeader {
+		if _, ok := r.headers[name]; !ok {
+			continue
+		}
+
+		for i := range values {
+			values[i] = redacted
+		}
+	}
+
+	return redactedHeader
+}
+
+// requestURI returns the request URI, with the values of the redacted query parameters replaced.
+func (r *redactor) requestURI(u *url.URL) string {
+	if len(r.queryParams) == 0 || u.RawQuery == "" {
+		return u.RequestURI()
+	}
+
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		// The query cannot be redacted, so it is not captured.
+		return u.EscapedPath()
+	}
+
+	redactedURL := *u
+	redactedURL.RawQuery = redactValues(values, r.queryParams).Encode()
+
+	return redactedURL.RequestURI()
+}
+
+// body returns the body, with the values of the redacted fields of the JSON and URL encoded form bodies replaced.
+// It reports false when the fields of the body cannot be redacted, as the body is truncated or cannot be parsed.
+func (r *redactor) body(contentType string, body []byte, truncated bool) ([]byte, bool) {
+	if len(r.bodyFields) == 0 {
+		return body, true
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if truncated {
+			return nil, false
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return nil, false
+		}
+
+		redactedBody, err := json.Marshal(r.jsonValue(value))
+		if err != nil {
+			return nil, false
+		}
+
+		return redactedBody, true
+
+	case mediaType == "application/x-www-form-urlencoded":
+		if truncated {
+			return nil, false
+		}
+
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, false
+		}
+
+		return []byte(redactValues(values, r.bodyFields).Encode()), true
+
+	default:
+		// The fields of the other bodies cannot be found, so they are not captured.
+		return nil, false
+	}
+}
+
+// jsonValue replaces the values of the redacted fields of the JSON objects, at any depth.
+func (r *redactor) jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, fieldValue := range v {
+			if _, ok := r.bodyFields[name]; ok {
+				v[name] = redacted
+				continue
+			}
+
+			v[name] = r.jsonValue(fieldValue)
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.jsonValue(item)
+		}
+	}
+
+	return value
+}
+
+// redactValues replaces the values of the redacted fields of a form.
+func redactValues(values url.Values, names map[string]struct{}) url.Values {
+	for name, fieldValues := range values {
+		if _, ok := names[name]; !ok {
+			continue
+		}
+
+		for i := range fieldValues {
+			fieldValues[i] = redacted
+		}
+	}
+
+	return values
+}
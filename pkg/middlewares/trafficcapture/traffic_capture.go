@@ -0,0 +1,308 @@
+package trafficcapture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	typeName = "TrafficCapture"
+
+	// bufferSize is the maximum number of captured requests waiting to be written,
+	// beyond which the requests are not captured.
+	bufferSize = 1024
+)
+
+// Record is a captured request, with the status code and the headers of its response.
+type Record struct {
+	Time          time.Time
+	Duration      time.Duration
+	EntryPoint    string
+	Method        string
+	URL           string
+	Proto         string
+	RemoteAddr    string
+	Header        http.Header
+	Body          []byte
+	BodyTruncated bool
+
+	StatusCode     int
+	ResponseHeader http.Header
+}
+
+// recordWriter writes the captured requests in the format of the capture file.
+type recordWriter interface {
+	Write(record *Record) error
+}
+
+// Capture records a percentage of the requests of an entry point to a file.
+// It is shared by the handlers built for the entry point on each configuration change,
+// so that the captured percentage and the capture file are kept.
+type Capture struct {
+	percent     uint64
+	maxBodySize int64
+	redactor    *redactor
+
+	file    *os.File
+	writer  recordWriter
+	records chan *Record
+	done    chan struct{}
+
+	mu       sync.Mutex
+	total    uint64
+	captured uint64
+
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// New creates a capture, writing the captured requests to its file.
+func New(config types.TrafficCapture) (*Capture, error) {
+	if config.FilePath == "" {
+		return nil, errors.New("the file path is missing")
+	}
+
+	if config.Percent < 0 || config.Percent > 100 {
+		return nil, errors.New("percent must be between 0 and 100")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.FilePath), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create the directory of the capture file: %w", err)
+	}
+
+	file, err := os.OpenFile(config.FilePath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the capture file: %w", err)
+	}
+
+	var writer recordWriter
+	switch config.Format {
+	case types.CaptureFormatHAR, "":
+		writer, err = newHARWriter(file)
+	case types.CaptureFormatBinary:
+		writer, err = newBinaryWriter(file)
+	default:
+		err = fmt.Errorf("unsupported format %q", config.Format)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	c := &Capture{
+		percent:     uint64(config.Percent),
+		maxBodySize: config.MaxBodySize,
+		redactor:    newRedactor(config.Redaction),
+		file:        file,
+		writer:      writer,
+		records:     make(chan *Record, bufferSize),
+		done:        make(chan struct{}),
+	}
+
+	go c.write()
+
+	return c, nil
+}
+
+// WrapHandler wraps the capture into an alice.Constructor.
+func (c *Capture) WrapHandler(ctx context.Context, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		log.FromContext(middlewares.GetLoggerCtx(ctx, entryPointName, typeName)).Debug("Creating middleware")
+
+		return &handler{capture: c, next: next, entryPointName: entryPointName}, nil
+	}
+}
+
+// Close writes the pending captured requests, and closes the capture file.
+func (c *Capture) Close() {
+	c.closeMu.Lock()
+	c.closed = true
+	close(c.records)
+	c.closeMu.Unlock()
+
+	<-c.done
+
+	if err := c.file.Close(); err != nil {
+		log.WithoutContext().Errorf("Could not close the capture file: %v", err)
+	}
+}
+
+func (c *Capture) write() {
+	defer close(c.done)
+
+	for record := range c.records {
+		if err := c.writer.Write(record); err != nil {
+			log.WithoutContext().Errorf("Unable to write the captured request: %v", err)
+		}
+	}
+}
+
+// enqueue queues a captured request to be written, unless too many captured requests are waiting.
+func (c *Capture) enqueue(record *Record) bool {
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+
+	if c.closed {
+		return false
+	}
+
+	select {
+	case c.records <- record:
+		return true
+	default:
+		return false
+	}
+}
+
+// selected tells whether the next request is captured, to capture the percentage of the requests.
+func (c *Capture) selected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total++
+	if c.captured*100 < c.total*c.percent {
+		c.captured++
+		return true
+	}
+
+	return false
+}
+
+type handler struct {
+	capture        *Capture
+	next           http.Handler
+	entryPointName string
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The upgraded connections, such as the WebSocket ones, are not captured.
+	if req.Header.Get("Upgrade") != "" || req.Method == http.MethodConnect || !h.capture.selected() {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.entryPointName, typeName))
+
+	body, truncated, err := h.readBody(req)
+	if err != nil {
+		logger.Debugf("Error while reading the request body: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	// The record is created before forwarding the request, which can be modified by the middlewares.
+	record := h.newRecord(req, body, truncated)
+
+	recorder := &responseRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+	h.next.ServeHTTP(recorder, req)
+
+	record.Duration = time.Since(record.Time)
+	record.StatusCode = recorder.statusCode
+	record.ResponseHeader = h.capture.redactor.header(rw.Header())
+
+	if !h.capture.enqueue(record) {
+		logger.Debug("Too many captured requests waiting to be written, the request is not captured")
+	}
+}
+
+func (h *handler) newRecord(req *http.Request, body []byte, truncated bool) *Record {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	record := &Record{
+		Time:          time.Now(),
+		EntryPoint:    h.entryPointName,
+		Method:        req.Method,
+		URL:           scheme + "://" + req.Host + h.capture.redactor.requestURI(req.URL),
+		Proto:         req.Proto,
+		RemoteAddr:    req.RemoteAddr,
+		Header:        h.capture.redactor.header(req.Header),
+		BodyTruncated: truncated,
+	}
+
+	if len(body) > 0 {
+		var ok bool
+		record.Body, ok = h.capture.redactor.body(req.Header.Get("Content-Type"), body, truncated)
+		if !ok {
+			// The bodies which cannot be redacted are not captured.
+			record.BodyTruncated = true
+		}
+	}
+
+	return record
+}
+
+// readBody reads the body of the request, up to the maximum body size, and replaces it by a copy.
+func (h *handler) readBody(req *http.Request) ([]byte, bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false, nil
+	}
+
+	reader := io.Reader(req.Body)
+	if h.capture.maxBodySize >= 0 {
+		reader = io.LimitReader(req.Body, h.capture.maxBodySize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if h.capture.maxBodySize >= 0 && int64(len(body)) > h.capture.maxBodySize {
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+		return body[:h.capture.maxBodySize], true, nil
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, false, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder records the status code of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if !r.wroteHeader && !middlewares.IsInformational(code) {
+		r.statusCode = code
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
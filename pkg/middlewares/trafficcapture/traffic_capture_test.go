@@ -0,0 +1,268 @@
+package trafficcapture
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func newCaptureHandler(t *testing.T, config types.TrafficCapture) (*Capture, http.Handler) {
+	t.Helper()
+
+	capture, err := New(config)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Header().Set("Set-Cookie", "session=secret")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write(body)
+	})
+
+	handler, err := capture.WrapHandler(context.Background(), "web")(next)
+	require.NoError(t, err)
+
+	return capture, handler
+}
+
+func newTestConfig(t *testing.T, format string) types.TrafficCapture {
+	t.Helper()
+
+	config := types.TrafficCapture{}
+	config.SetDefaults()
+	config.FilePath = filepath.Join(t.TempDir(), "capture")
+	config.Format = format
+	config.Redaction.QueryParams = []string{"token"}
+	config.Redaction.BodyFields = []string{"password"}
+
+	return config
+}
+
+func TestCapture_har(t *testing.T) {
+	config := newTestConfig(t, types.CaptureFormatHAR)
+
+	// The entries are appended to the file when the capture is created again.
+	for i := 0; i < 2; i++ {
+		capture, handler := newCaptureHandler(t, config)
+
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/login?token=secret&foo=bar", strings.NewReader(`{"user":"bob","password":"secret"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		// The request body is still forwarded as is.
+		assert.Equal(t, `{"user":"bob","password":"secret"}`, rw.Body.String())
+
+		capture.Close()
+	}
+
+	data, err := ioutil.ReadFile(config.FilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	var har struct {
+		Log struct {
+			Version string     `json:"version"`
+			Entries []harEntry `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(data, &har))
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 2)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "web", entry.EntryPoint)
+	assert.Equal(t, http.MethodPost, entry.Request.Method)
+	assert.Equal(t, "http://example.com/login?foo=bar&token=REDACTED", entry.Request.URL)
+	assert.Contains(t, entry.Request.Headers, harNameValue{Name: "Authorization", Value: "REDACTED"})
+	require.NotNil(t, entry.Request.PostData)
+	assert.JSONEq(t, `{"user":"bob","password":"REDACTED"}`, entry.Request.PostData.Text)
+	assert.Equal(t, http.StatusCreated, entry.Response.Status)
+	assert.Contains(t, entry.Response.Headers, harNameValue{Name: "Set-Cookie", Value: "REDACTED"})
+}
+
+func TestCapture_binary(t *testing.T) {
+	config := newTestConfig(t, types.CaptureFormatBinary)
+	config.MaxBodySize = 4
+	config.Redaction.BodyFields = nil
+
+	capture, handler := newCaptureHandler(t, config)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/foo", nil))
+
+	// The bodies larger than the maximum body size are truncated.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "http://example.com/bar", strings.NewReader("hello")))
+	assert.Equal(t, "hello", rw.Body.String())
+
+	// The upgraded connections are not captured.
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/websocket", nil)
+	req.Header.Set("Upgrade", "websocket")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	capture.Close()
+
+	file, err := os.Open(config.FilePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	reader, err := NewReader(file)
+	require.NoError(t, err)
+
+	record, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "web", record.EntryPoint)
+	assert.Equal(t, http.MethodGet, record.Method)
+	assert.Equal(t, "http://example.com/foo", record.URL)
+	assert.Equal(t, "HTTP/1.1", record.Proto)
+	assert.Nil(t, record.Body)
+	assert.False(t, record.BodyTruncated)
+	assert.Equal(t, http.StatusCreated, record.StatusCode)
+	assert.Equal(t, "REDACTED", record.ResponseHeader.Get("Set-Cookie"))
+
+	record, err = reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, record.Method)
+	assert.Equal(t, "hell", string(record.Body))
+	assert.True(t, record.BodyTruncated)
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestCapture_percent(t *testing.T) {
+	config := newTestConfig(t, types.CaptureFormatBinary)
+	config.Percent = 25
+
+	capture, handler := newCaptureHandler(t, config)
+
+	for i := 0; i < 8; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+	}
+
+	capture.Close()
+
+	file, err := os.Open(config.FilePath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	reader, err := NewReader(file)
+	require.NoError(t, err)
+
+	var count int
+	for {
+		_, err = reader.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		count++
+	}
+
+	assert.Equal(t, 2, count)
+}
+
+func TestRedactor_body(t *testing.T) {
+	r := newRedactor(&types.CaptureRedaction{BodyFields: []string{"password"}})
+
+	testCases := []struct {
+		desc         string
+		contentType  string
+		body         string
+		truncated    bool
+		expectedBody string
+		expectedOK   bool
+	}{
+		{
+			desc:         "nested JSON fields",
+			contentType:  "application/json; charset=utf-8",
+			body:         `{"users":[{"name":"bob","password":"secret"}]}`,
+			expectedBody: `{"users":[{"name":"bob","password":"REDACTED"}]}`,
+			expectedOK:   true,
+		},
+		{
+			desc:         "form fields",
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "name=bob&password=secret",
+			expectedBody: "name=bob&password=REDACTED",
+			expectedOK:   true,
+		},
+		{
+			desc:        "truncated JSON body",
+			contentType: "application/json",
+			body:        `{"password":"sec`,
+			truncated:   true,
+		},
+		{
+			desc:        "other body",
+			contentType: "text/plain",
+			body:        "password=secret",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			body, ok := r.body(test.contentType, []byte(test.body), test.truncated)
+
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	notHAR := filepath.Join(dir, "not-har")
+	require.NoError(t, ioutil.WriteFile(notHAR, []byte("foo"), 0o600))
+
+	testCases := []struct {
+		desc   string
+		config types.TrafficCapture
+	}{
+		{
+			desc:   "missing file path",
+			config: types.TrafficCapture{Percent: 10},
+		},
+		{
+			desc:   "percent out of range",
+			config: types.TrafficCapture{FilePath: filepath.Join(dir, "capture"), Percent: 101},
+		},
+		{
+			desc:   "unsupported format",
+			config: types.TrafficCapture{FilePath: filepath.Join(dir, "capture"), Format: "pcap", Percent: 10},
+		},
+		{
+			desc:   "file not written by Traefik",
+			config: types.TrafficCapture{FilePath: notHAR, Format: types.CaptureFormatHAR, Percent: 10},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := New(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
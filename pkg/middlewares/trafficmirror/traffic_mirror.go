@@ -0,0 +1,244 @@
+package trafficmirror
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/vulcand/oxy/forward"
+	"github.com/vulcand/oxy/utils"
+)
+
+const (
+	typeName = "TrafficMirror"
+
+	// maxInFlight is the maximum number of mirrored requests in flight,
+	// beyond which the requests are not mirrored.
+	maxInFlight = 100
+)
+
+// Mirror mirrors a percentage of the requests of an entry point to a secondary destination, discarding its responses.
+// It is shared by the handlers built for the entry point on each configuration change,
+// so that the mirrored percentage and the connections to the destination are kept.
+type Mirror struct {
+	target      *url.URL
+	percent     uint64
+	maxBodySize int64
+	client      *http.Client
+	inFlight    chan struct{}
+
+	mu       sync.Mutex
+	total    uint64
+	mirrored uint64
+}
+
+// New creates a mirror.
+func New(config types.TrafficMirroring) (*Mirror, error) {
+	target, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", config.URL, err)
+	}
+
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("invalid URL %s: an absolute HTTP URL is expected", config.URL)
+	}
+
+	if config.Percent < 0 || config.Percent > 100 {
+		return nil, errors.New("percent must be between 0 and 100")
+	}
+
+	return &Mirror{
+		target:      target,
+		percent:     uint64(config.Percent),
+		maxBodySize: config.MaxBodySize,
+		client: &http.Client{
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+			Timeout:   time.Duration(config.Timeout),
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		inFlight: make(chan struct{}, maxInFlight),
+	}, nil
+}
+
+// WrapHandler wraps the mirror into an alice.Constructor.
+func (m *Mirror) WrapHandler(ctx context.Context, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		log.FromContext(middlewares.GetLoggerCtx(ctx, entryPointName, typeName)).Debug("Creating middleware")
+
+		return &handler{mirror: m, next: next, entryPointName: entryPointName}, nil
+	}
+}
+
+// Close closes the idle connections to the destination.
+func (m *Mirror) Close() {
+	m.client.CloseIdleConnections()
+}
+
+// selected tells whether the next request is mirrored, to mirror the percentage of the requests.
+func (m *Mirror) selected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total++
+	if m.mirrored*100 < m.total*m.percent {
+		m.mirrored++
+		return true
+	}
+
+	return false
+}
+
+// newRequest creates the mirrored request, sent to the destination with the host of the given request.
+func (m *Mirror) newRequest(req *http.Request, body []byte) *http.Request {
+	mirrored := req.Clone(context.Background())
+	mirrored.RequestURI = ""
+
+	mirrored.URL.Scheme = m.target.Scheme
+	mirrored.URL.Host = m.target.Host
+	if m.target.Path != "" {
+		mirrored.URL.Path = strings.TrimSuffix(m.target.Path, "/") + req.URL.Path
+		if req.URL.RawPath != "" {
+			mirrored.URL.RawPath = strings.TrimSuffix(m.target.EscapedPath(), "/") + req.URL.RawPath
+		}
+	}
+
+	mirrored.Body = nil
+	mirrored.GetBody = nil
+	mirrored.ContentLength = 0
+	if body != nil {
+		mirrored.Body = ioutil.NopCloser(bytes.NewReader(body))
+		mirrored.ContentLength = int64(len(body))
+	}
+
+	utils.RemoveHeaders(mirrored.Header, forward.HopHeaders...)
+
+	if clientIP, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		if prior := req.Header.Get(forward.XForwardedFor); prior != "" {
+			clientIP = prior + ", " + clientIP
+		}
+		mirrored.Header.Set(forward.XForwardedFor, clientIP)
+	}
+
+	if mirrored.Header.Get(forward.XForwardedHost) == "" {
+		mirrored.Header.Set(forward.XForwardedHost, req.Host)
+	}
+
+	if req.TLS != nil {
+		mirrored.Header.Set(forward.XForwardedProto, "https")
+	} else {
+		mirrored.Header.Set(forward.XForwardedProto, "http")
+	}
+
+	return mirrored
+}
+
+// send sends the mirrored request in the background, unless too many mirrored requests are in flight.
+func (m *Mirror) send(logger log.Logger, req *http.Request) {
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		logger.Debug("Too many mirrored requests in flight, the request is not mirrored")
+		return
+	}
+
+	go func() {
+		defer func() { <-m.inFlight }()
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			logger.Debugf("Error while mirroring the request: %v", err)
+			return
+		}
+
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+}
+
+type handler struct {
+	mirror         *Mirror
+	next           http.Handler
+	entryPointName string
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The upgraded connections, such as the WebSocket ones, are not mirrored.
+	if req.Header.Get("Upgrade") != "" || !h.mirror.selected() {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.entryPointName, typeName))
+
+	body, tooLarge, err := h.readBody(req)
+	if err != nil {
+		logger.Debugf("Error while reading the request body: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if tooLarge {
+		logger.Debug("The request body is larger than the maximum body size, the request is not mirrored")
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The mirrored request is created before forwarding the request, which can be modified by the middlewares.
+	mirrored := h.mirror.newRequest(req, body)
+
+	h.next.ServeHTTP(rw, req)
+
+	// The request is not mirrored when it has been canceled.
+	if req.Context().Err() != nil {
+		return
+	}
+
+	h.mirror.send(logger, mirrored)
+}
+
+// readBody reads the body of the request, and replaces it by a copy.
+// When the body is larger than the maximum body size, only the beginning of the body is read.
+func (h *handler) readBody(req *http.Request) ([]byte, bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false, nil
+	}
+
+	reader := io.Reader(req.Body)
+	if h.mirror.maxBodySize >= 0 {
+		reader = io.LimitReader(req.Body, h.mirror.maxBodySize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if h.mirror.maxBodySize >= 0 && int64(len(body)) > h.mirror.maxBodySize {
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+		return nil, true, nil
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, false, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,184 @@
+package trafficmirror
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+type mirroredRequest struct {
+	host          string
+	path          string
+	body          string
+	xForwardedFor string
+}
+
+type destination struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []mirroredRequest
+}
+
+func newDestination(t *testing.T) *destination {
+	t.Helper()
+
+	d := &destination{}
+	d.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		d.mu.Lock()
+		d.requests = append(d.requests, mirroredRequest{
+			host:          req.Host,
+			path:          req.URL.RequestURI(),
+			body:          string(body),
+			xForwardedFor: req.Header.Get("X-Forwarded-For"),
+		})
+		d.mu.Unlock()
+
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	t.Cleanup(d.Close)
+
+	return d
+}
+
+func (d *destination) mirrored() []mirroredRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]mirroredRequest(nil), d.requests...)
+}
+
+func newMirrorHandler(t *testing.T, config types.TrafficMirroring, next http.Handler) http.Handler {
+	t.Helper()
+
+	mirror, err := New(config)
+	require.NoError(t, err)
+	t.Cleanup(mirror.Close)
+
+	handler, err := mirror.WrapHandler(context.Background(), "web")(next)
+	require.NoError(t, err)
+
+	return handler
+}
+
+func TestMirror(t *testing.T) {
+	dest := newDestination(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+
+		// The modifications of the request by the next handlers are not mirrored.
+		req.URL.Path = "/modified"
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler := newMirrorHandler(t, types.TrafficMirroring{
+		URL:         dest.URL + "/shadow/",
+		Percent:     50,
+		MaxBodySize: 1024,
+		Timeout:     ptypes.Duration(time.Second),
+	}, next)
+
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/foo?bar=baz", strings.NewReader("hello"))
+		rw := httptest.NewRecorder()
+
+		handler.ServeHTTP(rw, req)
+
+		assert.Equal(t, http.StatusOK, rw.Code)
+	}
+
+	assert.Eventually(t, func() bool { return len(dest.mirrored()) == 5 }, 5*time.Second, 10*time.Millisecond)
+
+	for _, mirrored := range dest.mirrored() {
+		assert.Equal(t, mirroredRequest{
+			host:          "example.com",
+			path:          "/shadow/foo?bar=baz",
+			body:          "hello",
+			xForwardedFor: "192.0.2.1",
+		}, mirrored)
+	}
+}
+
+func TestMirror_notMirrored(t *testing.T) {
+	dest := newDestination(t)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		_, _ = rw.Write(body)
+	})
+
+	handler := newMirrorHandler(t, types.TrafficMirroring{
+		URL:         dest.URL,
+		Percent:     100,
+		MaxBodySize: 4,
+		Timeout:     ptypes.Duration(time.Second),
+	}, next)
+
+	// The requests with a body larger than the maximum body size are forwarded, but not mirrored.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "http://example.com/large", strings.NewReader("hello")))
+	assert.Equal(t, "hello", rw.Body.String())
+
+	// The upgraded connections are not mirrored.
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/websocket", nil)
+	req.Header.Set("Upgrade", "websocket")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// The canceled requests are not mirrored.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/canceled", nil).WithContext(ctx))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com/mirrored", nil))
+
+	assert.Eventually(t, func() bool { return len(dest.mirrored()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "/mirrored", dest.mirrored()[0].path)
+}
+
+func TestNew_invalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config types.TrafficMirroring
+	}{
+		{
+			desc:   "relative URL",
+			config: types.TrafficMirroring{URL: "/shadow", Percent: 10},
+		},
+		{
+			desc:   "unsupported scheme",
+			config: types.TrafficMirroring{URL: "ftp://shadow.example.com", Percent: 10},
+		},
+		{
+			desc:   "percent out of range",
+			config: types.TrafficMirroring{URL: "http://shadow.example.com", Percent: 101},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package upgrade
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+// protocolConnect is the protocol reported in the metrics for the tunnels established with the CONNECT requests.
+const protocolConnect = "connect"
+
+// routerHandler enforces the protocol upgrades and the CONNECT requests allowed on a router,
+// and counts the upgraded connections open on it.
+type routerHandler struct {
+	next             http.Handler
+	routerName       string
+	allowedProtocols []string
+	deniedProtocols  []string
+	allowConnect     bool
+	openGauge        gokitmetrics.Gauge
+}
+
+// NewRouterHandler creates a handler enforcing the upgrades configuration of a router.
+// Without configuration, all the protocol upgrades are allowed, and the CONNECT requests are rejected.
+func NewRouterHandler(next http.Handler, routerName string, config *dynamic.RouterUpgrades, metricsRegistry metrics.Registry) http.Handler {
+	h := &routerHandler{
+		next:       next,
+		routerName: routerName,
+	}
+
+	if config != nil {
+		h.allowedProtocols = config.AllowedProtocols
+		h.deniedProtocols = config.DeniedProtocols
+		h.allowConnect = config.AllowConnect
+	}
+
+	if metricsRegistry != nil && metricsRegistry.IsSvcEnabled() {
+		h.openGauge = metricsRegistry.RouterOpenUpgradedConnsGauge()
+	}
+
+	return h
+}
+
+func (h *routerHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var protocol string
+
+	switch {
+	case req.Method == http.MethodConnect:
+		if !h.allowConnect {
+			log.FromContext(req.Context()).Debugf("Rejecting CONNECT request to %s", req.Host)
+			tracing.SetErrorWithEvent(req, "CONNECT request not allowed")
+			http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		protocol = protocolConnect
+
+	case isUpgrade(req):
+		offered := parseList(req.Header.Values("Upgrade"))
+		allowed := h.allowedUpgrades(offered)
+
+		if len(allowed) == 0 {
+			log.FromContext(req.Context()).Debugf("Rejecting upgrade to %q", offered)
+			tracing.SetErrorWithEvent(req, "upgrade to %q not allowed", offered)
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		// Only the allowed protocols can be selected by the backend.
+		req.Header.Set("Upgrade", strings.Join(allowed, ", "))
+
+		protocol = strings.ToLower(protocolName(allowed[0]))
+
+	default:
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if h.openGauge == nil {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The upgraded connections and the tunnels are relayed until the end of the request.
+	recorder := &responseWriter{ResponseWriter: rw, h: h, protocol: protocol, connect: protocol == protocolConnect}
+	h.next.ServeHTTP(recorder, req)
+
+	if recorder.upgraded {
+		h.openGauge.With("router", h.routerName, "protocol", protocol).Add(-1)
+	}
+}
+
+// allowedUpgrades returns the offered protocols which are allowed and not denied.
+func (h *routerHandler) allowedUpgrades(offered []string) []string {
+	var allowed []string
+	for _, protocol := range offered {
+		if matchProtocol(h.deniedProtocols, protocol) {
+			continue
+		}
+
+		if len(h.allowedProtocols) > 0 && !matchProtocol(h.allowedProtocols, protocol) {
+			continue
+		}
+
+		allowed = append(allowed, protocol)
+	}
+
+	return allowed
+}
+
+// matchProtocol tells whether a protocol, such as websocket or HTTP/2.0, is in the list.
+// An item without version matches all the versions of the protocol, and * matches all the protocols.
+func matchProtocol(list []string, protocol string) bool {
+	for _, item := range list {
+		if item == "*" || strings.EqualFold(item, protocol) {
+			return true
+		}
+
+		if !strings.Contains(item, "/") && strings.EqualFold(item, protocolName(protocol)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protocolName returns the name of a protocol without its version.
+func protocolName(protocol string) string {
+	if i := strings.Index(protocol, "/"); i >= 0 {
+		return protocol[:i]
+	}
+
+	return protocol
+}
+
+func isUpgrade(req *http.Request) bool {
+	if req.Header.Get("Upgrade") == "" {
+		return false
+	}
+
+	for _, value := range parseList(req.Header.Values("Connection")) {
+		if strings.EqualFold(value, "upgrade") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseList parses the comma separated values of a header.
+func parseList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// responseWriter detects when the connection is upgraded, or when the tunnel of a CONNECT request is established.
+type responseWriter struct {
+	http.ResponseWriter
+	h        *routerHandler
+	protocol string
+	connect  bool
+	upgraded bool
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if r.connect && code >= 200 && code < 300 {
+		r.setUpgraded()
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.ResponseWriter)
+	}
+
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.setUpgraded()
+
+	return conn, rw, nil
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// CloseNotify returns a channel that receives at most a single value (true)
+// when the client connection has gone away.
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if n, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+
+	return make(<-chan bool)
+}
+
+func (r *responseWriter) setUpgraded() {
+	if r.upgraded {
+		return
+	}
+
+	r.upgraded = true
+	r.h.openGauge.With("router", r.h.routerName, "protocol", r.protocol).Add(1)
+}
@@ -0,0 +1,200 @@
+package upgrade
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestRouterHandler(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          *dynamic.RouterUpgrades
+		method          string
+		upgrade         string
+		expectedCode    int
+		expectedUpgrade string
+	}{
+		{
+			desc:         "request without upgrade",
+			config:       &dynamic.RouterUpgrades{DeniedProtocols: []string{"*"}},
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:            "upgrade without configuration",
+			method:          http.MethodGet,
+			upgrade:         "websocket",
+			expectedCode:    http.StatusOK,
+			expectedUpgrade: "websocket",
+		},
+		{
+			desc:            "allowed protocol",
+			config:          &dynamic.RouterUpgrades{AllowedProtocols: []string{"websocket"}},
+			method:          http.MethodGet,
+			upgrade:         "WebSocket/13",
+			expectedCode:    http.StatusOK,
+			expectedUpgrade: "WebSocket/13",
+		},
+		{
+			desc:         "protocol not allowed",
+			config:       &dynamic.RouterUpgrades{AllowedProtocols: []string{"websocket"}},
+			method:       http.MethodGet,
+			upgrade:      "h2c",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "denied protocol",
+			config:       &dynamic.RouterUpgrades{AllowedProtocols: []string{"*"}, DeniedProtocols: []string{"websocket"}},
+			method:       http.MethodGet,
+			upgrade:      "websocket",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:            "denied protocol version",
+			config:          &dynamic.RouterUpgrades{DeniedProtocols: []string{"foo/1"}},
+			method:          http.MethodGet,
+			upgrade:         "foo/1, foo/2",
+			expectedCode:    http.StatusOK,
+			expectedUpgrade: "foo/2",
+		},
+		{
+			desc:         "CONNECT without configuration",
+			method:       http.MethodConnect,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:         "CONNECT allowed",
+			config:       &dynamic.RouterUpgrades{AllowConnect: true},
+			method:       http.MethodConnect,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var upgrade string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				upgrade = req.Header.Get("Upgrade")
+			})
+
+			handler := NewRouterHandler(next, "foo@file", test.config, nil)
+
+			req := httptest.NewRequest(test.method, "http://foo.com", nil)
+			if test.upgrade != "" {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", test.upgrade)
+			}
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedUpgrade, upgrade)
+		})
+	}
+}
+
+// hijackRecorder is a ResponseRecorder whose connection can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, peer := net.Pipe()
+	_ = peer.Close()
+
+	return conn, bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)), nil
+}
+
+func TestRouterHandler_metrics(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		method           string
+		upgrade          string
+		hijack           bool
+		code             int
+		expectedProtocol string
+		expectedOpen     bool
+	}{
+		{
+			desc:             "upgraded connection",
+			method:           http.MethodGet,
+			upgrade:          "WebSocket",
+			hijack:           true,
+			expectedProtocol: "websocket",
+			expectedOpen:     true,
+		},
+		{
+			desc:    "upgrade refused by the backend",
+			method:  http.MethodGet,
+			upgrade: "websocket",
+			code:    http.StatusBadRequest,
+		},
+		{
+			desc:             "tunnel",
+			method:           http.MethodConnect,
+			code:             http.StatusOK,
+			expectedProtocol: "connect",
+			expectedOpen:     true,
+		},
+		{
+			desc:   "tunnel refused by the backend",
+			method: http.MethodConnect,
+			code:   http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			gauge := &testhelpers.CollectingGauge{}
+
+			var open bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.hijack {
+					conn, _, err := rw.(http.Hijacker).Hijack()
+					require.NoError(t, err)
+					_ = conn.Close()
+				} else {
+					rw.WriteHeader(test.code)
+				}
+
+				open = gauge.GaugeValue == 1
+			})
+
+			handler := NewRouterHandler(next, "foo@file", &dynamic.RouterUpgrades{AllowConnect: true}, nil).(*routerHandler)
+			handler.openGauge = gauge
+
+			req := httptest.NewRequest(test.method, "http://foo.com", nil)
+			if test.upgrade != "" {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", test.upgrade)
+			}
+
+			handler.ServeHTTP(hijackRecorder{httptest.NewRecorder()}, req)
+
+			assert.Equal(t, test.expectedOpen, open)
+
+			if test.expectedOpen {
+				// The collecting gauge holds the last delta, the connection being closed once the request is handled.
+				assert.Equal(t, float64(-1), gauge.GaugeValue)
+				assert.Equal(t, []string{"router", "foo@file", "protocol", test.expectedProtocol}, gauge.LastLabelValues)
+			} else {
+				assert.Nil(t, gauge.LastLabelValues)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package websocket
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Close codes, cf https://tools.ietf.org/html/rfc6455#section-7.4.1
+const (
+	closeGoingAway     = 1001
+	closeMessageTooBig = 1009
+)
+
+// limitedConn is the client connection of a WebSocket,
+// closed when it is idle or when the client sends a message which is too big.
+type limitedConn struct {
+	net.Conn
+
+	limiter     frameLimiter
+	idleTimeout time.Duration
+	idleTimer   *time.Timer
+	onClose     func()
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+func (c *limitedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.resetIdleTimer()
+
+		if limitErr := c.limiter.consume(p[:n]); limitErr != nil {
+			c.closeWith(closeMessageTooBig)
+			return 0, limitErr
+		}
+	}
+
+	return n, err
+}
+
+func (c *limitedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		c.resetIdleTimer()
+	}
+
+	return n, err
+}
+
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+
+	c.closeOnce.Do(func() {
+		if c.idleTimer != nil {
+			c.idleTimer.Stop()
+		}
+		if c.onClose != nil {
+			c.onClose()
+		}
+	})
+
+	return err
+}
+
+func (c *limitedConn) resetIdleTimer() {
+	if c.idleTimer != nil {
+		c.idleTimer.Reset(c.idleTimeout)
+	}
+}
+
+// closeWith sends a close frame with the given code to the client, and closes the connection.
+func (c *limitedConn) closeWith(code uint16) {
+	frame := []byte{0x88, 2, 0, 0}
+	binary.BigEndian.PutUint16(frame[2:], code)
+
+	// Unblocks any pending write.
+	_ = c.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+
+	c.writeMu.Lock()
+	_, _ = c.Conn.Write(frame)
+	c.writeMu.Unlock()
+
+	_ = c.Close()
+}
+
+// frameLimiter follows the frames sent by the client, to check the size of the messages.
+// cf https://tools.ietf.org/html/rfc6455#section-5.2
+type frameLimiter struct {
+	maxMessageSize int64
+
+	header      []byte
+	payloadLeft uint64
+	messageSize uint64
+	fin         bool
+}
+
+// consume reads the data sent by the client, and returns an error if a message is too big.
+func (f *frameLimiter) consume(p []byte) error {
+	if f.maxMessageSize <= 0 {
+		return nil
+	}
+
+	for len(p) > 0 {
+		if f.payloadLeft > 0 {
+			skipped := uint64(len(p))
+			if skipped > f.payloadLeft {
+				skipped = f.payloadLeft
+			}
+
+			f.payloadLeft -= skipped
+			p = p[skipped:]
+			continue
+		}
+
+		// Reads the frame header, which may be received in several parts.
+		needed := 2
+		if len(f.header) >= 2 {
+			needed = headerSize(f.header)
+		}
+
+		missing := needed - len(f.header)
+		if missing > len(p) {
+			missing = len(p)
+		}
+		f.header = append(f.header, p[:missing]...)
+		p = p[missing:]
+
+		if len(f.header) < 2 || len(f.header) < headerSize(f.header) {
+			continue
+		}
+
+		if err := f.startFrame(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *frameLimiter) startFrame() error {
+	header := f.header
+	f.header = f.header[:0]
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+
+	var length uint64
+	switch l := header[1] & 0x7f; l {
+	case 126:
+		length = uint64(binary.BigEndian.Uint16(header[2:4]))
+	case 127:
+		length = binary.BigEndian.Uint64(header[2:10])
+	default:
+		length = uint64(l)
+	}
+
+	f.payloadLeft = length
+
+	// Control frames can be sent in the middle of a fragmented message, and are not part of it.
+	if opcode&0x08 != 0 {
+		return nil
+	}
+
+	// A data frame which is not a continuation one starts a new message.
+	if opcode != 0 || f.fin {
+		f.messageSize = 0
+	}
+	f.fin = fin
+
+	f.messageSize += length
+	if f.messageSize > uint64(f.maxMessageSize) {
+		return fmt.Errorf("websocket message bigger than %d bytes", f.maxMessageSize)
+	}
+
+	return nil
+}
+
+// headerSize returns the size of a frame header, from its first 2 bytes.
+func headerSize(header []byte) int {
+	size := 2
+
+	switch header[1] & 0x7f {
+	case 126:
+		size += 2
+	case 127:
+		size += 8
+	}
+
+	if header[1]&0x80 != 0 {
+		// Masking key.
+		size += 4
+	}
+
+	return size
+}
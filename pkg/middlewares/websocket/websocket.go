@@ -0,0 +1,221 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "WebSocket"
+)
+
+// webSocket is a middleware enforcing limits on the WebSocket connections.
+type webSocket struct {
+	name           string
+	next           http.Handler
+	routerName     string
+	allowOrigins   []string
+	subprotocols   []string
+	maxMessageSize int64
+	idleTimeout    time.Duration
+	openGauge      gokitmetrics.Gauge
+}
+
+// New creates a WebSocket middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.WebSocket, metricsRegistry metrics.Registry, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.MaxMessageSize < 0 || config.IdleTimeout < 0 {
+		return nil, errors.New("maxMessageSize and idleTimeout must be positive")
+	}
+
+	w := &webSocket{
+		name:           name,
+		next:           next,
+		allowOrigins:   config.AllowOrigins,
+		subprotocols:   config.Subprotocols,
+		maxMessageSize: config.MaxMessageSize,
+		idleTimeout:    time.Duration(config.IdleTimeout),
+	}
+
+	// Middlewares are built for each router, with the router name in the logger fields.
+	if routerName, ok := logger.Data[log.RouterName].(string); ok {
+		w.routerName = routerName
+	}
+
+	if metricsRegistry != nil && metricsRegistry.IsSvcEnabled() && w.routerName != "" {
+		w.openGauge = metricsRegistry.RouterOpenWebSocketsGauge()
+	}
+
+	return w, nil
+}
+
+func (w *webSocket) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return w.name, tracing.SpanKindNoneEnum
+}
+
+func (w *webSocket) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !isWebSocketUpgrade(req) {
+		w.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), w.name, typeName))
+
+	if origin := req.Header.Get("Origin"); !w.isOriginAllowed(origin) {
+		logger.Debugf("Rejecting WebSocket connection from origin %q", origin)
+		tracing.SetErrorWithEvent(req, "websocket origin %q not allowed", origin)
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	if len(w.subprotocols) > 0 {
+		offered := parseList(req.Header.Values("Sec-WebSocket-Protocol"))
+		allowed := w.allowedSubprotocols(offered)
+
+		if len(offered) > 0 && len(allowed) == 0 {
+			logger.Debugf("Rejecting WebSocket connection with subprotocols %q", offered)
+			tracing.SetErrorWithEvent(req, "websocket subprotocols %q not allowed", offered)
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		// Only the allowed subprotocols can be selected by the backend.
+		req.Header.Del("Sec-WebSocket-Protocol")
+		if len(allowed) > 0 {
+			req.Header.Set("Sec-WebSocket-Protocol", strings.Join(allowed, ", "))
+		}
+	}
+
+	w.next.ServeHTTP(&responseWriter{ResponseWriter: rw, ws: w}, req)
+}
+
+func (w *webSocket) isOriginAllowed(origin string) bool {
+	if len(w.allowOrigins) == 0 {
+		return true
+	}
+
+	for _, allowed := range w.allowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *webSocket) allowedSubprotocols(offered []string) []string {
+	var allowed []string
+	for _, protocol := range offered {
+		for _, subprotocol := range w.subprotocols {
+			if strings.EqualFold(protocol, subprotocol) {
+				allowed = append(allowed, protocol)
+				break
+			}
+		}
+	}
+
+	return allowed
+}
+
+// wrapConn wraps the hijacked connection to enforce the limits on it.
+func (w *webSocket) wrapConn(conn net.Conn) net.Conn {
+	c := &limitedConn{
+		Conn:    conn,
+		limiter: frameLimiter{maxMessageSize: w.maxMessageSize},
+	}
+
+	if w.idleTimeout > 0 {
+		c.idleTimer = time.AfterFunc(w.idleTimeout, func() { c.closeWith(closeGoingAway) })
+		c.idleTimeout = w.idleTimeout
+	}
+
+	if w.openGauge != nil {
+		gauge := w.openGauge.With("router", w.routerName)
+		gauge.Add(1)
+		c.onClose = func() { gauge.Add(-1) }
+	}
+
+	return c
+}
+
+func isWebSocketUpgrade(req *http.Request) bool {
+	if !strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+
+	for _, value := range parseList(req.Header.Values("Connection")) {
+		if strings.EqualFold(value, "upgrade") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseList parses the comma separated values of a header.
+func parseList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// responseWriter wraps the connection hijacked for the WebSocket.
+type responseWriter struct {
+	http.ResponseWriter
+	ws *webSocket
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.ResponseWriter)
+	}
+
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r.ws.wrapConn(conn), rw, nil
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// CloseNotify returns a channel that receives at most a single value (true)
+// when the client connection has gone away.
+func (r *responseWriter) CloseNotify() <-chan bool {
+	if n, ok := r.ResponseWriter.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+
+	return make(<-chan bool)
+}
@@ -0,0 +1,213 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestWebSocket_handshake(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		config               dynamic.WebSocket
+		reqHeaders           map[string]string
+		expectedStatus       int
+		expectedSubprotocols string
+	}{
+		{
+			desc:           "not a WebSocket request",
+			config:         dynamic.WebSocket{AllowOrigins: []string{"https://example.com"}},
+			reqHeaders:     map[string]string{"Origin": "https://example.org"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "allowed origin",
+			config: dynamic.WebSocket{AllowOrigins: []string{"https://example.com"}},
+			reqHeaders: map[string]string{
+				"Connection": "Upgrade",
+				"Upgrade":    "websocket",
+				"Origin":     "https://example.com",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "any origin",
+			config: dynamic.WebSocket{AllowOrigins: []string{"*"}},
+			reqHeaders: map[string]string{
+				"Connection": "keep-alive, Upgrade",
+				"Upgrade":    "websocket",
+				"Origin":     "https://example.org",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "origin not allowed",
+			config: dynamic.WebSocket{AllowOrigins: []string{"https://example.com"}},
+			reqHeaders: map[string]string{
+				"Connection": "Upgrade",
+				"Upgrade":    "websocket",
+				"Origin":     "https://example.org",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:   "allowed subprotocols",
+			config: dynamic.WebSocket{Subprotocols: []string{"graphql-ws", "mqtt"}},
+			reqHeaders: map[string]string{
+				"Connection":             "Upgrade",
+				"Upgrade":                "websocket",
+				"Sec-WebSocket-Protocol": "soap, mqtt, graphql-ws",
+			},
+			expectedStatus:       http.StatusOK,
+			expectedSubprotocols: "mqtt, graphql-ws",
+		},
+		{
+			desc:   "subprotocols not allowed",
+			config: dynamic.WebSocket{Subprotocols: []string{"mqtt"}},
+			reqHeaders: map[string]string{
+				"Connection":             "Upgrade",
+				"Upgrade":                "websocket",
+				"Sec-WebSocket-Protocol": "soap",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:   "no subprotocol",
+			config: dynamic.WebSocket{Subprotocols: []string{"mqtt"}},
+			reqHeaders: map[string]string{
+				"Connection": "Upgrade",
+				"Upgrade":    "websocket",
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var subprotocols string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				subprotocols = req.Header.Get("Sec-WebSocket-Protocol")
+			})
+
+			handler, err := New(context.Background(), next, test.config, nil, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for k, v := range test.reqHeaders {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedSubprotocols, subprotocols)
+		})
+	}
+}
+
+func TestWebSocket_limits(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.WebSocket
+		frames        [][]byte
+		expectedClose []byte
+	}{
+		{
+			desc:          "idle timeout",
+			config:        dynamic.WebSocket{IdleTimeout: ptypes.Duration(100 * time.Millisecond)},
+			expectedClose: []byte{0x88, 2, 0x03, 0xe9},
+		},
+		{
+			desc:   "message too big",
+			config: dynamic.WebSocket{MaxMessageSize: 5},
+			frames: [][]byte{
+				// Masked text frame of 4 bytes.
+				{0x81, 0x84, 0, 0, 0, 0, 'f', 'o', 'o', '!'},
+				// Masked fragmented text message of 6 bytes.
+				{0x01, 0x83, 0, 0, 0, 0, 'f', 'o', 'o'},
+				{0x80, 0x83, 0, 0, 0, 0, 'b', 'a', 'r'},
+			},
+			expectedClose: []byte{0x88, 2, 0x03, 0xf1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Mimics the reverse proxy, forwarding the client data once the connection is upgraded.
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				conn, brw, err := rw.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				defer func() { _ = conn.Close() }()
+
+				_, _ = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+				_ = brw.Flush()
+
+				_, _ = io.Copy(ioutil.Discard, conn)
+			})
+
+			handler, err := New(context.Background(), next, test.config, nil, "traefikTest")
+			require.NoError(t, err)
+
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			require.NoError(t, err)
+			defer func() { _ = conn.Close() }()
+
+			_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+			require.NoError(t, err)
+
+			reader := bufio.NewReader(conn)
+			resp, err := http.ReadResponse(reader, nil)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+			for _, frame := range test.frames {
+				_, err = conn.Write(frame)
+				require.NoError(t, err)
+			}
+
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+			data, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedClose, data)
+		})
+	}
+}
+
+func TestFrameLimiter(t *testing.T) {
+	limiter := frameLimiter{maxMessageSize: 200}
+
+	// Masked binary frame of 150 bytes, with an extended payload length, received in several parts.
+	frame := append([]byte{0x82, 0xfe, 0, 150, 1, 2, 3, 4}, make([]byte, 150)...)
+	for _, part := range [][]byte{frame[:1], frame[1:3], frame[3:20], frame[20:]} {
+		require.NoError(t, limiter.consume(part))
+	}
+
+	// Ping frame, not counted in the message size.
+	require.NoError(t, limiter.consume([]byte{0x89, 0x80, 1, 2, 3, 4}))
+
+	// Fragmented message of 150 + 100 bytes.
+	require.NoError(t, limiter.consume(append([]byte{0x02, 0xfe, 0, 150, 1, 2, 3, 4}, make([]byte, 150)...)))
+	assert.Error(t, limiter.consume([]byte{0x80, 0xe4, 1, 2, 3, 4}))
+}
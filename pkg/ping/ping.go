@@ -4,14 +4,25 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Handler expose ping routes.
 type Handler struct {
-	EntryPoint            string `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
-	ManualRouting         bool   `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
-	TerminatingStatusCode int    `description:"Terminating status code" json:"terminatingStatusCode,omitempty" toml:"terminatingStatusCode,omitempty" yaml:"terminatingStatusCode,omitempty" export:"true"`
+	EntryPoint            string   `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	ManualRouting         bool     `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
+	TerminatingStatusCode int      `description:"Terminating status code" json:"terminatingStatusCode,omitempty" toml:"terminatingStatusCode,omitempty" yaml:"terminatingStatusCode,omitempty" export:"true"`
+	RequiredProviders     []string `description:"Providers whose configuration must be loaded before the ready endpoint reports ready." json:"requiredProviders,omitempty" toml:"requiredProviders,omitempty" yaml:"requiredProviders,omitempty" export:"true"`
 	terminating           bool
+
+	providersMu     sync.RWMutex
+	loadedProviders map[string]struct{}
+
+	// draining is set once Traefik starts draining its entry points.
+	draining int32
 }
 
 // SetDefaults sets the default values.
@@ -36,3 +47,71 @@ func (h *Handler) ServeHTTP(response http.ResponseWriter, request *http.Request)
 	response.WriteHeader(statusCode)
 	fmt.Fprint(response, http.StatusText(statusCode))
 }
+
+// Drain causes the ready endpoint to report not ready, as the entry points are draining.
+func (h *Handler) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// ProviderLoaded records that the configuration of the provider has been loaded.
+func (h *Handler) ProviderLoaded(providerName string) {
+	h.providersMu.Lock()
+	defer h.providersMu.Unlock()
+
+	if h.loadedProviders == nil {
+		h.loadedProviders = make(map[string]struct{})
+	}
+
+	h.loadedProviders[providerName] = struct{}{}
+}
+
+// pendingProviders returns the required providers whose configuration has not been loaded yet,
+// and whether any configuration has been loaded.
+func (h *Handler) pendingProviders() ([]string, bool) {
+	h.providersMu.RLock()
+	defer h.providersMu.RUnlock()
+
+	var pending []string
+	for _, name := range h.RequiredProviders {
+		if _, ok := h.loadedProviders[name]; !ok {
+			pending = append(pending, name)
+		}
+	}
+
+	sort.Strings(pending)
+
+	return pending, len(h.loadedProviders) > 0
+}
+
+// ReadyHandler returns the handler of the ready endpoint,
+// which reports ready once a configuration, and the configurations of all the required providers, have been loaded,
+// until the entry points start draining.
+func (h *Handler) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		if h.terminating {
+			rw.WriteHeader(h.TerminatingStatusCode)
+			fmt.Fprint(rw, http.StatusText(h.TerminatingStatusCode))
+			return
+		}
+
+		if atomic.LoadInt32(&h.draining) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(rw, "%s: draining", http.StatusText(http.StatusServiceUnavailable))
+			return
+		}
+
+		pending, loaded := h.pendingProviders()
+		if !loaded || len(pending) > 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(rw, http.StatusText(http.StatusServiceUnavailable))
+
+			if len(pending) > 0 {
+				fmt.Fprintf(rw, ": waiting for providers %s", strings.Join(pending, ", "))
+			}
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprint(rw, http.StatusText(http.StatusOK))
+	})
+}
@@ -0,0 +1,82 @@
+package ping
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ReadyHandler(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		requiredProviders  []string
+		loadedProviders    []string
+		draining           bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "no configuration loaded",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable",
+		},
+		{
+			desc:               "configuration loaded",
+			loadedProviders:    []string{"internal"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "OK",
+		},
+		{
+			desc:               "required providers not loaded",
+			requiredProviders:  []string{"kubernetescrd", "file", "myresolver.acme"},
+			loadedProviders:    []string{"internal", "file"},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable: waiting for providers kubernetescrd, myresolver.acme",
+		},
+		{
+			desc:               "required providers loaded",
+			requiredProviders:  []string{"kubernetescrd", "file"},
+			loadedProviders:    []string{"internal", "kubernetescrd", "file"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "OK",
+		},
+		{
+			desc:               "draining",
+			loadedProviders:    []string{"internal"},
+			draining:           true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable: draining",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handler{RequiredProviders: test.requiredProviders}
+			h.SetDefaults()
+
+			for _, name := range test.loadedProviders {
+				h.ProviderLoaded(name)
+			}
+
+			if test.draining {
+				h.Drain()
+			}
+
+			recorder := httptest.NewRecorder()
+			h.ReadyHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+
+			body, err := ioutil.ReadAll(recorder.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
@@ -29,6 +29,9 @@ type pluginContext struct {
 	// BasePkg plugin's base package name (optional)
 	BasePkg string `json:"basePkg,omitempty" toml:"basePkg,omitempty" yaml:"basePkg,omitempty"`
 
+	// Type plugin's type (middleware or provider)
+	Type string `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty"`
+
 	interpreter *interp.Interpreter
 }
 
@@ -58,11 +61,19 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, devPlugin *DevPlu
 			return nil, fmt.Errorf("%s: failed to import plugin code %q: %w", desc.ModuleName, manifest.Import, err)
 		}
 
+		if manifest.Type == typeProvider {
+			err = importProviderPackages(i)
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to import provider packages: %w", desc.ModuleName, err)
+			}
+		}
+
 		pb.descriptors[pName] = pluginContext{
 			interpreter: i,
 			GoPath:      client.GoPath(),
 			Import:      manifest.Import,
 			BasePkg:     manifest.BasePkg,
+			Type:        manifest.Type,
 		}
 	}
 
@@ -80,11 +91,19 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, devPlugin *DevPlu
 			return nil, fmt.Errorf("%s: failed to import plugin code %q: %w", devPlugin.ModuleName, manifest.Import, err)
 		}
 
+		if manifest.Type == typeProvider {
+			err = importProviderPackages(i)
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to import provider packages: %w", devPlugin.ModuleName, err)
+			}
+		}
+
 		pb.descriptors[devPluginName] = pluginContext{
 			interpreter: i,
 			GoPath:      devPlugin.GoPath,
 			Import:      manifest.Import,
 			BasePkg:     manifest.BasePkg,
+			Type:        manifest.Type,
 		}
 	}
 
@@ -102,6 +121,10 @@ func (b Builder) Build(pName string, config map[string]interface{}, middlewareNa
 		return nil, fmt.Errorf("plugin: unknown plugin type: %s", pName)
 	}
 
+	if descriptor.Type == typeProvider {
+		return nil, fmt.Errorf("plugin: %s is not a middleware plugin", pName)
+	}
+
 	m, err := newMiddleware(descriptor, config, middlewareName)
 	if err != nil {
 		return nil, err
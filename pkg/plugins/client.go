@@ -333,6 +333,11 @@ func (c *Client) CleanArchives(plugins map[string]Descriptor) error {
 				if err = os.RemoveAll(archivePath); err != nil {
 					return fmt.Errorf("failed to remove archive %s: %w", archivePath, err)
 				}
+
+				manifestPath := c.buildManifestPath(pName, pVersion)
+				if err = os.RemoveAll(manifestPath); err != nil {
+					return fmt.Errorf("failed to remove manifest %s: %w", manifestPath, err)
+				}
 			}
 		}
 	}
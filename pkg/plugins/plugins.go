@@ -26,6 +26,16 @@ func Setup(client *Client, plugins map[string]Descriptor, devPlugin *DevPlugin)
 	for pAlias, desc := range plugins {
 		log.FromContext(ctx).Debugf("loading of plugin: %s: %s@%s", pAlias, desc.ModuleName, desc.Version)
 
+		if desc.Registry != nil {
+			err = client.Pull(ctx, desc.ModuleName, desc.Version, *desc.Registry)
+			if err != nil {
+				_ = client.ResetAll()
+				return fmt.Errorf("failed to pull plugin %s from %s: %w", desc.ModuleName, desc.Registry.Repository, err)
+			}
+
+			continue
+		}
+
 		hash, err := client.Download(ctx, desc.ModuleName, desc.Version)
 		if err != nil {
 			_ = client.ResetAll()
@@ -81,7 +91,7 @@ func checkDevPluginConfiguration(plugin *DevPlugin) error {
 		return err
 	}
 
-	if m.Type != "middleware" {
+	if m.Type != typeMiddleware && m.Type != typeProvider {
 		return errors.New("unsupported type")
 	}
 
@@ -125,6 +135,16 @@ func checkPluginsConfiguration(plugins map[string]Descriptor) error {
 			errs = append(errs, fmt.Sprintf("%s: plugin version is missing", pAlias))
 		}
 
+		if descriptor.Registry != nil {
+			if descriptor.Registry.Repository == "" {
+				errs = append(errs, fmt.Sprintf("%s: plugin registry repository is missing", pAlias))
+			}
+
+			if !strings.HasPrefix(descriptor.Registry.Digest, digestPrefix) {
+				errs = append(errs, fmt.Sprintf("%s: plugin registry digest must be a %s digest", pAlias, strings.TrimSuffix(digestPrefix, ":")))
+			}
+		}
+
 		if strings.HasPrefix(descriptor.ModuleName, "/") || strings.HasSuffix(descriptor.ModuleName, "/") {
 			errs = append(errs, fmt.Sprintf("%s: plugin name should not start or end with a /", pAlias))
 			continue
@@ -0,0 +1,263 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/yaegi/interp"
+)
+
+const (
+	typeMiddleware = "middleware"
+	typeProvider   = "provider"
+)
+
+// PP the interface of a plugin's provider.
+// The configurations sent on cfgChan are JSON encoded dynamic configurations.
+type PP interface {
+	Init() error
+	Provide(cfgChan chan<- []byte) error
+	Stop() error
+}
+
+// _PP is the interface wrapper used by Yaegi to convert the interpreted providers to PP.
+type _PP struct {
+	WInit    func() error
+	WProvide func(cfgChan chan<- []byte) error
+	WStop    func() error
+}
+
+func (p _PP) Init() error {
+	return p.WInit()
+}
+
+func (p _PP) Provide(cfgChan chan<- []byte) error {
+	return p.WProvide(cfgChan)
+}
+
+func (p _PP) Stop() error {
+	return p.WStop()
+}
+
+func ppSymbols() map[string]map[string]reflect.Value {
+	return map[string]map[string]reflect.Value{
+		"github.com/traefik/traefik/v2/pkg/plugins": {
+			"PP":  reflect.ValueOf((*PP)(nil)),
+			"_PP": reflect.ValueOf((*_PP)(nil)),
+		},
+	}
+}
+
+// providerInstances counts the provider instances created in the interpreters,
+// to give a unique name to their interpreted variables.
+var providerInstances uint64
+
+// Provider is a provider.Provider implemented by a plugin.
+type Provider struct {
+	name             string
+	pp               PP
+	throttleDuration time.Duration
+}
+
+func newProvider(descriptor pluginContext, config map[string]interface{}, providerName string) (*Provider, error) {
+	basePkg := descriptor.BasePkg
+	if basePkg == "" {
+		basePkg = strings.ReplaceAll(path.Base(descriptor.Import), "-", "_")
+	}
+
+	// Each instance has its own configuration, held by an interpreted variable,
+	// and the interpreted provider is converted to PP by an interpreted function,
+	// as Yaegi only exposes the methods of the interpreted types to the interpreted code.
+	instance := atomic.AddUint64(&providerInstances, 1)
+	configVar := fmt.Sprintf("traefikProviderConfig%d", instance)
+	newFunc := fmt.Sprintf("traefikNewProvider%d", instance)
+
+	_, err := descriptor.interpreter.Eval(fmt.Sprintf(`var %s = %s.CreateConfig()`, configVar, basePkg))
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to eval CreateConfig: %w", err)
+	}
+
+	vConfig, err := descriptor.interpreter.Eval(configVar)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to eval CreateConfig: %w", err)
+	}
+
+	cfg := &mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToSliceHookFunc(","),
+		WeaklyTypedInput: true,
+		Result:           vConfig.Interface(),
+	}
+
+	decoder, err := mapstructure.NewDecoder(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to create configuration decoder: %w", err)
+	}
+
+	err = decoder.Decode(config)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to decode configuration: %w", err)
+	}
+
+	_, err = descriptor.interpreter.Eval(fmt.Sprintf(`func %s(ctx context.Context, name string) (plugins.PP, time.Duration, error) {
+	p, err := %s.New(ctx, %s, name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var v interface{} = p
+	var throttleDuration time.Duration
+	if t, ok := v.(interface{ ThrottleDuration() time.Duration }); ok {
+		throttleDuration = t.ThrottleDuration()
+	}
+
+	return p, throttleDuration, nil
+}`, newFunc, basePkg, configVar))
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to eval New: %w", err)
+	}
+
+	fnNew, err := descriptor.interpreter.Eval(newFunc)
+	if err != nil {
+		return nil, fmt.Errorf("plugin: failed to eval New: %w", err)
+	}
+
+	args := []reflect.Value{reflect.ValueOf(context.Background()), reflect.ValueOf(providerName)}
+	results := fnNew.Call(args)
+
+	if results[2].Interface() != nil {
+		return nil, results[2].Interface().(error)
+	}
+
+	pp, ok := results[0].Interface().(PP)
+	if !ok {
+		return nil, fmt.Errorf("plugin: invalid provider type: %T", results[0].Interface())
+	}
+
+	return &Provider{
+		name:             providerName,
+		pp:               pp,
+		throttleDuration: results[1].Interface().(time.Duration),
+	}, nil
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	err := p.pp.Init()
+	if err != nil {
+		return fmt.Errorf("plugin: failed to init provider %s: %w", p.name, err)
+	}
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	cfgChan := make(chan []byte)
+
+	pool.GoCtx(func(ctx context.Context) {
+		logger := log.FromContext(log.With(ctx, log.Str(log.ProviderName, p.name)))
+
+		for {
+			select {
+			case <-ctx.Done():
+				err := p.pp.Stop()
+				if err != nil {
+					logger.Errorf("Failed to stop the provider: %v", err)
+				}
+
+				return
+
+			case rawConf := <-cfgChan:
+				conf := &dynamic.Configuration{}
+				err := json.Unmarshal(rawConf, conf)
+				if err != nil {
+					logger.Errorf("Invalid configuration: %v", err)
+					continue
+				}
+
+				configurationChan <- dynamic.Message{ProviderName: p.name, Configuration: conf}
+			}
+		}
+	})
+
+	err := p.pp.Provide(cfgChan)
+	if err != nil {
+		return fmt.Errorf("plugin: failed to start provider %s: %w", p.name, err)
+	}
+
+	return nil
+}
+
+// ThrottleDuration returns the minimum duration between two configurations of the provider,
+// when the plugin's provider defines a ThrottleDuration method.
+func (p *Provider) ThrottleDuration() time.Duration {
+	return p.throttleDuration
+}
+
+// BuildProvider builds an instance of a provider plugin.
+// The instance name is qualified as plugin-<instanceName>, which is the name of the provider of its configurations.
+func (b Builder) BuildProvider(instanceName string, pName string, config map[string]interface{}) (*Provider, error) {
+	if instanceName == "" || strings.Contains(instanceName, "@") {
+		return nil, fmt.Errorf("plugin: invalid provider instance name %q", instanceName)
+	}
+
+	if b.descriptors == nil {
+		return nil, fmt.Errorf("plugin: no plugin definition in the static configuration: %s", pName)
+	}
+
+	descriptor, ok := b.descriptors[pName]
+	if !ok {
+		return nil, fmt.Errorf("plugin: unknown plugin type: %s", pName)
+	}
+
+	if descriptor.Type != typeProvider {
+		return nil, fmt.Errorf("plugin: %s is not a provider plugin", pName)
+	}
+
+	return newProvider(descriptor, config, "plugin-"+instanceName)
+}
+
+// FindProviderConfig returns the plugin name and the configuration of a provider instance.
+func FindProviderConfig(rawConfig ProviderConf) (string, map[string]interface{}, error) {
+	if len(rawConfig) != 1 {
+		return "", nil, errors.New("plugin: invalid configuration: no configuration or too many plugin definition")
+	}
+
+	var pluginType string
+	var rawPluginConfig map[string]interface{}
+
+	for pType, pConfig := range rawConfig {
+		pluginType = pType
+		rawPluginConfig = pConfig
+	}
+
+	if pluginType == "" {
+		return "", nil, errors.New("plugin: missing plugin type")
+	}
+
+	return pluginType, rawPluginConfig, nil
+}
+
+// importProviderPackages imports the packages used by the functions converting the interpreted providers to PP.
+func importProviderPackages(i *interp.Interpreter) error {
+	i.Use(ppSymbols())
+
+	_, err := i.Eval(`import (
+	"context"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/plugins"
+)`)
+	return err
+}
@@ -0,0 +1,173 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/yaegi/interp"
+	"github.com/traefik/yaegi/stdlib"
+)
+
+const providerSource = `package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Rule     string
+	Service  string
+	Throttle string
+}
+
+func CreateConfig() *Config {
+	return &Config{}
+}
+
+type Provider struct {
+	name   string
+	config *Config
+}
+
+func New(ctx context.Context, config *Config, name string) (*Provider, error) {
+	return &Provider{name: name, config: config}, nil
+}
+
+func (p *Provider) Init() error {
+	return nil
+}
+
+func (p *Provider) Provide(cfgChan chan<- []byte) error {
+	conf := fmt.Sprintf("{\"http\": {\"routers\": {%q: {\"rule\": %q, \"service\": %q}}}}", p.name, p.config.Rule, p.config.Service)
+
+	go func() { cfgChan <- []byte(conf) }()
+
+	return nil
+}
+
+func (p *Provider) Stop() error {
+	return nil
+}
+
+func (p *Provider) ThrottleDuration() time.Duration {
+	d, _ := time.ParseDuration(p.config.Throttle)
+	return d
+}
+`
+
+func newTestBuilder(t *testing.T, pluginType string) Builder {
+	t.Helper()
+
+	i := interp.New(interp.Options{})
+	i.Use(stdlib.Symbols)
+
+	_, err := i.Eval(providerSource)
+	require.NoError(t, err)
+
+	require.NoError(t, importProviderPackages(i))
+
+	return Builder{
+		descriptors: map[string]pluginContext{
+			"demo": {interpreter: i, Import: "github.com/traefik/demo", Type: pluginType},
+		},
+	}
+}
+
+func TestBuilder_BuildProvider(t *testing.T) {
+	builder := newTestBuilder(t, typeProvider)
+
+	foo, err := builder.BuildProvider("foo", "demo", map[string]interface{}{"rule": "Host(`foo.localhost`)", "service": "foo-service", "throttle": "2s"})
+	require.NoError(t, err)
+
+	bar, err := builder.BuildProvider("bar", "demo", map[string]interface{}{"rule": "Host(`bar.localhost`)", "service": "bar-service"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Second, foo.ThrottleDuration())
+	assert.Equal(t, time.Duration(0), bar.ThrottleDuration())
+
+	pool := safe.NewPool(context.Background())
+	defer pool.Stop()
+
+	expected := map[string]dynamic.Router{
+		"plugin-foo": {Rule: "Host(`foo.localhost`)", Service: "foo-service"},
+		"plugin-bar": {Rule: "Host(`bar.localhost`)", Service: "bar-service"},
+	}
+
+	for _, p := range []*Provider{foo, bar} {
+		require.NoError(t, p.Init())
+
+		configurationChan := make(chan dynamic.Message)
+		require.NoError(t, p.Provide(configurationChan, pool))
+
+		select {
+		case msg := <-configurationChan:
+			require.NotNil(t, msg.Configuration.HTTP)
+
+			router := msg.Configuration.HTTP.Routers[msg.ProviderName]
+			require.NotNil(t, router)
+			assert.Equal(t, expected[msg.ProviderName], *router)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the configuration")
+		}
+	}
+}
+
+func TestBuilder_BuildProvider_errors(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		pluginType   string
+		instanceName string
+		pName        string
+	}{
+		{
+			desc:         "middleware plugin",
+			pluginType:   typeMiddleware,
+			instanceName: "foo",
+			pName:        "demo",
+		},
+		{
+			desc:         "unknown plugin",
+			pluginType:   typeProvider,
+			instanceName: "foo",
+			pName:        "unknown",
+		},
+		{
+			desc:         "qualified instance name",
+			pluginType:   typeProvider,
+			instanceName: "foo@bar",
+			pName:        "demo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			builder := newTestBuilder(t, test.pluginType)
+
+			_, err := builder.BuildProvider(test.instanceName, test.pName, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFindProviderConfig(t *testing.T) {
+	pName, pConf, err := FindProviderConfig(ProviderConf{"demo": {"service": "foo"}})
+	require.NoError(t, err)
+	assert.Equal(t, "demo", pName)
+	assert.Equal(t, map[string]interface{}{"service": "foo"}, pConf)
+
+	_, _, err = FindProviderConfig(ProviderConf{"demo": {}, "other": {}})
+	require.Error(t, err)
+
+	_, _, err = FindProviderConfig(ProviderConf{})
+	require.Error(t, err)
+}
@@ -0,0 +1,363 @@
+package plugins
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	pluginLayerMediaType = "application/vnd.traefik.plugin.layer.v1+zip"
+	zipLayerMediaType    = "application/zip"
+
+	digestPrefix = "sha256:"
+)
+
+type imageManifest struct {
+	MediaType string            `json:"mediaType"`
+	Layers    []imageDescriptor `json:"layers"`
+}
+
+type imageDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Pull pulls a plugin archive from an OCI registry, and checks it against the pinned digest.
+// Nothing is pulled when the archive matching the pinned digest has already been pulled,
+// which allows to start without any access to the registry.
+func (c *Client) Pull(ctx context.Context, pName, pVersion string, registry Registry) error {
+	manifestPath := c.buildManifestPath(pName, pVersion)
+	archivePath := c.buildArchivePath(pName, pVersion)
+
+	if err := checkPulledArchive(manifestPath, archivePath, registry.Digest); err == nil {
+		return nil
+	}
+
+	rc, err := newRegistryClient(c.HTTPClient, registry)
+	if err != nil {
+		return err
+	}
+
+	rawManifest, err := rc.fetch(ctx, "manifests/"+registry.Digest, ociManifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	if digest := computeDigest(rawManifest); digest != registry.Digest {
+		return fmt.Errorf("manifest digest mismatch: expected %s, got %s", registry.Digest, digest)
+	}
+
+	layer, err := findPluginLayer(rawManifest)
+	if err != nil {
+		return err
+	}
+
+	archive, err := rc.fetch(ctx, "blobs/"+layer.Digest, "")
+	if err != nil {
+		return fmt.Errorf("failed to fetch layer %s: %w", layer.Digest, err)
+	}
+
+	if digest := computeDigest(archive); digest != layer.Digest {
+		return fmt.Errorf("layer digest mismatch: expected %s, got %s", layer.Digest, digest)
+	}
+
+	err = os.MkdirAll(filepath.Dir(archivePath), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	err = ioutil.WriteFile(archivePath, archive, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write archive %s: %w", archivePath, err)
+	}
+
+	// The manifest is written last, as it marks the archive as pulled.
+	err = ioutil.WriteFile(manifestPath, rawManifest, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", manifestPath, err)
+	}
+
+	return nil
+}
+
+func (c *Client) buildManifestPath(pName, pVersion string) string {
+	return filepath.Join(c.archives, filepath.FromSlash(pName), pVersion+".manifest.json")
+}
+
+// checkPulledArchive checks that the pulled manifest matches the digest, and the pulled archive matches the manifest.
+func checkPulledArchive(manifestPath, archivePath, digest string) error {
+	rawManifest, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if computeDigest(rawManifest) != digest {
+		return errors.New("manifest digest mismatch")
+	}
+
+	layer, err := findPluginLayer(rawManifest)
+	if err != nil {
+		return err
+	}
+
+	hash, err := computeHash(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if digestPrefix+hash != layer.Digest {
+		return errors.New("archive digest mismatch")
+	}
+
+	return nil
+}
+
+func findPluginLayer(rawManifest []byte) (*imageDescriptor, error) {
+	var manifest imageManifest
+	if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == pluginLayerMediaType || layer.MediaType == zipLayerMediaType {
+			layer := layer
+			return &layer, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no layer of type %s in the manifest", pluginLayerMediaType)
+}
+
+func computeDigest(data []byte) string {
+	return fmt.Sprintf("%s%x", digestPrefix, sha256.Sum256(data))
+}
+
+// registryClient is a client of the OCI distribution API,
+// supporting the basic and the bearer token authentications.
+type registryClient struct {
+	httpClient *http.Client
+	baseURL    string
+	repository string
+	username   string
+	password   string
+
+	authorization string
+}
+
+func newRegistryClient(httpClient *http.Client, registry Registry) (*registryClient, error) {
+	parts := strings.SplitN(registry.Repository, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid repository %q: the registry host is missing", registry.Repository)
+	}
+
+	scheme := "https"
+	if registry.PlainHTTP {
+		scheme = "http"
+	}
+
+	return &registryClient{
+		httpClient: httpClient,
+		baseURL:    fmt.Sprintf("%s://%s/v2/%s/", scheme, parts[0], parts[1]),
+		repository: parts[1],
+		username:   registry.Username,
+		password:   registry.Password,
+	}, nil
+}
+
+// fetch gets the given resource of the repository, authenticating when requested by the registry.
+func (r *registryClient) fetch(ctx context.Context, resource, accept string) ([]byte, error) {
+	resp, err := r.do(ctx, r.baseURL+resource, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && r.authorization == "" {
+		_ = resp.Body.Close()
+
+		r.authorization, err = r.authorize(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+
+		resp, err = r.do(ctx, r.baseURL+resource, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error: %d: %s", resp.StatusCode, string(data))
+	}
+
+	return data, nil
+}
+
+func (r *registryClient) do(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if r.authorization != "" {
+		req.Header.Set("Authorization", r.authorization)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call registry: %w", err)
+	}
+
+	return resp, nil
+}
+
+// authorize answers the authentication challenge of the registry, and returns the Authorization header value to use.
+func (r *registryClient) authorize(ctx context.Context, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if r.username == "" && r.password == "" {
+			return "", errors.New("the registry requires credentials")
+		}
+
+		req := &http.Request{Header: make(http.Header)}
+		req.SetBasicAuth(r.username, r.password)
+
+		return req.Header.Get("Authorization"), nil
+
+	case "bearer":
+		return r.fetchToken(ctx, params)
+
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge: %q", challenge)
+	}
+}
+
+// fetchToken gets a bearer token from the authorization service of the registry.
+func (r *registryClient) fetchToken(ctx context.Context, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + r.repository + ":pull"
+	}
+
+	query := realm.Query()
+	query.Set("scope", scope)
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if r.username != "" || r.password != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call token service: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("error: %d: %s", resp.StatusCode, string(data))
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	if token.Token == "" {
+		return "", errors.New("empty token")
+	}
+
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header value, such as:
+// Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo:pull".
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+
+	scheme, rest := challenge, ""
+	if i := strings.IndexByte(challenge, ' '); i >= 0 {
+		scheme, rest = challenge[:i], challenge[i+1:]
+	}
+
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+
+		i := strings.IndexByte(rest, '=')
+		if i < 0 {
+			break
+		}
+
+		key := strings.ToLower(strings.TrimSpace(rest[:i]))
+		rest = rest[i+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end+1:]
+			}
+		}
+
+		params[key] = strings.TrimSpace(value)
+	}
+
+	return strings.ToLower(scheme), params
+}
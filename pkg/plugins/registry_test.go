@@ -0,0 +1,192 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Pull(t *testing.T) {
+	archive := []byte("plugin archive")
+	manifest, err := json.Marshal(imageManifest{
+		MediaType: ociManifestMediaType,
+		Layers: []imageDescriptor{
+			{MediaType: "application/vnd.oci.image.config.v1+json", Digest: computeDigest([]byte("{}")), Size: 2},
+			{MediaType: pluginLayerMediaType, Digest: computeDigest(archive), Size: int64(len(archive))},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc        string
+		digest      string
+		username    string
+		password    string
+		expectedErr bool
+	}{
+		{
+			desc:     "pinned digest",
+			digest:   computeDigest(manifest),
+			username: "user",
+			password: "secret",
+		},
+		{
+			desc:        "digest mismatch",
+			digest:      computeDigest([]byte("foo")),
+			username:    "user",
+			password:    "secret",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid credentials",
+			digest:      computeDigest(manifest),
+			username:    "user",
+			password:    "foo",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(newTestRegistry(t, "plugins/demo", manifest, archive))
+
+			client := &Client{HTTPClient: server.Client(), archives: t.TempDir()}
+
+			registry := Registry{
+				Repository: strings.TrimPrefix(server.URL, "http://") + "/plugins/demo",
+				Digest:     test.digest,
+				Username:   test.username,
+				Password:   test.password,
+				PlainHTTP:  true,
+			}
+
+			err := client.Pull(context.Background(), "github.com/traefik/demo", "v0.1.0", registry)
+			if test.expectedErr {
+				server.Close()
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			content, err := ioutil.ReadFile(client.buildArchivePath("github.com/traefik/demo", "v0.1.0"))
+			require.NoError(t, err)
+			assert.Equal(t, archive, content)
+
+			// The archive has been pulled, the registry is not needed anymore.
+			server.Close()
+
+			err = client.Pull(context.Background(), "github.com/traefik/demo", "v0.1.0", registry)
+			require.NoError(t, err)
+		})
+	}
+}
+
+// newTestRegistry creates an OCI registry serving a single image, behind a bearer token authentication.
+func newTestRegistry(t *testing.T, repository string, manifest, archive []byte) http.Handler {
+	t.Helper()
+
+	const token = "token"
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "repository:"+repository+":pull", req.URL.Query().Get("scope"))
+		assert.Equal(t, "test-registry", req.URL.Query().Get("service"))
+
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = fmt.Fprintf(rw, `{"token": %q}`, token)
+	})
+
+	mux.HandleFunc("/v2/", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="test-registry",scope="repository:%s:pull"`, req.Host, repository))
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/v2/" + repository + "/manifests/" + computeDigest(manifest):
+			assert.Contains(t, req.Header.Get("Accept"), ociManifestMediaType)
+
+			rw.Header().Set("Content-Type", ociManifestMediaType)
+			_, _ = rw.Write(manifest)
+
+		case "/v2/" + repository + "/blobs/" + computeDigest(archive):
+			_, _ = rw.Write(archive)
+
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	return mux
+}
+
+func TestParseChallenge(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		challenge      string
+		expectedScheme string
+		expectedParams map[string]string
+	}{
+		{
+			desc:           "bearer",
+			challenge:      `Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo/bar:pull,push"`,
+			expectedScheme: "bearer",
+			expectedParams: map[string]string{
+				"realm":   "https://auth.example.com/token",
+				"service": "registry.example.com",
+				"scope":   "repository:foo/bar:pull,push",
+			},
+		},
+		{
+			desc:           "basic",
+			challenge:      `Basic realm="Registry"`,
+			expectedScheme: "basic",
+			expectedParams: map[string]string{"realm": "Registry"},
+		},
+		{
+			desc:           "unquoted values",
+			challenge:      `Bearer realm=https://auth.example.com/token, service=registry`,
+			expectedScheme: "bearer",
+			expectedParams: map[string]string{
+				"realm":   "https://auth.example.com/token",
+				"service": "registry",
+			},
+		},
+		{
+			desc:           "no parameters",
+			challenge:      `Basic`,
+			expectedScheme: "basic",
+			expectedParams: map[string]string{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			scheme, params := parseChallenge(test.challenge)
+			assert.Equal(t, test.expectedScheme, scheme)
+			assert.Equal(t, test.expectedParams, params)
+		})
+	}
+}
@@ -7,6 +7,25 @@ type Descriptor struct {
 
 	// Version (required)
 	Version string `description:"plugin's version." json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+
+	// Registry the OCI registry to pull the plugin from, instead of the Traefik Pilot catalog (optional).
+	Registry *Registry `description:"OCI registry to pull the plugin from." json:"registry,omitempty" toml:"registry,omitempty" yaml:"registry,omitempty" export:"true"`
+}
+
+// Registry The OCI registry hosting a plugin.
+// The plugin image has a single layer holding the plugin archive, in the same format as the Traefik Pilot catalog one.
+type Registry struct {
+	// Repository plugin's image repository, including the registry host (e.g. registry.example.com/plugins/demo). (required)
+	Repository string `description:"plugin's image repository, including the registry host." json:"repository,omitempty" toml:"repository,omitempty" yaml:"repository,omitempty" export:"true"`
+
+	// Digest plugin's image manifest digest (e.g. sha256:...), pinning the plugin. (required)
+	Digest string `description:"plugin's image manifest digest." json:"digest,omitempty" toml:"digest,omitempty" yaml:"digest,omitempty" export:"true"`
+
+	Username string `description:"Username used to authenticate to the registry." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password string `description:"Password used to authenticate to the registry." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+
+	// PlainHTTP allows to pull from a registry served over HTTP.
+	PlainHTTP bool `description:"Pull from the registry over HTTP." json:"plainHTTP,omitempty" toml:"plainHTTP,omitempty" yaml:"plainHTTP,omitempty" export:"true"`
 }
 
 // DevPlugin The static part of a plugin configuration (only for dev).
@@ -28,3 +47,7 @@ type Manifest struct {
 	Summary       string                 `yaml:"summary"`
 	TestData      map[string]interface{} `yaml:"testData"`
 }
+
+// ProviderConf The static configuration of a provider plugin instance.
+// It has a single entry, keyed by the name of the plugin, holding the configuration of the plugin.
+type ProviderConf map[string]map[string]interface{}
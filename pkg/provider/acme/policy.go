@@ -0,0 +1,189 @@
+package acme
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// maxCertificateDomains is the maximum number of domains of a certificate issued by Let's Encrypt.
+const maxCertificateDomains = 100
+
+// Policy selects the hosts handled by a resolver, and groups them into certificates.
+type Policy struct {
+	Suffixes  []string `description:"Host suffixes for which the resolver is selected, for the routers without certificate resolver." json:"suffixes,omitempty" toml:"suffixes,omitempty" yaml:"suffixes,omitempty" export:"true"`
+	MaxSANs   int      `description:"Merges the hosts of the routers into certificates holding up to this number of domains." json:"maxSANs,omitempty" toml:"maxSANs,omitempty" yaml:"maxSANs,omitempty" export:"true"`
+	Wildcards []string `description:"Domains whose subdomains are covered by a wildcard certificate, instead of a certificate per host." json:"wildcards,omitempty" toml:"wildcards,omitempty" yaml:"wildcards,omitempty" export:"true"`
+}
+
+// validate checks the policy, knowing whether the resolver uses the DNS challenge.
+func (p *Policy) validate(dnsChallenge bool) error {
+	if p == nil {
+		return nil
+	}
+
+	if p.MaxSANs < 0 || p.MaxSANs > maxCertificateDomains {
+		return fmt.Errorf("the maximum number of domains of a certificate must be between 0 and %d", maxCertificateDomains)
+	}
+
+	if len(p.Wildcards) > 0 && !dnsChallenge {
+		return errors.New("the wildcard certificates need a DNSChallenge")
+	}
+
+	for _, domain := range p.Wildcards {
+		if strings.Contains(domain, "*") {
+			return fmt.Errorf("the wildcard domain %q must be given without its wildcard label", domain)
+		}
+	}
+
+	return nil
+}
+
+// mergesHosts reports whether the hosts of the routers are merged into certificates.
+func (p *Policy) mergesHosts() bool {
+	return p != nil && p.MaxSANs > 0
+}
+
+// wildcardHosts replaces the hosts which are direct subdomains of the wildcard domains by the matching wildcard,
+// and removes the duplicates.
+func (p *Policy) wildcardHosts(hosts []string) []string {
+	if p == nil || len(p.Wildcards) == 0 {
+		return hosts
+	}
+
+	seen := make(map[string]struct{})
+
+	var result []string
+	for _, host := range hosts {
+		host = p.wildcardHost(host)
+		if _, ok := seen[host]; ok {
+			continue
+		}
+
+		seen[host] = struct{}{}
+		result = append(result, host)
+	}
+
+	return result
+}
+
+func (p *Policy) wildcardHost(host string) string {
+	labels := strings.SplitN(types.CanonicalDomain(host), ".", 2)
+	if len(labels) != 2 || labels[0] == "*" {
+		return host
+	}
+
+	for _, domain := range p.Wildcards {
+		if labels[1] == types.CanonicalDomain(domain) {
+			return "*." + labels[1]
+		}
+	}
+
+	return host
+}
+
+// mergeHosts groups the hosts into domains holding up to MaxSANs hosts, removing the duplicates.
+// The hosts are sorted, so that the same hosts are grouped the same way, and the wildcards are the main domains.
+func (p *Policy) mergeHosts(hosts []string) []types.Domain {
+	seen := make(map[string]struct{})
+
+	var sorted []string
+	for _, host := range hosts {
+		if _, ok := seen[host]; ok {
+			continue
+		}
+
+		seen[host] = struct{}{}
+		sorted = append(sorted, host)
+	}
+
+	sort.Strings(sorted)
+
+	var domains []types.Domain
+	for len(sorted) > 0 {
+		size := p.MaxSANs
+		if size > len(sorted) {
+			size = len(sorted)
+		}
+
+		domain := types.Domain{Main: sorted[0]}
+		if size > 1 {
+			domain.SANs = sorted[1:size]
+		}
+
+		domains = append(domains, domain)
+		sorted = sorted[size:]
+	}
+
+	return domains
+}
+
+// ResolverSelector selects the resolver of the hosts of the routers without certificate resolver,
+// by the longest suffix of the hosts among the suffixes of the resolver policies.
+type ResolverSelector struct {
+	suffixes map[string]string
+}
+
+// NewResolverSelector creates a new ResolverSelector from the policies of the resolvers, keyed by resolver name.
+func NewResolverSelector(policies map[string]*Policy) (*ResolverSelector, error) {
+	suffixes := make(map[string]string)
+	for name, policy := range policies {
+		if policy == nil {
+			continue
+		}
+
+		for _, suffix := range policy.Suffixes {
+			suffix = normalizeSuffix(suffix)
+			if other, ok := suffixes[suffix]; ok && other != name {
+				return nil, fmt.Errorf("the host suffix %q is selected by both the resolvers %q and %q", suffix, other, name)
+			}
+
+			suffixes[suffix] = name
+		}
+	}
+
+	return &ResolverSelector{suffixes: suffixes}, nil
+}
+
+// Select returns the name of the resolver selected for the host, or an empty string when no suffix matches.
+func (s *ResolverSelector) Select(host string) string {
+	if s == nil {
+		return ""
+	}
+
+	host = strings.TrimPrefix(types.CanonicalDomain(host), "*.")
+	for {
+		if name, ok := s.suffixes[host]; ok {
+			return name
+		}
+
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return ""
+		}
+
+		host = host[i+1:]
+	}
+}
+
+// selects reports whether the resolver is selected for some suffixes.
+func (s *ResolverSelector) selects(name string) bool {
+	if s == nil {
+		return false
+	}
+
+	for _, resolver := range s.suffixes {
+		if resolver == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeSuffix(suffix string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(types.CanonicalDomain(suffix), "*"), ".")
+}
@@ -0,0 +1,143 @@
+package acme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestPolicy_validate(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		policy       *Policy
+		dnsChallenge bool
+		expectedErr  bool
+	}{
+		{
+			desc: "no policy",
+		},
+		{
+			desc:   "merged hosts",
+			policy: &Policy{MaxSANs: 100},
+		},
+		{
+			desc:        "too many merged hosts",
+			policy:      &Policy{MaxSANs: 101},
+			expectedErr: true,
+		},
+		{
+			desc:         "wildcards with DNS challenge",
+			policy:       &Policy{Wildcards: []string{"example.com"}},
+			dnsChallenge: true,
+		},
+		{
+			desc:        "wildcards without DNS challenge",
+			policy:      &Policy{Wildcards: []string{"example.com"}},
+			expectedErr: true,
+		},
+		{
+			desc:         "wildcard domain with wildcard label",
+			policy:       &Policy{Wildcards: []string{"*.example.com"}},
+			dnsChallenge: true,
+			expectedErr:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.policy.validate(test.dnsChallenge)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPolicy_wildcardHosts(t *testing.T) {
+	policy := &Policy{Wildcards: []string{"example.com", "Example.org"}}
+
+	hosts := policy.wildcardHosts([]string{
+		"a.example.com",
+		"b.example.com",
+		"example.com",
+		"a.b.example.com",
+		"*.example.org",
+		"a.example.org",
+		"a.example.net",
+	})
+
+	assert.Equal(t, []string{"*.example.com", "example.com", "a.b.example.com", "*.example.org", "a.example.net"}, hosts)
+}
+
+func TestPolicy_mergeHosts(t *testing.T) {
+	policy := &Policy{MaxSANs: 2}
+
+	domains := policy.mergeHosts([]string{"c.example.com", "a.example.com", "*.example.org", "b.example.com", "a.example.com"})
+
+	expected := []types.Domain{
+		{Main: "*.example.org", SANs: []string{"a.example.com"}},
+		{Main: "b.example.com", SANs: []string{"c.example.com"}},
+	}
+	assert.Equal(t, expected, domains)
+}
+
+func TestResolverSelector(t *testing.T) {
+	selector, err := NewResolverSelector(map[string]*Policy{
+		"public":   {Suffixes: []string{"example.com"}},
+		"internal": {Suffixes: []string{"*.internal.example.com", ".corp"}},
+		"staging":  nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "public", selector.Select("example.com"))
+	assert.Equal(t, "public", selector.Select("www.Example.com"))
+	assert.Equal(t, "public", selector.Select("*.example.com"))
+	assert.Equal(t, "internal", selector.Select("internal.example.com"))
+	assert.Equal(t, "internal", selector.Select("a.internal.example.com"))
+	assert.Equal(t, "internal", selector.Select("a.corp"))
+	assert.Equal(t, "", selector.Select("example.org"))
+	assert.Equal(t, "", selector.Select("anotherexample.com"))
+
+	assert.True(t, selector.selects("internal"))
+	assert.False(t, selector.selects("staging"))
+}
+
+func TestNewResolverSelector_conflict(t *testing.T) {
+	_, err := NewResolverSelector(map[string]*Policy{
+		"public":   {Suffixes: []string{"example.com"}},
+		"internal": {Suffixes: []string{"*.example.com"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestProvider_selectHosts(t *testing.T) {
+	selector, err := NewResolverSelector(map[string]*Policy{
+		"public":   {Suffixes: []string{"example.com"}},
+		"internal": {Suffixes: []string{"internal.example.com"}},
+	})
+	require.NoError(t, err)
+
+	p := &Provider{ResolverName: "public", Selector: selector}
+
+	assert.True(t, p.handlesRouter(""))
+	assert.True(t, p.handlesRouter("public"))
+	assert.False(t, p.handlesRouter("internal"))
+
+	hosts := []string{"www.example.com", "a.internal.example.com", "example.org"}
+	assert.Equal(t, []string{"www.example.com"}, p.selectHosts("", hosts))
+	assert.Equal(t, hosts, p.selectHosts("public", hosts))
+
+	domains := []types.Domain{{Main: "www.example.com"}, {Main: "a.internal.example.com", SANs: []string{"www.example.com"}}}
+	assert.Equal(t, []types.Domain{{Main: "www.example.com"}}, p.selectDomains("", domains))
+
+	p = &Provider{ResolverName: "staging", Selector: selector}
+	assert.False(t, p.handlesRouter(""))
+}
@@ -21,6 +21,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
@@ -40,6 +41,8 @@ type Configuration struct {
 	KeyType        string `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
 	EAB            *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
 
+	Policy *Policy `description:"Selection of the hosts handled by the resolver, and grouping of the hosts into certificates." json:"policy,omitempty" toml:"policy,omitempty" yaml:"policy,omitempty" export:"true"`
+
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -92,6 +95,8 @@ type Provider struct {
 	*Configuration
 	ResolverName string
 	Store        Store `json:"store,omitempty" toml:"store,omitempty" yaml:"store,omitempty"`
+	// Selector selects the resolver of the hosts of the routers without certificate resolver.
+	Selector *ResolverSelector
 
 	TLSChallengeProvider  challenge.Provider
 	HTTPChallengeProvider challenge.Provider
@@ -107,6 +112,7 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	synced                 provider.SyncState
 }
 
 // SetTLSManager sets the tls manager to use.
@@ -133,6 +139,10 @@ func (p *Provider) Init() error {
 		return errors.New("unable to initialize ACME provider with no storage location for the certificates")
 	}
 
+	if err := p.Policy.validate(p.DNSChallenge != nil); err != nil {
+		return fmt.Errorf("invalid ACME policy: %w", err)
+	}
+
 	var err error
 	p.account, err = p.Store.GetAccount(p.ResolverName)
 	if err != nil {
@@ -174,6 +184,11 @@ func isAccountMatchingCaServer(ctx context.Context, accountURI, serverURI string
 	return cau.Hostname() == aru.Hostname()
 }
 
+// Synced returns the name of the provider, and a channel closed once the certificates loaded from the store have been provided.
+func (p *Provider) Synced() (string, <-chan struct{}) {
+	return p.ResolverName + ".acme", p.synced.Done()
+}
+
 // Provide allows the file provider to provide configurations to traefik
 // using the given Configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -186,6 +201,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 	p.configurationChan = configurationChan
 	p.refreshCertificates()
+	p.synced.MarkSynced()
 
 	p.renewCertificates(ctx)
 
@@ -374,9 +390,12 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 		for {
 			select {
 			case config := <-p.configFromListenerChan:
+				// The hosts merged into certificates by the policy.
+				var mergedHosts []string
+
 				if config.TCP != nil {
 					for routerName, route := range config.TCP.Routers {
-						if route.TLS == nil || route.TLS.CertResolver != p.ResolverName {
+						if route.TLS == nil || !p.handlesRouter(route.TLS.CertResolver) {
 							continue
 						}
 
@@ -396,7 +415,7 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 								}
 							}
 
-							domains := deleteUnnecessaryDomains(ctxRouter, route.TLS.Domains)
+							domains := deleteUnnecessaryDomains(ctxRouter, p.selectDomains(route.TLS.CertResolver, route.TLS.Domains))
 							for i := 0; i < len(domains); i++ {
 								domain := domains[i]
 								safe.Go(func() {
@@ -412,20 +431,26 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 								logger.Errorf("Error parsing domains in provider ACME: %v", err)
 								continue
 							}
+
+							domains = p.Policy.wildcardHosts(p.selectHosts(route.TLS.CertResolver, domains))
+							if p.Policy.mergesHosts() {
+								mergedHosts = append(mergedHosts, domains...)
+								continue
+							}
 							p.resolveDomains(ctxRouter, domains, tlsStore)
 						}
 					}
 				}
 
 				for routerName, route := range config.HTTP.Routers {
-					if route.TLS == nil || route.TLS.CertResolver != p.ResolverName {
+					if route.TLS == nil || !p.handlesRouter(route.TLS.CertResolver) {
 						continue
 					}
 					ctxRouter := log.With(ctx, log.Str(log.RouterName, routerName), log.Str(log.Rule, route.Rule))
 
 					tlsStore := "default"
 					if len(route.TLS.Domains) > 0 {
-						domains := deleteUnnecessaryDomains(ctxRouter, route.TLS.Domains)
+						domains := deleteUnnecessaryDomains(ctxRouter, p.selectDomains(route.TLS.CertResolver, route.TLS.Domains))
 						for i := 0; i < len(domains); i++ {
 							domain := domains[i]
 							safe.Go(func() {
@@ -441,9 +466,17 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 							log.FromContext(ctxRouter).Errorf("Error parsing domains in provider ACME: %v", err)
 							continue
 						}
+
+						domains = p.Policy.wildcardHosts(p.selectHosts(route.TLS.CertResolver, domains))
+						if p.Policy.mergesHosts() {
+							mergedHosts = append(mergedHosts, domains...)
+							continue
+						}
 						p.resolveDomains(ctxRouter, domains, tlsStore)
 					}
 				}
+
+				p.resolveMergedHosts(ctx, mergedHosts, "default")
 			case <-ctxPool.Done():
 				return
 			}
@@ -451,6 +484,61 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 	})
 }
 
+// handlesRouter reports whether the resolver handles hosts of the routers with the given certificate resolver:
+// all the hosts of the routers using it, and the hosts selected by the policies for the routers without certificate resolver.
+func (p *Provider) handlesRouter(certResolver string) bool {
+	return certResolver == p.ResolverName || certResolver == "" && p.Selector.selects(p.ResolverName)
+}
+
+// selectHosts returns the hosts of a router handled by the resolver.
+func (p *Provider) selectHosts(certResolver string, hosts []string) []string {
+	if certResolver == p.ResolverName {
+		return hosts
+	}
+
+	var selected []string
+	for _, host := range hosts {
+		if p.Selector.Select(host) == p.ResolverName {
+			selected = append(selected, host)
+		}
+	}
+
+	return selected
+}
+
+// selectDomains returns the domains of a router handled by the resolver, selected by their main domain.
+func (p *Provider) selectDomains(certResolver string, domains []types.Domain) []types.Domain {
+	if certResolver == p.ResolverName {
+		return domains
+	}
+
+	var selected []types.Domain
+	for _, domain := range domains {
+		if p.Selector.Select(domain.Main) == p.ResolverName {
+			selected = append(selected, domain)
+		}
+	}
+
+	return selected
+}
+
+// resolveMergedHosts obtains the certificates of the hosts which are not covered yet by a certificate,
+// merging them into certificates according to the policy.
+func (p *Provider) resolveMergedHosts(ctx context.Context, hosts []string, tlsStore string) {
+	if len(hosts) == 0 {
+		return
+	}
+
+	for _, domain := range p.Policy.mergeHosts(p.getUncoveredDomains(ctx, hosts, tlsStore)) {
+		domain := domain
+		safe.Go(func() {
+			if _, err := p.resolveCertificate(ctx, domain, tlsStore); err != nil {
+				log.FromContext(ctx).Errorf("Unable to obtain ACME certificate for domains %q: %v", strings.Join(domain.ToStrArray(), ","), err)
+			}
+		})
+	}
+}
+
 func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain, tlsStore string) (*certificate.Resource, error) {
 	domains, err := p.getValidDomains(ctx, domain)
 	if err != nil {
@@ -681,6 +769,28 @@ func (p *Provider) getUncheckedDomains(ctx context.Context, domainsToCheck []str
 
 	log.FromContext(ctx).Debugf("Looking for provided certificate(s) to validate %q...", domainsToCheck)
 
+	uncheckedDomains := searchUncheckedDomains(ctx, domainsToCheck, p.getExistentDomains(tlsStore))
+
+	// Lock domains that will be resolved by this routine
+	for _, domain := range uncheckedDomains {
+		p.resolvingDomains[domain] = struct{}{}
+	}
+
+	return uncheckedDomains
+}
+
+// getUncoveredDomains returns the domains which are not covered by the provided and the ACME certificates,
+// and which are not being resolved.
+func (p *Provider) getUncoveredDomains(ctx context.Context, domainsToCheck []string, tlsStore string) []string {
+	p.resolvingDomainsMutex.RLock()
+	defer p.resolvingDomainsMutex.RUnlock()
+
+	return searchUncheckedDomains(ctx, domainsToCheck, p.getExistentDomains(tlsStore))
+}
+
+// getExistentDomains returns the domains of the provided and the ACME certificates, and the ones being resolved.
+// The caller must hold the resolving domains lock.
+func (p *Provider) getExistentDomains(tlsStore string) []string {
 	allDomains := p.tlsManager.GetStore(tlsStore).GetAllDomains()
 
 	// Get ACME certificates
@@ -693,14 +803,7 @@ func (p *Provider) getUncheckedDomains(ctx context.Context, domainsToCheck []str
 		allDomains = append(allDomains, domain)
 	}
 
-	uncheckedDomains := searchUncheckedDomains(ctx, domainsToCheck, allDomains)
-
-	// Lock domains that will be resolved by this routine
-	for _, domain := range uncheckedDomains {
-		p.resolvingDomains[domain] = struct{}{}
-	}
-
-	return uncheckedDomains
+	return allDomains
 }
 
 func searchUncheckedDomains(ctx context.Context, domainsToCheck, existentDomains []string) []string {
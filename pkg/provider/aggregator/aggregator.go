@@ -1,8 +1,11 @@
 package aggregator
 
 import (
+	"context"
 	"encoding/json"
+	"time"
 
+	"github.com/eapache/channels"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -11,6 +14,11 @@ import (
 	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
+// throttled defines what kind of config refresh throttling the aggregator should take care of for a provider.
+type throttled interface {
+	ThrottleDuration() time.Duration
+}
+
 // ProviderAggregator aggregates providers.
 type ProviderAggregator struct {
 	fileProvider *file.Provider
@@ -49,6 +57,10 @@ func NewProviderAggregator(conf static.Providers) ProviderAggregator {
 		p.quietAddProvider(conf.KubernetesGateway)
 	}
 
+	if conf.KubernetesService != nil {
+		p.quietAddProvider(conf.KubernetesService)
+	}
+
 	if conf.Rancher != nil {
 		p.quietAddProvider(conf.Rancher)
 	}
@@ -61,6 +73,14 @@ func NewProviderAggregator(conf static.Providers) ProviderAggregator {
 		p.quietAddProvider(conf.ConsulCatalog)
 	}
 
+	if conf.Local != nil {
+		p.quietAddProvider(conf.Local)
+	}
+
+	if conf.Inventory != nil {
+		p.quietAddProvider(conf.Inventory)
+	}
+
 	if conf.Consul != nil {
 		p.quietAddProvider(conf.Consul)
 	}
@@ -106,6 +126,18 @@ func (p *ProviderAggregator) AddProvider(provider provider.Provider) error {
 	return nil
 }
 
+// Syncers returns the providers which report when their initial state has been loaded.
+func (p ProviderAggregator) Syncers() []provider.Syncer {
+	var syncers []provider.Syncer
+	for _, prd := range p.providers {
+		if s, ok := prd.(provider.Syncer); ok {
+			syncers = append(syncers, s)
+		}
+	}
+
+	return syncers
+}
+
 // Init the provider.
 func (p ProviderAggregator) Init() error {
 	return nil
@@ -134,9 +166,51 @@ func launchProvider(configurationChan chan<- dynamic.Message, pool *safe.Pool, p
 
 	log.WithoutContext().Infof("Starting provider %T %s", prd, jsonConf)
 
+	currentConfigurationChan := configurationChan
+	if t, ok := prd.(throttled); ok && t.ThrottleDuration() > 0 {
+		// The provider asks for its configurations to be throttled:
+		// only the latest one is sent, at most once per throttle duration.
+		throttledChan := make(chan dynamic.Message)
+		pool.GoCtx(func(ctx context.Context) {
+			throttle(ctx, t.ThrottleDuration(), throttledChan, configurationChan)
+		})
+
+		currentConfigurationChan = throttledChan
+	}
+
 	currentProvider := prd
-	err = currentProvider.Provide(configurationChan, pool)
+	err = currentProvider.Provide(currentConfigurationChan, pool)
 	if err != nil {
 		log.WithoutContext().Errorf("Cannot start the provider %T: %v", prd, err)
 	}
 }
+
+func throttle(ctx context.Context, throttleDuration time.Duration, in <-chan dynamic.Message, out chan<- dynamic.Message) {
+	ring := channels.NewRingChannel(1)
+	defer ring.Close()
+
+	go func() {
+		for nextConfig := range ring.Out() {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- nextConfig.(dynamic.Message):
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(throttleDuration):
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case nextConfig := <-in:
+			ring.In() <- nextConfig
+		}
+	}
+}
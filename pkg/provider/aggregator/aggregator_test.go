@@ -0,0 +1,49 @@
+package aggregator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestThrottle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan dynamic.Message)
+	out := make(chan dynamic.Message)
+	go throttle(ctx, 200*time.Millisecond, in, out)
+
+	in <- dynamic.Message{ProviderName: "first"}
+
+	select {
+	case msg := <-out:
+		assert.Equal(t, "first", msg.ProviderName)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the first configuration")
+	}
+
+	// The configurations received during the throttle duration are replaced by the latest one.
+	in <- dynamic.Message{ProviderName: "second"}
+	in <- dynamic.Message{ProviderName: "third"}
+
+	start := time.Now()
+
+	select {
+	case msg := <-out:
+		assert.Equal(t, "third", msg.ProviderName)
+		assert.Greater(t, int64(time.Since(start)), int64(100*time.Millisecond))
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the last configuration")
+	}
+
+	select {
+	case msg := <-out:
+		require.Failf(t, "unexpected configuration", "%s", msg.ProviderName)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
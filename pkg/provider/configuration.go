@@ -55,6 +55,12 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 	middlewaresToDelete := map[string]struct{}{}
 	middlewares := map[string][]string{}
 
+	middlewaresTCPToDelete := map[string]struct{}{}
+	middlewaresTCP := map[string][]string{}
+
+	cidrSetsToDelete := map[string]struct{}{}
+	cidrSets := map[string][]string{}
+
 	var sortedKeys []string
 	for key := range configurations {
 		sortedKeys = append(sortedKeys, key)
@@ -111,6 +117,20 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 				middlewaresToDelete[middlewareName] = struct{}{}
 			}
 		}
+
+		for middlewareName, middleware := range conf.TCP.Middlewares {
+			middlewaresTCP[middlewareName] = append(middlewaresTCP[middlewareName], root)
+			if !AddMiddlewareTCP(configuration.TCP, middlewareName, middleware) {
+				middlewaresTCPToDelete[middlewareName] = struct{}{}
+			}
+		}
+
+		for setName, set := range conf.TCP.CIDRSets {
+			cidrSets[setName] = append(cidrSets[setName], root)
+			if !AddCIDRSet(configuration.TCP, setName, set) {
+				cidrSetsToDelete[setName] = struct{}{}
+			}
+		}
 	}
 
 	for serviceName := range servicesToDelete {
@@ -155,6 +175,17 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 		delete(configuration.HTTP.Middlewares, middlewareName)
 	}
 
+	for middlewareName := range middlewaresTCPToDelete {
+		logger.WithField(log.MiddlewareName, middlewareName).
+			Errorf("TCP middleware defined multiple times with different configurations in %v", middlewaresTCP[middlewareName])
+		delete(configuration.TCP.Middlewares, middlewareName)
+	}
+
+	for setName := range cidrSetsToDelete {
+		logger.Errorf("CIDR set %s defined multiple times with different configurations in %v", setName, cidrSets[setName])
+		delete(configuration.TCP.CIDRSets, setName)
+	}
+
 	return configuration
 }
 
@@ -273,6 +304,36 @@ func AddMiddleware(configuration *dynamic.HTTPConfiguration, middlewareName stri
 	return reflect.DeepEqual(configuration.Middlewares[middlewareName], middleware)
 }
 
+// AddMiddlewareTCP Adds a middleware to a configurations.
+// The middlewares map is only created when needed, as most configurations do not define TCP middlewares.
+func AddMiddlewareTCP(configuration *dynamic.TCPConfiguration, middlewareName string, middleware *dynamic.TCPMiddleware) bool {
+	if configuration.Middlewares == nil {
+		configuration.Middlewares = make(map[string]*dynamic.TCPMiddleware)
+	}
+
+	if _, ok := configuration.Middlewares[middlewareName]; !ok {
+		configuration.Middlewares[middlewareName] = middleware
+		return true
+	}
+
+	return reflect.DeepEqual(configuration.Middlewares[middlewareName], middleware)
+}
+
+// AddCIDRSet Adds a CIDR set to a configurations.
+// The CIDR sets map is only created when needed, as most configurations do not define CIDR sets.
+func AddCIDRSet(configuration *dynamic.TCPConfiguration, setName string, set *dynamic.CIDRSet) bool {
+	if configuration.CIDRSets == nil {
+		configuration.CIDRSets = make(map[string]*dynamic.CIDRSet)
+	}
+
+	if _, ok := configuration.CIDRSets[setName]; !ok {
+		configuration.CIDRSets[setName] = set
+		return true
+	}
+
+	return reflect.DeepEqual(configuration.CIDRSets[setName], set)
+}
+
 // MakeDefaultRuleTemplate Creates the default rule template.
 func MakeDefaultRuleTemplate(defaultRule string, funcMap template.FuncMap) (*template.Template, error) {
 	defaultFuncMap := sprig.TxtFuncMap()
@@ -14,6 +14,7 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"github.com/traefik/paerser/file"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -431,6 +432,11 @@ func (p *Provider) decodeConfiguration(filePath, content string) (*dynamic.Confi
 		return nil, err
 	}
 
+	err = interpolate.Interpolate(configuration)
+	if err != nil {
+		return nil, err
+	}
+
 	return configuration, nil
 }
 
@@ -53,6 +53,38 @@ func TestTLSContent(t *testing.T) {
 	require.Equal(t, "CONTENT", configuration.TLS.Certificates[0].Certificate.KeyFile.String())
 }
 
+func TestInterpolatedContent(t *testing.T) {
+	tempDir := createTempDir(t, "testdir")
+	defer os.RemoveAll(tempDir)
+
+	require.NoError(t, os.Setenv("TRAEFIK_FILE_TEST_USER", "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"))
+	defer os.Unsetenv("TRAEFIK_FILE_TEST_USER")
+
+	secretFile := filepath.Join(tempDir, "secret")
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte("http://127.0.0.1:8080\n"), 0o600))
+
+	fileConfig, err := ioutil.TempFile(tempDir, "temp*.toml")
+	require.NoError(t, err)
+
+	content := `
+[http.middlewares.auth.basicAuth]
+  users = ["${env:TRAEFIK_FILE_TEST_USER}"]
+
+[[http.services.whoami.loadBalancer.servers]]
+  url = "${file:` + secretFile + `}"
+`
+
+	_, err = fileConfig.Write([]byte(content))
+	require.NoError(t, err)
+
+	provider := &Provider{}
+	configuration, err := provider.loadFileConfig(context.Background(), fileConfig.Name(), true)
+	require.NoError(t, err)
+
+	assert.Equal(t, dynamic.Users{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}, configuration.HTTP.Middlewares["auth"].BasicAuth.Users)
+	assert.Equal(t, "http://127.0.0.1:8080", configuration.HTTP.Services["whoami"].LoadBalancer.Servers[0].URL)
+}
+
 func TestErrorWhenEmptyConfig(t *testing.T) {
 	provider := &Provider{}
 	configChan := make(chan dynamic.Message)
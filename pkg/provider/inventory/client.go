@@ -0,0 +1,97 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// inventory is the response of the inventory service to a GET request on the endpoint.
+type inventory struct {
+	Instances []instance `json:"instances"`
+}
+
+// instance is a running instance of a service, such as a Marathon task or a Rancher container.
+// The instances sharing the same name and labels are load-balanced by the same service.
+type instance struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Address string            `json:"address"`
+	Port    int               `json:"port"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// fetchInventory fetches the inventory from the configured endpoint.
+func (p *Provider) fetchInventory(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
+// decodeInventory decodes the instances of the inventory,
+// whose identifiers are required and unique, as they identify the configuration of each instance.
+func decodeInventory(data []byte) ([]itemData, error) {
+	var inv inventory
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return nil, fmt.Errorf("unable to decode the inventory: %w", err)
+	}
+
+	ids := make(map[string]struct{})
+
+	var items []itemData
+	for i, inst := range inv.Instances {
+		if inst.ID == "" {
+			return nil, fmt.Errorf("the id of the instance %d is missing", i)
+		}
+
+		if _, exists := ids[inst.ID]; exists {
+			return nil, fmt.Errorf("the id %q of the instance %d is duplicated", inst.ID, i)
+		}
+		ids[inst.ID] = struct{}{}
+
+		if inst.Name == "" {
+			return nil, fmt.Errorf("the name of the instance %q is missing", inst.ID)
+		}
+
+		item := itemData{
+			ID:      inst.ID,
+			Name:    inst.Name,
+			Address: inst.Address,
+			Labels:  inst.Labels,
+		}
+
+		if inst.Port > 0 {
+			item.Port = strconv.Itoa(inst.Port)
+		}
+
+		if item.Labels == nil {
+			item.Labels = make(map[string]string)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,196 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const providerName = "inventory"
+
+// DefaultTemplateRule The default template for the default rule.
+const DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
+
+var _ provider.Provider = (*Provider)(nil)
+
+type itemData struct {
+	ID        string
+	Name      string
+	Address   string
+	Port      string
+	Labels    map[string]string
+	ExtraConf configuration
+}
+
+// Provider holds configurations of the provider.
+// It polls an inventory service listing the instances and their labels,
+// which keeps the label semantics of the Marathon and Rancher providers with any inventory.
+type Provider struct {
+	Endpoint         string            `description:"URL of the inventory service listing the instances." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Headers          map[string]string `description:"Headers sent to the inventory service, such as an Authorization one." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS              *types.ClientTLS  `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	PollInterval     ptypes.Duration   `description:"Polling interval for the inventory service." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
+	PollTimeout      ptypes.Duration   `description:"Polling timeout for the inventory service." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
+	Constraints      string            `description:"Constraints is an expression that Traefik matches against the instance's labels to determine whether to create any route for that instance." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	ExposedByDefault bool              `description:"Expose instances by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	DefaultRule      string            `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+
+	defaultRuleTpl        *template.Template
+	httpClient            *http.Client
+	lastConfigurationHash uint64
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.PollInterval = ptypes.Duration(15 * time.Second)
+	p.PollTimeout = ptypes.Duration(5 * time.Second)
+	p.ExposedByDefault = true
+	p.DefaultRule = DefaultTemplateRule
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.Endpoint == "" {
+		return fmt.Errorf("non-empty endpoint is required")
+	}
+
+	if p.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be greater than 0")
+	}
+
+	defaultRuleTpl, err := provider.MakeDefaultRuleTemplate(p.DefaultRule, nil)
+	if err != nil {
+		return fmt.Errorf("error while parsing default rule: %w", err)
+	}
+
+	p.defaultRuleTpl = defaultRuleTpl
+
+	p.httpClient = &http.Client{
+		Timeout: time.Duration(p.PollTimeout),
+	}
+
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+
+		p.httpClient.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	return nil
+}
+
+// Provide allows the inventory provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		operation := func() error {
+			// get configuration at the provider's startup.
+			err := p.loadConfiguration(ctxLog, configurationChan)
+			if err != nil {
+				return fmt.Errorf("failed to list the inventory instances: %w", err)
+			}
+
+			// Periodic refreshes.
+			ticker := time.NewTicker(time.Duration(p.PollInterval))
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					err = p.loadConfiguration(ctxLog, configurationChan)
+					if err != nil {
+						return fmt.Errorf("failed to refresh the inventory instances: %w", err)
+					}
+
+				case <-routineCtx.Done():
+					return nil
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to the inventory service %+v", err)
+		}
+	})
+
+	return nil
+}
+
+// loadConfiguration sends the configuration built from the inventory, unless the inventory is unchanged.
+func (p *Provider) loadConfiguration(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	data, err := p.fetchInventory(ctx)
+	if err != nil {
+		return err
+	}
+
+	fnvHasher := fnv.New64()
+
+	_, err = fnvHasher.Write(data)
+	if err != nil {
+		return fmt.Errorf("cannot hash the inventory: %w", err)
+	}
+
+	hash := fnvHasher.Sum64()
+	if hash == p.lastConfigurationHash {
+		return nil
+	}
+
+	items, err := p.getItems(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	p.lastConfigurationHash = hash
+
+	configurationChan <- dynamic.Message{
+		ProviderName:  providerName,
+		Configuration: p.buildConfiguration(ctx, items),
+	}
+
+	return nil
+}
+
+// getItems returns the instances of the inventory, with their configuration from the labels.
+func (p *Provider) getItems(ctx context.Context, data []byte) ([]itemData, error) {
+	items, err := decodeInventory(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []itemData
+	for _, item := range items {
+		extraConf, err := p.getConfiguration(item)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Skip item %s: %v", item.ID, err)
+			continue
+		}
+		item.ExtraConf = extraConf
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func Bool(v bool) *bool { return &v }
+
+func TestProvider_loadConfiguration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{
+  "instances": [
+    {"id": "whoami-1", "name": "whoami", "address": "10.0.0.1", "port": 8080, "labels": {"traefik.http.routers.whoami.rule": "Host(` + "`whoami.example.com`" + `)"}},
+    {"id": "whoami-2", "name": "whoami", "address": "10.0.0.2", "port": 8080, "labels": {"traefik.http.routers.whoami.rule": "Host(` + "`whoami.example.com`" + `)"}},
+    {"id": "worker-1", "name": "worker", "address": "10.0.0.3", "labels": {"traefik.enable": "false"}}
+  ]
+}`))
+	}))
+	t.Cleanup(server.Close)
+
+	p := Provider{}
+	p.SetDefaults()
+	p.Endpoint = server.URL
+	p.Headers = map[string]string{"Authorization": "Bearer secret"}
+
+	require.NoError(t, p.Init())
+
+	configurationChan := make(chan dynamic.Message, 2)
+
+	err := p.loadConfiguration(context.Background(), configurationChan)
+	require.NoError(t, err)
+
+	require.Len(t, configurationChan, 1)
+	message := <-configurationChan
+
+	expected := &dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  map[string]*dynamic.TCPRouter{},
+			Services: map[string]*dynamic.TCPService{},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  map[string]*dynamic.UDPRouter{},
+			Services: map[string]*dynamic.UDPService{},
+		},
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"whoami": {
+					Service: "whoami",
+					Rule:    "Host(`whoami.example.com`)",
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{},
+			Services: map[string]*dynamic.Service{
+				"whoami": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{
+							{URL: "http://10.0.0.1:8080"},
+							{URL: "http://10.0.0.2:8080"},
+						},
+						PassHostHeader: Bool(true),
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, providerName, message.ProviderName)
+	assert.Equal(t, expected, message.Configuration)
+
+	// The configuration is not sent again while the inventory is unchanged.
+	err = p.loadConfiguration(context.Background(), configurationChan)
+	require.NoError(t, err)
+	assert.Empty(t, configurationChan)
+
+	p.Headers = nil
+	err = p.loadConfiguration(context.Background(), configurationChan)
+	assert.Error(t, err)
+}
+
+func Test_decodeInventory(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		data          string
+		expected      []itemData
+		expectedError bool
+	}{
+		{
+			desc: "instances",
+			data: `{"instances": [{"id": "api-1", "name": "api", "address": "10.0.0.1", "port": 3000, "labels": {"traefik.enable": "true"}}, {"id": "dns-1", "name": "dns"}]}`,
+			expected: []itemData{
+				{
+					ID:      "api-1",
+					Name:    "api",
+					Address: "10.0.0.1",
+					Port:    "3000",
+					Labels:  map[string]string{"traefik.enable": "true"},
+				},
+				{
+					ID:     "dns-1",
+					Name:   "dns",
+					Labels: map[string]string{},
+				},
+			},
+		},
+		{
+			desc: "no instance",
+			data: `{"instances": []}`,
+		},
+		{
+			desc:          "invalid JSON",
+			data:          `{"instances": [`,
+			expectedError: true,
+		},
+		{
+			desc:          "missing id",
+			data:          `{"instances": [{"name": "api"}]}`,
+			expectedError: true,
+		},
+		{
+			desc:          "duplicated id",
+			data:          `{"instances": [{"id": "api-1", "name": "api"}, {"id": "api-1", "name": "api"}]}`,
+			expectedError: true,
+		},
+		{
+			desc:          "missing name",
+			data:          `{"instances": [{"id": "api-1"}]}`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			items, err := decodeInventory([]byte(test.data))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, items)
+		})
+	}
+}
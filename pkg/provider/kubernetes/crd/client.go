@@ -1,6 +1,8 @@
 package crd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +11,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/generated/clientset/versioned"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/generated/informers/externalversions"
@@ -18,14 +21,19 @@ import (
 	kubeerror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 const resyncPeriod = 10 * time.Minute
 
+// podIPIndex is the name of the index of the pods by IP.
+const podIPIndex = "podIP"
+
 type resourceEventHandler struct {
 	ev chan<- interface{}
 }
@@ -52,6 +60,7 @@ type Client interface {
 	GetIngressRouteTCPs() []*v1alpha1.IngressRouteTCP
 	GetIngressRouteUDPs() []*v1alpha1.IngressRouteUDP
 	GetMiddlewares() []*v1alpha1.Middleware
+	GetMiddlewareTCPs() []*v1alpha1.MiddlewareTCP
 	GetTraefikService(namespace, name string) (*v1alpha1.TraefikService, bool, error)
 	GetTraefikServices() []*v1alpha1.TraefikService
 	GetTLSOptions() []*v1alpha1.TLSOption
@@ -61,6 +70,9 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+	GetCIDRSetConfigMaps() []*corev1.ConfigMap
+
+	SetReadinessGates(ctx context.Context, ips []string) error
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -71,8 +83,13 @@ type clientWrapper struct {
 	factoriesCrd    map[string]externalversions.SharedInformerFactory
 	factoriesKube   map[string]informers.SharedInformerFactory
 	factoriesSecret map[string]informers.SharedInformerFactory
+	// factoriesCIDRSet are the factories of the ConfigMaps holding the CIDR sets, which are only watched with a label selector.
+	factoriesCIDRSet map[string]informers.SharedInformerFactory
 
-	labelSelector string
+	labelSelector         string
+	cidrSetsLabelSelector string
+	// readinessGate is the condition type of the pods readiness gate, the pods are not watched when it is empty.
+	readinessGate corev1.PodConditionType
 
 	isNamespaceAll    bool
 	watchedNamespaces []string
@@ -102,11 +119,12 @@ func createClientFromConfig(c *rest.Config) (*clientWrapper, error) {
 
 func newClientImpl(csKube kubernetes.Interface, csCrd versioned.Interface) *clientWrapper {
 	return &clientWrapper{
-		csCrd:           csCrd,
-		csKube:          csKube,
-		factoriesCrd:    make(map[string]externalversions.SharedInformerFactory),
-		factoriesKube:   make(map[string]informers.SharedInformerFactory),
-		factoriesSecret: make(map[string]informers.SharedInformerFactory),
+		csCrd:            csCrd,
+		csKube:           csKube,
+		factoriesCrd:     make(map[string]externalversions.SharedInformerFactory),
+		factoriesKube:    make(map[string]informers.SharedInformerFactory),
+		factoriesSecret:  make(map[string]informers.SharedInformerFactory),
+		factoriesCIDRSet: make(map[string]informers.SharedInformerFactory),
 	}
 }
 
@@ -182,6 +200,7 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		factoryCrd := externalversions.NewSharedInformerFactoryWithOptions(c.csCrd, resyncPeriod, externalversions.WithNamespace(ns), externalversions.WithTweakListOptions(matchesLabelSelector))
 		factoryCrd.Traefik().V1alpha1().IngressRoutes().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().Middlewares().Informer().AddEventHandler(eventHandler)
+		factoryCrd.Traefik().V1alpha1().MiddlewareTCPs().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().IngressRouteTCPs().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().IngressRouteUDPs().Informer().AddEventHandler(eventHandler)
 		factoryCrd.Traefik().V1alpha1().TLSOptions().Informer().AddEventHandler(eventHandler)
@@ -193,18 +212,41 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
 		factoryKube.Core().V1().Endpoints().Informer().AddEventHandler(eventHandler)
 
+		if c.readinessGate != "" {
+			podInformer := factoryKube.Core().V1().Pods().Informer()
+			if err := podInformer.AddIndexers(cache.Indexers{podIPIndex: podIPIndexFunc}); err != nil {
+				return nil, fmt.Errorf("unable to index the pods in namespace %q: %w", ns, err)
+			}
+			podInformer.AddEventHandler(eventHandler)
+		}
+
 		factorySecret := informers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, informers.WithNamespace(ns), informers.WithTweakListOptions(notOwnedByHelm))
 		factorySecret.Core().V1().Secrets().Informer().AddEventHandler(eventHandler)
 
 		c.factoriesCrd[ns] = factoryCrd
 		c.factoriesKube[ns] = factoryKube
 		c.factoriesSecret[ns] = factorySecret
+
+		if c.cidrSetsLabelSelector != "" {
+			matchesCIDRSetsLabelSelector := func(opts *metav1.ListOptions) {
+				opts.LabelSelector = c.cidrSetsLabelSelector
+			}
+
+			factoryCIDRSet := informers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, informers.WithNamespace(ns), informers.WithTweakListOptions(matchesCIDRSetsLabelSelector))
+			factoryCIDRSet.Core().V1().ConfigMaps().Informer().AddEventHandler(eventHandler)
+
+			c.factoriesCIDRSet[ns] = factoryCIDRSet
+		}
 	}
 
 	for _, ns := range namespaces {
 		c.factoriesCrd[ns].Start(stopCh)
 		c.factoriesKube[ns].Start(stopCh)
 		c.factoriesSecret[ns].Start(stopCh)
+
+		if factory, ok := c.factoriesCIDRSet[ns]; ok {
+			factory.Start(stopCh)
+		}
 	}
 
 	for _, ns := range namespaces {
@@ -225,6 +267,14 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s in namespace %q", t.String(), ns)
 			}
 		}
+
+		if factory, ok := c.factoriesCIDRSet[ns]; ok {
+			for t, ok := range factory.WaitForCacheSync(stopCh) {
+				if !ok {
+					return nil, fmt.Errorf("timed out waiting for controller caches to sync %s in namespace %q", t.String(), ns)
+				}
+			}
+		}
 	}
 
 	return eventCh, nil
@@ -286,6 +336,20 @@ func (c *clientWrapper) GetMiddlewares() []*v1alpha1.Middleware {
 	return result
 }
 
+func (c *clientWrapper) GetMiddlewareTCPs() []*v1alpha1.MiddlewareTCP {
+	var result []*v1alpha1.MiddlewareTCP
+
+	for ns, factory := range c.factoriesCrd {
+		middlewares, err := factory.Traefik().V1alpha1().MiddlewareTCPs().Lister().List(labels.Everything())
+		if err != nil {
+			log.Errorf("Failed to list TCP middlewares in namespace %s: %v", ns, err)
+		}
+		result = append(result, middlewares...)
+	}
+
+	return result
+}
+
 // GetTraefikService returns the named service from the given namespace.
 func (c *clientWrapper) GetTraefikService(namespace, name string) (*v1alpha1.TraefikService, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
@@ -376,7 +440,103 @@ func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints,
 
 	endpoint, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Endpoints().Lister().Endpoints(namespace).Get(name)
 	exist, err := translateNotFoundError(err)
-	return endpoint, exist, err
+	if !exist || c.readinessGate == "" {
+		return endpoint, exist, err
+	}
+
+	return c.withGatedAddresses(endpoint), true, nil
+}
+
+// withGatedAddresses returns the endpoints in which the not ready addresses of the pods only waiting for the readiness gate
+// are moved to the ready ones, so that they are load-balanced before their readiness gate is set.
+func (c *clientWrapper) withGatedAddresses(endpoints *corev1.Endpoints) *corev1.Endpoints {
+	result := endpoints
+
+	for i, subset := range endpoints.Subsets {
+		var gated, notReady []corev1.EndpointAddress
+		for _, address := range subset.NotReadyAddresses {
+			if address.TargetRef != nil && address.TargetRef.Kind == "Pod" && c.waitsForReadinessGate(address.TargetRef.Namespace, address.TargetRef.Name) {
+				gated = append(gated, address)
+				continue
+			}
+
+			notReady = append(notReady, address)
+		}
+
+		if len(gated) == 0 {
+			continue
+		}
+
+		// The endpoints of the informer cache must not be modified.
+		if result == endpoints {
+			result = endpoints.DeepCopy()
+		}
+
+		result.Subsets[i].Addresses = append(result.Subsets[i].Addresses, gated...)
+		result.Subsets[i].NotReadyAddresses = notReady
+	}
+
+	return result
+}
+
+func (c *clientWrapper) waitsForReadinessGate(namespace, name string) bool {
+	if !c.isWatchedNamespace(namespace) {
+		return false
+	}
+
+	pod, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Pods().Lister().Pods(namespace).Get(name)
+	if err != nil {
+		return false
+	}
+
+	return waitsForReadinessGate(pod, c.readinessGate)
+}
+
+// SetReadinessGates sets the readiness gate condition of the pods with the given IPs which are waiting for it.
+func (c *clientWrapper) SetReadinessGates(ctx context.Context, ips []string) error {
+	if c.readinessGate == "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.PodCondition{{
+				Type:               c.readinessGate,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var result *multierror.Error
+	for _, factory := range c.factoriesKube {
+		indexer := factory.Core().V1().Pods().Informer().GetIndexer()
+
+		for _, ip := range ips {
+			pods, err := indexer.ByIndex(podIPIndex, ip)
+			if err != nil {
+				result = multierror.Append(result, err)
+				continue
+			}
+
+			for _, obj := range pods {
+				pod, ok := obj.(*corev1.Pod)
+				if !ok || !waitsForReadinessGate(pod, c.readinessGate) {
+					continue
+				}
+
+				_, err = c.csKube.CoreV1().Pods(pod.Namespace).Patch(ctx, pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "status")
+				if err != nil {
+					result = multierror.Append(result, fmt.Errorf("unable to set the readiness gate of the pod %s/%s: %w", pod.Namespace, pod.Name, err))
+				}
+			}
+		}
+	}
+
+	return result.ErrorOrNil()
 }
 
 // GetSecret returns the named secret from the given namespace.
@@ -390,6 +550,21 @@ func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool,
 	return secret, exist, err
 }
 
+// GetCIDRSetConfigMaps returns the ConfigMaps holding the CIDR sets.
+func (c *clientWrapper) GetCIDRSetConfigMaps() []*corev1.ConfigMap {
+	var result []*corev1.ConfigMap
+
+	for ns, factory := range c.factoriesCIDRSet {
+		configMaps, err := factory.Core().V1().ConfigMaps().Lister().List(labels.Everything())
+		if err != nil {
+			log.Errorf("Failed to list CIDR set ConfigMaps in namespace %s: %v", ns, err)
+		}
+		result = append(result, configMaps...)
+	}
+
+	return result
+}
+
 // lookupNamespace returns the lookup namespace key for the given namespace.
 // When listening on all namespaces, it returns the client-go identifier ("")
 // for all-namespaces. Otherwise, it returns the given namespace.
@@ -413,6 +588,48 @@ func eventHandlerFunc(events chan<- interface{}, obj interface{}) {
 	}
 }
 
+// podIPIndexFunc indexes the pods by IP.
+func podIPIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Status.PodIP == "" {
+		return nil, nil
+	}
+
+	return []string{pod.Status.PodIP}, nil
+}
+
+// waitsForReadinessGate reports whether the pod is only waiting for the readiness gate to be ready:
+// it declares the readiness gate, its containers are ready, and the readiness gate condition is not set yet.
+func waitsForReadinessGate(pod *corev1.Pod, readinessGate corev1.PodConditionType) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+
+	var declared bool
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == readinessGate {
+			declared = true
+			break
+		}
+	}
+
+	if !declared {
+		return false
+	}
+
+	containersReady, gateSet := false, false
+	for _, condition := range pod.Status.Conditions {
+		switch condition.Type {
+		case corev1.ContainersReady:
+			containersReady = condition.Status == corev1.ConditionTrue
+		case readinessGate:
+			gateSet = condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return containersReady && !gateSet
+}
+
 // translateNotFoundError will translate a "not found" error to a boolean return
 // value which indicates if the resource exists and a nil error.
 func translateNotFoundError(err error) (bool, error) {
@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -63,3 +64,68 @@ func TestClientIgnoresHelmOwnedSecrets(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, found)
 }
+
+func TestClientReadinessGates(t *testing.T) {
+	newPod := func(name, ip string, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: corev1.PodSpec{
+				ReadinessGates: []corev1.PodReadinessGate{{ConditionType: "traefik.containo.us/ready"}},
+			},
+			Status: corev1.PodStatus{PodIP: ip, Conditions: conditions},
+		}
+	}
+
+	containersReady := corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "whoami"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}},
+			NotReadyAddresses: []corev1.EndpointAddress{
+				{IP: "10.10.0.2", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "gated"}},
+				{IP: "10.10.0.3", TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "starting"}},
+			},
+		}},
+	}
+
+	kubeClient := kubefake.NewSimpleClientset(
+		endpoints,
+		newPod("gated", "10.10.0.2", containersReady),
+		newPod("starting", "10.10.0.3"),
+	)
+
+	client := newClientImpl(kubeClient, crdfake.NewSimpleClientset())
+	client.readinessGate = "traefik.containo.us/ready"
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	_, err := client.WatchAll(nil, stopCh)
+	require.NoError(t, err)
+
+	gatedEndpoints, found, err := client.GetEndpoints("default", "whoami")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.Len(t, gatedEndpoints.Subsets, 1)
+	assert.Equal(t, []corev1.EndpointAddress{{IP: "10.10.0.1"}, endpoints.Subsets[0].NotReadyAddresses[0]}, gatedEndpoints.Subsets[0].Addresses)
+	assert.Equal(t, []corev1.EndpointAddress{endpoints.Subsets[0].NotReadyAddresses[1]}, gatedEndpoints.Subsets[0].NotReadyAddresses)
+
+	err = client.SetReadinessGates(context.Background(), []string{"10.10.0.1", "10.10.0.2", "10.10.0.3"})
+	require.NoError(t, err)
+
+	for name, expected := range map[string]bool{"gated": true, "starting": false} {
+		pod, err := kubeClient.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+
+		var gateSet bool
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == "traefik.containo.us/ready" && condition.Status == corev1.ConditionTrue {
+				gateSet = true
+			}
+		}
+
+		assert.Equal(t, expected, gateSet, name)
+	}
+}
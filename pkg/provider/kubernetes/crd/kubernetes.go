@@ -7,6 +7,8 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
@@ -20,6 +22,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
@@ -39,15 +42,24 @@ const (
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Endpoint            string          `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	Token               string          `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
-	CertAuthFilePath    string          `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
-	Namespaces          []string        `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
-	AllowCrossNamespace *bool           `description:"Allow cross namespace resource reference." json:"allowCrossNamespace,omitempty" toml:"allowCrossNamespace,omitempty" yaml:"allowCrossNamespace,omitempty" export:"true"`
-	LabelSelector       string          `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
-	IngressClass        string          `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
-	ThrottleDuration    ptypes.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
-	lastConfiguration   safe.Safe
+	Endpoint              string                 `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token                 string                 `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath      string                 `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces            []string               `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	AllowCrossNamespace   *bool                  `description:"Allow cross namespace resource reference." json:"allowCrossNamespace,omitempty" toml:"allowCrossNamespace,omitempty" yaml:"allowCrossNamespace,omitempty" export:"true"`
+	LabelSelector         string                 `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	IngressClass          string                 `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
+	ThrottleDuration      ptypes.Duration        `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	Webhook               *Webhook               `description:"Enables the admission webhook validating the Traefik resources." json:"webhook,omitempty" toml:"webhook,omitempty" yaml:"webhook,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ReadinessGate         string                 `description:"Condition type of the pods readiness gate, set once the pods are load-balanced." json:"readinessGate,omitempty" toml:"readinessGate,omitempty" yaml:"readinessGate,omitempty" export:"true"`
+	CIDRSetsLabelSelector string                 `description:"Kubernetes label selector of the ConfigMaps holding the CIDR sets, which are not watched when empty." json:"cidrSetsLabelSelector,omitempty" toml:"cidrSetsLabelSelector,omitempty" yaml:"cidrSetsLabelSelector,omitempty" export:"true"`
+	GenerationBarrier     *k8s.GenerationBarrier `description:"Holds back the configuration while resources referenced by the routes, their middlewares and services are missing." json:"generationBarrier,omitempty" toml:"generationBarrier,omitempty" yaml:"generationBarrier,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	lastConfiguration     safe.Safe
+	synced                provider.SyncState
+
+	// loadBalancedIPs are the IPs of the servers of the last applied configuration.
+	loadBalancedIPs      safe.Safe
+	configurationApplied chan struct{}
 }
 
 // SetDefaults sets the default values.
@@ -62,6 +74,10 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 	log.FromContext(ctx).Infof("label selector is: %q", p.LabelSelector)
 
+	if _, err = labels.Parse(p.CIDRSetsLabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid CIDR sets label selector: %q", p.CIDRSetsLabelSelector)
+	}
+
 	withEndpoint := ""
 	if p.Endpoint != "" {
 		withEndpoint = fmt.Sprintf(" with endpoint %s", p.Endpoint)
@@ -85,14 +101,41 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	client.labelSelector = p.LabelSelector
+	client.cidrSetsLabelSelector = p.CIDRSetsLabelSelector
+	client.readinessGate = corev1.PodConditionType(p.ReadinessGate)
 	return client, nil
 }
 
 // Init the provider.
 func (p *Provider) Init() error {
+	if p.ReadinessGate != "" {
+		p.configurationApplied = make(chan struct{}, 1)
+	}
+
 	return nil
 }
 
+// ListenConfiguration records the servers of the applied configuration,
+// so that the readiness gate of the pods they are made of is set.
+func (p *Provider) ListenConfiguration(conf dynamic.Configuration) {
+	if p.ReadinessGate == "" {
+		return
+	}
+
+	p.loadBalancedIPs.Set(getLoadBalancedIPs(conf))
+
+	select {
+	case p.configurationApplied <- struct{}{}:
+	default:
+	}
+}
+
+// Synced returns the name of the provider, and a channel closed once the configuration
+// built from the synced informers caches has been provided.
+func (p *Provider) Synced() (string, <-chan struct{}) {
+	return providerName, p.synced.Done()
+}
+
 // Provide allows the k8s provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -128,29 +171,67 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 				eventsChan = throttledChan
 			}
 
+			var barrier *k8s.Barrier
+			if p.GenerationBarrier != nil {
+				barrier = k8s.NewBarrier(*p.GenerationBarrier)
+			}
+
+			// retry fires when a configuration held back by the generation barrier has to be evaluated again.
+			var retry <-chan time.Time
+
+			provide := func(event interface{}) {
+				if barrier != nil {
+					missing := p.missingReferences(k8sClient)
+
+					wait, hold := barrier.Hold(missing)
+					if hold {
+						logger.Infof("Holding back the configuration for at most %s, until the referenced resources exist: %s", wait, strings.Join(missing, ", "))
+						retry = time.After(wait)
+						return
+					}
+
+					retry = nil
+
+					if len(missing) > 0 {
+						logger.Warnf("Applying the configuration with missing referenced resources: %s", strings.Join(missing, ", "))
+					}
+				}
+
+				conf := p.loadConfigurationFromCRD(ctxLog, k8sClient)
+
+				confHash, err := hashstructure.Hash(conf, nil)
+				switch {
+				case err != nil:
+					logger.Error("Unable to hash the configuration")
+				case p.lastConfiguration.Get() == confHash:
+					logger.Debugf("Skipping Kubernetes event kind %T", event)
+				default:
+					p.lastConfiguration.Set(confHash)
+					configurationChan <- dynamic.Message{
+						ProviderName:  providerName,
+						Configuration: conf,
+					}
+				}
+
+				p.synced.MarkSynced()
+			}
+
 			for {
 				select {
 				case <-ctxPool.Done():
 					return nil
+				case <-p.configurationApplied:
+					ips, _ := p.loadBalancedIPs.Get().([]string)
+					if err := k8sClient.SetReadinessGates(ctxPool, ips); err != nil {
+						logger.Errorf("Error while setting the readiness gates: %v", err)
+					}
+				case <-retry:
+					provide(nil)
 				case event := <-eventsChan:
 					// Note that event is the *first* event that came in during this throttling interval -- if we're hitting our throttle, we may have dropped events.
 					// This is fine, because we don't treat different event types differently.
 					// But if we do in the future, we'll need to track more information about the dropped events.
-					conf := p.loadConfigurationFromCRD(ctxLog, k8sClient)
-
-					confHash, err := hashstructure.Hash(conf, nil)
-					switch {
-					case err != nil:
-						logger.Error("Unable to hash the configuration")
-					case p.lastConfiguration.Get() == confHash:
-						logger.Debugf("Skipping Kubernetes event kind %T", event)
-					default:
-						p.lastConfiguration.Set(confHash)
-						configurationChan <- dynamic.Message{
-							ProviderName:  providerName,
-							Configuration: conf,
-						}
-					}
+					provide(event)
 
 					// If we're throttling,
 					// we sleep here for the throttle duration to enforce that we don't refresh faster than our throttle.
@@ -207,19 +288,17 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
-		errorPage, errorPageService, err := p.createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
+		errorPage, errorPageServices, err := p.createErrorPageMiddleware(client, middleware.Namespace, id, middleware.Spec.Errors)
 		if err != nil {
 			log.FromContext(ctxMid).Errorf("Error while reading error page middleware: %v", err)
 			continue
 		}
 
-		if errorPage != nil && errorPageService != nil {
-			serviceName := id + "-errorpage-service"
-			errorPage.Service = serviceName
+		for serviceName, errorPageService := range errorPageServices {
 			conf.HTTP.Services[serviceName] = errorPageService
 		}
 
-		conf.HTTP.Middlewares[id] = &dynamic.Middleware{
+		middlewareConf := &dynamic.Middleware{
 			AddPrefix:         middleware.Spec.AddPrefix,
 			StripPrefix:       middleware.Spec.StripPrefix,
 			StripPrefixRegex:  middleware.Spec.StripPrefixRegex,
@@ -242,10 +321,49 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             middleware.Spec.Retry,
 			ContentType:       middleware.Spec.ContentType,
+			CORS:              middleware.Spec.CORS,
+			RequestTimeout:    middleware.Spec.RequestTimeout,
+			GeoIP:             middleware.Spec.GeoIP,
+			Experiment:        middleware.Spec.Experiment,
+			Maintenance:       middleware.Spec.Maintenance,
+			GRPCWeb:           middleware.Spec.GRPCWeb,
+			WebSocket:         middleware.Spec.WebSocket,
+			ExternalProcessor: middleware.Spec.ExternalProcessor,
+			EarlyHints:        middleware.Spec.EarlyHints,
+			Limits:            middleware.Spec.Limits,
+			SAML:              middleware.Spec.SAML,
+			Redirects:         middleware.Spec.Redirects,
+			Decompress:        middleware.Spec.Decompress,
+			SecurityHeaders:   middleware.Spec.SecurityHeaders,
 			Plugin:            middleware.Spec.Plugin,
 		}
+
+		qualified, err := qualifySecretReferences(middleware.Namespace, middlewareConf)
+		if err != nil {
+			log.FromContext(ctxMid).Errorf("Error while reading the secret references of the middleware: %v", err)
+			continue
+		}
+
+		conf.HTTP.Middlewares[id] = qualified
+	}
+
+	for _, middlewareTCP := range client.GetMiddlewareTCPs() {
+		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
+
+		if conf.TCP.Middlewares == nil {
+			conf.TCP.Middlewares = make(map[string]*dynamic.TCPMiddleware)
+		}
+
+		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
+			SNIWhiteList: middlewareTCP.Spec.SNIWhiteList,
+			IPWhiteList:  createTCPIPWhiteListMiddleware(middlewareTCP.Namespace, middlewareTCP.Spec.IPWhiteList),
+			InFlightConn: middlewareTCP.Spec.InFlightConn,
+			RateLimit:    middlewareTCP.Spec.RateLimit,
+		}
 	}
 
+	conf.TCP.CIDRSets = buildCIDRSets(client)
+
 	cb := configBuilder{client, p.AllowCrossNamespace}
 
 	for _, service := range client.GetTraefikServices() {
@@ -309,6 +427,46 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 					logger.Errorf("Error while reading IdleConnTimeout: %v", err)
 				}
 			}
+
+			if serversTransport.Spec.ForwardingTimeouts.MaxConnLifetime != nil {
+				err := forwardingTimeout.MaxConnLifetime.Set(serversTransport.Spec.ForwardingTimeouts.MaxConnLifetime.String())
+				if err != nil {
+					logger.Errorf("Error while reading MaxConnLifetime: %v", err)
+				}
+			}
+		}
+
+		var resolver *dynamic.ServersResolver
+		if serversTransport.Spec.Resolver != nil {
+			resolver = &dynamic.ServersResolver{Servers: serversTransport.Spec.Resolver.Servers}
+			resolver.SetDefaults()
+
+			if serversTransport.Spec.Resolver.MinTTL != nil {
+				err := resolver.MinTTL.Set(serversTransport.Spec.Resolver.MinTTL.String())
+				if err != nil {
+					logger.Errorf("Error while reading MinTTL: %v", err)
+				}
+			}
+
+			if serversTransport.Spec.Resolver.MaxTTL != nil {
+				err := resolver.MaxTTL.Set(serversTransport.Spec.Resolver.MaxTTL.String())
+				if err != nil {
+					logger.Errorf("Error while reading MaxTTL: %v", err)
+				}
+			}
+		}
+
+		var hedging *dynamic.Hedging
+		if serversTransport.Spec.Hedging != nil {
+			hedging = &dynamic.Hedging{}
+			hedging.SetDefaults()
+
+			if serversTransport.Spec.Hedging.Delay != nil {
+				err := hedging.Delay.Set(serversTransport.Spec.Hedging.Delay.String())
+				if err != nil {
+					logger.Errorf("Error while reading Delay: %v", err)
+				}
+			}
 		}
 
 		conf.HTTP.ServersTransports[serversTransport.Name] = &dynamic.ServersTransport{
@@ -318,6 +476,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Certificates:        certs,
 			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
 			ForwardingTimeouts:  forwardingTimeout,
+			Protocol:            serversTransport.Spec.Protocol,
+			Resolver:            resolver,
+			Hedging:             hedging,
 		}
 	}
 
@@ -356,22 +517,57 @@ func getServicePort(svc *corev1.Service, port intstr.IntOrString) (*corev1.Servi
 	return &corev1.ServicePort{Port: port.IntVal}, nil
 }
 
-func (p *Provider) createErrorPageMiddleware(client Client, namespace string, errorPage *v1alpha1.ErrorPage) (*dynamic.ErrorPage, *dynamic.Service, error) {
+// createErrorPageMiddleware returns the error page middleware, along with the services serving the error pages.
+func (p *Provider) createErrorPageMiddleware(client Client, namespace, id string, errorPage *v1alpha1.ErrorPage) (*dynamic.ErrorPage, map[string]*dynamic.Service, error) {
 	if errorPage == nil {
 		return nil, nil, nil
 	}
 
+	cb := configBuilder{client, p.AllowCrossNamespace}
+	services := make(map[string]*dynamic.Service)
+
 	errorPageMiddleware := &dynamic.ErrorPage{
-		Status: errorPage.Status,
-		Query:  errorPage.Query,
+		Status:      errorPage.Status,
+		Query:       errorPage.Query,
+		Template:    errorPage.Template,
+		ContentType: errorPage.ContentType,
 	}
 
-	balancerServerHTTP, err := configBuilder{client, p.AllowCrossNamespace}.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
-	if err != nil {
-		return nil, nil, err
+	// The service is optional when the error page is rendered from a template.
+	if errorPage.Service.Name != "" {
+		balancerServerHTTP, err := cb.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		serviceName := id + "-errorpage-service"
+		errorPageMiddleware.Service = serviceName
+		services[serviceName] = balancerServerHTTP
 	}
 
-	return errorPageMiddleware, balancerServerHTTP, nil
+	for i, statusPage := range errorPage.StatusPages {
+		statusPageMiddleware := dynamic.StatusErrorPage{
+			Status:      statusPage.Status,
+			Query:       statusPage.Query,
+			Template:    statusPage.Template,
+			ContentType: statusPage.ContentType,
+		}
+
+		if statusPage.Service.Name != "" {
+			balancerServerHTTP, err := cb.buildServersLB(namespace, statusPage.Service.LoadBalancerSpec)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			serviceName := fmt.Sprintf("%s-errorpage-service-%d", id, i)
+			statusPageMiddleware.Service = serviceName
+			services[serviceName] = balancerServerHTTP
+		}
+
+		errorPageMiddleware.StatusPages = append(errorPageMiddleware.StatusPages, statusPageMiddleware)
+	}
+
+	return errorPageMiddleware, services, nil
 }
 
 func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alpha1.ForwardAuth) (*dynamic.ForwardAuth, error) {
@@ -463,9 +659,14 @@ func createBasicAuthMiddleware(client Client, namespace string, basicAuth *v1alp
 		return nil, nil
 	}
 
-	credentials, err := getAuthCredentials(client, basicAuth.Secret, namespace)
-	if err != nil {
-		return nil, err
+	// The users can be authenticated by LDAP only.
+	var credentials []string
+	if basicAuth.Secret != "" || basicAuth.LDAP == nil {
+		var err error
+		credentials, err = getAuthCredentials(client, basicAuth.Secret, namespace)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &dynamic.BasicAuth{
@@ -473,6 +674,7 @@ func createBasicAuthMiddleware(client Client, namespace string, basicAuth *v1alp
 		Realm:        basicAuth.Realm,
 		RemoveHeader: basicAuth.RemoveHeader,
 		HeaderField:  basicAuth.HeaderField,
+		LDAP:         basicAuth.LDAP,
 	}, nil
 }
 
@@ -570,6 +772,56 @@ func createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha
 	return &dynamic.Chain{Middlewares: mds}
 }
 
+// createTCPIPWhiteListMiddleware qualifies the CIDR sets referenced by the middleware with their namespace,
+// unless they are cross-provider references.
+func createTCPIPWhiteListMiddleware(namespace string, ipWhiteList *dynamic.TCPIPWhiteList) *dynamic.TCPIPWhiteList {
+	if ipWhiteList == nil || len(ipWhiteList.SourceRangeSets) == 0 {
+		return ipWhiteList
+	}
+
+	result := ipWhiteList.DeepCopy()
+	for i, name := range result.SourceRangeSets {
+		if !strings.Contains(name, providerNamespaceSeparator) {
+			result.SourceRangeSets[i] = makeID(namespace, name)
+		}
+	}
+
+	return result
+}
+
+// buildCIDRSets builds the CIDR sets of the ConfigMaps, named after their namespace and name.
+// Each value of the ConfigMap holds IP ranges, one per line, where the empty lines and the lines starting with # are ignored.
+func buildCIDRSets(client Client) map[string]*dynamic.CIDRSet {
+	var cidrSets map[string]*dynamic.CIDRSet
+
+	for _, configMap := range client.GetCIDRSetConfigMaps() {
+		var keys []string
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		cidrSet := &dynamic.CIDRSet{}
+		for _, key := range keys {
+			for _, line := range strings.Split(configMap.Data[key], "\n") {
+				line = strings.TrimSpace(line)
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+
+				cidrSet.SourceRange = append(cidrSet.SourceRange, line)
+			}
+		}
+
+		if cidrSets == nil {
+			cidrSets = make(map[string]*dynamic.CIDRSet)
+		}
+		cidrSets[makeID(configMap.Namespace, configMap.Name)] = cidrSet
+	}
+
+	return cidrSets
+}
+
 func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options {
 	tlsOptionsCRD := client.GetTLSOptions()
 	var tlsOptions map[string]tls.Options
@@ -831,3 +1083,61 @@ func isNamespaceAllowed(allowCrossNamespace *bool, parentNamespace, namespace st
 	// If allowCrossNamespace option is not defined the default behavior is to allow cross namespace references.
 	return allowCrossNamespace == nil || *allowCrossNamespace || parentNamespace == namespace
 }
+
+// getLoadBalancedIPs returns the IPs of the servers of the services created by the provider.
+func getLoadBalancedIPs(conf dynamic.Configuration) []string {
+	suffix := providerNamespaceSeparator + providerName
+
+	var ips []string
+	addHost := func(host string) {
+		if net.ParseIP(host) != nil {
+			ips = append(ips, host)
+		}
+	}
+
+	if conf.HTTP != nil {
+		for name, service := range conf.HTTP.Services {
+			if !strings.HasSuffix(name, suffix) || service.LoadBalancer == nil {
+				continue
+			}
+
+			for _, server := range service.LoadBalancer.Servers {
+				if u, err := url.Parse(server.URL); err == nil {
+					addHost(u.Hostname())
+				}
+			}
+		}
+	}
+
+	if conf.TCP != nil {
+		for name, service := range conf.TCP.Services {
+			if !strings.HasSuffix(name, suffix) || service.LoadBalancer == nil {
+				continue
+			}
+
+			for _, server := range service.LoadBalancer.Servers {
+				if host, _, err := net.SplitHostPort(server.Address); err == nil {
+					addHost(host)
+				}
+			}
+		}
+	}
+
+	if conf.UDP != nil {
+		for name, service := range conf.UDP.Services {
+			if !strings.HasSuffix(name, suffix) || service.LoadBalancer == nil {
+				continue
+			}
+
+			for _, server := range service.LoadBalancer.Servers {
+				if host, _, err := net.SplitHostPort(server.Address); err == nil {
+					addHost(host)
+				}
+			}
+		}
+	}
+
+	sort.Strings(ips)
+
+	return ips
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -186,6 +187,22 @@ func (c configBuilder) buildTraefikService(ctx context.Context, tService *v1alph
 		return c.buildServicesLB(ctx, tService.Namespace, tService.Spec, id, conf)
 	} else if tService.Spec.Mirroring != nil {
 		return c.buildMirroring(ctx, tService, id, conf)
+	} else if tService.Spec.Redirect != nil {
+		redirect := tService.Spec.Redirect.DeepCopy()
+		if redirect.StatusCode == 0 {
+			redirect.StatusCode = http.StatusFound
+		}
+
+		conf[id] = &dynamic.Service{Redirect: redirect}
+		return nil
+	} else if tService.Spec.Static != nil {
+		static := tService.Spec.Static.DeepCopy()
+		if static.StatusCode == 0 {
+			static.StatusCode = http.StatusOK
+		}
+
+		conf[id] = &dynamic.Service{Static: static}
+		return nil
 	}
 
 	return errors.New("unspecified service type")
@@ -52,6 +52,12 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				continue
 			}
 
+			mds, err := p.makeMiddlewareKeys(ctx, ingressRouteTCP.Namespace, route.Middlewares)
+			if err != nil {
+				logger.Errorf("Failed to create middleware keys: %v", err)
+				continue
+			}
+
 			serviceName := makeID(ingressRouteTCP.Namespace, key)
 
 			for _, service := range route.Services {
@@ -88,6 +94,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 
 			conf.Routers[serviceName] = &dynamic.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
+				Middlewares: mds,
 				Rule:        route.Match,
 				Service:     serviceName,
 			}
@@ -159,6 +166,10 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 		tcpService.LoadBalancer.TerminationDelay = service.TerminationDelay
 	}
 
+	if service.ConnectRetry != nil {
+		tcpService.LoadBalancer.ConnectRetry = &dynamic.TCPConnectRetry{Attempts: service.ConnectRetry.Attempts}
+	}
+
 	return tcpService, nil
 }
 
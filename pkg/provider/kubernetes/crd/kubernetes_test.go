@@ -94,6 +94,63 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "Simple Ingress Route, with middlewares and CIDR sets",
+			paths: []string{"tcp/services.yml", "tcp/with_middleware.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers:           map[string]*dynamic.Router{},
+					Middlewares:       map[string]*dynamic.Middleware{},
+					Services:          map[string]*dynamic.Service{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"default-test.route-fdd3e9338e47a45efefc": {
+							EntryPoints: []string{"foo"},
+							Middlewares: []string{"default-ipwhitelist", "cross-ns-inflightconn", "sniwhitelist@file"},
+							Service:     "default-test.route-fdd3e9338e47a45efefc",
+							Rule:        "HostSNI(`foo.com`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.TCPMiddleware{
+						"default-ipwhitelist": {
+							IPWhiteList: &dynamic.TCPIPWhiteList{
+								SourceRange:     []string{"127.0.0.1/32"},
+								SourceRangeSets: []string{"default-office", "shared@file"},
+							},
+						},
+						"cross-ns-inflightconn": {
+							InFlightConn: &dynamic.TCPInFlightConn{Amount: 10},
+						},
+					},
+					Services: map[string]*dynamic.TCPService{
+						"default-test.route-fdd3e9338e47a45efefc": {
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{
+									{
+										Address: "10.10.0.1:8000",
+									},
+									{
+										Address: "10.10.0.2:8000",
+									},
+								},
+							},
+						},
+					},
+					CIDRSets: map[string]*dynamic.CIDRSet{
+						"default-office": {
+							SourceRange: []string{"10.10.0.0/16", "192.168.1.0/24", "192.168.2.0/24"},
+						},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:  "One ingress Route with two different rules",
 			paths: []string{"tcp/services.yml", "tcp/with_two_rules.yml"},
@@ -1995,6 +2052,54 @@ func TestLoadIngressRoutes(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "redirect and static response services",
+			paths: []string{"with_response_services.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers: map[string]*dynamic.Router{
+						"default-test-route-77c62dfe9517144aeeaa": {
+							EntryPoints: []string{"web"},
+							Service:     "default-moved",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/foo`)",
+							Priority:    12,
+						},
+						"default-test-route-6b204d94623b3df4370c": {
+							EntryPoints: []string{"web"},
+							Service:     "default-maintenance",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:    12,
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-moved": {
+							Redirect: &dynamic.Redirection{
+								Location:   "https://new.foo.com{{ .Path }}",
+								StatusCode: 301,
+							},
+						},
+						"default-maintenance": {
+							Static: &dynamic.StaticResponse{
+								StatusCode: 200,
+								Headers:    map[string]string{"Content-Type": "text/plain"},
+								Body:       "Under maintenance",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:  "one kube service (== servers lb) in a mirroring",
 			paths: []string{"with_mirroring.yml"},
@@ -3286,6 +3391,7 @@ func TestLoadIngressRoutes(t *testing.T) {
 								DialTimeout:           types.Duration(42 * time.Second),
 								ResponseHeaderTimeout: types.Duration(42 * time.Second),
 								IdleConnTimeout:       types.Duration(42 * time.Millisecond),
+								MaxConnLifetime:       types.Duration(42 * time.Minute),
 							},
 						},
 					},
@@ -3383,6 +3489,49 @@ func TestLoadIngressRouteUDPs(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "Simple Ingress Route, with passive health check",
+			paths: []string{"udp/services.yml", "udp/with_passive_health_check.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{
+						"default-test.route-0": {
+							EntryPoints: []string{"foo"},
+							Service:     "default-test.route-0",
+						},
+					},
+					Services: map[string]*dynamic.UDPService{
+						"default-test.route-0": {
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								PassiveHealthCheck: &dynamic.UDPPassiveHealthCheck{
+									MaxFailures:    3,
+									FailureTimeout: types.Duration(10 * time.Second),
+								},
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "10.10.0.1:8000",
+									},
+									{
+										Address: "10.10.0.2:8000",
+									},
+								},
+							},
+						},
+					},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers:           map[string]*dynamic.Router{},
+					Middlewares:       map[string]*dynamic.Middleware{},
+					Services:          map[string]*dynamic.Service{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:  "One ingress Route with two different routes",
 			paths: []string{"udp/services.yml", "udp/with_two_routes.yml"},
@@ -4590,3 +4739,47 @@ func TestCrossNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestGetLoadBalancedIPs(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Services: map[string]*dynamic.Service{
+				"default-whoami-80@kubernetescrd": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://10.10.0.2:80"}, {URL: "http://[fd00::1]:80"}},
+					},
+				},
+				"default-external-80@kubernetescrd": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://external.domain:80"}},
+					},
+				},
+				"whoami@file": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://10.10.0.9:80"}},
+					},
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"default-tcp-8000@kubernetescrd": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "10.10.0.3:8000"}},
+					},
+				},
+			},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Services: map[string]*dynamic.UDPService{
+				"default-udp-8000@kubernetescrd": {
+					LoadBalancer: &dynamic.UDPServersLoadBalancer{
+						Servers: []dynamic.UDPServer{{Address: "10.10.0.1:8000"}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"10.10.0.1", "10.10.0.2", "10.10.0.3", "fd00::1"}, getLoadBalancedIPs(conf))
+}
@@ -98,6 +98,17 @@ func (p *Provider) createLoadBalancerServerUDP(client Client, parentNamespace st
 		},
 	}
 
+	if service.PassiveHealthCheck != nil {
+		udpService.LoadBalancer.PassiveHealthCheck = &dynamic.UDPPassiveHealthCheck{}
+		udpService.LoadBalancer.PassiveHealthCheck.SetDefaults()
+		if service.PassiveHealthCheck.MaxFailures > 0 {
+			udpService.LoadBalancer.PassiveHealthCheck.MaxFailures = service.PassiveHealthCheck.MaxFailures
+		}
+		if service.PassiveHealthCheck.FailureTimeout > 0 {
+			udpService.LoadBalancer.PassiveHealthCheck.FailureTimeout = service.PassiveHealthCheck.FailureTimeout
+		}
+	}
+
 	return udpService, nil
 }
 
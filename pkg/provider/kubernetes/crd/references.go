@@ -0,0 +1,228 @@
+package crd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+)
+
+// referenceChecker lists the resources referenced by the Traefik resources which do not exist (yet).
+type referenceChecker struct {
+	client Client
+
+	middlewares    map[string]*v1alpha1.Middleware
+	middlewareTCPs map[string]struct{}
+	missing        map[string]struct{}
+}
+
+// missingReferences returns the sorted list of the resources referenced by the routes,
+// directly or through their middlewares and services, which do not exist.
+// A resource which cannot be fetched for another reason than its absence is not reported,
+// as the error is not expected to be fixed by the creation of the resource.
+func (p *Provider) missingReferences(client Client) []string {
+	r := referenceChecker{
+		client:         client,
+		middlewares:    make(map[string]*v1alpha1.Middleware),
+		middlewareTCPs: make(map[string]struct{}),
+		missing:        make(map[string]struct{}),
+	}
+
+	for _, middleware := range client.GetMiddlewares() {
+		r.middlewares[refID(middleware.Namespace, middleware.Name)] = middleware
+	}
+
+	for _, middleware := range client.GetMiddlewareTCPs() {
+		r.middlewareTCPs[refID(middleware.Namespace, middleware.Name)] = struct{}{}
+	}
+
+	for _, ingressRoute := range client.GetIngressRoutes() {
+		if !shouldProcessIngress(p.IngressClass, ingressRoute.Annotations[annotationKubernetesIngressClass]) {
+			continue
+		}
+
+		if ingressRoute.Spec.TLS != nil {
+			r.checkSecret(ingressRoute.Namespace, ingressRoute.Spec.TLS.SecretName)
+		}
+
+		for _, route := range ingressRoute.Spec.Routes {
+			for _, ref := range route.Middlewares {
+				r.checkMiddleware(ingressRoute.Namespace, ref, map[string]struct{}{})
+			}
+
+			for _, service := range route.Services {
+				r.checkService(ingressRoute.Namespace, service.LoadBalancerSpec, map[string]struct{}{})
+			}
+		}
+	}
+
+	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
+		if !shouldProcessIngress(p.IngressClass, ingressRouteTCP.Annotations[annotationKubernetesIngressClass]) {
+			continue
+		}
+
+		if ingressRouteTCP.Spec.TLS != nil {
+			r.checkSecret(ingressRouteTCP.Namespace, ingressRouteTCP.Spec.TLS.SecretName)
+		}
+
+		for _, route := range ingressRouteTCP.Spec.Routes {
+			for _, ref := range route.Middlewares {
+				if strings.Contains(ref.Name, providerNamespaceSeparator) {
+					continue
+				}
+
+				id := refID(namespaceOrDefault(ref.Namespace, ingressRouteTCP.Namespace), ref.Name)
+				if _, ok := r.middlewareTCPs[id]; !ok {
+					r.missing["MiddlewareTCP "+id] = struct{}{}
+				}
+			}
+
+			for _, service := range route.Services {
+				r.checkKubernetesService(namespaceOrDefault(service.Namespace, ingressRouteTCP.Namespace), service.Name)
+			}
+		}
+	}
+
+	missing := make([]string, 0, len(r.missing))
+	for ref := range r.missing {
+		missing = append(missing, ref)
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+// checkMiddleware checks that the referenced middleware exists, as well as the resources it references.
+// The visited middlewares are tracked to stop on cyclic chains.
+func (r referenceChecker) checkMiddleware(parentNamespace string, ref v1alpha1.MiddlewareRef, visited map[string]struct{}) {
+	if strings.Contains(ref.Name, providerNamespaceSeparator) {
+		return
+	}
+
+	id := refID(namespaceOrDefault(ref.Namespace, parentNamespace), ref.Name)
+	if _, ok := visited[id]; ok {
+		return
+	}
+	visited[id] = struct{}{}
+
+	middleware, ok := r.middlewares[id]
+	if !ok {
+		r.missing["Middleware "+id] = struct{}{}
+		return
+	}
+
+	spec := middleware.Spec
+	namespace := middleware.Namespace
+
+	if spec.BasicAuth != nil {
+		r.checkSecret(namespace, spec.BasicAuth.Secret)
+	}
+
+	if spec.DigestAuth != nil {
+		r.checkSecret(namespace, spec.DigestAuth.Secret)
+	}
+
+	if spec.ForwardAuth != nil && spec.ForwardAuth.TLS != nil {
+		r.checkSecret(namespace, spec.ForwardAuth.TLS.CASecret)
+		r.checkSecret(namespace, spec.ForwardAuth.TLS.CertSecret)
+	}
+
+	if spec.Errors != nil {
+		r.checkService(namespace, spec.Errors.Service.LoadBalancerSpec, map[string]struct{}{})
+
+		for _, page := range spec.Errors.StatusPages {
+			r.checkService(namespace, page.Service.LoadBalancerSpec, map[string]struct{}{})
+		}
+	}
+
+	if spec.Chain != nil {
+		for _, chained := range spec.Chain.Middlewares {
+			r.checkMiddleware(namespace, chained, visited)
+		}
+	}
+}
+
+// checkService checks that the referenced service exists,
+// as well as the services it references when it is a TraefikService.
+// The visited TraefikServices are tracked to stop on cyclic references.
+func (r referenceChecker) checkService(parentNamespace string, service v1alpha1.LoadBalancerSpec, visited map[string]struct{}) {
+	if service.Name == "" {
+		return
+	}
+
+	namespace := namespaceOrDefault(service.Namespace, parentNamespace)
+
+	switch service.Kind {
+	case "", "Service":
+		r.checkKubernetesService(namespace, service.Name)
+
+	case "TraefikService":
+		if strings.Contains(service.Name, providerNamespaceSeparator) {
+			return
+		}
+
+		id := refID(namespace, service.Name)
+		if _, ok := visited[id]; ok {
+			return
+		}
+		visited[id] = struct{}{}
+
+		traefikService, exists, err := r.client.GetTraefikService(namespace, service.Name)
+		if err != nil {
+			return
+		}
+
+		if !exists {
+			r.missing["TraefikService "+id] = struct{}{}
+			return
+		}
+
+		if traefikService.Spec.Weighted != nil {
+			for _, weighted := range traefikService.Spec.Weighted.Services {
+				r.checkService(namespace, weighted.LoadBalancerSpec, visited)
+			}
+		}
+
+		if traefikService.Spec.Mirroring != nil {
+			r.checkService(namespace, traefikService.Spec.Mirroring.LoadBalancerSpec, visited)
+
+			for _, mirror := range traefikService.Spec.Mirroring.Mirrors {
+				r.checkService(namespace, mirror.LoadBalancerSpec, visited)
+			}
+		}
+	}
+}
+
+func (r referenceChecker) checkKubernetesService(namespace, name string) {
+	if name == "" {
+		return
+	}
+
+	if _, exists, err := r.client.GetService(namespace, name); err == nil && !exists {
+		r.missing["Service "+refID(namespace, name)] = struct{}{}
+	}
+}
+
+func (r referenceChecker) checkSecret(namespace, name string) {
+	if name == "" {
+		return
+	}
+
+	if _, exists, err := r.client.GetSecret(namespace, name); err == nil && !exists {
+		r.missing["Secret "+refID(namespace, name)] = struct{}{}
+	}
+}
+
+func namespaceOrDefault(namespace, defaultNamespace string) string {
+	if namespace == "" {
+		return defaultNamespace
+	}
+
+	return namespace
+}
+
+// refID returns the identifier of a referenced resource, as reported in the logs.
+func refID(namespace, name string) string {
+	return namespace + "/" + name
+}
@@ -0,0 +1,51 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingReferences(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		ingressClass string
+		paths        []string
+		expected     []string
+	}{
+		{
+			desc:     "all references exist",
+			paths:    []string{"services.yml", "with_middleware.yml"},
+			expected: []string{},
+		},
+		{
+			desc:  "missing references",
+			paths: []string{"services.yml", "with_missing_references.yml"},
+			expected: []string{
+				"Middleware foo/stripprefix",
+				"MiddlewareTCP default/ipwhitelist",
+				"Secret default/authsecret",
+				"Secret default/supersecret",
+				"Service default/whoamitcp",
+				"TraefikService default/wrr",
+			},
+		},
+		{
+			desc:         "ignored ingress class",
+			ingressClass: "foo",
+			paths:        []string{"services.yml", "with_missing_references.yml"},
+			expected:     []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{IngressClass: test.ingressClass}
+
+			assert.Equal(t, test.expected, p.missingReferences(newClientMock(test.paths...)))
+		})
+	}
+}
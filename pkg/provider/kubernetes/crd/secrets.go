@@ -0,0 +1,30 @@
+package crd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
+)
+
+// qualifySecretReferences returns a copy of the middleware options whose Kubernetes secret references,
+// such as ${kubernetes:name/key}, are qualified with the namespace of the middleware,
+// so that a middleware can only reference the secrets of its own namespace.
+func qualifySecretReferences(namespace string, middleware *dynamic.Middleware) (*dynamic.Middleware, error) {
+	rewriter := interpolate.NewRewriter([]string{"kubernetes"}, func(kind, name string) (string, error) {
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", fmt.Errorf("invalid Kubernetes secret reference %q, name/key is expected, the secret being in the namespace of the middleware", name)
+		}
+
+		return "${kubernetes:" + namespace + "/" + name + "}", nil
+	})
+
+	qualified := middleware.DeepCopy()
+	if err := rewriter.Interpolate(qualified); err != nil {
+		return nil, err
+	}
+
+	return qualified, nil
+}
@@ -0,0 +1,72 @@
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestQualifySecretReferences(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		value         string
+		expected      string
+		expectedError bool
+	}{
+		{
+			desc:     "without reference",
+			value:    "Bearer token",
+			expected: "Bearer token",
+		},
+		{
+			desc:     "Kubernetes reference",
+			value:    "Bearer ${kubernetes:backend/token}",
+			expected: "Bearer ${kubernetes:default/backend/token}",
+		},
+		{
+			desc:     "escaped Kubernetes reference",
+			value:    "$${kubernetes:backend/token}",
+			expected: "$${kubernetes:backend/token}",
+		},
+		{
+			desc:     "other references",
+			value:    "${vault:secret/data/backend#token} ${env:TOKEN}",
+			expected: "${vault:secret/data/backend#token} ${env:TOKEN}",
+		},
+		{
+			desc:          "reference to another namespace",
+			value:         "${kubernetes:kube-system/backend/token}",
+			expectedError: true,
+		},
+		{
+			desc:          "reference without key",
+			value:         "${kubernetes:backend}",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			middleware := &dynamic.Middleware{Headers: &dynamic.Headers{
+				CustomRequestHeaders: map[string]string{"Authorization": test.value},
+			}}
+
+			qualified, err := qualifySecretReferences("default", middleware)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, qualified.Headers.CustomRequestHeaders["Authorization"])
+
+			// The options read from the Kubernetes resources must not be modified.
+			assert.Equal(t, test.value, middleware.Headers.CustomRequestHeaders["Authorization"])
+		})
+	}
+}
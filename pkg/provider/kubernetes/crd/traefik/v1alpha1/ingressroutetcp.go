@@ -18,6 +18,8 @@ type IngressRouteTCPSpec struct {
 type RouteTCP struct {
 	Match    string       `json:"match"`
 	Services []ServiceTCP `json:"services,omitempty"`
+	// Middlewares are the references to the MiddlewareTCP resources applied to the connections of the route.
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
 }
 
 // TLSTCP contains the TLS certificates configuration of the routes.
@@ -55,12 +57,13 @@ type TLSStoreTCPRef struct {
 
 // ServiceTCP defines an upstream to proxy traffic.
 type ServiceTCP struct {
-	Name             string                 `json:"name"`
-	Namespace        string                 `json:"namespace"`
-	Port             intstr.IntOrString     `json:"port"`
-	Weight           *int                   `json:"weight,omitempty"`
-	TerminationDelay *int                   `json:"terminationDelay,omitempty"`
-	ProxyProtocol    *dynamic.ProxyProtocol `json:"proxyProtocol,omitempty"`
+	Name             string                   `json:"name"`
+	Namespace        string                   `json:"namespace"`
+	Port             intstr.IntOrString       `json:"port"`
+	Weight           *int                     `json:"weight,omitempty"`
+	TerminationDelay *int                     `json:"terminationDelay,omitempty"`
+	ProxyProtocol    *dynamic.ProxyProtocol   `json:"proxyProtocol,omitempty"`
+	ConnectRetry     *dynamic.TCPConnectRetry `json:"connectRetry,omitempty"`
 }
 
 // +genclient
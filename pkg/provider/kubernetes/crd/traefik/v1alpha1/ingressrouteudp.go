@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -24,10 +25,11 @@ type TLSOptionUDPRef struct {
 
 // ServiceUDP defines an upstream to proxy traffic.
 type ServiceUDP struct {
-	Name      string             `json:"name"`
-	Namespace string             `json:"namespace"`
-	Port      intstr.IntOrString `json:"port"`
-	Weight    *int               `json:"weight,omitempty"`
+	Name               string                         `json:"name"`
+	Namespace          string                         `json:"namespace"`
+	Port               intstr.IntOrString             `json:"port"`
+	Weight             *int                           `json:"weight,omitempty"`
+	PassiveHealthCheck *dynamic.UDPPassiveHealthCheck `json:"passiveHealthCheck,omitempty"`
 }
 
 // +genclient
@@ -42,6 +42,20 @@ type MiddlewareSpec struct {
 	PassTLSClientCert *dynamic.PassTLSClientCert    `json:"passTLSClientCert,omitempty"`
 	Retry             *dynamic.Retry                `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType          `json:"contentType,omitempty"`
+	CORS              *dynamic.CORS                 `json:"cors,omitempty"`
+	RequestTimeout    *dynamic.RequestTimeout       `json:"requestTimeout,omitempty"`
+	GeoIP             *dynamic.GeoIP                `json:"geoIP,omitempty"`
+	Experiment        *dynamic.Experiment           `json:"experiment,omitempty"`
+	Maintenance       *dynamic.Maintenance          `json:"maintenance,omitempty"`
+	GRPCWeb           *dynamic.GRPCWeb              `json:"grpcWeb,omitempty"`
+	WebSocket         *dynamic.WebSocket            `json:"webSocket,omitempty"`
+	ExternalProcessor *dynamic.ExternalProcessor    `json:"externalProcessor,omitempty"`
+	EarlyHints        *dynamic.EarlyHints           `json:"earlyHints,omitempty"`
+	Limits            *dynamic.Limits               `json:"limits,omitempty"`
+	SAML              *dynamic.SAML                 `json:"saml,omitempty"`
+	Redirects         *dynamic.Redirects            `json:"redirects,omitempty"`
+	Decompress        *dynamic.Decompress           `json:"decompress,omitempty"`
+	SecurityHeaders   *dynamic.SecurityHeaders      `json:"securityHeaders,omitempty"`
 	Plugin            map[string]dynamic.PluginConf `json:"plugin,omitempty"`
 }
 
@@ -49,9 +63,23 @@ type MiddlewareSpec struct {
 
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
-	Status  []string `json:"status,omitempty"`
-	Service Service  `json:"service,omitempty"`
-	Query   string   `json:"query,omitempty"`
+	Status      []string          `json:"status,omitempty"`
+	Service     Service           `json:"service,omitempty"`
+	Query       string            `json:"query,omitempty"`
+	Template    string            `json:"template,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	StatusPages []StatusErrorPage `json:"statusPages,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StatusErrorPage holds the error page of specific status codes.
+type StatusErrorPage struct {
+	Status      []string `json:"status,omitempty"`
+	Service     Service  `json:"service,omitempty"`
+	Query       string   `json:"query,omitempty"`
+	Template    string   `json:"template,omitempty"`
+	ContentType string   `json:"contentType,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -65,10 +93,11 @@ type Chain struct {
 
 // BasicAuth holds the HTTP basic authentication configuration.
 type BasicAuth struct {
-	Secret       string `json:"secret,omitempty"`
-	Realm        string `json:"realm,omitempty"`
-	RemoveHeader bool   `json:"removeHeader,omitempty"`
-	HeaderField  string `json:"headerField,omitempty"`
+	Secret       string            `json:"secret,omitempty"`
+	Realm        string            `json:"realm,omitempty"`
+	RemoveHeader bool              `json:"removeHeader,omitempty"`
+	HeaderField  string            `json:"headerField,omitempty"`
+	LDAP         *dynamic.LDAPAuth `json:"ldap,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -26,6 +26,9 @@ type ServersTransportSpec struct {
 	CertificatesSecrets []string            `description:"Certificates for mTLS." json:"certificatesSecrets,omitempty" toml:"certificatesSecrets,omitempty" yaml:"certificatesSecrets,omitempty"`
 	MaxIdleConnsPerHost int                 `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	Protocol            string              `description:"Protocol used to contact the servers: http/1.1, h2, h2c, or h3. If empty, HTTP/2 is negotiated with ALPN, and HTTP/1.1 is used otherwise." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+	Resolver            *ServersResolver    `description:"DNS resolver used to resolve the servers addresses, instead of the system resolver." json:"resolver,omitempty" toml:"resolver,omitempty" yaml:"resolver,omitempty" export:"true"`
+	Hedging             *Hedging            `description:"Sends a second attempt of the idempotent requests to another server, when the first one is slow to respond." json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -35,6 +38,23 @@ type ForwardingTimeouts struct {
 	DialTimeout           *intstr.IntOrString `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
 	ResponseHeaderTimeout *intstr.IntOrString `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
 	IdleConnTimeout       *intstr.IntOrString `description:"The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself" json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty" export:"true"`
+	MaxConnLifetime       *intstr.IntOrString `description:"The maximum period for which a connection to a backend server is reused, before being closed once idle to dial a new one. If zero, the connections are reused without limit." json:"maxConnLifetime,omitempty" toml:"maxConnLifetime,omitempty" yaml:"maxConnLifetime,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersResolver holds the DNS resolver configuration used to resolve the servers addresses.
+type ServersResolver struct {
+	Servers []string            `description:"DNS servers (host:port) to query. If empty, the servers of /etc/resolv.conf are queried." json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" export:"true"`
+	MinTTL  *intstr.IntOrString `description:"Minimum duration for which a DNS answer is cached, whatever its TTL." json:"minTTL,omitempty" toml:"minTTL,omitempty" yaml:"minTTL,omitempty" export:"true"`
+	MaxTTL  *intstr.IntOrString `description:"Maximum duration for which a DNS answer is cached, whatever its TTL." json:"maxTTL,omitempty" toml:"maxTTL,omitempty" yaml:"maxTTL,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Hedging holds the hedging policy of the idempotent requests forwarded with a servers transport.
+type Hedging struct {
+	Delay *intstr.IntOrString `description:"Time to wait for the response headers of the first attempt, before sending the second attempt to another server." json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -31,11 +31,13 @@ type TraefikServiceList struct {
 
 // +k8s:deepcopy-gen=true
 
-// ServiceSpec defines whether a TraefikService is a load-balancer of services or a
-// mirroring service.
+// ServiceSpec defines whether a TraefikService is a load-balancer of services, a
+// mirroring service, or a service answering the requests by itself.
 type ServiceSpec struct {
-	Weighted  *WeightedRoundRobin `json:"weighted,omitempty"`
-	Mirroring *Mirroring          `json:"mirroring,omitempty"`
+	Weighted  *WeightedRoundRobin     `json:"weighted,omitempty"`
+	Mirroring *Mirroring              `json:"mirroring,omitempty"`
+	Redirect  *dynamic.Redirection    `json:"redirect,omitempty"`
+	Static    *dynamic.StaticResponse `json:"static,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -0,0 +1,246 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxAdmissionReviewSize is the maximum size of the admission reviews sent by the Kubernetes API server.
+const maxAdmissionReviewSize = 3 * 1024 * 1024
+
+// webhookObjects creates, by kind, the objects into which the admitted resources are decoded.
+var webhookObjects = map[string]func() interface{}{
+	"IngressRoute":     func() interface{} { return &v1alpha1.IngressRoute{} },
+	"IngressRouteTCP":  func() interface{} { return &v1alpha1.IngressRouteTCP{} },
+	"IngressRouteUDP":  func() interface{} { return &v1alpha1.IngressRouteUDP{} },
+	"Middleware":       func() interface{} { return &v1alpha1.Middleware{} },
+	"MiddlewareTCP":    func() interface{} { return &v1alpha1.MiddlewareTCP{} },
+	"TraefikService":   func() interface{} { return &v1alpha1.TraefikService{} },
+	"TLSOption":        func() interface{} { return &v1alpha1.TLSOption{} },
+	"TLSStore":         func() interface{} { return &v1alpha1.TLSStore{} },
+	"ServersTransport": func() interface{} { return &v1alpha1.ServersTransport{} },
+}
+
+// Webhook is the admission webhook checking the Traefik resources when they are applied.
+// The resources sent to its /validate path are rejected when they hold fields or options unknown to this version of Traefik,
+// and the ones sent to its /default path are patched with the default values of their options.
+type Webhook struct {
+	EntryPoint    string `description:"EntryPoint" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty" export:"true"`
+	ManualRouting bool   `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (w *Webhook) SetDefaults() {
+	w.EntryPoint = "traefik"
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review admissionv1.AdmissionReview
+	err := json.NewDecoder(io.LimitReader(req.Body, maxAdmissionReviewSize)).Decode(&review)
+	if err != nil || review.Request == nil {
+		http.Error(rw, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	var response *admissionv1.AdmissionResponse
+	switch path.Base(req.URL.Path) {
+	case "validate":
+		response = validate(review.Request)
+	case "default":
+		response = setDefaults(review.Request)
+	default:
+		http.NotFound(rw, req)
+		return
+	}
+
+	response.UID = review.Request.UID
+	review.Request = nil
+	review.Response = response
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to write the admission review: %v", err)
+	}
+}
+
+// validate allows the resource when it is made of known fields holding valid options.
+func validate(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	// The deleted resources are not sent along with the request.
+	if request.Operation == admissionv1.Delete {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	if err := validateObject(request.Kind.Kind, request.Object.Raw); err != nil {
+		return &admissionv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: fmt.Sprintf("invalid %s %s: %v", request.Kind.Kind, request.Name, err),
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			},
+		}
+	}
+
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func validateObject(kind string, raw []byte) error {
+	newObject, ok := webhookObjects[kind]
+	if !ok {
+		return fmt.Errorf("unsupported kind %s", kind)
+	}
+
+	object := newObject()
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(object); err != nil {
+		return err
+	}
+
+	switch o := object.(type) {
+	case *v1alpha1.IngressRoute:
+		return validateIngressRoute(o.Spec)
+	case *v1alpha1.Middleware:
+		if count := countSetFields(o.Spec); count != 1 {
+			return fmt.Errorf("a middleware must define exactly one middleware type, got %d", count)
+		}
+	case *v1alpha1.MiddlewareTCP:
+		if count := countSetFields(o.Spec); count != 1 {
+			return fmt.Errorf("a TCP middleware must define exactly one middleware type, got %d", count)
+		}
+	case *v1alpha1.TraefikService:
+		if count := countSetFields(o.Spec); count != 1 {
+			return fmt.Errorf("a TraefikService must define exactly one of weighted, mirroring, redirect, or static, got %d", count)
+		}
+	}
+
+	return nil
+}
+
+func validateIngressRoute(spec v1alpha1.IngressRouteSpec) error {
+	router, err := rules.NewRouter()
+	if err != nil {
+		return err
+	}
+
+	for i, route := range spec.Routes {
+		if route.Kind != "Rule" {
+			return fmt.Errorf("routes[%d]: unsupported match kind %q, only \"Rule\" is supported", i, route.Kind)
+		}
+
+		if route.Match == "" {
+			return fmt.Errorf("routes[%d]: empty match", i)
+		}
+
+		if err := router.AddRoute(route.Match, 0, http.NotFoundHandler()); err != nil {
+			return fmt.Errorf("routes[%d]: invalid match: %w", i, err)
+		}
+
+		for j, service := range route.Services {
+			if service.Name == "" {
+				return fmt.Errorf("routes[%d].services[%d]: empty name", i, j)
+			}
+
+			if service.Kind != "" && service.Kind != "Service" && service.Kind != "TraefikService" {
+				return fmt.Errorf("routes[%d].services[%d]: unsupported service kind %q", i, j, service.Kind)
+			}
+		}
+	}
+
+	return nil
+}
+
+// countSetFields counts the non-nil fields of the struct, the entries of its maps being counted one by one.
+func countSetFields(v interface{}) int {
+	var count int
+
+	value := reflect.ValueOf(v)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.Map:
+			count += field.Len()
+		case reflect.Ptr:
+			if !field.IsNil() {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// jsonPatchOperation is an operation of the JSON patches applied to the resources.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// setDefaults patches the IngressRoute resources with the match kind and the service kinds they omit.
+func setDefaults(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Kind.Kind != "IngressRoute" || request.Operation == admissionv1.Delete {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	var ingressRoute v1alpha1.IngressRoute
+	if err := json.Unmarshal(request.Object.Raw, &ingressRoute); err != nil {
+		return &admissionv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: fmt.Sprintf("invalid IngressRoute %s: %v", request.Name, err),
+				Reason:  metav1.StatusReasonBadRequest,
+				Code:    http.StatusBadRequest,
+			},
+		}
+	}
+
+	var patch []jsonPatchOperation
+	for i, route := range ingressRoute.Spec.Routes {
+		if route.Kind == "" {
+			patch = append(patch, jsonPatchOperation{Op: "add", Path: fmt.Sprintf("/spec/routes/%d/kind", i), Value: "Rule"})
+		}
+
+		for j, service := range route.Services {
+			if service.Kind == "" {
+				patch = append(patch, jsonPatchOperation{Op: "add", Path: fmt.Sprintf("/spec/routes/%d/services/%d/kind", i, j), Value: "Service"})
+			}
+		}
+	}
+
+	if len(patch) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	rawPatch, err := json.Marshal(patch)
+	if err != nil {
+		return &admissionv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: fmt.Sprintf("unable to create the patch: %v", err),
+				Code:    http.StatusInternalServerError,
+			},
+		}
+	}
+
+	patchType := admissionv1.PatchTypeJSONPatch
+
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: rawPatch, PatchType: &patchType}
+}
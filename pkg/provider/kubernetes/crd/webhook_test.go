@@ -0,0 +1,201 @@
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestWebhook_validate(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		kind            string
+		object          string
+		expectedAllowed bool
+	}{
+		{
+			desc:            "valid IngressRoute",
+			kind:            "IngressRoute",
+			object:          `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","kind":"Rule","services":[{"name":"whoami","port":80}]}]}}`,
+			expectedAllowed: true,
+		},
+		{
+			desc:   "IngressRoute with an unknown field",
+			kind:   "IngressRoute",
+			object: `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","kind":"Rule","unknown":true}]}}`,
+		},
+		{
+			desc:   "IngressRoute with an invalid match",
+			kind:   "IngressRoute",
+			object: `{"spec":{"routes":[{"match":"Unknown(` + "`foo.com`" + `)","kind":"Rule"}]}}`,
+		},
+		{
+			desc:   "IngressRoute with an unsupported match kind",
+			kind:   "IngressRoute",
+			object: `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","kind":"Regexp"}]}}`,
+		},
+		{
+			desc:   "IngressRoute with an unsupported service kind",
+			kind:   "IngressRoute",
+			object: `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","kind":"Rule","services":[{"name":"whoami","kind":"Unknown"}]}]}}`,
+		},
+		{
+			desc:            "valid Middleware",
+			kind:            "Middleware",
+			object:          `{"spec":{"addPrefix":{"prefix":"/foo"}}}`,
+			expectedAllowed: true,
+		},
+		{
+			desc:   "Middleware with an unknown option",
+			kind:   "Middleware",
+			object: `{"spec":{"addPrefix":{"prefix":"/foo","unknown":true}}}`,
+		},
+		{
+			desc:   "Middleware with an unknown type",
+			kind:   "Middleware",
+			object: `{"spec":{"unknown":{}}}`,
+		},
+		{
+			desc:   "Middleware with two types",
+			kind:   "Middleware",
+			object: `{"spec":{"addPrefix":{"prefix":"/foo"},"stripPrefix":{"prefixes":["/bar"]}}}`,
+		},
+		{
+			desc:            "valid MiddlewareTCP",
+			kind:            "MiddlewareTCP",
+			object:          `{"spec":{"ipWhiteList":{"sourceRange":["10.0.0.0/8"],"sourceRangeSets":["office"]}}}`,
+			expectedAllowed: true,
+		},
+		{
+			desc:   "MiddlewareTCP with two types",
+			kind:   "MiddlewareTCP",
+			object: `{"spec":{"ipWhiteList":{"sourceRange":["10.0.0.0/8"]},"inFlightConn":{"amount":10}}}`,
+		},
+		{
+			desc:            "valid TraefikService",
+			kind:            "TraefikService",
+			object:          `{"spec":{"weighted":{"services":[{"name":"whoami","port":80,"weight":1}]}}}`,
+			expectedAllowed: true,
+		},
+		{
+			desc:   "TraefikService without service type",
+			kind:   "TraefikService",
+			object: `{"spec":{}}`,
+		},
+		{
+			desc:   "unsupported kind",
+			kind:   "Unknown",
+			object: `{"spec":{}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			response := serveAdmissionReview(t, "/kubernetes/webhook/validate", test.kind, test.object)
+
+			assert.Equal(t, test.expectedAllowed, response.Allowed)
+			if !test.expectedAllowed {
+				require.NotNil(t, response.Result)
+				assert.NotEmpty(t, response.Result.Message)
+			}
+		})
+	}
+}
+
+func TestWebhook_default(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		kind          string
+		object        string
+		expectedPatch string
+	}{
+		{
+			desc:          "IngressRoute without kinds",
+			kind:          "IngressRoute",
+			object:        `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","services":[{"name":"whoami"},{"name":"wrr","kind":"TraefikService"}]}]}}`,
+			expectedPatch: `[{"op":"add","path":"/spec/routes/0/kind","value":"Rule"},{"op":"add","path":"/spec/routes/0/services/0/kind","value":"Service"}]`,
+		},
+		{
+			desc:   "IngressRoute with kinds",
+			kind:   "IngressRoute",
+			object: `{"spec":{"routes":[{"match":"Host(` + "`foo.com`" + `)","kind":"Rule","services":[{"name":"whoami","kind":"Service"}]}]}}`,
+		},
+		{
+			desc:   "Middleware",
+			kind:   "Middleware",
+			object: `{"spec":{"addPrefix":{"prefix":"/foo"}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			response := serveAdmissionReview(t, "/kubernetes/webhook/default", test.kind, test.object)
+
+			assert.True(t, response.Allowed)
+
+			if test.expectedPatch == "" {
+				assert.Nil(t, response.Patch)
+				return
+			}
+
+			require.NotNil(t, response.PatchType)
+			assert.Equal(t, admissionv1.PatchTypeJSONPatch, *response.PatchType)
+			assert.JSONEq(t, test.expectedPatch, string(response.Patch))
+		})
+	}
+}
+
+func TestWebhook_invalidRequest(t *testing.T) {
+	webhook := &Webhook{}
+
+	rw := httptest.NewRecorder()
+	webhook.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/kubernetes/webhook/validate", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+
+	rw = httptest.NewRecorder()
+	webhook.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/kubernetes/webhook/validate", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
+
+// serveAdmissionReview sends an admission review for the object to the webhook, and returns its response.
+func serveAdmissionReview(t *testing.T, path, kind, object string) *admissionv1.AdmissionResponse {
+	t.Helper()
+
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       "uid",
+			Kind:      metav1.GroupVersionKind{Group: "traefik.containo.us", Version: "v1alpha1", Kind: kind},
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: []byte(object)},
+		},
+	}
+
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	(&Webhook{}).ServeHTTP(rw, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rw.Code)
+
+	var result admissionv1.AdmissionReview
+	require.NoError(t, json.NewDecoder(rw.Body).Decode(&result))
+	require.NotNil(t, result.Response)
+	assert.Equal(t, review.Request.UID, result.Response.UID)
+
+	return result.Response
+}
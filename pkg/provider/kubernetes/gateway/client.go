@@ -12,10 +12,13 @@ import (
 	kubeerror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/service-apis/apis/v1alpha1"
 	"sigs.k8s.io/service-apis/pkg/client/clientset/versioned"
 	"sigs.k8s.io/service-apis/pkg/client/informers/externalversions"
@@ -60,6 +63,8 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+
+	RecordEvent(object runtime.Object, eventType, reason, message string)
 }
 
 type clientWrapper struct {
@@ -75,6 +80,8 @@ type clientWrapper struct {
 	watchedNamespaces []string
 
 	labelSelector string
+
+	recorder record.EventRecorder
 }
 
 func createClientFromConfig(c *rest.Config) (*clientWrapper, error) {
@@ -161,6 +168,14 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 
 	c.watchedNamespaces = namespaces
 
+	if c.recorder == nil {
+		recorder, err := c.newEventRecorder(stopCh)
+		if err != nil {
+			return nil, err
+		}
+		c.recorder = recorder
+	}
+
 	notOwnedByHelm := func(opts *metav1.ListOptions) {
 		opts.LabelSelector = "owner!=helm"
 	}
@@ -226,6 +241,33 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 	return eventCh, nil
 }
 
+// newEventRecorder creates a recorder publishing the events on the Gateway API resources, until stopCh is closed.
+func (c *clientWrapper) newEventRecorder(stopCh <-chan struct{}) (record.EventRecorder, error) {
+	eventScheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(eventScheme); err != nil {
+		return nil, fmt.Errorf("failed to create the events scheme: %w", err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.csKube.CoreV1().Events("")})
+
+	go func() {
+		<-stopCh
+		broadcaster.Shutdown()
+	}()
+
+	return broadcaster.NewRecorder(eventScheme, corev1.EventSource{Component: "traefik"}), nil
+}
+
+// RecordEvent records an event on the given Gateway API resource.
+func (c *clientWrapper) RecordEvent(object runtime.Object, eventType, reason, message string) {
+	if c.recorder == nil {
+		return
+	}
+
+	c.recorder.Event(object, eventType, reason, message)
+}
+
 func (c *clientWrapper) GetHTTPRoutes(namespace string, selector labels.Selector) ([]*v1alpha1.HTTPRoute, error) {
 	if !c.isWatchedNamespace(namespace) {
 		return nil, fmt.Errorf("failed to get HTTPRoute %s with labels selector %s: namespace is not within watched namespaces", namespace, selector)
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/service-apis/apis/v1alpha1"
 )
@@ -36,11 +37,13 @@ type clientMock struct {
 	gateways       []*v1alpha1.Gateway
 	httpRoutes     []*v1alpha1.HTTPRoute
 
+	events *[]string
+
 	watchChan chan interface{}
 }
 
 func newClientMock(paths ...string) clientMock {
-	var c clientMock
+	c := clientMock{events: new([]string)}
 
 	for _, path := range paths {
 		yamlContent, err := ioutil.ReadFile(filepath.FromSlash("./fixtures/" + path))
@@ -176,6 +179,10 @@ func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, err
 	return nil, false, nil
 }
 
+func (c clientMock) RecordEvent(object runtime.Object, eventType, reason, message string) {
+	*c.events = append(*c.events, eventType+" "+reason+" "+message)
+}
+
 func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error) {
 	return c.watchChan, nil
 }
@@ -19,7 +19,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
@@ -30,17 +32,49 @@ import (
 
 const providerName = "kubernetesgateway"
 
+// The TraefikService resources of the Kubernetes CRD provider, which can be referenced by the backendRefs.
+const (
+	crdProviderName     = "kubernetescrd"
+	traefikServiceGroup = "traefik.containo.us"
+	traefikServiceKind  = "TraefikService"
+)
+
+// The only kind of routes which can be attached to the listeners.
+const (
+	routesGroup = "networking.x-k8s.io"
+	routesKind  = "HTTPRoute"
+)
+
+// The reasons of the events recorded on the HTTPRoutes whose rules cannot be translated.
+const (
+	eventReasonUnsupportedRule   = "UnsupportedRule"
+	eventReasonUnsupportedFilter = "UnsupportedFilter"
+	eventReasonInvalidBackend    = "InvalidBackend"
+)
+
 // Provider holds configurations of the provider.
 type Provider struct {
-	Endpoint         string                `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	Token            string                `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
-	CertAuthFilePath string                `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
-	Namespaces       []string              `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
-	LabelSelector    string                `description:"Kubernetes label selector to select specific GatewayClasses." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
-	ThrottleDuration ptypes.Duration       `description:"Kubernetes refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
-	EntryPoints      map[string]Entrypoint `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+	Endpoint          string                 `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token             string                 `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath  string                 `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces        []string               `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	LabelSelector     string                 `description:"Kubernetes label selector to select specific GatewayClasses." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	ThrottleDuration  ptypes.Duration        `description:"Kubernetes refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	GenerationBarrier *k8s.GenerationBarrier `description:"Holds back the configuration while resources referenced by the Gateways and their routes are missing." json:"generationBarrier,omitempty" toml:"generationBarrier,omitempty" yaml:"generationBarrier,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EntryPoints       map[string]Entrypoint  `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
 
 	lastConfiguration safe.Safe
+	synced            provider.SyncState
+
+	metricsRegistry metrics.Registry
+	attachedRoutes  map[listenerRef]int
+}
+
+// listenerRef identifies a listener of a Gateway.
+type listenerRef struct {
+	namespace string
+	gateway   string
+	port      v1alpha1.PortNumber
 }
 
 // Entrypoint defines the available entry points.
@@ -83,11 +117,22 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	return client, nil
 }
 
+// SetMetricsRegistry sets the registry reporting the number of routes attached to the listeners of the Gateways.
+func (p *Provider) SetMetricsRegistry(registry metrics.Registry) {
+	p.metricsRegistry = registry
+}
+
 // Init the provider.
 func (p *Provider) Init() error {
 	return nil
 }
 
+// Synced returns the name of the provider, and a channel closed once the configuration
+// built from the synced informers caches has been provided.
+func (p *Provider) Synced() (string, <-chan struct{}) {
+	return providerName, p.synced.Done()
+}
+
 // Provide allows the k8s provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -119,29 +164,62 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 				eventsChan = throttledChan
 			}
 
+			var barrier *k8s.Barrier
+			if p.GenerationBarrier != nil {
+				barrier = k8s.NewBarrier(*p.GenerationBarrier)
+			}
+
+			// retry fires when a configuration held back by the generation barrier has to be evaluated again.
+			var retry <-chan time.Time
+
+			provide := func(event interface{}) {
+				if barrier != nil {
+					missing := p.missingReferences(k8sClient)
+
+					wait, hold := barrier.Hold(missing)
+					if hold {
+						logger.Infof("Holding back the configuration for at most %s, until the referenced resources exist: %s", wait, strings.Join(missing, ", "))
+						retry = time.After(wait)
+						return
+					}
+
+					retry = nil
+
+					if len(missing) > 0 {
+						logger.Warnf("Applying the configuration with missing referenced resources: %s", strings.Join(missing, ", "))
+					}
+				}
+
+				conf := p.loadConfigurationFromGateway(ctxLog, k8sClient)
+
+				confHash, err := hashstructure.Hash(conf, nil)
+				switch {
+				case err != nil:
+					logger.Error("Unable to hash the configuration")
+				case p.lastConfiguration.Get() == confHash:
+					logger.Debugf("Skipping Kubernetes event kind %T", event)
+				default:
+					p.lastConfiguration.Set(confHash)
+					configurationChan <- dynamic.Message{
+						ProviderName:  providerName,
+						Configuration: conf,
+					}
+				}
+
+				p.synced.MarkSynced()
+			}
+
 			for {
 				select {
 				case <-ctxPool.Done():
 					return nil
+				case <-retry:
+					provide(nil)
 				case event := <-eventsChan:
 					// Note that event is the *first* event that came in during this throttling interval -- if we're hitting our throttle, we may have dropped events.
 					// This is fine, because we don't treat different event types differently.
 					// But if we do in the future, we'll need to track more information about the dropped events.
-					conf := p.loadConfigurationFromGateway(ctxLog, k8sClient)
-
-					confHash, err := hashstructure.Hash(conf, nil)
-					switch {
-					case err != nil:
-						logger.Error("Unable to hash the configuration")
-					case p.lastConfiguration.Get() == confHash:
-						logger.Debugf("Skipping Kubernetes event kind %T", event)
-					default:
-						p.lastConfiguration.Set(confHash)
-						configurationChan <- dynamic.Message{
-							ProviderName:  providerName,
-							Configuration: conf,
-						}
-					}
+					provide(event)
 
 					// If we're throttling,
 					// we sleep here for the throttle duration to enforce that we don't refresh faster than our throttle.
@@ -208,6 +286,7 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 	}
 
 	cfgs := map[string]*dynamic.Configuration{}
+	attachedRoutes := map[listenerRef]int{}
 
 	// TODO check if we can only use the default filtering mechanism
 	for _, gateway := range client.GetGateways() {
@@ -218,7 +297,7 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 			continue
 		}
 
-		cfg, err := p.createGatewayConf(client, gateway)
+		cfg, err := p.createGatewayConf(client, gateway, attachedRoutes)
 		if err != nil {
 			logger.Error(err)
 			continue
@@ -227,6 +306,8 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 		cfgs[gateway.Name+gateway.Namespace] = cfg
 	}
 
+	p.reportAttachedRoutes(attachedRoutes)
+
 	conf := provider.Merge(ctx, cfgs)
 
 	conf.TLS = &dynamic.TLSConfiguration{}
@@ -258,7 +339,7 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 	return conf
 }
 
-func (p *Provider) createGatewayConf(client Client, gateway *v1alpha1.Gateway) (*dynamic.Configuration, error) {
+func (p *Provider) createGatewayConf(client Client, gateway *v1alpha1.Gateway, attachedRoutes map[listenerRef]int) (*dynamic.Configuration, error) {
 	conf := &dynamic.Configuration{
 		UDP: &dynamic.UDPConfiguration{
 			Routers:  map[string]*dynamic.UDPRouter{},
@@ -281,7 +362,9 @@ func (p *Provider) createGatewayConf(client Client, gateway *v1alpha1.Gateway) (
 	// GatewayReasonListenersNotValid is used when one or more
 	// Listeners have an invalid or unsupported configuration
 	// and cannot be configured on the Gateway.
-	listenerStatuses := p.fillGatewayConf(client, gateway, conf, tlsConfigs)
+	listenerStatuses := p.fillGatewayConf(client, gateway, conf, tlsConfigs, attachedRoutes)
+
+	recordListenerEvents(client, gateway, listenerStatuses)
 
 	gatewayStatus, errG := p.makeGatewayStatus(listenerStatuses)
 
@@ -301,7 +384,7 @@ func (p *Provider) createGatewayConf(client Client, gateway *v1alpha1.Gateway) (
 	return conf, nil
 }
 
-func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, conf *dynamic.Configuration, tlsConfigs map[string]*tls.CertAndStores) []v1alpha1.ListenerStatus {
+func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, conf *dynamic.Configuration, tlsConfigs map[string]*tls.CertAndStores, attachedRoutes map[listenerRef]int) []v1alpha1.ListenerStatus {
 	listenerStatuses := make([]v1alpha1.ListenerStatus, len(gateway.Spec.Listeners))
 
 	for i, listener := range gateway.Spec.Listeners {
@@ -310,6 +393,9 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 			Conditions: []metav1.Condition{},
 		}
 
+		ref := listenerRef{namespace: gateway.Namespace, gateway: gateway.Name, port: listener.Port}
+		attachedRoutes[ref] = 0
+
 		// Supported Protocol
 		if listener.Protocol != v1alpha1.HTTPProtocolType && listener.Protocol != v1alpha1.HTTPSProtocolType {
 			// update "Detached" status true with "UnsupportedProtocol" reason
@@ -386,14 +472,14 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 		}
 
 		// Supported Route types
-		if listener.Routes.Kind != "HTTPRoute" {
+		if !isSupportedRoutes(listener.Routes.Group, listener.Routes.Kind) {
 			// update "ResolvedRefs" status true with "InvalidRoutesRef" reason
 			listenerStatuses[i].Conditions = append(listenerStatuses[i].Conditions, metav1.Condition{
 				Type:               string(v1alpha1.ListenerConditionResolvedRefs),
 				Status:             metav1.ConditionFalse,
 				LastTransitionTime: metav1.Now(),
 				Reason:             string(v1alpha1.ListenerReasonInvalidRoutesRef),
-				Message:            fmt.Sprintf("Unsupported Route Kind %q", listener.Routes.Kind),
+				Message:            fmt.Sprintf("Unsupported Route group/kind %q/%q, only the %s/%s kind is supported", listener.Routes.Group, listener.Routes.Kind, routesGroup, routesKind),
 			})
 
 			continue
@@ -421,7 +507,12 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 
 			hostRule := hostRule(httpRoute.Spec)
 
+			var attached bool
 			for _, routeRule := range httpRoute.Spec.Rules {
+				for _, filter := range routeRule.Filters {
+					client.RecordEvent(httpRoute, corev1.EventTypeWarning, eventReasonUnsupportedFilter, fmt.Sprintf("Filter %s ignored on Gateway %s/%s: the filters are not supported", filter.Type, gateway.Namespace, gateway.Name))
+				}
+
 				rule, err := extractRule(routeRule, hostRule)
 				if err != nil {
 					// update "ResolvedRefs" status true with "DroppedRoutes" reason
@@ -432,6 +523,8 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 						Reason:             string(v1alpha1.ListenerReasonDegradedRoutes),
 						Message:            fmt.Sprintf("Skipping HTTPRoute %s: cannot generate rule: %v", httpRoute.Name, err),
 					})
+
+					client.RecordEvent(httpRoute, corev1.EventTypeWarning, eventReasonUnsupportedRule, fmt.Sprintf("Rule not attached to Gateway %s/%s: cannot generate rule: %v", gateway.Namespace, gateway.Name, err))
 					continue
 				}
 
@@ -458,6 +551,8 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 						Message:            fmt.Sprintf("Skipping HTTPRoute %s: cannot make router's key with rule %s: %v", httpRoute.Name, router.Rule, err),
 					})
 
+					client.RecordEvent(httpRoute, corev1.EventTypeWarning, eventReasonUnsupportedRule, fmt.Sprintf("Rule not attached to Gateway %s/%s: cannot make router's key with rule %s: %v", gateway.Namespace, gateway.Name, router.Rule, err))
+
 					// TODO update the RouteStatus condition / deduplicate conditions on listener
 					continue
 				}
@@ -474,6 +569,8 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 							Message:            fmt.Sprintf("Cannot load service from HTTPRoute %s/%s : %v", gateway.Namespace, httpRoute.Name, err),
 						})
 
+						client.RecordEvent(httpRoute, corev1.EventTypeWarning, eventReasonInvalidBackend, fmt.Sprintf("Rule not attached to Gateway %s/%s: cannot load service: %v", gateway.Namespace, gateway.Name, err))
+
 						// TODO update the RouteStatus condition / deduplicate conditions on listener
 						continue
 					}
@@ -492,14 +589,57 @@ func (p *Provider) fillGatewayConf(client Client, gateway *v1alpha1.Gateway, con
 					routerKey = provider.Normalize(routerKey)
 
 					conf.HTTP.Routers[routerKey] = &router
+					attached = true
 				}
 			}
+
+			if attached {
+				attachedRoutes[ref]++
+			}
 		}
 	}
 
 	return listenerStatuses
 }
 
+// recordListenerEvents records a Warning event on the Gateway for each condition of the listeners,
+// which are all errors until the listeners are reported ready.
+func recordListenerEvents(client Client, gateway *v1alpha1.Gateway, listenerStatuses []v1alpha1.ListenerStatus) {
+	for _, listenerStatus := range listenerStatuses {
+		for _, condition := range listenerStatus.Conditions {
+			client.RecordEvent(gateway, corev1.EventTypeWarning, condition.Reason, fmt.Sprintf("Listener on port %d: %s", listenerStatus.Port, condition.Message))
+		}
+	}
+}
+
+// isSupportedRoutes reports whether the routes selected by a listener are of the supported group and kind.
+// The group defaults to the Gateway API group when it is not set.
+func isSupportedRoutes(group, kind string) bool {
+	return (group == "" || group == routesGroup) && kind == routesKind
+}
+
+// reportAttachedRoutes reports the number of routes attached to each listener of the Gateways,
+// and resets it for the listeners which no longer exist.
+func (p *Provider) reportAttachedRoutes(attachedRoutes map[listenerRef]int) {
+	if p.metricsRegistry == nil {
+		return
+	}
+
+	gauge := p.metricsRegistry.GatewayAttachedRoutesGauge()
+
+	for ref := range p.attachedRoutes {
+		if _, ok := attachedRoutes[ref]; !ok {
+			gauge.With("namespace", ref.namespace, "gateway", ref.gateway, "port", strconv.Itoa(int(ref.port))).Set(0)
+		}
+	}
+
+	for ref, count := range attachedRoutes {
+		gauge.With("namespace", ref.namespace, "gateway", ref.gateway, "port", strconv.Itoa(int(ref.port))).Set(float64(count))
+	}
+
+	p.attachedRoutes = attachedRoutes
+}
+
 func (p *Provider) makeGatewayStatus(listenerStatuses []v1alpha1.ListenerStatus) (v1alpha1.GatewayStatus, error) {
 	// As Status.Addresses are not implemented yet, we initialize an empty array to follow the API expectations.
 	gatewayStatus := v1alpha1.GatewayStatus{
@@ -765,7 +905,20 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 	}
 
 	for _, forwardTo := range targets {
+		weight := int(forwardTo.Weight)
+
+		// The ServiceName takes precedence over the BackendRef.
 		if forwardTo.ServiceName == nil {
+			if forwardTo.BackendRef == nil {
+				continue
+			}
+
+			serviceName, err := backendRefServiceName(namespace, *forwardTo.BackendRef)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			wrrSvc.Weighted.Services = append(wrrSvc.Weighted.Services, dynamic.WRRService{Name: serviceName, Weight: &weight})
 			continue
 		}
 
@@ -775,15 +928,13 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 			},
 		}
 
-		// TODO Handle BackendRefs
-
 		service, exists, err := client.GetService(namespace, *forwardTo.ServiceName)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		if !exists {
-			return nil, nil, errors.New("service not found")
+			return nil, nil, fmt.Errorf("service %s/%s not found", namespace, *forwardTo.ServiceName)
 		}
 
 		if len(service.Spec.Ports) > 1 && forwardTo.Port == 0 {
@@ -812,7 +963,7 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 		}
 
 		if !match {
-			return nil, nil, errors.New("service port not found")
+			return nil, nil, fmt.Errorf("service %s/%s has no port %d", namespace, *forwardTo.ServiceName, forwardTo.Port)
 		}
 
 		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, *forwardTo.ServiceName)
@@ -821,11 +972,11 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 		}
 
 		if !endpointsExists {
-			return nil, nil, errors.New("endpoints not found")
+			return nil, nil, fmt.Errorf("endpoints %s/%s not found", namespace, *forwardTo.ServiceName)
 		}
 
 		if len(endpoints.Subsets) == 0 {
-			return nil, nil, errors.New("subset not found")
+			return nil, nil, fmt.Errorf("endpoints %s/%s have no subset", namespace, *forwardTo.ServiceName)
 		}
 
 		var port int32
@@ -839,7 +990,7 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 			}
 
 			if port == 0 {
-				return nil, nil, errors.New("cannot define a port")
+				return nil, nil, fmt.Errorf("endpoints %s/%s have no port %q", namespace, *forwardTo.ServiceName, portName)
 			}
 
 			protocol := getProtocol(portSpec, portName)
@@ -855,17 +1006,30 @@ func loadServices(client Client, namespace string, targets []v1alpha1.HTTPRouteF
 		serviceName := provider.Normalize(makeID(service.Namespace, service.Name) + "-" + portStr)
 		services[serviceName] = &svc
 
-		weight := int(forwardTo.Weight)
 		wrrSvc.Weighted.Services = append(wrrSvc.Weighted.Services, dynamic.WRRService{Name: serviceName, Weight: &weight})
 	}
 
-	if len(services) == 0 {
+	if len(wrrSvc.Weighted.Services) == 0 {
 		return nil, nil, errors.New("no service has been created")
 	}
 
 	return wrrSvc, services, nil
 }
 
+// backendRefServiceName returns the name of the service referenced by the backendRef,
+// which must be a TraefikService, defined in the same namespace by the Kubernetes CRD provider.
+func backendRefServiceName(namespace string, backendRef v1alpha1.LocalObjectReference) (string, error) {
+	if backendRef.Group != traefikServiceGroup || backendRef.Kind != traefikServiceKind {
+		return "", fmt.Errorf("unsupported backendRef %s/%s %s, only the %s/%s kind is supported", backendRef.Group, backendRef.Kind, backendRef.Name, traefikServiceGroup, traefikServiceKind)
+	}
+
+	if backendRef.Name == "" {
+		return "", errors.New("the TraefikService backendRef has no name")
+	}
+
+	return provider.Normalize(makeID(namespace, backendRef.Name)) + "@" + crdProviderName, nil
+}
+
 func getProtocol(portSpec corev1.ServicePort, portName string) string {
 	protocol := "http"
 	if portSpec.Port == 443 || strings.HasPrefix(portName, "https") {
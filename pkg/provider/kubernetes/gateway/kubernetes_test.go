@@ -4,9 +4,11 @@ import (
 	"context"
 	"testing"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	"sigs.k8s.io/service-apis/apis/v1alpha1"
@@ -501,6 +503,109 @@ func TestLoadHTTPRoutes(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "One HTTPRoute with one rule targeting a service and a TraefikService",
+			paths: []string{"services.yml", "with_traefikservice_backendref.yml"},
+			entryPoints: map[string]Entrypoint{"web": {
+				Address: ":80",
+			}},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"default-http-app-1-my-gateway-web-1c0cf64bde37d9d0df06": {
+							EntryPoints: []string{"web"},
+							Rule:        "Host(`foo.com`) && Path(`/bar`)",
+							Service:     "default-http-app-1-my-gateway-web-1c0cf64bde37d9d0df06-wrr",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-http-app-1-my-gateway-web-1c0cf64bde37d9d0df06-wrr": {
+							Weighted: &dynamic.WeightedRoundRobin{
+								Services: []dynamic.WRRService{
+									{
+										Name:   "default-whoami-80",
+										Weight: func(i int) *int { return &i }(1),
+									},
+									{
+										Name:   "default-wrr@kubernetescrd",
+										Weight: func(i int) *int { return &i }(1),
+									},
+								},
+							},
+						},
+						"default-whoami-80": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
+		{
+			desc:  "Empty caused by an unsupported backendRef",
+			paths: []string{"services.yml", "with_unsupported_backendref.yml"},
+			entryPoints: map[string]Entrypoint{"web": {
+				Address: ":80",
+			}},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
+		{
+			desc:  "Empty caused by an unsupported routes group",
+			paths: []string{"services.yml", "with_unsupported_routes_group.yml"},
+			entryPoints: map[string]Entrypoint{"web": {
+				Address: ":80",
+			}},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:  "Two Gateways and one HTTPRoute",
 			paths: []string{"services.yml", "with_two_gateways_one_httproute.yml"},
@@ -754,6 +859,124 @@ func TestLoadHTTPRoutes(t *testing.T) {
 	}
 }
 
+func TestLoadHTTPRoutes_attachedRoutes(t *testing.T) {
+	testCases := []struct {
+		desc                   string
+		paths                  []string
+		expectedAttachedRoutes map[string]float64
+		expectedEvents         []string
+	}{
+		{
+			desc:  "One attached HTTPRoute",
+			paths: []string{"services.yml", "simple.yml"},
+			expectedAttachedRoutes: map[string]float64{
+				"default/my-gateway/80": 1,
+			},
+		},
+		{
+			desc:  "One HTTPRoute attached to two listeners",
+			paths: []string{"services.yml", "with_two_listeners_one_httproute.yml"},
+			expectedAttachedRoutes: map[string]float64{
+				"default/my-gateway/80":  1,
+				"default/my-gateway/443": 1,
+			},
+		},
+		{
+			desc:  "HTTPRoute rejected because of an unsupported backendRef",
+			paths: []string{"services.yml", "with_unsupported_backendref.yml"},
+			expectedAttachedRoutes: map[string]float64{
+				"default/my-gateway/80": 0,
+			},
+			expectedEvents: []string{
+				"Warning InvalidBackend Rule not attached to Gateway default/my-gateway: cannot load service: unsupported backendRef foo.com/Unknown wrr, only the traefik.containo.us/TraefikService kind is supported",
+				"Warning DegradedRoutes Listener on port 80: Cannot load service from HTTPRoute default/http-app-1 : unsupported backendRef foo.com/Unknown wrr, only the traefik.containo.us/TraefikService kind is supported",
+			},
+		},
+		{
+			desc:  "HTTPRoute rejected because of a missing service port",
+			paths: []string{"services.yml", "with_wrong_service_port.yml"},
+			expectedAttachedRoutes: map[string]float64{
+				"default/my-gateway/80": 0,
+			},
+			expectedEvents: []string{
+				"Warning InvalidBackend Rule not attached to Gateway default/my-gateway: cannot load service: service default/whoami has no port 9000",
+				"Warning DegradedRoutes Listener on port 80: Cannot load service from HTTPRoute default/http-app-1 : service default/whoami has no port 9000",
+			},
+		},
+		{
+			desc:  "Listener rejected because of an unsupported routes group",
+			paths: []string{"services.yml", "with_unsupported_routes_group.yml"},
+			expectedAttachedRoutes: map[string]float64{
+				"default/my-gateway/80": 0,
+			},
+			expectedEvents: []string{
+				`Warning InvalidRoutesRef Listener on port 80: Unsupported Route group/kind "acme.io"/"HTTPRoute", only the networking.x-k8s.io/HTTPRoute kind is supported`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := &attachedRoutesRegistry{
+				Registry: metrics.NewVoidRegistry(),
+				gauge:    &attachedRoutesGauge{values: map[string]float64{}},
+			}
+
+			p := Provider{
+				EntryPoints: map[string]Entrypoint{
+					"web":       {Address: ":80"},
+					"websecure": {Address: ":443"},
+				},
+			}
+			p.SetMetricsRegistry(registry)
+
+			client := newClientMock(test.paths...)
+			p.loadConfigurationFromGateway(context.Background(), client)
+
+			assert.Equal(t, test.expectedAttachedRoutes, registry.gauge.values)
+			assert.Equal(t, test.expectedEvents, *client.events)
+
+			// The number of attached routes of the listeners which no longer exist is reset.
+			p.loadConfigurationFromGateway(context.Background(), newClientMock())
+
+			for key := range test.expectedAttachedRoutes {
+				assert.Zero(t, registry.gauge.values[key], key)
+			}
+		})
+	}
+}
+
+type attachedRoutesRegistry struct {
+	metrics.Registry
+	gauge *attachedRoutesGauge
+}
+
+func (r *attachedRoutesRegistry) GatewayAttachedRoutesGauge() gokitmetrics.Gauge {
+	return r.gauge
+}
+
+type attachedRoutesGauge struct {
+	labels []string
+	values map[string]float64
+}
+
+func (g *attachedRoutesGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	return &attachedRoutesGauge{labels: append(append([]string{}, g.labels...), labelValues...), values: g.values}
+}
+
+func (g *attachedRoutesGauge) Set(value float64) {
+	// The labels are the namespace, the gateway and the port of the listener, in this order.
+	g.values[g.labels[1]+"/"+g.labels[3]+"/"+g.labels[5]] = value
+}
+
+func (g *attachedRoutesGauge) Add(delta float64) {
+	g.values[g.labels[1]+"/"+g.labels[3]+"/"+g.labels[5]] += delta
+}
+
 func TestHostRule(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/service-apis/apis/v1alpha1"
+)
+
+// missingReferences returns the sorted list of the Secrets and Services referenced by the Gateways handled by Traefik,
+// directly or through their HTTPRoutes, which do not exist.
+// A resource which cannot be fetched for another reason than its absence is not reported,
+// as the error is not expected to be fixed by the creation of the resource.
+func (p *Provider) missingReferences(client Client) []string {
+	gatewayClasses, err := client.GetGatewayClasses()
+	if err != nil {
+		return nil
+	}
+
+	gatewayClassNames := map[string]struct{}{}
+	for _, gatewayClass := range gatewayClasses {
+		if gatewayClass.Spec.Controller == "traefik.io/gateway-controller" {
+			gatewayClassNames[gatewayClass.Name] = struct{}{}
+		}
+	}
+
+	missing := map[string]struct{}{}
+
+	for _, gateway := range client.GetGateways() {
+		if _, ok := gatewayClassNames[gateway.Spec.GatewayClassName]; !ok {
+			continue
+		}
+
+		for _, listener := range gateway.Spec.Listeners {
+			if listener.TLS != nil && listener.TLS.CertificateRef != nil &&
+				listener.TLS.CertificateRef.Kind == "Secret" && listener.TLS.CertificateRef.Group == "core" {
+				name := listener.TLS.CertificateRef.Name
+				if _, exists, err := client.GetSecret(gateway.Namespace, name); err == nil && !exists {
+					missing["Secret "+gateway.Namespace+"/"+name] = struct{}{}
+				}
+			}
+
+			if !isSupportedRoutes(listener.Routes.Group, listener.Routes.Kind) {
+				continue
+			}
+
+			httpRoutes, err := client.GetHTTPRoutes(gateway.Namespace, labels.SelectorFromSet(listener.Routes.Selector.MatchLabels))
+			if err != nil {
+				continue
+			}
+
+			for _, httpRoute := range httpRoutes {
+				// Should never happen
+				if httpRoute == nil {
+					continue
+				}
+
+				for _, rule := range httpRoute.Spec.Rules {
+					for _, forwardTo := range rule.ForwardTo {
+						if forwardTo.ServiceName == nil {
+							continue
+						}
+
+						name := *forwardTo.ServiceName
+						if _, exists, err := client.GetService(gateway.Namespace, name); err == nil && !exists {
+							missing["Service "+gateway.Namespace+"/"+name] = struct{}{}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	refs := make([]string, 0, len(missing))
+	for ref := range missing {
+		refs = append(refs, ref)
+	}
+
+	sort.Strings(refs)
+
+	return refs
+}
@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingReferences(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		paths    []string
+		expected []string
+	}{
+		{
+			desc:     "all references exist",
+			paths:    []string{"services.yml", "with_protocol_https.yml"},
+			expected: []string{},
+		},
+		{
+			desc:     "missing service",
+			paths:    []string{"with_protocol_https.yml"},
+			expected: []string{"Service default/whoami"},
+		},
+		{
+			desc:     "gateway not handled by Traefik",
+			paths:    []string{"gatewayclass_with_unknown_controller.yml"},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{}
+
+			assert.Equal(t, test.expected, p.missingReferences(newClientMock(test.paths...)))
+		})
+	}
+}
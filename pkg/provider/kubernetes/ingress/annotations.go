@@ -41,9 +41,12 @@ type ServiceConfig struct {
 
 // ServiceIng is the service's configuration from annotations.
 type ServiceIng struct {
-	ServersScheme  string          `json:"serversScheme,omitempty"`
-	PassHostHeader *bool           `json:"passHostHeader"`
-	Sticky         *dynamic.Sticky `json:"sticky,omitempty" label:"allowEmpty"`
+	ServersScheme      string                      `json:"serversScheme,omitempty"`
+	ServersTransport   string                      `json:"serversTransport,omitempty"`
+	PassHostHeader     *bool                       `json:"passHostHeader"`
+	Sticky             *dynamic.Sticky             `json:"sticky,omitempty" label:"allowEmpty"`
+	HealthCheck        *dynamic.HealthCheck        `json:"healthCheck,omitempty"`
+	ResponseForwarding *dynamic.ResponseForwarding `json:"responseForwarding,omitempty"`
 }
 
 // SetDefaults sets the default values.
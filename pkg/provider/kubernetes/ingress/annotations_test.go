@@ -101,15 +101,20 @@ func Test_parseServiceConfig(t *testing.T) {
 		{
 			desc: "service annotations",
 			annotations: map[string]string{
-				"ingress.kubernetes.io/foo":                                    "bar",
-				"traefik.ingress.kubernetes.io/foo":                            "bar",
-				"traefik.ingress.kubernetes.io/service.serversscheme":          "protocol",
-				"traefik.ingress.kubernetes.io/service.passhostheader":         "true",
-				"traefik.ingress.kubernetes.io/service.sticky.cookie":          "true",
-				"traefik.ingress.kubernetes.io/service.sticky.cookie.httponly": "true",
-				"traefik.ingress.kubernetes.io/service.sticky.cookie.name":     "foobar",
-				"traefik.ingress.kubernetes.io/service.sticky.cookie.secure":   "true",
-				"traefik.ingress.kubernetes.io/service.sticky.cookie.samesite": "none",
+				"ingress.kubernetes.io/foo":                                              "bar",
+				"traefik.ingress.kubernetes.io/foo":                                      "bar",
+				"traefik.ingress.kubernetes.io/service.serversscheme":                    "protocol",
+				"traefik.ingress.kubernetes.io/service.passhostheader":                   "true",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie":                    "true",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie.httponly":           "true",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie.name":               "foobar",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie.secure":             "true",
+				"traefik.ingress.kubernetes.io/service.sticky.cookie.samesite":           "none",
+				"traefik.ingress.kubernetes.io/service.serverstransport":                 "foobar@file",
+				"traefik.ingress.kubernetes.io/service.healthcheck.path":                 "/health",
+				"traefik.ingress.kubernetes.io/service.healthcheck.interval":             "10s",
+				"traefik.ingress.kubernetes.io/service.healthcheck.headers.foo":          "bar",
+				"traefik.ingress.kubernetes.io/service.responseforwarding.flushinterval": "10ms",
 			},
 			expected: &ServiceConfig{
 				Service: &ServiceIng{
@@ -121,8 +126,18 @@ func Test_parseServiceConfig(t *testing.T) {
 							SameSite: "none",
 						},
 					},
-					ServersScheme:  "protocol",
-					PassHostHeader: Bool(true),
+					ServersScheme:    "protocol",
+					ServersTransport: "foobar@file",
+					PassHostHeader:   Bool(true),
+					HealthCheck: &dynamic.HealthCheck{
+						Path:            "/health",
+						Interval:        "10s",
+						FollowRedirects: Bool(true),
+						Headers:         map[string]string{"foo": "bar"},
+					},
+					ResponseForwarding: &dynamic.ResponseForwarding{
+						FlushInterval: "10ms",
+					},
 				},
 			},
 		},
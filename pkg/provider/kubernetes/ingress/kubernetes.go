@@ -46,6 +46,7 @@ type Provider struct {
 	IngressEndpoint   *EndpointIngress `description:"Kubernetes Ingress Endpoint." json:"ingressEndpoint,omitempty" toml:"ingressEndpoint,omitempty" yaml:"ingressEndpoint,omitempty" export:"true"`
 	ThrottleDuration  ptypes.Duration  `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	lastConfiguration safe.Safe
+	synced            provider.SyncState
 }
 
 // EndpointIngress holds the endpoint information for the Kubernetes provider.
@@ -96,6 +97,12 @@ func (p *Provider) Init() error {
 	return nil
 }
 
+// Synced returns the name of the provider, and a channel closed once the configuration
+// built from the synced informers caches has been provided.
+func (p *Provider) Synced() (string, <-chan struct{}) {
+	return "kubernetes", p.synced.Done()
+}
+
 // Provide allows the k8s provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -153,6 +160,8 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						}
 					}
 
+					p.synced.MarkSynced()
+
 					// If we're throttling, we sleep here for the throttle duration to
 					// enforce that we don't refresh faster than our throttle. time.Sleep
 					// returns immediately if p.ThrottleDuration is 0 (no throttle).
@@ -494,6 +503,9 @@ func loadService(client Client, namespace string, backend networkingv1beta1.Ingr
 
 	if svcConfig != nil && svcConfig.Service != nil {
 		svc.LoadBalancer.Sticky = svcConfig.Service.Sticky
+		svc.LoadBalancer.ServersTransport = svcConfig.Service.ServersTransport
+		svc.LoadBalancer.HealthCheck = svcConfig.Service.HealthCheck
+		svc.LoadBalancer.ResponseForwarding = svcConfig.Service.ResponseForwarding
 		if svcConfig.Service.PassHostHeader != nil {
 			svc.LoadBalancer.PassHostHeader = svcConfig.Service.PassHostHeader
 		}
@@ -121,6 +121,15 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 										HTTPOnly: true,
 									},
 								},
+								ServersTransport: "foobar@file",
+								HealthCheck: &dynamic.HealthCheck{
+									Path:            "/health",
+									Interval:        "10s",
+									FollowRedirects: Bool(true),
+								},
+								ResponseForwarding: &dynamic.ResponseForwarding{
+									FlushInterval: "10ms",
+								},
 								Servers: []dynamic.Server{
 									{
 										URL: "protocol://10.10.0.1:8080",
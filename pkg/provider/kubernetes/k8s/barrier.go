@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// GenerationBarrier holds the generation barrier configuration.
+type GenerationBarrier struct {
+	Timeout ptypes.Duration `description:"Maximum duration a configuration is held back while resources it references are missing." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (g *GenerationBarrier) SetDefaults() {
+	g.Timeout = ptypes.Duration(30 * time.Second)
+}
+
+// Barrier holds back the configurations referencing missing resources,
+// until the resources are created, or until they have been missing for longer than the timeout,
+// in which case the references are considered broken, and the configuration is applied anyway.
+type Barrier struct {
+	timeout time.Duration
+	now     func() time.Time
+
+	// missingSince is the time each missing reference was first seen missing.
+	missingSince map[string]time.Time
+}
+
+// NewBarrier creates a barrier.
+func NewBarrier(config GenerationBarrier) *Barrier {
+	return &Barrier{
+		timeout:      time.Duration(config.Timeout),
+		now:          time.Now,
+		missingSince: make(map[string]time.Time),
+	}
+}
+
+// Hold reports whether the configuration referencing the given missing resources must be held back,
+// and the duration after which it has to be evaluated again.
+func (b *Barrier) Hold(missing []string) (time.Duration, bool) {
+	now := b.now()
+
+	current := make(map[string]time.Time, len(missing))
+	for _, ref := range missing {
+		since, ok := b.missingSince[ref]
+		if !ok {
+			since = now
+		}
+
+		current[ref] = since
+	}
+
+	// The references which are not missing anymore are forgotten,
+	// so that they get a whole timeout again if they go missing later.
+	b.missingSince = current
+
+	var wait time.Duration
+	for _, since := range current {
+		if remaining := b.timeout - now.Sub(since); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	return wait, wait > 0
+}
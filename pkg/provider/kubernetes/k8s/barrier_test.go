@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func TestBarrier_Hold(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	barrier := NewBarrier(GenerationBarrier{Timeout: ptypes.Duration(30 * time.Second)})
+	barrier.now = func() time.Time { return now }
+
+	wait, hold := barrier.Hold(nil)
+	assert.False(t, hold)
+	assert.Equal(t, time.Duration(0), wait)
+
+	wait, hold = barrier.Hold([]string{"Secret default/foo"})
+	assert.True(t, hold)
+	assert.Equal(t, 30*time.Second, wait)
+
+	now = now.Add(10 * time.Second)
+
+	wait, hold = barrier.Hold([]string{"Secret default/foo", "Middleware default/bar"})
+	assert.True(t, hold)
+	assert.Equal(t, 30*time.Second, wait)
+
+	now = now.Add(25 * time.Second)
+
+	// The secret has been missing for longer than the timeout, but not the middleware.
+	wait, hold = barrier.Hold([]string{"Secret default/foo", "Middleware default/bar"})
+	assert.True(t, hold)
+	assert.Equal(t, 5*time.Second, wait)
+
+	now = now.Add(5 * time.Second)
+
+	wait, hold = barrier.Hold([]string{"Secret default/foo", "Middleware default/bar"})
+	assert.False(t, hold)
+	assert.Equal(t, time.Duration(0), wait)
+
+	// A reference missing again gets a whole timeout.
+	wait, hold = barrier.Hold(nil)
+	assert.False(t, hold)
+	assert.Equal(t, time.Duration(0), wait)
+
+	wait, hold = barrier.Hold([]string{"Secret default/foo"})
+	assert.True(t, hold)
+	assert.Equal(t, 30*time.Second, wait)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ Client = (*clientMock)(nil)
+
+type clientMock struct {
+	services  []*corev1.Service
+	endpoints []*corev1.Endpoints
+
+	watchChan chan interface{}
+}
+
+func newClientMock(paths ...string) clientMock {
+	c := clientMock{}
+
+	for _, path := range paths {
+		yamlContent, err := ioutil.ReadFile(path)
+		if err != nil {
+			panic(err)
+		}
+
+		k8sObjects := k8s.MustParseYaml(yamlContent)
+		for _, obj := range k8sObjects {
+			switch o := obj.(type) {
+			case *corev1.Service:
+				c.services = append(c.services, o)
+			case *corev1.Endpoints:
+				c.endpoints = append(c.endpoints, o)
+			default:
+				panic(fmt.Sprintf("Unknown runtime object %+v %T", o, o))
+			}
+		}
+	}
+
+	return c
+}
+
+func (c clientMock) GetServices() []*corev1.Service {
+	return c.services
+}
+
+func (c clientMock) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	for _, endpoints := range c.endpoints {
+		if endpoints.Namespace == namespace && endpoints.Name == name {
+			return endpoints, true, nil
+		}
+	}
+
+	return &corev1.Endpoints{}, false, nil
+}
+
+func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error) {
+	return c.watchChan, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/label"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/constraints"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// configuration contains the information from the annotations which is not related to the dynamic configuration.
+type configuration struct {
+	Enable bool
+}
+
+func (p *Provider) loadConfigurationFromServices(ctx context.Context, client Client) *dynamic.Configuration {
+	configurations := make(map[string]*dynamic.Configuration)
+
+	for _, service := range client.GetServices() {
+		serviceName := getServiceName(service)
+		ctxService := log.With(ctx, log.Str(log.ServiceName, serviceName))
+
+		if !p.keepService(ctxService, service) {
+			continue
+		}
+
+		logger := log.FromContext(ctxService)
+
+		confFromLabel, err := label.DecodeConfiguration(service.Annotations)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		var tcpOrUDP bool
+		if len(confFromLabel.TCP.Routers) > 0 || len(confFromLabel.TCP.Services) > 0 {
+			tcpOrUDP = true
+
+			err := p.buildTCPServiceConfiguration(client, service, confFromLabel.TCP)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+			provider.BuildTCPRouterConfiguration(ctxService, confFromLabel.TCP)
+		}
+
+		if len(confFromLabel.UDP.Routers) > 0 || len(confFromLabel.UDP.Services) > 0 {
+			tcpOrUDP = true
+
+			err := p.buildUDPServiceConfiguration(client, service, confFromLabel.UDP)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+			provider.BuildUDPRouterConfiguration(ctxService, confFromLabel.UDP)
+		}
+
+		if tcpOrUDP && len(confFromLabel.HTTP.Routers) == 0 &&
+			len(confFromLabel.HTTP.Middlewares) == 0 &&
+			len(confFromLabel.HTTP.Services) == 0 {
+			configurations[serviceName] = confFromLabel
+			continue
+		}
+
+		err = p.buildServiceConfiguration(client, service, confFromLabel.HTTP)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		model := struct {
+			Name        string
+			Namespace   string
+			Labels      map[string]string
+			Annotations map[string]string
+		}{
+			Name:        service.Name,
+			Namespace:   service.Namespace,
+			Labels:      service.Labels,
+			Annotations: service.Annotations,
+		}
+
+		provider.BuildRouterConfiguration(ctxService, confFromLabel.HTTP, serviceName, p.defaultRuleTpl, model)
+
+		configurations[serviceName] = confFromLabel
+	}
+
+	return provider.Merge(ctx, configurations)
+}
+
+func (p *Provider) keepService(ctx context.Context, service *corev1.Service) bool {
+	logger := log.FromContext(ctx)
+
+	conf := configuration{Enable: p.ExposedByDefault}
+	if err := label.Decode(service.Annotations, &conf, "traefik.enable"); err != nil {
+		logger.Errorf("Error decoding the traefik.enable annotation: %v", err)
+		return false
+	}
+
+	if !conf.Enable {
+		logger.Debug("Filtering disabled service")
+		return false
+	}
+
+	matches, err := constraints.MatchLabels(service.Annotations, p.Constraints)
+	if err != nil {
+		logger.Errorf("Error matching constraints expression: %v", err)
+		return false
+	}
+	if !matches {
+		logger.Debugf("Service pruned by constraint expression: %q", p.Constraints)
+		return false
+	}
+
+	return true
+}
+
+func (p *Provider) buildTCPServiceConfiguration(client Client, service *corev1.Service, configuration *dynamic.TCPConfiguration) error {
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.TCPService)
+		lb := &dynamic.TCPServersLoadBalancer{}
+		lb.SetDefaults()
+		configuration.Services[getServiceName(service)] = &dynamic.TCPService{
+			LoadBalancer: lb,
+		}
+	}
+
+	for name, svc := range configuration.Services {
+		if svc.LoadBalancer == nil {
+			return fmt.Errorf("service %q error: load-balancer is not defined", name)
+		}
+
+		var serverPort string
+		if len(svc.LoadBalancer.Servers) > 0 {
+			serverPort = svc.LoadBalancer.Servers[0].Port
+		}
+
+		addresses, err := getAddresses(client, service, serverPort, corev1.ProtocolTCP)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+
+		svc.LoadBalancer.Servers = nil
+		for _, address := range addresses {
+			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.TCPServer{Address: address})
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) buildUDPServiceConfiguration(client Client, service *corev1.Service, configuration *dynamic.UDPConfiguration) error {
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.UDPService)
+		configuration.Services[getServiceName(service)] = &dynamic.UDPService{
+			LoadBalancer: &dynamic.UDPServersLoadBalancer{},
+		}
+	}
+
+	for name, svc := range configuration.Services {
+		if svc.LoadBalancer == nil {
+			return fmt.Errorf("service %q error: load-balancer is not defined", name)
+		}
+
+		var serverPort string
+		if len(svc.LoadBalancer.Servers) > 0 {
+			serverPort = svc.LoadBalancer.Servers[0].Port
+		}
+
+		addresses, err := getAddresses(client, service, serverPort, corev1.ProtocolUDP)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+
+		svc.LoadBalancer.Servers = nil
+		for _, address := range addresses {
+			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.UDPServer{Address: address})
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) buildServiceConfiguration(client Client, service *corev1.Service, configuration *dynamic.HTTPConfiguration) error {
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.Service)
+		lb := &dynamic.ServersLoadBalancer{}
+		lb.SetDefaults()
+		configuration.Services[getServiceName(service)] = &dynamic.Service{
+			LoadBalancer: lb,
+		}
+	}
+
+	for name, svc := range configuration.Services {
+		if svc.LoadBalancer == nil {
+			return fmt.Errorf("service %q error: load-balancer is not defined", name)
+		}
+
+		server := dynamic.Server{}
+		server.SetDefaults()
+		if len(svc.LoadBalancer.Servers) > 0 {
+			server = svc.LoadBalancer.Servers[0]
+		}
+
+		addresses, err := getAddresses(client, service, server.Port, corev1.ProtocolTCP)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+
+		svc.LoadBalancer.Servers = nil
+		for _, address := range addresses {
+			svc.LoadBalancer.Servers = append(svc.LoadBalancer.Servers, dynamic.Server{
+				URL: fmt.Sprintf("%s://%s", server.Scheme, address),
+			})
+		}
+	}
+
+	return nil
+}
+
+// getAddresses returns the addresses of the endpoints of the service port,
+// which is matched by number or name, or is the first port with the protocol when not given.
+func getAddresses(client Client, service *corev1.Service, port string, protocol corev1.Protocol) ([]string, error) {
+	servicePort, err := getServicePort(service, port, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return []string{net.JoinHostPort(service.Spec.ExternalName, strconv.Itoa(int(servicePort.Port)))}, nil
+	}
+
+	endpoints, exists, err := client.GetEndpoints(service.Namespace, service.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, errors.New("endpoints not found")
+	}
+
+	var addresses []string
+	for _, subset := range endpoints.Subsets {
+		var endpointPort int32
+		for _, p := range subset.Ports {
+			if p.Name == servicePort.Name && protocolOrDefault(p.Protocol) == protocol {
+				endpointPort = p.Port
+				break
+			}
+		}
+
+		if endpointPort == 0 {
+			continue
+		}
+
+		for _, addr := range subset.Addresses {
+			addresses = append(addresses, net.JoinHostPort(addr.IP, strconv.Itoa(int(endpointPort))))
+		}
+	}
+
+	return addresses, nil
+}
+
+func getServicePort(service *corev1.Service, port string, protocol corev1.Protocol) (corev1.ServicePort, error) {
+	for _, p := range service.Spec.Ports {
+		if protocolOrDefault(p.Protocol) != protocol {
+			continue
+		}
+
+		if port == "" || port == p.Name || port == strconv.Itoa(int(p.Port)) {
+			return p, nil
+		}
+	}
+
+	if port == "" {
+		return corev1.ServicePort{}, fmt.Errorf("no %s port", protocol)
+	}
+
+	return corev1.ServicePort{}, fmt.Errorf("%s port %s not found", protocol, port)
+}
+
+// protocolOrDefault returns the protocol, which is TCP when omitted.
+func protocolOrDefault(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return protocol
+}
+
+func getServiceName(service *corev1.Service) string {
+	return provider.Normalize(service.Namespace + "-" + service.Name)
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Int(v int) *int    { return &v }
+func Bool(v bool) *bool { return &v }
+
+func TestLoadConfigurationFromServices(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		fixture          string
+		exposedByDefault bool
+		constraints      string
+		expected         *dynamic.Configuration
+	}{
+		{
+			desc:     "Service without annotations",
+			fixture:  "Service-without-annotations.yml",
+			expected: emptyConfiguration(),
+		},
+		{
+			desc:             "Service without annotations exposed by default",
+			fixture:          "Service-without-annotations.yml",
+			exposedByDefault: true,
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"testing-whoami": {
+							Service: "testing-whoami",
+							Rule:    "Host(`whoami`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"testing-whoami": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{URL: "http://10.10.0.1:8000"},
+									{URL: "http://10.10.0.2:8000"},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:     "Enabled service",
+			fixture:  "Enabled-service.yml",
+			expected: whoamiConfiguration(),
+		},
+		{
+			desc:             "Disabled service",
+			fixture:          "Disabled-service.yml",
+			exposedByDefault: true,
+			expected:         emptyConfiguration(),
+		},
+		{
+			desc:    "Service with router and service annotations",
+			fixture: "Service-with-router-and-service-annotations.yml",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"whoami": {
+							Service: "whoami",
+							Rule:    "Host(`whoami.example.com`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"whoami": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{URL: "h2c://10.10.0.1:8000"},
+									{URL: "h2c://10.10.0.2:8000"},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:    "Service with TCP and UDP routers",
+			fixture: "Service-with-TCP-and-UDP-routers.yml",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"whoami": {
+							Service: "testing-whoami",
+							Rule:    "HostSNI(`*`)",
+						},
+					},
+					Services: map[string]*dynamic.TCPService{
+						"testing-whoami": {
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{
+									{Address: "10.10.0.1:8000"},
+									{Address: "10.10.0.2:8000"},
+								},
+								TerminationDelay: Int(100),
+							},
+						},
+					},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{
+						"whoami": {
+							EntryPoints: []string{"udp"},
+							Service:     "testing-whoami",
+						},
+					},
+					Services: map[string]*dynamic.UDPService{
+						"testing-whoami": {
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{Address: "10.10.0.1:5353"},
+									{Address: "10.10.0.2:5353"},
+								},
+							},
+						},
+					},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc:     "Service with unknown port",
+			fixture:  "Service-with-unknown-port.yml",
+			expected: emptyConfiguration(),
+		},
+		{
+			desc:     "Service without endpoints",
+			fixture:  "Service-without-endpoints.yml",
+			expected: emptyConfiguration(),
+		},
+		{
+			desc:    "ExternalName service",
+			fixture: "ExternalName-service.yml",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"testing-whoami": {
+							Service: "testing-whoami",
+							Rule:    "Host(`whoami`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"testing-whoami": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{URL: "http://whoami.example.com:80"},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "Service matching constraints",
+			fixture:     "Service-matching-constraints.yml",
+			constraints: `Label("traefik.tags", "public")`,
+			expected:    whoamiConfiguration(),
+		},
+		{
+			desc:        "Service not matching constraints",
+			fixture:     "Service-matching-constraints.yml",
+			constraints: `Label("traefik.tags", "private")`,
+			expected:    emptyConfiguration(),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClientMock(filepath.Join("fixtures", test.fixture))
+
+			p := Provider{
+				ExposedByDefault: test.exposedByDefault,
+				Constraints:      test.constraints,
+			}
+			p.SetDefaults()
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			conf := p.loadConfigurationFromServices(context.Background(), client)
+
+			assert.Equal(t, test.expected, conf)
+		})
+	}
+}
+
+func TestGetServicePort(t *testing.T) {
+	service := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "web", Port: 80},
+				{Name: "admin", Port: 8080, Protocol: corev1.ProtocolTCP},
+				{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc          string
+		port          string
+		protocol      corev1.Protocol
+		expected      string
+		expectedError bool
+	}{
+		{
+			desc:     "first TCP port",
+			protocol: corev1.ProtocolTCP,
+			expected: "web",
+		},
+		{
+			desc:     "first UDP port",
+			protocol: corev1.ProtocolUDP,
+			expected: "dns",
+		},
+		{
+			desc:     "port by name",
+			port:     "admin",
+			protocol: corev1.ProtocolTCP,
+			expected: "admin",
+		},
+		{
+			desc:     "port by number",
+			port:     "8080",
+			protocol: corev1.ProtocolTCP,
+			expected: "admin",
+		},
+		{
+			desc:          "port with another protocol",
+			port:          "dns",
+			protocol:      corev1.ProtocolTCP,
+			expectedError: true,
+		},
+		{
+			desc:          "unknown port",
+			port:          "9000",
+			protocol:      corev1.ProtocolTCP,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			port, err := getServicePort(service, test.port, test.protocol)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, port.Name)
+		})
+	}
+}
+
+func emptyConfiguration() *dynamic.Configuration {
+	return &dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  map[string]*dynamic.TCPRouter{},
+			Services: map[string]*dynamic.TCPService{},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  map[string]*dynamic.UDPRouter{},
+			Services: map[string]*dynamic.UDPService{},
+		},
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     map[string]*dynamic.Router{},
+			Middlewares: map[string]*dynamic.Middleware{},
+			Services:    map[string]*dynamic.Service{},
+		},
+	}
+}
+
+func whoamiConfiguration() *dynamic.Configuration {
+	conf := emptyConfiguration()
+	conf.HTTP.Routers["testing-whoami"] = &dynamic.Router{
+		Service: "testing-whoami",
+		Rule:    "Host(`whoami`)",
+	}
+	conf.HTTP.Services["testing-whoami"] = &dynamic.Service{
+		LoadBalancer: &dynamic.ServersLoadBalancer{
+			Servers: []dynamic.Server{
+				{URL: "http://10.10.0.1:8000"},
+				{URL: "http://10.10.0.2:8000"},
+			},
+			PassHostHeader: Bool(true),
+		},
+	}
+
+	return conf
+}
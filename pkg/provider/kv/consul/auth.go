@@ -0,0 +1,221 @@
+package consul
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const (
+	namespaceHeader = "X-Consul-Namespace"
+	tokenHeader     = "X-Consul-Token"
+)
+
+// minRenewDelay is the minimum delay between two renewals of the token,
+// which is the delay between the retries once the token has expired.
+const minRenewDelay = time.Second
+
+// AuthMethod holds the configuration of the login with a Consul auth method.
+type AuthMethod struct {
+	Name            string            `description:"Name of the Consul auth method, such as a Kubernetes or a JWT one." json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	BearerTokenFile string            `description:"Path of the file holding the bearer token presented to the auth method." json:"bearerTokenFile,omitempty" toml:"bearerTokenFile,omitempty" yaml:"bearerTokenFile,omitempty" export:"true"`
+	Meta            map[string]string `description:"Metadata set on the ACL tokens created by the login." json:"meta,omitempty" toml:"meta,omitempty" yaml:"meta,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *AuthMethod) SetDefaults() {
+	a.BearerTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+}
+
+// authenticator logs in with the auth method to get the ACL token,
+// and logs in again before the token expires.
+type authenticator struct {
+	method *AuthMethod
+	// client is the Consul client used to log in and out, whose requests are not authenticated.
+	client *api.Client
+
+	mu    sync.Mutex
+	token *api.ACLToken
+}
+
+// Token returns the secret of the ACL token, logging in when there is no token yet.
+func (a *authenticator) Token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		if err := a.login(); err != nil {
+			return "", err
+		}
+	}
+
+	return a.token.SecretID, nil
+}
+
+// Renew replaces the ACL token with a new one, and logs the previous one out.
+func (a *authenticator) Renew() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	previous := a.token
+	if err := a.login(); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		a.logout(previous)
+	}
+
+	return nil
+}
+
+// login must be called with the lock held.
+func (a *authenticator) login() error {
+	bearerToken, err := ioutil.ReadFile(a.method.BearerTokenFile)
+	if err != nil {
+		return fmt.Errorf("unable to read the bearer token: %w", err)
+	}
+
+	token, _, err := a.client.ACL().Login(&api.ACLLoginParams{
+		AuthMethod:  a.method.Name,
+		BearerToken: strings.TrimSpace(string(bearerToken)),
+		Meta:        a.method.Meta,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("unable to log in with the %s auth method: %w", a.method.Name, err)
+	}
+
+	a.token = token
+	return nil
+}
+
+func (a *authenticator) logout(token *api.ACLToken) {
+	if _, err := a.client.ACL().Logout(&api.WriteOptions{Token: token.SecretID}); err != nil {
+		log.WithoutContext().Debugf("Unable to log the Consul ACL token %s out: %v", token.AccessorID, err)
+	}
+}
+
+// renewDelay returns the delay before the renewal of the token, which is half of its remaining validity,
+// and is false when the token does not expire.
+func (a *authenticator) renewDelay() (time.Duration, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil || a.token.ExpirationTime == nil {
+		return 0, false
+	}
+
+	delay := time.Until(*a.token.ExpirationTime) / 2
+	if delay < minRenewDelay {
+		delay = minRenewDelay
+	}
+
+	return delay, true
+}
+
+// watch renews the token before it expires, and logs it out once the context is done.
+func (a *authenticator) watch(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	for {
+		// Checks the token again later on when it does not expire, as it may not be issued yet.
+		delay, ok := a.renewDelay()
+		if !ok {
+			delay = watchWaitTime
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			a.mu.Lock()
+			if a.token != nil {
+				a.logout(a.token)
+				a.token = nil
+			}
+			a.mu.Unlock()
+
+			return
+
+		case <-timer.C:
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := a.Renew(); err != nil {
+			logger.Errorf("Unable to renew the Consul ACL token: %v", err)
+			continue
+		}
+
+		logger.Debug("Consul ACL token renewed")
+	}
+}
+
+// transport scopes the requests to the namespace,
+// and authenticates them with the token of the authenticator, when any.
+type transport struct {
+	base          http.RoundTripper
+	namespace     string
+	authenticator *authenticator
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.roundTrip(req)
+	if err != nil || t.authenticator == nil || req.Body != nil || !isACLNotFound(resp) {
+		return resp, err
+	}
+
+	// The token has been revoked, or has expired before its renewal: the request is sent again with a new token.
+	if err := t.authenticator.Renew(); err != nil {
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	return t.roundTrip(req)
+}
+
+func (t *transport) roundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	if t.namespace != "" {
+		req.Header.Set(namespaceHeader, t.namespace)
+	}
+
+	if t.authenticator != nil {
+		token, err := t.authenticator.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set(tokenHeader, token)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// isACLNotFound returns whether the response is the Consul one to an unknown token.
+// Its body is restored to be read again.
+func isACLNotFound(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return err == nil && strings.Contains(string(body), "ACL not found")
+}
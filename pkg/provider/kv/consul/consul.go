@@ -1,16 +1,33 @@
 package consul
 
 import (
-	"github.com/abronan/valkeyrie/store"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kv"
+	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
+const providerName = "consul"
+
 var _ provider.Provider = (*Provider)(nil)
 
 // Provider holds configurations of the provider.
 type Provider struct {
 	kv.Provider `export:"true"`
+
+	Token      string      `description:"Per-request ACL token." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	Namespace  string      `description:"Namespace of the KV store (Consul Enterprise only)." json:"namespace,omitempty" toml:"namespace,omitempty" yaml:"namespace,omitempty" export:"true"`
+	AuthMethod *AuthMethod `description:"Logs in with a Consul auth method to get the ACL token, which is renewed automatically." json:"authMethod,omitempty" toml:"authMethod,omitempty" yaml:"authMethod,omitempty" export:"true"`
+
+	authenticator *authenticator
 }
 
 // SetDefaults sets the default values.
@@ -21,5 +38,84 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
-	return p.Provider.Init(store.CONSUL, "consul")
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
+
+	kvStore, err := p.createStore(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to Connect to KV store: %w", err)
+	}
+
+	p.Provider.InitWithStore(kvStore, providerName)
+
+	return nil
+}
+
+// Provide allows the consul provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	if p.authenticator != nil {
+		pool.GoCtx(func(ctxPool context.Context) {
+			p.authenticator.watch(log.With(ctxPool, log.Str(log.ProviderName, providerName)))
+		})
+	}
+
+	return p.Provider.Provide(configurationChan, pool)
+}
+
+func (p *Provider) createStore(ctx context.Context) (*kvStore, error) {
+	if len(p.Endpoints) != 1 {
+		return nil, errors.New("a single Consul endpoint is expected")
+	}
+
+	if p.AuthMethod != nil {
+		if p.Token != "" {
+			return nil, errors.New("the token and the auth method are mutually exclusive")
+		}
+
+		if p.AuthMethod.Name == "" {
+			return nil, errors.New("the name of the auth method is missing")
+		}
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	config := api.DefaultConfig()
+	config.Address = p.Endpoints[0]
+	config.WaitTime = 3 * time.Second
+	if p.Token != "" {
+		config.Token = p.Token
+	}
+
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		base.TLSClientConfig = tlsConfig
+		config.Scheme = "https"
+	}
+
+	p.authenticator = nil
+	if p.AuthMethod != nil {
+		loginConfig := *config
+		loginConfig.HttpClient = &http.Client{Transport: &transport{base: base, namespace: p.Namespace}}
+
+		loginClient, err := api.NewClient(&loginConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		p.authenticator = &authenticator{method: p.AuthMethod, client: loginClient}
+	}
+
+	config.HttpClient = &http.Client{
+		Transport: &transport{base: base, namespace: p.Namespace, authenticator: p.authenticator},
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kvStore{client: client}, nil
 }
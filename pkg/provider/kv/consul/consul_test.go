@@ -0,0 +1,235 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
+)
+
+// consulServer is a fake Consul agent, serving the KV pairs of the traefik root key,
+// to the requests authenticated with the tokens of its login endpoint.
+type consulServer struct {
+	*httptest.Server
+
+	ttl time.Duration
+
+	mu         sync.Mutex
+	logins     int
+	valid      map[string]bool
+	loggedOut  []string
+	namespaces []string
+}
+
+func newConsulServer(t *testing.T, ttl time.Duration) *consulServer {
+	t.Helper()
+
+	s := &consulServer{ttl: ttl, valid: make(map[string]bool)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/acl/login", s.login)
+	mux.HandleFunc("/v1/acl/logout", s.logout)
+	mux.HandleFunc("/v1/kv/traefik", s.list)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *consulServer) login(rw http.ResponseWriter, req *http.Request) {
+	var params api.ACLLoginParams
+	if err := json.NewDecoder(req.Body).Decode(&params); err != nil || params.AuthMethod != "kubernetes" || params.BearerToken != "jwt" {
+		http.Error(rw, "invalid login", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	s.logins++
+	token := &api.ACLToken{AccessorID: fmt.Sprintf("accessor-%d", s.logins), SecretID: fmt.Sprintf("secret-%d", s.logins)}
+	s.valid[token.SecretID] = true
+	s.namespaces = append(s.namespaces, req.Header.Get(namespaceHeader))
+	s.mu.Unlock()
+
+	if s.ttl > 0 {
+		expiration := time.Now().Add(s.ttl)
+		token.ExpirationTime = &expiration
+	}
+
+	_ = json.NewEncoder(rw).Encode(token)
+}
+
+func (s *consulServer) logout(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := req.Header.Get(tokenHeader)
+	delete(s.valid, token)
+	s.loggedOut = append(s.loggedOut, token)
+}
+
+func (s *consulServer) list(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	valid := s.valid[req.Header.Get(tokenHeader)]
+	s.namespaces = append(s.namespaces, req.Header.Get(namespaceHeader))
+	s.mu.Unlock()
+
+	if !valid {
+		http.Error(rw, "ACL not found", http.StatusForbidden)
+		return
+	}
+
+	rw.Header().Set("X-Consul-Index", "1")
+	_ = json.NewEncoder(rw).Encode(api.KVPairs{
+		{Key: "traefik/http/routers/foo/rule", Value: []byte("Host(`foo.example.com`)"), ModifyIndex: 1},
+	})
+}
+
+func (s *consulServer) revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.valid, token)
+}
+
+func newProvider(t *testing.T, server *consulServer) *Provider {
+	t.Helper()
+
+	bearerTokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(bearerTokenFile, []byte("jwt\n"), 0o600))
+
+	p := &Provider{}
+	p.SetDefaults()
+	p.Endpoints = []string{server.Listener.Addr().String()}
+	p.Namespace = "team"
+	p.AuthMethod = &AuthMethod{Name: "kubernetes", BearerTokenFile: bearerTokenFile}
+
+	return p
+}
+
+func TestProvider_authMethod(t *testing.T) {
+	server := newConsulServer(t, 0)
+	p := newProvider(t, server)
+
+	kvStore, err := p.createStore(context.Background())
+	require.NoError(t, err)
+
+	pairs, err := kvStore.List("traefik", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "Host(`foo.example.com`)", string(pairs[0].Value))
+
+	// The token is revoked: the provider logs in again.
+	server.revoke("secret-1")
+
+	pairs, err = kvStore.List("traefik", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+
+	assert.Equal(t, 2, server.logins)
+	assert.Equal(t, []string{"team", "team", "team", "team", "team"}, server.namespaces)
+}
+
+func TestProvider_authMethodRenewal(t *testing.T) {
+	server := newConsulServer(t, time.Hour)
+	p := newProvider(t, server)
+
+	_, err := p.createStore(context.Background())
+	require.NoError(t, err)
+
+	_, ok := p.authenticator.renewDelay()
+	assert.False(t, ok)
+
+	token, err := p.authenticator.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "secret-1", token)
+
+	delay, ok := p.authenticator.renewDelay()
+	require.True(t, ok)
+	assert.InDelta(t, 30*time.Minute, delay, float64(time.Minute))
+
+	require.NoError(t, p.authenticator.Renew())
+
+	token, err = p.authenticator.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "secret-2", token)
+	assert.Equal(t, []string{"secret-1"}, server.loggedOut)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.authenticator.watch(ctx)
+
+	assert.Equal(t, []string{"secret-1", "secret-2"}, server.loggedOut)
+}
+
+func TestProvider_Init(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		provider      *Provider
+		expectedError bool
+	}{
+		{
+			desc: "defaults",
+			provider: &Provider{
+				Provider: kv.Provider{Endpoints: []string{"127.0.0.1:8500"}},
+			},
+		},
+		{
+			desc: "auth method",
+			provider: &Provider{
+				Provider:   kv.Provider{Endpoints: []string{"127.0.0.1:8500"}},
+				AuthMethod: &AuthMethod{Name: "kubernetes"},
+			},
+		},
+		{
+			desc: "multiple endpoints",
+			provider: &Provider{
+				Provider: kv.Provider{Endpoints: []string{"127.0.0.1:8500", "127.0.0.1:8501"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "token and auth method",
+			provider: &Provider{
+				Provider:   kv.Provider{Endpoints: []string{"127.0.0.1:8500"}},
+				Token:      "secret",
+				AuthMethod: &AuthMethod{Name: "kubernetes"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "auth method without name",
+			provider: &Provider{
+				Provider:   kv.Provider{Endpoints: []string{"127.0.0.1:8500"}},
+				AuthMethod: &AuthMethod{},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.provider.Init()
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
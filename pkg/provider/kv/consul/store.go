@@ -0,0 +1,171 @@
+package consul
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/hashicorp/consul/api"
+)
+
+// watchWaitTime is how long the watches block at a time to check if the watched keys have changed,
+// which is also the minimum time it takes to cancel a watch.
+const watchWaitTime = 15 * time.Second
+
+var _ store.Store = (*kvStore)(nil)
+
+// kvStore is a read-only store.Store backed by the Consul KV API,
+// which unlike the valkeyrie one uses the HTTP client of the provider,
+// scoping the requests to the namespace and authenticating them with the auth method token.
+type kvStore struct {
+	client *api.Client
+}
+
+// Get a value given its key.
+func (s *kvStore) Get(key string, opts *store.ReadOptions) (*store.KVPair, error) {
+	options := &api.QueryOptions{RequireConsistent: true}
+	if opts != nil {
+		options.RequireConsistent = opts.Consistent
+	}
+
+	pair, meta, err := s.client.KV().Get(normalize(key), options)
+	if err != nil {
+		return nil, err
+	}
+
+	if pair == nil {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return &store.KVPair{Key: pair.Key, Value: pair.Value, LastIndex: meta.LastIndex}, nil
+}
+
+// Exists checks if the key exists.
+func (s *kvStore) Exists(key string, opts *store.ReadOptions) (bool, error) {
+	_, err := s.Get(key, opts)
+	if err != nil {
+		if err == store.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List the child nodes of the directory.
+func (s *kvStore) List(directory string, opts *store.ReadOptions) ([]*store.KVPair, error) {
+	options := &api.QueryOptions{RequireConsistent: true}
+	if opts != nil && !opts.Consistent {
+		options.AllowStale = true
+		options.RequireConsistent = false
+	}
+
+	pairs, _, err := s.client.KV().List(normalize(directory), options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return toKVPairs(directory, pairs), nil
+}
+
+// WatchTree watches for the changes of the child nodes of the directory.
+func (s *kvStore) WatchTree(directory string, stopCh <-chan struct{}, _ *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	watchCh := make(chan []*store.KVPair)
+
+	go func() {
+		defer close(watchCh)
+
+		opts := &api.QueryOptions{WaitTime: watchWaitTime}
+		for {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+
+			pairs, meta, err := s.client.KV().List(directory, opts)
+			if err != nil {
+				return
+			}
+
+			// The LastIndex is unchanged when the query returns because of the WaitTime.
+			if opts.WaitIndex == meta.LastIndex {
+				continue
+			}
+			opts.WaitIndex = meta.LastIndex
+
+			select {
+			case <-stopCh:
+				return
+			case watchCh <- toKVPairs(directory, pairs):
+			}
+		}
+	}()
+
+	return watchCh, nil
+}
+
+// Close the store connection.
+func (s *kvStore) Close() {}
+
+// Put is not supported.
+func (s *kvStore) Put(string, []byte, *store.WriteOptions) error {
+	return store.ErrCallNotSupported
+}
+
+// Delete is not supported.
+func (s *kvStore) Delete(string) error {
+	return store.ErrCallNotSupported
+}
+
+// Watch is not supported.
+func (s *kvStore) Watch(string, <-chan struct{}, *store.ReadOptions) (<-chan *store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+// NewLock is not supported.
+func (s *kvStore) NewLock(string, *store.LockOptions) (store.Locker, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+// DeleteTree is not supported.
+func (s *kvStore) DeleteTree(string) error {
+	return store.ErrCallNotSupported
+}
+
+// AtomicPut is not supported.
+func (s *kvStore) AtomicPut(string, []byte, *store.KVPair, *store.WriteOptions) (bool, *store.KVPair, error) {
+	return false, nil, store.ErrCallNotSupported
+}
+
+// AtomicDelete is not supported.
+func (s *kvStore) AtomicDelete(string, *store.KVPair) (bool, error) {
+	return false, store.ErrCallNotSupported
+}
+
+func toKVPairs(directory string, pairs api.KVPairs) []*store.KVPair {
+	kvPairs := []*store.KVPair{}
+	for _, pair := range pairs {
+		if pair.Key == directory {
+			continue
+		}
+
+		kvPairs = append(kvPairs, &store.KVPair{
+			Key:       pair.Key,
+			Value:     pair.Value,
+			LastIndex: pair.ModifyIndex,
+		})
+	}
+
+	return kvPairs
+}
+
+// normalize the key for the Consul KV API.
+func normalize(key string) string {
+	return strings.TrimPrefix(store.Normalize(key), "/")
+}
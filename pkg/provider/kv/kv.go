@@ -11,7 +11,6 @@ import (
 	"github.com/abronan/valkeyrie/store"
 	"github.com/abronan/valkeyrie/store/consul"
 	etcdv3 "github.com/abronan/valkeyrie/store/etcd/v3"
-	"github.com/abronan/valkeyrie/store/redis"
 	"github.com/abronan/valkeyrie/store/zookeeper"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -58,6 +57,13 @@ func (p *Provider) Init(storeType store.Backend, name string) error {
 	return nil
 }
 
+// InitWithStore initializes the provider with the given KV store client,
+// for the providers which create their own client.
+func (p *Provider) InitWithStore(kvClient store.Store, name string) {
+	p.name = name
+	p.kvClient = &storeWrapper{Store: kvClient}
+}
+
 // Provide allows the docker provider to provide configurations to traefik using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.name))
@@ -181,8 +187,6 @@ func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
 		etcdv3.Register()
 	case store.ZK:
 		zookeeper.Register()
-	case store.REDIS:
-		redis.Register()
 	}
 
 	kvStore, err := valkeyrie.NewStore(p.storeType, p.Endpoints, storeConfig)
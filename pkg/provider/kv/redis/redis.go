@@ -1,16 +1,34 @@
 package redis
 
 import (
-	"github.com/abronan/valkeyrie/store"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kv"
 )
 
+const providerName = "redis"
+
 var _ provider.Provider = (*Provider)(nil)
 
 // Provider holds configurations of the provider.
+// Several endpoints are the seed nodes of a Redis Cluster, unless Sentinel is enabled.
 type Provider struct {
 	kv.Provider `export:"true"`
+
+	DB       int       `description:"Database to be selected after connecting to the server (not supported by Redis Cluster)." json:"db,omitempty" toml:"db,omitempty" yaml:"db,omitempty" export:"true"`
+	Sentinel *Sentinel `description:"Enable Sentinel support, the endpoints being the Sentinel nodes." json:"sentinel,omitempty" toml:"sentinel,omitempty" yaml:"sentinel,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// Sentinel holds the Redis Sentinel configuration.
+type Sentinel struct {
+	MasterName string `description:"Name of the master." json:"masterName,omitempty" toml:"masterName,omitempty" yaml:"masterName,omitempty" export:"true"`
+	Password   string `description:"Password for the Sentinel nodes." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
 }
 
 // SetDefaults sets the default values.
@@ -21,5 +39,51 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
-	return p.Provider.Init(store.REDIS, "redis")
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
+
+	client, err := p.createClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to Connect to KV store: %w", err)
+	}
+
+	p.Provider.InitWithStore(&kvStore{client: client, db: p.DB}, providerName)
+
+	return nil
+}
+
+func (p *Provider) createClient(ctx context.Context) (redis.UniversalClient, error) {
+	if len(p.Endpoints) == 0 {
+		return nil, errors.New("at least one Redis endpoint is expected")
+	}
+
+	options := &redis.UniversalOptions{
+		Addrs:        p.Endpoints,
+		DB:           p.DB,
+		Username:     p.Username,
+		Password:     p.Password,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	if p.Sentinel != nil {
+		if p.Sentinel.MasterName == "" {
+			return nil, errors.New("the name of the Sentinel master is missing")
+		}
+
+		options.MasterName = p.Sentinel.MasterName
+		options.SentinelPassword = p.Sentinel.Password
+	} else if len(p.Endpoints) > 1 && p.DB != 0 {
+		return nil, errors.New("the database cannot be selected with Redis Cluster")
+	}
+
+	if p.TLS != nil {
+		var err error
+		options.TLSConfig, err = p.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return redis.NewUniversalClient(options), nil
 }
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
+)
+
+func TestProvider_createClient(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		provider      Provider
+		expected      interface{}
+		expectedError bool
+	}{
+		{
+			desc:     "single endpoint",
+			provider: Provider{Provider: kv.Provider{Endpoints: []string{"127.0.0.1:6379"}}, DB: 1},
+			expected: &redis.Client{},
+		},
+		{
+			desc:     "cluster",
+			provider: Provider{Provider: kv.Provider{Endpoints: []string{"127.0.0.1:7000", "127.0.0.1:7001"}}},
+			expected: &redis.ClusterClient{},
+		},
+		{
+			desc: "sentinel",
+			provider: Provider{
+				Provider: kv.Provider{Endpoints: []string{"127.0.0.1:26379", "127.0.0.1:26380"}},
+				DB:       1,
+				Sentinel: &Sentinel{MasterName: "mymaster"},
+			},
+			expected: &redis.Client{},
+		},
+		{
+			desc:          "no endpoint",
+			provider:      Provider{},
+			expectedError: true,
+		},
+		{
+			desc: "sentinel without master name",
+			provider: Provider{
+				Provider: kv.Provider{Endpoints: []string{"127.0.0.1:26379"}},
+				Sentinel: &Sentinel{},
+			},
+			expectedError: true,
+		},
+		{
+			desc:          "cluster with database",
+			provider:      Provider{Provider: kv.Provider{Endpoints: []string{"127.0.0.1:7000", "127.0.0.1:7001"}}, DB: 1},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := test.provider.createClient(context.Background())
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = client.Close() })
+
+			assert.IsType(t, test.expected, client)
+		})
+	}
+}
+
+func TestEscapePattern(t *testing.T) {
+	assert.Equal(t, "traefik", escapePattern("traefik"))
+	assert.Equal(t, `foo\*bar\?\[baz\]\\`, escapePattern(`foo*bar?[baz]\`))
+}
@@ -0,0 +1,310 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/go-redis/redis/v8"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+var _ store.Store = (*kvStore)(nil)
+
+// kvStore is a read-only store.Store backed by Redis, Redis Cluster or Redis Sentinel,
+// which unlike the valkeyrie one watches the keys with the keyspace notifications of all the master nodes.
+// The values are the raw strings written by the valkeyrie store.
+type kvStore struct {
+	client redis.UniversalClient
+	db     int
+}
+
+// Get a value given its key.
+func (s *kvStore) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	value, err := s.client.Get(context.Background(), normalize(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, store.ErrKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &store.KVPair{Key: normalize(key), Value: value}, nil
+}
+
+// Exists checks if the key exists.
+func (s *kvStore) Exists(key string, _ *store.ReadOptions) (bool, error) {
+	count, err := s.client.Exists(context.Background(), normalize(key)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// List the child nodes of the directory.
+func (s *kvStore) List(directory string, _ *store.ReadOptions) ([]*store.KVPair, error) {
+	ctx := context.Background()
+
+	keys, err := s.keys(ctx, escapePattern(normalize(directory))+"/*")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	// The pipeline sends the commands to the node of each key with Redis Cluster,
+	// where a multi-key command such as MGET fails if the keys belong to different slots.
+	pipe := s.client.Pipeline()
+
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Get(ctx, key)
+	}
+
+	// The errors are checked for each command, as the keys deleted since the scan are skipped.
+	_, _ = pipe.Exec(ctx)
+
+	pairs := []*store.KVPair{}
+	for i, cmd := range cmds {
+		value, err := cmd.Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, &store.KVPair{Key: keys[i], Value: value})
+	}
+
+	return pairs, nil
+}
+
+// WatchTree watches for the changes of the child nodes of the directory,
+// with the keyspace notifications which are enabled when needed.
+func (s *kvStore) WatchTree(directory string, stopCh <-chan struct{}, _ *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pattern := fmt.Sprintf("__keyspace@%d__:%s/*", s.db, escapePattern(normalize(directory)))
+
+	subscriptions, err := s.subscribe(ctx, pattern)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The notifications are coalesced, as the whole directory is listed for each of them.
+	events := make(chan struct{}, 1)
+	for _, subscription := range subscriptions {
+		go func(messages <-chan *redis.Message) {
+			for range messages {
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			}
+		}(subscription.Channel())
+	}
+
+	watchCh := make(chan []*store.KVPair)
+
+	go func() {
+		defer cancel()
+		defer close(watchCh)
+
+		for _, subscription := range subscriptions {
+			defer func(subscription *redis.PubSub) { _ = subscription.Close() }(subscription)
+		}
+
+		for {
+			pairs, err := s.List(directory, nil)
+			if err != nil && err != store.ErrKeyNotFound {
+				return
+			}
+
+			select {
+			case <-stopCh:
+				return
+			case watchCh <- pairs:
+			}
+
+			select {
+			case <-stopCh:
+				return
+			case <-events:
+			}
+		}
+	}()
+
+	return watchCh, nil
+}
+
+// Close the store connection.
+func (s *kvStore) Close() {
+	_ = s.client.Close()
+}
+
+// Put is not supported.
+func (s *kvStore) Put(string, []byte, *store.WriteOptions) error {
+	return store.ErrCallNotSupported
+}
+
+// Delete is not supported.
+func (s *kvStore) Delete(string) error {
+	return store.ErrCallNotSupported
+}
+
+// Watch is not supported.
+func (s *kvStore) Watch(string, <-chan struct{}, *store.ReadOptions) (<-chan *store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+// NewLock is not supported.
+func (s *kvStore) NewLock(string, *store.LockOptions) (store.Locker, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+// DeleteTree is not supported.
+func (s *kvStore) DeleteTree(string) error {
+	return store.ErrCallNotSupported
+}
+
+// AtomicPut is not supported.
+func (s *kvStore) AtomicPut(string, []byte, *store.KVPair, *store.WriteOptions) (bool, *store.KVPair, error) {
+	return false, nil, store.ErrCallNotSupported
+}
+
+// AtomicDelete is not supported.
+func (s *kvStore) AtomicDelete(string, *store.KVPair) (bool, error) {
+	return false, store.ErrCallNotSupported
+}
+
+// keys returns the keys matching the pattern, which are scanned on all the master nodes with Redis Cluster.
+func (s *kvStore) keys(ctx context.Context, pattern string) ([]string, error) {
+	cluster, ok := s.client.(*redis.ClusterClient)
+	if !ok {
+		return scan(ctx, s.client, pattern)
+	}
+
+	var mu sync.Mutex
+	var keys []string
+
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		nodeKeys, err := scan(ctx, client, pattern)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		keys = append(keys, nodeKeys...)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// subscribe subscribes to the channels matching the pattern on all the master nodes with Redis Cluster,
+// as the keyspace notifications are only published by the node of the key.
+func (s *kvStore) subscribe(ctx context.Context, pattern string) ([]*redis.PubSub, error) {
+	cluster, ok := s.client.(*redis.ClusterClient)
+	if !ok {
+		subscription, err := psubscribe(ctx, s.client, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*redis.PubSub{subscription}, nil
+	}
+
+	var mu sync.Mutex
+	var subscriptions []*redis.PubSub
+
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		subscription, err := psubscribe(ctx, client, pattern)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		subscriptions = append(subscriptions, subscription)
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		for _, subscription := range subscriptions {
+			_ = subscription.Close()
+		}
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func scan(ctx context.Context, client redis.UniversalClient, pattern string) ([]string, error) {
+	var keys []string
+
+	iter := client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	return keys, iter.Err()
+}
+
+func psubscribe(ctx context.Context, client redis.UniversalClient, pattern string) (*redis.PubSub, error) {
+	if err := enableKeyspaceEvents(ctx, client); err != nil {
+		log.FromContext(ctx).Warnf("Unable to enable the keyspace notifications, notify-keyspace-events must contain K and A: %v", err)
+	}
+
+	subscription := client.PSubscribe(ctx, pattern)
+
+	// Waits for the confirmation of the subscription to report the connection errors.
+	if _, err := subscription.Receive(ctx); err != nil {
+		_ = subscription.Close()
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// enableKeyspaceEvents adds the keyspace notifications of all the events to the notify-keyspace-events configuration of the node,
+// which is left untouched if it already enables them, the CONFIG command being usually disabled by the managed Redis services.
+func enableKeyspaceEvents(ctx context.Context, client redis.UniversalClient) error {
+	values, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return err
+	}
+
+	var flags string
+	if len(values) == 2 {
+		flags, _ = values[1].(string)
+	}
+
+	if strings.Contains(flags, "K") && strings.Contains(flags, "A") {
+		return nil
+	}
+
+	return client.ConfigSet(ctx, "notify-keyspace-events", flags+"KA").Err()
+}
+
+// escapePattern escapes the special characters of the glob-style patterns of SCAN and PSUBSCRIBE.
+func escapePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return replacer.Replace(s)
+}
+
+// normalize the key the way the valkeyrie store does.
+func normalize(key string) string {
+	return strings.TrimPrefix(store.Normalize(key), "/")
+}
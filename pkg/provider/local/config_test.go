@@ -0,0 +1,361 @@
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func Int(v int) *int    { return &v }
+func Bool(v bool) *bool { return &v }
+
+func Test_buildConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		items       []itemData
+		constraints string
+		expected    *dynamic.Configuration
+	}{
+		{
+			desc: "one service no label",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8080",
+					Labels:  map[string]string{},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"whoami": {
+							Service: "whoami",
+							Rule:    "Host(`whoami.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"whoami": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "two services with the same name",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8080",
+					Labels:  map[string]string{},
+				},
+				{
+					ID:      "registry.yml#0",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8081",
+					Labels:  map[string]string{},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"whoami": {
+							Service: "whoami",
+							Rule:    "Host(`whoami.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"whoami": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:8081",
+									},
+									{
+										URL: "http://127.0.0.1:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "one service with router and service labels",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8080",
+					Labels: map[string]string{
+						"traefik.http.routers.router1.rule":                       "Host(`whoami.example.com`)",
+						"traefik.http.services.service1.loadbalancer.server.port": "8081",
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"router1": {
+							Service: "service1",
+							Rule:    "Host(`whoami.example.com`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"service1": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:8081",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "one service with TCP and UDP routers",
+			items: []itemData{
+				{
+					ID:      "postgres.service",
+					Name:    "postgres",
+					Address: "10.0.0.2",
+					Port:    "5432",
+					Labels: map[string]string{
+						"traefik.tcp.routers.postgres.rule":        "HostSNI(`*`)",
+						"traefik.udp.routers.postgres.entrypoints": "udp",
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"postgres": {
+							Service: "postgres",
+							Rule:    "HostSNI(`*`)",
+						},
+					},
+					Services: map[string]*dynamic.TCPService{
+						"postgres": {
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{
+									{
+										Address: "10.0.0.2:5432",
+									},
+								},
+								TerminationDelay: Int(100),
+							},
+						},
+					},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{
+						"postgres": {
+							EntryPoints: []string{"udp"},
+							Service:     "postgres",
+						},
+					},
+					Services: map[string]*dynamic.UDPService{
+						"postgres": {
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "10.0.0.2:5432",
+									},
+								},
+							},
+						},
+					},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc: "one service without port",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Labels:  map[string]string{},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc: "one disabled service",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8080",
+					Labels: map[string]string{
+						"traefik.enable": "false",
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc: "one service not matching constraints",
+			items: []itemData{
+				{
+					ID:      "whoami.service",
+					Name:    "whoami",
+					Address: "127.0.0.1",
+					Port:    "8080",
+					Labels: map[string]string{
+						"traefik.tags": "foo",
+					},
+				},
+			},
+			constraints: `Label("traefik.tags", "bar")`,
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:  map[string]*dynamic.TCPRouter{},
+					Services: map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				ExposedByDefault: true,
+				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+			}
+			p.Constraints = test.constraints
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			for i := 0; i < len(test.items); i++ {
+				var err error
+				test.items[i].ExtraConf, err = p.getConfiguration(test.items[i])
+				require.NoError(t, err)
+			}
+
+			configuration := p.buildConfiguration(context.Background(), test.items)
+
+			assert.Equal(t, test.expected, configuration)
+		})
+	}
+}
+
+func TestProvider_getItems(t *testing.T) {
+	p := Provider{}
+	p.SetDefaults()
+	p.UnitDirectories = []string{filepath.Join("fixtures", "units")}
+	p.RegistryFile = filepath.Join("fixtures", "registry.yml")
+
+	items, err := p.getItems(context.Background())
+	require.NoError(t, err)
+
+	var names, addresses []string
+	var enabled []bool
+	for _, item := range items {
+		names = append(names, item.Name)
+		addresses = append(addresses, item.Address)
+		enabled = append(enabled, item.ExtraConf.Enable)
+	}
+
+	assert.Equal(t, []string{"postgres", "whoami", "api", "dns"}, names)
+	assert.Equal(t, []string{"10.0.0.2", "127.0.0.1", "127.0.0.1", "10.0.0.3"}, addresses)
+	assert.Equal(t, []bool{false, true, true, false}, enabled)
+}
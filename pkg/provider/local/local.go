@@ -0,0 +1,164 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+const providerName = "local"
+
+// DefaultTemplateRule The default template for the default rule.
+const DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
+
+var _ provider.Provider = (*Provider)(nil)
+
+type itemData struct {
+	ID        string
+	Name      string
+	Address   string
+	Port      string
+	Labels    map[string]string
+	ExtraConf configuration
+}
+
+// Provider holds configurations of the provider.
+// It discovers the services running on the local host, from the systemd unit files and from a process registry file,
+// for the deployments without any orchestrator.
+type Provider struct {
+	UnitDirectories  []string        `description:"Directories of the systemd unit files to discover the services from." json:"unitDirectories,omitempty" toml:"unitDirectories,omitempty" yaml:"unitDirectories,omitempty" export:"true"`
+	RegistryFile     string          `description:"Path of the process registry file to discover the services from." json:"registryFile,omitempty" toml:"registryFile,omitempty" yaml:"registryFile,omitempty" export:"true"`
+	DefaultAddress   string          `description:"Address of the discovered services which do not define one." json:"defaultAddress,omitempty" toml:"defaultAddress,omitempty" yaml:"defaultAddress,omitempty" export:"true"`
+	Constraints      string          `description:"Constraints is an expression that Traefik matches against the service's labels to determine whether to create any route for that service." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	ExposedByDefault bool            `description:"Expose services by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	DefaultRule      string          `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	RefreshInterval  ptypes.Duration `description:"Interval between the discoveries of the services. Default 15s" json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+
+	defaultRuleTpl *template.Template
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.UnitDirectories = []string{"/etc/systemd/system"}
+	p.DefaultAddress = "127.0.0.1"
+	p.DefaultRule = DefaultTemplateRule
+	p.RefreshInterval = ptypes.Duration(15 * time.Second)
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	defaultRuleTpl, err := provider.MakeDefaultRuleTemplate(p.DefaultRule, nil)
+	if err != nil {
+		return fmt.Errorf("error while parsing default rule: %w", err)
+	}
+
+	p.defaultRuleTpl = defaultRuleTpl
+	return nil
+}
+
+// Provide allows the local provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		operation := func() error {
+			// get configuration at the provider's startup.
+			err := p.loadConfiguration(ctxLog, configurationChan)
+			if err != nil {
+				return fmt.Errorf("failed to discover the local services: %w", err)
+			}
+
+			// Periodic refreshes.
+			ticker := time.NewTicker(time.Duration(p.RefreshInterval))
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					err = p.loadConfiguration(ctxLog, configurationChan)
+					if err != nil {
+						return fmt.Errorf("failed to refresh the local services: %w", err)
+					}
+
+				case <-routineCtx.Done():
+					return nil
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider error %+v, retrying in %s", err, time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+		if err != nil {
+			logger.Errorf("Cannot discover the local services %+v", err)
+		}
+	})
+
+	return nil
+}
+
+func (p *Provider) loadConfiguration(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	items, err := p.getItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	configurationChan <- dynamic.Message{
+		ProviderName:  providerName,
+		Configuration: p.buildConfiguration(ctx, items),
+	}
+
+	return nil
+}
+
+// getItems returns the services declared by the unit files and by the registry file.
+func (p *Provider) getItems(ctx context.Context) ([]itemData, error) {
+	var items []itemData
+	for _, directory := range p.UnitDirectories {
+		units, err := readUnits(ctx, directory)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, units...)
+	}
+
+	if p.RegistryFile != "" {
+		processes, err := readRegistry(ctx, p.RegistryFile)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, processes...)
+	}
+
+	var data []itemData
+	for _, item := range items {
+		if item.Address == "" {
+			item.Address = p.DefaultAddress
+		}
+
+		extraConf, err := p.getConfiguration(item)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Skip item %s: %v", item.Name, err)
+			continue
+		}
+		item.ExtraConf = extraConf
+
+		data = append(data, item)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,70 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"gopkg.in/yaml.v3"
+)
+
+// registry is the content of the process registry file,
+// where the local processes declare themselves.
+type registry struct {
+	Services []registryService `yaml:"services"`
+}
+
+type registryService struct {
+	Name    string            `yaml:"name"`
+	Address string            `yaml:"address"`
+	Port    int               `yaml:"port"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+// readRegistry returns the services declared by the registry file, in the YAML or JSON format.
+// A missing file declares no service, as it may be written later on.
+func readRegistry(ctx context.Context, path string) ([]itemData, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.FromContext(ctx).Debugf("Skipping the missing registry file %s", path)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read the registry file: %w", err)
+	}
+
+	var reg registry
+	if err := yaml.Unmarshal(content, &reg); err != nil {
+		return nil, fmt.Errorf("unable to decode the registry file: %w", err)
+	}
+
+	var items []itemData
+	for i, service := range reg.Services {
+		if service.Name == "" {
+			return nil, fmt.Errorf("the name of the registry service %d is missing", i)
+		}
+
+		item := itemData{
+			ID:      fmt.Sprintf("%s#%d", path, i),
+			Name:    service.Name,
+			Address: service.Address,
+			Labels:  service.Labels,
+		}
+
+		if service.Port > 0 {
+			item.Port = strconv.Itoa(service.Port)
+		}
+
+		if item.Labels == nil {
+			item.Labels = make(map[string]string)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,72 @@
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readRegistry(t *testing.T) {
+	testCases := []struct {
+		desc string
+		path string
+	}{
+		{
+			desc: "YAML registry",
+			path: filepath.Join("fixtures", "registry.yml"),
+		},
+		{
+			desc: "JSON registry",
+			path: filepath.Join("fixtures", "registry.json"),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			items, err := readRegistry(context.Background(), test.path)
+			require.NoError(t, err)
+
+			expected := []itemData{
+				{
+					ID:   test.path + "#0",
+					Name: "api",
+					Port: "3000",
+					Labels: map[string]string{
+						"traefik.enable":                "true",
+						"traefik.http.routers.api.rule": "Host(`api.example.com`)",
+					},
+				},
+				{
+					ID:      test.path + "#1",
+					Name:    "dns",
+					Address: "10.0.0.3",
+					Port:    "53",
+					Labels: map[string]string{
+						"traefik.udp.routers.dns.entrypoints": "dns",
+					},
+				},
+			}
+
+			assert.Equal(t, expected, items)
+		})
+	}
+}
+
+func Test_readRegistry_errors(t *testing.T) {
+	items, err := readRegistry(context.Background(), filepath.Join(t.TempDir(), "missing.yml"))
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	path := filepath.Join(t.TempDir(), "registry.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("services:\n  - port: 80\n"), 0o600))
+
+	_, err = readRegistry(context.Background(), path)
+	assert.Error(t, err)
+}
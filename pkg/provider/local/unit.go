@@ -0,0 +1,99 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-systemd/unit"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// unitSection is the section of the unit files holding the Traefik metadata,
+// which is ignored by systemd as its name starts with X-.
+const unitSection = "X-Traefik"
+
+// readUnits returns the services declared by the systemd unit files of the directory,
+// with their Label, Address and Port options of the X-Traefik section.
+// The unit files without such a section, and the template unit files, are ignored.
+func readUnits(ctx context.Context, directory string) ([]itemData, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.FromContext(ctx).Debugf("Skipping the missing unit directory %s", directory)
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read the unit directory: %w", err)
+	}
+
+	var items []itemData
+	for _, file := range files {
+		name := strings.TrimSuffix(file.Name(), ".service")
+		if file.IsDir() || name == file.Name() || strings.HasSuffix(name, "@") {
+			continue
+		}
+
+		path := filepath.Join(directory, file.Name())
+
+		item, ok, err := readUnit(path)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Skipping the unit file %s: %v", path, err)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		item.ID = path
+		item.Name = name
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func readUnit(path string) (itemData, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return itemData{}, false, err
+	}
+	defer func() { _ = file.Close() }()
+
+	options, err := unit.Deserialize(file)
+	if err != nil {
+		return itemData{}, false, err
+	}
+
+	var found bool
+	item := itemData{Labels: make(map[string]string)}
+	for _, option := range options {
+		if option.Section != unitSection {
+			continue
+		}
+
+		found = true
+
+		switch option.Name {
+		case "Label":
+			parts := strings.SplitN(option.Value, "=", 2)
+			if len(parts) != 2 {
+				return itemData{}, false, fmt.Errorf("invalid label %q, key=value expected", option.Value)
+			}
+
+			item.Labels[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		case "Address":
+			item.Address = option.Value
+		case "Port":
+			item.Port = option.Value
+		default:
+			return itemData{}, false, fmt.Errorf("unknown option %s of the %s section", option.Name, unitSection)
+		}
+	}
+
+	return item, found, nil
+}
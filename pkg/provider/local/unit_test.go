@@ -0,0 +1,45 @@
+package local
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readUnits(t *testing.T) {
+	items, err := readUnits(context.Background(), filepath.Join("fixtures", "units"))
+	require.NoError(t, err)
+
+	expected := []itemData{
+		{
+			ID:      filepath.Join("fixtures", "units", "postgres.service"),
+			Name:    "postgres",
+			Address: "10.0.0.2",
+			Port:    "5432",
+			Labels: map[string]string{
+				"traefik.tcp.routers.postgres.rule": "HostSNI(`*`)",
+			},
+		},
+		{
+			ID:   filepath.Join("fixtures", "units", "whoami.service"),
+			Name: "whoami",
+			Port: "8080",
+			Labels: map[string]string{
+				"traefik.enable":                   "true",
+				"traefik.http.routers.whoami.rule": "Host(`whoami.example.com`)",
+			},
+		},
+	}
+
+	assert.Equal(t, expected, items)
+}
+
+func Test_readUnits_missingDirectory(t *testing.T) {
+	items, err := readUnits(context.Background(), filepath.Join("fixtures", "missing"))
+	require.NoError(t, err)
+
+	assert.Empty(t, items)
+}
@@ -86,6 +86,9 @@ type Basic struct {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	log.WithoutContext().WithField(log.ProviderName, "marathon").
+		Warn("The Marathon provider is deprecated, the Inventory provider keeps its label semantics with any inventory service.")
+
 	fm := template.FuncMap{
 		"strsToItfs": func(values []string) []interface{} {
 			var r []interface{}
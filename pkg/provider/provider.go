@@ -12,3 +12,10 @@ type Provider interface {
 	Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error
 	Init() error
 }
+
+// Syncer is implemented by the providers which report when their initial state has been loaded,
+// even when it results in an empty configuration.
+type Syncer interface {
+	// Synced returns the name of the provider, and a channel closed once its initial state has been loaded.
+	Synced() (string, <-chan struct{})
+}
@@ -73,6 +73,9 @@ type rancherData struct {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	log.WithoutContext().WithField(log.ProviderName, "rancher").
+		Warn("The Rancher provider is deprecated, the Inventory provider keeps its label semantics with any inventory service.")
+
 	defaultRuleTpl, err := provider.MakeDefaultRuleTemplate(p.DefaultRule, nil)
 	if err != nil {
 		return fmt.Errorf("error while parsing default rule: %w", err)
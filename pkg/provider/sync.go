@@ -0,0 +1,34 @@
+package provider
+
+import "sync"
+
+// SyncState tracks whether the initial state of a provider has been loaded.
+// The zero value is ready to use.
+type SyncState struct {
+	mu     sync.Mutex
+	once   sync.Once
+	synced chan struct{}
+}
+
+// Done returns a channel closed once the state has been marked as synced.
+func (s *SyncState) Done() <-chan struct{} {
+	return s.channel()
+}
+
+// MarkSynced marks the state as synced. Subsequent calls are no-ops.
+func (s *SyncState) MarkSynced() {
+	s.once.Do(func() {
+		close(s.channel())
+	})
+}
+
+func (s *SyncState) channel() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.synced == nil {
+		s.synced = make(chan struct{})
+	}
+
+	return s.synced
+}
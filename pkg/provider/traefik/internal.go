@@ -68,6 +68,7 @@ func (i *Provider) createConfiguration(ctx context.Context) *dynamic.Configurati
 	i.apiConfiguration(cfg)
 	i.pingConfiguration(cfg)
 	i.restConfiguration(cfg)
+	i.kubernetesWebhookConfiguration(cfg)
 	i.prometheusConfiguration(cfg)
 	i.entryPointModels(cfg)
 	i.redirection(ctx, cfg)
@@ -183,12 +184,13 @@ func (i *Provider) getEntryPointPort(name string, def *static.Redirections) (str
 
 func (i *Provider) entryPointModels(cfg *dynamic.Configuration) {
 	for name, ep := range i.staticCfg.EntryPoints {
-		if len(ep.HTTP.Middlewares) == 0 && ep.HTTP.TLS == nil {
+		if len(ep.HTTP.Middlewares) == 0 && ep.HTTP.TLS == nil && ep.HTTP.AccessLog == nil {
 			continue
 		}
 
 		m := &dynamic.Model{
 			Middlewares: ep.HTTP.Middlewares,
+			AccessLog:   ep.HTTP.AccessLog,
 		}
 
 		if ep.HTTP.TLS != nil {
@@ -266,9 +268,17 @@ func (i *Provider) pingConfiguration(cfg *dynamic.Configuration) {
 			Priority:    math.MaxInt32,
 			Rule:        "PathPrefix(`/ping`)",
 		}
+
+		cfg.HTTP.Routers["ready"] = &dynamic.Router{
+			EntryPoints: []string{i.staticCfg.Ping.EntryPoint},
+			Service:     "ready@internal",
+			Priority:    math.MaxInt32,
+			Rule:        "PathPrefix(`/ready`)",
+		}
 	}
 
 	cfg.HTTP.Services["ping"] = &dynamic.Service{}
+	cfg.HTTP.Services["ready"] = &dynamic.Service{}
 }
 
 func (i *Provider) restConfiguration(cfg *dynamic.Configuration) {
@@ -288,6 +298,27 @@ func (i *Provider) restConfiguration(cfg *dynamic.Configuration) {
 	cfg.HTTP.Services["rest"] = &dynamic.Service{}
 }
 
+func (i *Provider) kubernetesWebhookConfiguration(cfg *dynamic.Configuration) {
+	if i.staticCfg.Providers == nil || i.staticCfg.Providers.KubernetesCRD == nil || i.staticCfg.Providers.KubernetesCRD.Webhook == nil {
+		return
+	}
+
+	webhook := i.staticCfg.Providers.KubernetesCRD.Webhook
+
+	// The Kubernetes API server only calls the webhooks over HTTPS.
+	if !webhook.ManualRouting {
+		cfg.HTTP.Routers["kubernetes-webhook"] = &dynamic.Router{
+			EntryPoints: []string{webhook.EntryPoint},
+			Service:     "kubernetes-webhook@internal",
+			Priority:    math.MaxInt32,
+			Rule:        "PathPrefix(`/kubernetes/webhook`)",
+			TLS:         &dynamic.RouterTLSConfig{},
+		}
+	}
+
+	cfg.HTTP.Services["kubernetes-webhook"] = &dynamic.Service{}
+}
+
 func (i *Provider) prometheusConfiguration(cfg *dynamic.Configuration) {
 	if i.staticCfg.Metrics == nil || i.staticCfg.Metrics.Prometheus == nil {
 		return
@@ -321,6 +352,7 @@ func (i *Provider) serverTransport(cfg *dynamic.Configuration) {
 			DialTimeout:           i.staticCfg.ServersTransport.ForwardingTimeouts.DialTimeout,
 			ResponseHeaderTimeout: i.staticCfg.ServersTransport.ForwardingTimeouts.ResponseHeaderTimeout,
 			IdleConnTimeout:       i.staticCfg.ServersTransport.ForwardingTimeouts.IdleConnTimeout,
+			MaxConnLifetime:       i.staticCfg.ServersTransport.ForwardingTimeouts.MaxConnLifetime,
 		}
 	}
 
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/ping"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
 	"github.com/traefik/traefik/v2/pkg/provider/rest"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -146,6 +147,18 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "kubernetes_webhook.json",
+			staticCfg: static.Configuration{
+				Providers: &static.Providers{
+					KubernetesCRD: &crd.Provider{
+						Webhook: &crd.Webhook{
+							EntryPoint: "test",
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "prometheus_simple.json",
 			staticCfg: static.Configuration{
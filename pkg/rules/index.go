@@ -0,0 +1,264 @@
+package rules
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+)
+
+// routeKeys are the hosts and the path prefixes a request needs to match a route.
+// A nil slice means that the route does not restrict the hosts, or the paths, of the requests.
+type routeKeys struct {
+	hosts    []string
+	prefixes []string
+}
+
+// keysOf returns the keys of a rule, from its Host and Path matchers.
+func keysOf(rule *tree) routeKeys {
+	switch rule.matcher {
+	case "and":
+		left, right := keysOf(rule.ruleLeft), keysOf(rule.ruleRight)
+
+		return routeKeys{
+			hosts:    narrowest(left.hosts, right.hosts),
+			prefixes: narrowest(left.prefixes, right.prefixes),
+		}
+	case "or":
+		left, right := keysOf(rule.ruleLeft), keysOf(rule.ruleRight)
+
+		return routeKeys{
+			hosts:    union(left.hosts, right.hosts),
+			prefixes: union(left.prefixes, right.prefixes),
+		}
+	case "Host", "HostHeader":
+		var hosts []string
+		for _, host := range rule.value {
+			hosts = append(hosts, hostKeys(strings.ToLower(host))...)
+		}
+
+		return routeKeys{hosts: hosts}
+	case "Path", "PathPrefix":
+		var prefixes []string
+		for _, path := range rule.value {
+			// The path matches the literal part of its template, before the first variable.
+			if i := strings.Index(path, "{"); i >= 0 {
+				path = path[:i]
+			}
+
+			prefixes = append(prefixes, path)
+		}
+
+		return routeKeys{prefixes: prefixes}
+	default:
+		return routeKeys{}
+	}
+}
+
+// hostKeys returns the keys of a host, with and without its trailing period,
+// as the host matcher ignores it.
+func hostKeys(host string) []string {
+	if last := len(host) - 1; last >= 0 && host[last] == '.' {
+		return []string{host, host[:last]}
+	}
+
+	return []string{host}
+}
+
+// narrowest returns the keys of one of the sides of an "and", preferring the most selective one.
+// A request matching both sides has one of the keys of each side, but not always the same one,
+// because of the CNAME flattening.
+func narrowest(left, right []string) []string {
+	if left == nil {
+		return right
+	}
+
+	if right == nil || len(left) <= len(right) {
+		return left
+	}
+
+	return right
+}
+
+// union returns the keys allowed by either side of an "or", where nil means no restriction.
+func union(left, right []string) []string {
+	if left == nil || right == nil {
+		return nil
+	}
+
+	return append(append([]string{}, left...), right...)
+}
+
+// routeIndex indexes the routes by host, and then by path prefix,
+// so that only the routes a request can match are evaluated.
+// It only applies to the HTTP routes: the TCP routes are not indexed here,
+// as the TCP router already looks its HostSNI routes up in a map keyed by server name.
+type routeIndex struct {
+	// routes are the routes sorted by priority, the indexes reference their position.
+	routes  []*mux.Route
+	hosts   map[string]*pathIndex
+	anyHost pathIndex
+}
+
+func newRouteIndex(routes []*mux.Route, keys map[*mux.Route]routeKeys) *routeIndex {
+	index := &routeIndex{
+		routes: routes,
+		hosts:  make(map[string]*pathIndex),
+	}
+
+	for position, route := range routes {
+		// The routes without keys failed to build, and never match.
+		routeKeys, ok := keys[route]
+		if !ok {
+			continue
+		}
+
+		if routeKeys.hosts == nil {
+			index.anyHost.add(routeKeys.prefixes, position)
+			continue
+		}
+
+		for _, host := range routeKeys.hosts {
+			if _, exists := index.hosts[host]; !exists {
+				index.hosts[host] = &pathIndex{}
+			}
+
+			index.hosts[host].add(routeKeys.prefixes, position)
+		}
+	}
+
+	return index
+}
+
+// match evaluates the routes the request can match, in the order of their priority.
+// As the Host matchers are case-insensitive, the hosts are indexed, and looked up, in lower case.
+func (i *routeIndex) match(req *http.Request, host string, match *mux.RouteMatch) bool {
+	var hosts []string
+	hosts = append(hosts, hostKeys(strings.ToLower(host))...)
+	if flatH := requestdecorator.GetCNAMEFlatten(req.Context()); len(flatH) > 0 {
+		hosts = append(hosts, hostKeys(strings.ToLower(flatH))...)
+	}
+
+	positions := i.anyHost.collect(req.URL.Path, nil)
+	for _, host := range hosts {
+		if bucket, ok := i.hosts[host]; ok {
+			positions = bucket.collect(req.URL.Path, positions)
+		}
+	}
+
+	sort.Ints(positions)
+
+	previous := -1
+	for _, position := range positions {
+		if position == previous {
+			continue
+		}
+		previous = position
+
+		if i.routes[position].Match(req, match) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathIndex indexes routes by path prefix.
+type pathIndex struct {
+	prefixes pathTrie
+	anyPath  []int
+}
+
+func (p *pathIndex) add(prefixes []string, position int) {
+	if prefixes == nil {
+		p.anyPath = append(p.anyPath, position)
+		return
+	}
+
+	for _, prefix := range prefixes {
+		p.prefixes.insert(prefix, position)
+	}
+}
+
+func (p *pathIndex) collect(path string, positions []int) []int {
+	positions = append(positions, p.anyPath...)
+	return p.prefixes.collect(path, positions)
+}
+
+// pathTrie is a radix tree of path prefixes.
+type pathTrie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	// label is the part of the prefix between the parent node and this node.
+	label     string
+	children  map[byte]*trieNode
+	positions []int
+}
+
+func (t *pathTrie) insert(prefix string, position int) {
+	node := &t.root
+
+	for {
+		if prefix == "" {
+			node.positions = append(node.positions, position)
+			return
+		}
+
+		child, ok := node.children[prefix[0]]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[byte]*trieNode)
+			}
+
+			node.children[prefix[0]] = &trieNode{label: prefix, positions: []int{position}}
+			return
+		}
+
+		common := commonPrefixLength(child.label, prefix)
+		if common < len(child.label) {
+			// The child is split at the end of the common part.
+			split := &trieNode{
+				label:    child.label[:common],
+				children: map[byte]*trieNode{child.label[common]: child},
+			}
+			child.label = child.label[common:]
+			node.children[prefix[0]] = split
+			child = split
+		}
+
+		prefix = prefix[common:]
+		node = child
+	}
+}
+
+// collect appends the positions of the prefixes of the path.
+func (t *pathTrie) collect(path string, positions []int) []int {
+	node := &t.root
+	positions = append(positions, node.positions...)
+
+	for path != "" {
+		child, ok := node.children[path[0]]
+		if !ok || !strings.HasPrefix(path, child.label) {
+			return positions
+		}
+
+		path = path[len(child.label):]
+		node = child
+		positions = append(positions, node.positions...)
+	}
+
+	return positions
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
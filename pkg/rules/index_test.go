@@ -0,0 +1,236 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func Test_keysOf(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected routeKeys
+	}{
+		{
+			desc:     "Host",
+			rule:     "Host(`Foo.com`, `bar.com.`)",
+			expected: routeKeys{hosts: []string{"foo.com", "bar.com.", "bar.com"}},
+		},
+		{
+			desc:     "PathPrefix with a variable",
+			rule:     "PathPrefix(`/api/{version}`)",
+			expected: routeKeys{prefixes: []string{"/api/"}},
+		},
+		{
+			desc:     "Host and Path",
+			rule:     "Host(`foo.com`) && Path(`/foo`)",
+			expected: routeKeys{hosts: []string{"foo.com"}, prefixes: []string{"/foo"}},
+		},
+		{
+			desc:     "Host or Path",
+			rule:     "Host(`foo.com`) || Path(`/foo`)",
+			expected: routeKeys{},
+		},
+		{
+			desc:     "Host or Host",
+			rule:     "Host(`foo.com`) || (Host(`bar.com`) && Method(`GET`))",
+			expected: routeKeys{hosts: []string{"foo.com", "bar.com"}},
+		},
+		{
+			desc:     "HostRegexp",
+			rule:     "HostRegexp(`{sub:[a-z]+}.foo.com`)",
+			expected: routeKeys{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			parser, err := newParser()
+			require.NoError(t, err)
+
+			parse, err := parser.Parse(test.rule)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, keysOf(parse.(treeBuilder)()))
+		})
+	}
+}
+
+func Test_pathTrie(t *testing.T) {
+	var trie pathTrie
+	trie.insert("/api", 0)
+	trie.insert("/app", 1)
+	trie.insert("/", 2)
+	trie.insert("/api/v1", 3)
+	trie.insert("", 4)
+
+	assert.Equal(t, []int{4, 2, 0, 3}, trie.collect("/api/v1/users", nil))
+	assert.Equal(t, []int{4, 2, 1}, trie.collect("/app", nil))
+	assert.Equal(t, []int{4, 2}, trie.collect("/ap", nil))
+	assert.Equal(t, []int{4}, trie.collect("foo", nil))
+}
+
+func TestRouter_index(t *testing.T) {
+	routes := []struct {
+		rule     string
+		priority int
+	}{
+		{rule: "Host(`foo.com`)", priority: 10},
+		{rule: "Host(`foo.com`) && PathPrefix(`/api`)", priority: 20},
+		{rule: "Host(`foo.com`) && PathPrefix(`/api`) && Method(`POST`)", priority: 30},
+		{rule: "Host(`bar.com`) || Host(`baz.com`)", priority: 10},
+		{rule: "PathPrefix(`/.well-known/`)", priority: 100},
+		{rule: "HostRegexp(`{sub:[a-z]+}.bar.com`)", priority: 5},
+		{rule: "PathPrefix(`/`)", priority: 1},
+		{rule: "Host(`Mixed.COM`)", priority: 10},
+	}
+
+	testCases := []struct {
+		desc           string
+		method         string
+		url            string
+		expectedRoute  string
+		expectedStatus int
+	}{
+		{
+			desc:           "host",
+			url:            "http://foo.com/",
+			expectedRoute:  "0",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "host and path prefix",
+			url:            "http://foo.com/api/users",
+			expectedRoute:  "1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "host, path prefix and method",
+			method:         http.MethodPost,
+			url:            "http://foo.com/api/users",
+			expectedRoute:  "2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "host with a trailing period",
+			url:            "http://foo.com./",
+			expectedRoute:  "0",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "one of the hosts",
+			url:            "http://baz.com/",
+			expectedRoute:  "3",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "higher priority without host",
+			url:            "http://foo.com/.well-known/acme",
+			expectedRoute:  "4",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "host regexp",
+			url:            "http://www.bar.com/",
+			expectedRoute:  "5",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "mixed-case host",
+			url:            "http://MIXED.com/",
+			expectedRoute:  "7",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "unknown host",
+			url:            "http://unknown.com/",
+			expectedRoute:  "6",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	for i, route := range routes {
+		name := string(rune('0' + i))
+		err := router.AddRoute(route.rule, route.priority, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.Header().Set("X-Route", name)
+		}))
+		require.NoError(t, err, route.rule)
+	}
+
+	router.SortRoutes()
+
+	// RequestDecorator is necessary for the host rule
+	reqHost := requestdecorator.New(nil)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			recorder := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(method, test.url, nil)
+			reqHost.ServeHTTP(recorder, req, router.ServeHTTP)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedRoute, recorder.Header().Get("X-Route"))
+		})
+	}
+}
+
+func TestRouteIndex_match_mixedCaseHost(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	err = router.AddRoute("Host(`Foo.COM`)", 0, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {}))
+	require.NoError(t, err)
+
+	router.SortRoutes()
+
+	// The host given to the index is not canonized, unlike the one the Host matcher reads from the context.
+	var matched bool
+	next := func(_ http.ResponseWriter, req *http.Request) {
+		var match mux.RouteMatch
+		matched = router.index.match(req, "FOO.com", &match)
+	}
+
+	requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), testhelpers.MustNewRequest(http.MethodGet, "http://FOO.com/", nil), next)
+	assert.True(t, matched)
+}
+
+func TestRouter_index_notFound(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	err = router.AddRoute("Host(`foo.com`) && Method(`POST`)", 0, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {}))
+	require.NoError(t, err)
+
+	router.SortRoutes()
+
+	reqHost := requestdecorator.New(nil)
+
+	recorder := httptest.NewRecorder()
+	reqHost.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://foo.com/", nil), router.ServeHTTP)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	reqHost.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://bar.com/", nil), router.ServeHTTP)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -50,6 +50,27 @@ func ParseHostSNI(rule string) ([]string, error) {
 	return lower(parseDomain(buildTree())), nil
 }
 
+// ParseDataPrefixes extracts the prefixes of the first bytes of the connections declared in a rule.
+// This is used in TCP routing, for the non-TLS connections.
+func ParseDataPrefixes(rule string) ([]string, error) {
+	parser, err := newTCPParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, errors.New("cannot parse")
+	}
+
+	return parseDataPrefix(buildTree()), nil
+}
+
 func lower(slice []string) []string {
 	var lowerStrings []string
 	for _, value := range slice {
@@ -69,6 +90,17 @@ func parseDomain(tree *tree) []string {
 	}
 }
 
+func parseDataPrefix(tree *tree) []string {
+	switch tree.matcher {
+	case "or":
+		return append(parseDataPrefix(tree.ruleLeft), parseDataPrefix(tree.ruleRight)...)
+	case "DataPrefix":
+		return tree.value
+	default:
+		return nil
+	}
+}
+
 func andFunc(left, right treeBuilder) treeBuilder {
 	return func() *tree {
 		return &tree{
@@ -121,19 +153,21 @@ func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
 	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
+	for _, matcherName := range []string{"HostSNI", "DataPrefix"} {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
 			}
 		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/vulcand/predicate"
 )
@@ -27,6 +28,10 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 type Router struct {
 	*mux.Router
 	parser predicate.Parser
+
+	// keys are the keys of the valid routes, used to index them.
+	keys  map[*mux.Route]routeKeys
+	index *routeIndex
 }
 
 // NewRouter returns a new router instance.
@@ -39,6 +44,7 @@ func NewRouter() (*Router, error) {
 	return &Router{
 		Router: mux.NewRouter().SkipClean(true),
 		parser: parser,
+		keys:   make(map[*mux.Route]routeKeys),
 	}, nil
 }
 
@@ -60,15 +66,85 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 
 	route := r.NewRoute().Handler(handler).Priority(priority)
 
-	err = addRuleOnRoute(route, buildTree())
+	// The routes are indexed again once sorted.
+	r.index = nil
+
+	ruleTree := buildTree()
+
+	err = addRuleOnRoute(route, ruleTree)
 	if err != nil {
 		route.BuildOnly()
 		return err
 	}
 
+	r.keys[route] = keysOf(ruleTree)
+
 	return nil
 }
 
+// SortRoutes sorts the routes by priority, and indexes them by host and path prefix.
+func (r *Router) SortRoutes() {
+	r.Router.SortRoutes()
+
+	var routes []*mux.Route
+	_ = r.Router.Walk(func(route *mux.Route, _ *mux.Router, ancestors []*mux.Route) error {
+		if len(ancestors) == 0 {
+			routes = append(routes, route)
+		}
+		return nil
+	})
+
+	r.index = newRouteIndex(routes, r.keys)
+}
+
+// ServeHTTP dispatches the request to the handler of the first matching route.
+// Once the routes are sorted, only the routes indexed under the host and the path of the request are evaluated,
+// so a method mismatch is only reported for the routes which could match the host and the path.
+func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// Without host, the evaluation of the Host matchers is kept for its warnings.
+	host := requestdecorator.GetCanonizedHost(req.Context())
+	if r.index == nil || len(host) == 0 {
+		// The route is matched beforehand for its path template only, as the mux router does not expose it.
+		var match mux.RouteMatch
+		if r.Router.Match(req, &match) {
+			req = withPathTemplate(req, match.Route)
+		}
+
+		r.Router.ServeHTTP(rw, req)
+		return
+	}
+
+	// Unlike the mux router, the route variables are not set in the request,
+	// as none of the route handlers uses them.
+	var match mux.RouteMatch
+	if r.index.match(req, host, &match) && match.Handler != nil {
+		match.Handler.ServeHTTP(rw, withPathTemplate(req, match.Route))
+		return
+	}
+
+	if match.MatchErr == mux.ErrMethodMismatch {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	http.NotFound(rw, req)
+}
+
+// withPathTemplate returns the request with the path template of the matched route in its context,
+// unless the route has no Path or PathPrefix matcher, or a path template was already matched.
+func withPathTemplate(req *http.Request, route *mux.Route) *http.Request {
+	if route == nil || middlewares.GetPathTemplate(req.Context()) != "" {
+		return req
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return req
+	}
+
+	return req.WithContext(middlewares.WithPathTemplate(req.Context(), template))
+}
+
 type tree struct {
 	matcher   string
 	value     []string
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -462,6 +463,68 @@ func Test_addRoute(t *testing.T) {
 	}
 }
 
+func TestRouter_pathTemplate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		url      string
+		expected string
+	}{
+		{
+			desc:     "Path",
+			rule:     "Path(`/users/{id:[0-9]+}`)",
+			url:      "http://localhost/users/42",
+			expected: "/users/{id}",
+		},
+		{
+			desc:     "Host and PathPrefix",
+			rule:     "Host(`localhost`) && PathPrefix(`/api`)",
+			url:      "http://localhost/api/users/42",
+			expected: "/api",
+		},
+		{
+			desc:     "Path alternatives",
+			rule:     "Path(`/users`) || Path(`/users/{id}`)",
+			url:      "http://localhost/users/42",
+			expected: "/users/{id}",
+		},
+		{
+			desc: "Host",
+			rule: "Host(`localhost`)",
+			url:  "http://localhost/users/42",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for _, sorted := range []bool{false, true} {
+				var template string
+				handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					template = middlewares.GetPathTemplate(req.Context())
+				})
+
+				router, err := NewRouter()
+				require.NoError(t, err)
+
+				err = router.AddRoute(test.rule, 0, handler)
+				require.NoError(t, err)
+
+				if sorted {
+					router.SortRoutes()
+				}
+
+				req := testhelpers.MustNewRequest(http.MethodGet, test.url, nil)
+				requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, router.ServeHTTP)
+
+				assert.Equal(t, test.expected, template)
+			}
+		})
+	}
+}
+
 func Test_addRoutePriority(t *testing.T) {
 	type Case struct {
 		xFrom    string
@@ -752,3 +815,48 @@ func TestParseDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDataPrefixes(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		expected   []string
+	}{
+		{
+			desc:       "data prefixes",
+			expression: "DataPrefix(`SSH-`, `MyProto`)",
+			expected:   []string{"SSH-", "MyProto"},
+		},
+		{
+			desc:       "data prefixes are case sensitive",
+			expression: "dataprefix(`SSH-`) || DataPrefix(`ssh-`)",
+			expected:   []string{"SSH-", "ssh-"},
+		},
+		{
+			desc:       "escaped data prefix",
+			expression: `DataPrefix("\x00\x01")`,
+			expected:   []string{"\x00\x01"},
+		},
+		{
+			desc:       "data prefix and host SNI",
+			expression: "HostSNI(`*`) || DataPrefix(`SSH-`)",
+			expected:   []string{"SSH-"},
+		},
+		{
+			desc:       "no data prefix",
+			expression: "HostSNI(`foo.bar`)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			prefixes, err := ParseDataPrefixes(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, prefixes)
+		})
+	}
+}
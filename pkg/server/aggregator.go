@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -18,8 +20,9 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			ServersTransports: make(map[string]*dynamic.ServersTransport),
 		},
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  make(map[string]*dynamic.TCPRouter),
-			Services: make(map[string]*dynamic.TCPService),
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
 		},
 		UDP: &dynamic.UDPConfiguration{
 			Routers:  make(map[string]*dynamic.UDPRouter),
@@ -63,9 +66,18 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			for routerName, router := range configuration.TCP.Routers {
 				conf.TCP.Routers[provider.MakeQualifiedName(pvd, routerName)] = router
 			}
+			for middlewareName, middleware := range configuration.TCP.Middlewares {
+				conf.TCP.Middlewares[provider.MakeQualifiedName(pvd, middlewareName)] = middleware
+			}
 			for serviceName, service := range configuration.TCP.Services {
 				conf.TCP.Services[provider.MakeQualifiedName(pvd, serviceName)] = service
 			}
+			for setName, set := range configuration.TCP.CIDRSets {
+				if conf.TCP.CIDRSets == nil {
+					conf.TCP.CIDRSets = make(map[string]*dynamic.CIDRSet)
+				}
+				conf.TCP.CIDRSets[provider.MakeQualifiedName(pvd, setName)] = set
+			}
 		}
 
 		if configuration.UDP != nil {
@@ -148,7 +160,11 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 					cp.TLS = m.TLS
 				}
 
-				cp.Middlewares = append(m.Middlewares, cp.Middlewares...)
+				if cp.AccessLog == nil {
+					cp.AccessLog = m.AccessLog
+				}
+
+				cp.Middlewares = applyModelMiddlewares(m.Middlewares, cp.Middlewares, cp.ModelMiddlewares)
 
 				rtName := name
 				if len(eps) > 1 {
@@ -168,6 +184,54 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	return cfg
 }
 
+// applyCIDRSets adds the IP ranges of the CIDR sets referenced by the TCP IPWhiteList middlewares to their source range,
+// so that the middlewares are built again when the sets are updated.
+// The references to unknown sets are kept, for the middlewares to report them.
+func applyCIDRSets(cfg dynamic.Configuration) dynamic.Configuration {
+	if cfg.TCP == nil {
+		return cfg
+	}
+
+	for name, middleware := range cfg.TCP.Middlewares {
+		if middleware == nil || middleware.IPWhiteList == nil || len(middleware.IPWhiteList.SourceRangeSets) == 0 {
+			continue
+		}
+
+		ctx := provider.AddInContext(context.Background(), name)
+
+		mid := middleware.DeepCopy()
+
+		var unknown []string
+		for _, setName := range mid.IPWhiteList.SourceRangeSets {
+			set, ok := cfg.TCP.CIDRSets[provider.GetQualifiedName(ctx, setName)]
+			if !ok || set == nil {
+				unknown = append(unknown, setName)
+				continue
+			}
+
+			mid.IPWhiteList.SourceRange = append(mid.IPWhiteList.SourceRange, set.SourceRange...)
+		}
+
+		mid.IPWhiteList.SourceRangeSets = unknown
+		cfg.TCP.Middlewares[name] = mid
+	}
+
+	return cfg
+}
+
+// applyModelMiddlewares returns the middlewares of a router, with the ones of the model placed as configured on the router.
+// An unknown placement, reported by the router manager, falls back to the default one.
+func applyModelMiddlewares(modelMiddlewares, routerMiddlewares []string, placement string) []string {
+	switch placement {
+	case dynamic.ModelMiddlewaresNone:
+		return routerMiddlewares
+	case dynamic.ModelMiddlewaresAfter:
+		return append(append([]string(nil), routerMiddlewares...), modelMiddlewares...)
+	default:
+		return append(append([]string(nil), modelMiddlewares...), routerMiddlewares...)
+	}
+}
+
 func containsACMETLS1(stores []string) bool {
 	for _, store := range stores {
 		if store == tlsalpn01.ACMETLS1Protocol {
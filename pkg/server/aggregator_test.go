@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 func Test_mergeConfiguration(t *testing.T) {
@@ -587,6 +588,50 @@ func Test_applyModel(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with model, one entry point, and router with access log",
+			input: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints: []string{"websecure"},
+							AccessLog:   &types.RouterAccessLog{Sampling: &types.AccessLogSampling{Rate: 0.5}},
+						},
+						"test2": {
+							EntryPoints: []string{"websecure"},
+						},
+					},
+					Middlewares: make(map[string]*dynamic.Middleware),
+					Services:    make(map[string]*dynamic.Service),
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							AccessLog: &types.RouterAccessLog{Sampling: &types.AccessLogSampling{Rate: 0.1}},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints: []string{"websecure"},
+							AccessLog:   &types.RouterAccessLog{Sampling: &types.AccessLogSampling{Rate: 0.5}},
+						},
+						"test2": {
+							EntryPoints: []string{"websecure"},
+							AccessLog:   &types.RouterAccessLog{Sampling: &types.AccessLogSampling{Rate: 0.1}},
+						},
+					},
+					Middlewares: make(map[string]*dynamic.Middleware),
+					Services:    make(map[string]*dynamic.Service),
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							AccessLog: &types.RouterAccessLog{Sampling: &types.AccessLogSampling{Rate: 0.1}},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "with model, two entry points",
 			input: dynamic.Configuration{
@@ -629,6 +674,111 @@ func Test_applyModel(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with model, model middlewares before the router ones",
+			input: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"auth"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresBefore,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"test", "auth"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresBefore,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "with model, model middlewares after the router ones",
+			input: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"auth"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresAfter,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"auth", "test"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresAfter,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "with model, without model middlewares",
+			input: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"auth"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresNone,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"test": {
+							EntryPoints:      []string{"websecure"},
+							Middlewares:      []string{"auth"},
+							ModelMiddlewares: dynamic.ModelMiddlewaresNone,
+						},
+					},
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							Middlewares: []string{"test"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -642,3 +792,39 @@ func Test_applyModel(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyCIDRSets(t *testing.T) {
+	input := dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Middlewares: map[string]*dynamic.TCPMiddleware{
+				"allow@file": {
+					IPWhiteList: &dynamic.TCPIPWhiteList{
+						SourceRange:     []string{"10.0.0.0/8"},
+						SourceRangeSets: []string{"offices", "partners@kubernetescrd", "unknown"},
+					},
+				},
+				"sni@file": {
+					SNIWhiteList: &dynamic.SNIWhiteList{ServerNames: []string{"example.com"}},
+				},
+			},
+			CIDRSets: map[string]*dynamic.CIDRSet{
+				"offices@file":           {SourceRange: []string{"192.168.1.0/24"}},
+				"partners@kubernetescrd": {SourceRange: []string{"203.0.113.0/24", "198.51.100.1"}},
+			},
+		},
+	}
+
+	middleware := input.TCP.Middlewares["allow@file"]
+	original := middleware.DeepCopy()
+
+	actual := applyCIDRSets(input)
+
+	assert.Equal(t, &dynamic.TCPIPWhiteList{
+		SourceRange:     []string{"10.0.0.0/8", "192.168.1.0/24", "203.0.113.0/24", "198.51.100.1"},
+		SourceRangeSets: []string{"unknown"},
+	}, actual.TCP.Middlewares["allow@file"].IPWhiteList)
+	assert.Equal(t, &dynamic.SNIWhiteList{ServerNames: []string{"example.com"}}, actual.TCP.Middlewares["sni@file"].SNIWhiteList)
+
+	// The middleware of the provider configuration is left unchanged.
+	assert.Equal(t, original, middleware)
+}
@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Entry is the record of a dynamic configuration applied for a provider.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	// Hash is the SHA-256 hash of the JSON representation of the configuration of the provider.
+	Hash string `json:"hash"`
+}
+
+// Log is a bounded audit log of the applied dynamic configurations,
+// stored on disk as JSON lines, the oldest entries being dropped once the maximum number of entries is reached.
+type Log struct {
+	path       string
+	maxEntries int
+
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// New creates a new Log stored in the given file, and loads its existing entries.
+func New(path string, maxEntries int) (*Log, error) {
+	if maxEntries <= 0 {
+		return nil, errors.New("the maximum number of entries must be positive")
+	}
+
+	entries, err := readEntries(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the audit log %s: %w", path, err)
+	}
+
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+
+	return &Log{path: path, maxEntries: maxEntries, entries: entries}, nil
+}
+
+// Record records the configuration applied for the provider.
+func (l *Log) Record(providerName string, conf *dynamic.Configuration) error {
+	hash, err := Hash(conf)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, Entry{Time: time.Now().UTC(), Provider: providerName, Hash: hash})
+	if len(l.entries) > l.maxEntries {
+		l.entries = append([]Entry(nil), l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+
+	return l.write()
+}
+
+// Entries returns the recorded entries, from the oldest to the latest.
+func (l *Log) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]Entry(nil), l.entries...)
+}
+
+// write replaces the file with the entries, through a temporary file renamed once written,
+// so that the file is never left truncated.
+func (l *Log) write() error {
+	tmp, err := ioutil.TempFile(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to write the audit log: %w", err)
+	}
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range l.entries {
+		if err = encoder.Encode(entry); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), l.path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("unable to write the audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Hash returns the SHA-256 hash of the JSON representation of the configuration.
+// The maps being encoded with sorted keys, equal configurations have the same hash.
+func Hash(conf *dynamic.Configuration) (string, error) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash the configuration: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func readEntries(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []Entry
+
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var entry Entry
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestLog_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	auditLog, err := New(path, 2)
+	require.NoError(t, err)
+	assert.Empty(t, auditLog.Entries())
+
+	confFoo := &dynamic.Configuration{HTTP: &dynamic.HTTPConfiguration{
+		Routers: map[string]*dynamic.Router{"foo": {Rule: "Host(`foo.com`)", Service: "foo"}},
+	}}
+	confBar := &dynamic.Configuration{HTTP: &dynamic.HTTPConfiguration{
+		Routers: map[string]*dynamic.Router{"bar": {Rule: "Host(`bar.com`)", Service: "bar"}},
+	}}
+
+	require.NoError(t, auditLog.Record("file", confFoo))
+	require.NoError(t, auditLog.Record("docker", confBar))
+	require.NoError(t, auditLog.Record("file", confBar))
+
+	entries := auditLog.Entries()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "docker", entries[0].Provider)
+	assert.Equal(t, "file", entries[1].Provider)
+	assert.Equal(t, entries[0].Hash, entries[1].Hash)
+	assert.False(t, entries[1].Time.Before(entries[0].Time))
+
+	hashFoo, err := Hash(confFoo)
+	require.NoError(t, err)
+	assert.NotEqual(t, hashFoo, entries[0].Hash)
+	assert.Len(t, hashFoo, 64)
+
+	// The entries are loaded back from the file, and trimmed to the maximum number of entries.
+	reloaded, err := New(path, 1)
+	require.NoError(t, err)
+	assert.Equal(t, entries[1:], reloaded.Entries())
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "audit.log"), 0)
+	assert.Error(t, err)
+
+	_, err = New(t.TempDir(), 10)
+	assert.Error(t, err)
+}
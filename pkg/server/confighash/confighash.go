@@ -0,0 +1,90 @@
+package confighash
+
+import (
+	"fmt"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/mitchellh/hashstructure"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// mask keeps the bits of the hash which a float64 represents exactly,
+// so that the value of the metric is the hash itself.
+const mask = 1<<53 - 1
+
+// Hash returns the hash of a dynamic configuration.
+// It is stable across the Traefik instances of a same version, and does not depend on the order of the map entries.
+func Hash(conf dynamic.Configuration) (uint64, error) {
+	hash, err := hashstructure.Hash(conf, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return hash & mask, nil
+}
+
+// Tracker holds the hash of the applied dynamic configuration.
+type Tracker struct {
+	gauge gokitmetrics.Gauge
+
+	mu         sync.RWMutex
+	hash       uint64
+	generation uint64
+	applied    bool
+}
+
+// NewTracker creates a tracker, reporting the hash of the applied configuration to the gauge.
+func NewTracker(gauge gokitmetrics.Gauge) *Tracker {
+	return &Tracker{gauge: gauge}
+}
+
+// Update records the configuration about to be applied, with the generation of the resolved secrets it is built with,
+// and reports whether it differs from the last applied configuration, and thus has to be applied.
+// A configuration which cannot be hashed is always applied.
+func (t *Tracker) Update(conf dynamic.Configuration, generation uint64) (bool, error) {
+	if t == nil {
+		return true, nil
+	}
+
+	hash, err := Hash(conf)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.applied = false
+		return true, fmt.Errorf("unable to hash the configuration: %w", err)
+	}
+
+	if t.applied && t.hash == hash && t.generation == generation {
+		return false, nil
+	}
+
+	t.hash = hash
+	t.generation = generation
+	t.applied = true
+
+	if t.gauge != nil {
+		t.gauge.Set(float64(hash))
+	}
+
+	return true, nil
+}
+
+// Get returns the hexadecimal hash of the applied configuration,
+// or an empty string when no configuration has been applied yet.
+func (t *Tracker) Get() string {
+	if t == nil {
+		return ""
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.applied {
+		return ""
+	}
+
+	return fmt.Sprintf("%014x", t.hash)
+}
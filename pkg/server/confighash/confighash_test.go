@@ -0,0 +1,88 @@
+package confighash
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestHash_stable(t *testing.T) {
+	newConf := func() dynamic.Configuration {
+		return dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"foo": {Rule: "Host(`foo`)", Service: "foo", Middlewares: []string{"a", "b"}},
+					"bar": {Rule: "Host(`bar`)", Service: "bar"},
+					"baz": {Rule: "Host(`baz`)", Service: "baz"},
+				},
+			},
+		}
+	}
+
+	expected, err := Hash(newConf())
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		hash, err := Hash(newConf())
+		require.NoError(t, err)
+		assert.Equal(t, expected, hash)
+	}
+
+	changed := newConf()
+	changed.HTTP.Routers["foo"].Middlewares = []string{"b", "a"}
+
+	hash, err := Hash(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected, hash)
+}
+
+func TestTracker_Update(t *testing.T) {
+	gauge := generic.NewGauge("config_hash")
+	tracker := NewTracker(gauge)
+
+	assert.Empty(t, tracker.Get())
+
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{"foo": {Rule: "Host(`foo`)", Service: "foo"}},
+		},
+	}
+
+	changed, err := tracker.Update(conf, 0)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	hash, err := Hash(conf)
+	require.NoError(t, err)
+	assert.Equal(t, float64(hash), gauge.Value())
+	assert.Len(t, tracker.Get(), 14)
+
+	// The same configuration, sent again.
+	changed, err = tracker.Update(*conf.DeepCopy(), 0)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// The same configuration, with rotated secrets.
+	changed, err = tracker.Update(conf, 1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	conf.HTTP.Routers["foo"].Priority = 42
+
+	changed, err = tracker.Update(conf, 1)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotEqual(t, float64(hash), gauge.Value())
+}
+
+func TestTracker_nil(t *testing.T) {
+	var tracker *Tracker
+
+	changed, err := tracker.Update(dynamic.Configuration{}, 0)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Empty(t, tracker.Get())
+}
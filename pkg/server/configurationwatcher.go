@@ -12,6 +12,12 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+	"github.com/traefik/traefik/v2/pkg/server/confighash"
+	"github.com/traefik/traefik/v2/pkg/server/freshness"
+	"github.com/traefik/traefik/v2/pkg/server/override"
+	"github.com/traefik/traefik/v2/pkg/server/secret"
+	"github.com/traefik/traefik/v2/pkg/server/tenancy"
 )
 
 // ConfigurationWatcher watches configuration changes.
@@ -28,7 +34,25 @@ type ConfigurationWatcher struct {
 	configurationValidatedChan chan dynamic.Message
 	providerConfigUpdateMap    map[string]chan dynamic.Message
 
+	overrides *override.Store
+	// reloadChan requests a reload of the current configurations, when the overrides or the secrets change.
+	reloadChan chan struct{}
+	// pausedConfigurations are the configurations of the paused providers when they were paused.
+	pausedConfigurations map[string]*dynamic.Configuration
+
+	secrets *secret.Resolver
+
+	// hashes skips the configurations identical to the applied one.
+	hashes *confighash.Tracker
+
+	auditLog *audit.Log
+
+	freshness *freshness.Watchdog
+
+	tenants *tenancy.Enforcer
+
 	configurationListeners []func(dynamic.Configuration)
+	providerListeners      []func(providerName string)
 
 	routinesPool *safe.Pool
 }
@@ -45,6 +69,8 @@ func NewConfigurationWatcher(
 		configurationChan:          make(chan dynamic.Message, 100),
 		configurationValidatedChan: make(chan dynamic.Message, 100),
 		providerConfigUpdateMap:    make(map[string]chan dynamic.Message),
+		reloadChan:                 make(chan struct{}, 1),
+		pausedConfigurations:       make(map[string]*dynamic.Configuration),
 		providersThrottleDuration:  providersThrottleDuration,
 		routinesPool:               routinesPool,
 		defaultEntryPoints:         defaultEntryPoints,
@@ -77,6 +103,58 @@ func (c *ConfigurationWatcher) AddListener(listener func(dynamic.Configuration))
 	c.configurationListeners = append(c.configurationListeners, listener)
 }
 
+// AddProviderListener adds a new listener function used when a non-empty configuration of a provider has been loaded.
+func (c *ConfigurationWatcher) AddProviderListener(listener func(providerName string)) {
+	c.providerListeners = append(c.providerListeners, listener)
+}
+
+// SetOverrides sets the runtime overrides layered on top of the configuration of the providers.
+// The configuration is reloaded each time the overrides change.
+func (c *ConfigurationWatcher) SetOverrides(overrides *override.Store) {
+	c.overrides = overrides
+
+	overrides.AddListener(c.requestReload)
+}
+
+// SetSecrets sets the resolver of the secret references of the middleware options.
+// The configuration is reloaded each time the value of a resolved secret changes.
+func (c *ConfigurationWatcher) SetSecrets(secrets *secret.Resolver) {
+	c.secrets = secrets
+
+	secrets.AddListener(c.requestReload)
+}
+
+// SetHashTracker sets the tracker of the hash of the applied configuration.
+// The merged configurations identical to the applied one are then not passed to the listeners,
+// unless the value of a resolved secret changed in the meantime.
+func (c *ConfigurationWatcher) SetHashTracker(hashes *confighash.Tracker) {
+	c.hashes = hashes
+}
+
+// requestReload requests a reload of the current configurations.
+func (c *ConfigurationWatcher) requestReload() {
+	// Pending reloads are coalesced, as they all apply the latest overrides and secrets.
+	select {
+	case c.reloadChan <- struct{}{}:
+	default:
+	}
+}
+
+// SetAuditLog sets the audit log into which the configurations applied for the providers are recorded.
+func (c *ConfigurationWatcher) SetAuditLog(auditLog *audit.Log) {
+	c.auditLog = auditLog
+}
+
+// SetFreshnessWatchdog sets the watchdog into which the configuration messages received from the providers are recorded.
+func (c *ConfigurationWatcher) SetFreshnessWatchdog(watchdog *freshness.Watchdog) {
+	c.freshness = watchdog
+}
+
+// SetTenants sets the enforcer of the entry points and the quotas of the tenants, applied to the configurations of their providers before they are merged.
+func (c *ConfigurationWatcher) SetTenants(tenants *tenancy.Enforcer) {
+	c.tenants = tenants
+}
+
 func (c *ConfigurationWatcher) startProvider() {
 	logger := log.WithoutContext()
 
@@ -115,6 +193,8 @@ func (c *ConfigurationWatcher) listenProviders(ctx context.Context) {
 				return
 			}
 
+			c.freshness.Touch(configMsg.ProviderName)
+
 			c.preLoadConfiguration(configMsg)
 		}
 	}
@@ -130,6 +210,8 @@ func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
 				return
 			}
 			c.loadMessage(configMsg)
+		case <-c.reloadChan:
+			c.applyConfigurations(c.currentConfigurations.Get().(dynamic.Configurations))
 		}
 	}
 }
@@ -143,14 +225,87 @@ func (c *ConfigurationWatcher) loadMessage(configMsg dynamic.Message) {
 
 	c.currentConfigurations.Set(newConfigurations)
 
-	conf := mergeConfiguration(newConfigurations, c.defaultEntryPoints)
+	c.applyConfigurations(newConfigurations)
+
+	if c.auditLog != nil {
+		if err := c.auditLog.Record(configMsg.ProviderName, configMsg.Configuration); err != nil {
+			log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName).Errorf("Unable to record the configuration in the audit log: %v", err)
+		}
+	}
+
+	c.notifyProviderListeners(configMsg.ProviderName)
+}
+
+// applyConfigurations enforces the tenants on the configurations of the providers, merges them, applies the runtime overrides,
+// and passes the resulting configuration to the listeners.
+func (c *ConfigurationWatcher) applyConfigurations(configurations dynamic.Configurations) {
+	configurations = c.tenants.Apply(c.applyPausedProviders(configurations), c.defaultEntryPoints)
+
+	conf := mergeConfiguration(configurations, c.defaultEntryPoints)
 	conf = applyModel(conf)
+	conf = applyCIDRSets(conf)
+	conf = c.overrides.Apply(conf)
+
+	changed, err := c.hashes.Update(conf, c.secrets.Generation())
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to compare the configuration with the applied one: %v", err)
+	}
+
+	if !changed {
+		log.WithoutContext().Debugf("Skipping the configuration identical to the applied one, with hash %s", c.hashes.Get())
+		return
+	}
 
 	for _, listener := range c.configurationListeners {
 		listener(conf)
 	}
 }
 
+// applyPausedProviders replaces the configurations of the paused providers by the ones they had when they were paused,
+// or removes them when their configuration is dropped.
+// The configurations received from the paused providers are kept, and applied once they are resumed.
+func (c *ConfigurationWatcher) applyPausedProviders(configurations dynamic.Configurations) dynamic.Configurations {
+	paused := c.overrides.PausedProviders()
+
+	for name := range c.pausedConfigurations {
+		if _, ok := paused[name]; !ok {
+			delete(c.pausedConfigurations, name)
+		}
+	}
+
+	if len(paused) == 0 {
+		return configurations
+	}
+
+	result := make(dynamic.Configurations, len(configurations))
+	for name, conf := range configurations {
+		result[name] = conf
+	}
+
+	for name, mode := range paused {
+		conf, ok := c.pausedConfigurations[name]
+		if !ok {
+			conf = configurations[name]
+			c.pausedConfigurations[name] = conf
+		}
+
+		if mode == override.PauseDrop || conf == nil {
+			delete(result, name)
+			continue
+		}
+
+		result[name] = conf
+	}
+
+	return result
+}
+
+func (c *ConfigurationWatcher) notifyProviderListeners(providerName string) {
+	for _, listener := range c.providerListeners {
+		listener(providerName)
+	}
+}
+
 func (c *ConfigurationWatcher) preLoadConfiguration(configMsg dynamic.Message) {
 	logger := log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName)
 	if log.GetLevel() == logrus.DebugLevel {
@@ -247,7 +402,7 @@ func isEmptyConfiguration(conf *dynamic.Configuration) bool {
 
 	httpEmpty := conf.HTTP.Routers == nil && conf.HTTP.Services == nil && conf.HTTP.Middlewares == nil
 	tlsEmpty := conf.TLS == nil || conf.TLS.Certificates == nil && conf.TLS.Stores == nil && conf.TLS.Options == nil
-	tcpEmpty := conf.TCP.Routers == nil && conf.TCP.Services == nil
+	tcpEmpty := conf.TCP.Routers == nil && conf.TCP.Services == nil && conf.TCP.Middlewares == nil
 	udpEmpty := conf.UDP.Routers == nil && conf.UDP.Services == nil
 
 	return httpEmpty && tlsEmpty && tcpEmpty && udpEmpty
@@ -3,13 +3,18 @@ package server
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+	"github.com/traefik/traefik/v2/pkg/server/confighash"
+	"github.com/traefik/traefik/v2/pkg/server/override"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
@@ -70,8 +75,9 @@ func TestNewConfigurationWatcher(t *testing.T) {
 				th.WithLoadBalancerServices(),
 			),
 			TCP: &dynamic.TCPConfiguration{
-				Routers:  map[string]*dynamic.TCPRouter{},
-				Services: map[string]*dynamic.TCPService{},
+				Routers:     map[string]*dynamic.TCPRouter{},
+				Middlewares: map[string]*dynamic.TCPMiddleware{},
+				Services:    map[string]*dynamic.TCPService{},
 			},
 			TLS: &dynamic.TLSConfiguration{
 				Options: map[string]tls.Options{
@@ -147,6 +153,262 @@ func TestListenProvidersSkipsEmptyConfigs(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func TestListenProvidersNotifiesProviderListeners(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{ProviderName: "empty", Configuration: &dynamic.Configuration{}},
+			{
+				ProviderName: "mock",
+				Configuration: &dynamic.Configuration{
+					HTTP: th.BuildConfiguration(
+						th.WithRouters(th.WithRouter("foo")),
+						th.WithLoadBalancerServices(th.WithService("bar")),
+					),
+				},
+			},
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{})
+
+	var published bool
+	watcher.AddListener(func(_ dynamic.Configuration) {
+		published = true
+	})
+
+	providers := make(chan string, 2)
+	watcher.AddProviderListener(func(providerName string) {
+		// The configuration of a provider must be applied before its listeners are notified.
+		assert.True(t, published)
+		providers <- providerName
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	select {
+	case providerName := <-providers:
+		assert.Equal(t, "mock", providerName)
+	case <-time.After(time.Second):
+		t.Fatal("provider mock was not notified")
+	}
+
+	// The empty configuration is skipped, and its provider is not notified.
+	select {
+	case providerName := <-providers:
+		t.Fatalf("unexpected notification for provider %s", providerName)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestListenProvidersRecordsAuditLog(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	conf := &dynamic.Configuration{
+		HTTP: th.BuildConfiguration(
+			th.WithRouters(th.WithRouter("foo")),
+			th.WithLoadBalancerServices(th.WithService("bar")),
+		),
+	}
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{
+			{ProviderName: "empty", Configuration: &dynamic.Configuration{}},
+			{ProviderName: "mock", Configuration: conf},
+		},
+	}
+
+	auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"), 10)
+	require.NoError(t, err)
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{})
+	watcher.SetAuditLog(auditLog)
+
+	providers := make(chan string, 2)
+	watcher.AddProviderListener(func(providerName string) {
+		providers <- providerName
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	select {
+	case <-providers:
+	case <-time.After(time.Second):
+		t.Fatal("provider mock was not notified")
+	}
+
+	expectedHash, err := audit.Hash(conf)
+	require.NoError(t, err)
+
+	// The empty configuration is skipped, and is not recorded.
+	entries := auditLog.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "mock", entries[0].Provider)
+	assert.Equal(t, expectedHash, entries[0].Hash)
+}
+
+func TestListenProvidersAppliesOverrides(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	pvd := &mockProvider{
+		messages: []dynamic.Message{{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter("foo"), th.WithRouter("bar")),
+					th.WithLoadBalancerServices(th.WithService("baz",
+						th.WithServers(th.WithServer("http://127.0.0.1"), th.WithServer("http://127.0.0.2")),
+					)),
+				),
+			},
+		}},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{})
+
+	overrides := override.NewStore()
+	watcher.SetOverrides(overrides)
+
+	published := make(chan dynamic.Configuration, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		published <- conf
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	waitConfiguration := func() dynamic.Configuration {
+		t.Helper()
+
+		select {
+		case conf := <-published:
+			return conf
+		case <-time.After(time.Second):
+			t.Fatal("configuration was not published")
+			return dynamic.Configuration{}
+		}
+	}
+
+	conf := waitConfiguration()
+	assert.Len(t, conf.HTTP.Routers, 2)
+
+	overrides.DisableRouter("foo@mock")
+
+	conf = waitConfiguration()
+	assert.Contains(t, conf.HTTP.Routers, "bar@mock")
+	assert.NotContains(t, conf.HTTP.Routers, "foo@mock")
+
+	overrides.DrainServer("baz@mock", "http://127.0.0.1")
+
+	conf = waitConfiguration()
+	assert.NotContains(t, conf.HTTP.Routers, "foo@mock")
+	assert.Equal(t, []dynamic.Server{{URL: "http://127.0.0.2"}}, conf.HTTP.Services["baz@mock"].LoadBalancer.Servers)
+
+	overrides.Clear()
+
+	conf = waitConfiguration()
+	assert.Len(t, conf.HTTP.Routers, 2)
+	assert.Len(t, conf.HTTP.Services["baz@mock"].LoadBalancer.Servers, 2)
+}
+
+func TestListenProvidersSkipsSameMergedConfiguration(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	pvd := &mockProvider{
+		messages: []dynamic.Message{{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter("foo")),
+					th.WithLoadBalancerServices(th.WithService("bar")),
+				),
+			},
+		}},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{})
+
+	hashes := confighash.NewTracker(nil)
+	watcher.SetHashTracker(hashes)
+
+	overrides := override.NewStore()
+	watcher.SetOverrides(overrides)
+
+	published := make(chan dynamic.Configuration, 10)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		published <- conf
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("configuration was not published")
+	}
+
+	hash := hashes.Get()
+	assert.NotEmpty(t, hash)
+
+	// A reload leading to the same configuration is not published.
+	watcher.requestReload()
+
+	select {
+	case <-published:
+		t.Fatal("same configuration was published")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, hash, hashes.Get())
+
+	overrides.DisableRouter("foo@mock")
+
+	select {
+	case conf := <-published:
+		assert.NotContains(t, conf.HTTP.Routers, "foo@mock")
+	case <-time.After(time.Second):
+		t.Fatal("configuration was not published")
+	}
+
+	assert.NotEqual(t, hash, hashes.Get())
+}
+
+func TestApplyPausedProviders(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, time.Second, []string{})
+
+	overrides := override.NewStore()
+	watcher.SetOverrides(overrides)
+
+	confFoo := &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("foo")))}
+	confBar := &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("bar")))}
+	confBaz := &dynamic.Configuration{HTTP: th.BuildConfiguration(th.WithRouters(th.WithRouter("baz")))}
+
+	configurations := dynamic.Configurations{"file": confFoo, "docker": confBar}
+	assert.Equal(t, configurations, watcher.applyPausedProviders(configurations))
+
+	overrides.PauseProvider("file", override.PauseKeep)
+	overrides.PauseProvider("docker", override.PauseDrop)
+	overrides.PauseProvider("consul", override.PauseKeep)
+
+	assert.Equal(t, dynamic.Configurations{"file": confFoo}, watcher.applyPausedProviders(configurations))
+
+	// The configurations received while the providers are paused are not applied.
+	configurations = dynamic.Configurations{"file": confBaz, "docker": confBaz, "consul": confBaz}
+	assert.Equal(t, dynamic.Configurations{"file": confFoo}, watcher.applyPausedProviders(configurations))
+
+	overrides.ResumeProvider("file")
+	overrides.ResumeProvider("consul")
+
+	assert.Equal(t, dynamic.Configurations{"file": confBaz, "consul": confBaz}, watcher.applyPausedProviders(configurations))
+
+	overrides.Clear()
+
+	assert.Equal(t, configurations, watcher.applyPausedProviders(configurations))
+	assert.Empty(t, watcher.pausedConfigurations)
+}
+
 func TestListenProvidersSkipsSameConfigurationForProvider(t *testing.T) {
 	routinesPool := safe.NewPool(context.Background())
 	message := dynamic.Message{
@@ -225,8 +487,9 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 			th.WithMiddlewares(),
 		),
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  map[string]*dynamic.TCPRouter{},
-			Services: map[string]*dynamic.TCPService{},
+			Routers:     map[string]*dynamic.TCPRouter{},
+			Middlewares: map[string]*dynamic.TCPMiddleware{},
+			Services:    map[string]*dynamic.TCPService{},
 		},
 		UDP: &dynamic.UDPConfiguration{
 			Routers:  map[string]*dynamic.UDPRouter{},
@@ -284,8 +547,9 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 			th.WithMiddlewares(),
 		),
 		TCP: &dynamic.TCPConfiguration{
-			Routers:  map[string]*dynamic.TCPRouter{},
-			Services: map[string]*dynamic.TCPService{},
+			Routers:     map[string]*dynamic.TCPRouter{},
+			Middlewares: map[string]*dynamic.TCPMiddleware{},
+			Services:    map[string]*dynamic.TCPService{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -0,0 +1,94 @@
+package connections
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// The protocols of the connections.
+const (
+	ProtocolHTTP  = "http"
+	ProtocolHTTPS = "https"
+	ProtocolTCP   = "tcp"
+	ProtocolTLS   = "tls"
+)
+
+var (
+	// ErrUnknownEntryPoint is returned when the entry point is not registered.
+	ErrUnknownEntryPoint = errors.New("unknown entry point")
+	// ErrUnknownConnection is returned when the connection is not active on the entry point.
+	ErrUnknownConnection = errors.New("unknown connection")
+)
+
+// Info describes an active connection of an entry point.
+type Info struct {
+	ID         uint64    `json:"id"`
+	ClientAddr string    `json:"clientAddr"`
+	Protocol   string    `json:"protocol,omitempty"`
+	SNI        string    `json:"sni,omitempty"`
+	Router     string    `json:"router,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	Age        string    `json:"age"`
+}
+
+// Table is the table of the active connections of an entry point.
+type Table interface {
+	// Connections returns the active connections, sorted by ID.
+	Connections() []Info
+	// CloseConnection closes the active connection with the given ID.
+	CloseConnection(id uint64) error
+}
+
+// Registry holds the connection tables of the entry points.
+type Registry struct {
+	mu     sync.RWMutex
+	tables map[string]Table
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		tables: make(map[string]Table),
+	}
+}
+
+// Register registers the connection table of an entry point.
+func (r *Registry) Register(entryPointName string, table Table) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tables[entryPointName] = table
+}
+
+// Connections returns the active connections of an entry point.
+func (r *Registry) Connections(entryPointName string) ([]Info, error) {
+	table, err := r.table(entryPointName)
+	if err != nil {
+		return nil, err
+	}
+
+	return table.Connections(), nil
+}
+
+// Close closes the active connection of an entry point with the given ID.
+func (r *Registry) Close(entryPointName string, id uint64) error {
+	table, err := r.table(entryPointName)
+	if err != nil {
+		return err
+	}
+
+	return table.CloseConnection(id)
+}
+
+func (r *Registry) table(entryPointName string) (Table, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	table, ok := r.tables[entryPointName]
+	if !ok {
+		return nil, ErrUnknownEntryPoint
+	}
+
+	return table, nil
+}
@@ -0,0 +1,192 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// The states of the entry points.
+const (
+	StateServing  = "serving"
+	StateDraining = "draining"
+	StateDrained  = "drained"
+)
+
+// ErrUnknownEntryPoint is returned when draining an entry point which is not registered.
+var ErrUnknownEntryPoint = errors.New("unknown entry point")
+
+// Target is an entry point which can be drained.
+type Target interface {
+	// Drain stops accepting new connections, and waits for the active ones to finish until the context is done,
+	// when they are closed.
+	Drain(ctx context.Context)
+	// ActiveConnections returns the number of active connections, including the upgraded ones such as WebSockets.
+	ActiveConnections() int
+}
+
+// EntryPointStatus is the drain progress of an entry point.
+type EntryPointStatus struct {
+	EntryPoint        string     `json:"entryPoint"`
+	State             string     `json:"state"`
+	ActiveConnections int        `json:"activeConnections"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	Deadline          *time.Time `json:"deadline,omitempty"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty"`
+}
+
+type progress struct {
+	startedAt   time.Time
+	deadline    time.Time
+	completedAt time.Time
+}
+
+// Manager drains the registered entry points, and reports their drain progress.
+// A drained entry point serves again only once Traefik restarts.
+type Manager struct {
+	timeout            time.Duration
+	defaultEntryPoints []string
+
+	mu        sync.RWMutex
+	targets   map[string]Target
+	drains    map[string]*progress
+	listeners []func()
+}
+
+// NewManager creates a new Manager, giving the timeout to the active connections to finish.
+// The default entry points are drained when no entry point is given.
+func NewManager(timeout time.Duration, defaultEntryPoints []string) *Manager {
+	return &Manager{
+		timeout:            timeout,
+		defaultEntryPoints: defaultEntryPoints,
+		targets:            make(map[string]Target),
+		drains:             make(map[string]*progress),
+	}
+}
+
+// Register registers an entry point which can be drained.
+func (m *Manager) Register(entryPointName string, target Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.targets[entryPointName] = target
+}
+
+// AddListener adds a listener function called once, when the first drain starts.
+func (m *Manager) AddListener(listener func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.listeners = append(m.listeners, listener)
+}
+
+// Draining returns whether a drain has started.
+func (m *Manager) Draining() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.drains) > 0
+}
+
+// Drain starts draining the given entry points, or the default ones when none is given.
+// The entry points already draining are left untouched.
+func (m *Manager) Drain(entryPointNames ...string) error {
+	m.mu.Lock()
+
+	if len(entryPointNames) == 0 {
+		entryPointNames = m.defaultEntryPoints
+	}
+
+	for _, name := range entryPointNames {
+		if _, ok := m.targets[name]; !ok {
+			m.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrUnknownEntryPoint, name)
+		}
+	}
+
+	var listeners []func()
+	if len(m.drains) == 0 {
+		listeners = m.listeners
+	}
+
+	now := time.Now()
+	for _, name := range entryPointNames {
+		if _, ok := m.drains[name]; ok {
+			continue
+		}
+
+		p := &progress{startedAt: now, deadline: now.Add(m.timeout)}
+		m.drains[name] = p
+
+		m.start(name, m.targets[name], p)
+	}
+
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+
+	return nil
+}
+
+// Status returns the drain progress of the registered entry points, sorted by name.
+func (m *Manager) Status() []EntryPointStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]EntryPointStatus, 0, len(m.targets))
+	for name, target := range m.targets {
+		status := EntryPointStatus{
+			EntryPoint:        name,
+			State:             StateServing,
+			ActiveConnections: target.ActiveConnections(),
+		}
+
+		if p, ok := m.drains[name]; ok {
+			startedAt, deadline := p.startedAt, p.deadline
+			status.State = StateDraining
+			status.StartedAt = &startedAt
+			status.Deadline = &deadline
+
+			if !p.completedAt.IsZero() {
+				completedAt := p.completedAt
+				status.State = StateDrained
+				status.CompletedAt = &completedAt
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].EntryPoint < statuses[j].EntryPoint
+	})
+
+	return statuses
+}
+
+func (m *Manager) start(name string, target Target, p *progress) {
+	safe.Go(func() {
+		ctx := log.With(context.Background(), log.Str(log.EntryPointName, name))
+		logger := log.FromContext(ctx)
+
+		logger.Infof("Draining the entry point, %d active connections", target.ActiveConnections())
+
+		ctx, cancel := context.WithDeadline(ctx, p.deadline)
+		target.Drain(ctx)
+		cancel()
+
+		m.mu.Lock()
+		p.completedAt = time.Now()
+		m.mu.Unlock()
+
+		logger.Info("Entry point drained")
+	})
+}
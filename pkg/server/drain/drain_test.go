@@ -0,0 +1,100 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTarget struct {
+	connections int
+	release     chan struct{}
+}
+
+func newFakeTarget(connections int) *fakeTarget {
+	return &fakeTarget{connections: connections, release: make(chan struct{})}
+}
+
+func (f *fakeTarget) Drain(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-f.release:
+	}
+}
+
+func (f *fakeTarget) ActiveConnections() int {
+	return f.connections
+}
+
+func TestManager_Drain(t *testing.T) {
+	web := newFakeTarget(2)
+	traefik := newFakeTarget(1)
+
+	manager := NewManager(time.Minute, []string{"web"})
+	manager.Register("web", web)
+	manager.Register("traefik", traefik)
+
+	var calls int
+	manager.AddListener(func() { calls++ })
+
+	assert.False(t, manager.Draining())
+	assert.Equal(t, []EntryPointStatus{
+		{EntryPoint: "traefik", State: StateServing, ActiveConnections: 1},
+		{EntryPoint: "web", State: StateServing, ActiveConnections: 2},
+	}, manager.Status())
+
+	err := manager.Drain("web", "unknown")
+	assert.True(t, errors.Is(err, ErrUnknownEntryPoint))
+	assert.False(t, manager.Draining())
+	assert.Equal(t, 0, calls)
+
+	// Without entry points, the default ones are drained.
+	err = manager.Drain()
+	require.NoError(t, err)
+
+	assert.True(t, manager.Draining())
+	assert.Equal(t, 1, calls)
+
+	status := manager.Status()
+	require.Len(t, status, 2)
+	assert.Equal(t, StateServing, status[0].State)
+	assert.Equal(t, StateDraining, status[1].State)
+	require.NotNil(t, status[1].StartedAt)
+	require.NotNil(t, status[1].Deadline)
+	assert.Equal(t, time.Minute, status[1].Deadline.Sub(*status[1].StartedAt))
+	assert.Nil(t, status[1].CompletedAt)
+
+	close(web.release)
+
+	assert.Eventually(t, func() bool {
+		return manager.Status()[1].State == StateDrained
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotNil(t, manager.Status()[1].CompletedAt)
+
+	// The listeners are only called on the first drain.
+	err = manager.Drain("web", "traefik")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, StateDrained, manager.Status()[1].State)
+	assert.Equal(t, StateDraining, manager.Status()[0].State)
+}
+
+func TestManager_Drain_deadline(t *testing.T) {
+	web := newFakeTarget(1)
+
+	manager := NewManager(50*time.Millisecond, []string{"web"})
+	manager.Register("web", web)
+
+	err := manager.Drain()
+	require.NoError(t, err)
+
+	// The target is never released, so the drain completes on its deadline.
+	assert.Eventually(t, func() bool {
+		return manager.Status()[0].State == StateDrained
+	}, 5*time.Second, 10*time.Millisecond)
+}
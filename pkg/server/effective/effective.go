@@ -0,0 +1,72 @@
+package effective
+
+import (
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// redacted replaces the private keys given as content in the configuration.
+const redacted = "REDACTED"
+
+// Snapshot is the effective dynamic configuration, once the configurations of the providers are merged,
+// and the entry point models and the runtime overrides are applied.
+type Snapshot struct {
+	// UpdatedAt is the time the configuration was applied.
+	UpdatedAt     time.Time              `json:"updatedAt"`
+	Configuration *dynamic.Configuration `json:"configuration"`
+}
+
+// Store holds the latest effective dynamic configuration, to export it.
+type Store struct {
+	mu       sync.RWMutex
+	snapshot *Snapshot
+}
+
+// NewStore creates a new Store, holding no configuration until the first one is applied.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Set records the applied configuration.
+// The private keys given as content are redacted, so that the configuration can be exported safely.
+func (s *Store) Set(conf dynamic.Configuration) {
+	snapshot := &Snapshot{
+		UpdatedAt:     time.Now().UTC(),
+		Configuration: redactKeys(conf.DeepCopy()),
+	}
+
+	s.mu.Lock()
+	s.snapshot = snapshot
+	s.mu.Unlock()
+}
+
+// Get returns the latest applied configuration, or nil when no configuration has been applied yet.
+// The returned snapshot must not be modified.
+func (s *Store) Get() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.snapshot
+}
+
+func redactKeys(conf *dynamic.Configuration) *dynamic.Configuration {
+	if conf.TLS == nil {
+		return conf
+	}
+
+	for _, cert := range conf.TLS.Certificates {
+		if cert != nil && cert.KeyFile != "" && !cert.KeyFile.IsPath() {
+			cert.KeyFile = redacted
+		}
+	}
+
+	for _, store := range conf.TLS.Stores {
+		if store.DefaultCertificate != nil && store.DefaultCertificate.KeyFile != "" && !store.DefaultCertificate.KeyFile.IsPath() {
+			store.DefaultCertificate.KeyFile = redacted
+		}
+	}
+
+	return conf
+}
@@ -0,0 +1,136 @@
+package freshness
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// Watchdog tracks the last configuration message received from each provider,
+// and reports the providers which do not send any message within their maximum age as stale.
+type Watchdog struct {
+	maxAges       map[string]time.Duration
+	checkInterval time.Duration
+
+	lastMessageGauge gokitmetrics.Gauge
+	staleGauge       gokitmetrics.Gauge
+
+	mu           sync.Mutex
+	startedAt    time.Time
+	lastMessages map[string]time.Time
+	stale        map[string]bool
+}
+
+// New creates a Watchdog.
+// Without configuration, it only reports the time of the last message of each provider.
+func New(config *static.Freshness, registry metrics.Registry) *Watchdog {
+	w := &Watchdog{
+		maxAges:          make(map[string]time.Duration),
+		lastMessageGauge: registry.ProviderLastMessageGauge(),
+		staleGauge:       registry.ProviderStaleGauge(),
+		startedAt:        time.Now(),
+		lastMessages:     make(map[string]time.Time),
+		stale:            make(map[string]bool),
+	}
+
+	if config == nil {
+		return w
+	}
+
+	w.checkInterval = time.Duration(config.CheckInterval)
+	for name, maxAge := range config.MaxAge {
+		w.maxAges[name] = time.Duration(maxAge)
+	}
+
+	return w
+}
+
+// Touch records a configuration message received from the provider.
+func (w *Watchdog) Touch(providerName string) {
+	if w == nil {
+		return
+	}
+
+	now := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastMessages[providerName] = now
+	w.lastMessageGauge.With("provider", providerName).Set(float64(now.Unix()))
+
+	if w.stale[providerName] {
+		w.stale[providerName] = false
+		w.staleGauge.With("provider", providerName).Set(0)
+
+		log.WithoutContext().WithField(log.ProviderName, providerName).
+			Infof("Provider %s is sending configuration messages again", providerName)
+	}
+}
+
+// Run checks the freshness of the providers periodically, until the context is done.
+func (w *Watchdog) Run(ctx context.Context) {
+	if len(w.maxAges) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
+
+// check reports the providers whose last message, or the start of the watchdog when they did not send any, is older than their maximum age.
+func (w *Watchdog) check(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	names := make([]string, 0, len(w.maxAges))
+	for name := range w.maxAges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		last, ok := w.lastMessages[name]
+		if !ok {
+			last = w.startedAt
+		}
+
+		age := now.Sub(last)
+		if age <= w.maxAges[name] {
+			if _, ok := w.stale[name]; !ok {
+				w.stale[name] = false
+				w.staleGauge.With("provider", name).Set(0)
+			}
+			continue
+		}
+
+		if w.stale[name] {
+			continue
+		}
+
+		w.stale[name] = true
+		w.staleGauge.With("provider", name).Set(1)
+
+		logger := log.WithoutContext().WithField(log.ProviderName, name)
+		if ok {
+			logger.Warnf("Provider %s is stale: no configuration message received for %s, beyond its maximum age of %s", name, age.Truncate(time.Second), w.maxAges[name])
+		} else {
+			logger.Warnf("Provider %s is stale: no configuration message received since the start, %s ago, beyond its maximum age of %s", name, age.Truncate(time.Second), w.maxAges[name])
+		}
+	}
+}
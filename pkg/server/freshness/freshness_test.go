@@ -0,0 +1,61 @@
+package freshness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestWatchdog(t *testing.T) {
+	config := &static.Freshness{
+		MaxAge: map[string]ptypes.Duration{
+			"consul": ptypes.Duration(time.Minute),
+			"http":   ptypes.Duration(time.Minute),
+		},
+	}
+	config.SetDefaults()
+
+	watchdog := New(config, metrics.NewVoidRegistry())
+
+	lastMessageGauge := &testhelpers.CollectingGauge{}
+	watchdog.lastMessageGauge = lastMessageGauge
+	staleGauge := &testhelpers.CollectingGauge{}
+	watchdog.staleGauge = staleGauge
+
+	watchdog.Touch("consul")
+	assert.Equal(t, []string{"provider", "consul"}, lastMessageGauge.LastLabelValues)
+	assert.InDelta(t, float64(time.Now().Unix()), lastMessageGauge.GaugeValue, 1)
+
+	// The providers without maximum age are never stale.
+	watchdog.Touch("file")
+
+	now := time.Now()
+
+	watchdog.check(now.Add(30 * time.Second))
+	assert.Equal(t, map[string]bool{"consul": false, "http": false}, watchdog.stale)
+
+	// The http provider did not send any message since the start.
+	watchdog.startedAt = now.Add(-2 * time.Minute)
+	watchdog.check(now.Add(30 * time.Second))
+	assert.Equal(t, map[string]bool{"consul": false, "http": true}, watchdog.stale)
+	assert.Equal(t, []string{"provider", "http"}, staleGauge.LastLabelValues)
+	assert.Equal(t, float64(1), staleGauge.GaugeValue)
+
+	watchdog.check(now.Add(3 * time.Minute))
+	assert.Equal(t, map[string]bool{"consul": true, "http": true}, watchdog.stale)
+
+	watchdog.Touch("http")
+	assert.Equal(t, map[string]bool{"consul": true, "http": false}, watchdog.stale)
+	assert.Equal(t, []string{"provider", "http"}, staleGauge.LastLabelValues)
+	assert.Equal(t, float64(0), staleGauge.GaugeValue)
+}
+
+func TestWatchdog_nil(t *testing.T) {
+	var watchdog *Watchdog
+	watchdog.Touch("consul")
+}
@@ -8,11 +8,19 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/middlewares/errorresponses"
 	metricsmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/pathtemplate"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdebug"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestid"
 	mTracing "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/trafficcapture"
+	"github.com/traefik/traefik/v2/pkg/middlewares/trafficmirror"
+	"github.com/traefik/traefik/v2/pkg/server/resources"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/jaeger"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 // ChainBuilder Creates a middleware chain by entry point. It is used for middlewares that are created almost systematically and that need to be created before all others.
@@ -21,6 +29,18 @@ type ChainBuilder struct {
 	accessLoggerMiddleware *accesslog.Handler
 	tracer                 *tracing.Tracing
 	requestDecorator       *requestdecorator.RequestDecorator
+	entryPoints            static.EntryPoints
+	errorResponses         *errorresponses.Renderer
+
+	// resourceGuards are the guards shedding the requests of the entry points while the process resources are close to exhaustion.
+	resourceGuards map[string]*resources.Guard
+
+	// mirrors are the traffic mirrors of the entry points, kept across the configuration changes.
+	mirrors map[string]*trafficmirror.Mirror
+	// captures are the traffic captures of the entry points, kept across the configuration changes.
+	captures map[string]*trafficcapture.Capture
+	// requestDebugger logs the routing of the requests carrying the debug header, and is nil when it is disabled.
+	requestDebugger *requestdebug.Debugger
 }
 
 // NewChainBuilder Creates a new ChainBuilder.
@@ -30,6 +50,11 @@ func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry m
 		accessLoggerMiddleware: accessLoggerMiddleware,
 		tracer:                 setupTracing(staticConfiguration.Tracing),
 		requestDecorator:       requestdecorator.New(staticConfiguration.HostResolver),
+		entryPoints:            staticConfiguration.EntryPoints,
+		errorResponses:         setupErrorResponses(staticConfiguration.ErrorResponses),
+		mirrors:                setupMirrors(staticConfiguration.EntryPoints),
+		captures:               setupCaptures(staticConfiguration.EntryPoints),
+		requestDebugger:        setupRequestDebugger(staticConfiguration.RequestDebug),
 	}
 }
 
@@ -37,21 +62,68 @@ func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry m
 func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.Chain {
 	chain := alice.New()
 
-	if c.accessLoggerMiddleware != nil {
+	// The access logs, traces and metrics of the routers, middlewares and services are only reported
+	// for the requests of the entry points whose corresponding signal is enabled.
+	var observability *static.ObservabilityConfig
+	if ep, ok := c.entryPoints[entryPointName]; ok {
+		observability = ep.Observability
+	}
+
+	if c.accessLoggerMiddleware != nil && observability.AccessLogsEnabled() {
 		chain = chain.Append(accesslog.WrapHandler(c.accessLoggerMiddleware))
 	}
 
-	if c.tracer != nil {
+	if c.tracer != nil && observability.TracingEnabled() {
 		chain = chain.Append(mTracing.WrapEntryPointHandler(ctx, c.tracer, entryPointName))
 	}
 
-	if c.metricsRegistry != nil && c.metricsRegistry.IsEpEnabled() {
+	if c.metricsRegistry != nil && !observability.MetricsEnabled() {
+		chain = chain.Append(metricsmiddleware.WrapDisabledHandler())
+	} else if c.metricsRegistry != nil && c.metricsRegistry.IsEpEnabled() {
 		chain = chain.Append(metricsmiddleware.WrapEntryPointHandler(ctx, c.metricsRegistry, entryPointName))
 	}
 
+	if guard, ok := c.resourceGuards[entryPointName]; ok {
+		chain = chain.Append(guard.WrapHandler(ctx))
+	}
+
+	if ep, ok := c.entryPoints[entryPointName]; ok && ep.HTTP.RequestID != nil {
+		chain = chain.Append(requestid.WrapHandler(ctx, *ep.HTTP.RequestID))
+	}
+
+	if ep, ok := c.entryPoints[entryPointName]; ok && len(ep.HTTP.PathTemplates) > 0 {
+		chain = chain.Append(pathtemplate.WrapHandler(ctx, ep.HTTP.PathTemplates))
+	}
+
+	if c.requestDebugger != nil {
+		chain = chain.Append(c.requestDebugger.WrapHandler(ctx, entryPointName))
+	}
+
+	if mirror, ok := c.mirrors[entryPointName]; ok {
+		chain = chain.Append(mirror.WrapHandler(ctx, entryPointName))
+	}
+
+	if capture, ok := c.captures[entryPointName]; ok {
+		chain = chain.Append(capture.WrapHandler(ctx, entryPointName))
+	}
+
+	if c.errorResponses != nil {
+		chain = chain.Append(c.errorResponses.WrapHandler(ctx))
+	}
+
 	return chain.Append(requestdecorator.WrapHandler(c.requestDecorator))
 }
 
+// HasRequestDebug reports whether the routing of the requests carrying the debug header is logged.
+func (c *ChainBuilder) HasRequestDebug() bool {
+	return c.requestDebugger != nil
+}
+
+// SetResourceGuards sets the guards shedding the requests of the entry points, by entry point name.
+func (c *ChainBuilder) SetResourceGuards(guards map[string]*resources.Guard) {
+	c.resourceGuards = guards
+}
+
 // Close accessLogger and tracer.
 func (c *ChainBuilder) Close() {
 	if c.accessLoggerMiddleware != nil {
@@ -63,6 +135,95 @@ func (c *ChainBuilder) Close() {
 	if c.tracer != nil {
 		c.tracer.Close()
 	}
+
+	for _, mirror := range c.mirrors {
+		mirror.Close()
+	}
+
+	for _, capture := range c.captures {
+		capture.Close()
+	}
+
+	if c.requestDebugger != nil {
+		c.requestDebugger.Close()
+	}
+}
+
+func setupMirrors(entryPoints static.EntryPoints) map[string]*trafficmirror.Mirror {
+	mirrors := make(map[string]*trafficmirror.Mirror)
+	for name, ep := range entryPoints {
+		if ep.HTTP.Mirroring == nil {
+			continue
+		}
+
+		mirror, err := trafficmirror.New(*ep.HTTP.Mirroring)
+		if err != nil {
+			log.WithoutContext().WithField(log.EntryPointName, name).Errorf("Unable to set up the traffic mirroring: %v", err)
+			continue
+		}
+
+		mirrors[name] = mirror
+	}
+
+	return mirrors
+}
+
+func setupCaptures(entryPoints static.EntryPoints) map[string]*trafficcapture.Capture {
+	captures := make(map[string]*trafficcapture.Capture)
+	// filePaths holds the entry points capturing their requests to each file.
+	filePaths := make(map[string]string)
+
+	for name, ep := range entryPoints {
+		if ep.HTTP.Capture == nil {
+			continue
+		}
+
+		logger := log.WithoutContext().WithField(log.EntryPointName, name)
+
+		if other, ok := filePaths[ep.HTTP.Capture.FilePath]; ok {
+			logger.Errorf("Unable to set up the traffic capture: the file %s is already used by the entry point %s", ep.HTTP.Capture.FilePath, other)
+			continue
+		}
+
+		capture, err := trafficcapture.New(*ep.HTTP.Capture)
+		if err != nil {
+			logger.Errorf("Unable to set up the traffic capture: %v", err)
+			continue
+		}
+
+		captures[name] = capture
+		filePaths[ep.HTTP.Capture.FilePath] = name
+	}
+
+	return captures
+}
+
+func setupRequestDebugger(conf *static.RequestDebug) *requestdebug.Debugger {
+	if conf == nil {
+		return nil
+	}
+
+	debugger, err := requestdebug.New(*conf)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to set up the request debug: %v", err)
+		return nil
+	}
+
+	return debugger
+}
+
+func setupErrorResponses(conf *types.ErrorResponses) *errorresponses.Renderer {
+	if conf == nil {
+		return nil
+	}
+
+	renderer, err := errorresponses.New(*conf)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to set up the error responses: %v", err)
+		return nil
+	}
+
+	return renderer
 }
 
 func setupTracing(conf *static.Tracing) *tracing.Tracing {
@@ -128,5 +289,13 @@ func setupTracing(conf *static.Tracing) *tracing.Tracing {
 		log.WithoutContext().Warnf("Unable to create tracer: %v", err)
 		return nil
 	}
+
+	policy, err := tracing.NewSamplingPolicy(conf.Sampling)
+	if err != nil {
+		log.WithoutContext().Warnf("Unable to create tracer: invalid sampling configuration: %v", err)
+		return nil
+	}
+	tracer.SetSamplingPolicy(policy)
+
 	return tracer
 }
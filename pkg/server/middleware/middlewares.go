@@ -9,39 +9,65 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
 	"github.com/traefik/traefik/v2/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
+	"github.com/traefik/traefik/v2/pkg/middlewares/cors"
 	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v2/pkg/middlewares/decompress"
+	"github.com/traefik/traefik/v2/pkg/middlewares/earlyhints"
+	"github.com/traefik/traefik/v2/pkg/middlewares/experiment"
+	"github.com/traefik/traefik/v2/pkg/middlewares/extproc"
+	"github.com/traefik/traefik/v2/pkg/middlewares/geoip"
+	"github.com/traefik/traefik/v2/pkg/middlewares/grpcweb"
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
 	"github.com/traefik/traefik/v2/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ipwhitelist"
+	"github.com/traefik/traefik/v2/pkg/middlewares/limits"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
+	metricsmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/passtlsclientcert"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdebug"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requesttimeout"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/websocket"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/secret"
 )
 
 type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	middlewareFollowedKey
 )
 
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	pluginBuilder  PluginsBuilder
-	serviceBuilder serviceBuilder
+	configs         map[string]*runtime.MiddlewareInfo
+	pluginBuilder   PluginsBuilder
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
+
+	// circuitBreakers resets the circuit breakers, and is nil when they cannot be reset.
+	circuitBreakers *circuitbreaker.Registry
+
+	// secrets resolves the secret references of the middleware options, and is nil when they are not resolved.
+	secrets *secret.Resolver
+
+	// requestDebug enables the logging of the decisions of the middlewares for the requests carrying the debug header.
+	requestDebug bool
 }
 
 type serviceBuilder interface {
@@ -49,15 +75,35 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, metricsRegistry metrics.Registry) *Builder {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, metricsRegistry: metricsRegistry}
+}
+
+// SetCircuitBreakers sets the registry resetting the circuit breakers.
+func (b *Builder) SetCircuitBreakers(registry *circuitbreaker.Registry) {
+	b.circuitBreakers = registry
+}
+
+// SetSecrets sets the resolver of the secret references of the middleware options.
+func (b *Builder) SetSecrets(secrets *secret.Resolver) {
+	b.secrets = secrets
+}
+
+// SetRequestDebug enables the logging of the decisions of the middlewares for the requests carrying the debug header.
+func (b *Builder) SetRequestDebug(enabled bool) {
+	b.requestDebug = enabled
 }
 
 // BuildChain creates a middleware chain.
 func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.Chain {
 	chain := alice.New()
-	for _, name := range middlewares {
+	for i, name := range middlewares {
 		middlewareName := provider.GetQualifiedName(ctx, name)
+		followed := i < len(middlewares)-1 || isFollowed(ctx)
 
 		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 			constructorContext := provider.AddInContext(ctx, middlewareName)
@@ -71,12 +117,28 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 				return nil, err
 			}
 
+			if followed {
+				if err = checkLastMiddleware(b.configs[middlewareName], middlewareName); err != nil {
+					b.configs[middlewareName].AddError(err, true)
+					return nil, err
+				}
+			}
+			constructorContext = context.WithValue(constructorContext, middlewareFollowedKey, followed)
+
 			constructor, err := b.buildConstructor(constructorContext, middlewareName)
 			if err != nil {
 				b.configs[middlewareName].AddError(err, true)
 				return nil, err
 			}
 
+			if b.metricsRegistry.IsMiddlewareEnabled() {
+				constructor = metricsmiddleware.WrapMiddlewareHandler(b.metricsRegistry, middlewareName, constructor)
+			}
+
+			if b.requestDebug {
+				constructor = requestdebug.WrapMiddlewareHandler(middlewareName, constructor)
+			}
+
 			handler, err := constructor(next)
 			if err != nil {
 				b.configs[middlewareName].AddError(err, true)
@@ -100,6 +162,29 @@ func checkRecursion(ctx context.Context, middlewareName string) (context.Context
 	return context.WithValue(ctx, middlewareStackKey, append(currentStack, middlewareName)), nil
 }
 
+// isFollowed returns whether the chain being built is followed by other middlewares,
+// i.e. it is the chain of a Chain middleware which is not the last one of its own chain.
+func isFollowed(ctx context.Context) bool {
+	followed, _ := ctx.Value(middlewareFollowedKey).(bool)
+	return followed
+}
+
+// checkLastMiddleware checks that a middleware which can forward the requests to another service than the router one
+// is not followed by other middlewares, which would otherwise be skipped.
+func checkLastMiddleware(config *runtime.MiddlewareInfo, middlewareName string) error {
+	if config.Experiment == nil {
+		return nil
+	}
+
+	for _, variant := range config.Experiment.Variants {
+		if variant.Service != "" {
+			return fmt.Errorf("middleware %q forwards variant %q to service %q and must be the last middleware of the router", middlewareName, variant.Name, variant.Service)
+		}
+	}
+
+	return nil
+}
+
 // it is the responsibility of the caller to make sure that b.configs[middlewareName].Middleware exists.
 func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (alice.Constructor, error) {
 	config := b.configs[middlewareName]
@@ -107,6 +192,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		return nil, fmt.Errorf("invalid middleware %q configuration", middlewareName)
 	}
 
+	// The secrets are resolved in a copy of the options, so that they are not exposed by the runtime configuration.
+	resolved, err := b.secrets.Middleware(middlewareName, config.Middleware)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolved != config.Middleware {
+		config = &runtime.MiddlewareInfo{Middleware: resolved}
+	}
+
 	var middleware alice.Constructor
 	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
 
@@ -159,7 +254,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, middlewareName)
+			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, b.metricsRegistry, b.circuitBreakers, middlewareName)
 		}
 	}
 
@@ -173,6 +268,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Decompress
+	if config.Decompress != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return decompress.New(ctx, next, *config.Decompress, middlewareName)
+		}
+	}
+
 	// ContentType
 	if config.ContentType != nil {
 		if middleware != nil {
@@ -188,6 +293,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// CORS
+	if config.CORS != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cors.New(ctx, next, *config.CORS, middlewareName)
+		}
+	}
+
 	// CustomErrors
 	if config.Errors != nil {
 		if middleware != nil {
@@ -208,6 +323,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// EarlyHints
+	if config.EarlyHints != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return earlyhints.New(ctx, next, *config.EarlyHints, middlewareName)
+		}
+	}
+
+	// Experiment
+	if config.Experiment != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return experiment.New(ctx, next, *config.Experiment, b.serviceBuilder, b.metricsRegistry, middlewareName)
+		}
+	}
+
+	// ExternalProcessor
+	if config.ExternalProcessor != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return extproc.New(ctx, next, *config.ExternalProcessor, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
@@ -218,6 +363,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// GeoIP
+	if config.GeoIP != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return geoip.New(ctx, next, *config.GeoIP, b.serviceBuilder, middlewareName)
+		}
+	}
+
+	// GRPCWeb
+	if config.GRPCWeb != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return grpcweb.New(ctx, next, *config.GRPCWeb, middlewareName)
+		}
+	}
+
 	// Headers
 	if config.Headers != nil {
 		if middleware != nil {
@@ -228,6 +393,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// SecurityHeaders
+	if config.SecurityHeaders != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return headers.NewSecurityHeaders(ctx, next, *config.SecurityHeaders, middlewareName)
+		}
+	}
+
 	// IPWhiteList
 	if config.IPWhiteList != nil {
 		if middleware != nil {
@@ -248,6 +423,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Limits
+	if config.Limits != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return limits.New(ctx, next, *config.Limits, b.metricsRegistry, middlewareName)
+		}
+	}
+
+	// Maintenance
+	if config.Maintenance != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return maintenance.New(ctx, next, *config.Maintenance, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -278,6 +473,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Redirects
+	if config.Redirects != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return redirect.NewRedirects(ctx, next, *config.Redirects, middlewareName)
+		}
+	}
+
 	// RedirectScheme
 	if config.RedirectScheme != nil {
 		if middleware != nil {
@@ -308,6 +513,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RequestTimeout
+	if config.RequestTimeout != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requesttimeout.New(ctx, next, *config.RequestTimeout, middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
@@ -319,6 +534,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// SAML
+	if config.SAML != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewSAML(ctx, next, *config.SAML, middlewareName)
+		}
+	}
+
 	// StripPrefix
 	if config.StripPrefix != nil {
 		if middleware != nil {
@@ -339,6 +564,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// WebSocket
+	if config.WebSocket != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return websocket.New(ctx, next, *config.WebSocket, b.metricsRegistry, middlewareName)
+		}
+	}
+
 	// Plugin
 	if config.Plugin != nil {
 		if middleware != nil {
@@ -364,7 +599,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
 
-	return tracing.Wrap(ctx, middleware), nil
+	return tracing.Wrap(ctx, middlewareName, middleware), nil
 }
 
 func inSlice(element string, stack []string) bool {
@@ -18,7 +18,7 @@ func TestBuilder_BuildChainNilConfig(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"empty": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -29,7 +29,7 @@ func TestBuilder_BuildChainNonExistentChain(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"foobar": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -241,6 +241,63 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 			},
 			expectedError: errors.New("could not instantiate middleware m2: recursion detected in m0->m1->m2->m3->m2"),
 		},
+		{
+			desc:       "Experiment without variant services followed by a middleware",
+			buildChain: []string{"experiment", "middleware-1"},
+			configuration: map[string]*dynamic.Middleware{
+				"experiment": {
+					Experiment: &dynamic.Experiment{
+						Variants:      []dynamic.ExperimentVariant{{Name: "control"}},
+						VariantHeader: "X-Experiment-Variant",
+					},
+				},
+				"middleware-1": {
+					Headers: &dynamic.Headers{
+						CustomRequestHeaders: map[string]string{"middleware-1": "value-middleware-1"},
+					},
+				},
+			},
+			expected: map[string]string{"middleware-1": "value-middleware-1", "X-Experiment-Variant": "control"},
+		},
+		{
+			desc:       "Experiment with a variant service followed by a middleware",
+			buildChain: []string{"experiment", "middleware-1"},
+			configuration: map[string]*dynamic.Middleware{
+				"experiment": {
+					Experiment: &dynamic.Experiment{
+						Variants: []dynamic.ExperimentVariant{{Name: "control"}, {Name: "new", Service: "new-service"}},
+					},
+				},
+				"middleware-1": {
+					Headers: &dynamic.Headers{
+						CustomRequestHeaders: map[string]string{"middleware-1": "value-middleware-1"},
+					},
+				},
+			},
+			expectedError: errors.New(`middleware "experiment" forwards variant "new" to service "new-service" and must be the last middleware of the router`),
+		},
+		{
+			desc:       "Experiment with a variant service last in a chain followed by a middleware",
+			buildChain: []string{"middleware-chain-1", "middleware-1"},
+			configuration: map[string]*dynamic.Middleware{
+				"experiment": {
+					Experiment: &dynamic.Experiment{
+						Variants: []dynamic.ExperimentVariant{{Name: "control"}, {Name: "new", Service: "new-service"}},
+					},
+				},
+				"middleware-chain-1": {
+					Chain: &dynamic.Chain{
+						Middlewares: []string{"experiment"},
+					},
+				},
+				"middleware-1": {
+					Headers: &dynamic.Headers{
+						CustomRequestHeaders: map[string]string{"middleware-1": "value-middleware-1"},
+					},
+				},
+			},
+			expectedError: errors.New(`middleware "experiment" forwards variant "new" to service "new-service" and must be the last middleware of the router`),
+		},
 		{
 			desc:       "--",
 			buildChain: []string{"m0"},
@@ -270,7 +327,7 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 					Middlewares: test.configuration,
 				},
 			})
-			builder := NewBuilder(rtConf.Middlewares, nil, nil)
+			builder := NewBuilder(rtConf.Middlewares, nil, nil, nil)
 
 			result := builder.BuildChain(ctx, test.buildChain)
 
@@ -329,7 +386,7 @@ func TestBuilder_buildConstructor(t *testing.T) {
 			Middlewares: testConfig,
 		},
 	})
-	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil)
+	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil, nil)
 
 	testCases := []struct {
 		desc          string
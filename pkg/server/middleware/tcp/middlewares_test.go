@@ -0,0 +1,120 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestBuilder_BuildChain(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		middlewares   []string
+		configs       map[string]*runtime.TCPMiddlewareInfo
+		expectedError string
+	}{
+		{
+			desc:        "SNI white list",
+			middlewares: []string{"allowed"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"allowed@provider": {TCPMiddleware: &dynamic.TCPMiddleware{SNIWhiteList: &dynamic.SNIWhiteList{ServerNames: []string{"foo.bar"}}}},
+			},
+		},
+		{
+			desc:          "non-existent middleware",
+			middlewares:   []string{"missing"},
+			configs:       map[string]*runtime.TCPMiddlewareInfo{},
+			expectedError: `middleware "missing@provider" does not exist`,
+		},
+		{
+			desc:        "empty middleware",
+			middlewares: []string{"empty"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"empty@provider": {TCPMiddleware: &dynamic.TCPMiddleware{}},
+			},
+			expectedError: `invalid middleware "empty@provider" configuration: invalid middleware type or middleware does not exist`,
+		},
+		{
+			desc:        "invalid SNI white list",
+			middlewares: []string{"invalid"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"invalid@provider": {TCPMiddleware: &dynamic.TCPMiddleware{SNIWhiteList: &dynamic.SNIWhiteList{}}},
+			},
+			expectedError: "serverNames and denyServerNames are empty, SNIWhiteLister not created",
+		},
+		{
+			desc:        "IP white list and in flight connections",
+			middlewares: []string{"allowed", "limited"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"allowed@provider": {TCPMiddleware: &dynamic.TCPMiddleware{IPWhiteList: &dynamic.TCPIPWhiteList{SourceRange: []string{"10.0.0.0/8"}}}},
+				"limited@provider": {TCPMiddleware: &dynamic.TCPMiddleware{InFlightConn: &dynamic.TCPInFlightConn{Amount: 10}}},
+			},
+		},
+		{
+			desc:        "rate limit",
+			middlewares: []string{"limited"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"limited@provider": {TCPMiddleware: &dynamic.TCPMiddleware{RateLimit: &dynamic.TCPRateLimit{Average: 10, BanDuration: ptypes.Duration(time.Minute)}}},
+			},
+		},
+		{
+			desc:        "invalid rate limit",
+			middlewares: []string{"invalid"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"invalid@provider": {TCPMiddleware: &dynamic.TCPMiddleware{RateLimit: &dynamic.TCPRateLimit{}}},
+			},
+			expectedError: "invalid average 0, it must be greater than zero",
+		},
+		{
+			desc:        "IP white list with an unknown CIDR set",
+			middlewares: []string{"unknown"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"unknown@provider": {TCPMiddleware: &dynamic.TCPMiddleware{IPWhiteList: &dynamic.TCPIPWhiteList{SourceRangeSets: []string{"offices"}}}},
+			},
+			expectedError: "unknown CIDR sets: offices",
+		},
+		{
+			desc:        "multi-types middleware",
+			middlewares: []string{"multi"},
+			configs: map[string]*runtime.TCPMiddlewareInfo{
+				"multi@provider": {TCPMiddleware: &dynamic.TCPMiddleware{
+					SNIWhiteList: &dynamic.SNIWhiteList{ServerNames: []string{"foo.bar"}},
+					InFlightConn: &dynamic.TCPInFlightConn{Amount: 10},
+				}},
+			},
+			expectedError: "cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := provider.AddInContext(context.Background(), "router@provider")
+
+			builder := NewBuilder(test.configs, nil)
+
+			handler, err := builder.BuildChain(ctx, test.middlewares).Then(tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+
+				for _, config := range test.configs {
+					assert.Equal(t, []string{test.expectedError}, config.Err)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package override
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// The modes of the paused providers.
+const (
+	// PauseKeep keeps the last configuration of the provider applied before it was paused.
+	PauseKeep = "keep"
+	// PauseDrop removes the configuration of the provider while it is paused.
+	PauseDrop = "drop"
+)
+
+// Overrides are the runtime overrides layered on top of the configuration of the providers.
+type Overrides struct {
+	DisabledRouters []string            `json:"disabledRouters,omitempty"`
+	DrainingServers map[string][]string `json:"drainingServers,omitempty"`
+	// PausedProviders are the modes of the paused providers, by provider name.
+	PausedProviders map[string]string `json:"pausedProviders,omitempty"`
+}
+
+// Store holds the runtime overrides, until they are cleared or Traefik restarts.
+// It acts as an ephemeral provider, whose overrides are applied on top of the merged configuration of the providers.
+type Store struct {
+	mu              sync.RWMutex
+	disabledRouters map[string]struct{}
+	drainingServers map[string]map[string]struct{}
+	pausedProviders map[string]string
+	// reloads is the number of reloads requested, which forces the handlers to be rebuilt.
+	reloads uint64
+
+	listeners []func()
+}
+
+// NewStore creates a new Store.
+func NewStore() *Store {
+	return &Store{
+		disabledRouters: make(map[string]struct{}),
+		drainingServers: make(map[string]map[string]struct{}),
+		pausedProviders: make(map[string]string),
+	}
+}
+
+// AddListener adds a listener function called when the overrides change,
+// or when a reload of the configuration is requested.
+func (s *Store) AddListener(listener func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// DisableRouter disables the given HTTP router.
+func (s *Store) DisableRouter(routerName string) {
+	s.update(func() {
+		s.disabledRouters[routerName] = struct{}{}
+	})
+}
+
+// EnableRouter removes the override disabling the given HTTP router.
+func (s *Store) EnableRouter(routerName string) {
+	s.update(func() {
+		delete(s.disabledRouters, routerName)
+	})
+}
+
+// DrainServer marks the server of the given HTTP service as draining:
+// the server does not receive new requests anymore, while the ongoing ones complete.
+func (s *Store) DrainServer(serviceName, serverURL string) {
+	s.update(func() {
+		if _, ok := s.drainingServers[serviceName]; !ok {
+			s.drainingServers[serviceName] = make(map[string]struct{})
+		}
+
+		s.drainingServers[serviceName][serverURL] = struct{}{}
+	})
+}
+
+// UndrainServer removes the override marking the server of the given HTTP service as draining.
+func (s *Store) UndrainServer(serviceName, serverURL string) {
+	s.update(func() {
+		delete(s.drainingServers[serviceName], serverURL)
+
+		if len(s.drainingServers[serviceName]) == 0 {
+			delete(s.drainingServers, serviceName)
+		}
+	})
+}
+
+// PauseProvider pauses the given provider: its new configurations are not applied until it is resumed,
+// and its last configuration is either kept or dropped, depending on the mode.
+func (s *Store) PauseProvider(providerName, mode string) {
+	s.update(func() {
+		s.pausedProviders[providerName] = mode
+	})
+}
+
+// ResumeProvider resumes the given provider, whose latest configuration is applied again.
+func (s *Store) ResumeProvider(providerName string) {
+	s.update(func() {
+		delete(s.pausedProviders, providerName)
+	})
+}
+
+// PausedProviders returns the modes of the paused providers, by provider name.
+func (s *Store) PausedProviders() map[string]string {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.pausedProviders) == 0 {
+		return nil
+	}
+
+	paused := make(map[string]string, len(s.pausedProviders))
+	for name, mode := range s.pausedProviders {
+		paused[name] = mode
+	}
+
+	return paused
+}
+
+// Reload requests a reload of the configuration, without changing the overrides.
+// As the handlers are rebuilt on reload, it resets their state, such as the one of the circuit breakers.
+func (s *Store) Reload() {
+	s.update(func() {
+		s.reloads++
+	})
+}
+
+// Generation returns a number changing each time a reload is requested.
+func (s *Store) Generation() uint64 {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.reloads
+}
+
+// Clear removes all the overrides.
+func (s *Store) Clear() {
+	s.update(func() {
+		s.disabledRouters = make(map[string]struct{})
+		s.drainingServers = make(map[string]map[string]struct{})
+		s.pausedProviders = make(map[string]string)
+	})
+}
+
+// Get returns the current overrides.
+func (s *Store) Get() Overrides {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var overrides Overrides
+
+	for name := range s.disabledRouters {
+		overrides.DisabledRouters = append(overrides.DisabledRouters, name)
+	}
+	sort.Strings(overrides.DisabledRouters)
+
+	for serviceName, servers := range s.drainingServers {
+		if overrides.DrainingServers == nil {
+			overrides.DrainingServers = make(map[string][]string)
+		}
+
+		for serverURL := range servers {
+			overrides.DrainingServers[serviceName] = append(overrides.DrainingServers[serviceName], serverURL)
+		}
+		sort.Strings(overrides.DrainingServers[serviceName])
+	}
+
+	for name, mode := range s.pausedProviders {
+		if overrides.PausedProviders == nil {
+			overrides.PausedProviders = make(map[string]string)
+		}
+
+		overrides.PausedProviders[name] = mode
+	}
+
+	return overrides
+}
+
+// Apply applies the overrides to the given configuration.
+// The disabled routers and the draining servers are removed from the configuration.
+func (s *Store) Apply(conf dynamic.Configuration) dynamic.Configuration {
+	if s == nil || conf.HTTP == nil {
+		return conf
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for routerName := range s.disabledRouters {
+		delete(conf.HTTP.Routers, routerName)
+	}
+
+	for serviceName, servers := range s.drainingServers {
+		service, ok := conf.HTTP.Services[serviceName]
+		if !ok || service.LoadBalancer == nil {
+			continue
+		}
+
+		// The service is copied, as it is shared with the configuration of its provider.
+		service = service.DeepCopy()
+
+		var kept []dynamic.Server
+		for _, server := range service.LoadBalancer.Servers {
+			if _, draining := servers[server.URL]; !draining {
+				kept = append(kept, server)
+			}
+		}
+		service.LoadBalancer.Servers = kept
+
+		conf.HTTP.Services[serviceName] = service
+	}
+
+	return conf
+}
+
+func (s *Store) update(change func()) {
+	s.mu.Lock()
+	change()
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
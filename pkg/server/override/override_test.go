@@ -0,0 +1,109 @@
+package override
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestStore_Apply(t *testing.T) {
+	service := &dynamic.Service{
+		LoadBalancer: &dynamic.ServersLoadBalancer{
+			Servers: []dynamic.Server{{URL: "http://127.0.0.1"}, {URL: "http://127.0.0.2"}},
+		},
+	}
+
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@file": {Service: "svc@file"},
+				"bar@file": {Service: "svc@file"},
+			},
+			Services: map[string]*dynamic.Service{
+				"svc@file": service,
+			},
+		},
+	}
+
+	store := NewStore()
+	store.DisableRouter("foo@file")
+	store.DrainServer("svc@file", "http://127.0.0.1")
+	store.DrainServer("unknown@file", "http://127.0.0.1")
+
+	conf = store.Apply(conf)
+
+	assert.Equal(t, map[string]*dynamic.Router{"bar@file": {Service: "svc@file"}}, conf.HTTP.Routers)
+	assert.Equal(t, []dynamic.Server{{URL: "http://127.0.0.2"}}, conf.HTTP.Services["svc@file"].LoadBalancer.Servers)
+
+	// The configuration of the provider must not be changed.
+	assert.Len(t, service.LoadBalancer.Servers, 2)
+}
+
+func TestStore_Apply_nil(t *testing.T) {
+	var store *Store
+
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{"foo@file": {}},
+		},
+	}
+
+	assert.Equal(t, conf, store.Apply(conf))
+}
+
+func TestStore_Get(t *testing.T) {
+	store := NewStore()
+
+	var notified int
+	store.AddListener(func() {
+		notified++
+	})
+
+	assert.Equal(t, Overrides{}, store.Get())
+
+	store.DisableRouter("foo@file")
+	store.DisableRouter("bar@file")
+	store.DrainServer("svc@file", "http://127.0.0.2")
+	store.DrainServer("svc@file", "http://127.0.0.1")
+	store.PauseProvider("docker", PauseKeep)
+	store.PauseProvider("file", PauseDrop)
+
+	expected := Overrides{
+		DisabledRouters: []string{"bar@file", "foo@file"},
+		DrainingServers: map[string][]string{"svc@file": {"http://127.0.0.1", "http://127.0.0.2"}},
+		PausedProviders: map[string]string{"docker": PauseKeep, "file": PauseDrop},
+	}
+	assert.Equal(t, expected, store.Get())
+	assert.Equal(t, expected.PausedProviders, store.PausedProviders())
+
+	store.EnableRouter("foo@file")
+	store.UndrainServer("svc@file", "http://127.0.0.1")
+	store.UndrainServer("svc@file", "http://127.0.0.2")
+	store.ResumeProvider("file")
+
+	assert.Equal(t, Overrides{DisabledRouters: []string{"bar@file"}, PausedProviders: map[string]string{"docker": PauseKeep}}, store.Get())
+
+	store.Reload()
+	store.Clear()
+
+	assert.Equal(t, Overrides{}, store.Get())
+	assert.Nil(t, store.PausedProviders())
+	assert.Equal(t, 12, notified)
+}
+
+func TestStore_Generation(t *testing.T) {
+	var nilStore *Store
+	assert.Equal(t, uint64(0), nilStore.Generation())
+
+	store := NewStore()
+	store.DisableRouter("foo@file")
+	assert.Equal(t, uint64(0), store.Generation())
+
+	var notified int
+	store.AddListener(func() { notified++ })
+
+	store.Reload()
+	assert.Equal(t, uint64(1), store.Generation())
+	assert.Equal(t, 1, notified)
+}
@@ -0,0 +1,39 @@
+// +build !windows
+
+package resources
+
+import (
+	"math"
+	"os"
+	"syscall"
+)
+
+// fileDescriptors returns the number of open file descriptors of the process, and its soft limit, 0 when unlimited.
+func fileDescriptors() (int, int, error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, 0, err
+	}
+
+	dir, err := os.Open("/proc/self/fd")
+	if os.IsNotExist(err) {
+		dir, err = os.Open("/dev/fd")
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = dir.Close() }()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	maxFDs := 0
+	if uint64(limit.Cur) < math.MaxInt32 {
+		maxFDs = int(limit.Cur)
+	}
+
+	// The directory being read is itself an open file descriptor.
+	return len(names) - 1, maxFDs, nil
+}
@@ -0,0 +1,6 @@
+package resources
+
+// fileDescriptors returns no file descriptors, as the handles of the process are not limited like them on Windows.
+func fileDescriptors() (int, int, error) {
+	return 0, 0, nil
+}
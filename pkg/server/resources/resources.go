@@ -0,0 +1,207 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+const typeName = "ResourceGuard"
+
+// The resources reported by the usage gauge.
+const (
+	resourceFileDescriptors = "file_descriptors"
+	resourceMemory          = "memory"
+	resourceGoroutines      = "goroutines"
+)
+
+// usage is a sampling of the resource usage of the process.
+type usage struct {
+	// openFDs is the number of open file descriptors, 0 when it is unknown,
+	// and maxFDs its limit, 0 when it is unknown or unlimited.
+	openFDs int
+	maxFDs  int
+	// memory is the memory obtained from the system and not yet released, in bytes.
+	memory     int64
+	goroutines int
+}
+
+// Monitor samples the resource usage of the process periodically,
+// and reports it as overloaded while a resource usage is beyond its threshold,
+// so that the entry points shed their new connections before the process hits its limits.
+type Monitor struct {
+	checkInterval time.Duration
+	maxFDsPercent int
+	maxMemory     int64
+	maxGoroutines int
+	sample        func() usage
+
+	usageGauge      gokitmetrics.Gauge
+	overloadedGauge gokitmetrics.Gauge
+	shedCounter     gokitmetrics.Counter
+
+	overloaded int32
+	// reason describes the resource usages beyond their threshold, only accessed by the checks.
+	reason string
+}
+
+// NewMonitor creates a Monitor.
+func NewMonitor(config static.ResourceGuard, registry metrics.Registry) *Monitor {
+	return &Monitor{
+		checkInterval:   time.Duration(config.CheckInterval),
+		maxFDsPercent:   config.MaxFileDescriptorsPercent,
+		maxMemory:       config.MaxMemory,
+		maxGoroutines:   config.MaxGoroutines,
+		sample:          sampleUsage,
+		usageGauge:      registry.ResourceUsageGauge(),
+		overloadedGauge: registry.ResourceOverloadedGauge(),
+		shedCounter:     registry.ResourceShedCounter(),
+	}
+}
+
+// Run samples the resource usage periodically, until the context is done.
+func (m *Monitor) Run(ctx context.Context) {
+	m.check()
+
+	ticker := time.NewTicker(m.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// Overloaded tells whether a resource usage is beyond its threshold.
+func (m *Monitor) Overloaded() bool {
+	return atomic.LoadInt32(&m.overloaded) == 1
+}
+
+// check samples the resource usage, reports it, and updates the overload state.
+func (m *Monitor) check() {
+	u := m.sample()
+
+	if u.openFDs > 0 {
+		m.usageGauge.With("resource", resourceFileDescriptors).Set(float64(u.openFDs))
+	}
+	m.usageGauge.With("resource", resourceMemory).Set(float64(u.memory))
+	m.usageGauge.With("resource", resourceGoroutines).Set(float64(u.goroutines))
+
+	reasons := m.exceeded(u)
+
+	logger := log.WithoutContext()
+	if len(reasons) > 0 {
+		reason := strings.Join(reasons, ", ")
+		if atomic.SwapInt32(&m.overloaded, 1) == 0 || reason != m.reason {
+			logger.Warnf("The process resources are close to exhaustion, the new connections are shed: %s", reason)
+		}
+		m.reason = reason
+		m.overloadedGauge.Set(1)
+		return
+	}
+
+	if atomic.SwapInt32(&m.overloaded, 0) == 1 {
+		logger.Info("The process resources are back below their thresholds, the new connections are accepted again")
+	}
+	m.reason = ""
+	m.overloadedGauge.Set(0)
+}
+
+// exceeded returns the descriptions of the resource usages beyond their threshold.
+func (m *Monitor) exceeded(u usage) []string {
+	var reasons []string
+
+	if m.maxFDsPercent > 0 && u.maxFDs > 0 && u.openFDs*100 >= u.maxFDs*m.maxFDsPercent {
+		reasons = append(reasons, fmt.Sprintf("%d open file descriptors out of %d", u.openFDs, u.maxFDs))
+	}
+
+	if m.maxMemory > 0 && u.memory >= m.maxMemory {
+		reasons = append(reasons, fmt.Sprintf("%d bytes of memory used out of %d", u.memory, m.maxMemory))
+	}
+
+	if m.maxGoroutines > 0 && u.goroutines >= m.maxGoroutines {
+		reasons = append(reasons, fmt.Sprintf("%d goroutines out of %d", u.goroutines, m.maxGoroutines))
+	}
+
+	return reasons
+}
+
+// Guard returns the guard shedding the new connections and requests of an entry point.
+func (m *Monitor) Guard(entryPointName string) *Guard {
+	return &Guard{
+		monitor:     m,
+		connections: m.shedCounter.With("entrypoint", entryPointName, "type", "connection"),
+		requests:    m.shedCounter.With("entrypoint", entryPointName, "type", "request"),
+	}
+}
+
+// Guard sheds the new connections and requests of an entry point while the monitor reports an overload.
+type Guard struct {
+	monitor     *Monitor
+	connections gokitmetrics.Counter
+	requests    gokitmetrics.Counter
+}
+
+// AcceptConnection tells whether a new connection is accepted, counting the shed ones.
+func (g *Guard) AcceptConnection() bool {
+	if g == nil || !g.monitor.Overloaded() {
+		return true
+	}
+
+	g.connections.Add(1)
+	return false
+}
+
+// WrapHandler wraps the resource guard into an alice.Constructor,
+// which answers the requests with a 429 status code and closes their connection while the monitor reports an overload.
+func (g *Guard) WrapHandler(ctx context.Context) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		log.FromContext(middlewares.GetLoggerCtx(ctx, "resourceguard", typeName)).Debug("Creating middleware")
+
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if !g.monitor.Overloaded() {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			g.requests.Add(1)
+			rw.Header().Set("Connection", "close")
+			http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		}), nil
+	}
+}
+
+func sampleUsage() usage {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	u := usage{
+		memory:     int64(stats.Sys - stats.HeapReleased),
+		goroutines: runtime.NumGoroutine(),
+	}
+
+	openFDs, maxFDs, err := fileDescriptors()
+	if err != nil {
+		log.WithoutContext().Debugf("Unable to count the open file descriptors: %v", err)
+		return u
+	}
+
+	u.openFDs, u.maxFDs = openFDs, maxFDs
+
+	return u
+}
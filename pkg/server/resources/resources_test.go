@@ -0,0 +1,131 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestMonitor_check(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   static.ResourceGuard
+		usage    usage
+		expected bool
+	}{
+		{
+			desc:   "below the thresholds",
+			config: static.ResourceGuard{MaxFileDescriptorsPercent: 90, MaxMemory: 1000, MaxGoroutines: 100},
+			usage:  usage{openFDs: 89, maxFDs: 100, memory: 999, goroutines: 99},
+		},
+		{
+			desc:     "file descriptors",
+			config:   static.ResourceGuard{MaxFileDescriptorsPercent: 90},
+			usage:    usage{openFDs: 90, maxFDs: 100},
+			expected: true,
+		},
+		{
+			desc:   "unlimited file descriptors",
+			config: static.ResourceGuard{MaxFileDescriptorsPercent: 90},
+			usage:  usage{openFDs: 90},
+		},
+		{
+			desc:     "memory",
+			config:   static.ResourceGuard{MaxMemory: 1000},
+			usage:    usage{memory: 1000},
+			expected: true,
+		},
+		{
+			desc:     "goroutines",
+			config:   static.ResourceGuard{MaxGoroutines: 100},
+			usage:    usage{goroutines: 100},
+			expected: true,
+		},
+		{
+			desc:  "disabled thresholds",
+			usage: usage{openFDs: 100, maxFDs: 100, memory: 1000, goroutines: 100},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			monitor := NewMonitor(test.config, metrics.NewVoidRegistry())
+			monitor.sample = func() usage { return test.usage }
+
+			overloadedGauge := &testhelpers.CollectingGauge{}
+			monitor.overloadedGauge = overloadedGauge
+
+			monitor.check()
+
+			assert.Equal(t, test.expected, monitor.Overloaded())
+			if test.expected {
+				assert.Equal(t, float64(1), overloadedGauge.GaugeValue)
+			} else {
+				assert.Equal(t, float64(0), overloadedGauge.GaugeValue)
+			}
+		})
+	}
+}
+
+func TestGuard(t *testing.T) {
+	monitor := NewMonitor(static.ResourceGuard{MaxGoroutines: 100}, metrics.NewVoidRegistry())
+
+	shedCounter := &testhelpers.CollectingCounter{}
+	monitor.shedCounter = shedCounter
+
+	guard := monitor.Guard("web")
+
+	handler, err := guard.WrapHandler(context.Background())(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+
+	serve := func() *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+		return rw
+	}
+
+	goroutines := 10
+	monitor.sample = func() usage { return usage{goroutines: goroutines} }
+
+	monitor.check()
+	assert.True(t, guard.AcceptConnection())
+	assert.Equal(t, http.StatusOK, serve().Code)
+
+	goroutines = 100
+	monitor.check()
+	assert.False(t, guard.AcceptConnection())
+
+	rw := serve()
+	assert.Equal(t, http.StatusTooManyRequests, rw.Code)
+	assert.Equal(t, "close", rw.Header().Get("Connection"))
+	assert.Equal(t, float64(2), shedCounter.CounterValue)
+
+	goroutines = 10
+	monitor.check()
+	assert.True(t, guard.AcceptConnection())
+	assert.Equal(t, http.StatusOK, serve().Code)
+}
+
+func TestGuard_nil(t *testing.T) {
+	var guard *Guard
+	assert.True(t, guard.AcceptConnection())
+}
+
+func TestSampleUsage(t *testing.T) {
+	u := sampleUsage()
+
+	assert.Greater(t, u.memory, int64(0))
+	assert.Greater(t, u.goroutines, 0)
+}
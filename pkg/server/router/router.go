@@ -3,17 +3,23 @@ package router
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/middlewares/recovery"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdebug"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/upgrade"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service"
 )
 
 const (
@@ -36,16 +42,18 @@ type Manager struct {
 	middlewaresBuilder middlewareBuilder
 	chainBuilder       *middleware.ChainBuilder
 	conf               *runtime.Configuration
+	metricsRegistry    metrics.Registry
 }
 
 // NewManager Creates a new Manager.
-func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder) *Manager {
+func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
 		serviceManager:     serviceManager,
 		middlewaresBuilder: middlewaresBuilder,
 		chainBuilder:       chainBuilder,
 		conf:               conf,
+		metricsRegistry:    metricsRegistry,
 	}
 }
 
@@ -141,21 +149,32 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		return handler, nil
 	}
 
+	switch routerConfig.ModelMiddlewares {
+	case "", dynamic.ModelMiddlewaresBefore, dynamic.ModelMiddlewaresAfter, dynamic.ModelMiddlewaresNone:
+	default:
+		return nil, fmt.Errorf("invalid model middlewares placement %q, expected %s, %s, or %s",
+			routerConfig.ModelMiddlewares, dynamic.ModelMiddlewaresBefore, dynamic.ModelMiddlewaresAfter, dynamic.ModelMiddlewaresNone)
+	}
+
 	handler, err := m.buildHTTPHandler(ctx, routerConfig, routerName)
 	if err != nil {
 		return nil, err
 	}
 
-	handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
-		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, nil), nil
-	}).Then(handler)
+	handler, err = service.NewRouterUpstreamTimeouts(handler, routerConfig.Timeouts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeouts configuration: %w", err)
+	}
+
+	handler = upgrade.NewRouterHandler(handler, routerName, routerConfig.Upgrades, m.metricsRegistry)
+
+	handlerWithAccessLog, err := accesslog.NewRouterHandler(handler, routerName, routerConfig.AccessLog)
 	if err != nil {
-		log.FromContext(ctx).Error(err)
-		m.routerHandlers[routerName] = handler
-	} else {
-		m.routerHandlers[routerName] = handlerWithAccessLog
+		return nil, fmt.Errorf("invalid access log configuration: %w", err)
 	}
 
+	m.routerHandlers[routerName] = handlerWithAccessLog
+
 	return m.routerHandlers[routerName], nil
 }
 
@@ -178,10 +197,15 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
 	tHandler := func(next http.Handler) (http.Handler, error) {
-		return tracing.NewForwarder(ctx, routerName, router.Service, next), nil
+		return tracing.NewForwarder(ctx, routerName, router.Service, router.Tracing, next)
+	}
+
+	chain := alice.New()
+	if m.chainBuilder.HasRequestDebug() {
+		chain = chain.Append(requestdebug.WrapRouterHandler(routerName, router.Service))
 	}
 
-	return alice.New().Extend(*mHandler).Append(tHandler).Then(sHandler)
+	return chain.Extend(*mHandler).Append(tHandler).Then(sHandler)
 }
 
 // BuildDefaultHTTPRouter creates a default HTTP router.
@@ -12,6 +12,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/rules"
+	tcpmiddleware "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v2/pkg/tcp"
@@ -26,26 +27,29 @@ const (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *tcpservice.Manager,
+	middlewaresBuilder *tcpmiddleware.Builder,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		httpHandlers:   httpHandlers,
-		httpsHandlers:  httpsHandlers,
-		tlsManager:     tlsManager,
-		conf:           conf,
+		serviceManager:     serviceManager,
+		middlewaresBuilder: middlewaresBuilder,
+		httpHandlers:       httpHandlers,
+		httpsHandlers:      httpsHandlers,
+		tlsManager:         tlsManager,
+		conf:               conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *tcpservice.Manager
-	httpHandlers   map[string]http.Handler
-	httpsHandlers  map[string]http.Handler
-	tlsManager     *traefiktls.Manager
-	conf           *runtime.Configuration
+	serviceManager     *tcpservice.Manager
+	middlewaresBuilder *tcpmiddleware.Builder
+	httpHandlers       map[string]http.Handler
+	httpsHandlers      map[string]http.Handler
+	tlsManager         *traefiktls.Manager
+	conf               *runtime.Configuration
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -193,6 +197,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	})
 
 	router.HTTPSHandler(sniCheck, defaultTLSConf)
+	router.ACMETLSChallenge(m.tlsManager.HasACMETLSChallenge)
 
 	logger := log.FromContext(ctx)
 	for hostSNI, tlsConfigs := range tlsOptionsForHostSNI {
@@ -246,6 +251,21 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		// The middlewares handle the connections before the TLS termination,
+		// so that they can read the server name of the ClientHello.
+		var chain *tcp.Chain
+		if m.middlewaresBuilder != nil && len(routerConfig.Middlewares) > 0 {
+			chain = m.middlewaresBuilder.BuildChain(ctxRouter, routerConfig.Middlewares)
+		}
+
+		// The router name is recorded before the TLS termination, under which the recorder cannot be found.
+		recordedHandler, err := m.wrapHandler(routerName, chain, handler)
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+
 		domains, err := rules.ParseHostSNI(routerConfig.Rule)
 		if err != nil {
 			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
@@ -254,12 +274,28 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		prefixes, err := rules.ParseDataPrefixes(routerConfig.Rule)
+		if err == nil {
+			err = checkDataPrefixes(prefixes, routerConfig.TLS != nil)
+		}
+		if err != nil {
+			routerErr := fmt.Errorf("invalid rule %s: %w", routerConfig.Rule, err)
+			routerConfig.AddError(routerErr, true)
+			logger.Error(routerErr)
+			continue
+		}
+
+		for _, prefix := range prefixes {
+			logger.Debugf("Adding route for the data prefix %q on TCP", prefix)
+			router.AddRoutePrefix(prefix, recordedHandler)
+		}
+
 		for _, domain := range domains {
 			logger.Debugf("Adding route %s on TCP", domain)
 			switch {
 			case routerConfig.TLS != nil:
 				if routerConfig.TLS.Passthrough {
-					router.AddRoute(domain, handler)
+					router.AddRoute(domain, recordedHandler)
 				} else {
 					tlsOptionsName := routerConfig.TLS.Options
 
@@ -278,10 +314,20 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 						continue
 					}
 
-					router.AddRouteTLS(domain, handler, tlsConf)
+					tlsHandler, err := m.wrapHandler(routerName, chain, &tcp.TLSHandler{
+						Next:   handler,
+						Config: tlsConf,
+					})
+					if err != nil {
+						routerConfig.AddError(err, true)
+						logger.Error(err)
+						continue
+					}
+
+					router.AddRoute(domain, tlsHandler)
 				}
 			case domain == "*":
-				router.AddCatchAllNoTLS(handler)
+				router.AddCatchAllNoTLS(recordedHandler)
 			default:
 				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
 			}
@@ -291,6 +337,34 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 	return router, nil
 }
 
+// wrapHandler wraps handler with the middlewares of the router, if any, and records the router name.
+func (m *Manager) wrapHandler(routerName string, chain *tcp.Chain, handler tcp.Handler) (tcp.Handler, error) {
+	if chain != nil {
+		var err error
+		handler, err = chain.Then(handler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tcp.RouterRecorder(routerName, handler), nil
+}
+
+// checkDataPrefixes checks that the prefixes of the first bytes of the connections can be matched by the router.
+func checkDataPrefixes(prefixes []string, withTLS bool) error {
+	if len(prefixes) > 0 && withTLS {
+		return errors.New("the DataPrefix matcher only applies to the non-TLS routers")
+	}
+
+	for _, prefix := range prefixes {
+		if prefix == "" || len(prefix) > tcp.MaxDataPrefixLength {
+			return fmt.Errorf("the data prefix %q must be between 1 and %d bytes long", prefix, tcp.MaxDataPrefixLength)
+		}
+	}
+
+	return nil
+}
+
 func findTLSOptionName(tlsOptionsForHost map[string]string, host string) string {
 	tlsOptions, ok := tlsOptionsForHost[host]
 	if ok {
@@ -6,10 +6,13 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	tcpmiddleware "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	routertcp "github.com/traefik/traefik/v2/pkg/server/router/tcp"
 	routerudp "github.com/traefik/traefik/v2/pkg/server/router/udp"
+	"github.com/traefik/traefik/v2/pkg/server/secret"
 	"github.com/traefik/traefik/v2/pkg/server/service"
 	"github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/service/udp"
@@ -27,12 +30,20 @@ type RouterFactory struct {
 
 	pluginBuilder middleware.PluginsBuilder
 
-	chainBuilder *middleware.ChainBuilder
-	tlsManager   *tls.Manager
+	chainBuilder    *middleware.ChainBuilder
+	tlsManager      *tls.Manager
+	metricsRegistry metrics.Registry
+
+	// secrets resolves the secret references of the middleware options, and is nil when they are not resolved.
+	secrets *secret.Resolver
+
+	// previous is the configuration of the previous build, and groups its groups of entry points, keyed by their names.
+	previous *runtime.Configuration
+	groups   map[string]*entryPointGroup
 }
 
 // NewRouterFactory creates a new RouterFactory.
-func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager, chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder) *RouterFactory {
+func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager, chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
 	for name, cfg := range staticConfiguration.EntryPoints {
 		protocol, err := cfg.GetProtocol()
@@ -49,36 +60,66 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	}
 
 	return &RouterFactory{
-		entryPointsTCP: entryPointsTCP,
-		entryPointsUDP: entryPointsUDP,
-		managerFactory: managerFactory,
-		tlsManager:     tlsManager,
-		chainBuilder:   chainBuilder,
-		pluginBuilder:  pluginBuilder,
+		entryPointsTCP:  entryPointsTCP,
+		entryPointsUDP:  entryPointsUDP,
+		managerFactory:  managerFactory,
+		tlsManager:      tlsManager,
+		chainBuilder:    chainBuilder,
+		pluginBuilder:   pluginBuilder,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
+// SetSecrets sets the resolver of the secret references of the middleware options.
+// The entry points are rebuilt each time the value of a resolved secret changes.
+func (f *RouterFactory) SetSecrets(secrets *secret.Resolver) {
+	f.secrets = secrets
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
+// Only the TCPRouters of the entry points whose configuration changed since the previous call are created,
+// the handlers of the other entry points are kept.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcpCore.Router, map[string]udpCore.Handler) {
 	ctx := context.Background()
 
-	// HTTP
-	serviceManager := f.managerFactory.Build(rtConf)
+	generations := []uint64{f.tlsManager.Generation(), f.managerFactory.Generation(), f.managerFactory.ReloadGeneration(), f.secrets.Generation()}
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	groups := make(map[string]*entryPointGroup)
+	var changed []*entryPointGroup
+	for _, entryPoints := range groupEntryPoints(rtConf, f.entryPointsTCP) {
+		group := &entryPointGroup{entryPoints: entryPoints}
+		group.config = newEntryPointsConfig(rtConf, entryPoints, generations)
+		group.fingerprint = group.config.fingerprint()
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder)
+		groups[group.key()] = group
 
-	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
-	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
+		previous, ok := f.groups[group.key()]
+		if ok && group.fingerprint != "" && group.fingerprint == previous.fingerprint {
+			log.WithoutContext().Debugf("Keeping the handlers of the entry points %v, their configuration is unchanged", entryPoints)
 
-	serviceManager.LaunchHealthCheck()
+			group.serviceManager = previous.serviceManager
+			reuseRuntimeState(rtConf, f.previous, group.config)
+			continue
+		}
 
-	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
+		changed = append(changed, group)
+	}
+
+	f.reportOrphanRouters(ctx, rtConf, groups)
+
+	routersTCP := make(map[string]*tcpCore.Router)
+	for _, group := range changed {
+		for entryPointName, rt := range f.createGroupRouters(ctx, rtConf, group) {
+			routersTCP[entryPointName] = rt
+		}
+	}
+
+	serviceManagers := make([]*service.InternalHandlers, 0, len(groups))
+	for _, group := range groups {
+		serviceManagers = append(serviceManagers, group.serviceManager)
+	}
 
-	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager)
-	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
+	service.LaunchHealthChecks(serviceManagers...)
 
 	// UDP
 	svcUDPManager := udp.NewManager(rtConf)
@@ -87,5 +128,86 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	rtConf.PopulateUsedBy()
 
+	// The secrets of the removed middlewares are not followed anymore.
+	f.secrets.Prune(rtConf.Middlewares)
+
+	f.previous = rtConf
+	f.groups = groups
+
 	return routersTCP, routersUDP
 }
+
+// createGroupRouters creates the TCPRouters of a group of entry points.
+func (f *RouterFactory) createGroupRouters(ctx context.Context, rtConf *runtime.Configuration, group *entryPointGroup) map[string]*tcpCore.Router {
+	// The routers are limited to the ones of the group, the middlewares and services are shared by all the groups.
+	groupConf := &runtime.Configuration{
+		Routers:        make(map[string]*runtime.RouterInfo, len(group.config.Routers)),
+		Middlewares:    rtConf.Middlewares,
+		Services:       rtConf.Services,
+		TCPRouters:     make(map[string]*runtime.TCPRouterInfo, len(group.config.TCPRouters)),
+		TCPMiddlewares: rtConf.TCPMiddlewares,
+		TCPServices:    rtConf.TCPServices,
+	}
+
+	for name := range group.config.Routers {
+		groupConf.Routers[name] = rtConf.Routers[name]
+	}
+
+	for name := range group.config.TCPRouters {
+		groupConf.TCPRouters[name] = rtConf.TCPRouters[name]
+	}
+
+	// HTTP
+	serviceManager := f.managerFactory.Build(rtConf)
+	group.serviceManager = serviceManager
+
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.metricsRegistry)
+	middlewaresBuilder.SetCircuitBreakers(f.managerFactory.CircuitBreakers())
+	middlewaresBuilder.SetSecrets(f.secrets)
+	middlewaresBuilder.SetRequestDebug(f.chainBuilder.HasRequestDebug())
+
+	routerManager := router.NewManager(groupConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry)
+
+	handlersNonTLS := routerManager.BuildHandlers(ctx, group.entryPoints, false)
+	handlersTLS := routerManager.BuildHandlers(ctx, group.entryPoints, true)
+
+	// TCP
+	svcTCPManager := tcp.NewManager(rtConf, f.metricsRegistry)
+
+	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, f.metricsRegistry)
+
+	rtTCPManager := routertcp.NewManager(groupConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+
+	return rtTCPManager.BuildHandlers(ctx, group.entryPoints)
+}
+
+// reportOrphanRouters reports the errors of the routers not belonging to any group of entry points,
+// because none of their entry points exists.
+func (f *RouterFactory) reportOrphanRouters(ctx context.Context, rtConf *runtime.Configuration, groups map[string]*entryPointGroup) {
+	orphans := &runtime.Configuration{
+		Routers:    make(map[string]*runtime.RouterInfo),
+		TCPRouters: make(map[string]*runtime.TCPRouterInfo),
+	}
+
+	for name, rt := range rtConf.Routers {
+		orphans.Routers[name] = rt
+	}
+
+	for name, rt := range rtConf.TCPRouters {
+		orphans.TCPRouters[name] = rt
+	}
+
+	for _, group := range groups {
+		for name := range group.config.Routers {
+			delete(orphans.Routers, name)
+		}
+
+		for name := range group.config.TCPRouters {
+			delete(orphans.TCPRouters, name)
+		}
+	}
+
+	orphans.GetRoutersByEntryPoints(ctx, f.entryPointsTCP, false)
+	orphans.GetRoutersByEntryPoints(ctx, f.entryPointsTCP, true)
+	orphans.GetTCPRoutersByEntryPoints(ctx, f.entryPointsTCP)
+}
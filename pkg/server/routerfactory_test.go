@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	"github.com/traefik/traefik/v2/pkg/server/override"
 	"github.com/traefik/traefik/v2/pkg/server/service"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tls"
@@ -50,10 +52,10 @@ func TestReuseService(t *testing.T) {
 
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, service.APIOptions{})
 	tlsManager := tls.NewManager()
 
-	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil)
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
 
 	entryPointsHandlers, _ := factory.CreateRouters(runtime.NewConfig(dynamic.Configuration{HTTP: dynamicConfigs}))
 
@@ -186,10 +188,10 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, service.APIOptions{})
 			tlsManager := tls.NewManager()
 
-			factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil)
+			factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
 
 			entryPointsHandlers, _ := factory.CreateRouters(runtime.NewConfig(dynamic.Configuration{HTTP: test.config(testServer.URL)}))
 
@@ -227,10 +229,10 @@ func TestInternalServices(t *testing.T) {
 
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, service.APIOptions{})
 	tlsManager := tls.NewManager()
 
-	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil)
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
 
 	entryPointsHandlers, _ := factory.CreateRouters(runtime.NewConfig(dynamic.Configuration{HTTP: dynamicConfigs}))
 
@@ -241,3 +243,87 @@ func TestInternalServices(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, responseRecorderOk.Result().StatusCode, "status code")
 }
+
+func TestCreateRouters_partialRebuild(t *testing.T) {
+	staticConfig := static.Configuration{
+		EntryPoints: map[string]*static.EntryPoint{
+			"web":     {},
+			"private": {},
+			"admin":   {},
+		},
+	}
+
+	roundTripperManager := service.NewRoundTripperManager()
+	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+	overrides := override.NewStore()
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, service.APIOptions{Overrides: overrides})
+	tlsManager := tls.NewManager()
+
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
+
+	buildConfig := func(privateServer string, opts ...func(*dynamic.Router) string) *runtime.Configuration {
+		routers := []func(*dynamic.Router) string{
+			th.WithRouter("foo@file",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar"),
+				th.WithRouterMiddlewares("missing"),
+				th.WithRule("Path(`/foo`)")),
+			th.WithRouter("baz@file",
+				th.WithEntryPoints("private"),
+				th.WithServiceName("qux"),
+				th.WithRule("Path(`/baz`)")),
+		}
+
+		return runtime.NewConfig(dynamic.Configuration{HTTP: th.BuildConfiguration(
+			th.WithRouters(append(routers, opts...)...),
+			th.WithLoadBalancerServices(
+				th.WithService("bar@file", th.WithServers(th.WithServer("http://127.0.0.1:8080"))),
+				th.WithService("qux@file", th.WithServers(th.WithServer(privateServer))),
+			),
+		)})
+	}
+
+	routers, _ := factory.CreateRouters(buildConfig("http://127.0.0.1:8081"))
+	assert.Len(t, routers, 3)
+
+	// Unchanged configuration: all the handlers are kept, along with the state of the routers.
+	rtConf := buildConfig("http://127.0.0.1:8081")
+	routers, _ = factory.CreateRouters(rtConf)
+	assert.Empty(t, routers)
+	assert.Equal(t, runtime.StatusDisabled, rtConf.Routers["foo@file"].Status)
+	assert.Equal(t, []string{"web"}, rtConf.Routers["foo@file"].Using)
+	assert.Equal(t, []string{"foo@file"}, rtConf.Services["bar@file"].UsedBy)
+
+	// Changed service: only the entry point of its router is rebuilt.
+	routers, _ = factory.CreateRouters(buildConfig("http://127.0.0.1:8082"))
+	assert.Len(t, routers, 1)
+	assert.Contains(t, routers, "private")
+
+	// New router shared by two entry points: both are rebuilt together.
+	routers, _ = factory.CreateRouters(buildConfig("http://127.0.0.1:8082",
+		th.WithRouter("shared@file",
+			th.WithEntryPoints("web", "admin"),
+			th.WithServiceName("bar"),
+			th.WithRule("Path(`/shared`)"))))
+	assert.Len(t, routers, 2)
+	assert.Contains(t, routers, "web")
+	assert.Contains(t, routers, "admin")
+
+	// Changed TLS configuration: all the entry points are rebuilt.
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]tls.Options{"default": {MinVersion: "VersionTLS12"}}, nil)
+	routers, _ = factory.CreateRouters(buildConfig("http://127.0.0.1:8082",
+		th.WithRouter("shared@file",
+			th.WithEntryPoints("web", "admin"),
+			th.WithServiceName("bar"),
+			th.WithRule("Path(`/shared`)"))))
+	assert.Len(t, routers, 3)
+
+	// Reload requested through the overrides: all the entry points are rebuilt, even if the configuration is unchanged.
+	overrides.Reload()
+	routers, _ = factory.CreateRouters(buildConfig("http://127.0.0.1:8082",
+		th.WithRouter("shared@file",
+			th.WithEntryPoints("web", "admin"),
+			th.WithServiceName("bar"),
+			th.WithRule("Path(`/shared`)"))))
+	assert.Len(t, routers, 3)
+}
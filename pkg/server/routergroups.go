@@ -0,0 +1,425 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service"
+)
+
+// apiServiceName is the name of the internal service exposing the whole runtime configuration.
+const apiServiceName = "api@internal"
+
+// entryPointGroup is a set of entry points sharing routers, whose handlers are built together.
+type entryPointGroup struct {
+	entryPoints []string
+	config      *entryPointsConfig
+	// fingerprint identifies the configuration of the group, and is empty when the handlers must always be rebuilt.
+	fingerprint string
+	// serviceManager is the service manager which built the handlers of the group.
+	serviceManager *service.InternalHandlers
+}
+
+func (g *entryPointGroup) key() string {
+	return strings.Join(g.entryPoints, ",")
+}
+
+// groupEntryPoints splits the entry points into groups of entry points sharing routers.
+func groupEntryPoints(conf *runtime.Configuration, entryPoints []string) [][]string {
+	parents := make(map[string]string, len(entryPoints))
+	for _, entryPoint := range entryPoints {
+		parents[entryPoint] = entryPoint
+	}
+
+	find := func(entryPoint string) string {
+		for parents[entryPoint] != entryPoint {
+			parents[entryPoint] = parents[parents[entryPoint]]
+			entryPoint = parents[entryPoint]
+		}
+		return entryPoint
+	}
+
+	union := func(names []string) {
+		var root string
+		for _, name := range names {
+			if _, ok := parents[name]; !ok {
+				continue
+			}
+
+			if root == "" {
+				root = find(name)
+				continue
+			}
+
+			parents[find(name)] = root
+		}
+	}
+
+	for _, rt := range conf.Routers {
+		union(rt.EntryPoints)
+	}
+
+	for _, rt := range conf.TCPRouters {
+		// The TCP routers without entry points are attached to all of them.
+		if len(rt.EntryPoints) == 0 {
+			union(entryPoints)
+			continue
+		}
+
+		union(rt.EntryPoints)
+	}
+
+	byRoot := make(map[string][]string)
+	for _, entryPoint := range entryPoints {
+		root := find(entryPoint)
+		byRoot[root] = append(byRoot[root], entryPoint)
+	}
+
+	groups := make([][]string, 0, len(byRoot))
+	for _, group := range byRoot {
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0] < groups[j][0]
+	})
+
+	return groups
+}
+
+// entryPointsConfig is the part of the configuration the handlers of a group of entry points depend on:
+// the routers of the entry points, and the middlewares and services these routers reach.
+// The missing elements are recorded as well, with a nil configuration.
+type entryPointsConfig struct {
+	EntryPoints    []string                          `json:"entryPoints"`
+	Generations    []uint64                          `json:"generations"`
+	Routers        map[string]*dynamic.Router        `json:"routers"`
+	Middlewares    map[string]*dynamic.Middleware    `json:"middlewares"`
+	Services       map[string]*dynamic.Service       `json:"services"`
+	TCPRouters     map[string]*dynamic.TCPRouter     `json:"tcpRouters"`
+	TCPMiddlewares map[string]*dynamic.TCPMiddleware `json:"tcpMiddlewares"`
+	TCPServices    map[string]*dynamic.TCPService    `json:"tcpServices"`
+
+	// usesAPI reports whether the handlers expose the whole runtime configuration through the API.
+	usesAPI bool
+}
+
+// newEntryPointsConfig collects the configuration of the given entry points.
+// The generations are the versions of the shared resources used by the handlers, such as the TLS configurations.
+func newEntryPointsConfig(conf *runtime.Configuration, entryPoints []string, generations []uint64) *entryPointsConfig {
+	cfg := &entryPointsConfig{
+		EntryPoints:    entryPoints,
+		Generations:    generations,
+		Routers:        make(map[string]*dynamic.Router),
+		Middlewares:    make(map[string]*dynamic.Middleware),
+		Services:       make(map[string]*dynamic.Service),
+		TCPRouters:     make(map[string]*dynamic.TCPRouter),
+		TCPMiddlewares: make(map[string]*dynamic.TCPMiddleware),
+		TCPServices:    make(map[string]*dynamic.TCPService),
+	}
+
+	for name, rt := range conf.Routers {
+		if !containsAny(entryPoints, rt.EntryPoints) {
+			continue
+		}
+
+		cfg.Routers[name] = rt.Router
+
+		for _, middleware := range rt.Middlewares {
+			cfg.addMiddleware(conf, qualifiedName(name, middleware))
+		}
+
+		cfg.addService(conf, qualifiedName(name, rt.Service))
+	}
+
+	for name, rt := range conf.TCPRouters {
+		if len(rt.EntryPoints) > 0 && !containsAny(entryPoints, rt.EntryPoints) {
+			continue
+		}
+
+		cfg.TCPRouters[name] = rt.TCPRouter
+
+		for _, middleware := range rt.Middlewares {
+			cfg.addTCPMiddleware(conf, qualifiedName(name, middleware))
+		}
+
+		cfg.addTCPService(conf, qualifiedName(name, rt.Service))
+	}
+
+	return cfg
+}
+
+// fingerprint returns a hash of the configuration,
+// or an empty string when the handlers depend on the whole configuration.
+func (c *entryPointsConfig) fingerprint() string {
+	if c.usesAPI {
+		return ""
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to compute the fingerprint of the entry points %v: %v", c.EntryPoints, err)
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *entryPointsConfig) addMiddleware(conf *runtime.Configuration, name string) {
+	if _, ok := c.Middlewares[name]; ok {
+		return
+	}
+
+	mi, ok := conf.Middlewares[name]
+	if !ok {
+		c.Middlewares[name] = nil
+		return
+	}
+
+	c.Middlewares[name] = mi.Middleware
+	if mi.Middleware == nil {
+		return
+	}
+
+	if mi.Chain != nil {
+		for _, middleware := range mi.Chain.Middlewares {
+			c.addMiddleware(conf, qualifiedName(name, middleware))
+		}
+	}
+
+	for _, serviceName := range middlewareServices(mi.Middleware) {
+		c.addService(conf, qualifiedName(name, serviceName))
+	}
+}
+
+func (c *entryPointsConfig) addService(conf *runtime.Configuration, name string) {
+	if name == "" {
+		return
+	}
+
+	if name == apiServiceName {
+		c.usesAPI = true
+	}
+
+	if _, ok := c.Services[name]; ok {
+		return
+	}
+
+	si, ok := conf.Services[name]
+	if !ok {
+		c.Services[name] = nil
+		return
+	}
+
+	c.Services[name] = si.Service
+	if si.Service == nil {
+		return
+	}
+
+	if si.Weighted != nil {
+		for _, child := range si.Weighted.Services {
+			c.addService(conf, qualifiedName(name, child.Name))
+		}
+	}
+
+	if si.Mirroring != nil {
+		c.addService(conf, qualifiedName(name, si.Mirroring.Service))
+		for _, mirror := range si.Mirroring.Mirrors {
+			c.addService(conf, qualifiedName(name, mirror.Name))
+		}
+	}
+}
+
+func (c *entryPointsConfig) addTCPMiddleware(conf *runtime.Configuration, name string) {
+	if _, ok := c.TCPMiddlewares[name]; ok {
+		return
+	}
+
+	mi, ok := conf.TCPMiddlewares[name]
+	if !ok {
+		c.TCPMiddlewares[name] = nil
+		return
+	}
+
+	c.TCPMiddlewares[name] = mi.TCPMiddleware
+}
+
+func (c *entryPointsConfig) addTCPService(conf *runtime.Configuration, name string) {
+	if name == "" {
+		return
+	}
+
+	if _, ok := c.TCPServices[name]; ok {
+		return
+	}
+
+	si, ok := conf.TCPServices[name]
+	if !ok {
+		c.TCPServices[name] = nil
+		return
+	}
+
+	c.TCPServices[name] = si.TCPService
+	if si.TCPService == nil || si.Weighted == nil {
+		return
+	}
+
+	for _, child := range si.Weighted.Services {
+		c.addTCPService(conf, qualifiedName(name, child.Name))
+	}
+}
+
+// middlewareServices returns the names of the services called by a middleware:
+// the values of its "service" fields, and of its maps of services, at any depth of its configuration.
+func middlewareServices(middleware *dynamic.Middleware) []string {
+	var names []string
+	collectServices(reflect.ValueOf(middleware), "", &names)
+
+	return names
+}
+
+// collectServices appends the service names found in the given value, which is the one of the field with the given JSON name.
+func collectServices(value reflect.Value, name string, names *[]string) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			collectServices(value.Elem(), name, names)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			fieldName := strings.Split(field.Tag.Get("json"), ",")[0]
+			if fieldName == "" {
+				fieldName = field.Name
+			}
+
+			collectServices(value.Field(i), fieldName, names)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collectServices(value.Index(i), "", names)
+		}
+
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		isServices := strings.HasSuffix(name, "Services") && value.Type().Elem().Kind() == reflect.String
+		for _, key := range keys {
+			if isServices {
+				*names = append(*names, value.MapIndex(key).String())
+				continue
+			}
+
+			collectServices(value.MapIndex(key), "", names)
+		}
+
+	case reflect.String:
+		if name == "service" {
+			*names = append(*names, value.String())
+		}
+	}
+}
+
+// reuseRuntimeState carries the state of the previous build of the handlers of a group of entry points over to the new configuration,
+// as these handlers are kept.
+// The service infos are kept as they are, since the kept handlers report the health of the servers to them.
+func reuseRuntimeState(conf, previous *runtime.Configuration, cfg *entryPointsConfig) {
+	for name := range cfg.Routers {
+		current, old := conf.Routers[name], previous.Routers[name]
+		if current == nil || old == nil {
+			continue
+		}
+
+		current.Err = append([]string(nil), old.Err...)
+		current.Status = old.Status
+		current.Using = append([]string(nil), old.Using...)
+	}
+
+	for name := range cfg.Middlewares {
+		current, old := conf.Middlewares[name], previous.Middlewares[name]
+		if current == nil || old == nil {
+			continue
+		}
+
+		current.Err = append([]string(nil), old.Err...)
+		current.Status = old.Status
+	}
+
+	for name := range cfg.Services {
+		if conf.Services[name] == nil || previous.Services[name] == nil {
+			continue
+		}
+
+		conf.Services[name] = previous.Services[name]
+		conf.Services[name].UsedBy = nil
+	}
+
+	for name := range cfg.TCPRouters {
+		current, old := conf.TCPRouters[name], previous.TCPRouters[name]
+		if current == nil || old == nil {
+			continue
+		}
+
+		current.Err = append([]string(nil), old.Err...)
+		current.Status = old.Status
+		current.Using = append([]string(nil), old.Using...)
+	}
+
+	for name := range cfg.TCPServices {
+		current, old := conf.TCPServices[name], previous.TCPServices[name]
+		if current == nil || old == nil {
+			continue
+		}
+
+		current.Err = append([]string(nil), old.Err...)
+		current.Status = old.Status
+	}
+}
+
+// qualifiedName returns the fully qualified name of an element referenced by another one,
+// which provider is the default one.
+func qualifiedName(referrer, name string) string {
+	if name == "" || strings.Contains(name, "@") {
+		return name
+	}
+
+	parts := strings.Split(referrer, "@")
+	if len(parts) < 2 {
+		return name
+	}
+
+	return provider.MakeQualifiedName(parts[1], name)
+}
+
+func containsAny(entryPoints, names []string) bool {
+	for _, name := range names {
+		for _, entryPoint := range entryPoints {
+			if name == entryPoint {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func Test_middlewareServices(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		middleware *dynamic.Middleware
+		expected   []string
+	}{
+		{
+			desc:       "no service",
+			middleware: &dynamic.Middleware{Headers: &dynamic.Headers{}},
+		},
+		{
+			desc: "errors",
+			middleware: &dynamic.Middleware{Errors: &dynamic.ErrorPage{
+				Service:     "error",
+				StatusPages: []dynamic.StatusErrorPage{{Service: "not-found"}},
+			}},
+			expected: []string{"error", "not-found"},
+		},
+		{
+			desc: "experiment",
+			middleware: &dynamic.Middleware{Experiment: &dynamic.Experiment{
+				Variants: []dynamic.ExperimentVariant{{Service: "a"}, {Service: "b"}},
+			}},
+			expected: []string{"a", "b"},
+		},
+		{
+			desc: "geoip",
+			middleware: &dynamic.Middleware{GeoIP: &dynamic.GeoIP{
+				CountryServices: map[string]string{"FR": "fr", "DE": "de"},
+			}},
+			expected: []string{"de", "fr"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, middlewareServices(test.middleware))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubernetesClient reads the keys of the Kubernetes secrets.
+type kubernetesClient struct {
+	clientset  kubernetes.Interface
+	namespaces map[string]struct{}
+}
+
+func newKubernetesClient(config *static.KubernetesSecrets) (*kubernetesClient, error) {
+	logger := log.WithoutContext()
+
+	var restConfig *rest.Config
+	var err error
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "":
+		logger.Info("Creating in-cluster secrets client")
+
+		restConfig, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create in-cluster configuration: %w", err)
+		}
+
+		if config.Endpoint != "" {
+			restConfig.Host = config.Endpoint
+		}
+
+	case os.Getenv("KUBECONFIG") != "":
+		logger.Infof("Creating cluster-external secrets client from KUBECONFIG %s", os.Getenv("KUBECONFIG"))
+
+		restConfig, err = clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		logger.Info("Creating cluster-external secrets client")
+
+		if config.Endpoint == "" {
+			return nil, errors.New("endpoint missing for external cluster client")
+		}
+
+		restConfig = &rest.Config{
+			Host:        config.Endpoint,
+			BearerToken: config.Token,
+		}
+
+		if config.CertAuthFilePath != "" {
+			caData, err := ioutil.ReadFile(config.CertAuthFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file %s: %w", config.CertAuthFilePath, err)
+			}
+
+			restConfig.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+		}
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &kubernetesClient{
+		clientset:  clientset,
+		namespaces: make(map[string]struct{}),
+	}
+
+	for _, namespace := range config.Namespaces {
+		client.namespaces[namespace] = struct{}{}
+	}
+
+	return client, nil
+}
+
+// get returns a key of a secret, referenced as namespace/name/key.
+func (c *kubernetesClient) get(ctx context.Context, name string) (string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid Kubernetes reference %q, namespace/name/key is expected", name)
+	}
+
+	namespace, secretName, key := parts[0], parts[1], parts[2]
+
+	if _, ok := c.namespaces[namespace]; len(c.namespaces) > 0 && !ok {
+		return "", fmt.Errorf("the secrets of the namespace %s cannot be referenced", namespace)
+	}
+
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read the Kubernetes secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("the Kubernetes secret %s/%s has no key %s", namespace, secretName, key)
+	}
+
+	return string(value), nil
+}
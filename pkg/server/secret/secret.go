@@ -0,0 +1,307 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/interpolate"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// kinds are the kinds of the secret references.
+var kinds = []string{"env", "file", "vault", "kubernetes"}
+
+// reference is a secret reference, such as ${vault:secret/data/app#password}.
+type reference struct {
+	kind string
+	name string
+}
+
+func (r reference) String() string {
+	return r.kind + ":" + r.name
+}
+
+// Resolver resolves the secret references of the middleware options,
+// and resolves them again periodically to follow the rotation of the secrets.
+type Resolver struct {
+	providers       map[string]struct{}
+	refreshInterval time.Duration
+
+	vault      *vaultClient
+	kubernetes *kubernetesClient
+
+	mu         sync.RWMutex
+	values     map[reference]string
+	generation uint64
+	listeners  []func()
+}
+
+// New creates a Resolver.
+func New(config *static.Secrets) (*Resolver, error) {
+	r := &Resolver{
+		providers:       make(map[string]struct{}),
+		refreshInterval: time.Duration(config.RefreshInterval),
+		values:          make(map[reference]string),
+	}
+
+	for _, name := range config.Providers {
+		r.providers[name] = struct{}{}
+	}
+
+	if len(r.providers) == 0 {
+		log.WithoutContext().Warn("No provider is allowed to reference secrets, the secret references of the middlewares are not resolved")
+	}
+
+	if config.Vault != nil {
+		var err error
+		r.vault, err = newVaultClient(config.Vault)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the Vault client: %w", err)
+		}
+	}
+
+	if config.Kubernetes != nil {
+		var err error
+		r.kubernetes, err = newKubernetesClient(config.Kubernetes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the Kubernetes client: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// AddListener adds a listener called each time the value of a resolved secret changes.
+func (r *Resolver) AddListener(listener func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listeners = append(r.listeners, listener)
+}
+
+// Generation returns a number changing each time the value of a resolved secret changes.
+func (r *Resolver) Generation() uint64 {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.generation
+}
+
+// Middleware returns the options of the middleware with their secret references resolved.
+// The options are returned as is when they do not reference any secret,
+// or when the provider of the middleware is not allowed to reference secrets.
+func (r *Resolver) Middleware(middlewareName string, config *dynamic.Middleware) (*dynamic.Middleware, error) {
+	if r == nil || config == nil {
+		return config, nil
+	}
+
+	providerName, ok := r.provider(middlewareName)
+	if !ok {
+		return config, nil
+	}
+
+	interpolator := interpolate.NewInterpolator(kinds, func(kind, name string) (string, error) {
+		if err := checkReference(providerName, kind); err != nil {
+			return "", err
+		}
+
+		return r.get(kind, name)
+	})
+
+	resolved := config.DeepCopy()
+	if err := interpolator.Interpolate(resolved); err != nil {
+		return nil, fmt.Errorf("unable to resolve the secrets: %w", err)
+	}
+
+	if reflect.DeepEqual(resolved, config) {
+		return config, nil
+	}
+
+	return resolved, nil
+}
+
+// Prune forgets the resolved secret references which are not referenced by the given middlewares anymore,
+// so that they are not refreshed anymore.
+func (r *Resolver) Prune(middlewares map[string]*runtime.MiddlewareInfo) {
+	if r == nil {
+		return
+	}
+
+	referenced := make(map[reference]struct{})
+	collector := interpolate.NewInterpolator(kinds, func(kind, name string) (string, error) {
+		referenced[reference{kind: kind, name: name}] = struct{}{}
+		return "", nil
+	})
+
+	for _, middleware := range middlewares {
+		if middleware == nil || middleware.Middleware == nil {
+			continue
+		}
+
+		if err := collector.Interpolate(middleware.Middleware.DeepCopy()); err != nil {
+			log.WithoutContext().Errorf("Unable to list the secret references of the middlewares: %v", err)
+			return
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ref := range r.values {
+		if _, ok := referenced[ref]; !ok {
+			delete(r.values, ref)
+		}
+	}
+}
+
+// Run resolves the secret references periodically, until the context is done.
+func (r *Resolver) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// refresh resolves again the secret references, and notifies the listeners when a value changed.
+// A reference which cannot be resolved anymore keeps its previous value.
+func (r *Resolver) refresh(ctx context.Context) {
+	r.mu.RLock()
+	refs := make([]reference, 0, len(r.values))
+	for ref := range r.values {
+		refs = append(refs, ref)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+
+	changed := make(map[reference]string)
+	for _, ref := range refs {
+		value, err := r.fetch(ctx, ref)
+		if err != nil {
+			log.WithoutContext().Errorf("Unable to refresh the secret %s, its previous value is kept: %v", ref, err)
+			continue
+		}
+
+		r.mu.RLock()
+		previous := r.values[ref]
+		r.mu.RUnlock()
+
+		if value != previous {
+			changed[ref] = value
+			log.WithoutContext().Infof("The secret %s has changed, the middlewares referencing it are rebuilt", ref)
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	for ref, value := range changed {
+		r.values[ref] = value
+	}
+	r.generation++
+	listeners := r.listeners
+	r.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// get returns the value of a secret reference, which is fetched the first time it is resolved.
+// The resolved references are then refreshed by Run.
+func (r *Resolver) get(kind, name string) (string, error) {
+	ref := reference{kind: kind, name: name}
+
+	r.mu.RLock()
+	value, ok := r.values[ref]
+	r.mu.RUnlock()
+
+	if ok {
+		return value, nil
+	}
+
+	value, err := r.fetch(context.Background(), ref)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	r.values[ref] = value
+	r.mu.Unlock()
+
+	return value, nil
+}
+
+func (r *Resolver) fetch(ctx context.Context, ref reference) (string, error) {
+	switch ref.kind {
+	case "vault":
+		if r.vault == nil {
+			return "", fmt.Errorf("unable to resolve %s: the Vault secrets are not enabled", ref)
+		}
+
+		return r.vault.get(ctx, ref.name)
+
+	case "kubernetes":
+		if r.kubernetes == nil {
+			return "", fmt.Errorf("unable to resolve %s: the Kubernetes secrets are not enabled", ref)
+		}
+
+		return r.kubernetes.get(ctx, ref.name)
+
+	default:
+		return interpolate.Resolve(ref.kind, ref.name)
+	}
+}
+
+// provider returns the name of the provider of the middleware, and whether it is allowed to reference secrets.
+func (r *Resolver) provider(middlewareName string) (string, bool) {
+	parts := strings.Split(middlewareName, "@")
+	if len(parts) < 2 {
+		return "", false
+	}
+
+	providerName := parts[len(parts)-1]
+	_, ok := r.providers[providerName]
+
+	return providerName, ok
+}
+
+// checkReference checks that the middlewares of the provider can reference the secrets of the kind.
+// The environment variables and the files of Traefik can only be referenced by the file provider,
+// and the Kubernetes secrets by the file provider, and by the Kubernetes CRD provider, which binds them to the namespace of the middlewares.
+func checkReference(providerName, kind string) error {
+	switch kind {
+	case "env", "file":
+		if providerName != "file" {
+			return fmt.Errorf("the middlewares of the provider %s cannot reference %s secrets, only the ones of the file provider can", providerName, kind)
+		}
+
+	case "kubernetes":
+		if providerName != "file" && providerName != "kubernetescrd" {
+			return fmt.Errorf("the middlewares of the provider %s cannot reference Kubernetes secrets, only the ones of the file and kubernetescrd providers can", providerName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package secret
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestResolver(t *testing.T, providers ...string) *Resolver {
+	t.Helper()
+
+	resolver, err := New(&static.Secrets{Providers: providers})
+	require.NoError(t, err)
+
+	resolver.kubernetes = &kubernetesClient{
+		clientset: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "auth"},
+			Data:       map[string][]byte{"users": []byte("test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/")},
+		}),
+		namespaces: map[string]struct{}{"default": {}},
+	}
+
+	return resolver
+}
+
+func TestResolver_Middleware(t *testing.T) {
+	require.NoError(t, os.Setenv("TRAEFIK_SECRET_TEST", "token"))
+	t.Cleanup(func() { _ = os.Unsetenv("TRAEFIK_SECRET_TEST") })
+
+	testCases := []struct {
+		desc           string
+		providers      []string
+		middlewareName string
+		config         *dynamic.Middleware
+		expected       *dynamic.Middleware
+		expectedError  bool
+	}{
+		{
+			desc:           "without reference",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"test:secret"}}},
+			expected:       &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"test:secret"}}},
+		},
+		{
+			desc:           "Kubernetes and environment references",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config: &dynamic.Middleware{Headers: &dynamic.Headers{
+				CustomRequestHeaders: map[string]string{
+					"Authorization": "Bearer ${env:TRAEFIK_SECRET_TEST}",
+					"X-Users":       "${kubernetes:default/auth/users}",
+				},
+			}},
+			expected: &dynamic.Middleware{Headers: &dynamic.Headers{
+				CustomRequestHeaders: map[string]string{
+					"Authorization": "Bearer token",
+					"X-Users":       "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/",
+				},
+			}},
+		},
+		{
+			desc:           "escaped reference",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"$${env:TRAEFIK_SECRET_TEST}"}}},
+			expected:       &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+		},
+		{
+			desc:           "no provider allowed",
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+			expected:       &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+		},
+		{
+			desc:           "provider not allowed",
+			providers:      []string{"kubernetescrd"},
+			middlewareName: "auth@docker",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+			expected:       &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+		},
+		{
+			desc:           "provider allowed",
+			providers:      []string{"kubernetescrd"},
+			middlewareName: "default-auth@kubernetescrd",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:default/auth/users}"}}},
+			expected:       &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}}},
+		},
+		{
+			desc:           "environment reference of another provider than the file one",
+			providers:      []string{"kubernetescrd"},
+			middlewareName: "default-auth@kubernetescrd",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${env:TRAEFIK_SECRET_TEST}"}}},
+			expectedError:  true,
+		},
+		{
+			desc:           "file reference of another provider than the file one",
+			providers:      []string{"docker"},
+			middlewareName: "auth@docker",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${file:/etc/passwd}"}}},
+			expectedError:  true,
+		},
+		{
+			desc:           "Kubernetes reference of a provider without namespaces",
+			providers:      []string{"docker"},
+			middlewareName: "auth@docker",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:default/auth/users}"}}},
+			expectedError:  true,
+		},
+		{
+			desc:           "namespace not allowed",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:kube-system/auth/users}"}}},
+			expectedError:  true,
+		},
+		{
+			desc:           "missing key",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:default/auth/password}"}}},
+			expectedError:  true,
+		},
+		{
+			desc:           "Vault not enabled",
+			providers:      []string{"file"},
+			middlewareName: "auth@file",
+			config:         &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${vault:secret/data/auth#users}"}}},
+			expectedError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := newTestResolver(t, test.providers...)
+
+			result, err := resolver.Middleware(test.middlewareName, test.config)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+
+			if result != test.config {
+				assert.NotEqual(t, test.expected, test.config, "the options of the middleware must not be modified")
+			}
+		})
+	}
+}
+
+func TestResolver_refresh(t *testing.T) {
+	resolver := newTestResolver(t, "file")
+
+	var notified int
+	resolver.AddListener(func() { notified++ })
+
+	config := &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:default/auth/users}"}}}
+
+	result, err := resolver.Middleware("auth@file", config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"}, result.BasicAuth.Users)
+
+	resolver.refresh(context.Background())
+	assert.Equal(t, uint64(0), resolver.Generation())
+	assert.Equal(t, 0, notified)
+
+	secrets := resolver.kubernetes.clientset.CoreV1().Secrets("default")
+	_, err = secrets.Update(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "auth"},
+		Data:       map[string][]byte{"users": []byte("test:rotated")},
+	}, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	resolver.refresh(context.Background())
+	assert.Equal(t, uint64(1), resolver.Generation())
+	assert.Equal(t, 1, notified)
+
+	result, err = resolver.Middleware("auth@file", config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test:rotated"}, result.BasicAuth.Users)
+
+	// A secret which cannot be read anymore keeps its previous value.
+	require.NoError(t, secrets.Delete(context.Background(), "auth", metav1.DeleteOptions{}))
+
+	resolver.refresh(context.Background())
+	assert.Equal(t, uint64(1), resolver.Generation())
+
+	result, err = resolver.Middleware("auth@file", config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test:rotated"}, result.BasicAuth.Users)
+}
+
+func TestResolver_Prune(t *testing.T) {
+	require.NoError(t, os.Setenv("TRAEFIK_SECRET_TEST", "token"))
+	t.Cleanup(func() { _ = os.Unsetenv("TRAEFIK_SECRET_TEST") })
+
+	resolver := newTestResolver(t, "file")
+
+	auth := &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{Users: []string{"${kubernetes:default/auth/users}"}}}
+	headers := &dynamic.Middleware{Headers: &dynamic.Headers{
+		CustomRequestHeaders: map[string]string{"Authorization": "Bearer ${env:TRAEFIK_SECRET_TEST}"},
+	}}
+
+	_, err := resolver.Middleware("auth@file", auth)
+	require.NoError(t, err)
+
+	_, err = resolver.Middleware("headers@file", headers)
+	require.NoError(t, err)
+
+	assert.Len(t, resolver.values, 2)
+
+	resolver.Prune(map[string]*runtime.MiddlewareInfo{
+		"auth@file": {Middleware: auth},
+	})
+
+	assert.Equal(t, map[reference]string{
+		{kind: "kubernetes", name: "default/auth/users"}: "test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/",
+	}, resolver.values)
+}
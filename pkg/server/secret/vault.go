@@ -0,0 +1,106 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+// vaultClient reads the secrets of a Vault server through its HTTP API.
+type vaultClient struct {
+	endpoint  string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func newVaultClient(config *static.VaultSecrets) (*vaultClient, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the TLS configuration: %w", err)
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &vaultClient{
+		endpoint:  strings.TrimSuffix(config.Endpoint, "/"),
+		token:     config.Token,
+		namespace: config.Namespace,
+		client:    &http.Client{Timeout: 10 * time.Second, Transport: transport},
+	}, nil
+}
+
+// get returns a field of a secret, referenced as path#field.
+// The fields of the secrets of the KV version 2 engines, read from their data/ path, are looked up in their data.
+func (c *vaultClient) get(ctx context.Context, name string) (string, error) {
+	i := strings.LastIndex(name, "#")
+	if i <= 0 || i == len(name)-1 {
+		return "", fmt.Errorf("invalid Vault reference %q, path#field is expected", name)
+	}
+
+	path, field := strings.TrimPrefix(name[:i], "/"), name[i+1:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the Vault secret %s: %w", path, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to read the Vault secret %s: unexpected status code %d", path, resp.StatusCode)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("unable to decode the Vault secret %s: %w", path, err)
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	value, ok := data[field]
+	if !ok || value == nil {
+		return "", fmt.Errorf("the Vault secret %s has no field %s", path, field)
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.New("unable to encode the field of the Vault secret")
+	}
+
+	return string(raw), nil
+}
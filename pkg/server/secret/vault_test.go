@@ -0,0 +1,88 @@
+package secret
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestVaultClient_get(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Vault-Token") != "token" || req.Header.Get("X-Vault-Namespace") != "traefik" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/v1/secret/data/auth":
+			_, _ = rw.Write([]byte(`{"data":{"data":{"users":"test:secret","port":8080},"metadata":{"version":2}}}`))
+		case "/v1/kv/auth":
+			_, _ = rw.Write([]byte(`{"data":{"users":"test:secret"}}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newVaultClient(&static.VaultSecrets{Endpoint: server.URL + "/", Token: "token", Namespace: "traefik"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		name          string
+		expected      string
+		expectedError bool
+	}{
+		{
+			desc:     "KV version 2",
+			name:     "secret/data/auth#users",
+			expected: "test:secret",
+		},
+		{
+			desc:     "KV version 1",
+			name:     "/kv/auth#users",
+			expected: "test:secret",
+		},
+		{
+			desc:     "non-string field",
+			name:     "secret/data/auth#port",
+			expected: "8080",
+		},
+		{
+			desc:          "missing field",
+			name:          "secret/data/auth#password",
+			expectedError: true,
+		},
+		{
+			desc:          "missing secret",
+			name:          "secret/data/missing#users",
+			expectedError: true,
+		},
+		{
+			desc:          "missing field name",
+			name:          "secret/data/auth",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := client.get(context.Background(), test.name)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
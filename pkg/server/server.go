@@ -11,6 +11,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 )
 
@@ -23,6 +24,9 @@ type Server struct {
 
 	accessLoggerMiddleware *accesslog.Handler
 
+	drainer      *drain.Manager
+	drainSignals map[os.Signal]struct{}
+
 	signals  chan os.Signal
 	stopChan chan bool
 
@@ -48,6 +52,28 @@ func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsU
 	return srv
 }
 
+// SetDrainer sets the manager draining the entry points, when receiving one of the given signals.
+func (s *Server) SetDrainer(drainer *drain.Manager, signalNames []string) error {
+	drainSignals := make(map[os.Signal]struct{})
+	for _, name := range signalNames {
+		sig, err := parseDrainSignal(name)
+		if err != nil {
+			return err
+		}
+
+		drainSignals[sig] = struct{}{}
+	}
+
+	s.drainer = drainer
+	s.drainSignals = drainSignals
+
+	for sig := range drainSignals {
+		signal.Notify(s.signals, sig)
+	}
+
+	return nil
+}
+
 // Start starts the server and Stop/Close it when context is Done.
 func (s *Server) Start(ctx context.Context) {
 	go func() {
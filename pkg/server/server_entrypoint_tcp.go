@@ -7,7 +7,9 @@ import (
 	stdlog "log"
 	"net"
 	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,6 +21,8 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+	"github.com/traefik/traefik/v2/pkg/server/resources"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 	"golang.org/x/net/http2"
@@ -127,6 +131,12 @@ type TCPEntryPoint struct {
 	httpsServer            *httpServer
 
 	http3Server *http3server
+
+	// draining is set once the entry point starts draining.
+	draining int32
+
+	// resourceGuard sheds the new connections while the process resources are close to exhaustion.
+	resourceGuard *resources.Guard
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
@@ -198,6 +208,12 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 			return
 		}
 
+		if !e.resourceGuard.AcceptConnection() {
+			logger.Debugf("Closing the connection from %s, as the process resources are close to exhaustion", conn.RemoteAddr())
+			_ = conn.Close()
+			continue
+		}
+
 		writeCloser, err := writeCloser(conn)
 		if err != nil {
 			panic(err)
@@ -230,8 +246,9 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	logger := log.FromContext(ctx)
 
+	// A draining entry point does not accept incoming requests anymore.
 	reqAcceptGraceTimeOut := time.Duration(e.transportConfiguration.LifeCycle.RequestAcceptGraceTimeout)
-	if reqAcceptGraceTimeOut > 0 {
+	if reqAcceptGraceTimeOut > 0 && atomic.LoadInt32(&e.draining) == 0 {
 		logger.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
 		time.Sleep(reqAcceptGraceTimeOut)
 	}
@@ -240,6 +257,42 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	ctx, cancel := context.WithTimeout(ctx, graceTimeOut)
 	logger.Debugf("Waiting %s seconds before killing connections.", graceTimeOut)
 
+	e.shutdown(ctx)
+	cancel()
+}
+
+// SetResourceGuard sets the guard shedding the new connections, before the entry point is started.
+func (e *TCPEntryPoint) SetResourceGuard(guard *resources.Guard) {
+	e.resourceGuard = guard
+}
+
+// Drain stops accepting new connections,
+// and waits for the active requests and connections to finish until the context is done, when they are closed.
+func (e *TCPEntryPoint) Drain(ctx context.Context) {
+	atomic.StoreInt32(&e.draining, 1)
+
+	e.shutdown(ctx)
+}
+
+// ActiveConnections returns the number of active connections.
+func (e *TCPEntryPoint) ActiveConnections() int {
+	return e.tracker.count()
+}
+
+// Connections returns the active connections, sorted by ID.
+func (e *TCPEntryPoint) Connections() []connections.Info {
+	return e.tracker.Connections()
+}
+
+// CloseConnection closes the active connection with the given ID.
+func (e *TCPEntryPoint) CloseConnection(id uint64) error {
+	return e.tracker.CloseConnection(id)
+}
+
+// shutdown stops the servers and the TCP connections, and closes them once the context is done.
+func (e *TCPEntryPoint) shutdown(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
 	var wg sync.WaitGroup
 
 	shutdownServer := func(server stoppable) {
@@ -292,7 +345,6 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	}
 
 	wg.Wait()
-	cancel()
 }
 
 // SwitchRouter switches the TCP router handler.
@@ -409,7 +461,28 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 }
 
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+	var listeners []net.Listener
+	for _, address := range entryPoint.GetAddresses() {
+		listener, err := buildAddressListener(ctx, entryPoint, address)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 1 {
+		return listeners[0], nil
+	}
+
+	return newMultiListener(listeners), nil
+}
+
+func buildAddressListener(ctx context.Context, entryPoint *static.EntryPoint, address string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		return nil, fmt.Errorf("error opening listener: %w", err)
 	}
@@ -417,30 +490,142 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
 
 	if entryPoint.ProxyProtocol != nil {
-		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
+		proxyListener, err := buildProxyProtocolListener(ctx, entryPoint, listener)
 		if err != nil {
+			_ = listener.Close()
 			return nil, fmt.Errorf("error creating proxy protocol listener: %w", err)
 		}
+		return proxyListener, nil
 	}
 	return listener, nil
 }
 
+var errListenerClosed = errors.New("listener closed")
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener merges the connections accepted by several listeners,
+// to serve the addresses of an entry point with the same servers.
+type multiListener struct {
+	listeners []net.Listener
+
+	startOnce sync.Once
+	accepted  chan acceptResult
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	return &multiListener{
+		listeners: listeners,
+		accepted:  make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+}
+
+// Accept returns the next connection accepted by any of the listeners.
+// The listeners only start accepting connections on the first call.
+func (m *multiListener) Accept() (net.Conn, error) {
+	m.startOnce.Do(func() {
+		for _, listener := range m.listeners {
+			go m.accept(listener)
+		}
+	})
+
+	select {
+	case res := <-m.accepted:
+		return res.conn, res.err
+	case <-m.closed:
+		return nil, errListenerClosed
+	}
+}
+
+func (m *multiListener) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+
+		select {
+		case m.accepted <- acceptResult{conn: conn, err: err}:
+		case <-m.closed:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+
+		var netErr net.Error
+		if err != nil && (!errors.As(err, &netErr) || !netErr.Temporary()) {
+			return
+		}
+	}
+}
+
+// Close closes all the listeners.
+func (m *multiListener) Close() error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.closed)
+
+		for _, listener := range m.listeners {
+			if lErr := listener.Close(); lErr != nil && err == nil {
+				err = lErr
+			}
+		}
+	})
+
+	return err
+}
+
+// Addr returns the address of the first listener.
+func (m *multiListener) Addr() net.Addr {
+	return m.listeners[0].Addr()
+}
+
 func newConnectionTracker() *connectionTracker {
 	return &connectionTracker{
-		conns: make(map[net.Conn]struct{}),
+		conns: make(map[net.Conn]*connectionInfo),
 	}
 }
 
 type connectionTracker struct {
-	conns map[net.Conn]struct{}
-	lock  sync.RWMutex
+	conns  map[net.Conn]*connectionInfo
+	lastID uint64
+	lock   sync.RWMutex
 }
 
-// AddConnection add a connection in the tracked connections list.
-func (c *connectionTracker) AddConnection(conn net.Conn) {
+// connectionInfo describes a tracked connection, for the connection table of the entry point.
+type connectionInfo struct {
+	id         uint64
+	clientAddr string
+	startedAt  time.Time
+
+	mu         sync.RWMutex
+	isTLS      bool
+	serverName string
+	router     string
+}
+
+// AddConnection add a connection in the tracked connections list, and returns its information.
+func (c *connectionTracker) AddConnection(conn net.Conn) *connectionInfo {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	c.conns[conn] = struct{}{}
+
+	c.lastID++
+	info := &connectionInfo{
+		id:        c.lastID,
+		startedAt: time.Now(),
+	}
+	if addr := conn.RemoteAddr(); addr != nil {
+		info.clientAddr = addr.String()
+	}
+
+	c.conns[conn] = info
+
+	return info
 }
 
 // RemoveConnection remove a connection from the tracked connections list.
@@ -451,9 +636,50 @@ func (c *connectionTracker) RemoveConnection(conn net.Conn) {
 }
 
 func (c *connectionTracker) isEmpty() bool {
+	return c.count() == 0
+}
+
+func (c *connectionTracker) count() int {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	return len(c.conns) == 0
+	return len(c.conns)
+}
+
+// Connections returns the tracked connections, sorted by ID.
+func (c *connectionTracker) Connections() []connections.Info {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	now := time.Now()
+
+	conns := make([]connections.Info, 0, len(c.conns))
+	for _, info := range c.conns {
+		conns = append(conns, info.toInfo(now))
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].ID < conns[j].ID
+	})
+
+	return conns
+}
+
+// CloseConnection closes the tracked connection with the given ID.
+func (c *connectionTracker) CloseConnection(id uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for conn, info := range c.conns {
+		if info.id != id {
+			continue
+		}
+
+		delete(c.conns, conn)
+
+		return conn.Close()
+	}
+
+	return connections.ErrUnknownConnection
 }
 
 // Shutdown wait for the connection closing.
@@ -503,9 +729,7 @@ type httpServer struct {
 func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool) (*httpServer, error) {
 	httpSwitcher := middlewares.NewHandlerSwitcher(router.BuildDefaultHTTPRouter())
 
-	var handler http.Handler
-	var err error
-	handler, err = forwardedheaders.NewXForwarded(
+	xForwarded, err := forwardedheaders.NewXForwarded(
 		configuration.ForwardedHeaders.Insecure,
 		configuration.ForwardedHeaders.TrustedIPs,
 		httpSwitcher)
@@ -514,6 +738,13 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
+	xForwarded.SetForwarded(configuration.ForwardedHeaders.Forwarded)
+	if configuration.ForwardedHeaders.ClientIP != nil {
+		xForwarded.SetClientIP(configuration.ForwardedHeaders.ClientIP.Depth)
+	}
+
+	var handler http.Handler = xForwarded
+
 	if withH2c {
 		handler = h2c.NewHandler(handler, &http2.Server{})
 	}
@@ -524,6 +755,7 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
 		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+		ConnContext:  middlewares.WithConn,
 	}
 
 	listener := newHTTPForwarder(ln)
@@ -541,19 +773,71 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 }
 
 func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
-	tracker.AddConnection(conn)
 	return &trackedConnection{
 		WriteCloser: conn,
 		tracker:     tracker,
+		info:        tracker.AddConnection(conn),
 	}
 }
 
 type trackedConnection struct {
 	tracker *connectionTracker
+	info    *connectionInfo
 	tcp.WriteCloser
 }
 
+// Unwrap returns the tracked connection, whose bytes are transferred unchanged.
+func (t *trackedConnection) Unwrap() tcp.WriteCloser {
+	return t.WriteCloser
+}
+
+// RecordRouting records whether the connection is a TLS one, and its server name.
+func (t *trackedConnection) RecordRouting(isTLS bool, serverName string) {
+	t.info.mu.Lock()
+	defer t.info.mu.Unlock()
+
+	t.info.isTLS = isTLS
+	t.info.serverName = serverName
+}
+
+// RecordRouter records the name of the TCP router handling the connection.
+func (t *trackedConnection) RecordRouter(name string) {
+	t.info.mu.Lock()
+	defer t.info.mu.Unlock()
+
+	t.info.router = name
+}
+
 func (t *trackedConnection) Close() error {
 	t.tracker.RemoveConnection(t.WriteCloser)
 	return t.WriteCloser.Close()
 }
+
+// toInfo returns the description of the connection in the connection table, at the given time.
+// The connections handled by the HTTP routers have no router, as their requests are routed separately.
+func (i *connectionInfo) toInfo(now time.Time) connections.Info {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var protocol string
+	switch {
+	case i.isTLS && i.router != "":
+		protocol = connections.ProtocolTLS
+	case i.isTLS:
+		protocol = connections.ProtocolHTTPS
+	case i.router != "":
+		protocol = connections.ProtocolTCP
+	default:
+		protocol = connections.ProtocolHTTP
+	}
+
+	return connections.Info{
+		ID:         i.id,
+		ClientAddr: i.clientAddr,
+		Protocol:   protocol,
+		SNI:        i.serverName,
+		Router:     i.router,
+		StartedAt:  i.startedAt,
+		Age:        now.Sub(i.startedAt).Truncate(time.Second).String(),
+	}
+}
@@ -19,7 +19,7 @@ import (
 type http3server struct {
 	*http3.Server
 
-	http3conn net.PacketConn
+	http3conns []net.PacketConn
 
 	lock   sync.RWMutex
 	getter func(info *tls.ClientHelloInfo) (*tls.Config, error)
@@ -30,13 +30,21 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 		return nil, nil
 	}
 
-	conn, err := net.ListenPacket("udp", configuration.GetAddress())
-	if err != nil {
-		return nil, fmt.Errorf("error while starting http3 listener: %w", err)
+	var conns []net.PacketConn
+	for _, address := range configuration.GetAddresses() {
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			for _, c := range conns {
+				_ = c.Close()
+			}
+			return nil, fmt.Errorf("error while starting http3 listener: %w", err)
+		}
+
+		conns = append(conns, conn)
 	}
 
 	h3 := &http3server{
-		http3conn: conn,
+		http3conns: conns,
 		getter: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
 			return nil, errors.New("no tls config")
 		},
@@ -68,8 +76,15 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 	return h3, nil
 }
 
+// Start serves the connections of all the addresses of the entry point,
+// and returns when serving the first one stops.
 func (e *http3server) Start() error {
-	return e.Serve(e.http3conn)
+	for _, conn := range e.http3conns[1:] {
+		conn := conn
+		go func() { _ = e.Serve(conn) }()
+	}
+
+	return e.Serve(e.http3conns[0])
 }
 
 func (e *http3server) Switch(rt *tcp.Router) {
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
 
@@ -151,6 +152,127 @@ func startEntrypoint(entryPoint *TCPEntryPoint, router *tcp.Router) (net.Conn, e
 	return conn, err
 }
 
+func TestDrain(t *testing.T) {
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The hijacked connection is left open, like a WebSocket session.
+		conn, _, err := rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+
+		resp := http.Response{StatusCode: http.StatusSwitchingProtocols}
+		err = resp.Write(conn)
+		require.NoError(t, err)
+	}))
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	epConfig.LifeCycle.RequestAcceptGraceTimeout = ptypes.Duration(time.Hour)
+	epConfig.LifeCycle.GraceTimeOut = ptypes.Duration(5 * time.Second)
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+
+	epAddr := entryPoint.listener.Addr().String()
+
+	request, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:8082", nil)
+	require.NoError(t, err)
+
+	err = request.Write(conn)
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	assert.Equal(t, 1, entryPoint.ActiveConnections())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	entryPoint.Drain(ctx)
+
+	// The session still open on the deadline is closed.
+	assert.Equal(t, 0, entryPoint.ActiveConnections())
+
+	err = conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = conn.Read(make([]byte, 1))
+	assert.True(t, errors.Is(err, io.EOF))
+
+	_, err = net.Dial("tcp", epAddr)
+	require.Error(t, err)
+
+	// The drained entry point shuts down without waiting for the incoming requests to cease.
+	done := make(chan struct{})
+	go func() {
+		entryPoint.Shutdown(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("drained entry point never shut down")
+	}
+}
+
+func TestConnections(t *testing.T) {
+	router := &tcp.Router{}
+	router.AddCatchAllNoTLS(tcp.RouterRecorder("foo@file", tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, _ = io.Copy(conn, conn)
+	})))
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(conn, make([]byte, 4))
+	require.NoError(t, err)
+
+	conns := entryPoint.Connections()
+	require.Len(t, conns, 1)
+
+	assert.Equal(t, conn.LocalAddr().String(), conns[0].ClientAddr)
+	assert.Equal(t, connections.ProtocolTCP, conns[0].Protocol)
+	assert.Equal(t, "foo@file", conns[0].Router)
+	assert.Empty(t, conns[0].SNI)
+
+	err = entryPoint.CloseConnection(conns[0].ID + 1)
+	assert.True(t, errors.Is(err, connections.ErrUnknownConnection))
+
+	err = entryPoint.CloseConnection(conns[0].ID)
+	require.NoError(t, err)
+
+	assert.Empty(t, entryPoint.Connections())
+
+	err = conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = conn.Read(make([]byte, 1))
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
 func TestReadTimeoutWithoutFirstByte(t *testing.T) {
 	epConfig := &static.EntryPointsTransport{}
 	epConfig.SetDefaults()
@@ -225,3 +347,45 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		t.Error("Timeout while read")
 	}
 }
+
+func TestAdditionalAddresses(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:             "127.0.0.1:0",
+		AdditionalAddresses: []string{"127.0.0.1:0"},
+		Transport:           epConfig,
+		ForwardedHeaders:    &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	listener, ok := entryPoint.listener.(*multiListener)
+	require.True(t, ok)
+	require.Len(t, listener.listeners, 2)
+
+	for _, l := range listener.listeners {
+		resp, err := http.Get("http://" + l.Addr().String())
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	entryPoint.Shutdown(ctx)
+
+	_, err = net.Dial("tcp", listener.listeners[1].Addr().String())
+	require.Error(t, err)
+}
@@ -78,38 +78,62 @@ func (eps UDPEntryPoints) Switch(handlers map[string]udp.Handler) {
 
 // UDPEntryPoint is an entry point where we listen for UDP packets.
 type UDPEntryPoint struct {
-	listener               *udp.Listener
+	listeners              []*udp.Listener
 	switcher               *udp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
 }
 
 // NewUDPEntryPoint returns a UDP entry point.
 func NewUDPEntryPoint(cfg *static.EntryPoint) (*UDPEntryPoint, error) {
-	addr, err := net.ResolveUDPAddr("udp", cfg.GetAddress())
-	if err != nil {
-		return nil, err
+	var listeners []*udp.Listener
+	for _, address := range cfg.GetAddresses() {
+		listener, err := listenUDP(address, time.Duration(cfg.UDP.Timeout))
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
 	}
 
-	listener, err := udp.Listen("udp", addr, time.Duration(cfg.UDP.Timeout))
+	return &UDPEntryPoint{listeners: listeners, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+}
+
+func listenUDP(address string, timeout time.Duration) (*udp.Listener, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &UDPEntryPoint{listener: listener, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+	return udp.Listen("udp", addr, timeout)
 }
 
 // Start commences the listening for ep.
 func (ep *UDPEntryPoint) Start(ctx context.Context) {
 	log.FromContext(ctx).Debug("Start UDP Server")
-	for {
-		conn, err := ep.listener.Accept()
-		if err != nil {
-			// Only errClosedListener can happen that's why we return
-			return
-		}
 
-		go ep.switcher.ServeUDP(conn)
+	var wg sync.WaitGroup
+	for _, listener := range ep.listeners {
+		wg.Add(1)
+
+		go func(listener *udp.Listener) {
+			defer wg.Done()
+
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					// Only errClosedListener can happen that's why we return
+					return
+				}
+
+				go ep.switcher.ServeUDP(conn)
+			}
+		}(listener)
 	}
+
+	wg.Wait()
 }
 
 // Shutdown closes ep's listener. It eventually closes all "sessions" and
@@ -125,9 +149,21 @@ func (ep *UDPEntryPoint) Shutdown(ctx context.Context) {
 	}
 
 	graceTimeOut := time.Duration(ep.transportConfiguration.LifeCycle.GraceTimeOut)
-	if err := ep.listener.Shutdown(graceTimeOut); err != nil {
-		logger.Error(err)
+
+	var wg sync.WaitGroup
+	for _, listener := range ep.listeners {
+		wg.Add(1)
+
+		go func(listener *udp.Listener) {
+			defer wg.Done()
+
+			if err := listener.Shutdown(graceTimeOut); err != nil {
+				logger.Error(err)
+			}
+		}(listener)
 	}
+
+	wg.Wait()
 }
 
 // Switch replaces ep's handler with the one given as argument.
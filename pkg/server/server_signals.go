@@ -4,16 +4,34 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
+// drainSignals are the signals which can trigger the drain of the entry points.
+var drainSignals = map[string]os.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
 func (s *Server) configureSignals() {
 	signal.Notify(s.signals, syscall.SIGUSR1)
 }
 
+func parseDrainSignal(name string) (os.Signal, error) {
+	sig, ok := drainSignals[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported drain signal %q", name)
+	}
+
+	return sig, nil
+}
+
 func (s *Server) listenSignals(ctx context.Context) {
 	for {
 		select {
@@ -33,6 +51,14 @@ func (s *Server) listenSignals(ctx context.Context) {
 					log.WithoutContext().Errorf("Error rotating traefik log: %v", err)
 				}
 			}
+
+			if _, ok := s.drainSignals[sig]; ok {
+				log.WithoutContext().Infof("Draining the entry points: %+v", sig)
+
+				if err := s.drainer.Drain(); err != nil {
+					log.WithoutContext().Errorf("Error draining the entry points: %v", err)
+				}
+			}
 		}
 	}
 }
@@ -2,8 +2,16 @@
 
 package server
 
-import "context"
+import (
+	"context"
+	"errors"
+	"os"
+)
 
 func (s *Server) configureSignals() {}
 
+func parseDrainSignal(name string) (os.Signal, error) {
+	return nil, errors.New("drain signals are not supported on Windows")
+}
+
 func (s *Server) listenSignals(ctx context.Context) {}
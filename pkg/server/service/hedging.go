@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+)
+
+// hedgingRoundTripper sends a second attempt of the idempotent requests to another healthy server of the service,
+// when the first attempt has not received its response headers after the hedging delay,
+// and returns the first successful response, the other attempt being canceled.
+// A response is successful when it is not a server error.
+type hedgingRoundTripper struct {
+	http.RoundTripper
+
+	delay time.Duration
+	// balancer provides the healthy servers of the service, and is set once the load-balancer is built.
+	balancer healthcheck.Balancer
+	// concurrency limits the second attempts as the first ones, when the servers are limited.
+	concurrency *serverConcurrency
+	// next rotates the servers the second attempts are sent to.
+	next uint32
+}
+
+// hedgingAttempt is the result of an attempt of a hedged request.
+type hedgingAttempt struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+func (a hedgingAttempt) successful() bool {
+	return a.err == nil && a.resp.StatusCode < http.StatusInternalServerError
+}
+
+// close releases the response of a discarded attempt.
+func (a hedgingAttempt) close() {
+	if a.resp != nil {
+		_ = a.resp.Body.Close()
+	}
+}
+
+func newHedgingRoundTripper(roundTripper http.RoundTripper, config *dynamic.Hedging) (*hedgingRoundTripper, error) {
+	if config.Delay <= 0 {
+		return nil, errors.New("the hedging delay must be positive")
+	}
+
+	return &hedgingRoundTripper{
+		RoundTripper: roundTripper,
+		delay:        time.Duration(config.Delay),
+	}, nil
+}
+
+func (h *hedgingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The requests forced to a server by the upstream override are not sent to another one.
+	if !isHedgeable(req) || isUpstreamOverridden(req.Context()) {
+		return h.RoundTripper.RoundTrip(req)
+	}
+
+	server := h.otherServer(req.URL)
+	if server == nil {
+		return h.RoundTripper.RoundTrip(req)
+	}
+
+	// The channel is buffered, so that the discarded attempts never block.
+	attempts := make(chan hedgingAttempt, 2)
+	cancels := []context.CancelFunc{h.attempt(req, nil, nil, 0, attempts)}
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+
+	// The timer channel is reset once the hedging delay has elapsed.
+	delayed := timer.C
+
+	pending := 1
+	var failed *hedgingAttempt
+
+	for {
+		select {
+		case <-delayed:
+			delayed = nil
+
+			// Without a free request slot on the other server, the request is not hedged.
+			if release, ok := h.take(server); ok {
+				cancels = append(cancels, h.attempt(req, server, release, 1, attempts))
+				pending++
+			}
+
+		case attempt := <-attempts:
+			pending--
+
+			if attempt.successful() {
+				if failed != nil {
+					failed.close()
+				}
+
+				return h.keep(attempt, cancels, pending, attempts), nil
+			}
+
+			if failed == nil {
+				failed = &attempt
+			} else {
+				attempt.close()
+				cancels[attempt.index]()
+			}
+		}
+
+		// A failure of the first attempt before the hedging delay is not retried.
+		if pending == 0 && (delayed == nil || timer.Stop()) {
+			if failed.err != nil {
+				cancels[failed.index]()
+				return nil, failed.err
+			}
+
+			return h.keep(*failed, cancels, 0, attempts), nil
+		}
+	}
+}
+
+// take takes a request slot of the server of a second attempt, without waiting for it,
+// and returns the function releasing it.
+func (h *hedgingRoundTripper) take(server *url.URL) (func(), bool) {
+	if h.concurrency == nil {
+		return nil, true
+	}
+
+	return h.concurrency.take(server)
+}
+
+// attempt sends an attempt of the request, to the given server, or to the server of the request when nil.
+// The release function, when not nil, is called once the attempt is canceled.
+// It returns the function canceling the attempt.
+func (h *hedgingRoundTripper) attempt(req *http.Request, server *url.URL, release func(), index int, attempts chan<- hedgingAttempt) context.CancelFunc {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	if release != nil {
+		go func() {
+			<-ctx.Done()
+			release()
+		}()
+	}
+
+	outReq := req.Clone(ctx)
+	if server != nil {
+		// The Host header is the one of the server, when the one of the client is not passed.
+		if outReq.Host == outReq.URL.Host {
+			outReq.Host = server.Host
+		}
+
+		outReq.URL.Scheme = server.Scheme
+		outReq.URL.Host = server.Host
+	}
+
+	go func() {
+		resp, err := h.RoundTripper.RoundTrip(outReq)
+		attempts <- hedgingAttempt{index: index, resp: resp, err: err}
+	}()
+
+	return cancel
+}
+
+// keep returns the response of the kept attempt, once the other attempts are canceled.
+// The kept attempt is canceled when its response body is closed.
+func (h *hedgingRoundTripper) keep(kept hedgingAttempt, cancels []context.CancelFunc, pending int, attempts <-chan hedgingAttempt) *http.Response {
+	for i, cancel := range cancels {
+		if i != kept.index {
+			cancel()
+		}
+	}
+
+	// The responses of the canceled attempts still in flight are released in the background.
+	if pending > 0 {
+		go func() {
+			for i := 0; i < pending; i++ {
+				attempt := <-attempts
+				attempt.close()
+			}
+		}()
+	}
+
+	kept.resp.Body = &cancelingBody{ReadCloser: kept.resp.Body, cancel: cancels[kept.index]}
+
+	return kept.resp
+}
+
+// otherServer returns a healthy server of the service other than the one of the request URL, or nil if there is none.
+func (h *hedgingRoundTripper) otherServer(reqURL *url.URL) *url.URL {
+	if h.balancer == nil {
+		return nil
+	}
+
+	servers := h.balancer.Servers()
+	start := atomic.AddUint32(&h.next, 1)
+
+	for i := uint32(0); i < uint32(len(servers)); i++ {
+		server := servers[(start+i)%uint32(len(servers))]
+		if server.Scheme != reqURL.Scheme || server.Host != reqURL.Host {
+			return server
+		}
+	}
+
+	return nil
+}
+
+// isHedgeable reports whether a request can be sent twice: it must be idempotent, and without body.
+func isHedgeable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	if req.Header.Get("Upgrade") != "" {
+		return false
+	}
+
+	return req.ContentLength == 0 && len(req.TransferEncoding) == 0 && (req.Body == nil || req.Body == http.NoBody)
+}
+
+// cancelingBody cancels the context of a request once its response body is closed.
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestHedgingRoundTripper(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		method           string
+		body             string
+		firstDelay       time.Duration
+		firstStatus      int
+		expectedStatus   int
+		expectedBody     string
+		expectedAttempts int32
+	}{
+		{
+			desc:             "fast first attempt",
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "slow first attempt",
+			firstDelay:       time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "second",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "slow first attempt failing",
+			firstDelay:       100 * time.Millisecond,
+			firstStatus:      http.StatusServiceUnavailable,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "second",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "fast first attempt failing",
+			firstStatus:      http.StatusServiceUnavailable,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "non idempotent request",
+			method:           http.MethodPost,
+			firstDelay:       100 * time.Millisecond,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "request with a body",
+			body:             "foo",
+			firstDelay:       100 * time.Millisecond,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+
+			first := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+
+				select {
+				case <-time.After(test.firstDelay):
+				case <-req.Context().Done():
+					return
+				}
+
+				if test.firstStatus != 0 {
+					rw.WriteHeader(test.firstStatus)
+				}
+				_, _ = rw.Write([]byte("first"))
+			}))
+			t.Cleanup(first.Close)
+
+			second := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				_, _ = rw.Write([]byte("second"))
+			}))
+			t.Cleanup(second.Close)
+
+			roundTripper, err := newHedgingRoundTripper(http.DefaultTransport, &dynamic.Hedging{Delay: ptypes.Duration(20 * time.Millisecond)})
+			require.NoError(t, err)
+
+			roundTripper.balancer = newTestBalancer(first.URL, second.URL)
+
+			method := http.MethodGet
+			if test.method != "" {
+				method = test.method
+			}
+
+			req, err := http.NewRequest(method, first.URL, strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := roundTripper.RoundTrip(req)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, test.expectedBody, string(body))
+			assert.Equal(t, test.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestHedgingRoundTripper_secondAttempts(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		healthy          bool
+		limited          bool
+		overridden       bool
+		expectedBody     string
+		expectedAttempts int32
+	}{
+		{
+			desc:             "healthy other server",
+			healthy:          true,
+			expectedBody:     "second",
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "unhealthy other server",
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "other server without free slot",
+			healthy:          true,
+			limited:          true,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "request forced by the upstream override",
+			healthy:          true,
+			overridden:       true,
+			expectedBody:     "first",
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+
+			first := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+
+				select {
+				case <-time.After(100 * time.Millisecond):
+				case <-req.Context().Done():
+					return
+				}
+
+				_, _ = rw.Write([]byte("first"))
+			}))
+			t.Cleanup(first.Close)
+
+			second := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				_, _ = rw.Write([]byte("second"))
+			}))
+			t.Cleanup(second.Close)
+
+			roundTripper, err := newHedgingRoundTripper(http.DefaultTransport, &dynamic.Hedging{Delay: ptypes.Duration(20 * time.Millisecond)})
+			require.NoError(t, err)
+
+			roundTripper.balancer = newTestBalancer(first.URL)
+			if test.healthy {
+				roundTripper.balancer = newTestBalancer(first.URL, second.URL)
+			}
+
+			servers := map[string]string{first.URL: first.URL, second.URL: second.URL}
+			roundTripper.concurrency = newServerConcurrency(http.NotFoundHandler(), newServerConcurrencies(), "foo@file", servers, 1, 0, 0)
+			if test.limited {
+				secondURL := testhelpers.MustParseURL(second.URL)
+				release, ok := roundTripper.concurrency.take(secondURL)
+				require.True(t, ok)
+				t.Cleanup(release)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, first.URL, nil)
+			require.NoError(t, err)
+
+			if test.overridden {
+				req = req.WithContext(context.WithValue(req.Context(), upstreamOverriddenKey{}, true))
+			}
+
+			resp, err := roundTripper.RoundTrip(req)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			assert.Equal(t, test.expectedBody, string(body))
+			assert.Equal(t, test.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func TestHedgingRoundTripper_invalidDelay(t *testing.T) {
+	_, err := newHedgingRoundTripper(http.DefaultTransport, &dynamic.Hedging{})
+	assert.Error(t, err)
+}
+
+// testBalancer holds the healthy servers of a service.
+type testBalancer []*url.URL
+
+func newTestBalancer(servers ...string) testBalancer {
+	var lb testBalancer
+	for _, server := range servers {
+		lb = append(lb, testhelpers.MustParseURL(server))
+	}
+
+	return lb
+}
+
+func (b testBalancer) Servers() []*url.URL {
+	return b
+}
+
+func (b testBalancer) RemoveServer(u *url.URL) error {
+	return nil
+}
+
+func (b testBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	return nil
+}
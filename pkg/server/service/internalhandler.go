@@ -20,19 +20,23 @@ type InternalHandlers struct {
 	rest       http.Handler
 	prometheus http.Handler
 	ping       http.Handler
+	ready      http.Handler
 	acmeHTTP   http.Handler
+	webhook    http.Handler
 	serviceManager
 }
 
 // NewInternalHandlers creates a new InternalHandlers.
-func NewInternalHandlers(next serviceManager, apiHandler, rest, metricsHandler, pingHandler, dashboard, acmeHTTP http.Handler) *InternalHandlers {
+func NewInternalHandlers(next serviceManager, apiHandler, rest, metricsHandler, pingHandler, readyHandler, dashboard, acmeHTTP, webhook http.Handler) *InternalHandlers {
 	return &InternalHandlers{
 		api:            apiHandler,
 		dashboard:      dashboard,
 		rest:           rest,
 		prometheus:     metricsHandler,
 		ping:           pingHandler,
+		ready:          readyHandler,
 		acmeHTTP:       acmeHTTP,
+		webhook:        webhook,
 		serviceManager: next,
 	}
 }
@@ -88,6 +92,18 @@ func (m *InternalHandlers) get(serviceName string) (http.Handler, error) {
 		}
 		return m.ping, nil
 
+	case "ready@internal":
+		if m.ready == nil {
+			return nil, errors.New("ping is not enabled")
+		}
+		return m.ready, nil
+
+	case "kubernetes-webhook@internal":
+		if m.webhook == nil {
+			return nil, errors.New("kubernetes webhook is not enabled")
+		}
+		return m.webhook, nil
+
 	case "prometheus@internal":
 		if m.prometheus == nil {
 			return nil, errors.New("prometheus is not enabled")
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// loadSheddingBackoff is the factor applied to the concurrency limit when the latency exceeds the target.
+const loadSheddingBackoff = 0.9
+
+// adaptiveLimit is the concurrency limit of a service, adapted to its latency (AIMD):
+// the limit grows by one request per window of limit requests completed below the latency target,
+// and is multiplied by loadSheddingBackoff, at most once per latency target, when a request exceeds it.
+type adaptiveLimit struct {
+	config       dynamic.LoadShedding
+	target       time.Duration
+	queueTimeout time.Duration
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	waiters      *list.List // the channels of the requests waiting for a slot, closed when the slot is given
+	lastDecrease time.Time
+}
+
+func newAdaptiveLimit(config dynamic.LoadShedding) *adaptiveLimit {
+	return &adaptiveLimit{
+		config:       config,
+		target:       time.Duration(config.LatencyTarget),
+		queueTimeout: time.Duration(config.QueueTimeout),
+		limit:        float64(config.InitialLimit),
+		waiters:      list.New(),
+	}
+}
+
+// acquire takes a slot, waiting in the queue when the limit is reached, and tells whether the slot was taken.
+func (l *adaptiveLimit) acquire(ctx context.Context) bool {
+	l.mu.Lock()
+
+	if l.waiters.Len() == 0 && l.inFlight < l.currentLimit() {
+		l.inFlight++
+		l.mu.Unlock()
+		return true
+	}
+
+	if l.waiters.Len() >= l.config.QueueSize {
+		l.mu.Unlock()
+		return false
+	}
+
+	ready := make(chan struct{})
+	elem := l.waiters.PushBack(ready)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-ready:
+		// The slot was given while the request was giving up, so it is handed over to the next request.
+		l.inFlight--
+		l.dispatch()
+	default:
+		l.waiters.Remove(elem)
+	}
+
+	return false
+}
+
+// release gives back a slot, adapts the limit to the latency of the request, and gives the free slots to the waiting requests.
+func (l *adaptiveLimit) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	concurrency := l.inFlight
+	l.inFlight--
+
+	now := time.Now()
+	switch {
+	case latency > l.target:
+		if now.Sub(l.lastDecrease) >= l.target {
+			l.limit = math.Max(float64(l.config.MinLimit), l.limit*loadSheddingBackoff)
+			l.lastDecrease = now
+		}
+	case float64(concurrency) >= l.limit/2:
+		// The limit only grows when it is actually used, so that it does not grow unbounded while the service is idle.
+		l.limit = math.Min(float64(l.config.MaxLimit), l.limit+1/l.limit)
+	}
+
+	l.dispatch()
+}
+
+// dispatch gives the free slots to the waiting requests, in their arrival order.
+func (l *adaptiveLimit) dispatch() {
+	for l.waiters.Len() > 0 && l.inFlight < l.currentLimit() {
+		ready := l.waiters.Remove(l.waiters.Front()).(chan struct{})
+		l.inFlight++
+		close(ready)
+	}
+}
+
+func (l *adaptiveLimit) currentLimit() int {
+	return int(l.limit)
+}
+
+// adaptiveLimits remembers the concurrency limits of the services across the configuration changes,
+// so that a reload neither resets the limits learned from the latency nor forgets the requests in flight.
+type adaptiveLimits struct {
+	mu       sync.Mutex
+	services map[string]*adaptiveLimit
+}
+
+func newAdaptiveLimits() *adaptiveLimits {
+	return &adaptiveLimits{services: make(map[string]*adaptiveLimit)}
+}
+
+// get returns the concurrency limit of a service, which is reset when its configuration changed.
+func (a *adaptiveLimits) get(serviceName string, config dynamic.LoadShedding) *adaptiveLimit {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limit, ok := a.services[serviceName]
+	if !ok || limit.config != config {
+		limit = newAdaptiveLimit(config)
+		a.services[serviceName] = limit
+	}
+
+	return limit
+}
+
+// loadShedder rejects the requests to a service beyond its adaptive concurrency limit,
+// with a 503 status code and a Retry-After header.
+type loadShedder struct {
+	next        http.Handler
+	serviceName string
+	limit       *adaptiveLimit
+	retryAfter  string
+}
+
+func newLoadShedder(next http.Handler, limit *adaptiveLimit, serviceName string, retryAfter time.Duration) http.Handler {
+	return &loadShedder{
+		next:        next,
+		serviceName: serviceName,
+		limit:       limit,
+		retryAfter:  strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))),
+	}
+}
+
+func (s *loadShedder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !s.limit.acquire(req.Context()) {
+		log.FromContext(req.Context()).Debugf("The service %s is overloaded, the request is rejected", s.serviceName)
+		rw.Header().Set("Retry-After", s.retryAfter)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	start := time.Now()
+	defer func() { s.limit.release(time.Since(start)) }()
+
+	s.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestLoadShedder(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	config := dynamic.LoadShedding{
+		LatencyTarget: ptypes.Duration(time.Minute),
+		InitialLimit:  1,
+		MinLimit:      1,
+		MaxLimit:      10,
+		QueueSize:     1,
+		QueueTimeout:  ptypes.Duration(time.Minute),
+		RetryAfter:    ptypes.Duration(1500 * time.Millisecond),
+	}
+	limit := newAdaptiveLimit(config)
+	handler := newLoadShedder(next, limit, "foo@file", time.Duration(config.RetryAfter))
+
+	codes := make(chan int, 2)
+	var wg sync.WaitGroup
+	serve := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+			codes <- rw.Code
+		}()
+	}
+
+	// The first request is forwarded, and the second one waits in the queue.
+	serve()
+	<-started
+	serve()
+
+	assert.Eventually(t, func() bool {
+		limit.mu.Lock()
+		defer limit.mu.Unlock()
+
+		return limit.waiters.Len() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// The queue is full, so the third request is rejected.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, "2", rw.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	limit.mu.Lock()
+	defer limit.mu.Unlock()
+
+	assert.Equal(t, 0, limit.inFlight)
+	assert.Equal(t, 0, limit.waiters.Len())
+}
+
+func TestLoadShedder_queueTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+	})
+
+	config := dynamic.LoadShedding{
+		LatencyTarget: ptypes.Duration(time.Minute),
+		InitialLimit:  1,
+		MinLimit:      1,
+		MaxLimit:      1,
+		QueueSize:     1,
+		QueueTimeout:  ptypes.Duration(10 * time.Millisecond),
+	}
+	handler := newLoadShedder(next, newAdaptiveLimit(config), "foo@file", 0)
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+
+	assert.Eventually(t, func() bool {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+
+		return rw.Code == http.StatusServiceUnavailable && rw.Header().Get("Retry-After") == "0"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestAdaptiveLimit_release(t *testing.T) {
+	limit := newAdaptiveLimit(dynamic.LoadShedding{
+		LatencyTarget: ptypes.Duration(100 * time.Millisecond),
+		InitialLimit:  10,
+		MinLimit:      5,
+		MaxLimit:      11,
+	})
+
+	acquire := func(n int) {
+		for i := 0; i < n; i++ {
+			assert.True(t, limit.acquire(context.Background()))
+		}
+	}
+
+	// The limit does not grow while it is not used.
+	acquire(1)
+	limit.release(time.Millisecond)
+	assert.Equal(t, 10.0, limit.limit)
+
+	// It grows additively while the latency is below the target, up to the maximum limit.
+	acquire(10)
+	assert.False(t, limit.acquire(context.Background()))
+	for i := 0; i < 10; i++ {
+		limit.release(time.Millisecond)
+	}
+	assert.Greater(t, limit.limit, 10.0)
+
+	for round := 0; round < 10; round++ {
+		acquire(10)
+		for i := 0; i < 10; i++ {
+			limit.release(time.Millisecond)
+		}
+	}
+	assert.Equal(t, 11.0, limit.limit)
+
+	// It decreases multiplicatively when the latency exceeds the target, at most once per latency target.
+	acquire(2)
+	limit.release(time.Second)
+	limit.release(time.Second)
+	assert.InDelta(t, 9.9, limit.limit, 0.01)
+
+	limit.lastDecrease = time.Time{}
+	acquire(1)
+	limit.release(time.Second)
+	assert.InDelta(t, 8.91, limit.limit, 0.01)
+
+	// It does not go below the minimum limit.
+	for i := 0; i < 10; i++ {
+		limit.lastDecrease = time.Time{}
+		acquire(1)
+		limit.release(time.Second)
+	}
+	assert.Equal(t, 5.0, limit.limit)
+}
+
+func TestAdaptiveLimits_get(t *testing.T) {
+	limits := newAdaptiveLimits()
+
+	config := dynamic.LoadShedding{LatencyTarget: ptypes.Duration(time.Second), InitialLimit: 10, MinLimit: 1, MaxLimit: 100}
+
+	limit := limits.get("foo@file", config)
+
+	// The limits are kept across the configuration changes.
+	assert.Same(t, limit, limits.get("foo@file", config))
+
+	// They are reset when the configuration changes.
+	config.MaxLimit = 50
+	assert.NotSame(t, limit, limits.get("foo@file", config))
+	assert.NotSame(t, limit, limits.get("bar@file", config))
+}
@@ -7,7 +7,16 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/apiauth"
+	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/audit"
+	"github.com/traefik/traefik/v2/pkg/server/confighash"
+	"github.com/traefik/traefik/v2/pkg/server/connections"
+	"github.com/traefik/traefik/v2/pkg/server/drain"
+	"github.com/traefik/traefik/v2/pkg/server/effective"
+	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	"github.com/traefik/traefik/v2/pkg/server/override"
 )
 
 // ManagerFactory a factory of service manager.
@@ -21,25 +30,88 @@ type ManagerFactory struct {
 	dashboardHandler http.Handler
 	metricsHandler   http.Handler
 	pingHandler      http.Handler
+	readyHandler     http.Handler
 	acmeHTTPHandler  http.Handler
+	webhookHandler   http.Handler
+
+	routinesPool     *safe.Pool
+	upstreamOverride *static.UpstreamOverride
+	requestDebug     bool
+
+	// circuitBreakers resets the circuit breakers through the API, and is nil when the overrides are disabled.
+	circuitBreakers *circuitbreaker.Registry
+	overrides       *override.Store
+
+	// stickyDrains remembers the servers of the sticky services across the built service managers.
+	stickyDrains *stickyDrains
+	// promotions remembers the state of the promotions of the weighted services across the built service managers.
+	promotions *promotions
+	// serverConcurrencies remembers the request slots of the servers across the built service managers.
+	serverConcurrencies *serverConcurrencies
+	// adaptiveLimits remembers the concurrency limits of the services with load shedding across the built service managers.
+	adaptiveLimits *adaptiveLimits
+}
 
-	routinesPool *safe.Pool
+// APIOptions holds the dependencies of the API, which are nil when the matching features are disabled.
+type APIOptions struct {
+	// Overrides enables the overrides endpoints.
+	Overrides *override.Store
+	// Auth authenticates the requests to the API and the dashboard.
+	Auth *apiauth.Authenticator
+	// Rates reports the request rates in the topology graph.
+	Rates *metrics.RatesRegistry
+	// Drainer enables the drain endpoints.
+	Drainer *drain.Manager
+	// AuditLog enables the audit endpoint.
+	AuditLog *audit.Log
+	// Connections enables the connections endpoints.
+	Connections *connections.Registry
+	// Plugins builds the plugin middlewares of the middleware simulations.
+	Plugins middleware.PluginsBuilder
+	// EffectiveConfig enables the effective configuration endpoint.
+	EffectiveConfig *effective.Store
+	// ConfigHash reports the hash of the applied configuration in the overview.
+	ConfigHash *confighash.Tracker
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler) *ManagerFactory {
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler, apiOptions APIOptions) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
 		acmeHTTPHandler:     acmeHTTPHandler,
+		upstreamOverride:    staticConfiguration.UpstreamOverride,
+		requestDebug:        staticConfiguration.RequestDebug != nil,
+		overrides:           apiOptions.Overrides,
+		stickyDrains:        newStickyDrains(),
+		promotions:          newPromotions(),
+		serverConcurrencies: newServerConcurrencies(),
+		adaptiveLimits:      newAdaptiveLimits(),
+	}
+
+	if apiOptions.Overrides != nil {
+		factory.circuitBreakers = circuitbreaker.NewRegistry()
 	}
 
 	if staticConfiguration.API != nil {
-		factory.api = api.NewBuilder(staticConfiguration)
+		apiBuilder := api.NewBuilder(staticConfiguration, api.Options{
+			Overrides:       apiOptions.Overrides,
+			CircuitBreakers: factory.circuitBreakers,
+			Rates:           apiOptions.Rates,
+			Drainer:         apiOptions.Drainer,
+			AuditLog:        apiOptions.AuditLog,
+			Connections:     apiOptions.Connections,
+			Plugins:         apiOptions.Plugins,
+			EffectiveConfig: apiOptions.EffectiveConfig,
+			ConfigHash:      apiOptions.ConfigHash,
+		})
+		factory.api = func(configuration *runtime.Configuration) http.Handler {
+			return apiOptions.Auth.Wrap(apiBuilder(configuration))
+		}
 
 		if staticConfiguration.API.Dashboard {
-			factory.dashboardHandler = http.FileServer(staticConfiguration.API.DashboardAssets)
+			factory.dashboardHandler = apiOptions.Auth.Wrap(http.FileServer(staticConfiguration.API.DashboardAssets))
 		}
 	}
 
@@ -56,6 +128,11 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	// and would break things elsewhere.
 	if staticConfiguration.Ping != nil {
 		factory.pingHandler = staticConfiguration.Ping
+		factory.readyHandler = staticConfiguration.Ping.ReadyHandler()
+	}
+
+	if staticConfiguration.Providers != nil && staticConfiguration.Providers.KubernetesCRD != nil && staticConfiguration.Providers.KubernetesCRD.Webhook != nil {
+		factory.webhookHandler = staticConfiguration.Providers.KubernetesCRD.Webhook
 	}
 
 	return factory
@@ -63,12 +140,39 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
+	f.serverConcurrencies.prune(configuration.Services)
+
 	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager)
+	svcManager.upstreamOverride = f.upstreamOverride
+	svcManager.requestDebug = f.requestDebug
+	svcManager.stickyDrains = f.stickyDrains
+	svcManager.promotions = f.promotions
+	svcManager.serverConcurrencies = f.serverConcurrencies
+	svcManager.adaptiveLimits = f.adaptiveLimits
 
 	var apiHandler http.Handler
 	if f.api != nil {
 		apiHandler = f.api(configuration)
 	}
 
-	return NewInternalHandlers(svcManager, apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
+	return NewInternalHandlers(svcManager, apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.readyHandler, f.dashboardHandler, f.acmeHTTPHandler, f.webhookHandler)
+}
+
+// CircuitBreakers returns the registry resetting the circuit breakers, which is nil when the overrides are disabled.
+func (f *ManagerFactory) CircuitBreakers() *circuitbreaker.Registry {
+	return f.circuitBreakers
+}
+
+// Generation returns a number changing each time the servers transports of the built service managers change.
+func (f *ManagerFactory) Generation() uint64 {
+	if f.roundTripperManager == nil {
+		return 0
+	}
+
+	return f.roundTripperManager.Generation()
+}
+
+// ReloadGeneration returns a number changing each time a reload is requested through the overrides endpoints.
+func (f *ManagerFactory) ReloadGeneration() uint64 {
+	return f.overrides.Generation()
 }
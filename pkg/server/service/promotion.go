@@ -0,0 +1,323 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+// promotions remembers the state of the promotions of the weighted services across the configuration changes,
+// so that a promotion goes on through the reloads, and restarts only when its configuration changes.
+type promotions struct {
+	mu     sync.Mutex
+	states map[string]*promotionState
+	now    func() time.Time
+}
+
+func newPromotions() *promotions {
+	return &promotions{
+		states: make(map[string]*promotionState),
+		now:    time.Now,
+	}
+}
+
+// get returns the state of the promotion of a weighted service, which starts over when its configuration changed.
+func (p *promotions) get(serviceName string, config *dynamic.Promotion, weight gokitmetrics.Gauge, aborts gokitmetrics.Counter) *promotionState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, ok := p.states[serviceName]
+	if ok && reflect.DeepEqual(state.config, *config) {
+		return state
+	}
+
+	state = &promotionState{
+		serviceName:   serviceName,
+		config:        *config.DeepCopy(),
+		weight:        weight,
+		aborts:        aborts,
+		now:           p.now,
+		stepStartedAt: p.now(),
+	}
+	p.states[serviceName] = state
+
+	weight.Set(float64(config.Steps[0].Percent))
+
+	return state
+}
+
+// promotionState is the state of the promotion of the canary service of a weighted service.
+// The steps are advanced lazily, when the percentage or the status of the promotion is read.
+type promotionState struct {
+	serviceName string
+	config      dynamic.Promotion
+	weight      gokitmetrics.Gauge
+	aborts      gokitmetrics.Counter
+	now         func() time.Time
+
+	mu            sync.Mutex
+	step          int
+	stepStartedAt time.Time
+	requests      uint64
+	errors        uint64
+	abortReason   string
+}
+
+// advance moves to the steps whose start time is reached.
+func (s *promotionState) advance() {
+	now := s.now()
+
+	for s.abortReason == "" && s.step < len(s.config.Steps)-1 {
+		end := s.stepStartedAt.Add(time.Duration(s.config.Steps[s.step].Duration))
+		if now.Before(end) {
+			return
+		}
+
+		s.step++
+		s.stepStartedAt = end
+		s.requests = 0
+		s.errors = 0
+
+		s.weight.Set(float64(s.config.Steps[s.step].Percent))
+
+		log.WithoutContext().WithField(log.ServiceName, s.serviceName).
+			Infof("Promotion of the canary service %s: %d%% of the requests", s.config.Canary, s.config.Steps[s.step].Percent)
+	}
+}
+
+// percent returns the percentage of the requests to forward to the canary service.
+func (s *promotionState) percent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+
+	if s.abortReason != "" {
+		return 0
+	}
+
+	return s.config.Steps[s.step].Percent
+}
+
+// record records a response of the canary service,
+// and aborts the promotion when the error rate of the canary service during the step is above the maximum.
+// The responses are not recorded anymore once the last step is reached.
+func (s *promotionState) record(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+
+	if s.abortReason != "" || s.step == len(s.config.Steps)-1 {
+		return
+	}
+
+	s.requests++
+	if code >= http.StatusInternalServerError {
+		s.errors++
+	}
+
+	if s.config.MaxErrorRate <= 0 || s.requests < uint64(s.config.MinRequests) {
+		return
+	}
+
+	errorRate := float64(s.errors) / float64(s.requests)
+	if errorRate <= s.config.MaxErrorRate {
+		return
+	}
+
+	s.abortReason = fmt.Sprintf("error rate %.2f above the maximum %.2f at step %d", errorRate, s.config.MaxErrorRate, s.step+1)
+
+	s.weight.Set(0)
+	s.aborts.Add(1)
+
+	log.WithoutContext().WithField(log.ServiceName, s.serviceName).
+		Warnf("Promotion of the canary service %s aborted: %s", s.config.Canary, s.abortReason)
+}
+
+func (s *promotionState) status() runtime.PromotionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+
+	status := runtime.PromotionStatus{
+		Canary:        s.config.Canary,
+		State:         runtime.PromotionProgressing,
+		Step:          s.step + 1,
+		Steps:         len(s.config.Steps),
+		Percent:       s.config.Steps[s.step].Percent,
+		StepStartedAt: s.stepStartedAt,
+		Requests:      s.requests,
+		Errors:        s.errors,
+		AbortReason:   s.abortReason,
+	}
+
+	switch {
+	case s.abortReason != "":
+		status.State = runtime.PromotionAborted
+		status.Percent = 0
+	case s.step == len(s.config.Steps)-1:
+		status.State = runtime.PromotionPromoted
+	default:
+		next := s.stepStartedAt.Add(time.Duration(s.config.Steps[s.step].Duration))
+		status.NextStepAt = &next
+	}
+
+	return status
+}
+
+func validatePromotion(config *dynamic.WeightedRoundRobin) error {
+	promotion := config.Promotion
+
+	if len(promotion.Steps) == 0 {
+		return errors.New("the promotion must have at least one step")
+	}
+
+	for i, step := range promotion.Steps {
+		if step.Percent < 0 || step.Percent > 100 {
+			return fmt.Errorf("the percent of the promotion step %d must be between 0 and 100", i+1)
+		}
+
+		if step.Duration < 0 {
+			return fmt.Errorf("the duration of the promotion step %d must not be negative", i+1)
+		}
+	}
+
+	if promotion.MaxErrorRate < 0 || promotion.MaxErrorRate > 1 {
+		return errors.New("the maximum error rate of the promotion must be between 0 and 1")
+	}
+
+	var canary, others bool
+	for _, service := range config.Services {
+		if service.Name == promotion.Canary {
+			canary = true
+		} else {
+			others = true
+		}
+	}
+
+	if !canary {
+		return fmt.Errorf("the canary service %q is not one of the services of the weighted service", promotion.Canary)
+	}
+
+	if !others {
+		return errors.New("the weighted service must have other services than the canary service")
+	}
+
+	return nil
+}
+
+// promotionHandler forwards the percentage of the requests of the current step of a promotion to the canary service,
+// and the other requests to the load-balancer of the other services of the weighted service.
+type promotionHandler struct {
+	state  *promotionState
+	canary http.Handler
+	others http.Handler
+
+	stickyCookie *dynamic.Cookie
+	// otherNames holds the names of the other services, to forward their sticky sessions.
+	otherNames map[string]struct{}
+
+	mu sync.Mutex
+	// credit accumulates the percentages of the requests, a request being forwarded to the canary service every 100.
+	credit int
+}
+
+func (h *promotionHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	percent := h.state.percent()
+
+	// The sticky sessions are kept, unless their service does not receive any request at the current step.
+	if h.stickyCookie != nil {
+		if cookie, err := req.Cookie(h.stickyCookie.Name); err == nil {
+			if cookie.Value == h.state.config.Canary && percent > 0 {
+				h.serveCanary(rw, req)
+				return
+			}
+
+			if _, ok := h.otherNames[cookie.Value]; ok && percent < 100 {
+				h.others.ServeHTTP(rw, req)
+				return
+			}
+		}
+	}
+
+	if !h.selected(percent) {
+		h.others.ServeHTTP(rw, req)
+		return
+	}
+
+	if h.stickyCookie != nil {
+		http.SetCookie(rw, &http.Cookie{
+			Name:     h.stickyCookie.Name,
+			Value:    h.state.config.Canary,
+			Path:     "/",
+			HttpOnly: h.stickyCookie.HTTPOnly,
+			Secure:   h.stickyCookie.Secure,
+		})
+	}
+
+	h.serveCanary(rw, req)
+}
+
+func (h *promotionHandler) selected(percent int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.credit += percent
+	if h.credit < 100 {
+		return false
+	}
+
+	h.credit -= 100
+	return true
+}
+
+func (h *promotionHandler) serveCanary(rw http.ResponseWriter, req *http.Request) {
+	recorder := &promotionRecorder{ResponseWriter: rw, code: http.StatusOK}
+	h.canary.ServeHTTP(recorder, req)
+
+	h.state.record(recorder.code)
+}
+
+// promotionRecorder records the status code of the responses of the canary service.
+type promotionRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *promotionRecorder) WriteHeader(code int) {
+	if !middlewares.IsInformational(code) {
+		r.code = code
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Hijack hijacks the connection.
+func (r *promotionRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *promotionRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
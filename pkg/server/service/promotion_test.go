@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+func newPromotionManager(t *testing.T, promotion *dynamic.Promotion, canaryStatusCode int, sticky *dynamic.Sticky) *Manager {
+	t.Helper()
+
+	stableWeight, canaryWeight := 1, 1
+
+	manager := NewManager(map[string]*runtime.ServiceInfo{
+		"app@file": {
+			Service: &dynamic.Service{
+				Weighted: &dynamic.WeightedRoundRobin{
+					Services: []dynamic.WRRService{
+						{Name: "stable", Weight: &stableWeight},
+						{Name: "canary", Weight: &canaryWeight},
+					},
+					Sticky:    sticky,
+					Promotion: promotion,
+				},
+			},
+		},
+		"stable@file": {
+			Service: &dynamic.Service{
+				Static: &dynamic.StaticResponse{StatusCode: http.StatusOK, Body: "stable"},
+			},
+		},
+		"canary@file": {
+			Service: &dynamic.Service{
+				Static: &dynamic.StaticResponse{StatusCode: canaryStatusCode, Body: "canary"},
+			},
+		},
+	}, nil, nil, nil)
+
+	return manager
+}
+
+// canaryRequests returns the number of requests forwarded to the canary service out of the given number.
+func canaryRequests(t *testing.T, handler http.Handler, count int) int {
+	t.Helper()
+
+	var canary int
+	for i := 0; i < count; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.com", nil))
+
+		if rw.Body.String() == "canary" {
+			canary++
+		}
+	}
+
+	return canary
+}
+
+func TestPromotion(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	promotion := &dynamic.Promotion{
+		Canary: "canary",
+		Steps: []dynamic.PromotionStep{
+			{Percent: 5, Duration: ptypes.Duration(time.Minute)},
+			{Percent: 50, Duration: ptypes.Duration(time.Minute)},
+			{Percent: 100},
+		},
+	}
+
+	manager := newPromotionManager(t, promotion, http.StatusOK, nil)
+	manager.promotions.now = func() time.Time { return now }
+
+	handler, err := manager.BuildHTTP(context.Background(), "app@file")
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, canaryRequests(t, handler, 100))
+
+	status := manager.configs["app@file"].GetPromotionStatus()
+	require.NotNil(t, status)
+	nextStepAt := now.Add(time.Minute)
+	assert.Equal(t, &runtime.PromotionStatus{
+		Canary:        "canary",
+		State:         runtime.PromotionProgressing,
+		Step:          1,
+		Steps:         3,
+		Percent:       5,
+		StepStartedAt: now,
+		NextStepAt:    &nextStepAt,
+		Requests:      5,
+	}, status)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 50, canaryRequests(t, handler, 100))
+
+	// The promotion goes on when the configuration is reloaded.
+	manager.configs["app@file"].Weighted.Promotion = promotion.DeepCopy()
+	handler, err = manager.BuildHTTP(context.Background(), "app@file")
+	require.NoError(t, err)
+
+	// The steps are skipped when they all elapsed.
+	now = now.Add(time.Hour)
+	assert.Equal(t, 100, canaryRequests(t, handler, 100))
+
+	status = manager.configs["app@file"].GetPromotionStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, runtime.PromotionPromoted, status.State)
+	assert.Equal(t, 3, status.Step)
+	assert.Nil(t, status.NextStepAt)
+
+	// The promotion starts over when its configuration changes.
+	manager.configs["app@file"].Weighted.Promotion.Steps[0].Percent = 10
+	handler, err = manager.BuildHTTP(context.Background(), "app@file")
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, canaryRequests(t, handler, 100))
+}
+
+func TestPromotion_abort(t *testing.T) {
+	manager := newPromotionManager(t, &dynamic.Promotion{
+		Canary: "canary",
+		Steps: []dynamic.PromotionStep{
+			{Percent: 50, Duration: ptypes.Duration(time.Hour)},
+			{Percent: 100},
+		},
+		MaxErrorRate: 0.5,
+		MinRequests:  10,
+	}, http.StatusBadGateway, nil)
+
+	handler, err := manager.BuildHTTP(context.Background(), "app@file")
+	require.NoError(t, err)
+
+	// The promotion is aborted once the canary service answered the minimum number of requests.
+	assert.Equal(t, 10, canaryRequests(t, handler, 100))
+
+	status := manager.configs["app@file"].GetPromotionStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, runtime.PromotionAborted, status.State)
+	assert.Equal(t, 0, status.Percent)
+	assert.Equal(t, uint64(10), status.Errors)
+	assert.NotEmpty(t, status.AbortReason)
+}
+
+func TestPromotion_sticky(t *testing.T) {
+	manager := newPromotionManager(t, &dynamic.Promotion{
+		Canary: "canary",
+		Steps:  []dynamic.PromotionStep{{Percent: 50}},
+	}, http.StatusOK, &dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "sticky"}})
+
+	handler, err := manager.BuildHTTP(context.Background(), "app@file")
+	require.NoError(t, err)
+
+	for _, expected := range []string{"stable", "canary"} {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.com", nil))
+		require.Equal(t, expected, rw.Body.String())
+
+		cookies := rw.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodGet, "http://foo.com", nil)
+			req.AddCookie(cookies[0])
+
+			rw = httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			assert.Equal(t, expected, rw.Body.String())
+		}
+	}
+}
+
+func TestPromotion_invalid(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		promotion dynamic.Promotion
+	}{
+		{
+			desc:      "no steps",
+			promotion: dynamic.Promotion{Canary: "canary"},
+		},
+		{
+			desc:      "percent out of range",
+			promotion: dynamic.Promotion{Canary: "canary", Steps: []dynamic.PromotionStep{{Percent: 101}}},
+		},
+		{
+			desc:      "maximum error rate out of range",
+			promotion: dynamic.Promotion{Canary: "canary", Steps: []dynamic.PromotionStep{{Percent: 10}}, MaxErrorRate: 2},
+		},
+		{
+			desc:      "unknown canary service",
+			promotion: dynamic.Promotion{Canary: "unknown", Steps: []dynamic.PromotionStep{{Percent: 10}}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			manager := newPromotionManager(t, &test.promotion, http.StatusOK, nil)
+
+			_, err := manager.BuildHTTP(context.Background(), "app@file")
+			assert.Error(t, err)
+		})
+	}
+}
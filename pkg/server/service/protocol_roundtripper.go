@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http2"
+)
+
+// The protocols which can be pinned in a servers transport.
+const (
+	protocolHTTP1 = "http/1.1"
+	protocolH2    = "h2"
+	protocolH2C   = "h2c"
+	protocolH3    = "h3"
+)
+
+// downgradeDuration is the time during which a server is contacted with the fallback protocol,
+// once the preferred one could not be established, before the preferred protocol is tried again.
+const downgradeDuration = 5 * time.Minute
+
+// protocolError is returned when a connection with the preferred protocol cannot be established with a server.
+// As it happens before the request is sent, the request can be sent again with another protocol.
+type protocolError struct {
+	protocol string
+	err      error
+}
+
+func (e *protocolError) Error() string {
+	return fmt.Sprintf("unable to establish a %s connection: %v", e.protocol, e.err)
+}
+
+func (e *protocolError) Unwrap() error {
+	return e.err
+}
+
+// newProtocolRoundTripper creates a round tripper contacting the servers with the given protocol,
+// and falling back on the previous protocols, down to HTTP/1.1, when it cannot be established.
+func newProtocolRoundTripper(protocol string, transport *http.Transport, dial dialContextFunc) (http.RoundTripper, error) {
+	transportHTTP1 := transport.Clone()
+	// A non-nil map prevents the transport from negotiating HTTP/2.
+	transportHTTP1.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+
+	switch protocol {
+	case protocolHTTP1:
+		return transportHTTP1, nil
+
+	case protocolH2C:
+		return &pinnedRoundTripper{
+			pinned: &h2cTransportWrapper{Transport: &http2.Transport{
+				DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+					return dial(context.Background(), network, addr)
+				},
+				AllowHTTP: true,
+			}},
+			http: transportHTTP1,
+		}, nil
+
+	case protocolH2:
+		return &pinnedRoundTripper{
+			pinned:     newH2FallbackRoundTripper(transport, transportHTTP1, dial),
+			http:       transportHTTP1,
+			secureOnly: true,
+		}, nil
+
+	case protocolH3:
+		return &pinnedRoundTripper{
+			pinned: &fallbackRoundTripper{
+				preferred: newH3RoundTripper(transport.TLSClientConfig),
+				fallback:  newH2FallbackRoundTripper(transport, transportHTTP1, dial),
+				from:      protocolH3,
+				to:        protocolH2,
+			},
+			http:       transportHTTP1,
+			secureOnly: true,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q, it must be one of %s, %s, %s, or %s", protocol, protocolHTTP1, protocolH2, protocolH2C, protocolH3)
+	}
+}
+
+// newH2FallbackRoundTripper creates a round tripper contacting the servers with HTTP/2,
+// and falling back on HTTP/1.1 with the servers which do not negotiate HTTP/2 with ALPN.
+func newH2FallbackRoundTripper(transport, transportHTTP1 *http.Transport, dial dialContextFunc) http.RoundTripper {
+	return &fallbackRoundTripper{
+		preferred: &http2.Transport{
+			TLSClientConfig: transport.TLSClientConfig,
+			DialTLS:         dialH2(dial, transport.TLSHandshakeTimeout),
+		},
+		fallback: transportHTTP1,
+		from:     protocolH2,
+		to:       protocolHTTP1,
+	}
+}
+
+// dialH2 returns a function dialing TLS connections, which fails with a protocolError when the server does not negotiate HTTP/2.
+// HTTP/1.1 is offered along with HTTP/2, so that the servers supporting ALPN, but not HTTP/2, do not abort the handshake.
+func dialH2(dial dialContextFunc, handshakeTimeout time.Duration) func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+	return func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+		conn, err := dial(context.Background(), network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg = cfg.Clone()
+		cfg.NextProtos = []string{http2.NextProtoTLS, protocolHTTP1}
+
+		tlsConn := tls.Client(conn, cfg)
+
+		if handshakeTimeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+		}
+
+		if err := tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		_ = conn.SetDeadline(time.Time{})
+
+		if negotiated := tlsConn.ConnectionState().NegotiatedProtocol; negotiated != http2.NextProtoTLS {
+			_ = tlsConn.Close()
+			return nil, &protocolError{protocol: protocolH2, err: fmt.Errorf("the server negotiated %q with ALPN", negotiated)}
+		}
+
+		return tlsConn, nil
+	}
+}
+
+// pinnedRoundTripper sends the requests with a pinned protocol.
+// The requests upgrading the connection, such as the WebSocket ones, are sent with HTTP/1.1,
+// as HTTP/2 and HTTP/3 do not support the upgrades.
+type pinnedRoundTripper struct {
+	pinned http.RoundTripper
+	http   http.RoundTripper
+	// secureOnly reports whether the pinned protocol only applies to the servers contacted with TLS,
+	// the other servers being contacted with HTTP/1.1.
+	secureOnly bool
+}
+
+func (p *pinnedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if httpguts.HeaderValuesContainsToken(req.Header["Connection"], "Upgrade") || (p.secureOnly && req.URL.Scheme != "https") {
+		return p.http.RoundTrip(req)
+	}
+
+	return p.pinned.RoundTrip(req)
+}
+
+// fallbackRoundTripper sends the requests with a preferred protocol,
+// and falls back on another one for the servers with which the preferred protocol cannot be established.
+// These servers are then contacted with the fallback protocol for the downgradeDuration.
+type fallbackRoundTripper struct {
+	preferred http.RoundTripper
+	fallback  http.RoundTripper
+	from, to  string
+
+	mu sync.Mutex
+	// downgraded are the times until which the servers, keyed by address, are contacted with the fallback protocol.
+	downgraded map[string]time.Time
+}
+
+func (f *fallbackRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if f.isDowngraded(req.URL.Host) {
+		return f.fallback.RoundTrip(req)
+	}
+
+	resp, err := f.preferred.RoundTrip(req)
+
+	var protoErr *protocolError
+	if !errors.As(err, &protoErr) {
+		return resp, err
+	}
+
+	log.FromContext(req.Context()).Debugf("Falling back on %s with the server %s: %v", f.to, req.URL.Host, err)
+
+	f.downgrade(req.URL.Host)
+
+	if counter, ok := req.Context().Value(protocolDowngradesKey{}).(gokitmetrics.Counter); ok {
+		counter.With("from", f.from, "to", f.to).Add(1)
+	}
+
+	return f.fallback.RoundTrip(req)
+}
+
+func (f *fallbackRoundTripper) isDowngraded(host string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	until, ok := f.downgraded[host]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(until) {
+		delete(f.downgraded, host)
+		return false
+	}
+
+	return true
+}
+
+func (f *fallbackRoundTripper) downgrade(host string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.downgraded == nil {
+		f.downgraded = make(map[string]time.Time)
+	}
+
+	f.downgraded[host] = time.Now().Add(downgradeDuration)
+}
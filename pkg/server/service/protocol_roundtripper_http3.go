@@ -0,0 +1,80 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/lucas-clemente/quic-go"
+	"github.com/lucas-clemente/quic-go/http3"
+)
+
+// h3RoundTripper sends the requests with HTTP/3, using an HTTP/3 round tripper per server,
+// so that the one of a server with which no QUIC connection can be established is dropped without affecting the others.
+type h3RoundTripper struct {
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	servers map[string]*http3.RoundTripper
+}
+
+func newH3RoundTripper(tlsConfig *tls.Config) *h3RoundTripper {
+	if tlsConfig != nil {
+		tlsConfig = tlsConfig.Clone()
+	}
+
+	return &h3RoundTripper{
+		tlsConfig: tlsConfig,
+		servers:   make(map[string]*http3.RoundTripper),
+	}
+}
+
+func (h *h3RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	roundTripper := h.get(req.URL.Host)
+
+	resp, err := roundTripper.RoundTrip(req)
+
+	// The HTTP/3 round tripper keeps the failed connection attempt, it is thus dropped to try again later.
+	var protoErr *protocolError
+	if errors.As(err, &protoErr) {
+		h.drop(req.URL.Host, roundTripper)
+	}
+
+	return resp, err
+}
+
+func (h *h3RoundTripper) get(host string) *http3.RoundTripper {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if roundTripper, ok := h.servers[host]; ok {
+		return roundTripper
+	}
+
+	roundTripper := &http3.RoundTripper{
+		TLSClientConfig: h.tlsConfig,
+		Dial: func(network, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlySession, error) {
+			session, err := quic.DialAddrEarly(addr, tlsCfg, cfg)
+			if err != nil {
+				return nil, &protocolError{protocol: protocolH3, err: err}
+			}
+
+			return session, nil
+		},
+	}
+
+	h.servers[host] = roundTripper
+
+	return roundTripper
+}
+
+func (h *h3RoundTripper) drop(host string, roundTripper *http3.RoundTripper) {
+	h.mu.Lock()
+	if h.servers[host] == roundTripper {
+		delete(h.servers, host)
+	}
+	h.mu.Unlock()
+
+	_ = roundTripper.Close()
+}
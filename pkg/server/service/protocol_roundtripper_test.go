@@ -0,0 +1,119 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestProtocolRoundTripper(t *testing.T) {
+	protoHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Proto", req.Proto)
+	})
+
+	newTLSServer := func(enableHTTP2 bool) *httptest.Server {
+		srv := httptest.NewUnstartedServer(protoHandler)
+		srv.EnableHTTP2 = enableHTTP2
+		srv.StartTLS()
+		t.Cleanup(srv.Close)
+
+		return srv
+	}
+
+	srvH2 := newTLSServer(true)
+	srvHTTP1 := newTLSServer(false)
+
+	srvH2C := httptest.NewServer(h2c.NewHandler(protoHandler, &http2.Server{}))
+	t.Cleanup(srvH2C.Close)
+
+	testCases := []struct {
+		desc               string
+		protocol           string
+		url                string
+		upgrade            bool
+		expectedProtos     []string
+		expectedDowngrades float64
+	}{
+		{
+			desc:           "negotiated HTTP/2",
+			url:            srvH2.URL,
+			expectedProtos: []string{"HTTP/2.0"},
+		},
+		{
+			desc:           "pinned HTTP/1.1",
+			protocol:       "http/1.1",
+			url:            srvH2.URL,
+			expectedProtos: []string{"HTTP/1.1"},
+		},
+		{
+			desc:           "pinned HTTP/2",
+			protocol:       "h2",
+			url:            srvH2.URL,
+			expectedProtos: []string{"HTTP/2.0", "HTTP/2.0"},
+		},
+		{
+			desc:               "pinned HTTP/2 falling back on HTTP/1.1",
+			protocol:           "h2",
+			url:                srvHTTP1.URL,
+			expectedProtos:     []string{"HTTP/1.1", "HTTP/1.1"},
+			expectedDowngrades: 1,
+		},
+		{
+			desc:           "pinned HTTP/2 with a connection upgrade",
+			protocol:       "h2",
+			url:            srvH2.URL,
+			upgrade:        true,
+			expectedProtos: []string{"HTTP/1.1"},
+		},
+		{
+			desc:           "pinned HTTP/2 without TLS",
+			protocol:       "h2c",
+			url:            srvH2C.URL,
+			expectedProtos: []string{"HTTP/2.0"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			roundTripper, err := createRoundTripper(&dynamic.ServersTransport{InsecureSkipVerify: true, Protocol: test.protocol})
+			require.NoError(t, err)
+
+			passHostHeader := true
+			proxy, err := buildProxy(&passHostHeader, nil, roundTripper, nil)
+			require.NoError(t, err)
+
+			registry := newUpstreamMetricsRegistry()
+			handler := newUpstreamMetrics(proxy, registry, "foo")
+
+			for _, expected := range test.expectedProtos {
+				req := httptest.NewRequest(http.MethodGet, test.url, nil)
+				if test.upgrade {
+					req.Header.Set("Connection", "Upgrade")
+					req.Header.Set("Upgrade", "foo")
+				}
+
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, http.StatusOK, rw.Code)
+				assert.Equal(t, expected, rw.Header().Get("X-Proto"))
+			}
+
+			assert.Equal(t, test.expectedDowngrades, registry.values.get("downgrades", "service", "foo", "from", "h2", "to", "http/1.1"))
+		})
+	}
+}
+
+func TestProtocolRoundTripper_unsupportedProtocol(t *testing.T) {
+	_, err := createRoundTripper(&dynamic.ServersTransport{Protocol: "spdy"})
+	assert.Error(t, err)
+}
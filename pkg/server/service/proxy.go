@@ -14,6 +14,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/errorresponses"
 )
 
 // StatusClientClosedRequest non-standard HTTP status code for client disconnection.
@@ -76,10 +77,21 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 			delete(outReq.Header, "Sec-Websocket-Accept")
 			delete(outReq.Header, "Sec-Websocket-Protocol")
 			delete(outReq.Header, "Sec-Websocket-Version")
+
+			// The outgoing request holds a copy of the trailers declared by the client,
+			// which are only received once the request body has been read.
+			if body, ok := outReq.Body.(*trailersReader); ok {
+				body.dst = outReq.Trailer
+			}
 		},
 		Transport:     roundTripper,
 		FlushInterval: time.Duration(flushInterval),
 		BufferPool:    bufferPool,
+		ModifyResponse: func(resp *http.Response) error {
+			// The errors of the servers are not the ones generated by Traefik, so they are forwarded unchanged.
+			errorresponses.MarkUpstream(resp.Request)
+			return nil
+		},
 		ErrorHandler: func(w http.ResponseWriter, request *http.Request, err error) {
 			statusCode := http.StatusInternalServerError
 
@@ -108,7 +120,39 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 		},
 	}
 
-	return proxy, nil
+	// The reverse proxy does not establish tunnels, which are forwarded separately.
+	tunnel := newConnectTunnel(roundTripper, proxy.ErrorHandler)
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodConnect {
+			tunnel.ServeHTTP(rw, req)
+			return
+		}
+
+		if len(req.Trailer) > 0 && req.Body != nil {
+			req.Body = &trailersReader{ReadCloser: req.Body, src: req.Trailer}
+		}
+
+		proxy.ServeHTTP(rw, req)
+	}), nil
+}
+
+// trailersReader copies the request trailers into the ones of the outgoing request,
+// once they have been received, at the end of the request body.
+type trailersReader struct {
+	io.ReadCloser
+	src, dst http.Header
+}
+
+func (r *trailersReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) && r.dst != nil {
+		for key, values := range r.src {
+			r.dst[key] = values
+		}
+	}
+
+	return n, err
 }
 
 func statusText(statusCode int) string {
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// connectTunnel forwards the CONNECT requests to the servers, and relays the data of the tunnels they establish.
+// The connections of the HTTP/1 clients are hijacked, while the tunnels of the HTTP/2 clients are relayed through the streams of their requests.
+type connectTunnel struct {
+	dial         dialContextFunc
+	tlsConfig    *tls.Config
+	errorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// newConnectTunnel creates a connectTunnel opening the connections to the servers like the HTTP/1.1 transport of the round tripper.
+func newConnectTunnel(roundTripper http.RoundTripper, errorHandler func(http.ResponseWriter, *http.Request, error)) *connectTunnel {
+	tunnel := &connectTunnel{
+		dial:         (&net.Dialer{}).DialContext,
+		errorHandler: errorHandler,
+	}
+
+	if transport := http1Transport(roundTripper); transport != nil {
+		if transport.DialContext != nil {
+			tunnel.dial = transport.DialContext
+		}
+		tunnel.tlsConfig = transport.TLSClientConfig
+	}
+
+	return tunnel
+}
+
+// http1Transport returns the HTTP/1.1 transport of a round tripper, if any.
+func http1Transport(roundTripper http.RoundTripper) *http.Transport {
+	switch rt := roundTripper.(type) {
+	case *http.Transport:
+		return rt
+	case *smartRoundTripper:
+		return rt.http
+	case *pinnedRoundTripper:
+		return http1Transport(rt.http)
+	case *upstreamConnsRoundTripper:
+		return http1Transport(rt.RoundTripper)
+	case *upstreamTimeoutsRoundTripper:
+		return http1Transport(rt.RoundTripper)
+	default:
+		return nil
+	}
+}
+
+func (t *connectTunnel) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	backConn, err := t.dialServer(req.Context(), req.URL)
+	if err != nil {
+		t.errorHandler(rw, req, err)
+		return
+	}
+	defer func() { _ = backConn.Close() }()
+
+	outReq := &http.Request{
+		Method:     http.MethodConnect,
+		URL:        &url.URL{Host: req.Host},
+		Host:       req.Host,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     req.Header.Clone(),
+	}
+	outReq.Header.Del("Connection")
+	outReq.Header.Del("Proxy-Connection")
+
+	if err = outReq.Write(backConn); err != nil {
+		t.errorHandler(rw, req, err)
+		return
+	}
+
+	backReader := bufio.NewReader(backConn)
+
+	res, err := http.ReadResponse(backReader, outReq)
+	if err != nil {
+		t.errorHandler(rw, req, err)
+		return
+	}
+
+	// The server refused to establish the tunnel.
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer func() { _ = res.Body.Close() }()
+
+		copyResponseHeader(rw.Header(), res.Header)
+		rw.WriteHeader(res.StatusCode)
+		_, _ = io.Copy(rw, res.Body)
+		return
+	}
+
+	if req.ProtoMajor >= 2 {
+		copyResponseHeader(rw.Header(), res.Header)
+		rw.WriteHeader(res.StatusCode)
+
+		t.relay(req.Body, &flushWriter{w: rw}, backConn, backReader, func() { _ = req.Body.Close() })
+		return
+	}
+
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		t.errorHandler(rw, req, fmt.Errorf("can't establish a tunnel using non-Hijacker ResponseWriter type %T", rw))
+		return
+	}
+
+	clientConn, clientBuf, err := hijacker.Hijack()
+	if err != nil {
+		log.FromContext(req.Context()).Debugf("Unable to hijack the connection of the CONNECT request: %v", err)
+		return
+	}
+	defer func() { _ = clientConn.Close() }()
+
+	_, _ = fmt.Fprintf(clientBuf, "HTTP/1.1 %s\r\n", res.Status)
+	_ = res.Header.Write(clientBuf)
+	_, _ = clientBuf.WriteString("\r\n")
+	if err = clientBuf.Flush(); err != nil {
+		return
+	}
+
+	t.relay(clientBuf.Reader, clientConn, backConn, backReader, func() { _ = clientConn.Close() })
+}
+
+// relay copies the data between the client and the server until one of them closes the tunnel.
+func (t *connectTunnel) relay(clientReader io.Reader, clientWriter io.Writer, backConn net.Conn, backReader io.Reader, closeClient func()) {
+	var once sync.Once
+	closeAll := func() {
+		once.Do(func() {
+			_ = backConn.Close()
+			closeClient()
+		})
+	}
+
+	done := make(chan struct{}, 2)
+	copyData := func(dst io.Writer, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		closeAll()
+		done <- struct{}{}
+	}
+
+	go copyData(backConn, clientReader)
+	go copyData(clientWriter, backReader)
+
+	<-done
+	<-done
+}
+
+func (t *connectTunnel) dialServer(ctx context.Context, u *url.URL) (net.Conn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	conn, err := t.dial(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tunneled connections are not released to the idle pool.
+	if tracked, ok := conn.(*trackedConn); ok {
+		tracked.setState(connActive)
+	}
+
+	if u.Scheme != "https" {
+		return conn, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if t.tlsConfig != nil {
+		tlsConfig = t.tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err = tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func copyResponseHeader(dst, src http.Header) {
+	for key, values := range src {
+		for _, value := range values {
+			dst.Add(key, value)
+		}
+	}
+}
+
+// flushWriter flushes the data written to the tunnel of an HTTP/2 client right away.
+type flushWriter struct {
+	w io.Writer
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return n, err
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTunnelServer creates a server establishing the tunnels to the allowed host, and echoing their data.
+func newTunnelServer(t *testing.T, allowedHost string) *url.URL {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodConnect || req.Host != allowedHost {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		conn, brw, err := rw.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		_, err = conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+		require.NoError(t, err)
+
+		_, _ = io.Copy(conn, brw)
+	}))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return u
+}
+
+// connect sends a CONNECT request to the proxy, and returns the connection with the response.
+func connect(t *testing.T, proxyURL, host string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+
+	u, err := url.Parse(proxyURL)
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", u.Host)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("CONNECT " + host + " HTTP/1.1\r\nHost: " + host + "\r\n\r\n"))
+	require.NoError(t, err)
+
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	require.NoError(t, err)
+
+	return conn, br, res
+}
+
+func TestConnectTunnel(t *testing.T) {
+	serverURL := newTunnelServer(t, "example.com:443")
+
+	proxy, err := buildProxy(Bool(true), nil, http.DefaultTransport, nil)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		req.URL.Scheme = serverURL.Scheme
+		req.URL.Host = serverURL.Host
+
+		proxy.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(proxyServer.Close)
+
+	conn, br, res := connect(t, proxyServer.URL, "example.com:443")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	data := make([]byte, 4)
+	_, err = io.ReadFull(br, data)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(data))
+
+	// The tunnels refused by the server are not established.
+	_, _, res = connect(t, proxyServer.URL, "other.com:443")
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
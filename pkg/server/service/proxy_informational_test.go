@@ -0,0 +1,55 @@
+// +build go1.20
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy_earlyHints(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Link", "</style.css>; rel=preload; as=style")
+		rw.WriteHeader(http.StatusEarlyHints)
+
+		rw.Header().Set("Content-Type", "text/plain")
+		_, _ = rw.Write([]byte("foo"))
+	}))
+	t.Cleanup(backend.Close)
+
+	handler, err := buildProxy(Bool(true), nil, http.DefaultTransport, nil)
+	require.NoError(t, err)
+
+	proxy := createProxyWithForwarder(t, handler, backend.URL)
+	t.Cleanup(proxy.Close)
+
+	var hints []http.Header
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, http.Header(header))
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, proxy.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Len(t, hints, 1)
+	assert.Equal(t, "</style.css>; rel=preload; as=style", hints[0].Get("Link"))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+}
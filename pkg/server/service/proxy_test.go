@@ -1,12 +1,17 @@
 package service
 
 import (
+	"bufio"
+	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -35,3 +40,84 @@ func BenchmarkProxy(b *testing.B) {
 		handler.ServeHTTP(w, req)
 	}
 }
+
+func TestProxy_trailers(t *testing.T) {
+	requestTrailers := make(chan http.Header, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = ioutil.ReadAll(req.Body)
+		requestTrailers <- req.Trailer
+
+		rw.Header().Set("Trailer", "X-Response-Trailer")
+		_, _ = rw.Write([]byte("foo"))
+		rw.Header().Set("X-Response-Trailer", "bar")
+	}))
+	t.Cleanup(backend.Close)
+
+	handler, err := buildProxy(Bool(true), nil, http.DefaultTransport, nil)
+	require.NoError(t, err)
+
+	proxy := createProxyWithForwarder(t, handler, backend.URL)
+	t.Cleanup(proxy.Close)
+
+	conn, err := net.Dial("tcp", proxy.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The request is written by hand, as the HTTP client sends the trailers declared by the request, and not the request ones.
+	_, err = fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nTransfer-Encoding: chunked\r\nTrailer: X-Request-Trailer\r\n\r\n"+
+		"3\r\nfoo\r\n0\r\nX-Request-Trailer: baz\r\n\r\n")
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", string(body))
+	assert.Equal(t, "bar", resp.Trailer.Get("X-Response-Trailer"))
+	assert.Equal(t, "baz", (<-requestTrailers).Get("X-Request-Trailer"))
+}
+
+func TestProxy_expectContinue(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		_, _ = rw.Write(body)
+	}))
+	t.Cleanup(backend.Close)
+
+	handler, err := buildProxy(Bool(true), nil, http.DefaultTransport, nil)
+	require.NoError(t, err)
+
+	proxy := createProxyWithForwarder(t, handler, backend.URL)
+	t.Cleanup(proxy.Close)
+
+	conn, err := net.Dial("tcp", proxy.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = fmt.Fprint(conn, "POST / HTTP/1.1\r\nHost: localhost\r\nExpect: 100-continue\r\nContent-Length: 3\r\n\r\n")
+	require.NoError(t, err)
+
+	// The body is only sent once the 100 (Continue) response has been received.
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1 100 Continue\r\n", line)
+
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "\r\n", line)
+
+	_, err = fmt.Fprint(conn, "foo")
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "foo", string(body))
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const resolvConfPath = "/etc/resolv.conf"
+
+// dnsQuestion identifies a DNS answer in the cache.
+type dnsQuestion struct {
+	name  string
+	qtype uint16
+}
+
+type dnsAnswer struct {
+	records   []dns.RR
+	expiresAt time.Time
+}
+
+// dnsResolver resolves the servers addresses by querying its own DNS servers,
+// and caches the answers for their TTL, clamped between the minimum and maximum TTLs.
+type dnsResolver struct {
+	client  *dns.Client
+	servers []string
+	minTTL  time.Duration
+	maxTTL  time.Duration
+
+	mu    sync.Mutex
+	cache map[dnsQuestion]dnsAnswer
+}
+
+func newDNSResolver(cfg *dynamic.ServersResolver) (*dnsResolver, error) {
+	if cfg.MinTTL < 0 || cfg.MaxTTL < 0 {
+		return nil, errors.New("the resolver TTLs must be positive")
+	}
+
+	if cfg.MaxTTL > 0 && cfg.MinTTL > cfg.MaxTTL {
+		return nil, fmt.Errorf("the resolver minTTL (%s) is greater than its maxTTL (%s)", cfg.MinTTL, cfg.MaxTTL)
+	}
+
+	var servers []string
+	for _, server := range cfg.Servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		servers = append(servers, server)
+	}
+
+	if len(servers) == 0 {
+		config, err := dns.ClientConfigFromFile(resolvConfPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resolver configuration file %s: %w", resolvConfPath, err)
+		}
+
+		for _, server := range config.Servers {
+			servers = append(servers, net.JoinHostPort(server, config.Port))
+		}
+	}
+
+	return &dnsResolver{
+		client:  &dns.Client{Timeout: 5 * time.Second},
+		servers: servers,
+		minTTL:  time.Duration(cfg.MinTTL),
+		maxTTL:  time.Duration(cfg.MaxTTL),
+		cache:   make(map[dnsQuestion]dnsAnswer),
+	}, nil
+}
+
+// dialContext returns a dial function connecting to the addresses resolved by the resolver.
+// The SRV names, such as _http._tcp.example.com, are resolved to the target of one of their records,
+// chosen by priority and weight, whose port replaces the one of the dialed address.
+func (r *dnsResolver) dialContext(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		if isSRVName(host) {
+			record, err := r.lookupSRV(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+
+			host, port = strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))
+		}
+
+		ips, err := r.lookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ip := range ips {
+			var conn net.Conn
+			conn, err = dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+
+		return nil, err
+	}
+}
+
+// lookupIP returns the IPs of the host, the IPv4 ones first, as allowed by the network.
+func (r *dnsResolver) lookupIP(ctx context.Context, network, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	var qtypes []uint16
+	switch network {
+	case "tcp4", "udp4":
+		qtypes = []uint16{dns.TypeA}
+	case "tcp6", "udp6":
+		qtypes = []uint16{dns.TypeAAAA}
+	default:
+		qtypes = []uint16{dns.TypeA, dns.TypeAAAA}
+	}
+
+	var ips []string
+	for _, qtype := range qtypes {
+		records, err := r.lookup(ctx, host, qtype)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			switch rr := record.(type) {
+			case *dns.A:
+				ips = append(ips, rr.A.String())
+			case *dns.AAAA:
+				ips = append(ips, rr.AAAA.String())
+			}
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	return ips, nil
+}
+
+// lookupSRV returns one of the SRV records of the name, chosen by priority and weight.
+func (r *dnsResolver) lookupSRV(ctx context.Context, name string) (*dns.SRV, error) {
+	records, err := r.lookup(ctx, name, dns.TypeSRV)
+	if err != nil {
+		return nil, err
+	}
+
+	var srvs []*dns.SRV
+	for _, record := range records {
+		// The "." target means that the service is not available at this domain.
+		if srv, ok := record.(*dns.SRV); ok && srv.Target != "." {
+			srvs = append(srvs, srv)
+		}
+	}
+
+	if len(srvs) == 0 {
+		return nil, &net.DNSError{Err: "no SRV record", Name: name, IsNotFound: true}
+	}
+
+	return pickSRV(srvs), nil
+}
+
+// pickSRV chooses a record among the ones having the lowest priority,
+// randomly and proportionally to their weight, as described by RFC 2782.
+func pickSRV(records []*dns.SRV) *dns.SRV {
+	var candidates []*dns.SRV
+	var total int
+	for _, record := range records {
+		if len(candidates) > 0 && record.Priority > candidates[0].Priority {
+			continue
+		}
+
+		if len(candidates) > 0 && record.Priority < candidates[0].Priority {
+			candidates = candidates[:0]
+			total = 0
+		}
+
+		candidates = append(candidates, record)
+		total += int(record.Weight)
+	}
+
+	if total == 0 {
+		return candidates[rand.Intn(len(candidates))]
+	}
+
+	n := rand.Intn(total)
+	for _, candidate := range candidates {
+		if n < int(candidate.Weight) {
+			return candidate
+		}
+		n -= int(candidate.Weight)
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+// lookup returns the records answered for the name and type by the first DNS server answering, from the cache if possible.
+func (r *dnsResolver) lookup(ctx context.Context, name string, qtype uint16) ([]dns.RR, error) {
+	question := dnsQuestion{name: dns.Fqdn(name), qtype: qtype}
+
+	if records, ok := r.cached(question); ok {
+		return records, nil
+	}
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(question.name, qtype)
+
+	err := fmt.Errorf("no DNS server to resolve %s", name)
+	for _, server := range r.servers {
+		var resp *dns.Msg
+		resp, err = r.exchange(ctx, msg, server)
+		if err != nil {
+			continue
+		}
+
+		// The name does not exist, it is not worth asking the other servers.
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, nil
+		}
+
+		if resp.Rcode != dns.RcodeSuccess {
+			err = fmt.Errorf("DNS server %s answered %s for %s", server, dns.RcodeToString[resp.Rcode], name)
+			continue
+		}
+
+		r.store(question, resp.Answer)
+
+		return resp.Answer, nil
+	}
+
+	return nil, err
+}
+
+// exchange sends the query to the server, and sends it again over TCP when the UDP answer is truncated.
+func (r *dnsResolver) exchange(ctx context.Context, msg *dns.Msg, server string) (*dns.Msg, error) {
+	resp, _, err := r.client.ExchangeContext(ctx, msg, server)
+	if err != nil {
+		return nil, fmt.Errorf("exchange error for DNS server %s: %w", server, err)
+	}
+
+	if resp.Truncated {
+		client := &dns.Client{Net: "tcp", Timeout: r.client.Timeout}
+
+		resp, _, err = client.ExchangeContext(ctx, msg, server)
+		if err != nil {
+			return nil, fmt.Errorf("exchange error for DNS server %s over TCP: %w", server, err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *dnsResolver) cached(question dnsQuestion) ([]dns.RR, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	answer, ok := r.cache[question]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(answer.expiresAt) {
+		delete(r.cache, question)
+		return nil, false
+	}
+
+	return answer.records, true
+}
+
+// store caches the records for their lowest TTL, clamped between the minimum and maximum TTLs.
+func (r *dnsResolver) store(question dnsQuestion, records []dns.RR) {
+	var ttl time.Duration
+	for i, record := range records {
+		recordTTL := time.Duration(record.Header().Ttl) * time.Second
+		if i == 0 || recordTTL < ttl {
+			ttl = recordTTL
+		}
+	}
+
+	if ttl < r.minTTL {
+		ttl = r.minTTL
+	}
+
+	if r.maxTTL > 0 && ttl > r.maxTTL {
+		ttl = r.maxTTL
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.cache[question] = dnsAnswer{records: records, expiresAt: time.Now().Add(ttl)}
+	r.mu.Unlock()
+}
+
+// isSRVName reports whether the host is an SRV name, such as _http._tcp.example.com.
+func isSRVName(host string) bool {
+	return strings.HasPrefix(host, "_") && strings.Contains(host, "._")
+}
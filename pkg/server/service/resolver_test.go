@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// startDNSServer starts a DNS server answering with the given records, and returns its address and its query counter.
+func startDNSServer(t *testing.T, records ...string) (string, *int32) {
+	t.Helper()
+
+	var answers []dns.RR
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		require.NoError(t, err)
+		answers = append(answers, rr)
+	}
+
+	var queries int32
+	handler := dns.HandlerFunc(func(rw dns.ResponseWriter, req *dns.Msg) {
+		atomic.AddInt32(&queries, 1)
+
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+
+		for _, answer := range answers {
+			if answer.Header().Name == req.Question[0].Name && answer.Header().Rrtype == req.Question[0].Qtype {
+				resp.Answer = append(resp.Answer, answer)
+			}
+		}
+
+		_ = rw.WriteMsg(resp)
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	return conn.LocalAddr().String(), &queries
+}
+
+func TestNewDNSResolver(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.ServersResolver
+		expectedServers []string
+		expectedError   bool
+	}{
+		{
+			desc:            "servers",
+			config:          dynamic.ServersResolver{Servers: []string{"10.0.0.1", "10.0.0.2:5353", "::1"}},
+			expectedServers: []string{"10.0.0.1:53", "10.0.0.2:5353", "[::1]:53"},
+		},
+		{
+			desc: "minTTL greater than maxTTL",
+			config: dynamic.ServersResolver{
+				Servers: []string{"10.0.0.1"},
+				MinTTL:  ptypes.Duration(time.Minute),
+				MaxTTL:  ptypes.Duration(time.Second),
+			},
+			expectedError: true,
+		},
+		{
+			desc: "negative TTL",
+			config: dynamic.ServersResolver{
+				Servers: []string{"10.0.0.1"},
+				MinTTL:  ptypes.Duration(-time.Second),
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resolver, err := newDNSResolver(&test.config)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedServers, resolver.servers)
+		})
+	}
+}
+
+func TestDNSResolver_dialContext(t *testing.T) {
+	addr, _ := startDNSServer(t,
+		"backend.test. 60 IN A 10.0.0.1",
+		"backend.test. 60 IN AAAA ::1",
+		"_http._tcp.backends.test. 60 IN SRV 10 100 8080 backend.test.",
+		"_http._tcp.backends.test. 60 IN SRV 20 100 9090 other.test.",
+		"_http._tcp.down.test. 60 IN SRV 10 100 0 .",
+	)
+
+	resolver, err := newDNSResolver(&dynamic.ServersResolver{Servers: []string{addr}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		network       string
+		addr          string
+		expectedAddrs []string
+		expectedError bool
+	}{
+		{
+			desc:          "IP",
+			network:       "tcp",
+			addr:          "10.0.0.2:80",
+			expectedAddrs: []string{"10.0.0.2:80"},
+		},
+		{
+			desc:          "name",
+			network:       "tcp",
+			addr:          "backend.test:80",
+			expectedAddrs: []string{"10.0.0.1:80", "[::1]:80"},
+		},
+		{
+			desc:          "name with IPv4 only",
+			network:       "tcp4",
+			addr:          "backend.test:80",
+			expectedAddrs: []string{"10.0.0.1:80"},
+		},
+		{
+			desc:          "SRV name",
+			network:       "tcp",
+			addr:          "_http._tcp.backends.test:80",
+			expectedAddrs: []string{"10.0.0.1:8080", "[::1]:8080"},
+		},
+		{
+			desc:          "unknown name",
+			network:       "tcp",
+			addr:          "unknown.test:80",
+			expectedError: true,
+		},
+		{
+			desc:          "unavailable SRV name",
+			network:       "tcp",
+			addr:          "_http._tcp.down.test:80",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var dialed []string
+			dial := resolver.dialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+				dialed = append(dialed, addr)
+				return nil, errors.New("unreachable")
+			})
+
+			_, err := dial(context.Background(), test.network, test.addr)
+			require.Error(t, err)
+
+			if test.expectedError {
+				assert.Empty(t, dialed)
+				return
+			}
+
+			assert.Equal(t, test.expectedAddrs, dialed)
+		})
+	}
+}
+
+func TestDNSResolver_cache(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		minTTL          time.Duration
+		maxTTL          time.Duration
+		record          string
+		expectedQueries int32
+	}{
+		{
+			desc:            "cached for the record TTL",
+			record:          "backend.test. 60 IN A 10.0.0.1",
+			expectedQueries: 1,
+		},
+		{
+			desc:            "not cached without TTL",
+			record:          "backend.test. 0 IN A 10.0.0.1",
+			expectedQueries: 2,
+		},
+		{
+			desc:            "cached for the minimum TTL",
+			minTTL:          time.Minute,
+			record:          "backend.test. 0 IN A 10.0.0.1",
+			expectedQueries: 1,
+		},
+		{
+			desc:            "expired after the maximum TTL",
+			maxTTL:          time.Nanosecond,
+			record:          "backend.test. 60 IN A 10.0.0.1",
+			expectedQueries: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, queries := startDNSServer(t, test.record)
+
+			resolver, err := newDNSResolver(&dynamic.ServersResolver{
+				Servers: []string{addr},
+				MinTTL:  ptypes.Duration(test.minTTL),
+				MaxTTL:  ptypes.Duration(test.maxTTL),
+			})
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				ips, err := resolver.lookupIP(context.Background(), "tcp4", "backend.test")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"10.0.0.1"}, ips)
+
+				time.Sleep(time.Millisecond)
+			}
+
+			assert.Equal(t, test.expectedQueries, atomic.LoadInt32(queries))
+		})
+	}
+}
+
+func TestPickSRV(t *testing.T) {
+	records := []*dns.SRV{
+		{Priority: 20, Weight: 100, Target: "backup."},
+		{Priority: 10, Weight: 0, Target: "never."},
+		{Priority: 10, Weight: 100, Target: "primary."},
+	}
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "primary.", pickSRV(records).Target)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[pickSRV([]*dns.SRV{{Weight: 1, Target: "light."}, {Weight: 3, Target: "heavy."}}).Target]++
+	}
+
+	assert.Greater(t, counts["heavy."], counts["light."])
+}
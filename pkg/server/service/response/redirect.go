@@ -0,0 +1,69 @@
+package response
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// locationData is the data given to the location templates.
+type locationData struct {
+	Scheme string
+	Host   string
+	Path   string
+	Query  string
+}
+
+type redirect struct {
+	location   *template.Template
+	statusCode int
+}
+
+// NewRedirect creates a handler redirecting the requests to the location built from its template.
+func NewRedirect(config *dynamic.Redirection) (http.Handler, error) {
+	if config.Location == "" {
+		return nil, fmt.Errorf("the redirection location is required")
+	}
+
+	if config.StatusCode < 300 || config.StatusCode > 399 {
+		return nil, fmt.Errorf("invalid redirection status code %d: must be a 3xx status code", config.StatusCode)
+	}
+
+	location, err := template.New("location").Option("missingkey=error").Parse(config.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirection location: %w", err)
+	}
+
+	return &redirect{
+		location:   location,
+		statusCode: config.StatusCode,
+	}, nil
+}
+
+func (r *redirect) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	scheme := "http"
+	if req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	data := locationData{
+		Scheme: scheme,
+		Host:   req.Host,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+	}
+
+	var location bytes.Buffer
+	if err := r.location.Execute(&location, data); err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to build the redirection location: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Location", location.String())
+	rw.WriteHeader(r.statusCode)
+}
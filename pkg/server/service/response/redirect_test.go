@@ -0,0 +1,97 @@
+package response
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewRedirect(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.Redirection
+		url              string
+		tls              bool
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			desc:             "fixed location",
+			config:           dynamic.Redirection{Location: "https://example.com/", StatusCode: http.StatusFound},
+			url:              "http://foo.com/bar",
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://example.com/",
+		},
+		{
+			desc:             "templated location",
+			config:           dynamic.Redirection{Location: "https://new.example.com{{ .Path }}?{{ .Query }}", StatusCode: http.StatusMovedPermanently},
+			url:              "http://foo.com/bar?a=b",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "https://new.example.com/bar?a=b",
+		},
+		{
+			desc:             "scheme and host",
+			config:           dynamic.Redirection{Location: "{{ .Scheme }}://www.{{ .Host }}{{ .Path }}", StatusCode: http.StatusPermanentRedirect},
+			url:              "https://foo.com/bar",
+			tls:              true,
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "https://www.foo.com/bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := NewRedirect(&test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			if test.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedLocation, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestNewRedirect_invalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.Redirection
+	}{
+		{
+			desc:   "missing location",
+			config: dynamic.Redirection{StatusCode: http.StatusFound},
+		},
+		{
+			desc:   "not a redirection status code",
+			config: dynamic.Redirection{Location: "https://example.com", StatusCode: http.StatusOK},
+		},
+		{
+			desc:   "invalid template",
+			config: dynamic.Redirection{Location: "https://example.com{{ .Path ", StatusCode: http.StatusFound},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRedirect(&test.config)
+			assert.Error(t, err)
+		})
+	}
+}
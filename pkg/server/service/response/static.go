@@ -0,0 +1,51 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+type static struct {
+	statusCode int
+	headers    map[string]string
+	body       []byte
+}
+
+// NewStatic creates a handler answering all the requests with the same response.
+func NewStatic(config *dynamic.StaticResponse) (http.Handler, error) {
+	if config.StatusCode < 100 || config.StatusCode > 599 {
+		return nil, fmt.Errorf("invalid status code %d", config.StatusCode)
+	}
+
+	return &static{
+		statusCode: config.StatusCode,
+		headers:    config.Headers,
+		body:       []byte(config.Body),
+	}, nil
+}
+
+func (s *static) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	for name, value := range s.headers {
+		rw.Header().Set(name, value)
+	}
+
+	if len(s.body) == 0 {
+		rw.WriteHeader(s.statusCode)
+		return
+	}
+
+	rw.Header().Set("Content-Length", strconv.Itoa(len(s.body)))
+	rw.WriteHeader(s.statusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(s.body); err != nil {
+		log.FromContext(req.Context()).Debugf("Unable to write the static response: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewStatic(t *testing.T) {
+	handler, err := NewStatic(&dynamic.StaticResponse{
+		StatusCode: http.StatusServiceUnavailable,
+		Headers:    map[string]string{"Content-Type": "text/plain", "Retry-After": "120"},
+		Body:       "Under maintenance",
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/bar", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "120", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, "17", recorder.Header().Get("Content-Length"))
+	assert.Equal(t, "Under maintenance", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "http://foo.com/bar", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "17", recorder.Header().Get("Content-Length"))
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestNewStatic_invalidStatusCode(t *testing.T) {
+	_, err := NewStatic(&dynamic.StaticResponse{StatusCode: 42})
+	assert.Error(t, err)
+}
@@ -39,6 +39,7 @@ type RoundTripperManager struct {
 	rtLock        sync.RWMutex
 	roundTrippers map[string]http.RoundTripper
 	configs       map[string]*dynamic.ServersTransport
+	generation    uint64
 }
 
 // Update updates the roundtrippers configurations.
@@ -51,6 +52,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 		if !ok {
 			delete(r.configs, configName)
 			delete(r.roundTrippers, configName)
+			r.generation++
 			continue
 		}
 
@@ -58,6 +60,8 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
+		r.generation++
+
 		var err error
 		r.roundTrippers[configName], err = createRoundTripper(newConfig)
 		if err != nil {
@@ -71,6 +75,8 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
+		r.generation++
+
 		var err error
 		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig)
 		if err != nil {
@@ -82,6 +88,14 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 	r.configs = newConfigs
 }
 
+// Generation returns a number changing each time a roundtripper is added, removed, or replaced.
+func (r *RoundTripperManager) Generation() uint64 {
+	r.rtLock.RLock()
+	defer r.rtLock.RUnlock()
+
+	return r.generation
+}
+
 // Get get a roundtripper by name.
 func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 	if len(name) == 0 {
@@ -98,6 +112,22 @@ func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 	return nil, fmt.Errorf("servers transport not found %s", name)
 }
 
+// Hedging returns the hedging policy of a roundtripper by name, which is nil when the requests are not hedged.
+func (r *RoundTripperManager) Hedging(name string) *dynamic.Hedging {
+	if len(name) == 0 {
+		name = "default@internal"
+	}
+
+	r.rtLock.RLock()
+	defer r.rtLock.RUnlock()
+
+	if cfg, ok := r.configs[name]; ok {
+		return cfg.Hedging
+	}
+
+	return nil
+}
+
 // createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHostin Traefik at this point in time.
@@ -116,9 +146,27 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		dialer.Timeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
 	}
 
+	dialContext := dialer.DialContext
+	if cfg.Resolver != nil {
+		resolver, err := newDNSResolver(cfg.Resolver)
+		if err != nil {
+			return nil, err
+		}
+
+		dialContext = resolver.dialContext(dialer.DialContext)
+	}
+
+	var maxConnLifetime time.Duration
+	if cfg.ForwardingTimeouts != nil {
+		maxConnLifetime = time.Duration(cfg.ForwardingTimeouts.MaxConnLifetime)
+	}
+
+	conns := newUpstreamConns(maxConnLifetime)
+	dial := conns.track(dialContext)
+
 	transport := &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		DialContext:           dial,
 		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -148,7 +196,18 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		}
 	}
 
-	return newSmartRoundTripper(transport)
+	var roundTripper http.RoundTripper
+	var err error
+	if cfg.Protocol != "" {
+		roundTripper, err = newProtocolRoundTripper(cfg.Protocol, transport, dial)
+	} else {
+		roundTripper, err = newSmartRoundTripper(transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &upstreamConnsRoundTripper{RoundTripper: roundTripper, conns: conns}, nil
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
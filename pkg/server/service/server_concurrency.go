@@ -0,0 +1,200 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// serverSlots holds the request slots of a server, and counts the requests waiting for a slot.
+type serverSlots struct {
+	slots chan struct{}
+
+	mu     sync.Mutex
+	queued int
+}
+
+// enqueue adds a request to the queue of the server, unless the queue is full.
+func (s *serverSlots) enqueue(size int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.queued >= size {
+		return false
+	}
+
+	s.queued++
+	return true
+}
+
+func (s *serverSlots) dequeue() {
+	s.mu.Lock()
+	s.queued--
+	s.mu.Unlock()
+}
+
+// idle reports whether the server has neither requests in flight nor requests waiting for a slot.
+func (s *serverSlots) idle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.slots) == 0 && s.queued == 0
+}
+
+// serverConcurrencies remembers the request slots of the servers across the configuration changes,
+// so that the requests in flight during a reload are counted against the limits of the new configuration.
+type serverConcurrencies struct {
+	mu      sync.Mutex
+	servers map[string]*serverSlots
+}
+
+func newServerConcurrencies() *serverConcurrencies {
+	return &serverConcurrencies{servers: make(map[string]*serverSlots)}
+}
+
+// get returns the request slots of a server of a service, which are reset when the maximum number of requests changed.
+func (c *serverConcurrencies) get(serviceName, server string, maxRequests int) *serverSlots {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := concurrencyKey(serviceName, server)
+
+	slots, ok := c.servers[key]
+	if !ok || cap(slots.slots) != maxRequests {
+		slots = &serverSlots{slots: make(chan struct{}, maxRequests)}
+		c.servers[key] = slots
+	}
+
+	return slots
+}
+
+// prune forgets the request slots of the servers which are not part of the given services anymore,
+// once they have no requests in flight.
+// The servers still handling requests are forgotten on a later configuration change.
+func (c *serverConcurrencies) prune(services map[string]*runtime.ServiceInfo) {
+	current := make(map[string]struct{})
+	for serviceName, service := range services {
+		if service.Service == nil || service.LoadBalancer == nil || service.LoadBalancer.MaxConcurrentRequests == nil {
+			continue
+		}
+
+		for _, srv := range service.LoadBalancer.Servers {
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				continue
+			}
+
+			current[concurrencyKey(serviceName, u.String())] = struct{}{}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, slots := range c.servers {
+		if _, ok := current[key]; ok || !slots.idle() {
+			continue
+		}
+
+		delete(c.servers, key)
+	}
+}
+
+func concurrencyKey(serviceName, server string) string {
+	return serviceName + " " + server
+}
+
+// serverConcurrency limits the number of requests forwarded concurrently to each server of a service.
+// The requests beyond the limit wait in a bounded queue until a request to the server ends,
+// and are rejected when the queue is full or when they waited for longer than the queue timeout.
+type serverConcurrency struct {
+	next        http.Handler
+	serviceName string
+	// servers holds the request slots of the servers, keyed by the scheme and host of their URL.
+	servers   map[string]*serverSlots
+	queueSize int
+	timeout   time.Duration
+}
+
+func newServerConcurrency(next http.Handler, concurrencies *serverConcurrencies, serviceName string, servers map[string]string, maxRequests, queueSize int, timeout time.Duration) *serverConcurrency {
+	slots := make(map[string]*serverSlots, len(servers))
+	for key, server := range servers {
+		slots[key] = concurrencies.get(serviceName, server, maxRequests)
+	}
+
+	return &serverConcurrency{
+		next:        next,
+		serviceName: serviceName,
+		servers:     slots,
+		queueSize:   queueSize,
+		timeout:     timeout,
+	}
+}
+
+func (s *serverConcurrency) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The requests to the servers which are not part of the service anymore, such as the draining ones, are not limited.
+	server, ok := s.servers[serverKey(req.URL)]
+	if !ok {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	select {
+	case server.slots <- struct{}{}:
+	default:
+		if !s.wait(req, server) {
+			log.FromContext(req.Context()).Debugf("Too many concurrent requests to the server %s of the service %s, the request is rejected", req.URL.Host, s.serviceName)
+			http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	defer func() { <-server.slots }()
+
+	s.next.ServeHTTP(rw, req)
+}
+
+// take takes a slot of a server without waiting for it, and returns the function releasing it.
+// The servers which are not part of the service need no slot.
+func (s *serverConcurrency) take(u *url.URL) (func(), bool) {
+	server, ok := s.servers[serverKey(u)]
+	if !ok {
+		return nil, true
+	}
+
+	select {
+	case server.slots <- struct{}{}:
+		return func() { <-server.slots }, true
+	default:
+		return nil, false
+	}
+}
+
+// wait waits for a slot of the server, and tells whether the slot was taken.
+func (s *serverConcurrency) wait(req *http.Request, server *serverSlots) bool {
+	if s.queueSize <= 0 || !server.enqueue(s.queueSize) {
+		return false
+	}
+
+	defer server.dequeue()
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case server.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-req.Context().Done():
+		return false
+	}
+}
+
+func serverKey(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
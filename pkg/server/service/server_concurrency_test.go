@@ -0,0 +1,140 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+func TestServerConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 10)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		started <- struct{}{}
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	servers := map[string]string{"http://10.0.0.1:80": "http://10.0.0.1:80"}
+	handler := newServerConcurrency(next, newServerConcurrencies(), "foo@file", servers, 1, 1, time.Minute)
+
+	codes := make(chan int, 3)
+	var wg sync.WaitGroup
+	serve := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://10.0.0.1:80/", nil))
+			codes <- rw.Code
+		}()
+	}
+
+	// The first request is forwarded, and the second one waits in the queue.
+	serve()
+	<-started
+	serve()
+
+	assert.Eventually(t, func() bool {
+		slots := handler.servers["http://10.0.0.1:80"]
+		slots.mu.Lock()
+		defer slots.mu.Unlock()
+
+		return slots.queued == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// The queue is full, so the third request is rejected.
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://10.0.0.1:80/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	// The requests to the other servers are not limited.
+	close(release)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://10.0.0.2:80/", nil))
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+}
+
+func TestServerConcurrency_queueTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+	})
+
+	servers := map[string]string{"http://10.0.0.1:80": "http://10.0.0.1:80"}
+	handler := newServerConcurrency(next, newServerConcurrencies(), "foo@file", servers, 1, 1, 10*time.Millisecond)
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://10.0.0.1:80/", nil))
+
+	assert.Eventually(t, func() bool {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://10.0.0.1:80/", nil))
+
+		return rw.Code == http.StatusServiceUnavailable
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestServerConcurrencies_get(t *testing.T) {
+	concurrencies := newServerConcurrencies()
+
+	slots := concurrencies.get("foo@file", "http://10.0.0.1:80", 2)
+	require.NotNil(t, slots)
+
+	// The slots are kept across the configuration changes.
+	assert.Same(t, slots, concurrencies.get("foo@file", "http://10.0.0.1:80", 2))
+
+	// They are reset when the maximum number of requests changes.
+	assert.NotSame(t, slots, concurrencies.get("foo@file", "http://10.0.0.1:80", 3))
+	assert.NotSame(t, slots, concurrencies.get("bar@file", "http://10.0.0.1:80", 2))
+}
+
+func TestServerConcurrencies_prune(t *testing.T) {
+	concurrencies := newServerConcurrencies()
+
+	idle := concurrencies.get("foo@file", "http://10.0.0.1:80", 2)
+	busy := concurrencies.get("foo@file", "http://10.0.0.2:80", 2)
+	kept := concurrencies.get("foo@file", "http://10.0.0.3:80", 2)
+
+	busy.slots <- struct{}{}
+
+	services := map[string]*runtime.ServiceInfo{
+		"foo@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers:               []dynamic.Server{{URL: "http://10.0.0.3:80"}},
+					MaxConcurrentRequests: func(v int) *int { return &v }(2),
+				},
+			},
+		},
+	}
+
+	concurrencies.prune(services)
+
+	assert.NotSame(t, idle, concurrencies.get("foo@file", "http://10.0.0.1:80", 2))
+	assert.Same(t, busy, concurrencies.get("foo@file", "http://10.0.0.2:80", 2))
+	assert.Same(t, kept, concurrencies.get("foo@file", "http://10.0.0.3:80", 2))
+
+	// The server is forgotten once its requests ended.
+	<-busy.slots
+	concurrencies.prune(services)
+
+	assert.NotSame(t, busy, concurrencies.get("foo@file", "http://10.0.0.2:80", 2))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/containous/alice"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/healthcheck"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
@@ -20,11 +21,14 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/emptybackendhandler"
 	metricsMiddle "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/pipelining"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdebug"
+	tracingMiddle "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/cookie"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/mirror"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/traefik/traefik/v2/pkg/server/service/response"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
@@ -38,6 +42,7 @@ const defaultMaxBodySize int64 = -1
 // RoundTripperGetter is a roundtripper getter interface.
 type RoundTripperGetter interface {
 	Get(name string) (http.RoundTripper, error)
+	Hedging(name string) *dynamic.Hedging
 }
 
 // NewManager creates a new Manager.
@@ -49,6 +54,9 @@ func NewManager(configs map[string]*runtime.ServiceInfo, metricsRegistry metrics
 		roundTripperManager: roundTripperManager,
 		balancers:           make(map[string]healthcheck.Balancers),
 		configs:             configs,
+		promotions:          newPromotions(),
+		serverConcurrencies: newServerConcurrencies(),
+		adaptiveLimits:      newAdaptiveLimits(),
 	}
 }
 
@@ -64,6 +72,18 @@ type Manager struct {
 	// which is why there is not just one Balancer per service name.
 	balancers map[string]healthcheck.Balancers
 	configs   map[string]*runtime.ServiceInfo
+	// upstreamOverride enables the header forcing the server of the services, when not nil.
+	upstreamOverride *static.UpstreamOverride
+	// requestDebug enables the logging of the attempts to reach the servers, for the requests carrying the debug header.
+	requestDebug bool
+	// stickyDrains enables the drain period of the sticky cookies, when not nil.
+	stickyDrains *stickyDrains
+	// promotions holds the state of the promotions of the weighted services.
+	promotions *promotions
+	// serverConcurrencies holds the request slots of the servers limited by MaxConcurrentRequests.
+	serverConcurrencies *serverConcurrencies
+	// adaptiveLimits holds the concurrency limits of the services with load shedding.
+	adaptiveLimits *adaptiveLimits
 }
 
 // BuildHTTP Creates a http.Handler for a service configuration.
@@ -115,6 +135,24 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.Redirect != nil:
+		var err error
+		lb, err = m.getResponseServiceHandler(ctx, serviceName, func() (http.Handler, error) {
+			return response.NewRedirect(conf.Redirect)
+		})
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.Static != nil:
+		var err error
+		lb, err = m.getResponseServiceHandler(ctx, serviceName, func() (http.Handler, error) {
+			return response.NewStatic(conf.Static)
+		})
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -155,6 +193,10 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		config.Sticky.Cookie.Name = cookie.GetName(config.Sticky.Cookie.Name, serviceName)
 	}
 
+	if config.Promotion != nil {
+		return m.getPromotionHandler(ctx, serviceName, config)
+	}
+
 	balancer := wrr.New(config.Sticky)
 	for _, service := range config.Services {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
@@ -167,6 +209,71 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	return balancer, nil
 }
 
+// getPromotionHandler builds the handler of a weighted service whose canary service is being promoted.
+// The other services are load-balanced following their weights.
+func (m *Manager) getPromotionHandler(ctx context.Context, serviceName string, config *dynamic.WeightedRoundRobin) (http.Handler, error) {
+	if err := validatePromotion(config); err != nil {
+		return nil, err
+	}
+
+	handler := &promotionHandler{otherNames: make(map[string]struct{})}
+	if config.Sticky != nil {
+		handler.stickyCookie = config.Sticky.Cookie
+	}
+
+	others := wrr.New(config.Sticky)
+	for _, service := range config.Services {
+		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if service.Name == config.Promotion.Canary {
+			handler.canary = serviceHandler
+			continue
+		}
+
+		others.AddService(service.Name, serviceHandler, service.Weight)
+		handler.otherNames[service.Name] = struct{}{}
+	}
+	handler.others = others
+
+	registry := m.metricsRegistry
+	if registry == nil {
+		registry = metrics.NewVoidRegistry()
+	}
+
+	canary := config.Promotion.Canary
+	handler.state = m.promotions.get(serviceName, config.Promotion,
+		registry.ServiceCanaryWeightGauge().With("service", serviceName, "canary", canary),
+		registry.ServiceCanaryAbortsCounter().With("service", serviceName, "canary", canary))
+
+	if conf, ok := m.configs[serviceName]; ok {
+		conf.SetPromotionStatus(handler.state.status)
+	}
+
+	return handler, nil
+}
+
+// getResponseServiceHandler builds the handler of a service answering the requests by itself,
+// which are logged and measured like the ones of the other services.
+func (m *Manager) getResponseServiceHandler(ctx context.Context, serviceName string, build func() (http.Handler, error)) (http.Handler, error) {
+	handler, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	alHandler := func(next http.Handler) (http.Handler, error) {
+		return accesslog.NewFieldHandler(next, accesslog.ServiceName, serviceName, nil), nil
+	}
+	chain := alice.New()
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		chain = chain.Append(metricsMiddle.WrapServiceHandler(ctx, m.metricsRegistry, serviceName))
+	}
+
+	return chain.Append(alHandler).Then(handler)
+}
+
 func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer) (http.Handler, error) {
 	if service.PassHostHeader == nil {
 		defaultPassHostHeader := true
@@ -182,11 +289,50 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		return nil, err
 	}
 
+	roundTripper, err = newUpstreamTimeoutsRoundTripper(roundTripper, service.Timeouts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without another server to send the second attempts to, the requests are not hedged.
+	var hedger *hedgingRoundTripper
+	if hedging := m.roundTripperManager.Hedging(service.ServersTransport); hedging != nil && len(service.Servers) > 1 {
+		hedger, err = newHedgingRoundTripper(roundTripper, hedging)
+		if err != nil {
+			return nil, err
+		}
+
+		roundTripper = hedger
+	}
+
 	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool)
 	if err != nil {
 		return nil, err
 	}
 
+	fwd = tracingMiddle.NewUpstream(ctx, serviceName, fwd)
+
+	if m.requestDebug {
+		fwd = requestdebug.NewUpstream(fwd, serviceName)
+	}
+
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		fwd = newUpstreamMetrics(fwd, m.metricsRegistry, serviceName)
+	}
+
+	if service.MaxConcurrentRequests != nil && *service.MaxConcurrentRequests != 0 {
+		concurrency, err := m.getServerConcurrency(fwd, serviceName, service)
+		if err != nil {
+			return nil, err
+		}
+
+		if hedger != nil {
+			hedger.concurrency = concurrency
+		}
+
+		fwd = concurrency
+	}
+
 	alHandler := func(next http.Handler) (http.Handler, error) {
 		return accesslog.NewFieldHandler(next, accesslog.ServiceName, serviceName, accesslog.AddServiceFields), nil
 	}
@@ -208,17 +354,79 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 	// TODO rename and checks
 	m.balancers[serviceName] = append(m.balancers[serviceName], balancer)
 
+	// The second attempts are sent to the servers kept by the health check.
+	if hedger != nil {
+		hedger.balancer = balancer
+	}
+
 	// Empty (backend with no servers)
-	return emptybackendhandler.New(balancer), nil
+	var lb http.Handler = emptybackendhandler.New(balancer)
+
+	if m.stickyDrains != nil && service.Sticky != nil && service.Sticky.Cookie != nil && service.Sticky.Cookie.DrainPeriod != nil && *service.Sticky.Cookie.DrainPeriod > 0 {
+		lb, err = m.getStickyDrain(lb, handler, serviceName, service)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if m.upstreamOverride != nil && upstreamOverrideAllowed(m.upstreamOverride, serviceName) {
+		lb, err = newUpstreamOverride(lb, handler, serviceName, service.Servers, m.upstreamOverride)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The load shedding wraps the whole service, so that its latency includes the waits for the servers.
+	if service.LoadShedding != nil {
+		return m.getLoadShedder(lb, serviceName, service.LoadShedding)
+	}
+
+	return lb, nil
 }
 
 // LaunchHealthCheck Launches the health checks.
 func (m *Manager) LaunchHealthCheck() {
+	launchHealthChecks([]*Manager{m})
+}
+
+// LaunchHealthChecks launches the health checks of the services built by several service managers,
+// in place of the running ones.
+// The balancers built by different managers for the same service share its health check.
+func LaunchHealthChecks(handlers ...*InternalHandlers) {
+	var managers []*Manager
+	for _, handler := range handlers {
+		if manager, ok := handler.serviceManager.(*Manager); ok {
+			managers = append(managers, manager)
+		}
+	}
+
+	launchHealthChecks(managers)
+}
+
+func launchHealthChecks(managers []*Manager) {
+	if len(managers) == 0 {
+		return
+	}
+
+	// The first manager building a service provides its configuration.
+	owners := make(map[string]*Manager)
+	allBalancers := make(map[string]healthcheck.Balancers)
+	for _, m := range managers {
+		for serviceName, balancers := range m.balancers {
+			if _, ok := owners[serviceName]; !ok {
+				owners[serviceName] = m
+			}
+			allBalancers[serviceName] = append(allBalancers[serviceName], balancers...)
+		}
+	}
+
 	backendConfigs := make(map[string]*healthcheck.BackendConfig)
 
-	for serviceName, balancers := range m.balancers {
+	for serviceName, balancers := range allBalancers {
 		ctx := log.With(context.Background(), log.Str(log.ServiceName, serviceName))
 
+		m := owners[serviceName]
+
 		// TODO Should all the services handle healthcheck? Handle different types
 		service := m.configs[serviceName].LoadBalancer
 
@@ -236,7 +444,7 @@ func (m *Manager) LaunchHealthCheck() {
 		}
 	}
 
-	healthcheck.GetHealthCheck(m.metricsRegistry).SetBackendsConfiguration(context.Background(), backendConfigs)
+	healthcheck.GetHealthCheck(managers[0].metricsRegistry).SetBackendsConfiguration(context.Background(), backendConfigs)
 }
 
 func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backend string, hc *dynamic.HealthCheck) *healthcheck.Options {
@@ -328,6 +536,76 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	return lbsu, nil
 }
 
+// getLoadShedder rejects the requests beyond the concurrency limit of the service, adapted to its latency.
+func (m *Manager) getLoadShedder(lb http.Handler, serviceName string, config *dynamic.LoadShedding) (http.Handler, error) {
+	if config.LatencyTarget <= 0 {
+		return nil, errors.New("the latency target of the load shedding must be positive")
+	}
+
+	if config.MinLimit < 1 || config.MaxLimit < config.MinLimit {
+		return nil, errors.New("the limits of the load shedding must be positive, and the minimum limit must not exceed the maximum one")
+	}
+
+	if config.InitialLimit < config.MinLimit || config.InitialLimit > config.MaxLimit {
+		return nil, errors.New("the initial limit of the load shedding must be between the minimum and maximum ones")
+	}
+
+	if config.QueueSize < 0 || config.QueueTimeout < 0 || config.RetryAfter < 0 {
+		return nil, errors.New("the queue size, queue timeout and retry after of the load shedding must not be negative")
+	}
+
+	return newLoadShedder(lb, m.adaptiveLimits.get(serviceName, *config), serviceName, time.Duration(config.RetryAfter)), nil
+}
+
+// getServerConcurrency limits the number of requests forwarded concurrently to each server of the service.
+func (m *Manager) getServerConcurrency(fwd http.Handler, serviceName string, service *dynamic.ServersLoadBalancer) (*serverConcurrency, error) {
+	if *service.MaxConcurrentRequests < 0 {
+		return nil, errors.New("the maximum number of concurrent requests must not be negative")
+	}
+
+	var queueSize int
+	var timeout time.Duration
+	if service.ConcurrencyQueue != nil {
+		if service.ConcurrencyQueue.Size < 0 {
+			return nil, errors.New("the size of the concurrency queue must not be negative")
+		}
+
+		queueSize = service.ConcurrencyQueue.Size
+		timeout = time.Duration(service.ConcurrencyQueue.Timeout)
+	}
+
+	servers := make(map[string]string, len(service.Servers))
+	for _, srv := range service.Servers {
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", srv.URL, err)
+		}
+
+		servers[serverKey(u)] = u.String()
+	}
+
+	return newServerConcurrency(fwd, m.serverConcurrencies, serviceName, servers, *service.MaxConcurrentRequests, queueSize, timeout), nil
+}
+
+// getStickyDrain wraps the load-balancer of a service, to keep forwarding the sticky sessions of its removed servers.
+func (m *Manager) getStickyDrain(lb, fwd http.Handler, serviceName string, service *dynamic.ServersLoadBalancer) (http.Handler, error) {
+	// The sticky cookies hold the normalized URL of the servers.
+	servers := make([]string, 0, len(service.Servers))
+	for _, srv := range service.Servers {
+		u, err := url.Parse(srv.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", srv.URL, err)
+		}
+
+		servers = append(servers, u.String())
+	}
+
+	deadlines := m.stickyDrains.update(serviceName, servers, time.Duration(*service.Sticky.Cookie.DrainPeriod))
+	cookieName := cookie.GetName(service.Sticky.Cookie.Name, serviceName)
+
+	return newStickyDrain(lb, fwd, serviceName, cookieName, deadlines), nil
+}
+
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
 	logger := log.FromContext(ctx)
 
@@ -373,4 +373,50 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	assert.Error(t, err, "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 }
 
+func TestManager_BuildHTTP_responseServices(t *testing.T) {
+	services := map[string]*runtime.ServiceInfo{
+		"redirect@file": {
+			Service: &dynamic.Service{
+				Redirect: &dynamic.Redirection{Location: "https://example.com{{ .Path }}", StatusCode: http.StatusMovedPermanently},
+			},
+		},
+		"static@file": {
+			Service: &dynamic.Service{
+				Static: &dynamic.StaticResponse{StatusCode: http.StatusTeapot, Body: "short and stout"},
+			},
+		},
+		"invalid@file": {
+			Service: &dynamic.Service{
+				Redirect: &dynamic.Redirection{Location: "https://example.com", StatusCode: http.StatusOK},
+			},
+		},
+	}
+
+	manager := NewManager(services, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	})
+
+	handler, err := manager.BuildHTTP(context.Background(), "redirect@file")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/bar", nil))
+	assert.Equal(t, http.StatusMovedPermanently, recorder.Code)
+	assert.Equal(t, "https://example.com/bar", recorder.Header().Get("Location"))
+
+	handler, err = manager.BuildHTTP(context.Background(), "static@file")
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/bar", nil))
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+	assert.Equal(t, "short and stout", recorder.Body.String())
+
+	_, err = manager.BuildHTTP(context.Background(), "invalid@file")
+	require.Error(t, err)
+	assert.NotEmpty(t, services["invalid@file"].Err)
+}
+
 // FIXME Add healthcheck tests
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/utils"
+)
+
+// stickyDrains remembers the servers of the sticky services across the configuration changes,
+// so that the servers removed from a service keep receiving the requests of their sticky sessions during its drain period.
+type stickyDrains struct {
+	mu       sync.Mutex
+	services map[string]*serviceDrains
+	now      func() time.Time
+}
+
+type serviceDrains struct {
+	servers map[string]struct{}
+	// draining holds the drain deadline of the removed servers, keyed by server URL.
+	draining map[string]time.Time
+}
+
+func newStickyDrains() *stickyDrains {
+	return &stickyDrains{
+		services: make(map[string]*serviceDrains),
+		now:      time.Now,
+	}
+}
+
+// update records the current servers of a service,
+// and returns the drain deadline of the servers removed from the service less than drainPeriod ago.
+func (s *stickyDrains) update(serviceName string, servers []string, drainPeriod time.Duration) map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[string]struct{}, len(servers))
+	for _, server := range servers {
+		current[server] = struct{}{}
+	}
+
+	svc, ok := s.services[serviceName]
+	if !ok {
+		s.services[serviceName] = &serviceDrains{servers: current, draining: make(map[string]time.Time)}
+		return nil
+	}
+
+	now := s.now()
+	for server := range svc.servers {
+		if _, ok := current[server]; !ok {
+			svc.draining[server] = now.Add(drainPeriod)
+		}
+	}
+
+	draining := make(map[string]time.Time)
+	for server, deadline := range svc.draining {
+		_, added := current[server]
+		if added || !now.Before(deadline) {
+			delete(svc.draining, server)
+			continue
+		}
+
+		draining[server] = deadline
+	}
+
+	svc.servers = current
+
+	return draining
+}
+
+// stickyDrain forwards the requests whose sticky cookie designates a server removed from the service
+// to this server until the end of its drain period, bypassing the load-balancer.
+// After the drain period, the load-balancer sets the cookie to one of the servers of the service.
+type stickyDrain struct {
+	next        http.Handler
+	fwd         http.Handler
+	serviceName string
+	cookieName  string
+	draining    map[string]*url.URL
+	deadlines   map[string]time.Time
+	now         func() time.Time
+}
+
+// newStickyDrain wraps the load-balancer of a service, whose forwarder is fwd.
+func newStickyDrain(next, fwd http.Handler, serviceName, cookieName string, deadlines map[string]time.Time) http.Handler {
+	draining := make(map[string]*url.URL, len(deadlines))
+	for server := range deadlines {
+		u, err := url.Parse(server)
+		if err != nil {
+			continue
+		}
+
+		draining[server] = u
+	}
+
+	if len(draining) == 0 {
+		return next
+	}
+
+	return &stickyDrain{
+		next:        next,
+		fwd:         fwd,
+		serviceName: serviceName,
+		cookieName:  cookieName,
+		draining:    draining,
+		deadlines:   deadlines,
+		now:         time.Now,
+	}
+}
+
+func (s *stickyDrain) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	cookie, err := req.Cookie(s.cookieName)
+	if err != nil {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	server, ok := s.draining[cookie.Value]
+	if !ok || !s.now().Before(s.deadlines[cookie.Value]) {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	log.FromContext(req.Context()).Debugf("Forwarding the sticky session of the service %s to the draining server %s", s.serviceName, cookie.Value)
+
+	outReq := req.WithContext(req.Context())
+	outReq.URL = utils.CopyURL(server)
+
+	s.fwd.ServeHTTP(rw, outReq)
+}
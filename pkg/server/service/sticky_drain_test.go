@@ -0,0 +1,116 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStickyDrains_update(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	drains := newStickyDrains()
+	drains.now = func() time.Time { return now }
+
+	deadlines := drains.update("foo@file", []string{"http://10.0.0.1:80", "http://10.0.0.2:80"}, time.Minute)
+	assert.Empty(t, deadlines)
+
+	now = now.Add(10 * time.Second)
+	deadlines = drains.update("foo@file", []string{"http://10.0.0.1:80", "http://10.0.0.3:80"}, time.Minute)
+	assert.Equal(t, map[string]time.Time{"http://10.0.0.2:80": now.Add(time.Minute)}, deadlines)
+
+	// The drain deadline does not move when the configuration changes again.
+	deadline := now.Add(time.Minute)
+	now = now.Add(10 * time.Second)
+	deadlines = drains.update("foo@file", []string{"http://10.0.0.1:80", "http://10.0.0.3:80"}, time.Minute)
+	assert.Equal(t, map[string]time.Time{"http://10.0.0.2:80": deadline}, deadlines)
+
+	// The other services are not affected.
+	deadlines = drains.update("bar@file", []string{"http://10.0.0.2:80"}, time.Minute)
+	assert.Empty(t, deadlines)
+
+	// A server added back to the service is not draining anymore.
+	deadlines = drains.update("foo@file", []string{"http://10.0.0.2:80", "http://10.0.0.3:80"}, time.Minute)
+	assert.Equal(t, map[string]time.Time{"http://10.0.0.1:80": now.Add(time.Minute)}, deadlines)
+
+	now = now.Add(2 * time.Minute)
+	deadlines = drains.update("foo@file", []string{"http://10.0.0.2:80", "http://10.0.0.3:80"}, time.Minute)
+	assert.Empty(t, deadlines)
+}
+
+func TestStickyDrain(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc             string
+		cookie           string
+		elapsed          time.Duration
+		expectedUpstream string
+	}{
+		{
+			desc:             "no cookie",
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "cookie of a server of the service",
+			cookie:           "http://10.0.0.1:80",
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "cookie of a draining server",
+			cookie:           "http://10.0.0.2:80",
+			expectedUpstream: "10.0.0.2:80",
+		},
+		{
+			desc:             "cookie of a drained server",
+			cookie:           "http://10.0.0.2:80",
+			elapsed:          time.Minute,
+			expectedUpstream: "lb",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var upstream string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				upstream = "lb"
+			})
+			fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				upstream = req.URL.Host
+			})
+
+			handler := newStickyDrain(next, fwd, "foo@file", "sticky", map[string]time.Time{
+				"http://10.0.0.2:80": now.Add(time.Minute),
+			})
+
+			drain, ok := handler.(*stickyDrain)
+			require.True(t, ok)
+			drain.now = func() time.Time { return now.Add(test.elapsed) }
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil)
+			if test.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "sticky", Value: test.cookie})
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedUpstream, upstream)
+		})
+	}
+}
+
+func TestStickyDrain_noDrainingServer(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	handler := newStickyDrain(next, next, "foo@file", "sticky", nil)
+
+	_, ok := handler.(*stickyDrain)
+	assert.False(t, ok)
+}
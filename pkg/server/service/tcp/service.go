@@ -9,19 +9,23 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/transparent"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
+	configs         map[string]*runtime.TCPServiceInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:         conf.TCPServices,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
@@ -45,6 +49,13 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
+		transparentMode := conf.LoadBalancer.Transparent != nil && *conf.LoadBalancer.Transparent
+		if transparentMode && !transparent.Supported() {
+			err := errors.New("cannot create service: the transparent mode is only supported on Linux")
+			conf.AddError(err, true)
+			return nil, err
+		}
+
 		loadBalancer := tcp.NewWRRLoadBalancer()
 
 		if conf.LoadBalancer.TerminationDelay == nil {
@@ -65,9 +76,24 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
+			if transparentMode {
+				if err := handler.EnableTransparent(); err != nil {
+					logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
+					continue
+				}
+			}
+
 			loadBalancer.AddServer(handler)
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
+
+		if conf.LoadBalancer.ConnectRetry != nil && conf.LoadBalancer.ConnectRetry.Attempts > 1 {
+			retries := m.metricsRegistry.ServiceTCPConnectRetriesCounter().With("service", serviceQualifiedName)
+			loadBalancer.SetConnectRetry(conf.LoadBalancer.ConnectRetry.Attempts, func(attempt int) {
+				retries.Add(1)
+			})
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/transparent"
 	"github.com/traefik/traefik/v2/pkg/udp"
 )
 
@@ -44,6 +46,13 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
+		transparentMode := conf.LoadBalancer.Transparent != nil && *conf.LoadBalancer.Transparent
+		if transparentMode && !transparent.Supported() {
+			err := errors.New("cannot create service: the transparent mode is only supported on Linux")
+			conf.AddError(err, true)
+			return nil, err
+		}
+
 		loadBalancer := udp.NewWRRLoadBalancer()
 
 		for name, server := range conf.LoadBalancer.Servers {
@@ -58,9 +67,21 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
+			if transparentMode {
+				if err := handler.EnableTransparent(); err != nil {
+					logger.Errorf("In udp service %q server %q: %v", serviceQualifiedName, server.Address, err)
+					continue
+				}
+			}
+
 			loadBalancer.AddServer(handler)
 			logger.WithField(log.ServerName, name).Debugf("Creating UDP server %d at %s", name, server.Address)
 		}
+
+		if healthCheck := conf.LoadBalancer.PassiveHealthCheck; healthCheck != nil {
+			loadBalancer.SetPassiveHealthCheck(healthCheck.MaxFailures, time.Duration(healthCheck.FailureTimeout))
+		}
+
 		return loadBalancer, nil
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
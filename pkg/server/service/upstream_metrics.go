@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+type upstreamGaugesKey struct{}
+
+type protocolDowngradesKey struct{}
+
+// upstreamGauges are the gauges of the connections to the servers of a service.
+type upstreamGauges struct {
+	open   gokitmetrics.Gauge
+	idle   gokitmetrics.Gauge
+	active gokitmetrics.Gauge
+}
+
+// upstreamMetrics records the transport level metrics of the requests forwarded to the servers of a service:
+// the connections used, reused or dialed, and the failed resolutions of the servers.
+// It also provides the gauges of the connections to the dialer of the servers transport,
+// and the counter of the protocol downgrades to its round tripper, through the request context.
+type upstreamMetrics struct {
+	next         http.Handler
+	serviceName  string
+	gauges       *upstreamGauges
+	connsCounter gokitmetrics.Counter
+	dnsFailures  gokitmetrics.Counter
+	downgrades   gokitmetrics.Counter
+}
+
+func newUpstreamMetrics(next http.Handler, registry metrics.Registry, serviceName string) http.Handler {
+	return &upstreamMetrics{
+		next:        next,
+		serviceName: serviceName,
+		gauges: &upstreamGauges{
+			open:   registry.ServiceUpstreamOpenConnsGauge().With("service", serviceName),
+			idle:   registry.ServiceUpstreamIdleConnsGauge().With("service", serviceName),
+			active: registry.ServiceUpstreamActiveConnsGauge().With("service", serviceName),
+		},
+		connsCounter: registry.ServiceUpstreamConnsCounter(),
+		dnsFailures:  registry.ServiceUpstreamDNSFailuresCounter().With("service", serviceName),
+		downgrades:   registry.ServiceProtocolDowngradesCounter().With("service", serviceName),
+	}
+}
+
+func (u *upstreamMetrics) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			u.connsCounter.With("service", u.serviceName, "reused", strconv.FormatBool(info.Reused)).Add(1)
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err != nil {
+				u.dnsFailures.Add(1)
+			}
+		},
+	}
+
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	ctx = context.WithValue(ctx, upstreamGaugesKey{}, u.gauges)
+	ctx = context.WithValue(ctx, protocolDowngradesKey{}, u.downgrades)
+
+	u.next.ServeHTTP(rw, req.WithContext(ctx))
+}
+
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// upstreamConns tracks the connections to the servers opened by the dialer of a servers transport,
+// to report whether they are idle or active, and to close them once they reach their maximum lifetime.
+// The connections are found by their addresses, as the ones used by the requests can be the TLS connections established over them.
+type upstreamConns struct {
+	maxLifetime time.Duration
+
+	mu    sync.Mutex
+	conns map[string]*trackedConn
+}
+
+func newUpstreamConns(maxLifetime time.Duration) *upstreamConns {
+	return &upstreamConns{
+		maxLifetime: maxLifetime,
+		conns:       make(map[string]*trackedConn),
+	}
+}
+
+// track tracks the connections opened by dial, counting them with the gauges of the service of the request which triggered the dial.
+// As the connections are pooled by servers transport, a connection reused by another service is still counted for the first one.
+func (u *upstreamConns) track(dial dialContextFunc) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		gauges, _ := ctx.Value(upstreamGaugesKey{}).(*upstreamGauges)
+
+		tracked := &trackedConn{Conn: conn, conns: u, gauges: gauges, dialed: time.Now(), state: connIdle}
+		if gauges != nil {
+			gauges.open.Add(1)
+			gauges.idle.Add(1)
+		}
+
+		u.mu.Lock()
+		u.conns[connKey(conn)] = tracked
+		u.mu.Unlock()
+
+		return tracked, nil
+	}
+}
+
+// get returns the tracked connection with the addresses of conn, if any.
+func (u *upstreamConns) get(conn net.Conn) *trackedConn {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.conns[connKey(conn)]
+}
+
+func (u *upstreamConns) remove(conn *trackedConn) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	key := connKey(conn)
+	if u.conns[key] == conn {
+		delete(u.conns, key)
+	}
+}
+
+func connKey(conn net.Conn) string {
+	return conn.LocalAddr().String() + "->" + conn.RemoteAddr().String()
+}
+
+// upstreamConnsRoundTripper marks the connections used by the requests as active, and as idle once released,
+// closing them instead when they reached their maximum lifetime, so that a new connection is dialed to the servers,
+// resolving their addresses again.
+// The HTTP/2 connections, which are shared by the concurrent requests, are never released and stay active.
+type upstreamConnsRoundTripper struct {
+	http.RoundTripper
+
+	conns *upstreamConns
+}
+
+func (r *upstreamConnsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var conn *trackedConn
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			conn = r.conns.get(info.Conn)
+			if conn != nil {
+				conn.setState(connActive)
+			}
+		},
+		PutIdleConn: func(err error) {
+			// The connections which cannot be put back in the idle pool are closed by the transport.
+			if conn == nil || err != nil {
+				return
+			}
+
+			if r.conns.maxLifetime > 0 && time.Since(conn.dialed) >= r.conns.maxLifetime {
+				_ = conn.Close()
+				return
+			}
+
+			conn.setState(connIdle)
+		},
+	}
+
+	return r.RoundTripper.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+type connState int
+
+const (
+	connIdle connState = iota
+	connActive
+	connClosed
+)
+
+type trackedConn struct {
+	net.Conn
+
+	conns  *upstreamConns
+	gauges *upstreamGauges
+	dialed time.Time
+
+	mu    sync.Mutex
+	state connState
+}
+
+// setState moves the connection to state, updating the gauges.
+func (c *trackedConn) setState(state connState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == state || c.state == connClosed {
+		return
+	}
+
+	if c.gauges != nil {
+		c.stateGauge(c.state).Add(-1)
+
+		if state == connClosed {
+			c.gauges.open.Add(-1)
+		} else {
+			c.stateGauge(state).Add(1)
+		}
+	}
+
+	c.state = state
+}
+
+func (c *trackedConn) stateGauge(state connState) gokitmetrics.Gauge {
+	if state == connActive {
+		return c.gauges.active
+	}
+
+	return c.gauges.idle
+}
+
+func (c *trackedConn) Close() error {
+	c.setState(connClosed)
+	c.conns.remove(c)
+
+	return c.Conn.Close()
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+func TestUpstreamMetrics(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	roundTripper, err := createRoundTripper(&dynamic.ServersTransport{MaxIdleConnsPerHost: 1})
+	require.NoError(t, err)
+
+	passHostHeader := true
+	proxy, err := buildProxy(&passHostHeader, nil, roundTripper, nil)
+	require.NoError(t, err)
+
+	registry := newUpstreamMetricsRegistry()
+	handler := newUpstreamMetrics(proxy, registry, "foo")
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, backend.URL, nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+	}
+
+	assert.Equal(t, float64(1), registry.values.get("conns", "service", "foo", "reused", "false"))
+	assert.Equal(t, float64(1), registry.values.get("conns", "service", "foo", "reused", "true"))
+	assert.Equal(t, float64(1), registry.values.get("openConns", "service", "foo"))
+	assert.Equal(t, float64(1), registry.values.get("idleConns", "service", "foo"))
+	assert.Equal(t, float64(0), registry.values.get("activeConns", "service", "foo"))
+
+	roundTripper.(*upstreamConnsRoundTripper).RoundTripper.(*smartRoundTripper).http2.CloseIdleConnections()
+
+	assert.Eventually(t, func() bool {
+		return registry.values.get("openConns", "service", "foo") == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, float64(0), registry.values.get("idleConns", "service", "foo"))
+}
+
+func TestUpstreamMetrics_maxConnLifetime(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	roundTripper, err := createRoundTripper(&dynamic.ServersTransport{
+		MaxIdleConnsPerHost: 1,
+		ForwardingTimeouts:  &dynamic.ForwardingTimeouts{MaxConnLifetime: ptypes.Duration(time.Nanosecond)},
+	})
+	require.NoError(t, err)
+
+	passHostHeader := true
+	proxy, err := buildProxy(&passHostHeader, nil, roundTripper, nil)
+	require.NoError(t, err)
+
+	registry := newUpstreamMetricsRegistry()
+	handler := newUpstreamMetrics(proxy, registry, "foo")
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, backend.URL, nil))
+		assert.Equal(t, http.StatusOK, rw.Code)
+
+		// The connection is closed once released, as it reached its maximum lifetime.
+		assert.Eventually(t, func() bool {
+			return registry.values.get("openConns", "service", "foo") == 0
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	assert.Equal(t, float64(2), registry.values.get("conns", "service", "foo", "reused", "false"))
+	assert.Equal(t, float64(0), registry.values.get("conns", "service", "foo", "reused", "true"))
+	assert.Equal(t, float64(0), registry.values.get("idleConns", "service", "foo"))
+	assert.Equal(t, float64(0), registry.values.get("activeConns", "service", "foo"))
+}
+
+func TestUpstreamMetrics_dnsFailure(t *testing.T) {
+	roundTripper, err := createRoundTripper(&dynamic.ServersTransport{})
+	require.NoError(t, err)
+
+	passHostHeader := true
+	proxy, err := buildProxy(&passHostHeader, nil, roundTripper, nil)
+	require.NoError(t, err)
+
+	registry := newUpstreamMetricsRegistry()
+	handler := newUpstreamMetrics(proxy, registry, "foo")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://unknown.invalid", nil))
+	assert.NotEqual(t, http.StatusOK, rw.Code)
+
+	assert.Equal(t, float64(1), registry.values.get("dnsFailures", "service", "foo"))
+	assert.Equal(t, float64(0), registry.values.get("openConns", "service", "foo"))
+}
+
+type upstreamMetricsRegistry struct {
+	metrics.Registry
+
+	values *metricValues
+}
+
+func newUpstreamMetricsRegistry() *upstreamMetricsRegistry {
+	return &upstreamMetricsRegistry{
+		Registry: metrics.NewVoidRegistry(),
+		values:   &metricValues{values: make(map[string]float64)},
+	}
+}
+
+func (r *upstreamMetricsRegistry) ServiceUpstreamOpenConnsGauge() gokitmetrics.Gauge {
+	return &testGauge{name: "openConns", values: r.values}
+}
+
+func (r *upstreamMetricsRegistry) ServiceUpstreamIdleConnsGauge() gokitmetrics.Gauge {
+	return &testGauge{name: "idleConns", values: r.values}
+}
+
+func (r *upstreamMetricsRegistry) ServiceUpstreamActiveConnsGauge() gokitmetrics.Gauge {
+	return &testGauge{name: "activeConns", values: r.values}
+}
+
+func (r *upstreamMetricsRegistry) ServiceUpstreamConnsCounter() gokitmetrics.Counter {
+	return &testCounter{name: "conns", values: r.values}
+}
+
+func (r *upstreamMetricsRegistry) ServiceUpstreamDNSFailuresCounter() gokitmetrics.Counter {
+	return &testCounter{name: "dnsFailures", values: r.values}
+}
+
+func (r *upstreamMetricsRegistry) ServiceProtocolDowngradesCounter() gokitmetrics.Counter {
+	return &testCounter{name: "downgrades", values: r.values}
+}
+
+// metricValues holds the values of metrics, by name and labels, and is safe for concurrent use.
+type metricValues struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (m *metricValues) add(name string, labels []string, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[name+"{"+strings.Join(labels, ",")+"}"] += delta
+}
+
+func (m *metricValues) set(name string, labels []string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[name+"{"+strings.Join(labels, ",")+"}"] = value
+}
+
+func (m *metricValues) get(name string, labels ...string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.values[name+"{"+strings.Join(labels, ",")+"}"]
+}
+
+type testCounter struct {
+	name   string
+	labels []string
+	values *metricValues
+}
+
+func (c *testCounter) With(labelValues ...string) gokitmetrics.Counter {
+	return &testCounter{name: c.name, labels: append(append([]string{}, c.labels...), labelValues...), values: c.values}
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.values.add(c.name, c.labels, delta)
+}
+
+type testGauge struct {
+	name   string
+	labels []string
+	values *metricValues
+}
+
+func (g *testGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	return &testGauge{name: g.name, labels: append(append([]string{}, g.labels...), labelValues...), values: g.values}
+}
+
+func (g *testGauge) Set(value float64) {
+	g.values.set(g.name, g.labels, value)
+}
+
+func (g *testGauge) Add(delta float64) {
+	g.values.add(g.name, g.labels, delta)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/signedtoken"
+	"github.com/vulcand/oxy/utils"
+)
+
+type upstreamOverriddenKey struct{}
+
+// upstreamOverride forwards the requests carrying a valid upstream override header to the server it designates,
+// bypassing the load-balancer.
+// The header value is a token of the upstream domain of the service, made of the URL of the server, signed with the secret.
+// Only the servers of the service can be designated.
+type upstreamOverride struct {
+	next        http.Handler
+	fwd         http.Handler
+	serviceName string
+	servers     map[string]*url.URL
+	header      string
+	verifier    *signedtoken.Verifier
+	now         func() time.Time
+}
+
+// newUpstreamOverride wraps the load-balancer of a service, whose forwarder is fwd.
+func newUpstreamOverride(next, fwd http.Handler, serviceName string, servers []dynamic.Server, config *static.UpstreamOverride) (http.Handler, error) {
+	serverURLs := make(map[string]*url.URL, len(servers))
+	for _, server := range servers {
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server URL %s: %w", server.URL, err)
+		}
+
+		serverURLs[server.URL] = u
+	}
+
+	return &upstreamOverride{
+		next:        next,
+		fwd:         fwd,
+		serviceName: serviceName,
+		servers:     serverURLs,
+		header:      config.Header,
+		verifier:    signedtoken.NewVerifier(config.Secret, time.Duration(config.MaxAge)),
+		now:         time.Now,
+	}, nil
+}
+
+func (u *upstreamOverride) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	value := req.Header.Get(u.header)
+	if value == "" {
+		u.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// The header is meant for Traefik only.
+	req.Header.Del(u.header)
+
+	server, err := u.server(value)
+	if err != nil {
+		log.FromContext(req.Context()).Debugf("Ignoring the upstream override of the service %s: %v", u.serviceName, err)
+		u.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if data := accesslog.GetLogData(req); data != nil {
+		data.Core[accesslog.UpstreamOverride] = server.String()
+	}
+
+	outReq := req.WithContext(context.WithValue(req.Context(), upstreamOverriddenKey{}, true))
+	outReq.URL = utils.CopyURL(server)
+
+	u.fwd.ServeHTTP(rw, outReq)
+}
+
+// server returns the server designated by a header value, once its signature and expiration are checked.
+func (u *upstreamOverride) server(value string) (*url.URL, error) {
+	fields, err := u.verifier.Verify(u.now(), value, upstreamOverrideDomain(u.serviceName), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	server, ok := u.servers[fields[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown server %s", fields[0])
+	}
+
+	return server, nil
+}
+
+// upstreamOverrideAllowed reports whether the upstream override header is accepted by a service.
+func upstreamOverrideAllowed(config *static.UpstreamOverride, serviceName string) bool {
+	if len(config.Services) == 0 {
+		return true
+	}
+
+	for _, name := range config.Services {
+		if name == serviceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// upstreamOverrideDomain returns the domain of the tokens of the upstream override header of a service,
+// so that a token is only valid for the service it was signed for.
+func upstreamOverrideDomain(serviceName string) string {
+	return "upstream\n" + serviceName
+}
+
+// isUpstreamOverridden reports whether the request was forced to its server by the upstream override header.
+func isUpstreamOverridden(ctx context.Context) bool {
+	overridden, _ := ctx.Value(upstreamOverriddenKey{}).(bool)
+	return overridden
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/signedtoken"
+)
+
+func TestUpstreamOverride(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sign := func(serviceName, serverURL string, expiresAt time.Time) string {
+		return signedtoken.Sign("secret", upstreamOverrideDomain(serviceName), expiresAt, serverURL)
+	}
+
+	testCases := []struct {
+		desc             string
+		header           string
+		expectedUpstream string
+	}{
+		{
+			desc:             "no header",
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "valid header",
+			header:           sign("foo@file", "http://10.0.0.2:80", now.Add(time.Minute)),
+			expectedUpstream: "10.0.0.2:80",
+		},
+		{
+			desc:             "signed for another service",
+			header:           sign("bar@file", "http://10.0.0.2:80", now.Add(time.Minute)),
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "invalid signature",
+			header:           "http://10.0.0.2:80;" + strconv.FormatInt(now.Add(time.Minute).Unix(), 10) + ";deadbeef",
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "malformed header",
+			header:           "http://10.0.0.2:80",
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "expired header",
+			header:           sign("foo@file", "http://10.0.0.2:80", now.Add(-time.Second)),
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "expiration beyond the max age",
+			header:           sign("foo@file", "http://10.0.0.2:80", now.Add(time.Hour)),
+			expectedUpstream: "lb",
+		},
+		{
+			desc:             "server outside of the service",
+			header:           sign("foo@file", "http://10.0.0.3:80", now.Add(time.Minute)),
+			expectedUpstream: "lb",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var upstream, forwardedHeader string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				upstream = "lb"
+				forwardedHeader = req.Header.Get("X-Traefik-Upstream")
+			})
+			fwd := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				upstream = req.URL.Host
+				forwardedHeader = req.Header.Get("X-Traefik-Upstream")
+			})
+
+			config := &static.UpstreamOverride{Secret: "secret"}
+			config.SetDefaults()
+
+			servers := []dynamic.Server{{URL: "http://10.0.0.1:80"}, {URL: "http://10.0.0.2:80"}}
+
+			handler, err := newUpstreamOverride(next, fwd, "foo@file", servers, config)
+			require.NoError(t, err)
+			handler.(*upstreamOverride).now = func() time.Time { return now }
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.com/bar", nil)
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+			if test.header != "" {
+				req.Header.Set("X-Traefik-Upstream", test.header)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedUpstream, upstream)
+			assert.Empty(t, forwardedHeader)
+
+			if test.expectedUpstream == "lb" {
+				assert.Nil(t, logData.Core[accesslog.UpstreamOverride])
+			} else {
+				assert.Equal(t, "http://"+test.expectedUpstream, logData.Core[accesslog.UpstreamOverride])
+			}
+		})
+	}
+}
+
+func TestUpstreamOverrideAllowed(t *testing.T) {
+	assert.True(t, upstreamOverrideAllowed(&static.UpstreamOverride{}, "foo@file"))
+	assert.True(t, upstreamOverrideAllowed(&static.UpstreamOverride{Services: []string{"bar@file", "foo@file"}}, "foo@file"))
+	assert.False(t, upstreamOverrideAllowed(&static.UpstreamOverride{Services: []string{"bar@file"}}, "foo@file"))
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// upstreamTimeoutsKey is the key of the upstream timeouts of the router in the request context.
+type upstreamTimeoutsKey struct{}
+
+// upstreamPhase is a phase of the exchange with a server, each one being bounded by its own timeout.
+// The zero phase is the dial, bounded by the dial timeout of the servers transport.
+type upstreamPhase int
+
+const (
+	phaseWrite upstreamPhase = iota + 1
+	phaseResponseHeader
+	phaseRead
+	phaseDone
+)
+
+// upstreamTimeoutError is returned when a phase of the exchange with a server exceeds its timeout.
+// It is a timeout net.Error, so that the proxy answers with a 504 Gateway Timeout status code.
+type upstreamTimeoutError struct {
+	phase upstreamPhase
+}
+
+func (e upstreamTimeoutError) Error() string {
+	switch e.phase {
+	case phaseWrite:
+		return "timeout writing the request to the server"
+	case phaseResponseHeader:
+		return "timeout awaiting the response headers of the server"
+	default:
+		return "timeout reading the response body of the server"
+	}
+}
+
+func (e upstreamTimeoutError) Timeout() bool { return true }
+
+func (e upstreamTimeoutError) Temporary() bool { return true }
+
+// NewRouterUpstreamTimeouts wraps the handler of a router, so that its upstream timeouts override the ones of the services.
+func NewRouterUpstreamTimeouts(next http.Handler, config *dynamic.UpstreamTimeouts) (http.Handler, error) {
+	if config == nil {
+		return next, nil
+	}
+
+	if err := validateUpstreamTimeouts(config); err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), upstreamTimeoutsKey{}, config)))
+	}), nil
+}
+
+func validateUpstreamTimeouts(config *dynamic.UpstreamTimeouts) error {
+	if config.ResponseHeaderTimeout < 0 || config.ReadTimeout < 0 || config.WriteTimeout < 0 {
+		return errors.New("the upstream timeouts must not be negative")
+	}
+
+	return nil
+}
+
+// upstreamTimeoutsRoundTripper bounds the time spent writing a request to a server, awaiting its response headers,
+// and reading its response body, with the timeouts of the router of the request, or else the ones of the service.
+type upstreamTimeoutsRoundTripper struct {
+	http.RoundTripper
+
+	timeouts dynamic.UpstreamTimeouts
+}
+
+func newUpstreamTimeoutsRoundTripper(roundTripper http.RoundTripper, config *dynamic.UpstreamTimeouts) (http.RoundTripper, error) {
+	rt := &upstreamTimeoutsRoundTripper{RoundTripper: roundTripper}
+
+	if config != nil {
+		if err := validateUpstreamTimeouts(config); err != nil {
+			return nil, err
+		}
+
+		rt.timeouts = *config
+	}
+
+	return rt, nil
+}
+
+func (r *upstreamTimeoutsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeouts := r.timeouts
+	if config, ok := req.Context().Value(upstreamTimeoutsKey{}).(*dynamic.UpstreamTimeouts); ok {
+		if config.ResponseHeaderTimeout > 0 {
+			timeouts.ResponseHeaderTimeout = config.ResponseHeaderTimeout
+		}
+		if config.ReadTimeout > 0 {
+			timeouts.ReadTimeout = config.ReadTimeout
+		}
+		if config.WriteTimeout > 0 {
+			timeouts.WriteTimeout = config.WriteTimeout
+		}
+	}
+
+	if timeouts == (dynamic.UpstreamTimeouts{}) {
+		return r.RoundTripper.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	deadline := &upstreamDeadline{cancel: cancel}
+
+	// The transport waits for the request body to be read before giving up on the request,
+	// so a write timeout closes the body to unblock its pending reads.
+	if req.Body != nil && req.Body != http.NoBody {
+		deadline.interrupt = func() { _ = req.Body.Close() }
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			deadline.enter(phaseWrite, time.Duration(timeouts.WriteTimeout))
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			deadline.enter(phaseResponseHeader, time.Duration(timeouts.ResponseHeaderTimeout))
+		},
+	}
+
+	resp, err := r.RoundTripper.RoundTrip(req.WithContext(httptrace.WithClientTrace(ctx, trace)))
+	if err != nil {
+		deadline.enter(phaseDone, 0)
+		cancel()
+
+		if expired := deadline.expired(); expired != nil {
+			return nil, expired
+		}
+
+		return nil, err
+	}
+
+	// The upgraded connections are not bounded, and are closed with the request.
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		deadline.enter(phaseDone, 0)
+		return resp, nil
+	}
+
+	deadline.enter(phaseRead, time.Duration(timeouts.ReadTimeout))
+	resp.Body = &upstreamTimeoutsBody{ReadCloser: resp.Body, deadline: deadline}
+
+	return resp, nil
+}
+
+// upstreamDeadline cancels the exchange with a server when its current phase exceeds its timeout.
+type upstreamDeadline struct {
+	cancel    context.CancelFunc
+	interrupt func()
+
+	mu    sync.Mutex
+	phase upstreamPhase
+	timer *time.Timer
+	err   error
+}
+
+// enter moves the exchange to the given phase, bounded by timeout when positive.
+// The phases only move forward, as the request may still be written while the response is read.
+func (d *upstreamDeadline) enter(phase upstreamPhase, timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if phase <= d.phase || d.err != nil {
+		return
+	}
+
+	d.phase = phase
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	if timeout <= 0 {
+		return
+	}
+
+	d.timer = time.AfterFunc(timeout, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if d.phase != phase {
+			return
+		}
+
+		d.err = upstreamTimeoutError{phase: phase}
+		d.cancel()
+
+		// Closing the body may block until its pending read returns.
+		if phase == phaseWrite && d.interrupt != nil {
+			go d.interrupt()
+		}
+	})
+}
+
+// expired returns the timeout error of the phase which exceeded its timeout, if any.
+func (d *upstreamDeadline) expired() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.err
+}
+
+// upstreamTimeoutsBody reports the read timeout of the response body, and ends the exchange once it is closed.
+type upstreamTimeoutsBody struct {
+	io.ReadCloser
+
+	deadline *upstreamDeadline
+}
+
+func (b *upstreamTimeoutsBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		if expired := b.deadline.expired(); expired != nil {
+			return n, expired
+		}
+
+		if errors.Is(err, io.EOF) {
+			b.deadline.enter(phaseDone, 0)
+		}
+	}
+
+	return n, err
+}
+
+func (b *upstreamTimeoutsBody) Close() error {
+	b.deadline.enter(phaseDone, 0)
+	err := b.ReadCloser.Close()
+	b.deadline.cancel()
+
+	return err
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestUpstreamTimeouts_responseHeaderTimeout(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	t.Cleanup(func() { backend.Close() })
+
+	testCases := []struct {
+		desc           string
+		service        *dynamic.UpstreamTimeouts
+		router         *dynamic.UpstreamTimeouts
+		expectedStatus int
+	}{
+		{
+			desc:           "no timeouts",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "service timeout",
+			service:        &dynamic.UpstreamTimeouts{ResponseHeaderTimeout: ptypes.Duration(10 * time.Millisecond)},
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			desc:           "router timeout",
+			router:         &dynamic.UpstreamTimeouts{ResponseHeaderTimeout: ptypes.Duration(10 * time.Millisecond)},
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+		{
+			desc:           "router timeout overriding the service one",
+			service:        &dynamic.UpstreamTimeouts{ResponseHeaderTimeout: ptypes.Duration(10 * time.Millisecond)},
+			router:         &dynamic.UpstreamTimeouts{ResponseHeaderTimeout: ptypes.Duration(time.Second)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "router without the timeout of the service",
+			service:        &dynamic.UpstreamTimeouts{ResponseHeaderTimeout: ptypes.Duration(10 * time.Millisecond)},
+			router:         &dynamic.UpstreamTimeouts{ReadTimeout: ptypes.Duration(time.Second)},
+			expectedStatus: http.StatusGatewayTimeout,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			roundTripper, err := newUpstreamTimeoutsRoundTripper(http.DefaultTransport, test.service)
+			require.NoError(t, err)
+
+			proxy, err := buildProxy(Bool(true), nil, roundTripper, nil)
+			require.NoError(t, err)
+
+			handler, err := NewRouterUpstreamTimeouts(proxy, test.router)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, backend.URL, nil))
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+		})
+	}
+}
+
+func TestUpstreamTimeouts_writeTimeout(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(ioutil.Discard, req.Body)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	roundTripper, err := newUpstreamTimeoutsRoundTripper(http.DefaultTransport, &dynamic.UpstreamTimeouts{
+		WriteTimeout: ptypes.Duration(10 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	// The request body is never completed.
+	body, bodyWriter := io.Pipe()
+	defer func() { _ = bodyWriter.Close() }()
+
+	req, err := http.NewRequest(http.MethodPost, backend.URL, body)
+	require.NoError(t, err)
+
+	_, err = roundTripper.RoundTrip(req)
+
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+	assert.Equal(t, upstreamTimeoutError{phase: phaseWrite}, err)
+}
+
+func TestUpstreamTimeouts_readTimeout(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		rw.(http.Flusher).Flush()
+
+		select {
+		case <-req.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer backend.Close()
+
+	roundTripper, err := newUpstreamTimeoutsRoundTripper(http.DefaultTransport, &dynamic.UpstreamTimeouts{
+		ReadTimeout: ptypes.Duration(10 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, backend.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := roundTripper.RoundTrip(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = ioutil.ReadAll(resp.Body)
+	assert.Equal(t, upstreamTimeoutError{phase: phaseRead}, err)
+}
+
+func TestUpstreamTimeouts_negative(t *testing.T) {
+	config := &dynamic.UpstreamTimeouts{ReadTimeout: ptypes.Duration(-time.Second)}
+
+	_, err := newUpstreamTimeoutsRoundTripper(http.DefaultTransport, config)
+	assert.Error(t, err)
+
+	_, err = NewRouterUpstreamTimeouts(http.NotFoundHandler(), config)
+	assert.Error(t, err)
+}
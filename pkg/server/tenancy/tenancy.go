@@ -0,0 +1,226 @@
+package tenancy
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+)
+
+const tenantName = "tenantName"
+
+// Enforcer restricts the configurations of the providers of the tenants to the entry points allowed to them,
+// and truncates them to the quotas of the tenants.
+type Enforcer struct {
+	tenants map[string]static.Tenant
+}
+
+// New creates an Enforcer.
+func New(tenants map[string]static.Tenant) *Enforcer {
+	return &Enforcer{tenants: tenants}
+}
+
+// Apply returns the configurations of the providers, with the ones of the tenants enforced.
+// The given configurations are not modified, the enforced ones being copies.
+func (e *Enforcer) Apply(configurations dynamic.Configurations, defaultEntryPoints []string) dynamic.Configurations {
+	if e == nil || len(e.tenants) == 0 {
+		return configurations
+	}
+
+	result := make(dynamic.Configurations, len(configurations))
+	for name, conf := range configurations {
+		result[name] = conf
+	}
+
+	for name, tenant := range e.tenants {
+		var providerNames []string
+		for _, providerName := range tenant.Providers {
+			if conf := configurations[providerName]; conf != nil {
+				providerNames = append(providerNames, providerName)
+				result[providerName] = conf.DeepCopy()
+			}
+		}
+		sort.Strings(providerNames)
+
+		logger := log.WithoutContext().WithField(tenantName, name)
+
+		if len(tenant.EntryPoints) > 0 {
+			for _, providerName := range providerNames {
+				restrictEntryPoints(logger, result[providerName], providerName, tenant.EntryPoints, defaultEntryPoints)
+			}
+		}
+
+		enforceQuota(logger, tenant.MaxRouters, "router", routers(result, providerNames))
+		enforceQuota(logger, tenant.MaxMiddlewares, "middleware", middlewares(result, providerNames))
+		enforceQuota(logger, tenant.MaxServices, "service", services(result, providerNames))
+	}
+
+	return result
+}
+
+// restrictEntryPoints removes the entry points which are not allowed from the routers of a provider,
+// and the routers left without any entry point.
+// The HTTP routers without entry points use the allowed default entry points, and the TCP and UDP ones all the allowed entry points.
+func restrictEntryPoints(logger logrus.FieldLogger, conf *dynamic.Configuration, providerName string, allowed, defaultEntryPoints []string) {
+	restrict := func(routerName string, entryPoints, implicit []string) []string {
+		if len(entryPoints) == 0 {
+			entryPoints = implicit
+		}
+
+		var result []string
+		for _, entryPoint := range entryPoints {
+			if contains(allowed, entryPoint) {
+				result = append(result, entryPoint)
+				continue
+			}
+
+			logger.WithField(log.RouterName, provider.MakeQualifiedName(providerName, routerName)).
+				WithField(log.EntryPointName, entryPoint).
+				Errorf("The entry point %s is not allowed to the tenant, it is removed from the router", entryPoint)
+		}
+
+		return result
+	}
+
+	if conf.HTTP != nil {
+		for name, router := range conf.HTTP.Routers {
+			if router.EntryPoints = restrict(name, router.EntryPoints, defaultEntryPoints); len(router.EntryPoints) == 0 {
+				logRemovedRouter(logger, providerName, name)
+				delete(conf.HTTP.Routers, name)
+			}
+		}
+	}
+
+	if conf.TCP != nil {
+		for name, router := range conf.TCP.Routers {
+			if router.EntryPoints = restrict(name, router.EntryPoints, allowed); len(router.EntryPoints) == 0 {
+				logRemovedRouter(logger, providerName, name)
+				delete(conf.TCP.Routers, name)
+			}
+		}
+	}
+
+	if conf.UDP != nil {
+		for name, router := range conf.UDP.Routers {
+			if router.EntryPoints = restrict(name, router.EntryPoints, allowed); len(router.EntryPoints) == 0 {
+				logRemovedRouter(logger, providerName, name)
+				delete(conf.UDP.Routers, name)
+			}
+		}
+	}
+}
+
+func logRemovedRouter(logger logrus.FieldLogger, providerName, routerName string) {
+	logger.WithField(log.RouterName, provider.MakeQualifiedName(providerName, routerName)).
+		Error("None of the entry points of the router is allowed to the tenant, the router is removed")
+}
+
+// element is an element of the configuration of a tenant, counted in a quota.
+type element struct {
+	name   string
+	remove func()
+}
+
+// enforceQuota removes the elements beyond the quota, the elements being sorted by provider, protocol, and name,
+// so that the same elements are kept across the configuration changes.
+func enforceQuota(logger logrus.FieldLogger, quota int, kind string, elements []element) {
+	if quota <= 0 || len(elements) <= quota {
+		return
+	}
+
+	logger.Errorf("The tenant has %d %ss, beyond its quota of %d, the last ones are removed", len(elements), kind, quota)
+
+	for _, elt := range elements[quota:] {
+		logger.Errorf("The %s %s is beyond the quota of the tenant, it is removed", kind, elt.name)
+		elt.remove()
+	}
+}
+
+func routers(configurations dynamic.Configurations, providerNames []string) []element {
+	var elements []element
+	for _, providerName := range providerNames {
+		conf := configurations[providerName]
+
+		if conf.HTTP != nil {
+			elements = appendElements(elements, providerName, conf.HTTP.Routers)
+		}
+		if conf.TCP != nil {
+			elements = appendElements(elements, providerName, conf.TCP.Routers)
+		}
+		if conf.UDP != nil {
+			elements = appendElements(elements, providerName, conf.UDP.Routers)
+		}
+	}
+
+	return elements
+}
+
+func middlewares(configurations dynamic.Configurations, providerNames []string) []element {
+	var elements []element
+	for _, providerName := range providerNames {
+		conf := configurations[providerName]
+
+		if conf.HTTP != nil {
+			elements = appendElements(elements, providerName, conf.HTTP.Middlewares)
+		}
+		if conf.TCP != nil {
+			elements = appendElements(elements, providerName, conf.TCP.Middlewares)
+		}
+	}
+
+	return elements
+}
+
+func services(configurations dynamic.Configurations, providerNames []string) []element {
+	var elements []element
+	for _, providerName := range providerNames {
+		conf := configurations[providerName]
+
+		if conf.HTTP != nil {
+			elements = appendElements(elements, providerName, conf.HTTP.Services)
+		}
+		if conf.TCP != nil {
+			elements = appendElements(elements, providerName, conf.TCP.Services)
+		}
+		if conf.UDP != nil {
+			elements = appendElements(elements, providerName, conf.UDP.Services)
+		}
+	}
+
+	return elements
+}
+
+// appendElements appends the elements of a map of the configuration of a provider, sorted by name.
+func appendElements(elements []element, providerName string, m interface{}) []element {
+	value := reflect.ValueOf(m)
+
+	names := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := reflect.ValueOf(name)
+		elements = append(elements, element{
+			name:   provider.MakeQualifiedName(providerName, name),
+			remove: func() { value.SetMapIndex(key, reflect.Value{}) },
+		})
+	}
+
+	return elements
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+package tenancy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestEnforcer_Apply(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		tenant   static.Tenant
+		given    dynamic.Configurations
+		expected dynamic.Configurations
+	}{
+		{
+			desc:   "HTTP routers restricted to the allowed entry points",
+			tenant: static.Tenant{Providers: []string{"kubernetescrd"}, EntryPoints: []string{"websecure"}},
+			given: dynamic.Configurations{
+				"kubernetescrd": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"both":    {EntryPoints: []string{"web", "websecure"}},
+							"web":     {EntryPoints: []string{"web"}},
+							"default": {},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configurations{
+				"kubernetescrd": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"both":    {EntryPoints: []string{"websecure"}},
+							"default": {EntryPoints: []string{"websecure"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:   "TCP and UDP routers restricted to the allowed entry points",
+			tenant: static.Tenant{Providers: []string{"file"}, EntryPoints: []string{"tcp"}},
+			given: dynamic.Configurations{
+				"file": &dynamic.Configuration{
+					TCP: &dynamic.TCPConfiguration{
+						Routers: map[string]*dynamic.TCPRouter{
+							"all": {},
+							"foo": {EntryPoints: []string{"foo"}},
+						},
+					},
+					UDP: &dynamic.UDPConfiguration{
+						Routers: map[string]*dynamic.UDPRouter{
+							"udp": {EntryPoints: []string{"udp"}},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configurations{
+				"file": &dynamic.Configuration{
+					TCP: &dynamic.TCPConfiguration{
+						Routers: map[string]*dynamic.TCPRouter{
+							"all": {EntryPoints: []string{"tcp"}},
+						},
+					},
+					UDP: &dynamic.UDPConfiguration{
+						Routers: map[string]*dynamic.UDPRouter{},
+					},
+				},
+			},
+		},
+		{
+			desc:   "quotas across the providers of the tenant",
+			tenant: static.Tenant{Providers: []string{"file", "consul"}, MaxRouters: 2, MaxMiddlewares: 1, MaxServices: 3},
+			given: dynamic.Configurations{
+				"consul": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"b": {},
+							"a": {},
+						},
+						Middlewares: map[string]*dynamic.Middleware{
+							"a": {},
+						},
+						Services: map[string]*dynamic.Service{
+							"a": {},
+						},
+					},
+				},
+				"file": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"a": {},
+						},
+						Middlewares: map[string]*dynamic.Middleware{
+							"a": {},
+						},
+					},
+					TCP: &dynamic.TCPConfiguration{
+						Services: map[string]*dynamic.TCPService{
+							"a": {},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configurations{
+				"consul": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"a": {},
+							"b": {},
+						},
+						Middlewares: map[string]*dynamic.Middleware{
+							"a": {},
+						},
+						Services: map[string]*dynamic.Service{
+							"a": {},
+						},
+					},
+				},
+				"file": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers:     map[string]*dynamic.Router{},
+						Middlewares: map[string]*dynamic.Middleware{},
+					},
+					TCP: &dynamic.TCPConfiguration{
+						Services: map[string]*dynamic.TCPService{
+							"a": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:   "providers of other tenants",
+			tenant: static.Tenant{Providers: []string{"file"}, MaxRouters: 1},
+			given: dynamic.Configurations{
+				"docker": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"a": {},
+							"b": {},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configurations{
+				"docker": &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"a": {},
+							"b": {},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			given := test.given.DeepCopy()
+
+			enforcer := New(map[string]static.Tenant{"foo": test.tenant})
+			actual := enforcer.Apply(test.given, []string{"web", "websecure"})
+
+			assert.Equal(t, test.expected, actual)
+
+			// The configurations of the providers are not modified.
+			assert.Equal(t, given, test.given)
+		})
+	}
+}
+
+func TestEnforcer_Apply_nil(t *testing.T) {
+	var enforcer *Enforcer
+
+	configurations := dynamic.Configurations{"file": &dynamic.Configuration{}}
+	assert.Equal(t, configurations, enforcer.Apply(configurations, nil))
+}
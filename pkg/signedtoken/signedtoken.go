@@ -0,0 +1,86 @@
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verifier checks the tokens signed with a secret, such as the values of the headers reserved to the operators.
+// A token is made of its fields, the Unix time at which it expires,
+// and the hex encoded HMAC-SHA256 signature of the token, separated by semicolons.
+// The signed data is made of the domain of the token, its fields, and its expiration time, separated by new lines,
+// so that the token of a domain is never valid for another domain.
+type Verifier struct {
+	secret []byte
+	maxAge time.Duration
+}
+
+// NewVerifier creates a Verifier accepting the tokens expiring in less than maxAge.
+func NewVerifier(secret string, maxAge time.Duration) *Verifier {
+	return &Verifier{secret: []byte(secret), maxAge: maxAge}
+}
+
+// Verify checks the signature and the expiration of a token of the domain made of the given number of fields,
+// and returns its fields.
+func (v *Verifier) Verify(now time.Time, token, domain string, fields int) ([]string, error) {
+	parts := strings.Split(token, ";")
+	if len(parts) != fields+2 {
+		return nil, errors.New("malformed token")
+	}
+
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	values, expires, signature := parts[:fields], parts[fields], parts[fields+1]
+
+	mac, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, sign(v.secret, domain, expires, values)) {
+		return nil, errors.New("invalid signature")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration time: %w", err)
+	}
+
+	if now.Unix() > expiresAt {
+		return nil, errors.New("expired token")
+	}
+
+	if time.Unix(expiresAt, 0).Sub(now) > v.maxAge {
+		return nil, fmt.Errorf("the expiration time exceeds the max age of %s", v.maxAge)
+	}
+
+	return values, nil
+}
+
+// Sign returns the token of the domain made of the fields, expiring at the given time.
+func Sign(secret, domain string, expiresAt time.Time, fields ...string) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	signature := hex.EncodeToString(sign([]byte(secret), domain, expires, fields))
+
+	parts := make([]string, 0, len(fields)+2)
+	parts = append(parts, fields...)
+	parts = append(parts, expires, signature)
+
+	return strings.Join(parts, ";")
+}
+
+func sign(secret []byte, domain, expires string, fields []string) []byte {
+	data := make([]string, 0, len(fields)+2)
+	data = append(data, domain)
+	data = append(data, fields...)
+	data = append(data, expires)
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(strings.Join(data, "\n")))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,82 @@
+package signedtoken
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc          string
+		token         string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			desc:     "valid token",
+			token:    Sign("secret", "upstream\nfoo@file", now.Add(time.Minute), "http://10.0.0.1:80"),
+			expected: []string{"http://10.0.0.1:80"},
+		},
+		{
+			desc:          "token of another domain",
+			token:         Sign("secret", "upstream\nbar@file", now.Add(time.Minute), "http://10.0.0.1:80"),
+			expectedError: true,
+		},
+		{
+			desc:          "token signed with another secret",
+			token:         Sign("other", "upstream\nfoo@file", now.Add(time.Minute), "http://10.0.0.1:80"),
+			expectedError: true,
+		},
+		{
+			desc:          "invalid signature",
+			token:         "http://10.0.0.1:80;" + strconv.FormatInt(now.Add(time.Minute).Unix(), 10) + ";deadbeef",
+			expectedError: true,
+		},
+		{
+			desc:          "malformed token",
+			token:         "http://10.0.0.1:80",
+			expectedError: true,
+		},
+		{
+			desc:          "expired token",
+			token:         Sign("secret", "upstream\nfoo@file", now.Add(-time.Second), "http://10.0.0.1:80"),
+			expectedError: true,
+		},
+		{
+			desc:          "expiration beyond the max age",
+			token:         Sign("secret", "upstream\nfoo@file", now.Add(time.Hour), "http://10.0.0.1:80"),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := NewVerifier("secret", 5*time.Minute)
+
+			fields, err := verifier.Verify(now, test.token, "upstream\nfoo@file", 1)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, fields)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	expiresAt := time.Unix(1609459260, 0)
+
+	// printf 'debug\n1609459260' | openssl dgst -sha256 -hmac secret -hex
+	assert.Equal(t, "1609459260;e322ecee013ab378a8bb2f411a42d192a5a6a27416fb8ff1cc99eb6edfc3e8d4", Sign("secret", "debug", expiresAt))
+}
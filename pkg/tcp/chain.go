@@ -0,0 +1,44 @@
+package tcp
+
+import "errors"
+
+// Constructor creates a handler wrapping next.
+type Constructor func(next Handler) (Handler, error)
+
+// Chain is a chain of TCP middleware constructors, applied in order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// NewChain creates a new chain, memorizing the given list of middleware constructors.
+func NewChain(constructors ...Constructor) Chain {
+	return Chain{constructors: append([]Constructor(nil), constructors...)}
+}
+
+// Append extends a chain, adding the specified constructors as the last ones in the request flow.
+// A new chain is returned, the original one is left untouched.
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return Chain{constructors: newCons}
+}
+
+// Then chains the middlewares and returns the final handler:
+// the first constructor of the chain is the outermost one, and handles the connections first.
+func (c Chain) Then(h Handler) (Handler, error) {
+	if h == nil {
+		return nil, errors.New("cannot add a nil handler to the chain")
+	}
+
+	for i := len(c.constructors) - 1; i >= 0; i-- {
+		handler, err := c.constructors[i](h)
+		if err != nil {
+			return nil, err
+		}
+		h = handler
+	}
+
+	return h, nil
+}
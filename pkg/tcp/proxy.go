@@ -1,14 +1,15 @@
 package tcp
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"net"
 	"time"
 
 	"github.com/pires/go-proxyproto"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/transparent"
 )
 
 // Proxy forwards a TCP request to a TCP service.
@@ -18,6 +19,7 @@ type Proxy struct {
 	terminationDelay time.Duration
 	proxyProtocol    *dynamic.ProxyProtocol
 	refreshTarget    bool
+	transparent      bool
 }
 
 // NewProxy creates a new Proxy.
@@ -46,28 +48,66 @@ func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dyn
 	}, nil
 }
 
+// EnableTransparent makes the proxy dial the backend from the IP address of the client,
+// so that the backend sees the address of the client at the network level.
+func (p *Proxy) EnableTransparent() error {
+	if !transparent.Supported() {
+		return errors.New("the transparent mode is only supported on Linux")
+	}
+
+	p.transparent = true
+	return nil
+}
+
 // ServeTCP forwards the connection to a service.
 func (p *Proxy) ServeTCP(conn WriteCloser) {
 	log.Debugf("Handling connection from %s", conn.RemoteAddr())
 
-	// needed because of e.g. server.trackedConnection
-	defer conn.Close()
+	connBackend, err := p.dialBackend(conn.RemoteAddr())
+	if err != nil {
+		log.Errorf("Error while connection to backend: %v", err)
+		// needed because of e.g. server.trackedConnection
+		conn.Close()
+		return
+	}
 
+	p.serveBackend(conn, connBackend)
+}
+
+// dialBackend establishes the connection to the backend,
+// from the address of the client when the transparent mode is enabled.
+func (p *Proxy) dialBackend(client net.Addr) (*net.TCPConn, error) {
 	if p.refreshTarget {
 		tcpAddr, err := net.ResolveTCPAddr("tcp", p.address)
 		if err != nil {
-			log.Errorf("Error resolving tcp address: %v", err)
-			return
+			return nil, fmt.Errorf("error resolving tcp address: %w", err)
 		}
 		p.target = tcpAddr
 	}
 
-	connBackend, err := net.DialTCP("tcp", nil, p.target)
+	if !p.transparent {
+		return net.DialTCP("tcp", nil, p.target)
+	}
+
+	dialer, err := transparent.Dialer("tcp", client)
 	if err != nil {
-		log.Errorf("Error while connection to backend: %v", err)
-		return
+		return nil, err
 	}
 
+	conn, err := dialer.Dial("tcp", p.target.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.(*net.TCPConn), nil
+}
+
+// serveBackend forwards the connection to the established backend connection,
+// and closes both of them once done.
+func (p *Proxy) serveBackend(conn WriteCloser, connBackend *net.TCPConn) {
+	// needed because of e.g. server.trackedConnection
+	defer conn.Close()
+
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 	errChan := make(chan error)
@@ -83,7 +123,7 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
 
-	err = <-errChan
+	err := <-errChan
 	if err != nil {
 		log.WithoutContext().Errorf("Error during connection: %v", err)
 	}
@@ -92,7 +132,7 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 }
 
 func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
-	_, err := io.Copy(dst, src)
+	_, err := spliceCopy(dst, src)
 	errCh <- err
 
 	errClose := dst.CloseWrite()
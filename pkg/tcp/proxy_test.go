@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/transparent"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -173,6 +175,44 @@ func TestProxyProtocol(t *testing.T) {
 	}
 }
 
+func TestProxy_transparent(t *testing.T) {
+	if !transparent.Supported() {
+		t.Skip("the transparent mode is not supported on this platform")
+	}
+
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	remoteAddr := make(chan net.Addr, 1)
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		remoteAddr <- conn.RemoteAddr()
+		_ = conn.Close()
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil)
+	require.NoError(t, err)
+	require.NoError(t, proxy.EnableTransparent())
+
+	connBackend, err := proxy.dialBackend(&net.TCPAddr{IP: net.ParseIP("127.0.0.2"), Port: 41000})
+	if errors.Is(err, syscall.EPERM) {
+		t.Skip("the transparent mode requires the CAP_NET_ADMIN capability")
+	}
+	require.NoError(t, err)
+	defer connBackend.Close()
+
+	select {
+	case addr := <-remoteAddr:
+		assert.Equal(t, "127.0.0.2", addr.(*net.TCPAddr).IP.String())
+	case <-time.After(time.Second):
+		t.Fatal("the backend did not accept the connection")
+	}
+}
+
 func TestLookupAddress(t *testing.T) {
 	testCases := []struct {
 		desc       string
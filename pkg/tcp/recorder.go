@@ -0,0 +1,46 @@
+package tcp
+
+// RoutingRecorder is implemented by the connections recording how they are routed,
+// so that the active connections of an entry point can be listed.
+type RoutingRecorder interface {
+	// RecordRouting records whether the connection starts with a TLS ClientHello, and its server name.
+	RecordRouting(isTLS bool, serverName string)
+	// RecordRouter records the name of the TCP router handling the connection.
+	RecordRouter(name string)
+}
+
+// RouterRecorder returns a handler recording the name of the TCP router handling the connections,
+// before passing them to next.
+// It must wrap the TLS termination, as the recorder cannot be found under a TLS connection.
+func RouterRecorder(routerName string, next Handler) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		if recorder, ok := routingRecorder(conn); ok {
+			recorder.RecordRouter(routerName)
+		}
+
+		next.ServeTCP(conn)
+	})
+}
+
+// recordRouting records the routing information on the recorder under conn, if any.
+func recordRouting(conn WriteCloser, isTLS bool, serverName string) {
+	if recorder, ok := routingRecorder(conn); ok {
+		recorder.RecordRouting(isTLS, serverName)
+	}
+}
+
+// routingRecorder returns the recorder under conn, looking through the wrappers transferring the bytes unchanged.
+func routingRecorder(conn WriteCloser) (RoutingRecorder, bool) {
+	for {
+		switch c := conn.(type) {
+		case RoutingRecorder:
+			return c, true
+		case *Conn:
+			conn = c.WriteCloser
+		case Unwrapper:
+			conn = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
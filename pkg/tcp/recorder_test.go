@@ -0,0 +1,54 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedConn struct {
+	WriteCloser
+	isTLS      bool
+	serverName string
+	router     string
+}
+
+func (r *recordedConn) RecordRouting(isTLS bool, serverName string) {
+	r.isTLS = isTLS
+	r.serverName = serverName
+}
+
+func (r *recordedConn) RecordRouter(name string) {
+	r.router = name
+}
+
+func TestRouterRecorder(t *testing.T) {
+	recorded := &recordedConn{}
+
+	var served WriteCloser
+	handler := RouterRecorder("foo@file", HandlerFunc(func(conn WriteCloser) {
+		served = conn
+	}))
+
+	conn := &Conn{Peeked: []byte("peeked"), WriteCloser: unwrapper{WriteCloser: recorded}}
+	handler.ServeTCP(conn)
+
+	assert.Equal(t, "foo@file", recorded.router)
+	assert.Equal(t, conn, served)
+
+	recordRouting(conn, true, "foo.bar")
+
+	assert.True(t, recorded.isTLS)
+	assert.Equal(t, "foo.bar", recorded.serverName)
+}
+
+func TestRouterRecorder_noRecorder(t *testing.T) {
+	var served bool
+	handler := RouterRecorder("foo@file", HandlerFunc(func(conn WriteCloser) {
+		served = true
+	}))
+
+	handler.ServeTCP(&Conn{WriteCloser: opaqueConn{WriteCloser: &recordedConn{}}})
+
+	assert.True(t, served)
+}
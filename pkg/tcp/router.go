@@ -11,13 +11,18 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
+// MaxDataPrefixLength is the maximum length of the prefixes of the first bytes of the non-TLS connections used to route them.
+const MaxDataPrefixLength = 1024
+
 // Router is a TCP router.
 type Router struct {
 	routingTable      map[string]Handler
+	prefixRoutes      map[string]Handler // non-TLS routes keyed by the prefix of the first bytes of the connections
 	httpForwarder     Handler
 	httpsForwarder    Handler
 	httpHandler       http.Handler
@@ -25,6 +30,8 @@ type Router struct {
 	httpsTLSConfig    *tls.Config // default TLS config
 	catchAllNoTLS     Handler
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	// hasACMETLSChallenge reports whether the TLS-ALPN-01 challenge of a server name is answered by Traefik.
+	hasACMETLSChallenge func(serverName string) bool
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
@@ -41,18 +48,29 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
+	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 && len(r.prefixRoutes) == 0 {
+		recordRouting(conn, false, "")
 		r.catchAllNoTLS.ServeTCP(conn)
 		return
 	}
 
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked, err := clientHelloServerName(br)
+	hello, err := readClientHello(br)
 	if err != nil {
 		conn.Close()
 		return
 	}
 
+	serverName, tls, peeked := hello.serverName, hello.isTLS, hello.peeked
+
+	var prefixTarget Handler
+	if !tls && len(r.prefixRoutes) > 0 {
+		prefixTarget = r.matchPrefix(br)
+		peeked = getPeeked(br)
+	}
+
+	recordRouting(conn, tls, serverName)
+
 	// Remove read/write deadline and delegate this to underlying tcp server (for now only handled by HTTP Server)
 	err = conn.SetReadDeadline(time.Time{})
 	if err != nil {
@@ -66,6 +84,8 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 
 	if !tls {
 		switch {
+		case prefixTarget != nil:
+			prefixTarget.ServeTCP(r.GetConn(conn, peeked))
 		case r.catchAllNoTLS != nil:
 			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
 		case r.httpForwarder != nil:
@@ -78,21 +98,28 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 
 	// FIXME Optimize and test the routing table before helloServerName
 	serverName = types.CanonicalDomain(serverName)
+
+	// The TLS-ALPN-01 challenges answered by Traefik are terminated by the HTTPS forwarder,
+	// even for the hosts of the TLS passthrough routers.
+	if hello.isACMETLSChallenge() && r.httpsForwarder != nil && r.hasACMETLSChallenge != nil && r.hasACMETLSChallenge(serverName) {
+		r.httpsForwarder.ServeTCP(newConn(conn, peeked, serverName))
+		return
+	}
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			target.ServeTCP(newConn(conn, peeked, serverName))
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(newConn(conn, peeked, serverName))
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.httpsForwarder.ServeTCP(newConn(conn, peeked, serverName))
 	} else {
 		conn.Close()
 	}
@@ -106,6 +133,47 @@ func (r *Router) AddRoute(sniHost string, target Handler) {
 	r.routingTable[strings.ToLower(sniHost)] = target
 }
 
+// AddRoutePrefix defines a handler for the non-TLS connections whose first bytes start with prefix.
+// The prefix must not be longer than MaxDataPrefixLength.
+func (r *Router) AddRoutePrefix(prefix string, target Handler) {
+	if r.prefixRoutes == nil {
+		r.prefixRoutes = map[string]Handler{}
+	}
+	r.prefixRoutes[prefix] = target
+}
+
+// matchPrefix returns the handler of the longest prefix matching the first bytes of the connection, if any,
+// without consuming any bytes from br.
+// More bytes are only peeked while the bytes received so far can still match a longer prefix,
+// so that the connections of the other protocols are not delayed.
+func (r *Router) matchPrefix(br *bufio.Reader) Handler {
+	var target Handler
+	var matched int
+
+	for {
+		data, _ := br.Peek(br.Buffered())
+
+		undecided := false
+		for prefix, handler := range r.prefixRoutes {
+			switch {
+			case len(prefix) > matched && bytes.HasPrefix(data, []byte(prefix)):
+				target, matched = handler, len(prefix)
+			case len(prefix) > len(data) && strings.HasPrefix(prefix, string(data)):
+				undecided = true
+			}
+		}
+
+		if !undecided {
+			return target
+		}
+
+		if _, err := br.Peek(len(data) + 1); err != nil {
+			log.WithoutContext().Debugf("Error while peeking the first bytes: %v", err)
+			return target
+		}
+	}
+}
+
 // AddRouteTLS defines a handler for a given sniHost and sets the matching tlsConfig.
 func (r *Router) AddRouteTLS(sniHost string, target Handler, config *tls.Config) {
 	r.AddRoute(sniHost, &TLSHandler{
@@ -122,6 +190,12 @@ func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	r.hostHTTPTLSConfig[sniHost] = config
 }
 
+// ACMETLSChallenge sets the function reporting whether the TLS-ALPN-01 challenge of a server name is answered by Traefik,
+// so that these challenges are not passed through to the servers of the TLS passthrough routers.
+func (r *Router) ACMETLSChallenge(hasChallenge func(serverName string) bool) {
+	r.hasACMETLSChallenge = hasChallenge
+}
+
 // AddCatchAllNoTLS defines the fallback tcp handler.
 func (r *Router) AddCatchAllNoTLS(handler Handler) {
 	r.catchAllNoTLS = handler
@@ -130,11 +204,35 @@ func (r *Router) AddCatchAllNoTLS(handler Handler) {
 // GetConn creates a connection proxy with a peeked string.
 func (r *Router) GetConn(conn WriteCloser, peeked string) WriteCloser {
 	// FIXME should it really be on Router ?
-	conn = &Conn{
+	return newConn(conn, peeked, "")
+}
+
+// newConn creates a connection proxy with a peeked string, and the server name of its ClientHello, if any.
+func newConn(conn WriteCloser, peeked, serverName string) *Conn {
+	return &Conn{
 		Peeked:      []byte(peeked),
 		WriteCloser: conn,
+		serverName:  serverName,
+	}
+}
+
+// ServerName returns the canonical server name of the ClientHello which started the connection,
+// looking through the wrappers transferring the bytes unchanged.
+// It is empty when the connection does not start with a TLS handshake, or when the client did not send any SNI.
+func ServerName(conn WriteCloser) string {
+	for {
+		switch c := conn.(type) {
+		case *Conn:
+			if c.serverName != "" {
+				return c.serverName
+			}
+			conn = c.WriteCloser
+		case Unwrapper:
+			conn = c.Unwrap()
+		default:
+			return ""
+		}
 	}
-	return conn
 }
 
 // GetHTTPHandler gets the attached http handler.
@@ -187,6 +285,9 @@ type Conn struct {
 	// as needed. It should not be read from directly unless
 	// Peeked is nil.
 	WriteCloser
+
+	// serverName is the canonical server name of the ClientHello, set for the TLS connections.
+	serverName string
 }
 
 // Read reads bytes from the connection (using the buffer prior to actually reading).
@@ -202,10 +303,29 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
-// clientHelloServerName returns the SNI server name inside the TLS ClientHello,
+// clientHello holds the information of the beginning of a connection used to route it.
+type clientHello struct {
+	serverName string   // SNI server name
+	isTLS      bool     // whether the connection starts with a TLS handshake
+	protos     []string // ALPN protocols supported by the client
+	peeked     string   // the bytes peeked from the connection
+}
+
+// isACMETLSChallenge reports whether the ClientHello is the one of an ACME TLS-ALPN-01 challenge.
+func (h *clientHello) isACMETLSChallenge() bool {
+	for _, proto := range h.protos {
+		if proto == tlsalpn01.ACMETLS1Protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readClientHello returns the information of the TLS ClientHello, such as the SNI server name,
 // without consuming any bytes from br.
-// On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
+// On any error, the empty server name is returned.
+func readClientHello(br *bufio.Reader) (*clientHello, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		var opErr *net.OpError
@@ -213,7 +333,7 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 			log.WithoutContext().Debugf("Error while Peeking first byte: %s", err)
 		}
 
-		return "", false, "", err
+		return nil, err
 	}
 
 	// No valid TLS record has a type of 0x80, however SSLv2 handshakes
@@ -225,35 +345,38 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	if hdr[0] != recordTypeHandshake {
 		if hdr[0] == recordTypeSSLv2 {
 			// we consider SSLv2 as TLS and it will be refuse by real TLS handshake.
-			return "", true, getPeeked(br), nil
+			return &clientHello{isTLS: true, peeked: getPeeked(br)}, nil
 		}
-		return "", false, getPeeked(br), nil // Not TLS.
+		return &clientHello{peeked: getPeeked(br)}, nil // Not TLS.
 	}
 
 	const recordHeaderLen = 5
 	hdr, err = br.Peek(recordHeaderLen)
 	if err != nil {
 		log.Errorf("Error while Peeking hello: %s", err)
-		return "", false, getPeeked(br), nil
+		return &clientHello{peeked: getPeeked(br)}, nil
 	}
 
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
 	helloBytes, err := br.Peek(recordHeaderLen + recLen)
 	if err != nil {
 		log.Errorf("Error while Hello: %s", err)
-		return "", true, getPeeked(br), nil
+		return &clientHello{isTLS: true, peeked: getPeeked(br)}, nil
 	}
 
-	sni := ""
+	hello := &clientHello{isTLS: true}
 	server := tls.Server(sniSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
-		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
-			sni = hello.ServerName
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello.serverName = info.ServerName
+			hello.protos = info.SupportedProtos
 			return nil, nil
 		},
 	})
 	_ = server.Handshake()
 
-	return sni, true, getPeeked(br), nil
+	hello.peeked = getPeeked(br)
+
+	return hello, nil
 }
 
 func getPeeked(br *bufio.Reader) string {
@@ -0,0 +1,180 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_ACMETLSChallenge(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		protos          []string
+		hasChallenge    bool
+		expectedHandler string
+	}{
+		{
+			desc:            "ACME TLS challenge answered by Traefik",
+			protos:          []string{"acme-tls/1"},
+			hasChallenge:    true,
+			expectedHandler: "https",
+		},
+		{
+			desc:            "ACME TLS challenge of the server",
+			protos:          []string{"acme-tls/1"},
+			expectedHandler: "passthrough",
+		},
+		{
+			desc:            "other protocol",
+			protos:          []string{"h2", "http/1.1"},
+			hasChallenge:    true,
+			expectedHandler: "passthrough",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handled := make(chan string, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					handled <- name
+					_ = conn.Close()
+				})
+			}
+
+			router := &Router{}
+			router.AddRoute("foo.bar", handler("passthrough"))
+			router.HTTPSForwarder(handler("https"))
+			router.ACMETLSChallenge(func(serverName string) bool {
+				return test.hasChallenge && serverName == "foo.bar"
+			})
+
+			client, server := tcpConnPair(t)
+
+			go func() {
+				_ = tls.Client(client, &tls.Config{
+					ServerName: "foo.bar",
+					NextProtos: test.protos,
+					// The handshake is not completed by the handlers.
+					InsecureSkipVerify: true,
+				}).Handshake()
+			}()
+
+			router.ServeTCP(server)
+
+			assert.Equal(t, test.expectedHandler, <-handled)
+		})
+	}
+}
+
+func TestRouter_ServerName(t *testing.T) {
+	served := make(chan string, 1)
+
+	router := &Router{}
+	router.AddRoute("foo.bar", HandlerFunc(func(conn WriteCloser) {
+		served <- ServerName(conn)
+		_ = conn.Close()
+	}))
+
+	client, server := tcpConnPair(t)
+
+	go func() {
+		_ = tls.Client(client, &tls.Config{
+			ServerName: "Foo.Bar",
+			// The handshake is not completed by the handler.
+			InsecureSkipVerify: true,
+		}).Handshake()
+	}()
+
+	router.ServeTCP(server)
+
+	assert.Equal(t, "foo.bar", <-served)
+}
+
+func TestRouter_prefix(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		writes          []string
+		expectedHandler string
+		expectedData    string
+	}{
+		{
+			desc:            "SSH connection",
+			writes:          []string{"SSH-2.0-OpenSSH_8.4\r\n"},
+			expectedHandler: "ssh",
+			expectedData:    "SSH-2.0-OpenSSH_8.4\r\n",
+		},
+		{
+			desc:            "prefix split across several writes",
+			writes:          []string{"S", "SH", "-2.0-OpenSSH_8.4\r\n"},
+			expectedHandler: "ssh",
+			expectedData:    "SSH-2.0-OpenSSH_8.4\r\n",
+		},
+		{
+			desc:            "longest prefix",
+			writes:          []string{"SSH-1.99-OpenSSH_8.4\r\n"},
+			expectedHandler: "ssh1",
+			expectedData:    "SSH-1.99-OpenSSH_8.4\r\n",
+		},
+		{
+			desc:            "other protocol",
+			writes:          []string{"GET / HTTP/1.1\r\n\r\n"},
+			expectedHandler: "http",
+			expectedData:    "GET / HTTP/1.1\r\n\r\n",
+		},
+		{
+			desc:            "bytes matching no prefix anymore",
+			writes:          []string{"SSL"},
+			expectedHandler: "http",
+			expectedData:    "SSL",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			type served struct {
+				handler string
+				data    string
+			}
+			handled := make(chan served, 1)
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					buf := make([]byte, len(test.expectedData))
+					_, err := io.ReadFull(conn, buf)
+					require.NoError(t, err)
+
+					handled <- served{handler: name, data: string(buf)}
+					_ = conn.Close()
+				})
+			}
+
+			router := &Router{}
+			router.AddRoutePrefix("SSH-", handler("ssh"))
+			router.AddRoutePrefix("SSH-1.99", handler("ssh1"))
+			router.HTTPForwarder(handler("http"))
+
+			client, server := tcpConnPair(t)
+
+			go func() {
+				for _, data := range test.writes {
+					_, _ = client.Write([]byte(data))
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+
+			router.ServeTCP(server)
+
+			assert.Equal(t, served{handler: test.expectedHandler, data: test.expectedData}, <-handled)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package tcp
+
+import (
+	"io"
+	"net"
+)
+
+// Unwrapper is implemented by the connection wrappers which read and write the bytes of the wrapped connection unchanged,
+// so that the data can be transferred between the underlying TCP connections without being copied to user space.
+type Unwrapper interface {
+	Unwrap() WriteCloser
+}
+
+// spliceCopy copies from src to dst like io.Copy.
+// When both are TCP connections under wrappers transferring the bytes unchanged,
+// the copy is done between the TCP connections, which lets the kernel move the data (with splice on Linux).
+// Otherwise, e.g. when the connection is TLS terminated, it falls back to a regular copy.
+func spliceCopy(dst, src WriteCloser) (int64, error) {
+	dstConn, ok := rawTCPConn(dst)
+	if !ok {
+		return io.Copy(dst, src)
+	}
+
+	srcConn, ok := rawTCPConn(src)
+	if !ok {
+		return io.Copy(dst, src)
+	}
+
+	// The bytes peeked while routing the connection have to be sent first.
+	written, err := copyPeeked(dstConn, src)
+	if err != nil {
+		return written, err
+	}
+
+	n, err := dstConn.ReadFrom(srcConn)
+	return written + n, err
+}
+
+// rawTCPConn returns the TCP connection under conn, if all its wrappers transfer the bytes unchanged.
+func rawTCPConn(conn WriteCloser) (*net.TCPConn, bool) {
+	for {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			return c, true
+		case *Conn:
+			conn = c.WriteCloser
+		case Unwrapper:
+			conn = c.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+// copyPeeked writes to dst the peeked bytes of the connections under conn which have not been read yet,
+// and marks them as read.
+func copyPeeked(dst io.Writer, conn WriteCloser) (int64, error) {
+	var written int64
+	for {
+		switch c := conn.(type) {
+		case *Conn:
+			if len(c.Peeked) > 0 {
+				n, err := dst.Write(c.Peeked)
+				written += int64(n)
+				if err != nil {
+					return written, err
+				}
+				c.Peeked = nil
+			}
+			conn = c.WriteCloser
+		case Unwrapper:
+			conn = c.Unwrap()
+		default:
+			return written, nil
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tcp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unwrapper struct {
+	WriteCloser
+}
+
+func (u unwrapper) Unwrap() WriteCloser {
+	return u.WriteCloser
+}
+
+type opaqueConn struct {
+	WriteCloser
+}
+
+func TestSpliceCopy(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		wrap        func(conn WriteCloser) WriteCloser
+		expectedRaw bool
+	}{
+		{
+			desc:        "TCP connection",
+			wrap:        func(conn WriteCloser) WriteCloser { return conn },
+			expectedRaw: true,
+		},
+		{
+			desc: "peeked and unwrappable connection",
+			wrap: func(conn WriteCloser) WriteCloser {
+				return &Conn{Peeked: []byte("peeked "), WriteCloser: unwrapper{WriteCloser: conn}}
+			},
+			expectedRaw: true,
+		},
+		{
+			desc: "opaque connection",
+			wrap: func(conn WriteCloser) WriteCloser {
+				return &Conn{Peeked: []byte("peeked "), WriteCloser: opaqueConn{WriteCloser: conn}}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			srcClient, srcServer := tcpConnPair(t)
+			dstClient, dstServer := tcpConnPair(t)
+
+			src := test.wrap(srcServer)
+
+			_, raw := rawTCPConn(src)
+			assert.Equal(t, test.expectedRaw, raw)
+
+			data := bytes.Repeat([]byte("data"), 100000)
+			go func() {
+				_, _ = srcClient.Write(data)
+				_ = srcClient.CloseWrite()
+			}()
+
+			received := make(chan []byte)
+			go func() {
+				b, _ := ioutil.ReadAll(dstClient)
+				received <- b
+			}()
+
+			_, err := spliceCopy(dstServer, src)
+			require.NoError(t, err)
+			require.NoError(t, dstServer.CloseWrite())
+
+			expected := data
+			if c, ok := src.(*Conn); ok {
+				expected = append([]byte("peeked "), data...)
+				assert.Empty(t, c.Peeked)
+			}
+
+			assert.Equal(t, expected, <-received)
+		})
+	}
+}
+
+// tcpConnPair returns the client and server sides of a TCP connection.
+func tcpConnPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, _ := listener.Accept()
+		accepted <- conn
+	}()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	server := <-accepted
+	require.NotNil(t, server)
+	t.Cleanup(func() { _ = server.Close() })
+
+	return client.(*net.TCPConn), server.(*net.TCPConn)
+}
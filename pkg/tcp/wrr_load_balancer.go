@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"fmt"
+	"net"
 	"sync"
 
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -12,12 +13,22 @@ type server struct {
 	weight int
 }
 
+// backendDialer is implemented by the handlers establishing a connection to their backend,
+// whose dial can be retried against another server before serving the connection.
+type backendDialer interface {
+	dialBackend(client net.Addr) (*net.TCPConn, error)
+	serveBackend(conn WriteCloser, connBackend *net.TCPConn)
+}
+
 // WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
 type WRRLoadBalancer struct {
 	servers       []server
 	lock          sync.RWMutex
 	currentWeight int
 	index         int
+
+	connectAttempts int
+	connectRetried  func(attempt int)
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
@@ -34,6 +45,11 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 		return
 	}
 
+	if b.connectAttempts > 1 {
+		b.serveWithConnectRetry(conn)
+		return
+	}
+
 	next, err := b.next()
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
@@ -42,6 +58,54 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 	next.ServeTCP(conn)
 }
 
+// SetConnectRetry makes the load balancer dial up to the given number of servers for a connection,
+// when the connection to a server cannot be established.
+// The retried function, if not nil, is called before each retry.
+func (b *WRRLoadBalancer) SetConnectRetry(attempts int, retried func(attempt int)) {
+	b.connectAttempts = attempts
+	b.connectRetried = retried
+}
+
+func (b *WRRLoadBalancer) serveWithConnectRetry(conn WriteCloser) {
+	attempts := b.connectAttempts
+	if attempts > len(b.servers) {
+		attempts = len(b.servers)
+	}
+
+	for attempt := 1; ; attempt++ {
+		next, err := b.next()
+		if err != nil {
+			log.WithoutContext().Errorf("Error during load balancing: %v", err)
+			conn.Close()
+			return
+		}
+
+		dialer, ok := next.(server).Handler.(backendDialer)
+		if !ok {
+			next.ServeTCP(conn)
+			return
+		}
+
+		connBackend, err := dialer.dialBackend(conn.RemoteAddr())
+		if err == nil {
+			dialer.serveBackend(conn, connBackend)
+			return
+		}
+
+		if attempt >= attempts {
+			log.WithoutContext().Errorf("Error while connection to backend: %v", err)
+			conn.Close()
+			return
+		}
+
+		log.WithoutContext().Debugf("Error while connection to backend, trying the next server: %v", err)
+
+		if b.connectRetried != nil {
+			b.connectRetried(attempt)
+		}
+	}
+}
+
 // AddServer appends a server to the existing list.
 func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 	w := 1
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"io/ioutil"
 	"net"
 	"testing"
 	"time"
@@ -129,3 +130,94 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_connectRetry(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		attempts        int
+		expected        string
+		expectedRetries []int
+	}{
+		{
+			desc: "without retry",
+		},
+		{
+			desc:            "retry on the next server",
+			attempts:        2,
+			expected:        "backend",
+			expectedRetries: []int{1},
+		},
+		{
+			desc:            "attempts bounded by the number of servers",
+			attempts:        5,
+			expected:        "backend",
+			expectedRetries: []int{1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The address of a closed listener refuses the connections.
+			closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			require.NoError(t, closedListener.Close())
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backendListener.Close() })
+
+			go func() {
+				conn, err := backendListener.Accept()
+				if err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte("backend"))
+				_ = conn.Close()
+			}()
+
+			balancer := NewWRRLoadBalancer()
+
+			for _, address := range []string{closedListener.Addr().String(), backendListener.Addr().String()} {
+				proxy, err := NewProxy(address, 10*time.Millisecond, nil)
+				require.NoError(t, err)
+				balancer.AddServer(proxy)
+			}
+
+			var retries []int
+			balancer.SetConnectRetry(test.attempts, func(attempt int) {
+				retries = append(retries, attempt)
+			})
+
+			entryListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = entryListener.Close() })
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				conn, err := entryListener.Accept()
+				if err != nil {
+					return
+				}
+				balancer.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", entryListener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			data, err := ioutil.ReadAll(conn)
+			require.NoError(t, err)
+
+			<-done
+
+			assert.Equal(t, test.expected, string(data))
+			assert.Equal(t, test.expectedRetries, retries)
+		})
+	}
+}
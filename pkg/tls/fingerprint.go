@@ -0,0 +1,19 @@
+package tls
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+)
+
+// GetClientCertFingerprint returns the SHA-256 fingerprint of the client certificate, as lowercase hexadecimal,
+// or an empty string when the client did not send any certificate.
+func GetClientCertFingerprint(connState *tls.ConnectionState) string {
+	if len(connState.PeerCertificates) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(connState.PeerCertificates[0].Raw)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
@@ -24,6 +25,7 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+	generation   uint64
 	lock         sync.RWMutex
 }
 
@@ -42,6 +44,10 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	if !reflect.DeepEqual(m.configs, configs) || !reflect.DeepEqual(m.storesConfig, stores) || !reflect.DeepEqual(m.certs, certs) {
+		m.generation++
+	}
+
 	m.configs = configs
 	m.storesConfig = stores
 	m.certs = certs
@@ -59,14 +65,15 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 
 	storesCertificates := make(map[string]map[string]*tls.Certificate)
 	for _, conf := range certs {
-		if len(conf.Stores) == 0 {
+		certStores := conf.Stores
+		if len(certStores) == 0 {
 			if log.GetLevel() >= logrus.DebugLevel {
 				log.FromContext(ctx).Debugf("No store is defined to add the certificate %s, it will be added to the default store.",
 					conf.Certificate.GetTruncatedCertificateName())
 			}
-			conf.Stores = []string{"default"}
+			certStores = []string{"default"}
 		}
-		for _, store := range conf.Stores {
+		for _, store := range certStores {
 			ctxStore := log.With(ctx, log.Str(log.TLSStoreName, store))
 			if err := conf.Certificate.AppendCertificate(storesCertificates, store); err != nil {
 				log.FromContext(ctxStore).Errorf("Unable to append certificate %s to store: %v", conf.Certificate.GetTruncatedCertificateName(), err)
@@ -79,6 +86,15 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	}
 }
 
+// Generation returns a number changing each time the TLS options, stores, or certificates change.
+// The TLS configurations returned by Get are only up to date for the current generation.
+func (m *Manager) Generation() uint64 {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return m.generation
+}
+
 // Get gets the TLS configuration to use for a given store / configuration.
 func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	m.lock.RLock()
@@ -160,6 +176,24 @@ func (m *Manager) getStore(storeName string) *CertificateStore {
 	return m.stores[storeName]
 }
 
+// HasACMETLSChallenge reports whether a TLS-ALPN-01 challenge certificate is available for the server name,
+// i.e. whether the ACME TLS challenge of the server name is answered by Traefik.
+func (m *Manager) HasACMETLSChallenge(serverName string) bool {
+	if serverName == "" {
+		return false
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	store, ok := m.stores[tlsalpn01.ACMETLS1Protocol]
+	if !ok {
+		return false
+	}
+
+	return store.GetBestCertificate(&tls.ClientHelloInfo{ServerName: serverName}) != nil
+}
+
 // GetStore gets the certificate store of a given name.
 func (m *Manager) GetStore(storeName string) *CertificateStore {
 	m.lock.RLock()
@@ -298,3 +298,39 @@ func TestClientAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_Generation(t *testing.T) {
+	newCerts := func() []*CertAndStores {
+		return []*CertAndStores{{
+			Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+		}}
+	}
+
+	tlsManager := NewManager()
+	generation := tlsManager.Generation()
+
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, newCerts())
+	assert.NotEqual(t, generation, tlsManager.Generation())
+
+	// The same configuration, even without stores for its certificates, keeps the generation.
+	generation = tlsManager.Generation()
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, newCerts())
+	assert.Equal(t, generation, tlsManager.Generation())
+
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {MinVersion: "VersionTLS12"}}, newCerts())
+	assert.NotEqual(t, generation, tlsManager.Generation())
+}
+
+func TestManager_HasACMETLSChallenge(t *testing.T) {
+	tlsManager := NewManager()
+	assert.False(t, tlsManager.HasACMETLSChallenge("example.com"))
+
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": {}}, []*CertAndStores{{
+		Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+		Stores:      []string{"acme-tls/1"},
+	}})
+
+	assert.True(t, tlsManager.HasACMETLSChallenge("example.com"))
+	assert.False(t, tlsManager.HasACMETLSChallenge("foo.bar"))
+	assert.False(t, tlsManager.HasACMETLSChallenge(""))
+}
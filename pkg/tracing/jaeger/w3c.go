@@ -0,0 +1,164 @@
+package jaeger
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	jaegercli "github.com/uber/jaeger-client-go"
+)
+
+// The headers defined by the W3C Trace Context and Baggage specifications.
+const (
+	traceParentHeader = "traceparent"
+	baggageHeader     = "baggage"
+)
+
+const traceParentVersion = "00"
+
+const sampledFlag = 0x01
+
+// w3cPropagator propagates the span contexts with the W3C Trace Context (https://www.w3.org/TR/trace-context/)
+// and Baggage (https://www.w3.org/TR/baggage/) headers.
+// The tracestate header is not handled, as it is forwarded untouched with the request.
+type w3cPropagator struct{}
+
+// Inject implements jaeger.Injector.
+func (p *w3cPropagator) Inject(sc jaegercli.SpanContext, abstractCarrier interface{}) error {
+	carrier, ok := abstractCarrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	var flags byte
+	if sc.IsSampled() {
+		flags |= sampledFlag
+	}
+
+	traceID := sc.TraceID()
+	carrier.Set(traceParentHeader, fmt.Sprintf("%s-%016x%016x-%016x-%02x", traceParentVersion, traceID.High, traceID.Low, uint64(sc.SpanID()), flags))
+
+	var members []string
+	sc.ForeachBaggageItem(func(key, value string) bool {
+		members = append(members, url.PathEscape(key)+"="+url.PathEscape(value))
+		return true
+	})
+
+	if len(members) > 0 {
+		carrier.Set(baggageHeader, strings.Join(members, ","))
+	}
+
+	return nil
+}
+
+// Extract implements jaeger.Extractor.
+func (p *w3cPropagator) Extract(abstractCarrier interface{}) (jaegercli.SpanContext, error) {
+	carrier, ok := abstractCarrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaegercli.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+
+	var traceParent string
+	baggage := make(map[string]string)
+
+	err := carrier.ForeachKey(func(key, value string) error {
+		switch strings.ToLower(key) {
+		case traceParentHeader:
+			traceParent = value
+		case baggageHeader:
+			parseBaggage(value, baggage)
+		}
+		return nil
+	})
+	if err != nil {
+		return jaegercli.SpanContext{}, err
+	}
+
+	if traceParent == "" {
+		if len(baggage) == 0 {
+			return jaegercli.SpanContext{}, opentracing.ErrSpanContextNotFound
+		}
+
+		// The baggage is still propagated to the new trace.
+		return jaegercli.NewSpanContext(jaegercli.TraceID{}, 0, 0, false, baggage), nil
+	}
+
+	traceID, spanID, sampled, err := parseTraceParent(traceParent)
+	if err != nil {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	return jaegercli.NewSpanContext(traceID, spanID, 0, sampled, baggage), nil
+}
+
+// parseTraceParent parses a traceparent header: version-traceid-parentid-flags.
+// The fields added by the future versions are ignored, as required by the specification.
+func parseTraceParent(value string) (jaegercli.TraceID, jaegercli.SpanID, bool, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return jaegercli.TraceID{}, 0, false, errors.New("invalid traceparent")
+	}
+
+	version, rawTraceID, rawSpanID, rawFlags := parts[0], parts[1], parts[2], parts[3]
+
+	if len(version) != 2 || version == "ff" || (version == traceParentVersion && len(parts) != 4) {
+		return jaegercli.TraceID{}, 0, false, fmt.Errorf("invalid traceparent version: %s", version)
+	}
+
+	if len(rawTraceID) != 32 || len(rawSpanID) != 16 || len(rawFlags) != 2 {
+		return jaegercli.TraceID{}, 0, false, errors.New("invalid traceparent")
+	}
+
+	high, err := strconv.ParseUint(rawTraceID[:16], 16, 64)
+	if err != nil {
+		return jaegercli.TraceID{}, 0, false, err
+	}
+
+	low, err := strconv.ParseUint(rawTraceID[16:], 16, 64)
+	if err != nil {
+		return jaegercli.TraceID{}, 0, false, err
+	}
+
+	spanID, err := strconv.ParseUint(rawSpanID, 16, 64)
+	if err != nil {
+		return jaegercli.TraceID{}, 0, false, err
+	}
+
+	flags, err := strconv.ParseUint(rawFlags, 16, 8)
+	if err != nil {
+		return jaegercli.TraceID{}, 0, false, err
+	}
+
+	if (high == 0 && low == 0) || spanID == 0 {
+		return jaegercli.TraceID{}, 0, false, errors.New("invalid traceparent: zero trace or parent ID")
+	}
+
+	return jaegercli.TraceID{High: high, Low: low}, jaegercli.SpanID(spanID), flags&sampledFlag != 0, nil
+}
+
+// parseBaggage parses the list-members of a baggage header, ignoring their properties and the invalid ones.
+func parseBaggage(value string, baggage map[string]string) {
+	for _, member := range strings.Split(value, ",") {
+		member = strings.SplitN(member, ";", 2)[0]
+
+		keyValue := strings.SplitN(member, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		key, err := url.PathUnescape(strings.TrimSpace(keyValue[0]))
+		if err != nil || key == "" {
+			continue
+		}
+
+		val, err := url.PathUnescape(strings.TrimSpace(keyValue[1]))
+		if err != nil {
+			continue
+		}
+
+		baggage[key] = val
+	}
+}
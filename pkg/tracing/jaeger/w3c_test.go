@@ -0,0 +1,137 @@
+package jaeger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jaegercli "github.com/uber/jaeger-client-go"
+)
+
+func TestW3CPropagator_roundTrip(t *testing.T) {
+	p := &w3cPropagator{}
+
+	traceID := jaegercli.TraceID{High: 0x4bf92f3577b34da6, Low: 0xa3ce929d0e0e4736}
+	sc := jaegercli.NewSpanContext(traceID, 0x00f067aa0ba902b7, 0, true, map[string]string{"userId": "alice bob"})
+
+	header := http.Header{}
+	err := p.Inject(sc, opentracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get("Traceparent"))
+	assert.Equal(t, "userId=alice%20bob", header.Get("Baggage"))
+
+	extracted, err := p.Extract(opentracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+
+	assert.Equal(t, traceID, extracted.TraceID())
+	assert.Equal(t, jaegercli.SpanID(0x00f067aa0ba902b7), extracted.SpanID())
+	assert.True(t, extracted.IsSampled())
+
+	baggage := make(map[string]string)
+	extracted.ForeachBaggageItem(func(k, v string) bool {
+		baggage[k] = v
+		return true
+	})
+	assert.Equal(t, map[string]string{"userId": "alice bob"}, baggage)
+}
+
+func TestW3CPropagator_Extract(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		headers         map[string]string
+		expectedErr     error
+		expectedSampled bool
+		expectedBaggage map[string]string
+	}{
+		{
+			desc:        "no headers",
+			expectedErr: opentracing.ErrSpanContextNotFound,
+		},
+		{
+			desc:            "not sampled",
+			headers:         map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+			expectedBaggage: map[string]string{},
+		},
+		{
+			desc:            "future version with additional fields",
+			headers:         map[string]string{"traceparent": "cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-what-the-future-will-be-like"},
+			expectedSampled: true,
+			expectedBaggage: map[string]string{},
+		},
+		{
+			desc:        "version 00 with additional fields",
+			headers:     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-foo"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "forbidden version",
+			headers:     map[string]string{"traceparent": "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "zero trace ID",
+			headers:     map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "zero parent ID",
+			headers:     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "invalid trace ID",
+			headers:     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "short parent ID",
+			headers:     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01"},
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc: "baggage with properties and invalid members",
+			headers: map[string]string{
+				"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"baggage":     "foo=bar;prop=1, invalid, =empty,bar = baz%2C",
+			},
+			expectedSampled: true,
+			expectedBaggage: map[string]string{"foo": "bar", "bar": "baz,"},
+		},
+		{
+			desc:            "baggage only",
+			headers:         map[string]string{"baggage": "foo=bar"},
+			expectedBaggage: map[string]string{"foo": "bar"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for k, v := range test.headers {
+				header.Set(k, v)
+			}
+
+			sc, err := (&w3cPropagator{}).Extract(opentracing.HTTPHeadersCarrier(header))
+			if test.expectedErr != nil {
+				assert.Equal(t, test.expectedErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedSampled, sc.IsSampled())
+
+			baggage := make(map[string]string)
+			sc.ForeachBaggageItem(func(k, v string) bool {
+				baggage[k] = v
+				return true
+			})
+			assert.Equal(t, test.expectedBaggage, baggage)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Sampler decides, once a request has been handled, whether its trace must be kept.
+// It completes the sampling decision taken by the tracing backend when the trace was started,
+// which cannot take the outcome of the request into account, and would hide most of the errors.
+type Sampler interface {
+	// Sample returns true to keep the trace of the request.
+	Sample(req *http.Request, statusCode int, duration time.Duration) bool
+}
+
+// SamplerFunc is an adapter to allow the use of ordinary functions as samplers.
+type SamplerFunc func(req *http.Request, statusCode int, duration time.Duration) bool
+
+// Sample calls f(req, statusCode, duration).
+func (f SamplerFunc) Sample(req *http.Request, statusCode int, duration time.Duration) bool {
+	return f(req, statusCode, duration)
+}
+
+// SamplingPolicy overrides the sampling decisions of the tracing backend.
+// A nil SamplingPolicy keeps the decisions of the tracing backend.
+type SamplingPolicy struct {
+	rate     *float64
+	samplers []Sampler
+}
+
+// NewSamplingPolicy creates a SamplingPolicy from its configuration.
+func NewSamplingPolicy(config *types.TracingSampling) (*SamplingPolicy, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	policy := &SamplingPolicy{}
+
+	if config.Rate != nil {
+		if *config.Rate < 0 || *config.Rate > 1 {
+			return nil, fmt.Errorf("sampling rate must be between 0 and 1: %v", *config.Rate)
+		}
+
+		rate := *config.Rate
+		policy.rate = &rate
+	}
+
+	if len(config.StatusCodes) > 0 {
+		statusCodes, err := types.NewHTTPCodeRanges(config.StatusCodes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sampling status codes: %w", err)
+		}
+
+		policy.AddSampler(SamplerFunc(func(_ *http.Request, statusCode int, _ time.Duration) bool {
+			return statusCodes.Contains(statusCode)
+		}))
+	}
+
+	if config.MinDuration > 0 {
+		minDuration := time.Duration(config.MinDuration)
+
+		policy.AddSampler(SamplerFunc(func(_ *http.Request, _ int, duration time.Duration) bool {
+			return duration > minDuration
+		}))
+	}
+
+	return policy, nil
+}
+
+// AddSampler adds a sampler, which can keep the traces of the requests once they have been handled.
+func (p *SamplingPolicy) AddSampler(sampler Sampler) {
+	p.samplers = append(p.samplers, sampler)
+}
+
+// Start applies the sampling rate to the trace of the span, when the request starts being handled.
+func (p *SamplingPolicy) Start(span opentracing.Span) {
+	if p == nil || p.rate == nil {
+		return
+	}
+
+	if *p.rate >= 1 || rand.Float64() < *p.rate {
+		KeepTrace(span)
+		return
+	}
+
+	DropTrace(span)
+}
+
+// Finish keeps the trace of the span when one of the samplers selects the request, once it has been handled.
+func (p *SamplingPolicy) Finish(span opentracing.Span, req *http.Request, statusCode int, duration time.Duration) {
+	if p == nil {
+		return
+	}
+
+	for _, sampler := range p.samplers {
+		if sampler.Sample(req, statusCode, duration) {
+			KeepTrace(span)
+			return
+		}
+	}
+}
+
+// KeepTrace asks the tracing backend to keep the trace of the span,
+// regardless of its own sampling decision.
+func KeepTrace(span opentracing.Span) {
+	ext.SamplingPriority.Set(span, 1)
+}
+
+// DropTrace asks the tracing backend to drop the trace of the span,
+// regardless of its own sampling decision.
+func DropTrace(span opentracing.Span) {
+	ext.SamplingPriority.Set(span, 0)
+}
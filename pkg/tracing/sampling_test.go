@@ -0,0 +1,139 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestSamplingPolicy(t *testing.T) {
+	rate := func(r float64) *float64 { return &r }
+
+	testCases := []struct {
+		desc             string
+		config           *types.TracingSampling
+		statusCode       int
+		duration         time.Duration
+		expectedPriority interface{}
+	}{
+		{
+			desc:       "no policy",
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			desc:             "rate of 1",
+			config:           &types.TracingSampling{Rate: rate(1)},
+			statusCode:       http.StatusOK,
+			expectedPriority: uint16(1),
+		},
+		{
+			desc:             "rate of 0",
+			config:           &types.TracingSampling{Rate: rate(0)},
+			statusCode:       http.StatusOK,
+			expectedPriority: uint16(0),
+		},
+		{
+			desc:       "status code not matching",
+			config:     &types.TracingSampling{StatusCodes: []string{"500-599"}},
+			statusCode: http.StatusOK,
+		},
+		{
+			desc:             "status code matching",
+			config:           &types.TracingSampling{StatusCodes: []string{"500-599"}},
+			statusCode:       http.StatusBadGateway,
+			expectedPriority: uint16(1),
+		},
+		{
+			desc:             "status code matching with a rate of 0",
+			config:           &types.TracingSampling{Rate: rate(0), StatusCodes: []string{"500-599"}},
+			statusCode:       http.StatusBadGateway,
+			expectedPriority: uint16(1),
+		},
+		{
+			desc:       "faster than the min duration",
+			config:     &types.TracingSampling{MinDuration: ptypes.Duration(time.Second)},
+			statusCode: http.StatusOK,
+			duration:   time.Millisecond,
+		},
+		{
+			desc:             "slower than the min duration",
+			config:           &types.TracingSampling{MinDuration: ptypes.Duration(time.Second)},
+			statusCode:       http.StatusOK,
+			duration:         2 * time.Second,
+			expectedPriority: uint16(1),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := NewSamplingPolicy(test.config)
+			require.NoError(t, err)
+
+			span := newTagsSpan()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+
+			policy.Start(span)
+			policy.Finish(span, req, test.statusCode, test.duration)
+
+			assert.Equal(t, test.expectedPriority, span.tags[string(ext.SamplingPriority)])
+		})
+	}
+}
+
+func TestSamplingPolicy_invalidConfig(t *testing.T) {
+	rate := 1.5
+	_, err := NewSamplingPolicy(&types.TracingSampling{Rate: &rate})
+	assert.Error(t, err)
+
+	_, err = NewSamplingPolicy(&types.TracingSampling{StatusCodes: []string{"foo"}})
+	assert.Error(t, err)
+}
+
+func TestTracing_AddSampler(t *testing.T) {
+	tracing := &Tracing{}
+	tracing.AddSampler(SamplerFunc(func(req *http.Request, _ int, _ time.Duration) bool {
+		return req.Header.Get("X-Debug") != ""
+	}))
+
+	span := newTagsSpan()
+	tracing.SamplingPolicy().Finish(span, httptest.NewRequest(http.MethodGet, "http://localhost", nil), http.StatusOK, 0)
+	assert.Nil(t, span.tags[string(ext.SamplingPriority)])
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("X-Debug", "true")
+
+	tracing.SamplingPolicy().Finish(span, req, http.StatusOK, 0)
+	assert.Equal(t, uint16(1), span.tags[string(ext.SamplingPriority)])
+}
+
+// tagsSpan records the tags of a span.
+// The mock tracer cannot be used, as it does not record the sampling priority tag.
+type tagsSpan struct {
+	opentracing.Span
+
+	tags map[string]interface{}
+}
+
+func newTagsSpan() *tagsSpan {
+	return &tagsSpan{
+		Span: opentracing.NoopTracer{}.StartSpan("test"),
+		tags: make(map[string]interface{}),
+	}
+}
+
+func (s *tagsSpan) SetTag(key string, value interface{}) opentracing.Span {
+	s.tags[key] = value
+	return s
+}
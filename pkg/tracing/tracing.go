@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/log"
+	jaegercli "github.com/uber/jaeger-client-go"
 )
 
 type contextKey int
@@ -48,8 +50,9 @@ type Tracing struct {
 	ServiceName   string `description:"Set the name for this service" export:"true"`
 	SpanNameLimit int    `description:"Set the maximum character limit for Span names (default 0 = no limit)" export:"true"`
 
-	tracer opentracing.Tracer
-	closer io.Closer
+	tracer   opentracing.Tracer
+	closer   io.Closer
+	sampling *SamplingPolicy
 }
 
 // NewTracing Creates a Tracing.
@@ -89,6 +92,25 @@ func (t *Tracing) Extract(format, carrier interface{}) (opentracing.SpanContext,
 	return t.tracer.Extract(format, carrier)
 }
 
+// SetSamplingPolicy sets the policy overriding the sampling decisions of the tracing backend for all the requests.
+func (t *Tracing) SetSamplingPolicy(policy *SamplingPolicy) {
+	t.sampling = policy
+}
+
+// SamplingPolicy returns the policy overriding the sampling decisions of the tracing backend for all the requests, if any.
+func (t *Tracing) SamplingPolicy() *SamplingPolicy {
+	return t.sampling
+}
+
+// AddSampler adds a sampler, which can keep the traces of all the requests once they have been handled.
+func (t *Tracing) AddSampler(sampler Sampler) {
+	if t.sampling == nil {
+		t.sampling = &SamplingPolicy{}
+	}
+
+	t.sampling.AddSampler(sampler)
+}
+
 // IsEnabled determines if tracing was successfully activated.
 func (t *Tracing) IsEnabled() bool {
 	return t != nil && t.tracer != nil
@@ -128,6 +150,29 @@ func GetSpan(r *http.Request) opentracing.Span {
 	return opentracing.SpanFromContext(r.Context())
 }
 
+// GetTraceID returns the ID of the trace of the span in the request context.
+// It returns an empty string when the tracer does not expose the trace ID (only Jaeger and Datadog do),
+// or when the trace is not sampled by Jaeger.
+func GetTraceID(r *http.Request) string {
+	span := GetSpan(r)
+	if span == nil {
+		return ""
+	}
+
+	switch sc := span.Context().(type) {
+	case jaegercli.SpanContext:
+		if sc.IsValid() && sc.IsSampled() {
+			return sc.TraceID().String()
+		}
+	case interface{ TraceID() uint64 }:
+		if id := sc.TraceID(); id != 0 {
+			return strconv.FormatUint(id, 10)
+		}
+	}
+
+	return ""
+}
+
 // InjectRequestHeaders used to inject OpenTracing headers into the request.
 func InjectRequestHeaders(r *http.Request) {
 	if span := GetSpan(r); span != nil {
@@ -0,0 +1,59 @@
+// Package transparent dials the servers from the IP address of the clients,
+// so that the servers see the address of the clients at the network level.
+// It relies on the IP_TRANSPARENT socket option, which is only available on Linux,
+// and requires the CAP_NET_ADMIN capability and a routing of the answers of the servers back to Traefik.
+package transparent
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Supported reports whether the transparent mode is supported on this platform.
+func Supported() bool {
+	return supported
+}
+
+// Dialer returns a dialer binding the connections to the IP address of the client, on any port.
+// The network is either "tcp" or "udp".
+func Dialer(network string, client net.Addr) (*net.Dialer, error) {
+	if !supported {
+		return nil, errors.New("the transparent mode is only supported on Linux")
+	}
+
+	ip := clientIP(client)
+	if ip == nil {
+		return nil, fmt.Errorf("unable to get the IP address of the client %s", client)
+	}
+
+	var local net.Addr
+	switch network {
+	case "tcp":
+		local = &net.TCPAddr{IP: ip}
+	case "udp":
+		local = &net.UDPAddr{IP: ip}
+	default:
+		return nil, fmt.Errorf("unsupported network %s", network)
+	}
+
+	return &net.Dialer{LocalAddr: local, Control: control}, nil
+}
+
+func clientIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case *net.UDPAddr:
+		return a.IP
+	case nil:
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	return net.ParseIP(host)
+}
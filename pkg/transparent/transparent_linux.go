@@ -0,0 +1,36 @@
+// +build linux
+
+package transparent
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+const supported = true
+
+// ipv6Transparent is the IPV6_TRANSPARENT socket option, which is missing from the syscall package.
+const ipv6Transparent = 0x4b
+
+// control enables the IP_TRANSPARENT socket option, allowing to bind the socket to a non-local address.
+func control(network, _ string, c syscall.RawConn) error {
+	level, option := syscall.SOL_IP, syscall.IP_TRANSPARENT
+	if strings.HasSuffix(network, "6") {
+		level, option = syscall.SOL_IPV6, ipv6Transparent
+	}
+
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), level, option, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("unable to enable the transparent mode, which requires the CAP_NET_ADMIN capability: %w", sockErr)
+	}
+
+	return nil
+}
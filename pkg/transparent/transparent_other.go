@@ -0,0 +1,14 @@
+// +build !linux
+
+package transparent
+
+import (
+	"errors"
+	"syscall"
+)
+
+const supported = false
+
+func control(_, _ string, _ syscall.RawConn) error {
+	return errors.New("the transparent mode is only supported on Linux")
+}
@@ -0,0 +1,71 @@
+package transparent
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialer(t *testing.T) {
+	if !Supported() {
+		t.Skip("the transparent mode is not supported on this platform")
+	}
+
+	testCases := []struct {
+		desc          string
+		network       string
+		client        net.Addr
+		expected      net.Addr
+		expectedError bool
+	}{
+		{
+			desc:     "TCP client",
+			network:  "tcp",
+			client:   &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 41000},
+			expected: &net.TCPAddr{IP: net.ParseIP("10.0.0.1")},
+		},
+		{
+			desc:     "UDP client",
+			network:  "udp",
+			client:   &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 41000},
+			expected: &net.UDPAddr{IP: net.ParseIP("2001:db8::1")},
+		},
+		{
+			desc:     "TCP connection of a UDP client",
+			network:  "tcp",
+			client:   &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 41000},
+			expected: &net.TCPAddr{IP: net.ParseIP("10.0.0.1")},
+		},
+		{
+			desc:          "unknown network",
+			network:       "unix",
+			client:        &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 41000},
+			expectedError: true,
+		},
+		{
+			desc:          "client without IP address",
+			network:       "tcp",
+			client:        &net.UnixAddr{Name: "/tmp/traefik.sock", Net: "unix"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dialer, err := Dialer(test.network, test.client)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, dialer.LocalAddr)
+			assert.NotNil(t, dialer.Control)
+		})
+	}
+}
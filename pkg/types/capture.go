@@ -0,0 +1,37 @@
+package types
+
+// Formats of the traffic capture files.
+const (
+	CaptureFormatHAR    = "har"
+	CaptureFormatBinary = "binary"
+)
+
+// TrafficCapture holds the configuration of the capture of the requests of an entry point, for their offline replay.
+type TrafficCapture struct {
+	FilePath    string            `description:"Path of the file the captured requests are written to." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	Format      string            `description:"Format of the capture file (har or binary)." json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Percent     int               `description:"Percentage of the requests captured." json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	MaxBodySize int64             `description:"Maximum size of the captured body of the requests, the larger bodies being truncated (-1 for unlimited)." json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	Redaction   *CaptureRedaction `description:"Redacts the personal data from the captured requests." json:"redaction,omitempty" toml:"redaction,omitempty" yaml:"redaction,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *TrafficCapture) SetDefaults() {
+	c.Format = CaptureFormatHAR
+	c.Percent = 100
+	c.MaxBodySize = 64 * 1024
+	c.Redaction = &CaptureRedaction{}
+	c.Redaction.SetDefaults()
+}
+
+// CaptureRedaction holds the parts of the captured requests and responses whose value is redacted.
+type CaptureRedaction struct {
+	Headers     []string `description:"Names of the request and response headers whose value is redacted." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	QueryParams []string `description:"Names of the query parameters whose value is redacted." json:"queryParams,omitempty" toml:"queryParams,omitempty" yaml:"queryParams,omitempty" export:"true"`
+	BodyFields  []string `description:"Names of the fields of the JSON and form bodies whose value is redacted." json:"bodyFields,omitempty" toml:"bodyFields,omitempty" yaml:"bodyFields,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *CaptureRedaction) SetDefaults() {
+	r.Headers = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+}
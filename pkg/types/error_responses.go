@@ -0,0 +1,12 @@
+package types
+
+// ErrorResponses holds the configuration of the rendering of the errors generated by Traefik.
+type ErrorResponses struct {
+	Templates *ErrorResponseTemplates `description:"Go templates of the error bodies, overriding the built-in ones." json:"templates,omitempty" toml:"templates,omitempty" yaml:"templates,omitempty" export:"true"`
+}
+
+// ErrorResponseTemplates holds the Go templates of the error bodies, by format.
+type ErrorResponseTemplates struct {
+	JSON        string `description:"Go template of the application/json error bodies." json:"json,omitempty" toml:"json,omitempty" yaml:"json,omitempty" export:"true"`
+	ProblemJSON string `description:"Go template of the application/problem+json error bodies." json:"problemJson,omitempty" toml:"problemJson,omitempty" yaml:"problemJson,omitempty" export:"true"`
+}
@@ -9,6 +9,17 @@ const (
 	AccessLogDrop = "drop"
 	// AccessLogRedact is the redact string value.
 	AccessLogRedact = "redact"
+	// AccessLogHash is the hash string value.
+	AccessLogHash = "hash"
+	// AccessLogMask is the mask string value.
+	AccessLogMask = "mask"
+)
+
+const (
+	// AccessLogOverflowBlock is the block overflow policy value.
+	AccessLogOverflowBlock = "block"
+	// AccessLogOverflowDrop is the drop overflow policy value.
+	AccessLogOverflowDrop = "drop"
 )
 
 const (
@@ -39,6 +50,7 @@ type AccessLog struct {
 	Filters       *AccessLogFilters `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
 	Fields        *AccessLogFields  `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
 	BufferingSize int64             `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
+	AsyncWriter   *AccessLogWriter  `description:"Write the access logs asynchronously, through a bounded queue." json:"asyncWriter,omitempty" toml:"asyncWriter,omitempty" yaml:"asyncWriter,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -50,6 +62,30 @@ func (l *AccessLog) SetDefaults() {
 	l.Fields.SetDefaults()
 }
 
+// AccessLogWriter holds the asynchronous access log writer configuration.
+type AccessLogWriter struct {
+	QueueSize      int                  `description:"Maximum number of access log lines waiting in memory to be written." json:"queueSize,omitempty" toml:"queueSize,omitempty" yaml:"queueSize,omitempty" export:"true"`
+	OverflowPolicy string               `description:"Policy applied when the queue, and the disk buffer, are full: block | drop" json:"overflowPolicy,omitempty" toml:"overflowPolicy,omitempty" yaml:"overflowPolicy,omitempty" export:"true"`
+	DiskBuffer     *AccessLogDiskBuffer `description:"On-disk ring buffer holding the access log lines overflowing the queue." json:"diskBuffer,omitempty" toml:"diskBuffer,omitempty" yaml:"diskBuffer,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (w *AccessLogWriter) SetDefaults() {
+	w.QueueSize = 1024
+	w.OverflowPolicy = AccessLogOverflowBlock
+}
+
+// AccessLogDiskBuffer holds the on-disk ring buffer configuration.
+type AccessLogDiskBuffer struct {
+	FilePath string `description:"Ring buffer file path." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	MaxSize  int64  `description:"Maximum size of the ring buffer file, in bytes." json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (d *AccessLogDiskBuffer) SetDefaults() {
+	d.MaxSize = 64 * 1024 * 1024
+}
+
 // AccessLogFilters holds filters configuration.
 type AccessLogFilters struct {
 	StatusCodes   []string       `description:"Keep access logs with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
@@ -57,17 +93,30 @@ type AccessLogFilters struct {
 	MinDuration   types.Duration `description:"Keep access logs when request took longer than the specified duration." json:"minDuration,omitempty" toml:"minDuration,omitempty" yaml:"minDuration,omitempty" export:"true"`
 }
 
+// +k8s:deepcopy-gen=true
+
 // FieldHeaders holds configuration for access log headers.
 type FieldHeaders struct {
-	DefaultMode string            `description:"Default mode for fields: keep | drop | redact" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty" export:"true"`
+	DefaultMode string            `description:"Default mode for fields: keep | drop | redact | hash | mask" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty" export:"true"`
 	Names       map[string]string `description:"Override mode for headers" json:"names,omitempty" toml:"names,omitempty" yaml:"names,omitempty" export:"true"`
+	Cookies     *FieldCookies     `description:"Cookies to keep, drop, redact, hash or mask in the kept Cookie and Set-Cookie headers" json:"cookies,omitempty" toml:"cookies,omitempty" yaml:"cookies,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// FieldCookies holds configuration for access log cookies.
+type FieldCookies struct {
+	DefaultMode string            `description:"Default mode for cookies: keep | drop | redact | hash | mask" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty" export:"true"`
+	Names       map[string]string `description:"Override mode for cookies" json:"names,omitempty" toml:"names,omitempty" yaml:"names,omitempty" export:"true"`
 }
 
+// +k8s:deepcopy-gen=true
+
 // AccessLogFields holds configuration for access log fields.
 type AccessLogFields struct {
 	DefaultMode string            `description:"Default mode for fields: keep | drop" json:"defaultMode,omitempty" toml:"defaultMode,omitempty" yaml:"defaultMode,omitempty"  export:"true"`
 	Names       map[string]string `description:"Override mode for fields" json:"names,omitempty" toml:"names,omitempty" yaml:"names,omitempty" export:"true"`
-	Headers     *FieldHeaders     `description:"Headers to keep, drop or redact" json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	Headers     *FieldHeaders     `description:"Headers to keep, drop, redact, hash or mask" json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -91,7 +140,8 @@ func (f *AccessLogFields) Keep(field string) bool {
 	return defaultKeep
 }
 
-// KeepHeader checks if the headers need to be kept, dropped or redacted and returns the status.
+// KeepHeader checks if the headers need to be kept, dropped, redacted, hashed or masked and returns the status.
+// The credentials headers are redacted instead of being kept, unless they are explicitly kept.
 func (f *AccessLogFields) KeepHeader(header string) string {
 	defaultValue := AccessLogKeep
 	if f != nil && f.Headers != nil {
@@ -101,9 +151,58 @@ func (f *AccessLogFields) KeepHeader(header string) string {
 			return checkFieldHeaderValue(v, defaultValue)
 		}
 	}
+
+	if defaultValue == AccessLogKeep && isCredentialsHeader(header) {
+		return AccessLogRedact
+	}
+
 	return defaultValue
 }
 
+// KeepCookie checks if the cookies need to be kept, dropped, redacted, hashed or masked and returns the status.
+func (f *AccessLogFields) KeepCookie(cookie string) string {
+	defaultValue := AccessLogKeep
+	if f != nil && f.Headers != nil && f.Headers.Cookies != nil {
+		defaultValue = checkFieldHeaderValue(f.Headers.Cookies.DefaultMode, defaultValue)
+
+		if v, ok := f.Headers.Cookies.Names[cookie]; ok {
+			return checkFieldHeaderValue(v, defaultValue)
+		}
+	}
+	return defaultValue
+}
+
+func isCredentialsHeader(header string) bool {
+	return header == "Authorization" || header == "Proxy-Authorization"
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterAccessLog holds the access log configuration of a router, overriding the global one.
+type RouterAccessLog struct {
+	Enabled  *bool              `description:"Enable the access logs of the router." json:"enabled,omitempty" toml:"enabled,omitempty" yaml:"enabled,omitempty" export:"true"`
+	Fields   *AccessLogFields   `description:"Access log fields of the router, overriding the global ones." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
+	Sampling *AccessLogSampling `description:"Access log sampling, used to keep only a part of the access logs." json:"sampling,omitempty" toml:"sampling,omitempty" yaml:"sampling,omitempty" export:"true"`
+}
+
+// IsEnabled returns whether the access logs of the router are enabled, which is the case by default.
+func (r *RouterAccessLog) IsEnabled() bool {
+	return r == nil || r.Enabled == nil || *r.Enabled
+}
+
+// +k8s:deepcopy-gen=true
+
+// AccessLogSampling holds the access log sampling configuration.
+type AccessLogSampling struct {
+	Rate        float64  `description:"Ratio of the access logs to keep, between 0 and 1." json:"rate,omitempty" toml:"rate,omitempty" yaml:"rate,omitempty" export:"true"`
+	StatusCodes []string `description:"Always keep access logs with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *AccessLogSampling) SetDefaults() {
+	s.Rate = 1
+}
+
 func checkFieldValue(value string, defaultKeep bool) bool {
 	switch value {
 	case AccessLogKeep:
@@ -116,8 +215,10 @@ func checkFieldValue(value string, defaultKeep bool) bool {
 }
 
 func checkFieldHeaderValue(value, defaultValue string) string {
-	if value == AccessLogKeep || value == AccessLogDrop || value == AccessLogRedact {
+	switch value {
+	case AccessLogKeep, AccessLogDrop, AccessLogRedact, AccessLogHash, AccessLogMask:
 		return value
+	default:
+		return defaultValue
 	}
-	return defaultValue
 }
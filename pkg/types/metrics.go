@@ -12,15 +12,18 @@ type Metrics struct {
 	Datadog    *Datadog    `description:"Datadog metrics exporter type." json:"datadog,omitempty" toml:"datadog,omitempty" yaml:"datadog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	StatsD     *Statsd     `description:"StatsD metrics exporter type." json:"statsD,omitempty" toml:"statsD,omitempty" yaml:"statsD,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	InfluxDB   *InfluxDB   `description:"InfluxDB metrics exporter type." json:"influxDB,omitempty" toml:"influxDB,omitempty" yaml:"influxDB,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	OTLP       *OTLP       `description:"OpenTelemetry metrics exporter type." json:"otlp,omitempty" toml:"otlp,omitempty" yaml:"otlp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // Prometheus can contain specific configuration used by the Prometheus Metrics exporter.
 type Prometheus struct {
-	Buckets              []float64 `description:"Buckets for latency metrics." json:"buckets,omitempty" toml:"buckets,omitempty" yaml:"buckets,omitempty" export:"true"`
-	AddEntryPointsLabels bool      `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
-	AddServicesLabels    bool      `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
-	EntryPoint           string    `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
-	ManualRouting        bool      `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
+	Buckets                []float64 `description:"Buckets for latency metrics." json:"buckets,omitempty" toml:"buckets,omitempty" yaml:"buckets,omitempty" export:"true"`
+	AddEntryPointsLabels   bool      `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddServicesLabels      bool      `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	AddPathTemplatesLabels bool      `description:"Enable metrics on the path templates of the services." json:"addPathTemplatesLabels,omitempty" toml:"addPathTemplatesLabels,omitempty" yaml:"addPathTemplatesLabels,omitempty" export:"true"`
+	AddMiddlewaresLabels   bool      `description:"Enable metrics on middlewares." json:"addMiddlewaresLabels,omitempty" toml:"addMiddlewaresLabels,omitempty" yaml:"addMiddlewaresLabels,omitempty" export:"true"`
+	EntryPoint             string    `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	ManualRouting          bool      `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -33,10 +36,12 @@ func (p *Prometheus) SetDefaults() {
 
 // Datadog contains address and metrics pushing interval configuration.
 type Datadog struct {
-	Address              string         `description:"Datadog's address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	PushInterval         types.Duration `description:"Datadog push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
-	AddEntryPointsLabels bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
-	AddServicesLabels    bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	Address                string         `description:"Datadog's address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	PushInterval           types.Duration `description:"Datadog push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	AddEntryPointsLabels   bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddServicesLabels      bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	AddPathTemplatesLabels bool           `description:"Enable metrics on the path templates of the services." json:"addPathTemplatesLabels,omitempty" toml:"addPathTemplatesLabels,omitempty" yaml:"addPathTemplatesLabels,omitempty" export:"true"`
+	AddMiddlewaresLabels   bool           `description:"Enable metrics on middlewares." json:"addMiddlewaresLabels,omitempty" toml:"addMiddlewaresLabels,omitempty" yaml:"addMiddlewaresLabels,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -49,11 +54,13 @@ func (d *Datadog) SetDefaults() {
 
 // Statsd contains address and metrics pushing interval configuration.
 type Statsd struct {
-	Address              string         `description:"StatsD address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	PushInterval         types.Duration `description:"StatsD push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
-	AddEntryPointsLabels bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
-	AddServicesLabels    bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
-	Prefix               string         `description:"Prefix to use for metrics collection." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty" export:"true"`
+	Address                string         `description:"StatsD address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	PushInterval           types.Duration `description:"StatsD push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	AddEntryPointsLabels   bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddServicesLabels      bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	AddPathTemplatesLabels bool           `description:"Enable metrics on the path templates of the services." json:"addPathTemplatesLabels,omitempty" toml:"addPathTemplatesLabels,omitempty" yaml:"addPathTemplatesLabels,omitempty" export:"true"`
+	AddMiddlewaresLabels   bool           `description:"Enable metrics on middlewares." json:"addMiddlewaresLabels,omitempty" toml:"addMiddlewaresLabels,omitempty" yaml:"addMiddlewaresLabels,omitempty" export:"true"`
+	Prefix                 string         `description:"Prefix to use for metrics collection." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -67,15 +74,17 @@ func (s *Statsd) SetDefaults() {
 
 // InfluxDB contains address, login and metrics pushing interval configuration.
 type InfluxDB struct {
-	Address              string         `description:"InfluxDB address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	Protocol             string         `description:"InfluxDB address protocol (udp or http)." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty"`
-	PushInterval         types.Duration `description:"InfluxDB push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
-	Database             string         `description:"InfluxDB database used when protocol is http." json:"database,omitempty" toml:"database,omitempty" yaml:"database,omitempty" export:"true"`
-	RetentionPolicy      string         `description:"InfluxDB retention policy used when protocol is http." json:"retentionPolicy,omitempty" toml:"retentionPolicy,omitempty" yaml:"retentionPolicy,omitempty" export:"true"`
-	Username             string         `description:"InfluxDB username (only with http)." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
-	Password             string         `description:"InfluxDB password (only with http)." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
-	AddEntryPointsLabels bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
-	AddServicesLabels    bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	Address                string         `description:"InfluxDB address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Protocol               string         `description:"InfluxDB address protocol (udp or http)." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty"`
+	PushInterval           types.Duration `description:"InfluxDB push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	Database               string         `description:"InfluxDB database used when protocol is http." json:"database,omitempty" toml:"database,omitempty" yaml:"database,omitempty" export:"true"`
+	RetentionPolicy        string         `description:"InfluxDB retention policy used when protocol is http." json:"retentionPolicy,omitempty" toml:"retentionPolicy,omitempty" yaml:"retentionPolicy,omitempty" export:"true"`
+	Username               string         `description:"InfluxDB username (only with http)." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password               string         `description:"InfluxDB password (only with http)." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	AddEntryPointsLabels   bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddServicesLabels      bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	AddPathTemplatesLabels bool           `description:"Enable metrics on the path templates of the services." json:"addPathTemplatesLabels,omitempty" toml:"addPathTemplatesLabels,omitempty" yaml:"addPathTemplatesLabels,omitempty" export:"true"`
+	AddMiddlewaresLabels   bool           `description:"Enable metrics on middlewares." json:"addMiddlewaresLabels,omitempty" toml:"addMiddlewaresLabels,omitempty" yaml:"addMiddlewaresLabels,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -87,6 +96,38 @@ func (i *InfluxDB) SetDefaults() {
 	i.AddServicesLabels = true
 }
 
+// OTLP metrics temporalities.
+const (
+	OTLPTemporalityCumulative = "cumulative"
+	OTLPTemporalityDelta      = "delta"
+)
+
+// OTLP contains address, protocol and metrics pushing interval configuration.
+type OTLP struct {
+	Address                string            `description:"OTLP collector address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Protocol               string            `description:"OTLP protocol (grpc or http)." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+	Insecure               bool              `description:"Disables TLS for the connection to the collector." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Headers                map[string]string `description:"Headers sent with the export requests." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	PushInterval           types.Duration    `description:"OTLP push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	Temporality            string            `description:"Temporality of the counters and histograms (cumulative or delta)." json:"temporality,omitempty" toml:"temporality,omitempty" yaml:"temporality,omitempty" export:"true"`
+	ExplicitBoundaries     []float64         `description:"Boundaries of the buckets for latency metrics." json:"explicitBoundaries,omitempty" toml:"explicitBoundaries,omitempty" yaml:"explicitBoundaries,omitempty" export:"true"`
+	AddEntryPointsLabels   bool              `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddServicesLabels      bool              `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+	AddPathTemplatesLabels bool              `description:"Enable metrics on the path templates of the services." json:"addPathTemplatesLabels,omitempty" toml:"addPathTemplatesLabels,omitempty" yaml:"addPathTemplatesLabels,omitempty" export:"true"`
+	AddMiddlewaresLabels   bool              `description:"Enable metrics on middlewares." json:"addMiddlewaresLabels,omitempty" toml:"addMiddlewaresLabels,omitempty" yaml:"addMiddlewaresLabels,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OTLP) SetDefaults() {
+	o.Address = "localhost:4317"
+	o.Protocol = "grpc"
+	o.PushInterval = types.Duration(10 * time.Second)
+	o.Temporality = OTLPTemporalityCumulative
+	o.ExplicitBoundaries = []float64{0.1, 0.3, 1.2, 5}
+	o.AddEntryPointsLabels = true
+	o.AddServicesLabels = true
+}
+
 // Statistics provides options for monitoring request and response stats.
 type Statistics struct {
 	RecentErrors int `description:"Number of recent errors logged." json:"recentErrors,omitempty" toml:"recentErrors,omitempty" yaml:"recentErrors,omitempty" export:"true"`
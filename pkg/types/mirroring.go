@@ -0,0 +1,22 @@
+package types
+
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// TrafficMirroring holds the configuration of the mirroring of the requests of an entry point.
+type TrafficMirroring struct {
+	URL         string          `description:"URL of the destination the requests are mirrored to." json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	Percent     int             `description:"Percentage of the requests mirrored." json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	MaxBodySize int64           `description:"Maximum size of the body of the mirrored requests, the requests with a larger body are not mirrored (-1 for unlimited)." json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	Timeout     ptypes.Duration `description:"Timeout of the mirrored requests." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (m *TrafficMirroring) SetDefaults() {
+	m.Percent = 100
+	m.MaxBodySize = 1024 * 1024
+	m.Timeout = ptypes.Duration(10 * time.Second)
+}
@@ -0,0 +1,19 @@
+package types
+
+// The formats of the generated request IDs.
+const (
+	RequestIDGeneratorUUID = "uuid"
+	RequestIDGeneratorULID = "ulid"
+)
+
+// RequestID holds the configuration of the request IDs.
+type RequestID struct {
+	HeaderName string `description:"Name of the header holding the request ID." json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	Generator  string `description:"Format of the generated request IDs (uuid/ulid)." json:"generator,omitempty" toml:"generator,omitempty" yaml:"generator,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *RequestID) SetDefaults() {
+	r.HeaderName = "X-Request-Id"
+	r.Generator = RequestIDGeneratorUUID
+}
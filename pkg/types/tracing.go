@@ -0,0 +1,14 @@
+package types
+
+import (
+	"github.com/traefik/paerser/types"
+)
+
+// +k8s:deepcopy-gen=true
+
+// TracingSampling holds the sampling decisions overriding the one of the tracing backend.
+type TracingSampling struct {
+	Rate        *float64       `description:"Ratio of the traces to keep, between 0 and 1, overriding the sampling of the tracing backend." json:"rate,omitempty" toml:"rate,omitempty" yaml:"rate,omitempty" export:"true"`
+	StatusCodes []string       `description:"Always keep the traces of the requests with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
+	MinDuration types.Duration `description:"Always keep the traces of the requests which took longer than the specified duration." json:"minDuration,omitempty" toml:"minDuration,omitempty" yaml:"minDuration,omitempty" export:"true"`
+}
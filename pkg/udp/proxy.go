@@ -1,16 +1,21 @@
 package udp
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"net"
+	"syscall"
 
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/transparent"
 )
 
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
-	target string
+	target      string
+	transparent bool
 }
 
 // NewProxy creates a new Proxy.
@@ -18,39 +23,88 @@ func NewProxy(address string) (*Proxy, error) {
 	return &Proxy{target: address}, nil
 }
 
+// EnableTransparent makes the proxy send the datagrams to the backend from the IP address of the client,
+// so that the backend sees the address of the client at the network level.
+func (p *Proxy) EnableTransparent() error {
+	if !transparent.Supported() {
+		return errors.New("the transparent mode is only supported on Linux")
+	}
+
+	p.transparent = true
+	return nil
+}
+
 // ServeUDP implements the Handler interface.
 func (p *Proxy) ServeUDP(conn *Conn) {
+	_ = p.serveSession(conn)
+}
+
+// serveSession proxies the session to the backend,
+// and returns an error when the backend refused the datagrams or never answered them.
+func (p *Proxy) serveSession(conn *Conn) error {
 	log.Debugf("Handling connection from %s", conn.rAddr)
 
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := net.Dial("udp", p.target)
+	connBackend, err := p.dialBackend(conn.rAddr)
 	if err != nil {
 		log.Errorf("Error while connecting to backend: %v", err)
-		return
+		return err
 	}
 
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
 	errChan := make(chan error)
-	go p.connCopy(conn, connBackend, errChan)
+	replied := make(chan bool, 1)
+	go func() {
+		replied <- p.connCopy(conn, connBackend, errChan) > 0
+	}()
 	go p.connCopy(connBackend, conn, errChan)
 
+	var sessionErr error
+
 	err = <-errChan
 	if err != nil {
 		log.WithoutContext().Errorf("Error while serving UDP: %v", err)
+
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			sessionErr = err
+		}
 	}
 
 	<-errChan
+
+	if sessionErr == nil && !<-replied {
+		sessionErr = fmt.Errorf("no response from %s", p.target)
+	}
+
+	return sessionErr
 }
 
-func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
-	_, err := io.Copy(dst, src)
+// dialBackend connects to the backend,
+// from the address of the client when the transparent mode is enabled.
+func (p *Proxy) dialBackend(client net.Addr) (net.Conn, error) {
+	if !p.transparent {
+		return net.Dial("udp", p.target)
+	}
+
+	dialer, err := transparent.Dialer("udp", client)
+	if err != nil {
+		return nil, err
+	}
+
+	return dialer.Dial("udp", p.target)
+}
+
+func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) int64 {
+	n, err := io.Copy(dst, src)
 	errCh <- err
 
 	if err := dst.Close(); err != nil {
 		log.WithoutContext().Debugf("Error while terminating connection: %v", err)
 	}
+
+	return n
 }
@@ -3,6 +3,7 @@ package udp
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/log"
 )
@@ -10,14 +11,27 @@ import (
 type server struct {
 	Handler
 	weight int
+
+	// failures is the number of consecutive failed sessions of the server.
+	failures int
+	// unavailableUntil is the time until which the server does not receive new sessions.
+	unavailableUntil time.Time
+}
+
+// sessionServer is implemented by the handlers reporting the failure of the sessions they serve.
+type sessionServer interface {
+	serveSession(conn *Conn) error
 }
 
 // WRRLoadBalancer is a naive RoundRobin load balancer for UDP services.
 type WRRLoadBalancer struct {
-	servers       []server
+	servers       []*server
 	lock          sync.RWMutex
 	currentWeight int
 	index         int
+
+	maxFailures    int
+	failureTimeout time.Duration
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
@@ -38,8 +52,69 @@ func (b *WRRLoadBalancer) ServeUDP(conn *Conn) {
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
 		conn.Close()
+		return
+	}
+
+	handler, ok := next.Handler.(sessionServer)
+	if b.maxFailures <= 0 || !ok {
+		next.ServeUDP(conn)
+		return
+	}
+
+	b.reportSession(next, handler.serveSession(conn))
+}
+
+// SetPassiveHealthCheck makes the load balancer stop forwarding sessions to a server for failureTimeout,
+// once maxFailures consecutive sessions served by this server failed.
+func (b *WRRLoadBalancer) SetPassiveHealthCheck(maxFailures int, failureTimeout time.Duration) {
+	b.maxFailures = maxFailures
+	b.failureTimeout = failureTimeout
+}
+
+func (b *WRRLoadBalancer) reportSession(srv *server, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err == nil {
+		srv.failures = 0
+		return
+	}
+
+	srv.failures++
+	if srv.failures < b.maxFailures {
+		return
+	}
+
+	srv.failures = 0
+	srv.unavailableUntil = time.Now().Add(b.failureTimeout)
+	log.WithoutContext().Warnf("UDP server considered unavailable for %s after %d failed sessions: %v", b.failureTimeout, b.maxFailures, err)
+}
+
+// available reports whether the server can receive new sessions.
+func (s *server) available(now time.Time) bool {
+	if now.Before(s.unavailableUntil) {
+		return false
+	}
+
+	if lb, ok := s.Handler.(*WRRLoadBalancer); ok {
+		return lb.hasAvailableServer()
+	}
+
+	return true
+}
+
+func (b *WRRLoadBalancer) hasAvailableServer() bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	now := time.Now()
+	for _, s := range b.servers {
+		if s.weight > 0 && s.available(now) {
+			return true
+		}
 	}
-	next.ServeUDP(conn)
+
+	return false
 }
 
 // AddServer appends a handler to the existing list.
@@ -54,7 +129,7 @@ func (b *WRRLoadBalancer) AddWeightedServer(serverHandler Handler, weight *int)
 	if weight != nil {
 		w = *weight
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+	b.servers = append(b.servers, &server{Handler: serverHandler, weight: w})
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
@@ -86,7 +161,7 @@ func gcd(a, b int) int {
 	return a
 }
 
-func (b *WRRLoadBalancer) next() (Handler, error) {
+func (b *WRRLoadBalancer) next() (*server, error) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
@@ -103,6 +178,21 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 	// Maximum weight across all enabled servers
 	max := b.maxWeight()
 
+	// Servers made unavailable by the passive health check are skipped,
+	// so at least one of the weighted servers has to be available for the loop below to end.
+	now := time.Now()
+	available := make(map[*server]bool, len(b.servers))
+	var hasAvailable bool
+	for _, s := range b.servers {
+		if s.available(now) {
+			available[s] = true
+			hasAvailable = hasAvailable || s.weight > 0
+		}
+	}
+	if max > 0 && !hasAvailable {
+		return nil, fmt.Errorf("no available server")
+	}
+
 	for {
 		b.index = (b.index + 1) % len(b.servers)
 		if b.index == 0 {
@@ -115,7 +205,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
+		if srv.weight >= b.currentWeight && available[srv] {
 			return srv, nil
 		}
 	}
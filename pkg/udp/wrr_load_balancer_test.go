@@ -0,0 +1,181 @@
+package udp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBalancing_unavailableServers(t *testing.T) {
+	handlerA := HandlerFunc(func(conn *Conn) {})
+	handlerB := HandlerFunc(func(conn *Conn) {})
+
+	child := NewWRRLoadBalancer()
+	child.AddServer(handlerB)
+
+	balancer := NewWRRLoadBalancer()
+	balancer.AddServer(handlerA)
+	balancer.AddWeightedServer(child, intPtr(2))
+
+	for i := 0; i < 3; i++ {
+		_, err := balancer.next()
+		require.NoError(t, err)
+	}
+
+	balancer.servers[0].unavailableUntil = time.Now().Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		next, err := balancer.next()
+		require.NoError(t, err)
+		assert.Same(t, child, next.Handler)
+	}
+
+	child.servers[0].unavailableUntil = time.Now().Add(time.Minute)
+	_, err := balancer.next()
+	assert.Error(t, err)
+
+	balancer.servers[0].unavailableUntil = time.Time{}
+	next, err := balancer.next()
+	require.NoError(t, err)
+	assert.Equal(t, balancer.servers[0], next)
+}
+
+func TestLoadBalancing_passiveHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		failingServer func(t *testing.T) string
+	}{
+		{
+			desc: "refused datagrams",
+			failingServer: func(t *testing.T) string {
+				t.Helper()
+
+				conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+				require.NoError(t, err)
+
+				addr := conn.LocalAddr().String()
+				require.NoError(t, conn.Close())
+
+				return addr
+			},
+		},
+		{
+			desc: "no response",
+			failingServer: func(t *testing.T) string {
+				t.Helper()
+
+				conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = conn.Close() })
+
+				return conn.LocalAddr().String()
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			failingProxy, err := NewProxy(test.failingServer(t))
+			require.NoError(t, err)
+
+			echoProxy, err := NewProxy(newEchoServer(t))
+			require.NoError(t, err)
+
+			balancer := NewWRRLoadBalancer()
+			balancer.AddServer(failingProxy)
+			balancer.AddServer(echoProxy)
+			balancer.SetPassiveHealthCheck(1, time.Minute)
+
+			addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			listener, err := Listen("udp", addr, 200*time.Millisecond)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+					go balancer.ServeUDP(conn)
+				}
+			}()
+
+			// The first session is forwarded to the failing server.
+			_, err = sendDatagram(listener.Addr().String(), "DATA")
+			require.Error(t, err)
+
+			assert.Eventually(t, func() bool {
+				balancer.lock.RLock()
+				defer balancer.lock.RUnlock()
+
+				return !balancer.servers[0].available(time.Now())
+			}, 5*time.Second, 50*time.Millisecond)
+
+			for i := 0; i < 3; i++ {
+				resp, err := sendDatagram(listener.Addr().String(), "DATA")
+				require.NoError(t, err)
+				assert.Equal(t, "DATA", resp)
+			}
+		})
+	}
+}
+
+// newEchoServer starts a UDP server sending back the received datagrams, and returns its address.
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		b := make([]byte, receiveMTU)
+		for {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+
+			if _, err := conn.WriteTo(b[:n], addr); err != nil {
+				return
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// sendDatagram sends the data from a new client and waits for the response.
+func sendDatagram(addr, data string) (string, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err = conn.Write([]byte(data)); err != nil {
+		return "", err
+	}
+
+	if err = conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, receiveMTU)
+	n, err := conn.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b[:n]), nil
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,134 @@
+// Node types of the topology graph, in the order of a request path.
+const TYPES = ['entryPoint', 'router', 'middleware', 'service', 'server']
+
+export const NODE_WIDTH = 220
+export const NODE_HEIGHT = 52
+const COLUMN_GAP = 80
+const ROW_GAP = 16
+
+// depthsWithinType returns, for each node, the length of the longest chain of nodes of the same type leading to it,
+// such as the chained middlewares, or the services of a weighted service.
+function depthsWithinType (nodes, edges, typeOf) {
+  const depths = {}
+  nodes.forEach(node => { depths[node.id] = 0 })
+
+  const sameTypeEdges = edges.filter(edge =>
+    typeOf[edge.from] !== undefined && typeOf[edge.from] === typeOf[edge.to]
+  )
+
+  // The number of iterations bounds the depths in case of cycles.
+  for (let i = 0; i < nodes.length; i++) {
+    let changed = false
+    sameTypeEdges.forEach(edge => {
+      if (depths[edge.to] < depths[edge.from] + 1) {
+        depths[edge.to] = depths[edge.from] + 1
+        changed = true
+      }
+    })
+    if (!changed) {
+      break
+    }
+  }
+
+  return depths
+}
+
+// layoutGraph places the nodes of the graph in columns, from the entry points on the left to the servers on the right,
+// and returns the positioned nodes and edges, along with the size of the graph.
+export function layoutGraph ({ nodes = [], edges = [] } = {}) {
+  const typeOf = {}
+  nodes.forEach(node => { typeOf[node.id] = node.type })
+
+  const depths = depthsWithinType(nodes, edges, typeOf)
+
+  // Each type gets as many columns as its longest chain.
+  const offsets = {}
+  let offset = 0
+  TYPES.forEach(type => {
+    offsets[type] = offset
+
+    const typeNodes = nodes.filter(node => node.type === type)
+    if (typeNodes.length > 0) {
+      offset += Math.max(...typeNodes.map(node => depths[node.id])) + 1
+    }
+  })
+
+  const columns = []
+  nodes.forEach(node => {
+    const column = (offsets[node.type] || 0) + depths[node.id]
+    columns[column] = columns[column] || []
+    columns[column].push(node)
+  })
+
+  const predecessors = {}
+  edges.forEach(edge => {
+    predecessors[edge.to] = predecessors[edge.to] || []
+    predecessors[edge.to].push(edge.from)
+  })
+
+  // The nodes are ordered by the mean row of their predecessors, to limit the crossings of the edges.
+  const rows = {}
+  const positioned = {}
+  let height = 0
+  columns.forEach((columnNodes, column) => {
+    const meanRow = node => {
+      const placed = (predecessors[node.id] || []).filter(id => rows[id] !== undefined)
+      if (placed.length === 0) {
+        return Number.MAX_SAFE_INTEGER
+      }
+      return placed.reduce((sum, id) => sum + rows[id], 0) / placed.length
+    }
+
+    columnNodes
+      .map(node => ({ node, meanRow: meanRow(node) }))
+      .sort((a, b) => a.meanRow - b.meanRow || a.node.name.localeCompare(b.node.name))
+      .forEach(({ node }, row) => {
+        rows[node.id] = row
+        positioned[node.id] = {
+          ...node,
+          x: column * (NODE_WIDTH + COLUMN_GAP),
+          y: row * (NODE_HEIGHT + ROW_GAP)
+        }
+        height = Math.max(height, (row + 1) * (NODE_HEIGHT + ROW_GAP) - ROW_GAP)
+      })
+  })
+
+  return {
+    nodes: Object.values(positioned),
+    edges: edges
+      .filter(edge => positioned[edge.from] && positioned[edge.to])
+      .map(edge => ({
+        ...edge,
+        x1: positioned[edge.from].x + NODE_WIDTH,
+        y1: positioned[edge.from].y + NODE_HEIGHT / 2,
+        x2: positioned[edge.to].x,
+        y2: positioned[edge.to].y + NODE_HEIGHT / 2
+      })),
+    width: Math.max(0, columns.length * (NODE_WIDTH + COLUMN_GAP) - COLUMN_GAP),
+    height
+  }
+}
+
+// tracePaths returns the IDs of the nodes on the request paths going through the given node:
+// the nodes leading to it, and the nodes it leads to.
+export function tracePaths ({ edges = [] } = {}, id) {
+  const traced = new Set([id])
+
+  const walk = (from, to) => {
+    const pending = [id]
+    while (pending.length > 0) {
+      const current = pending.pop()
+      edges.forEach(edge => {
+        if (edge[from] === current && !traced.has(edge[to])) {
+          traced.add(edge[to])
+          pending.push(edge[to])
+        }
+      })
+    }
+  }
+
+  walk('from', 'to')
+  walk('to', 'from')
+
+  return traced
+}
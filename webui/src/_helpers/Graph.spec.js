@@ -0,0 +1,73 @@
+import { expect } from 'chai'
+import { layoutGraph, tracePaths, NODE_WIDTH } from './Graph'
+
+const graph = {
+  nodes: [
+    { id: 'entryPoint/web', type: 'entryPoint', name: 'web' },
+    { id: 'router/foo@docker', type: 'router', name: 'foo@docker' },
+    { id: 'router/bar@docker', type: 'router', name: 'bar@docker' },
+    { id: 'middleware/auth@docker', type: 'middleware', name: 'auth@docker' },
+    { id: 'middleware/compress@docker', type: 'middleware', name: 'compress@docker' },
+    { id: 'service/weighted@docker', type: 'service', name: 'weighted@docker' },
+    { id: 'service/foo@docker', type: 'service', name: 'foo@docker' },
+    { id: 'service/bar@docker', type: 'service', name: 'bar@docker' },
+    { id: 'server/foo@docker/http://10.0.0.1', type: 'server', name: 'http://10.0.0.1' },
+    { id: 'server/bar@docker/http://10.0.0.2', type: 'server', name: 'http://10.0.0.2' }
+  ],
+  edges: [
+    { from: 'entryPoint/web', to: 'router/foo@docker' },
+    { from: 'entryPoint/web', to: 'router/bar@docker' },
+    { from: 'router/foo@docker', to: 'middleware/auth@docker' },
+    { from: 'middleware/auth@docker', to: 'middleware/compress@docker' },
+    { from: 'middleware/compress@docker', to: 'service/weighted@docker' },
+    { from: 'router/bar@docker', to: 'service/bar@docker' },
+    { from: 'service/weighted@docker', to: 'service/foo@docker' },
+    { from: 'service/foo@docker', to: 'server/foo@docker/http://10.0.0.1' },
+    { from: 'service/bar@docker', to: 'server/bar@docker/http://10.0.0.2' }
+  ]
+}
+
+describe('graph helpers', function () {
+  it('layoutGraph places the nodes in columns by type', function () {
+    const layout = layoutGraph(graph)
+
+    const columnOf = id => layout.nodes.find(node => node.id === id).x / layout.nodes.find(node => node.type === 'router').x
+
+    expect(columnOf('router/foo@docker')).to.equal(1)
+    expect(columnOf('router/bar@docker')).to.equal(1)
+    expect(columnOf('middleware/auth@docker')).to.equal(2)
+    expect(columnOf('middleware/compress@docker')).to.equal(3)
+    expect(columnOf('service/weighted@docker')).to.equal(4)
+    expect(columnOf('service/bar@docker')).to.equal(4)
+    expect(columnOf('service/foo@docker')).to.equal(5)
+    expect(columnOf('server/foo@docker/http://10.0.0.1')).to.equal(6)
+
+    expect(layout.edges.length).to.equal(9)
+    expect(layout.edges[0].x1).to.equal(NODE_WIDTH)
+  })
+
+  it('layoutGraph ignores the edges of unknown nodes', function () {
+    const layout = layoutGraph({
+      nodes: [{ id: 'router/foo@docker', type: 'router', name: 'foo@docker' }],
+      edges: [{ from: 'router/foo@docker', to: 'service/foo@docker' }]
+    })
+
+    expect(layout.nodes.length).to.equal(1)
+    expect(layout.edges.length).to.equal(0)
+    expect(layout.width).to.equal(NODE_WIDTH)
+  })
+
+  it('tracePaths returns the nodes on the paths going through a node', function () {
+    const traced = tracePaths(graph, 'middleware/compress@docker')
+
+    expect([...traced].sort()).to.deep.equal([
+      'entryPoint/web',
+      'middleware/auth@docker',
+      'middleware/compress@docker',
+      'router/foo@docker',
+      'server/foo@docker/http://10.0.0.1',
+      'service/foo@docker',
+      'service/weighted@docker'
+    ])
+  })
+})
@@ -57,11 +57,20 @@ function getMiddlewareByName (name) {
     })
 }
 
+function getGraph () {
+  return APP.api.get(`${apiBase}/graph`)
+    .then(body => {
+      console.log('Success -> HttpService -> getGraph', body.data)
+      return body.data
+    })
+}
+
 export default {
   getAllRouters,
   getRouterByName,
   getAllServices,
   getServiceByName,
   getAllMiddlewares,
-  getMiddlewareByName
+  getMiddlewareByName,
+  getGraph
 }
@@ -97,6 +97,19 @@ const routes = [
           protocol: 'http',
           title: 'HTTP Middleware Detail'
         }
+      },
+      {
+        path: 'graph',
+        name: 'httpGraph',
+        components: {
+          default: () => import('pages/http/Graph.vue'),
+          NavBar: () => import('components/_commons/ToolBar.vue')
+        },
+        props: { default: true, NavBar: true },
+        meta: {
+          protocol: 'http',
+          title: 'HTTP Topology'
+        }
       }
     ]
   },
@@ -77,3 +77,16 @@ export function getMiddlewareByName ({ commit }, name) {
       return Promise.reject(error)
     })
 }
+
+export function getGraph ({ commit }) {
+  commit('getGraphRequest')
+  return HttpService.getGraph()
+    .then(body => {
+      commit('getGraphSuccess', body)
+      return body
+    })
+    .catch(error => {
+      commit('getGraphFailure', error)
+      return Promise.reject(error)
+    })
+}
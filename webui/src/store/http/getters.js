@@ -39,3 +39,10 @@ export function allMiddlewares (state) {
 export function middlewareByName (state) {
   return state.middlewareByName
 }
+
+// ----------------------------
+// Graph
+// ----------------------------
+export function graph (state) {
+  return state.graph
+}
@@ -156,3 +156,22 @@ export function getMiddlewareByNameFailure (state, error) {
 export function getMiddlewareByNameClear (state) {
   state.middlewareByName = {}
 }
+
+// ----------------------------
+// Get Graph
+// ----------------------------
+export function getGraphRequest (state) {
+  state.graph = { ...state.graph, loading: true }
+}
+
+export function getGraphSuccess (state, body) {
+  state.graph = { item: body, loading: false }
+}
+
+export function getGraphFailure (state, error) {
+  state.graph = { ...state.graph, loading: false, error }
+}
+
+export function getGraphClear (state) {
+  state.graph = {}
+}
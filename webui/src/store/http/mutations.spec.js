@@ -10,7 +10,10 @@ const {
   getAllServicesFailure,
   getAllMiddlewaresRequest,
   getAllMiddlewaresSuccess,
-  getAllMiddlewaresFailure
+  getAllMiddlewaresFailure,
+  getGraphRequest,
+  getGraphSuccess,
+  getGraphFailure
 } = store.mutations
 
 describe('http mutations', function () {
@@ -289,4 +292,52 @@ describe('http mutations', function () {
       expect(state.allMiddlewares.items.length).to.equal(3)
     })
   })
+
+  /* Graph */
+  describe('http graph mutations', function () {
+    it('getGraphRequest keeps the current graph while polling', function () {
+      const state = {
+        graph: {
+          item: { nodes: [{}, {}], edges: [{}] }
+        }
+      }
+
+      getGraphRequest(state)
+
+      expect(state.graph.loading).to.equal(true)
+      expect(state.graph.item.nodes.length).to.equal(2)
+    })
+
+    it('getGraphSuccess', function () {
+      const state = {
+        graph: {
+          loading: true,
+          error: { message: 'previous error' }
+        }
+      }
+
+      getGraphSuccess(state, { nodes: [{}, {}, {}], edges: [] })
+
+      expect(state.graph.loading).to.equal(false)
+      expect(state.graph.error).to.equal(undefined)
+      expect(state.graph.item.nodes.length).to.equal(3)
+    })
+
+    it('getGraphFailure keeps the current graph', function () {
+      const state = {
+        graph: {
+          item: { nodes: [{}], edges: [] },
+          loading: true
+        }
+      }
+
+      const error = { message: 'unauthorized' }
+
+      getGraphFailure(state, error)
+
+      expect(state.graph.loading).to.equal(false)
+      expect(state.graph.error).to.equal(error)
+      expect(state.graph.item.nodes.length).to.equal(1)
+    })
+  })
 })
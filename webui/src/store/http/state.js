@@ -4,5 +4,6 @@ export default {
   allServices: {},
   serviceByName: {},
   allMiddlewares: {},
-  middlewareByName: {}
+  middlewareByName: {},
+  graph: {}
 }